@@ -0,0 +1,179 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// doneCheckInterval is the interval of the disconnect checks of c.Done(),
+// if Config.DisconnectCheckInterval is not set.
+const doneCheckInterval = 100 * time.Millisecond
+
+// requestAbort tracks if the client of a request closed the connection.
+type requestAbort struct {
+	mutex    sync.Mutex
+	done     chan struct{}           // closed when the client disconnected or the request was handled
+	stop     chan struct{}           // stops the watcher of the connection
+	cancel   context.CancelCauseFunc // cancels the user context when the client disconnected
+	finished bool
+	aborted  atomic.Bool
+	active   atomic.Bool // set when the done channel or the watcher was used by the request
+}
+
+// reset prepares the state for a new request.
+func (a *requestAbort) reset() {
+	a.aborted.Store(false)
+	if a.active.Swap(false) {
+		a.mutex.Lock()
+		a.finished = false
+		a.mutex.Unlock()
+	}
+}
+
+// abortLocked marks the request as aborted, the mutex must be held.
+func (a *requestAbort) abortLocked() {
+	if a.finished || a.aborted.Load() {
+		return
+	}
+	a.aborted.Store(true)
+	if a.done != nil {
+		close(a.done)
+	}
+	if a.cancel != nil {
+		a.cancel(ErrClientDisconnected)
+	}
+}
+
+// abort marks the request as aborted.
+func (a *requestAbort) abort() {
+	a.mutex.Lock()
+	a.abortLocked()
+	a.mutex.Unlock()
+}
+
+// doneChan returns the done channel and starts the watcher of the connection.
+func (a *requestAbort) doneChan(conn net.Conn, interval time.Duration) <-chan struct{} {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.active.Store(true)
+	if a.done == nil {
+		a.done = make(chan struct{})
+		if a.finished || a.aborted.Load() {
+			close(a.done)
+			return a.done
+		}
+	}
+	a.watchLocked(conn, interval)
+
+	return a.done
+}
+
+// onAbort sets the cancel func of the user context and starts the watcher of the connection.
+func (a *requestAbort) onAbort(cancel context.CancelCauseFunc, conn net.Conn, interval time.Duration) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.active.Store(true)
+	a.cancel = cancel
+	a.watchLocked(conn, interval)
+}
+
+// watchLocked starts a goroutine which checks the connection in the interval
+// until the request was handled, the mutex must be held.
+func (a *requestAbort) watchLocked(conn net.Conn, interval time.Duration) {
+	if conn == nil || a.stop != nil || a.finished || a.aborted.Load() {
+		return
+	}
+
+	stop := make(chan struct{})
+	a.stop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if !isConnClosed(conn) {
+					continue
+				}
+				a.mutex.Lock()
+				// the ctx may already be used by the next request
+				if a.stop == stop {
+					a.abortLocked()
+				}
+				a.mutex.Unlock()
+				return
+			}
+		}
+	}()
+}
+
+// finish stops the watcher and closes the done channel after the request was handled.
+// It does nothing if the request didn't use them.
+func (a *requestAbort) finish() {
+	if !a.active.Load() {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.stop != nil {
+		close(a.stop)
+		a.stop = nil
+	}
+	if a.done != nil && !a.aborted.Load() {
+		close(a.done)
+	}
+	a.done = nil
+	a.cancel = nil
+	a.finished = true
+}
+
+// disconnectConn returns the connection of the request which can be checked
+// for a disconnect, or nil if the checks aren't supported.
+func disconnectConn(c Ctx) net.Conn {
+	if !disconnectCheckSupported {
+		return nil
+	}
+	return unwrapConn(c.Context().Conn())
+}
+
+// Done returns a channel which is closed when the client closed the connection
+// or the request was handled, so streaming handlers and long pollers can stop
+// their work. The connection is checked in the DisconnectCheckInterval of the
+// app, or every 100ms if it is not set. Use IsAborted to tell both cases apart.
+func (c *DefaultCtx) Done() <-chan struct{} {
+	interval := c.app.config.DisconnectCheckInterval
+	if interval <= 0 {
+		interval = doneCheckInterval
+	}
+	return c.abort.doneChan(disconnectConn(c), interval)
+}
+
+// IsAborted reports if the client closed the connection of the request.
+// The response of an aborted request is discarded.
+func (c *DefaultCtx) IsAborted() bool {
+	if c.abort.aborted.Load() {
+		return true
+	}
+	if conn := disconnectConn(c); conn != nil && isConnClosed(conn) {
+		c.abort.abort()
+	}
+	return c.abort.aborted.Load()
+}
+
+// abortState returns the abort state of the request.
+func (c *DefaultCtx) abortState() *requestAbort {
+	return &c.abort
+}
@@ -0,0 +1,88 @@
+package fiber
+
+import (
+	"net"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_Ctx_Done
+func Test_Ctx_Done(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var done <-chan struct{}
+	app.Get("/", func(c Ctx) error {
+		done = c.Done()
+		require.False(t, c.IsAborted())
+		select {
+		case <-done:
+			t.Error("done channel is closed before the request was handled")
+		default:
+		}
+		return c.SendStatus(StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+
+	// closed after the request was handled
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("done channel is not closed after the request was handled")
+	}
+}
+
+// go test -run Test_Ctx_Done_Disconnect
+func Test_Ctx_Done_Disconnect(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("disconnect checks are not supported on windows")
+	}
+
+	started := make(chan struct{})
+	aborted := make(chan bool, 1)
+	hooked := make(chan string, 1)
+
+	app := New(Config{DisconnectCheckInterval: 5 * time.Millisecond})
+	app.Hooks().OnAbort(func(c Ctx) error {
+		hooked <- c.Path()
+		return nil
+	})
+	app.Get("/poll", func(c Ctx) error {
+		close(started)
+		select {
+		case <-c.Done():
+			aborted <- c.IsAborted()
+		case <-time.After(5 * time.Second):
+			aborted <- false
+		}
+		return c.SendStatus(StatusOK)
+	})
+
+	ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+	}()
+	t.Cleanup(func() {
+		assert.NoError(t, app.Shutdown())
+	})
+
+	conn, err := net.Dial(NetworkTCP4, ln.Addr().String())
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("GET /poll HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	require.NoError(t, err)
+
+	<-started
+	require.NoError(t, conn.Close())
+	require.True(t, <-aborted)
+	require.Equal(t, "/poll", <-hooked)
+}
@@ -0,0 +1,146 @@
+// Package cdn contains the helpers to integrate CDNs: the surrogate keys of the responses,
+// the purgers of Fastly, Cloudflare and CloudFront, and the signed URLs and cookies of
+// protected assets.
+package cdn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// The headers of the surrogate keys
+const (
+	// HeaderSurrogateKey is the header of Fastly, the keys are separated by spaces.
+	HeaderSurrogateKey = "Surrogate-Key"
+	// HeaderCacheTag is the header of Cloudflare, the keys are separated by commas.
+	HeaderCacheTag = "Cache-Tag"
+	// HeaderEdgeCacheTag is the header of Akamai, the keys are separated by commas.
+	HeaderEdgeCacheTag = "Edge-Cache-Tag"
+)
+
+var (
+	// ErrPurgeRequest is returned by the purgers if the API of the CDN responded with an error.
+	ErrPurgeRequest = errors.New("cdn: purge request failed")
+	// ErrNotSupported is returned by the purgers if the CDN doesn't support the operation.
+	ErrNotSupported = errors.New("cdn: operation not supported")
+)
+
+// Purger removes content from the caches of a CDN.
+type Purger interface {
+	// PurgeKeys purges the responses with the surrogate keys.
+	PurgeKeys(ctx context.Context, keys ...string) error
+	// PurgeURLs purges the responses of the URLs.
+	PurgeURLs(ctx context.Context, urls ...string) error
+}
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+const (
+	keysKey contextKey = iota
+)
+
+// New creates a new middleware handler, which emits the surrogate keys of the responses
+// in the headers of the config, so they can be purged by their keys.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if cfg.Keys != nil {
+			AddKeys(c, cfg.Keys(c)...)
+		}
+
+		// error responses aren't cached, so they have no keys
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		keys := Keys(c)
+		if len(keys) == 0 {
+			return nil
+		}
+		for _, header := range cfg.Headers {
+			separator := ","
+			if strings.EqualFold(header, HeaderSurrogateKey) {
+				separator = " "
+			}
+			c.Set(header, strings.Join(keys, separator))
+		}
+		return nil
+	}
+}
+
+// AddKeys adds the surrogate keys to the response, e.g. the ids of the rendered products.
+// Empty and duplicated keys are ignored.
+func AddKeys(c fiber.Ctx, keys ...string) {
+	existing := Keys(c)
+	for _, key := range keys {
+		if key == "" || containsKey(existing, key) {
+			continue
+		}
+		existing = append(existing, key)
+	}
+	c.Locals(keysKey, existing)
+}
+
+// Keys returns the surrogate keys of the response.
+func Keys(c fiber.Ctx) []string {
+	keys, _ := c.Locals(keysKey).([]string) //nolint:errcheck // A missing value has no keys
+	return keys
+}
+
+// containsKey reports whether the key is in the keys.
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// chunks splits the values into chunks of the maximum size of a request.
+func chunks(values []string, size int) [][]string {
+	var result [][]string
+	for len(values) > size {
+		result = append(result, values[:size])
+		values = values[size:]
+	}
+	if len(values) > 0 {
+		result = append(result, values)
+	}
+	return result
+}
+
+// send sends the request and returns the body of the response, statuses other
+// than 2xx are returned as ErrPurgeRequest.
+func send(client *http.Client, req *http.Request, name string) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cdn: failed to send %s request: %w", name, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cdn: failed to read %s response: %w", name, err)
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("%w: %s responded with %d: %s", ErrPurgeRequest, name, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
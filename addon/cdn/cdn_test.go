@@ -0,0 +1,150 @@
+package cdn
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_SurrogateKeys
+func Test_SurrogateKeys(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Headers: []string{HeaderSurrogateKey, HeaderCacheTag},
+		Keys: func(c fiber.Ctx) []string {
+			return []string{"route:" + c.Path()}
+		},
+	}))
+	app.Get("/products", func(c fiber.Ctx) error {
+		AddKeys(c, "products", "product:1", "product:2", "product:1", "")
+		return c.SendString("products")
+	})
+	app.Get("/error", func(c fiber.Ctx) error {
+		AddKeys(c, "products")
+		return fiber.ErrInternalServerError
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/products", nil))
+	require.NoError(t, err)
+	require.Equal(t, "route:/products products product:1 product:2", resp.Header.Get(HeaderSurrogateKey))
+	require.Equal(t, "route:/products,products,product:1,product:2", resp.Header.Get(HeaderCacheTag))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/error", nil))
+	require.NoError(t, err)
+	require.Empty(t, resp.Header.Get(HeaderSurrogateKey))
+}
+
+// go test -run Test_SurrogateKeys_Next
+func Test_SurrogateKeys_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString("index")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Empty(t, resp.Header.Get(HeaderSurrogateKey))
+}
+
+// go test -run Test_Fastly
+func Test_Fastly(t *testing.T) {
+	t.Parallel()
+	var requests []*http.Request
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if strings.Contains(r.URL.Path, "missing") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`)) //nolint:errcheck // It's a test
+	}))
+	defer api.Close()
+
+	purger := NewFastly(FastlyConfig{ServiceID: "svc", Token: "token", Endpoint: api.URL, Soft: true})
+	keys := make([]string, 300)
+	for i := range keys {
+		keys[i] = "k"
+	}
+	require.NoError(t, purger.PurgeKeys(context.Background(), keys...))
+	require.Len(t, requests, 2)
+	require.Equal(t, "/service/svc/purge", requests[0].URL.Path)
+	require.Equal(t, "token", requests[0].Header.Get("Fastly-Key"))
+	require.Equal(t, "1", requests[0].Header.Get("Fastly-Soft-Purge"))
+	require.Len(t, strings.Fields(requests[0].Header.Get(HeaderSurrogateKey)), 256)
+	require.Len(t, strings.Fields(requests[1].Header.Get(HeaderSurrogateKey)), 44)
+
+	require.NoError(t, purger.PurgeURLs(context.Background(), "https://www.example.com/products?page=2"))
+	require.Equal(t, "/purge/www.example.com/products", requests[2].URL.Path)
+	require.Equal(t, "page=2", requests[2].URL.RawQuery)
+
+	err := purger.PurgeURLs(context.Background(), "www.example.com/missing")
+	require.ErrorIs(t, err, ErrPurgeRequest)
+}
+
+// go test -run Test_Cloudflare
+func Test_Cloudflare(t *testing.T) {
+	t.Parallel()
+	var bodies []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/zones/zone/purge_cache", r.URL.Path)
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		if strings.Contains(string(body), "invalid") {
+			_, _ = w.Write([]byte(`{"success":false,"errors":[{"code":1012,"message":"Request must contain one of purge_everything, files, tags, hosts or prefixes"}]}`)) //nolint:errcheck // It's a test
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true,"errors":[]}`)) //nolint:errcheck // It's a test
+	}))
+	defer api.Close()
+
+	purger := NewCloudflare(CloudflareConfig{ZoneID: "zone", Token: "token", Endpoint: api.URL})
+	require.NoError(t, purger.PurgeKeys(context.Background(), "products", "product:1"))
+	require.JSONEq(t, `{"tags":["products","product:1"]}`, bodies[0])
+
+	require.NoError(t, purger.PurgeURLs(context.Background(), "https://www.example.com/products"))
+	require.JSONEq(t, `{"files":["https://www.example.com/products"]}`, bodies[1])
+
+	err := purger.PurgeKeys(context.Background(), "invalid")
+	require.ErrorIs(t, err, ErrPurgeRequest)
+	require.ErrorContains(t, err, "1012")
+}
+
+// go test -run Test_URLSigner
+func Test_URLSigner(t *testing.T) {
+	t.Parallel()
+	signer := NewURLSigner([]byte("secret"))
+	signed, err := signer.SignURL("https://cdn.example.com/videos/intro.mp4?quality=hd", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Contains(t, signed, "expires=")
+	require.Contains(t, signed, "signature=")
+
+	require.NoError(t, signer.Verify(signed))
+	// the origin verifies the path and the query of the request
+	require.NoError(t, signer.Verify(strings.TrimPrefix(signed, "https://cdn.example.com")))
+
+	require.ErrorIs(t, signer.Verify(strings.Replace(signed, "quality=hd", "quality=4k", 1)), ErrInvalidSignature)
+	require.ErrorIs(t, signer.Verify(strings.Replace(signed, "intro", "outro", 1)), ErrInvalidSignature)
+	require.ErrorIs(t, NewURLSigner([]byte("other")).Verify(signed), ErrInvalidSignature)
+	require.ErrorIs(t, signer.Verify("/videos/intro.mp4"), ErrInvalidSignature)
+
+	expired, err := signer.SignURL("/videos/intro.mp4", time.Now().Add(-time.Second))
+	require.NoError(t, err)
+	require.True(t, errors.Is(signer.Verify(expired), ErrExpired))
+}
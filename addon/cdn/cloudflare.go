@@ -0,0 +1,84 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// cloudflareMaxItems is the maximum number of tags or files of a purge request.
+const cloudflareMaxItems = 30
+
+// Cloudflare purges the content of a Cloudflare zone.
+type Cloudflare struct {
+	config CloudflareConfig
+}
+
+// cloudflareResponse is the response of the Cloudflare API.
+type cloudflareResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"errors"`
+	Success bool `json:"success"`
+}
+
+// NewCloudflare creates a purger of a Cloudflare zone.
+func NewCloudflare(config CloudflareConfig) *Cloudflare {
+	return &Cloudflare{config: cloudflareConfigDefault(config)}
+}
+
+// PurgeKeys purges the responses with the cache tags, up to 30 tags per request.
+func (cf *Cloudflare) PurgeKeys(ctx context.Context, keys ...string) error {
+	for _, chunk := range chunks(keys, cloudflareMaxItems) {
+		if err := cf.purge(ctx, map[string][]string{"tags": chunk}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PurgeURLs purges the responses of the URLs, up to 30 URLs per request.
+func (cf *Cloudflare) PurgeURLs(ctx context.Context, urls ...string) error {
+	for _, chunk := range chunks(urls, cloudflareMaxItems) {
+		if err := cf.purge(ctx, map[string][]string{"files": chunk}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purge sends a purge request with the body.
+func (cf *Cloudflare) purge(ctx context.Context, body map[string][]string) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("cdn: failed to encode cloudflare request: %w", err)
+	}
+	url := strings.TrimSuffix(cf.config.Endpoint, "/") + "/zones/" + cf.config.ZoneID + "/purge_cache"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("cdn: failed to create cloudflare request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+cf.config.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	respBody, err := send(cf.config.Client, req, "cloudflare")
+	if err != nil {
+		return err
+	}
+	var resp cloudflareResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("cdn: failed to decode cloudflare response: %w", err)
+	}
+	if !resp.Success {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, fmt.Sprintf("%d %s", e.Code, e.Message))
+		}
+		return fmt.Errorf("%w: cloudflare: %s", ErrPurgeRequest, strings.Join(messages, "; "))
+	}
+	return nil
+}
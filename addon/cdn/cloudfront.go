@@ -0,0 +1,229 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // CloudFront requires SHA-1 signatures
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// The names of the cookies of CloudFront
+const (
+	CookieCloudFrontPolicy    = "CloudFront-Policy"
+	CookieCloudFrontSignature = "CloudFront-Signature"
+	CookieCloudFrontKeyPairID = "CloudFront-Key-Pair-Id"
+)
+
+// cloudFrontAPIVersion is the version of the CloudFront API.
+const cloudFrontAPIVersion = "2020-05-31"
+
+// CloudFront purges the content of a CloudFront distribution with invalidations.
+type CloudFront struct {
+	config CloudFrontConfig
+}
+
+// cloudFrontInvalidation is the body of a CreateInvalidation request, the elements are
+// in the order of the schema of the API.
+type cloudFrontInvalidation struct {
+	XMLName xml.Name `xml:"http://cloudfront.amazonaws.com/doc/2020-05-31/ InvalidationBatch"`
+	Paths   struct {
+		Quantity int      `xml:"Quantity"`
+		Items    []string `xml:"Items>Path"`
+	} `xml:"Paths"`
+	CallerReference string `xml:"CallerReference"`
+}
+
+// NewCloudFront creates a purger of a CloudFront distribution.
+func NewCloudFront(config CloudFrontConfig) *CloudFront {
+	return &CloudFront{config: cloudFrontConfigDefault(config)}
+}
+
+// PurgeKeys returns ErrNotSupported, CloudFront can't purge by keys.
+func (*CloudFront) PurgeKeys(context.Context, ...string) error {
+	return fmt.Errorf("%w: cloudfront has no surrogate keys", ErrNotSupported)
+}
+
+// PurgeURLs creates an invalidation of the paths of the URLs, the URLs may also be
+// paths with wildcards, e.g. "/images/*".
+func (cf *CloudFront) PurgeURLs(ctx context.Context, urls ...string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	var batch cloudFrontInvalidation
+	batch.CallerReference = "fiber-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	for _, u := range urls {
+		path := u
+		if parsed, err := url.Parse(u); err == nil && parsed.Host != "" {
+			path = parsed.EscapedPath()
+			if parsed.RawQuery != "" {
+				path += "?" + parsed.RawQuery
+			}
+		}
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		batch.Paths.Items = append(batch.Paths.Items, path)
+	}
+	batch.Paths.Quantity = len(batch.Paths.Items)
+
+	body, err := xml.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("cdn: failed to encode cloudfront request: %w", err)
+	}
+	endpoint := strings.TrimSuffix(cf.config.Endpoint, "/") +
+		"/" + cloudFrontAPIVersion + "/distribution/" + cf.config.DistributionID + "/invalidation"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cdn: failed to create cloudfront request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/xml")
+	signV4(req, body, awsCredentials{
+		accessKeyID:     cf.config.AccessKeyID,
+		secretAccessKey: cf.config.SecretAccessKey,
+		sessionToken:    cf.config.SessionToken,
+	}, "us-east-1", "cloudfront", time.Now())
+
+	_, err = send(cf.config.Client, req, "cloudfront")
+	return err
+}
+
+// CloudFrontSigner creates the signed URLs and cookies of CloudFront, which grant access
+// to the content of a distribution which requires signed requests.
+type CloudFrontSigner struct {
+	key       *rsa.PrivateKey
+	keyPairID string
+}
+
+// cloudFrontPolicy is the policy of a signed URL or cookie.
+type cloudFrontPolicy struct {
+	Statement []cloudFrontStatement `json:"Statement"` //nolint:tagliatelle // the name is defined by CloudFront
+}
+
+// cloudFrontStatement is a statement of a cloudFrontPolicy.
+// The fields are in the order of the canned policy, which CloudFront verifies the signatures with.
+type cloudFrontStatement struct {
+	Resource  string `json:"Resource"` //nolint:tagliatelle // the name is defined by CloudFront
+	Condition struct {
+		DateLessThan struct {
+			EpochTime int64 `json:"AWS:EpochTime"` //nolint:tagliatelle // the name is defined by CloudFront
+		} `json:"DateLessThan"` //nolint:tagliatelle // the name is defined by CloudFront
+	} `json:"Condition"` //nolint:tagliatelle // the name is defined by CloudFront
+}
+
+// NewCloudFrontSigner creates a signer with the private key of the public key with the ID
+// of CloudFront.
+func NewCloudFrontSigner(keyPairID string, key *rsa.PrivateKey) *CloudFrontSigner {
+	if keyPairID == "" || key == nil {
+		panic("cdn: cloudfront signer requires a key pair id and a private key")
+	}
+	return &CloudFrontSigner{keyPairID: keyPairID, key: key}
+}
+
+// SignURL returns the URL signed with a canned policy, which grants access to the URL
+// until it expires.
+func (s *CloudFrontSigner) SignURL(rawURL string, expires time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("cdn: invalid url %q: %w", rawURL, err)
+	}
+	// the canned policy is signed, but not included in the URL
+	policy, err := s.policy(rawURL, expires)
+	if err != nil {
+		return "", err
+	}
+	signature, err := s.sign(policy)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.RawQuery
+	if query != "" {
+		query += "&"
+	}
+	u.RawQuery = query + "Expires=" + strconv.FormatInt(expires.Unix(), 10) +
+		"&Signature=" + signature + "&Key-Pair-Id=" + url.QueryEscape(s.keyPairID)
+	return u.String(), nil
+}
+
+// Cookies returns the signed cookies with a custom policy, which grant access to the resource
+// until it expires. The resource may contain wildcards, e.g. "https://cdn.example.com/videos/*".
+// The cookies are secure and HTTP only, their domain must be set to the domain of the distribution
+// if it differs from the domain of the app.
+func (s *CloudFrontSigner) Cookies(resource string, expires time.Time) ([]*fiber.Cookie, error) {
+	policy, err := s.policy(resource, expires)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := s.sign(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{
+		CookieCloudFrontPolicy:    cloudFrontEncode(policy),
+		CookieCloudFrontSignature: signature,
+		CookieCloudFrontKeyPairID: s.keyPairID,
+	}
+	cookies := make([]*fiber.Cookie, 0, len(values))
+	for _, name := range []string{CookieCloudFrontPolicy, CookieCloudFrontSignature, CookieCloudFrontKeyPairID} {
+		cookies = append(cookies, &fiber.Cookie{
+			Name:     name,
+			Value:    values[name],
+			Path:     "/",
+			Expires:  expires,
+			Secure:   true,
+			HTTPOnly: true,
+		})
+	}
+	return cookies, nil
+}
+
+// policy returns the policy of the resource in the format of the canned policy.
+func (*CloudFrontSigner) policy(resource string, expires time.Time) ([]byte, error) {
+	if resource == "" {
+		return nil, errors.New("cdn: the resource of a cloudfront policy is required")
+	}
+	var statement cloudFrontStatement
+	statement.Resource = resource
+	statement.Condition.DateLessThan.EpochTime = expires.Unix()
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	// the URLs must be signed as they are
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(cloudFrontPolicy{Statement: []cloudFrontStatement{statement}}); err != nil {
+		return nil, fmt.Errorf("cdn: failed to encode cloudfront policy: %w", err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// sign returns the encoded RSA-SHA1 signature of the policy.
+func (s *CloudFrontSigner) sign(policy []byte) (string, error) {
+	hash := sha1.Sum(policy) //nolint:gosec // CloudFront requires SHA-1 signatures
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA1, hash[:])
+	if err != nil {
+		return "", fmt.Errorf("cdn: failed to sign cloudfront policy: %w", err)
+	}
+	return cloudFrontEncode(signature), nil
+}
+
+// cloudFrontEncode encodes the value with base64, with the characters which are
+// invalid in URLs replaced like CloudFront expects.
+func cloudFrontEncode(value []byte) string {
+	return strings.NewReplacer("+", "-", "=", "_", "/", "~").Replace(base64.StdEncoding.EncodeToString(value))
+}
@@ -0,0 +1,113 @@
+package cdn
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // CloudFront requires SHA-1 signatures
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// cloudFrontDecode decodes a value which was encoded with cloudFrontEncode.
+func cloudFrontDecode(t *testing.T, value string) []byte {
+	t.Helper()
+	decoded, err := base64.StdEncoding.DecodeString(strings.NewReplacer("-", "+", "_", "=", "~", "/").Replace(value))
+	require.NoError(t, err)
+	return decoded
+}
+
+// go test -run Test_SignV4
+func Test_SignV4(t *testing.T) {
+	t.Parallel()
+	// the example of the AWS documentation
+	req := httptest.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signV4(req, nil, awsCredentials{
+		accessKeyID:     "AKIDEXAMPLE",
+		secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	require.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	require.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, "+
+		"SignedHeaders=content-type;host;x-amz-date, "+
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7", req.Header.Get("Authorization"))
+}
+
+// go test -run Test_CloudFront
+func Test_CloudFront(t *testing.T) {
+	t.Parallel()
+	var (
+		request *http.Request
+		body    string
+	)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		request, body = r, string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer api.Close()
+
+	purger := NewCloudFront(CloudFrontConfig{
+		DistributionID:  "EDFDVBD6EXAMPLE",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+		Endpoint:        api.URL,
+	})
+	require.ErrorIs(t, purger.PurgeKeys(context.Background(), "products"), ErrNotSupported)
+
+	require.NoError(t, purger.PurgeURLs(context.Background(), "https://www.example.com/products?page=2", "images/*"))
+	require.Equal(t, "/2020-05-31/distribution/EDFDVBD6EXAMPLE/invalidation", request.URL.Path)
+	require.Contains(t, body, "<Quantity>2</Quantity><Items><Path>/products?page=2</Path><Path>/images/*</Path></Items>")
+	require.Contains(t, body, `<InvalidationBatch xmlns="http://cloudfront.amazonaws.com/doc/2020-05-31/">`)
+	require.Contains(t, request.Header.Get("Authorization"), "Credential=AKIDEXAMPLE/")
+	require.Contains(t, request.Header.Get("Authorization"), "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token")
+	require.Equal(t, "session", request.Header.Get("X-Amz-Security-Token"))
+}
+
+// go test -run Test_CloudFrontSigner
+func Test_CloudFrontSigner(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	signer := NewCloudFrontSigner("K2JCJMDEHXQW5F", key)
+	expires := time.Unix(1767225600, 0)
+
+	signed, err := signer.SignURL("https://d111111abcdef8.cloudfront.net/image.jpg?size=large", expires)
+	require.NoError(t, err)
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	query := u.Query()
+	require.Equal(t, "large", query.Get("size"))
+	require.Equal(t, "1767225600", query.Get("Expires"))
+	require.Equal(t, "K2JCJMDEHXQW5F", query.Get("Key-Pair-Id"))
+
+	// the signature is of the canned policy of the URL without the parameters of the signature
+	policy := `{"Statement":[{"Resource":"https://d111111abcdef8.cloudfront.net/image.jpg?size=large",` +
+		`"Condition":{"DateLessThan":{"AWS:EpochTime":1767225600}}}]}`
+	hash := sha1.Sum([]byte(policy)) //nolint:gosec // CloudFront requires SHA-1 signatures
+	require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA1, hash[:], cloudFrontDecode(t, query.Get("Signature"))))
+
+	cookies, err := signer.Cookies("https://d111111abcdef8.cloudfront.net/videos/*", expires)
+	require.NoError(t, err)
+	require.Len(t, cookies, 3)
+	require.Equal(t, CookieCloudFrontPolicy, cookies[0].Name)
+	require.Equal(t, `{"Statement":[{"Resource":"https://d111111abcdef8.cloudfront.net/videos/*",`+
+		`"Condition":{"DateLessThan":{"AWS:EpochTime":1767225600}}}]}`, string(cloudFrontDecode(t, cookies[0].Value)))
+	hash = sha1.Sum(cloudFrontDecode(t, cookies[0].Value)) //nolint:gosec // CloudFront requires SHA-1 signatures
+	require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA1, hash[:], cloudFrontDecode(t, cookies[1].Value)))
+	require.Equal(t, "K2JCJMDEHXQW5F", cookies[2].Value)
+	require.True(t, cookies[1].Secure)
+	require.True(t, cookies[1].HTTPOnly)
+}
@@ -0,0 +1,203 @@
+package cdn
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config of the surrogate keys middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Keys returns the surrogate keys of every request, e.g. of the route, the handlers add
+	// their keys with AddKeys.
+	//
+	// Optional. Default: nil
+	Keys func(c fiber.Ctx) []string
+
+	// Headers are the headers of the surrogate keys, the keys are separated by spaces in the
+	// Surrogate-Key header of Fastly and by commas in the other headers, e.g. Cache-Tag of Cloudflare.
+	//
+	// Optional. Default: []string{"Surrogate-Key"}
+	Headers []string
+}
+
+// ConfigDefault is the default config of the surrogate keys middleware.
+var ConfigDefault = Config{
+	Next:    nil,
+	Headers: []string{HeaderSurrogateKey},
+}
+
+// configDefault sets the config values if they are not set.
+func configDefault(config ...Config) Config {
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+	cfg := config[0]
+	if len(cfg.Headers) == 0 {
+		cfg.Headers = ConfigDefault.Headers
+	}
+	return cfg
+}
+
+// FastlyConfig defines the config of the Fastly purger.
+type FastlyConfig struct {
+	// ServiceID is the ID of the Fastly service.
+	//
+	// Required.
+	ServiceID string
+
+	// Token is the API token, which requires the purge_select scope.
+	//
+	// Optional. Default: the FASTLY_API_TOKEN environment variable
+	Token string
+
+	// Endpoint is the URL of the Fastly API.
+	//
+	// Optional. Default: "https://api.fastly.com"
+	Endpoint string
+
+	// Client sends the requests to the API.
+	//
+	// Optional. Default: &http.Client{Timeout: 10 * time.Second}
+	Client *http.Client
+
+	// Soft marks the content as stale instead of removing it, so it can still be served
+	// with stale-while-revalidate and stale-if-error.
+	//
+	// Optional. Default: false
+	Soft bool
+}
+
+// FastlyConfigDefault is the default config of the Fastly purger.
+var FastlyConfigDefault = FastlyConfig{
+	Endpoint: "https://api.fastly.com",
+}
+
+// fastlyConfigDefault sets the config values if they are not set.
+func fastlyConfigDefault(config FastlyConfig) FastlyConfig {
+	cfg := config
+	if cfg.ServiceID == "" {
+		panic("cdn: fastly purger requires a service id")
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("FASTLY_API_TOKEN")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = FastlyConfigDefault.Endpoint
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return cfg
+}
+
+// CloudflareConfig defines the config of the Cloudflare purger.
+type CloudflareConfig struct {
+	// ZoneID is the ID of the Cloudflare zone.
+	//
+	// Required.
+	ZoneID string
+
+	// Token is the API token, which requires the Cache Purge permission.
+	//
+	// Optional. Default: the CLOUDFLARE_API_TOKEN environment variable
+	Token string
+
+	// Endpoint is the URL of the Cloudflare API.
+	//
+	// Optional. Default: "https://api.cloudflare.com/client/v4"
+	Endpoint string
+
+	// Client sends the requests to the API.
+	//
+	// Optional. Default: &http.Client{Timeout: 10 * time.Second}
+	Client *http.Client
+}
+
+// CloudflareConfigDefault is the default config of the Cloudflare purger.
+var CloudflareConfigDefault = CloudflareConfig{
+	Endpoint: "https://api.cloudflare.com/client/v4",
+}
+
+// cloudflareConfigDefault sets the config values if they are not set.
+func cloudflareConfigDefault(config CloudflareConfig) CloudflareConfig {
+	cfg := config
+	if cfg.ZoneID == "" {
+		panic("cdn: cloudflare purger requires a zone id")
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("CLOUDFLARE_API_TOKEN")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = CloudflareConfigDefault.Endpoint
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return cfg
+}
+
+// CloudFrontConfig defines the config of the CloudFront purger.
+type CloudFrontConfig struct {
+	// DistributionID is the ID of the CloudFront distribution.
+	//
+	// Required.
+	DistributionID string
+
+	// AccessKeyID is the access key of the AWS credentials.
+	//
+	// Optional. Default: the AWS_ACCESS_KEY_ID environment variable
+	AccessKeyID string
+
+	// SecretAccessKey is the secret key of the AWS credentials.
+	//
+	// Optional. Default: the AWS_SECRET_ACCESS_KEY environment variable
+	SecretAccessKey string
+
+	// SessionToken is the session token of temporary AWS credentials.
+	//
+	// Optional. Default: the AWS_SESSION_TOKEN environment variable
+	SessionToken string
+
+	// Endpoint is the URL of the CloudFront API.
+	//
+	// Optional. Default: "https://cloudfront.amazonaws.com"
+	Endpoint string
+
+	// Client sends the requests to the API.
+	//
+	// Optional. Default: &http.Client{Timeout: 10 * time.Second}
+	Client *http.Client
+}
+
+// CloudFrontConfigDefault is the default config of the CloudFront purger.
+var CloudFrontConfigDefault = CloudFrontConfig{
+	Endpoint: "https://cloudfront.amazonaws.com",
+}
+
+// cloudFrontConfigDefault sets the config values if they are not set.
+func cloudFrontConfigDefault(config CloudFrontConfig) CloudFrontConfig {
+	cfg := config
+	if cfg.DistributionID == "" {
+		panic("cdn: cloudfront purger requires a distribution id")
+	}
+	if cfg.AccessKeyID == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = CloudFrontConfigDefault.Endpoint
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return cfg
+}
@@ -0,0 +1,66 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// fastlyMaxKeys is the maximum number of surrogate keys of a purge request.
+const fastlyMaxKeys = 256
+
+// Fastly purges the content of a Fastly service.
+type Fastly struct {
+	config FastlyConfig
+}
+
+// NewFastly creates a purger of a Fastly service.
+func NewFastly(config FastlyConfig) *Fastly {
+	return &Fastly{config: fastlyConfigDefault(config)}
+}
+
+// PurgeKeys purges the responses with the surrogate keys, up to 256 keys per request.
+func (f *Fastly) PurgeKeys(ctx context.Context, keys ...string) error {
+	for _, chunk := range chunks(keys, fastlyMaxKeys) {
+		req, err := f.newRequest(ctx, "/service/"+f.config.ServiceID+"/purge")
+		if err != nil {
+			return err
+		}
+		req.Header.Set(HeaderSurrogateKey, strings.Join(chunk, " "))
+		if _, err := send(f.config.Client, req, "fastly"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PurgeURLs purges the responses of the URLs, one request per URL.
+func (f *Fastly) PurgeURLs(ctx context.Context, urls ...string) error {
+	for _, u := range urls {
+		// the cached URL is passed without the scheme
+		cached := strings.TrimPrefix(strings.TrimPrefix(u, "https://"), "http://")
+		req, err := f.newRequest(ctx, "/purge/"+cached)
+		if err != nil {
+			return err
+		}
+		if _, err := send(f.config.Client, req, "fastly"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newRequest creates an authenticated purge request of the path.
+func (f *Fastly) newRequest(ctx context.Context, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(f.config.Endpoint, "/")+path, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("cdn: failed to create fastly request: %w", err)
+	}
+	req.Header.Set("Fastly-Key", f.config.Token)
+	req.Header.Set("Accept", "application/json")
+	if f.config.Soft {
+		req.Header.Set("Fastly-Soft-Purge", "1")
+	}
+	return req, nil
+}
@@ -0,0 +1,68 @@
+package mail
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/addon/retry"
+)
+
+// Config defines the config for addon.
+type Config struct {
+	// Views renders the templates of EnqueueTemplate, e.g. the Views of the app.
+	//
+	// Optional. Default: nil
+	Views fiber.Views
+
+	// OnError is called with the messages which weren't delivered, after all retries
+	// failed, for permanent errors of the server, or when the queue was closed.
+	//
+	// Optional. Default: nil, the errors are logged
+	OnError func(msg *Message, err error)
+
+	// Retry configures the exponential backoff of the retries.
+	//
+	// Optional. Default: retry.DefaultConfig
+	Retry retry.Config
+
+	// Timeout limits the time of a delivery attempt.
+	//
+	// Optional. Default: 30 * time.Second
+	Timeout time.Duration
+
+	// Workers is the number of concurrent deliveries.
+	//
+	// Optional. Default: 2
+	Workers int
+
+	// QueueSize is the number of messages which are queued, Enqueue fails with ErrQueueFull beyond it.
+	//
+	// Optional. Default: 1000
+	QueueSize int
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Retry:     retry.DefaultConfig,
+	Timeout:   30 * time.Second,
+	Workers:   2,
+	QueueSize: 1000,
+}
+
+// configDefault sets the config values if they are not set.
+func configDefault(config ...Config) Config {
+	if len(config) == 0 {
+		return ConfigDefault
+	}
+	cfg := config[0]
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = ConfigDefault.Timeout
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = ConfigDefault.Workers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = ConfigDefault.QueueSize
+	}
+	return cfg
+}
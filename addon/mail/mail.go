@@ -0,0 +1,170 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"sync"
+
+	"github.com/gofiber/fiber/v3/addon/retry"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+var (
+	// ErrClosed is returned by Enqueue after Close, and passed to OnError for
+	// the messages which weren't delivered before the context of Close was done.
+	ErrClosed = errors.New("mail: the queue is closed")
+	// ErrQueueFull is returned by Enqueue if QueueSize messages are queued.
+	ErrQueueFull = errors.New("mail: the queue is full")
+	// ErrNoViews is returned by EnqueueTemplate if the config has no Views.
+	ErrNoViews = errors.New("mail: the config has no views")
+)
+
+// Mailer sends messages, e.g. with SMTP or the API of an email service.
+type Mailer interface {
+	// Send sends the message, it returns when the context is done.
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Queue delivers the messages of handlers in the background with a Mailer, so the handlers
+// don't wait for the server. Failed deliveries are retried with exponential backoff,
+// except for permanent errors of SMTP servers, e.g. unknown recipients.
+type Queue struct {
+	mailer Mailer
+	jobs   chan *Message
+	abort  chan struct{}
+	cfg    Config
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	once   sync.Once
+	closed bool
+}
+
+// New creates a queue of the mailer and starts its workers. Close the queue on shutdown,
+// so the queued messages are delivered:
+//
+//	app.Hooks().OnShutdownNamed("mail", queue.Close)
+func New(mailer Mailer, config ...Config) *Queue {
+	cfg := configDefault(config...)
+	q := &Queue{
+		mailer: mailer,
+		cfg:    cfg,
+		jobs:   make(chan *Message, cfg.QueueSize),
+		abort:  make(chan struct{}),
+	}
+	q.wg.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go func() {
+			defer q.wg.Done()
+			for msg := range q.jobs {
+				q.deliver(msg)
+			}
+		}()
+	}
+	return q
+}
+
+// Enqueue validates the message and queues it for the delivery, it doesn't block.
+func (q *Queue) Enqueue(msg *Message) error {
+	if _, err := msg.Recipients(); err != nil {
+		return err
+	}
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrClosed
+	}
+	select {
+	case q.jobs <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// EnqueueTemplate renders the template with the Views of the config as HTML body of the message
+// and queues it. The template is rendered before EnqueueTemplate returns, so its errors are returned.
+func (q *Queue) EnqueueTemplate(msg *Message, name string, bind any, layouts ...string) error {
+	if q.cfg.Views == nil {
+		return ErrNoViews
+	}
+	var buf bytes.Buffer
+	if err := q.cfg.Views.Render(&buf, name, bind, layouts...); err != nil {
+		return fmt.Errorf("mail: failed to render %s: %w", name, err)
+	}
+	msg.HTML = buf.String()
+	return q.Enqueue(msg)
+}
+
+// Close stops accepting messages and waits until the queued messages are delivered or the context
+// is done. Then the pending messages are passed to OnError with ErrClosed, retries which are
+// already waiting finish their backoff first.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.once.Do(func() {
+			close(q.abort)
+		})
+		return fmt.Errorf("mail: failed to deliver the queued messages: %w", ctx.Err())
+	}
+}
+
+// deliver sends the message with retries, the errors are passed to OnError.
+func (q *Queue) deliver(msg *Message) {
+	var final error
+	err := retry.NewExponentialBackoff(q.cfg.Retry).Retry(func() error {
+		select {
+		case <-q.abort:
+			final = ErrClosed
+			return nil
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), q.cfg.Timeout)
+		defer cancel()
+		err := q.mailer.Send(ctx, msg)
+		if err != nil && isPermanent(err) {
+			final = err
+			return nil
+		}
+		return err
+	})
+	if err == nil {
+		err = final
+	}
+	if err == nil {
+		return
+	}
+
+	if q.cfg.OnError != nil {
+		q.cfg.OnError(msg, err)
+		return
+	}
+	log.Errorw("mail: failed to deliver the message", "subject", msg.Subject, "error", err)
+}
+
+// isPermanent reports if the error is a permanent error of an SMTP server, which isn't retried.
+func isPermanent(err error) bool {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 500
+	}
+	return errors.Is(err, ErrNoSender) || errors.Is(err, ErrNoRecipients) || errors.Is(err, ErrInvalidHeader)
+}
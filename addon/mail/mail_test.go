@@ -0,0 +1,285 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3/addon/retry"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpServer is a minimal SMTP server, which records the commands and the data of the messages.
+type smtpServer struct {
+	ln       net.Listener
+	commands []string
+	data     []string
+	mu       sync.Mutex
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpServer{ln: ln}
+	t.Cleanup(func() {
+		require.NoError(t, ln.Close())
+	})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close() //nolint:errcheck // the test server doesn't care
+	r := textproto.NewReader(bufio.NewReader(conn))
+	reply := func(line string) {
+		_, _ = io.WriteString(conn, line+"\r\n") //nolint:errcheck // the test server doesn't care
+	}
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadLine()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+
+		switch verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); verb {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			reply("235 2.7.0 Authentication successful")
+		case "RCPT":
+			if strings.Contains(line, "unknown@") {
+				reply("550 5.1.1 No such user")
+				continue
+			}
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := r.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = append(s.data, string(data))
+			s.mu.Unlock()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// go test -run Test_Message_Bytes
+func Test_Message_Bytes(t *testing.T) {
+	t.Parallel()
+	msg := &Message{
+		From:    "Shop <shop@example.com>",
+		To:      []string{"Jäne <jane@example.com>"},
+		Bcc:     []string{"audit@example.com"},
+		Subject: "Your order — #1",
+		Text:    "Thanks!",
+		HTML:    "<p>Thanks!</p>",
+		Headers: map[string]string{"list-unsubscribe": "<https://example.com/unsubscribe>"},
+	}
+	data, err := msg.Bytes()
+	require.NoError(t, err)
+
+	parsed, err := netmail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	to, err := parsed.Header.AddressList("To")
+	require.NoError(t, err)
+	require.Equal(t, "Jäne", to[0].Name)
+	decoded, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Your order — #1", decoded)
+	require.Equal(t, "<https://example.com/unsubscribe>", parsed.Header.Get("List-Unsubscribe"))
+	require.Empty(t, parsed.Header.Get("Bcc"))
+	require.True(t, strings.HasSuffix(parsed.Header.Get("Message-Id"), "@example.com>"))
+	require.True(t, strings.HasPrefix(parsed.Header.Get("Content-Type"), "multipart/alternative; boundary="))
+	require.Contains(t, string(data), "<p>Thanks!</p>")
+
+	recipients, err := msg.Recipients()
+	require.NoError(t, err)
+	require.Equal(t, []string{"jane@example.com", "audit@example.com"}, recipients)
+
+	_, err = (&Message{To: []string{"jane@example.com"}}).Bytes()
+	require.ErrorIs(t, err, ErrNoSender)
+	_, err = (&Message{From: "shop@example.com"}).Bytes()
+	require.ErrorIs(t, err, ErrNoRecipients)
+	_, err = (&Message{
+		From: "shop@example.com", To: []string{"jane@example.com"},
+		Headers: map[string]string{"X-Note": "a\r\nBcc: victim@example.com"},
+	}).Bytes()
+	require.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+// go test -run Test_SMTP
+func Test_SMTP(t *testing.T) {
+	t.Parallel()
+	server := newSMTPServer(t)
+	mailer := NewSMTP(SMTPConfig{
+		Addr:     server.ln.Addr().String(),
+		Username: "user",
+		Password: "pass",
+		From:     "shop@example.com",
+	})
+
+	err := mailer.Send(context.Background(), &Message{
+		To:      []string{"jane@example.com"},
+		Cc:      []string{"bob@example.com"},
+		Subject: "Hello",
+		Text:    "Hi Jane",
+	})
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	require.Contains(t, server.commands, "MAIL FROM:<shop@example.com>")
+	require.Contains(t, server.commands, "RCPT TO:<bob@example.com>")
+	require.True(t, strings.HasPrefix(server.commands[1], "AUTH PLAIN "))
+	require.Len(t, server.data, 1)
+	require.Contains(t, server.data[0], "Subject: Hello\n")
+	require.Contains(t, server.data[0], "Hi Jane")
+	server.mu.Unlock()
+
+	// the server rejects the recipient permanently
+	err = mailer.Send(context.Background(), &Message{To: []string{"unknown@example.com"}, Text: "x"})
+	require.True(t, isPermanent(err))
+
+	require.Panics(t, func() {
+		NewSMTP(SMTPConfig{Addr: "localhost"})
+	})
+}
+
+// mailerFunc is a Mailer of a function.
+type mailerFunc func(ctx context.Context, msg *Message) error
+
+func (f mailerFunc) Send(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// go test -run Test_Queue
+func Test_Queue(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	var failed []string
+	mailer := mailerFunc(func(_ context.Context, msg *Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[msg.Subject]++
+		switch {
+		case msg.Subject == "flaky" && attempts[msg.Subject] < 3:
+			return errors.New("connection reset")
+		case msg.Subject == "rejected":
+			return &textproto.Error{Code: 550, Msg: "No such user"}
+		}
+		return nil
+	})
+
+	queue := New(mailer, Config{
+		Retry: retry.Config{InitialInterval: time.Millisecond, MaxBackoffTime: 2 * time.Millisecond, MaxRetryCount: 5},
+		Views: testViews{},
+		OnError: func(msg *Message, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, msg.Subject+": "+err.Error())
+		},
+	})
+
+	to := []string{"jane@example.com"}
+	require.NoError(t, queue.Enqueue(&Message{Subject: "flaky", To: to}))
+	require.NoError(t, queue.Enqueue(&Message{Subject: "rejected", To: to}))
+	welcome := &Message{Subject: "welcome", To: to}
+	require.NoError(t, queue.EnqueueTemplate(welcome, "welcome", map[string]string{"Name": "<Jane>"}))
+	require.Equal(t, "<p>Hi &lt;Jane&gt;</p>", welcome.HTML)
+	require.ErrorIs(t, queue.Enqueue(&Message{Subject: "nobody"}), ErrNoRecipients)
+
+	require.NoError(t, queue.Close(context.Background()))
+	require.ErrorIs(t, queue.Enqueue(&Message{Subject: "late", To: to}), ErrClosed)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, map[string]int{"flaky": 3, "rejected": 1, "welcome": 1}, attempts)
+	require.Equal(t, []string{`rejected: 550 "No such user"`}, failed)
+}
+
+// go test -run Test_Queue_Close
+func Test_Queue_Close(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var failed []error
+	queue := New(mailerFunc(func(_ context.Context, _ *Message) error {
+		<-release
+		return nil
+	}), Config{
+		Workers:   1,
+		QueueSize: 2,
+		OnError: func(_ *Message, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, err)
+		},
+	})
+
+	to := []string{"jane@example.com"}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, queue.Enqueue(&Message{To: to}))
+		if i == 0 {
+			// the worker takes the first message
+			require.Eventually(t, func() bool { return len(queue.jobs) == 0 }, time.Second, time.Millisecond)
+		}
+	}
+	require.ErrorIs(t, queue.Enqueue(&Message{To: to}), ErrQueueFull)
+
+	// the pending messages aren't delivered after the context of Close is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, queue.Close(ctx), context.DeadlineExceeded)
+	close(release)
+	require.NoError(t, queue.Close(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []error{ErrClosed, ErrClosed}, failed)
+
+	queue = New(mailerFunc(nil))
+	require.ErrorIs(t, queue.EnqueueTemplate(&Message{To: to}, "welcome", nil), ErrNoViews)
+	require.NoError(t, queue.Close(context.Background()))
+}
+
+// testViews renders the welcome template.
+type testViews struct{}
+
+func (testViews) Load() error {
+	return nil
+}
+
+func (testViews) Render(out io.Writer, _ string, binding any, _ ...string) error {
+	return template.Must(template.New("").Parse(`<p>Hi {{ .Name }}</p>`)).Execute(out, binding)
+}
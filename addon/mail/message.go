@@ -0,0 +1,183 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoSender is returned for messages without From address if the mailer has no default sender.
+	ErrNoSender = errors.New("mail: the message has no sender")
+	// ErrNoRecipients is returned for messages without To, Cc and Bcc addresses.
+	ErrNoRecipients = errors.New("mail: the message has no recipients")
+	// ErrInvalidHeader is returned for headers which contain line breaks.
+	ErrInvalidHeader = errors.New("mail: invalid header")
+)
+
+// Message is an email. The addresses are RFC 5322 addresses, e.g. "Jane <jane@example.com>".
+type Message struct {
+	// Headers are additional headers, e.g. "List-Unsubscribe".
+	Headers map[string]string
+	// From is the sender, the default sender of the mailer if it's empty.
+	From string
+	// ReplyTo is the address of the replies.
+	ReplyTo string
+	// Subject is the subject, it may contain any UTF-8 characters.
+	Subject string
+	// Text is the plain text body.
+	Text string
+	// HTML is the HTML body, the message is sent as multipart/alternative if it has a Text too.
+	HTML string
+	// To, Cc and Bcc are the recipients, the Bcc addresses aren't written to the headers.
+	To  []string
+	Cc  []string
+	Bcc []string
+}
+
+// Recipients returns the addresses of all recipients, without names.
+func (m *Message) Recipients() ([]string, error) {
+	recipients := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	for _, list := range [][]string{m.To, m.Cc, m.Bcc} {
+		for _, address := range list {
+			parsed, err := netmail.ParseAddress(address)
+			if err != nil {
+				return nil, fmt.Errorf("mail: invalid recipient %q: %w", address, err)
+			}
+			recipients = append(recipients, parsed.Address)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+	return recipients, nil
+}
+
+// Bytes returns the message in the MIME format, e.g. for the raw APIs of email services.
+func (m *Message) Bytes() ([]byte, error) {
+	from, err := netmail.ParseAddress(m.From)
+	if err != nil {
+		if m.From == "" {
+			return nil, ErrNoSender
+		}
+		return nil, fmt.Errorf("mail: invalid sender %q: %w", m.From, err)
+	}
+	if _, err := m.Recipients(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	header := func(name, value string) error {
+		if strings.ContainsAny(name, "\r\n:") || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("%w: %s", ErrInvalidHeader, name)
+		}
+		buf.WriteString(textproto.CanonicalMIMEHeaderKey(name) + ": " + value + "\r\n")
+		return nil
+	}
+	addresses := func(name string, list []string) error {
+		if len(list) == 0 {
+			return nil
+		}
+		formatted := make([]string, len(list))
+		for i, address := range list {
+			parsed, _ := netmail.ParseAddress(address) //nolint:errcheck // validated by Recipients
+			formatted[i] = parsed.String()
+		}
+		return header(name, strings.Join(formatted, ", "))
+	}
+
+	domain := from.Address[strings.LastIndexByte(from.Address, '@')+1:]
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("mail: failed to generate the message id: %w", err)
+	}
+	headers := [][2]string{
+		{"From", from.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", "<" + hex.EncodeToString(id) + "@" + domain + ">"},
+		{"MIME-Version", "1.0"},
+	}
+	if m.ReplyTo != "" {
+		replyTo, err := netmail.ParseAddress(m.ReplyTo)
+		if err != nil {
+			return nil, fmt.Errorf("mail: invalid reply-to %q: %w", m.ReplyTo, err)
+		}
+		headers = append(headers, [2]string{"Reply-To", replyTo.String()})
+	}
+	for _, h := range headers {
+		if err := header(h[0], h[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err := addresses("To", m.To); err != nil {
+		return nil, err
+	}
+	if err := addresses("Cc", m.Cc); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(m.Headers))
+	for name := range m.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := header(name, m.Headers[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.Text == "" || m.HTML == "" {
+		contentType, body := "text/plain; charset=utf-8", m.Text
+		if m.HTML != "" {
+			contentType, body = "text/html; charset=utf-8", m.HTML
+		}
+		buf.WriteString("Content-Type: " + contentType + "\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	buf.WriteString("Content-Type: multipart/alternative; boundary=" + mw.Boundary() + "\r\n\r\n")
+	for _, part := range [][2]string{{"text/plain; charset=utf-8", m.Text}, {"text/html; charset=utf-8", m.HTML}} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part[0]},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("mail: failed to write the body: %w", err)
+		}
+		if err := writeQuotedPrintable(w, part[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("mail: failed to write the body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeQuotedPrintable writes the body encoded as quoted-printable.
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qw := quotedprintable.NewWriter(w)
+	if _, err := qw.Write([]byte(body)); err != nil {
+		return fmt.Errorf("mail: failed to write the body: %w", err)
+	}
+	if err := qw.Close(); err != nil {
+		return fmt.Errorf("mail: failed to write the body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+)
+
+// ErrNoAuth is returned by SMTP if it has credentials but the server doesn't support authentication.
+var ErrNoAuth = errors.New("mail: the smtp server doesn't support authentication")
+
+// SMTPConfig defines the config of SMTP.
+type SMTPConfig struct {
+	// TLSConfig is the TLS config of STARTTLS and implicit TLS.
+	//
+	// Optional. Default: a config with the host of Addr as server name
+	TLSConfig *tls.Config
+
+	// Addr is the address of the server, e.g. "smtp.example.com:587".
+	//
+	// Required. Default: ""
+	Addr string
+
+	// Username and Password are the credentials of PLAIN authentication.
+	//
+	// Optional. Default: ""
+	Username string
+	Password string
+
+	// From is the default sender of the messages without From address.
+	//
+	// Optional. Default: ""
+	From string
+
+	// LocalName is the host name which is sent with EHLO.
+	//
+	// Optional. Default: "localhost"
+	LocalName string
+
+	// ImplicitTLS connects with TLS, e.g. to port 465, instead of upgrading the connection with STARTTLS.
+	//
+	// Optional. Default: false
+	ImplicitTLS bool
+}
+
+// SMTP is a Mailer which sends the messages to an SMTP server. The connection is upgraded
+// with STARTTLS if the server supports it.
+type SMTP struct {
+	config SMTPConfig
+	host   string
+}
+
+// NewSMTP creates a mailer of the SMTP server.
+func NewSMTP(config SMTPConfig) *SMTP {
+	host, _, err := net.SplitHostPort(config.Addr)
+	if err != nil {
+		panic(fmt.Sprintf("mail: invalid smtp address %q: %v", config.Addr, err))
+	}
+	if config.TLSConfig == nil {
+		config.TLSConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	}
+	if config.LocalName == "" {
+		config.LocalName = "localhost"
+	}
+	return &SMTP{config: config, host: host}
+}
+
+// Send sends the message, the connection is closed when the context is done.
+func (s *SMTP) Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		withSender := *msg
+		withSender.From = s.config.From
+		msg = &withSender
+	}
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+	recipients, err := msg.Recipients()
+	if err != nil {
+		return err
+	}
+	from, _ := netmail.ParseAddress(msg.From) //nolint:errcheck // validated by Bytes
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("mail: failed to connect to %s: %w", s.config.Addr, err)
+	}
+	if s.config.ImplicitTLS {
+		conn = tls.Client(conn, s.config.TLSConfig)
+	}
+	// the blocking reads and writes of the client are interrupted when the context is done
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now()) //nolint:errcheck // the connection fails anyway
+	})
+	defer stop()
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close() //nolint:errcheck // the handshake failed already
+		return fmt.Errorf("mail: failed to connect to %s: %w", s.config.Addr, err)
+	}
+	defer client.Close() //nolint:errcheck // the message was sent or failed already
+
+	if err := s.send(client, from.Address, recipients, data); err != nil {
+		return fmt.Errorf("mail: failed to send the message: %w", err)
+	}
+	return nil
+}
+
+// send sends the message with the connected client.
+func (s *SMTP) send(client *smtp.Client, from string, recipients []string, data []byte) error {
+	if err := client.Hello(s.config.LocalName); err != nil {
+		return err //nolint:wrapcheck // the error is wrapped by Send
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok && !s.config.ImplicitTLS {
+		if err := client.StartTLS(s.config.TLSConfig); err != nil {
+			return err //nolint:wrapcheck // the error is wrapped by Send
+		}
+	}
+	if s.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return ErrNoAuth
+		}
+		if err := client.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.host)); err != nil {
+			return err //nolint:wrapcheck // the error is wrapped by Send
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err //nolint:wrapcheck // the error is wrapped by Send
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err //nolint:wrapcheck // the error is wrapped by Send
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err //nolint:wrapcheck // the error is wrapped by Send
+	}
+	if _, err := w.Write(data); err != nil {
+		return err //nolint:wrapcheck // the error is wrapped by Send
+	}
+	if err := w.Close(); err != nil {
+		return err //nolint:wrapcheck // the error is wrapped by Send
+	}
+	return client.Quit() //nolint:wrapcheck // the error is wrapped by Send
+}
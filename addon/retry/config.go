@@ -2,6 +2,8 @@ package retry
 
 import (
 	"time"
+
+	"github.com/gofiber/fiber/v3"
 )
 
 // Config defines the config for addon.
@@ -28,6 +30,17 @@ type Config struct {
 	// Optional. Default: 10
 	MaxRetryCount int
 
+	// Clock is the source of the time of the waits between the retries,
+	// e.g. a fiber.ManualClock in tests.
+	//
+	// Optional. Default: nil, the system time
+	Clock fiber.Clock
+
+	// Rand is the source of the jitter of the waits, e.g. fiber.NewRand in tests.
+	//
+	// Optional. Default: nil, a secure random number
+	Rand fiber.Rand
+
 	// currentInterval tracks the current waiting time.
 	//
 	// Optional. Default: 1 * time.Second
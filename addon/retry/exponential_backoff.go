@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"math/big"
 	"time"
+
+	"github.com/gofiber/fiber/v3"
 )
 
 // ExponentialBackoff is a retry mechanism for retrying some calls.
@@ -21,6 +23,12 @@ type ExponentialBackoff struct {
 	// MaxRetryCount is the maximum number of retry count.
 	MaxRetryCount int
 
+	// Clock is the source of the time of the waits, nil uses the system time.
+	Clock fiber.Clock
+
+	// Rand is the source of the jitter, nil uses a secure random number.
+	Rand fiber.Rand
+
 	// currentInterval tracks the current sleep time.
 	currentInterval time.Duration
 }
@@ -33,6 +41,8 @@ func NewExponentialBackoff(config ...Config) *ExponentialBackoff {
 		MaxBackoffTime:  cfg.MaxBackoffTime,
 		Multiplier:      cfg.Multiplier,
 		MaxRetryCount:   cfg.MaxRetryCount,
+		Clock:           cfg.Clock,
+		Rand:            cfg.Rand,
 		currentInterval: cfg.currentInterval,
 	}
 }
@@ -50,20 +60,40 @@ func (e *ExponentialBackoff) Retry(f func() error) error {
 		if err == nil {
 			return nil
 		}
-		next := e.next()
-		time.Sleep(next)
+		e.sleep(e.next())
 	}
 	return err
 }
 
+// sleep waits for the duration on the clock.
+func (e *ExponentialBackoff) sleep(d time.Duration) {
+	if e.Clock == nil {
+		time.Sleep(d)
+		return
+	}
+	timer := e.Clock.NewTimer(d)
+	<-timer.C()
+}
+
+// jitter returns a random value between [0, 1000).
+func (e *ExponentialBackoff) jitter() (int64, error) {
+	if e.Rand != nil {
+		return e.Rand.Int63n(1000), nil
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(1000))
+	if err != nil {
+		return 0, err //nolint:wrapcheck // The error is handled by next
+	}
+	return n.Int64(), nil
+}
+
 // next calculates the next sleeping time interval.
 func (e *ExponentialBackoff) next() time.Duration {
-	// generate a random value between [0, 1000)
-	n, err := rand.Int(rand.Reader, big.NewInt(1000))
+	n, err := e.jitter()
 	if err != nil {
 		return e.MaxBackoffTime
 	}
-	t := e.currentInterval + (time.Duration(n.Int64()) * time.Millisecond)
+	t := e.currentInterval + (time.Duration(n) * time.Millisecond)
 	e.currentInterval = time.Duration(float64(e.currentInterval) * e.Multiplier)
 	if t >= e.MaxBackoffTime {
 		e.currentInterval = e.MaxBackoffTime
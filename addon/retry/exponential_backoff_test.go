@@ -1,10 +1,12 @@
 package retry
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/gofiber/fiber/v3"
 	"github.com/stretchr/testify/require"
 )
 
@@ -121,3 +123,39 @@ func Test_ExponentialBackoff_Next(t *testing.T) {
 		})
 	}
 }
+
+func Test_ExponentialBackoff_Clock(t *testing.T) {
+	t.Parallel()
+	clock := fiber.NewManualClock(time.Now())
+	expBackoff := NewExponentialBackoff(Config{
+		InitialInterval: time.Second,
+		MaxBackoffTime:  time.Minute,
+		MaxRetryCount:   3,
+		Clock:           clock,
+		Rand:            fiber.NewRand(1),
+	})
+
+	calls := make(chan struct{}, 3)
+	done := make(chan error, 1)
+	go func() {
+		done <- expBackoff.Retry(func() error {
+			calls <- struct{}{}
+			return errors.New("failed function")
+		})
+	}()
+
+	// the retries wait for the clock instead of sleeping
+	for i := 0; i < 3; i++ {
+		<-calls
+		require.NoError(t, clock.WaitForTimers(context.Background(), 1))
+		clock.Advance(time.Minute)
+	}
+	require.EqualError(t, <-done, "failed function")
+
+	// the jitter of the same seed is deterministic
+	a := NewExponentialBackoff(Config{Rand: fiber.NewRand(1)})
+	b := NewExponentialBackoff(Config{Rand: fiber.NewRand(1)})
+	for i := 0; i < 3; i++ {
+		require.Equal(t, a.next(), b.next())
+	}
+}
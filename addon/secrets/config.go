@@ -0,0 +1,101 @@
+package secrets
+
+import (
+	"net/http"
+	"os"
+	"time"
+)
+
+// Config defines the config for addon.
+type Config struct {
+	// Interval is the interval in which Watch checks the secrets for changes.
+	//
+	// Optional. Default: 10 * time.Second
+	Interval time.Duration
+}
+
+// DefaultConfig is the default config for the env and file providers.
+var DefaultConfig = Config{
+	Interval: 10 * time.Second,
+}
+
+// configDefault sets the config values if they are not set.
+func configDefault(config ...Config) Config {
+	if len(config) == 0 {
+		return DefaultConfig
+	}
+	cfg := config[0]
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultConfig.Interval
+	}
+	return cfg
+}
+
+// VaultConfig defines the config of the Vault provider.
+type VaultConfig struct {
+	// Address is the address of the Vault server, e.g. "https://vault.example.com:8200".
+	//
+	// Required.
+	Address string
+
+	// Token authenticates the requests.
+	//
+	// Optional. Default: the VAULT_TOKEN environment variable
+	Token string
+
+	// Namespace is the namespace of the secret, for Vault Enterprise.
+	//
+	// Optional. Default: ""
+	Namespace string
+
+	// Mount is the mount path of the KV version 2 secrets engine.
+	//
+	// Optional. Default: "secret"
+	Mount string
+
+	// Path is the path of the secret in the secrets engine, the names
+	// passed to Get are the keys of its data.
+	//
+	// Required.
+	Path string
+
+	// Client sends the requests to the Vault server.
+	//
+	// Optional. Default: &http.Client{Timeout: 10 * time.Second}
+	Client *http.Client
+
+	// Interval is the interval in which Watch checks the secrets for changes.
+	//
+	// Optional. Default: 1 * time.Minute
+	Interval time.Duration
+}
+
+// VaultConfigDefault is the default config of the Vault provider.
+var VaultConfigDefault = VaultConfig{
+	Mount:    "secret",
+	Interval: 1 * time.Minute,
+}
+
+// vaultConfigDefault sets the config values if they are not set.
+func vaultConfigDefault(config VaultConfig) VaultConfig {
+	cfg := config
+	if cfg.Address == "" {
+		panic("secrets: vault provider requires an address")
+	}
+	if cfg.Path == "" {
+		panic("secrets: vault provider requires a path")
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = VaultConfigDefault.Mount
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = VaultConfigDefault.Interval
+	}
+	return cfg
+}
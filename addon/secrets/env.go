@@ -0,0 +1,51 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Env provides the secrets of environment variables.
+type Env struct {
+	prefix string
+	config Config
+}
+
+// NewEnv creates a provider of the environment variables. The variable of a secret
+// is the prefix and the upper-cased name, other characters than letters and digits
+// are replaced by underscores, e.g. "APP_TLS_CERT" for the prefix "APP_" and the name "tls.cert".
+func NewEnv(prefix string, config ...Config) *Env {
+	return &Env{prefix: prefix, config: configDefault(config...)}
+}
+
+// Variable returns the name of the environment variable of the secret.
+func (e *Env) Variable(name string) string {
+	return e.prefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// Get returns the value of the environment variable of the secret.
+func (e *Env) Get(_ context.Context, name string) ([]byte, error) {
+	value, ok := os.LookupEnv(e.Variable(name))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", fiber.ErrSecretNotFound, e.Variable(name))
+	}
+	return []byte(value), nil
+}
+
+// Watch checks the environment variable of the secret for changes in the interval of the config.
+func (e *Env) Watch(ctx context.Context, name string, fn func(value []byte)) error {
+	return poll(ctx, e.config.Interval, name, e.Get, fn)
+}
@@ -0,0 +1,54 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ErrInvalidName is returned by the file provider if the name of a secret isn't a local path.
+var ErrInvalidName = errors.New("secrets: invalid secret name")
+
+// File provides the secrets of the files in a directory, like the secrets
+// mounted by Docker and Kubernetes.
+type File struct {
+	dir    string
+	config Config
+}
+
+// NewFile creates a provider of the files in the directory, the name of a secret
+// is the path of its file relative to the directory, e.g. "tls/cert.pem".
+func NewFile(dir string, config ...Config) *File {
+	return &File{dir: dir, config: configDefault(config...)}
+}
+
+// Get returns the content of the file of the secret, a trailing newline is removed.
+func (f *File) Get(_ context.Context, name string) ([]byte, error) {
+	if !filepath.IsLocal(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	value, err := os.ReadFile(filepath.Join(f.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", fiber.ErrSecretNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("secrets: failed to read %s: %w", name, err)
+	}
+
+	if bytes.HasSuffix(value, []byte("\n")) {
+		value = bytes.TrimSuffix(value[:len(value)-1], []byte("\r"))
+	}
+	return value, nil
+}
+
+// Watch checks the file of the secret for changes in the interval of the config.
+// Files which are replaced atomically, e.g. by Kubernetes, are detected too.
+func (f *File) Watch(ctx context.Context, name string, fn func(value []byte)) error {
+	return poll(ctx, f.config.Interval, name, f.Get, fn)
+}
@@ -0,0 +1,35 @@
+// Package secrets contains the adapters of fiber.SecretsProvider for
+// environment variables, files and Vault.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"time"
+)
+
+// getFunc is the Get method of a provider.
+type getFunc func(ctx context.Context, name string) ([]byte, error)
+
+// poll calls get in the interval and calls fn when the value changed, until the
+// context is done. Errors of get are ignored, the secret is checked again in the
+// next interval. A secret which is removed isn't reported.
+func poll(ctx context.Context, interval time.Duration, name string, get getFunc, fn func(value []byte)) error {
+	last, _ := get(ctx, name) //nolint:errcheck // A missing secret is reported when it is created
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			value, err := get(ctx, name)
+			if err != nil || bytes.Equal(value, last) {
+				continue
+			}
+			last = value
+			fn(value)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// watch watches the secret until the test ends and returns the reported values.
+func watch(t *testing.T, provider fiber.SecretsProvider, name string) func() []string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	t.Cleanup(func() {
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+	})
+
+	var mutex sync.Mutex
+	var values []string
+	go func() {
+		done <- provider.Watch(ctx, name, func(value []byte) {
+			mutex.Lock()
+			values = append(values, string(value))
+			mutex.Unlock()
+		})
+	}()
+	return func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string(nil), values...)
+	}
+}
+
+func Test_Env(t *testing.T) {
+	t.Setenv("TEST_SECRETS_COOKIE_KEY", "key")
+
+	env := NewEnv("TEST_SECRETS_", Config{Interval: time.Millisecond})
+	require.Equal(t, "TEST_SECRETS_COOKIE_KEY", env.Variable("cookie.key"))
+	require.Equal(t, "TEST_SECRETS_COOKIE_KEY", env.Variable("Cookie-Key"))
+
+	value, err := env.Get(context.Background(), "cookie.key")
+	require.NoError(t, err)
+	require.Equal(t, "key", string(value))
+
+	_, err = env.Get(context.Background(), "missing")
+	require.ErrorIs(t, err, fiber.ErrSecretNotFound)
+
+	values := watch(t, env, "cookie.key")
+	time.Sleep(10 * time.Millisecond)
+	t.Setenv("TEST_SECRETS_COOKIE_KEY", "rotated")
+	require.Eventually(t, func() bool {
+		return len(values()) == 1
+	}, time.Second, time.Millisecond)
+	require.Equal(t, []string{"rotated"}, values())
+}
+
+func Test_File(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "tls"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls", "cert.pem"), []byte("cert\r\n"), 0o600))
+
+	file := NewFile(dir, Config{Interval: time.Millisecond})
+	value, err := file.Get(context.Background(), "tls/cert.pem")
+	require.NoError(t, err)
+	require.Equal(t, "cert", string(value))
+
+	_, err = file.Get(context.Background(), "missing")
+	require.ErrorIs(t, err, fiber.ErrSecretNotFound)
+	_, err = file.Get(context.Background(), "../secret")
+	require.ErrorIs(t, err, ErrInvalidName)
+	_, err = file.Get(context.Background(), "/etc/passwd")
+	require.ErrorIs(t, err, ErrInvalidName)
+
+	// a secret which is created later is reported
+	values := watch(t, file, "key")
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key"), []byte("v1\n"), 0o600))
+	require.Eventually(t, func() bool {
+		return len(values()) == 1
+	}, time.Second, time.Millisecond)
+
+	// a file which is replaced is reported
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key.tmp"), []byte("v2"), 0o600))
+	require.NoError(t, os.Rename(filepath.Join(dir, "key.tmp"), filepath.Join(dir, "key")))
+	require.Eventually(t, func() bool {
+		return len(values()) == 2
+	}, time.Second, time.Millisecond)
+	require.Equal(t, []string{"v1", "v2"}, values())
+}
+
+func Test_Vault(t *testing.T) {
+	t.Parallel()
+	var mutex sync.Mutex
+	body := `{"data":{"data":{"cookie_key":"v1","config":{"a":1}},"metadata":{"version":1}}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" || r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/app/prod" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		_, _ = w.Write([]byte(body)) //nolint:errcheck // It is fine to ignore the error here
+	}))
+	t.Cleanup(server.Close)
+
+	vault := NewVault(VaultConfig{
+		Address:   server.URL + "/",
+		Token:     "token",
+		Namespace: "team",
+		Mount:     "kv",
+		Path:      "/app/prod",
+		Interval:  time.Millisecond,
+	})
+	value, err := vault.Get(context.Background(), "cookie_key")
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(value))
+	value, err = vault.Get(context.Background(), "config")
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1}`, string(value))
+	_, err = vault.Get(context.Background(), "missing")
+	require.ErrorIs(t, err, fiber.ErrSecretNotFound)
+
+	_, err = NewVault(VaultConfig{Address: server.URL, Token: "token", Namespace: "team", Path: "app/prod"}).Get(context.Background(), "cookie_key")
+	require.ErrorIs(t, err, fiber.ErrSecretNotFound)
+	_, err = NewVault(VaultConfig{Address: server.URL, Token: "invalid", Path: "app/prod"}).Get(context.Background(), "cookie_key")
+	require.ErrorIs(t, err, ErrVaultRequest)
+
+	values := watch(t, vault, "cookie_key")
+	time.Sleep(10 * time.Millisecond)
+	mutex.Lock()
+	body = `{"data":{"data":{"cookie_key":"v2"}}}`
+	mutex.Unlock()
+	require.Eventually(t, func() bool {
+		return len(values()) == 1
+	}, time.Second, time.Millisecond)
+	require.Equal(t, []string{"v2"}, values())
+
+	require.Panics(t, func() {
+		NewVault(VaultConfig{Path: "app"})
+	})
+	require.Panics(t, func() {
+		NewVault(VaultConfig{Address: server.URL})
+	})
+}
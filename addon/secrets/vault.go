@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ErrVaultRequest is returned by the Vault provider if the Vault server responded with an error.
+var ErrVaultRequest = errors.New("secrets: vault request failed")
+
+// Vault provides the secrets of a secret of the KV version 2 secrets engine of
+// HashiCorp Vault, or of a server with a compatible API.
+type Vault struct {
+	config VaultConfig
+	path   string
+}
+
+// vaultResponse is the response of the read secret endpoint of the KV version 2 secrets engine.
+type vaultResponse struct {
+	Data struct {
+		Data map[string]json.RawMessage `json:"data"`
+	} `json:"data"`
+}
+
+// NewVault creates a provider of a Vault secret, the names passed to Get are the keys of its data.
+func NewVault(config VaultConfig) *Vault {
+	cfg := vaultConfigDefault(config)
+	return &Vault{
+		config: cfg,
+		path:   "/v1/" + strings.Trim(cfg.Mount, "/") + "/data/" + strings.Trim(cfg.Path, "/"),
+	}
+}
+
+// Get reads the secret from Vault and returns the value of the key.
+// String values are returned as they are, other values in JSON.
+func (v *Vault) Get(ctx context.Context, name string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(v.config.Address, "/")+v.path, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.config.Token)
+	if v.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.config.Namespace)
+	}
+
+	resp, err := v.config.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: failed to send vault request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", fiber.ErrSecretNotFound, v.path)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: %s responded with %d", ErrVaultRequest, v.path, resp.StatusCode)
+	}
+
+	var body vaultResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("secrets: failed to decode vault response: %w", err)
+	}
+	raw, ok := body.Data.Data[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s#%s", fiber.ErrSecretNotFound, v.path, name)
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return []byte(value), nil
+	}
+	return raw, nil
+}
+
+// Watch reads the secret from Vault in the interval of the config and calls fn
+// when the value of the key changed.
+func (v *Vault) Watch(ctx context.Context, name string, fn func(value []byte)) error {
+	return poll(ctx, v.config.Interval, name, v.Get, fn)
+}
@@ -0,0 +1,69 @@
+package spiffe
+
+import (
+	"os"
+	"time"
+)
+
+// EndpointSocketEnv is the environment variable of the address of the Workload API.
+const EndpointSocketEnv = "SPIFFE_ENDPOINT_SOCKET"
+
+// Config defines the config for the source.
+type Config struct {
+	// Address is the address of the Workload API, e.g. "unix:///run/spire/sockets/agent.sock"
+	// or "tcp://127.0.0.1:8081".
+	//
+	// Optional. Default: the SPIFFE_ENDPOINT_SOCKET environment variable, or "unix:///tmp/spire-agent/public/api.sock"
+	Address string
+
+	// Hint selects the SVID with the hint if the Workload API returns multiple SVIDs.
+	//
+	// Optional. Default: "", the first SVID
+	Hint string
+
+	// OnUpdate is called with the SVID when it was received, e.g. after a rotation.
+	//
+	// Optional. Default: nil
+	OnUpdate func(svid *SVID)
+
+	// RetryInterval is the initial interval of the reconnects when the stream of the Workload API
+	// failed, it is doubled after each failed attempt up to MaxRetryInterval.
+	//
+	// Optional. Default: time.Second
+	RetryInterval time.Duration
+
+	// MaxRetryInterval is the maximum interval of the reconnects.
+	//
+	// Optional. Default: 30 * time.Second
+	MaxRetryInterval time.Duration
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Address:          "unix:///tmp/spire-agent/public/api.sock",
+	RetryInterval:    time.Second,
+	MaxRetryInterval: 30 * time.Second,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	cfg := Config{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Set default values
+	if cfg.Address == "" {
+		cfg.Address = os.Getenv(EndpointSocketEnv)
+	}
+	if cfg.Address == "" {
+		cfg.Address = ConfigDefault.Address
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = ConfigDefault.RetryInterval
+	}
+	if cfg.MaxRetryInterval < cfg.RetryInterval {
+		cfg.MaxRetryInterval = max(ConfigDefault.MaxRetryInterval, cfg.RetryInterval)
+	}
+	return cfg
+}
@@ -0,0 +1,245 @@
+package spiffe
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// ErrNoSVID is returned if the source hasn't received an SVID, or the Workload API has no SVID with the hint.
+var ErrNoSVID = errors.New("spiffe: no X.509 SVID")
+
+// SVID is an X.509 SVID, the identity of the workload.
+type SVID struct {
+	// ID is the SPIFFE ID, e.g. "spiffe://example.org/ns/prod/sa/orders".
+	ID string
+	// Hint is the hint of the Workload API, which distinguishes the SVIDs of a workload.
+	Hint string
+	// Certificates are the certificate and its intermediates.
+	Certificates []*x509.Certificate
+	// PrivateKey is the private key of the certificate.
+	PrivateKey crypto.Signer
+}
+
+// TrustDomain returns the trust domain of the SPIFFE ID.
+func (svid *SVID) TrustDomain() string {
+	u, err := url.Parse(svid.ID)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// sourceState is the state of a response of the Workload API.
+type sourceState struct {
+	svid        *SVID
+	cert        *tls.Certificate
+	trustDomain string
+	bundles     map[string]*x509.CertPool // including the bundle of the trust domain of the SVID
+}
+
+// Source fetches the X.509 SVID and the bundles of the workload from the SPIFFE Workload API,
+// e.g. of a SPIRE agent, and keeps them up to date with the stream of the Workload API, so
+// the certificates are rotated without a restart. It is a fiber.CertificateSource for the
+// TLS of the server, ClientTLSConfig returns the TLS config of mTLS clients.
+type Source struct {
+	transport *http.Transport
+	cancel    context.CancelFunc
+	done      chan struct{}
+	state     atomic.Pointer[sourceState]
+	updated   chan struct{} // closed when the first SVID was received
+
+	mutex   sync.Mutex
+	lastErr error
+	config  Config
+}
+
+// NewSource connects to the Workload API and waits until the SVID was received or the
+// context is done. The stream of the Workload API is reconnected until Close is called.
+func NewSource(ctx context.Context, config ...Config) (*Source, error) {
+	cfg := configDefault(config...)
+	transport, err := newTransport(cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	s := &Source{
+		config:    cfg,
+		transport: transport,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		updated:   make(chan struct{}),
+	}
+	go s.run(watchCtx)
+
+	select {
+	case <-s.updated:
+		return s, nil
+	case <-ctx.Done():
+		s.Close()
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return nil, fmt.Errorf("spiffe: no X.509 SVID was received from %q: %w", cfg.Address, errors.Join(ctx.Err(), s.lastErr))
+	}
+}
+
+// run watches the stream of the Workload API and reconnects it with a backoff until the context is done.
+func (s *Source) run(ctx context.Context) {
+	defer close(s.done)
+	client := &http.Client{Transport: s.transport}
+
+	interval := s.config.RetryInterval
+	for {
+		received := false
+		err := fetchX509SVIDs(ctx, client, func(resp *x509SVIDResponse) {
+			if err := s.update(resp); err != nil {
+				s.setErr(err)
+				log.Warnw("spiffe: invalid X.509 SVID, the previous SVID is used", "error", err)
+				return
+			}
+			received = true
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		s.setErr(err)
+		log.Warnw("spiffe: the workload api stream failed", "address", s.config.Address, "error", err)
+
+		if received {
+			interval = s.config.RetryInterval
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		interval = min(interval*2, s.config.MaxRetryInterval)
+	}
+}
+
+// setErr stores the latest error, it is returned by NewSource if no SVID was received.
+func (s *Source) setErr(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastErr = err
+}
+
+// update replaces the SVID and the bundles with the response.
+func (s *Source) update(resp *x509SVIDResponse) error {
+	var msg *x509SVIDMessage
+	for i := range resp.svids {
+		if s.config.Hint == "" || resp.svids[i].hint == s.config.Hint {
+			msg = &resp.svids[i]
+			break
+		}
+	}
+	if msg == nil {
+		return fmt.Errorf("%w: hint %q", ErrNoSVID, s.config.Hint)
+	}
+
+	certs, err := x509.ParseCertificates(msg.certs)
+	if err != nil || len(certs) == 0 {
+		return fmt.Errorf("%w: invalid certificates of %q: %w", ErrInvalidResponse, msg.id, err)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(msg.key)
+	if err != nil {
+		return fmt.Errorf("%w: invalid private key of %q: %w", ErrInvalidResponse, msg.id, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("%w: the private key of %q isn't a signer", ErrInvalidResponse, msg.id)
+	}
+
+	svid := &SVID{ID: msg.id, Hint: msg.hint, Certificates: certs, PrivateKey: signer}
+	state := &sourceState{
+		svid:        svid,
+		trustDomain: svid.TrustDomain(),
+		bundles:     make(map[string]*x509.CertPool, len(resp.federatedBundles)+1),
+		cert:        &tls.Certificate{PrivateKey: signer, Leaf: certs[0]},
+	}
+	for _, cert := range certs {
+		state.cert.Certificate = append(state.cert.Certificate, cert.Raw)
+	}
+
+	bundles := map[string][]byte{state.trustDomain: msg.bundle}
+	for trustDomain, bundle := range resp.federatedBundles {
+		bundles[strings.ToLower(strings.TrimPrefix(trustDomain, "spiffe://"))] = bundle
+	}
+	for trustDomain, bundle := range bundles {
+		roots, err := x509.ParseCertificates(bundle)
+		if err != nil {
+			return fmt.Errorf("%w: invalid bundle of %q: %w", ErrInvalidResponse, trustDomain, err)
+		}
+		pool := x509.NewCertPool()
+		for _, root := range roots {
+			pool.AddCert(root)
+		}
+		state.bundles[trustDomain] = pool
+	}
+
+	first := s.state.Swap(state) == nil
+	if s.config.OnUpdate != nil {
+		s.config.OnUpdate(svid)
+	}
+	if first {
+		close(s.updated)
+	}
+	return nil
+}
+
+// SVID returns the current SVID.
+func (s *Source) SVID() *SVID {
+	if state := s.state.Load(); state != nil {
+		return state.svid
+	}
+	return nil
+}
+
+// Certificate returns the TLS certificate of the current SVID.
+func (s *Source) Certificate() (*tls.Certificate, error) {
+	state := s.state.Load()
+	if state == nil {
+		return nil, ErrNoSVID
+	}
+	return state.cert, nil
+}
+
+// ClientCAs returns the bundle of the trust domain of the SVID, the roots of the client
+// certificates of the workloads of the trust domain.
+func (s *Source) ClientCAs() (*x509.CertPool, error) {
+	state := s.state.Load()
+	if state == nil {
+		return nil, ErrNoSVID
+	}
+	return state.bundles[state.trustDomain], nil
+}
+
+// Bundle returns the bundle of the trust domain, including the federated trust domains, or nil.
+func (s *Source) Bundle(trustDomain string) *x509.CertPool {
+	state := s.state.Load()
+	if state == nil {
+		return nil
+	}
+	return state.bundles[strings.ToLower(trustDomain)]
+}
+
+// Close stops watching the Workload API, the current SVID is kept.
+func (s *Source) Close() {
+	s.cancel()
+	<-s.done
+	s.transport.CloseIdleConnections()
+}
@@ -0,0 +1,254 @@
+//go:build go1.24
+
+package spiffe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/client"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA issues the SVIDs of a trust domain.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, trustDomain string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{trustDomain}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		URIs:                  []*url.URL{{Scheme: "spiffe", Host: trustDomain}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// svid returns the X509SVID message of the SPIFFE ID, like the SVIDs of SPIRE without a host name.
+func (ca *testCA) svid(t *testing.T, id, hint string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	u, err := url.Parse(id)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: serial,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{u},
+	}, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	var msg []byte
+	msg = appendField(msg, 1, []byte(id))
+	msg = appendField(msg, 2, der)
+	msg = appendField(msg, 3, pkcs8)
+	msg = appendField(msg, 4, ca.cert.Raw)
+	return appendField(msg, 5, []byte(hint))
+}
+
+// appendField appends a length-delimited protobuf field.
+func appendField(msg []byte, num uint64, value []byte) []byte {
+	msg = binary.AppendUvarint(msg, num<<3|2)
+	msg = binary.AppendUvarint(msg, uint64(len(value)))
+	return append(msg, value...)
+}
+
+// testResponse returns the X509SVIDResponse of the SVIDs.
+func testResponse(svids ...[]byte) []byte {
+	var msg []byte
+	for _, svid := range svids {
+		msg = appendField(msg, 1, svid)
+	}
+	return msg
+}
+
+// startWorkloadAPI starts a fake Workload API which streams the responses of the channel.
+func startWorkloadAPI(t *testing.T, handler http.HandlerFunc) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	ln, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	srv := &http.Server{Handler: handler, Protocols: new(http.Protocols), ReadHeaderTimeout: time.Second}
+	srv.Protocols.SetUnencryptedHTTP2(true)
+	go srv.Serve(ln) //nolint:errcheck // It is closed by the cleanup
+	t.Cleanup(func() {
+		require.NoError(t, srv.Close())
+	})
+	return "unix://" + socket
+}
+
+// streamResponses returns a handler of the Workload API which streams the responses.
+func streamResponses(t *testing.T, responses <-chan []byte) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != fetchX509SVIDPath || r.Header.Get(workloadHeader) != "true" {
+			w.Header().Set("Grpc-Status", "3")
+			return
+		}
+		w.Header().Set("Content-Type", "application/grpc")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush() //nolint:forcetypeassert // It is a HTTP/2 response writer
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case msg := <-responses:
+				prefix := make([]byte, 5)
+				binary.BigEndian.PutUint32(prefix[1:], uint32(len(msg))) //nolint:gosec // The test messages are small
+				_, _ = w.Write(append(prefix, msg...))                   //nolint:errcheck // The test checks the received SVIDs
+				w.(http.Flusher).Flush()                                 //nolint:forcetypeassert // It is a HTTP/2 response writer
+			}
+		}
+	}
+}
+
+// go test -run Test_Source
+func Test_Source(t *testing.T) {
+	t.Parallel()
+	ca := newTestCA(t, "example.org")
+	responses := make(chan []byte, 2)
+	address := startWorkloadAPI(t, streamResponses(t, responses))
+
+	responses <- testResponse(
+		ca.svid(t, "spiffe://example.org/billing", "billing"),
+		ca.svid(t, "spiffe://example.org/orders", "orders"),
+	)
+	updates := make(chan *SVID, 2)
+	source, err := NewSource(context.Background(), Config{
+		Address:  address,
+		Hint:     "orders",
+		OnUpdate: func(svid *SVID) { updates <- svid },
+	})
+	require.NoError(t, err)
+	defer source.Close()
+
+	svid := <-updates
+	require.Equal(t, "spiffe://example.org/orders", svid.ID)
+	require.Equal(t, "example.org", svid.TrustDomain())
+	require.Equal(t, svid, source.SVID())
+	cert, err := source.Certificate()
+	require.NoError(t, err)
+	require.Equal(t, svid.Certificates[0], cert.Leaf)
+	roots, err := source.ClientCAs()
+	require.NoError(t, err)
+	require.NotNil(t, roots)
+	require.Same(t, roots, source.Bundle("Example.org"))
+	require.Nil(t, source.Bundle("other.org"))
+
+	// the rotated SVID is used for the next handshakes
+	responses <- testResponse(ca.svid(t, "spiffe://example.org/orders", "orders"))
+	rotated := <-updates
+	require.NotEqual(t, svid.Certificates[0].SerialNumber, rotated.Certificates[0].SerialNumber)
+	cert, err = source.Certificate()
+	require.NoError(t, err)
+	require.Equal(t, rotated.Certificates[0], cert.Leaf)
+
+	// responses without the hint keep the previous SVID
+	responses <- testResponse(ca.svid(t, "spiffe://example.org/billing", "billing"))
+	responses <- testResponse(ca.svid(t, "spiffe://example.org/orders", "orders"))
+	require.Equal(t, "spiffe://example.org/orders", (<-updates).ID)
+}
+
+// go test -run Test_Source_Error
+func Test_Source_Error(t *testing.T) {
+	t.Parallel()
+	address := startWorkloadAPI(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Grpc-Status", "7")
+		w.Header().Set("Grpc-Message", "no%20identity%20issued")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err := NewSource(ctx, Config{Address: address, RetryInterval: 10 * time.Millisecond})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, ErrWorkloadAPI)
+	require.ErrorContains(t, err, "status 7: no identity issued")
+
+	_, err = NewSource(ctx, Config{Address: "https://127.0.0.1:8081"})
+	require.ErrorIs(t, err, ErrInvalidAddress)
+}
+
+// testSource returns a source of the SVID of the CA.
+func testSource(t *testing.T, ca *testCA, id string) *Source {
+	t.Helper()
+	responses := make(chan []byte, 1)
+	responses <- testResponse(ca.svid(t, id, ""))
+	source, err := NewSource(context.Background(), Config{Address: startWorkloadAPI(t, streamResponses(t, responses))})
+	require.NoError(t, err)
+	t.Cleanup(source.Close)
+	return source
+}
+
+// go test -run Test_Source_MTLS
+func Test_Source_MTLS(t *testing.T) {
+	t.Parallel()
+	ca := newTestCA(t, "example.org")
+	server := testSource(t, ca, "spiffe://example.org/orders")
+	clientSource := testSource(t, ca, "spiffe://example.org/billing")
+
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(c.ClientCertificate().SPIFFEID)
+	})
+	addr := make(chan net.Addr, 1)
+	go func() {
+		_ = app.Listen("127.0.0.1:0", fiber.ListenConfig{ //nolint:errcheck // It is shut down by the test
+			CertificateSource:     server,
+			DisableStartupMessage: true,
+			ListenerAddrFunc:      func(a net.Addr) { addr <- a },
+		})
+	}()
+	url := "https://" + (<-addr).String()
+	defer app.Shutdown() //nolint:errcheck // It is fine to ignore the error here
+
+	cc := client.New().SetTLSConfig(clientSource.ClientTLSConfig(AuthorizeID("spiffe://example.org/orders")))
+	resp, err := cc.Get(url)
+	require.NoError(t, err)
+	require.Equal(t, "spiffe://example.org/billing", resp.String())
+
+	// the SPIFFE ID of the server isn't authorized
+	cc = client.New().SetTLSConfig(clientSource.ClientTLSConfig(AuthorizeID("spiffe://example.org/payments")))
+	_, err = cc.Get(url)
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	// the certificates of another CA of the trust domain aren't verified
+	other := testSource(t, newTestCA(t, "example.org"), "spiffe://example.org/billing")
+	cc = client.New().SetTLSConfig(other.ClientTLSConfig(AuthorizeMemberOf("example.org")))
+	_, err = cc.Get(url)
+	require.ErrorContains(t, err, "failed to verify the certificate")
+}
@@ -0,0 +1,111 @@
+package spiffe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ErrUnauthorized is returned by the authorizers if the SPIFFE ID of the peer isn't allowed.
+var ErrUnauthorized = errors.New("spiffe: unauthorized SPIFFE ID")
+
+// Authorizer authorizes the SPIFFE ID of the peer, it returns an error if the peer isn't allowed.
+type Authorizer func(id string) error
+
+// AuthorizeAny allows all SPIFFE IDs whose certificates are verified by the bundles.
+func AuthorizeAny() Authorizer {
+	return func(string) error {
+		return nil
+	}
+}
+
+// AuthorizeID allows the SPIFFE IDs.
+func AuthorizeID(ids ...string) Authorizer {
+	return func(id string) error {
+		if slices.Contains(ids, id) {
+			return nil
+		}
+		return fmt.Errorf("%w: %q", ErrUnauthorized, id)
+	}
+}
+
+// AuthorizeMemberOf allows the SPIFFE IDs of the trust domains.
+func AuthorizeMemberOf(trustDomains ...string) Authorizer {
+	return func(id string) error {
+		cert := &fiber.ClientCertificate{SPIFFEID: id}
+		for _, trustDomain := range trustDomains {
+			if strings.EqualFold(cert.TrustDomain(), trustDomain) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %q isn't a member of %v", ErrUnauthorized, id, trustDomains)
+	}
+}
+
+// ClientTLSConfig returns the TLS config of mTLS clients, e.g. of the Fiber client, which
+// present the current SVID. The certificate of the server is verified with the bundle of
+// the trust domain of its SPIFFE ID instead of the host name, and authorized by the
+// authorizer. Without an authorizer, the SPIFFE IDs of the trust domain of the SVID are allowed.
+//
+//	cc := client.New().SetTLSConfig(source.ClientTLSConfig(spiffe.AuthorizeID("spiffe://example.org/billing")))
+func (s *Source) ClientTLSConfig(authorize Authorizer) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return s.Certificate()
+		},
+		// the certificate is verified by VerifyPeerCertificate
+		InsecureSkipVerify: true, //nolint:gosec // SVIDs don't contain the host names
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return s.verify(rawCerts, authorize)
+		},
+	}
+}
+
+// verify verifies the certificates of the peer with the bundle of the trust domain of
+// its SPIFFE ID and authorizes the SPIFFE ID.
+func (s *Source) verify(rawCerts [][]byte, authorize Authorizer) error {
+	state := s.state.Load()
+	if state == nil {
+		return ErrNoSVID
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("spiffe: invalid peer certificate: %w", err)
+		}
+		certs[i] = cert
+	}
+	peer := fiber.NewClientCertificate(certs)
+	if peer == nil || peer.SPIFFEID == "" {
+		return fmt.Errorf("%w: the peer certificate has no SPIFFE ID", ErrUnauthorized)
+	}
+
+	roots := state.bundles[peer.TrustDomain()]
+	if roots == nil {
+		return fmt.Errorf("%w: no bundle of the trust domain of %q", ErrUnauthorized, peer.SPIFFEID)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("spiffe: failed to verify the certificate of %q: %w", peer.SPIFFEID, err)
+	}
+
+	if authorize == nil {
+		authorize = AuthorizeMemberOf(state.trustDomain)
+	}
+	return authorize(peer.SPIFFEID)
+}
@@ -0,0 +1,27 @@
+//go:build go1.24
+
+package spiffe
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// newTransport creates the transport of the Workload API, gRPC over HTTP/2 without TLS.
+func newTransport(address string) (*http.Transport, error) {
+	network, addr, err := parseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		},
+		Protocols: new(http.Protocols),
+	}
+	transport.Protocols.SetUnencryptedHTTP2(true)
+	return transport, nil
+}
@@ -0,0 +1,12 @@
+//go:build !go1.24
+
+package spiffe
+
+import (
+	"net/http"
+)
+
+// newTransport returns ErrNotSupported, HTTP/2 without TLS is only supported by net/http since Go 1.24.
+func newTransport(string) (*http.Transport, error) {
+	return nil, ErrNotSupported
+}
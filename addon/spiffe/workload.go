@@ -0,0 +1,255 @@
+package spiffe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+var (
+	// ErrNotSupported is returned by NewSource if the app is built with Go older than 1.24.
+	ErrNotSupported = errors.New("spiffe: the Workload API requires Go 1.24 or newer")
+	// ErrInvalidAddress is returned by NewSource if the address of the Workload API isn't a unix or tcp URL.
+	ErrInvalidAddress = errors.New("spiffe: invalid Workload API address")
+	// ErrWorkloadAPI is returned if the Workload API responded with an error or ended the stream.
+	ErrWorkloadAPI = errors.New("spiffe: workload api request failed")
+	// ErrInvalidResponse is returned if the response of the Workload API can't be decoded.
+	ErrInvalidResponse = errors.New("spiffe: invalid workload api response")
+)
+
+const (
+	// fetchX509SVIDPath is the gRPC method which streams the X.509 SVIDs and bundles.
+	fetchX509SVIDPath = "/SpiffeWorkloadAPI/FetchX509SVID"
+	// workloadHeader is the metadata which is required by the Workload API against SSRF.
+	workloadHeader = "Workload.Spiffe.Io"
+	// maxMessageSize limits the size of the messages of the Workload API.
+	maxMessageSize = 16 << 20
+)
+
+// x509SVIDResponse is the message of the FetchX509SVID stream.
+type x509SVIDResponse struct {
+	federatedBundles map[string][]byte
+	svids            []x509SVIDMessage
+}
+
+// x509SVIDMessage is an X.509 SVID of the response, the certificates and the bundle are
+// concatenated DER certificates, the key is in PKCS #8.
+type x509SVIDMessage struct {
+	id     string
+	hint   string
+	certs  []byte
+	key    []byte
+	bundle []byte
+}
+
+// parseAddress returns the network and the address of the URL of the Workload API.
+func parseAddress(address string) (network, addr string, err error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %q: %w", ErrInvalidAddress, address, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		path := u.Path
+		if u.Opaque != "" {
+			path = u.Opaque
+		}
+		if path == "" || u.Host != "" {
+			return "", "", fmt.Errorf("%w: %q has no socket path", ErrInvalidAddress, address)
+		}
+		return "unix", path, nil
+	case "tcp":
+		if net.ParseIP(u.Hostname()) == nil || u.Port() == "" {
+			return "", "", fmt.Errorf("%w: %q has no IP and port", ErrInvalidAddress, address)
+		}
+		return "tcp", u.Host, nil
+	default:
+		return "", "", fmt.Errorf("%w: %q isn't a unix or tcp URL", ErrInvalidAddress, address)
+	}
+}
+
+// fetchX509SVIDs calls fn with the responses of the FetchX509SVID stream until the stream
+// failed or the context is done.
+func fetchX509SVIDs(ctx context.Context, client *http.Client, fn func(resp *x509SVIDResponse)) error {
+	// the request is an empty X509SVIDRequest message
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://localhost"+fetchX509SVIDPath, bytes.NewReader(make([]byte, 5)))
+	if err != nil {
+		return fmt.Errorf("spiffe: failed to create workload api request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("Te", "trailers")
+	req.Header.Set(workloadHeader, "true")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("spiffe: failed to connect to the workload api: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: responded with %d", ErrWorkloadAPI, resp.StatusCode)
+	}
+	// errors are returned as trailers-only responses
+	if err := grpcStatus(resp.Header); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(resp.Body)
+	for {
+		msg, err := readMessage(r)
+		if errors.Is(err, io.EOF) {
+			if err := grpcStatus(resp.Trailer); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: the stream ended", ErrWorkloadAPI)
+		}
+		if err != nil {
+			return err
+		}
+
+		svids, err := decodeX509SVIDResponse(msg)
+		if err != nil {
+			return err
+		}
+		fn(svids)
+	}
+}
+
+// grpcStatus returns the error of the gRPC status of the headers.
+func grpcStatus(header http.Header) error {
+	status := header.Get("Grpc-Status")
+	if status == "" || status == "0" {
+		return nil
+	}
+	message, err := url.PathUnescape(header.Get("Grpc-Message"))
+	if err != nil {
+		message = header.Get("Grpc-Message")
+	}
+	return fmt.Errorf("%w: status %s: %s", ErrWorkloadAPI, status, message)
+}
+
+// readMessage reads a length-prefixed gRPC message.
+func readMessage(r io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("spiffe: failed to read workload api response: %w", err)
+	}
+	if prefix[0] != 0 {
+		return nil, fmt.Errorf("%w: compressed message", ErrInvalidResponse)
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if size > maxMessageSize {
+		return nil, fmt.Errorf("%w: message of %d bytes", ErrInvalidResponse, size)
+	}
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, fmt.Errorf("spiffe: failed to read workload api response: %w", err)
+	}
+	return msg, nil
+}
+
+// decodeX509SVIDResponse decodes the X509SVIDResponse protobuf message.
+func decodeX509SVIDResponse(msg []byte) (*x509SVIDResponse, error) {
+	resp := &x509SVIDResponse{federatedBundles: make(map[string][]byte)}
+	err := decodeFields(msg, func(num int, value []byte) error {
+		switch num {
+		case 1: // repeated X509SVID svids
+			var svid x509SVIDMessage
+			err := decodeFields(value, func(num int, value []byte) error {
+				switch num {
+				case 1:
+					svid.id = string(value)
+				case 2:
+					svid.certs = value
+				case 3:
+					svid.key = value
+				case 4:
+					svid.bundle = value
+				case 5:
+					svid.hint = string(value)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			resp.svids = append(resp.svids, svid)
+		case 3: // map<string, bytes> federated_bundles
+			var trustDomain string
+			var bundle []byte
+			err := decodeFields(value, func(num int, value []byte) error {
+				switch num {
+				case 1:
+					trustDomain = string(value)
+				case 2:
+					bundle = value
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			resp.federatedBundles[trustDomain] = bundle
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// decodeFields calls fn with the length-delimited fields of a protobuf message,
+// the fields of the other wire types are skipped.
+func decodeFields(msg []byte, fn func(num int, value []byte) error) error {
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return fmt.Errorf("%w: invalid field key", ErrInvalidResponse)
+		}
+		msg = msg[n:]
+
+		num := int(key >> 3) //nolint:gosec // The field numbers are small
+		switch key & 7 {
+		case 0: // varint
+			if _, n = binary.Uvarint(msg); n <= 0 {
+				return fmt.Errorf("%w: invalid varint of field %d", ErrInvalidResponse, num)
+			}
+			msg = msg[n:]
+		case 1: // fixed64
+			if len(msg) < 8 {
+				return fmt.Errorf("%w: truncated field %d", ErrInvalidResponse, num)
+			}
+			msg = msg[8:]
+		case 2: // length-delimited
+			size, n := binary.Uvarint(msg)
+			if n <= 0 || size > uint64(len(msg)-n) {
+				return fmt.Errorf("%w: truncated field %d", ErrInvalidResponse, num)
+			}
+			if err := fn(num, msg[n:n+int(size)]); err != nil {
+				return err
+			}
+			msg = msg[n+int(size):]
+		case 5: // fixed32
+			if len(msg) < 4 {
+				return fmt.Errorf("%w: truncated field %d", ErrInvalidResponse, num)
+			}
+			msg = msg[4:]
+		default:
+			return fmt.Errorf("%w: unsupported wire type of field %d", ErrInvalidResponse, num)
+		}
+	}
+	return nil
+}
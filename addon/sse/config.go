@@ -0,0 +1,107 @@
+package sse
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for the hub.
+type Config struct {
+	// Topics returns the topic filters of the subscriber, e.g. after the topics which the
+	// user may read were checked. Returned errors are passed to the error handler of the app.
+	//
+	// Optional. Default: the comma-separated filters of the "topic" query parameter
+	Topics func(c fiber.Ctx) ([]string, error)
+
+	// PubSub sends the events to the hubs of the other instances. Without it, the events
+	// reach the subscribers of this instance only.
+	//
+	// Optional. Default: nil
+	PubSub fiber.PubSub
+
+	// Channel is the channel of the PubSub.
+	//
+	// Optional. Default: "fiber_sse"
+	Channel string
+
+	// ReplaySize is the number of the last events of all topics which are buffered to catch up
+	// the subscribers which reconnect with the Last-Event-ID header.
+	//
+	// Optional. Default: 256
+	ReplaySize int
+
+	// BufferSize is the number of events which are queued per subscriber. Subscribers which
+	// fall behind are disconnected, they catch up with the replay when they reconnect.
+	//
+	// Optional. Default: 64
+	BufferSize int
+
+	// HeartbeatInterval is the interval of the comments which keep the streams open through
+	// proxies and detect the disconnected clients.
+	//
+	// Optional. Default: 15 * time.Second
+	HeartbeatInterval time.Duration
+
+	// Retry is the reconnection time which is sent to the clients.
+	//
+	// Optional. Default: 0, the default of the client
+	Retry time.Duration
+
+	// ShutdownEvent is the final event which is sent to the subscribers when the graceful
+	// shutdown of the app begins, before the streams are ended. Without an ID, the clients
+	// keep the ID of the last event for the reconnect.
+	//
+	// Optional. Default: nil
+	ShutdownEvent *Event
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Topics:            queryTopics,
+	Channel:           "fiber_sse",
+	ReplaySize:        256,
+	BufferSize:        64,
+	HeartbeatInterval: 15 * time.Second,
+}
+
+// queryTopics returns the comma-separated filters of the "topic" query parameter.
+func queryTopics(c fiber.Ctx) ([]string, error) {
+	var topics []string
+	for _, topic := range strings.Split(c.Query("topic"), ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	return topics, nil
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Topics == nil {
+		cfg.Topics = ConfigDefault.Topics
+	}
+	if cfg.Channel == "" {
+		cfg.Channel = ConfigDefault.Channel
+	}
+	if cfg.ReplaySize <= 0 {
+		cfg.ReplaySize = ConfigDefault.ReplaySize
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = ConfigDefault.BufferSize
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = ConfigDefault.HeartbeatInterval
+	}
+	return cfg
+}
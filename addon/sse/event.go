@@ -0,0 +1,65 @@
+package sse
+
+import (
+	"bufio"
+	"strings"
+)
+
+// Event is a server-sent event of a topic.
+type Event struct {
+	// Topic is the topic of the event, its segments are separated by dots, e.g. "orders.created".
+	Topic string `json:"topic"`
+	// ID is the ID of the event, which the clients send in the Last-Event-ID header when they
+	// reconnect. It's generated if it's empty.
+	ID string `json:"id"`
+	// Type is the type of the event, which the clients listen to with addEventListener.
+	// The clients dispatch events without type as "message".
+	Type string `json:"type,omitempty"`
+	// Data is the data of the event.
+	Data string `json:"data"`
+}
+
+// fieldReplacer removes the line breaks of the single-line fields.
+var fieldReplacer = strings.NewReplacer("\r", "", "\n", "")
+
+// lineReplacer normalizes the line breaks of the data.
+var lineReplacer = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// write writes the event in the format of the event stream. The ID is omitted if it's empty,
+// the clients then keep the ID of the previous event.
+func (e *Event) write(w *bufio.Writer) {
+	if e.ID != "" {
+		_, _ = w.WriteString("id: " + fieldReplacer.Replace(e.ID) + "\n") //nolint:errcheck // the error is returned by Flush
+	}
+	if e.Type != "" {
+		_, _ = w.WriteString("event: " + fieldReplacer.Replace(e.Type) + "\n") //nolint:errcheck // the error is returned by Flush
+	}
+	for _, line := range strings.Split(lineReplacer.Replace(e.Data), "\n") {
+		_, _ = w.WriteString("data: " + line + "\n") //nolint:errcheck // the error is returned by Flush
+	}
+	_ = w.WriteByte('\n') //nolint:errcheck // the error is returned by Flush
+}
+
+// isPattern returns true if the filter contains wildcards.
+func isPattern(filter string) bool {
+	return strings.ContainsAny(filter, "*>")
+}
+
+// match returns true if the topic matches the filter. The segments of the topics are
+// separated by dots, "*" matches one segment and a trailing ">" matches one or more segments.
+func match(filter, topic string) bool {
+	for {
+		segment, filterRest, filterMore := strings.Cut(filter, ".")
+		if segment == ">" && !filterMore {
+			return topic != ""
+		}
+		topicSegment, topicRest, topicMore := strings.Cut(topic, ".")
+		if segment != "*" && segment != topicSegment {
+			return false
+		}
+		if !filterMore || !topicMore {
+			return filterMore == topicMore
+		}
+		filter, topic = filterRest, topicRest
+	}
+}
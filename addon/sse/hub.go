@@ -0,0 +1,356 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// resubscribeDelay is the delay before the PubSub is subscribed again after subscribing failed.
+const resubscribeDelay = time.Second
+
+// ErrTopicRequired is returned by Publish for an event without topic.
+var ErrTopicRequired = errors.New("sse: the topic of the event is required")
+
+// Hub manages the subscribers of the topics and sends them the published events.
+// The last events are buffered, so the subscribers which reconnect catch up.
+type Hub struct {
+	cfg      Config
+	cancel   context.CancelFunc
+	instance string
+	ids      atomic.Uint64
+
+	mutex    sync.Mutex
+	topics   map[string]map[*subscriber]struct{}
+	patterns map[*subscriber]struct{}
+	replay   *ring
+	count    int
+	closed   bool
+}
+
+// subscriber is a client which is connected to the hub.
+type subscriber struct {
+	filters []string
+	events  chan *Event
+	done    chan struct{}
+	once    sync.Once
+}
+
+// New creates a new hub. With a PubSub, the events of all instances are received
+// until Close is called.
+func New(config ...Config) *Hub {
+	// Set default config
+	cfg := configDefault(config...)
+
+	instance := make([]byte, 4)
+	_, _ = rand.Read(instance) //nolint:errcheck // crypto/rand doesn't fail
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &Hub{
+		cfg:      cfg,
+		cancel:   cancel,
+		instance: hex.EncodeToString(instance),
+		topics:   make(map[string]map[*subscriber]struct{}),
+		patterns: make(map[*subscriber]struct{}),
+		replay:   newRing(cfg.ReplaySize),
+	}
+	if cfg.PubSub != nil {
+		go h.receive(ctx)
+	}
+	return h
+}
+
+// Publish sends the event to the subscribers of its topic. With a PubSub, the event is
+// sent to the hubs of all instances, including this one.
+func (h *Hub) Publish(ctx context.Context, event Event) error {
+	if event.Topic == "" {
+		return ErrTopicRequired
+	}
+	if event.ID == "" {
+		event.ID = h.instance + "-" + strconv.FormatUint(h.ids.Add(1), 10)
+	}
+
+	if h.cfg.PubSub == nil {
+		h.dispatch(&event)
+		return nil
+	}
+	message, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("sse: failed to encode the event: %w", err)
+	}
+	if err := h.cfg.PubSub.Publish(ctx, h.cfg.Channel, message); err != nil {
+		return fmt.Errorf("sse: failed to publish the event: %w", err)
+	}
+	return nil
+}
+
+// Subscribers returns the number of the connected subscribers.
+func (h *Hub) Subscribers() int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.count
+}
+
+// Close disconnects the subscribers and stops receiving the events of the PubSub.
+// The streams block the graceful shutdown, so it should be called by an OnShutdown hook.
+func (h *Hub) Close() error {
+	h.cancel()
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.closed = true
+	for _, subscribers := range h.topics {
+		for sub := range subscribers {
+			h.remove(sub)
+		}
+	}
+	for sub := range h.patterns {
+		h.remove(sub)
+	}
+	return nil
+}
+
+// Handler returns the handler of the event streams. The subscribers which send the
+// Last-Event-ID header receive the buffered events after the event first.
+func (h *Hub) Handler() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		filters, err := h.cfg.Topics(c)
+		if err != nil {
+			return err
+		}
+		if len(filters) == 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "no topic")
+		}
+
+		sub := &subscriber{
+			filters: filters,
+			events:  make(chan *Event, h.cfg.BufferSize),
+			done:    make(chan struct{}),
+		}
+		replay, ok := h.subscribe(sub, c.Get(fiber.HeaderLastEventID))
+		if !ok {
+			return fiber.ErrServiceUnavailable
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set("X-Accel-Buffering", "no")
+		// the streams are ended with the shutdown event when the graceful shutdown begins
+		drain, done := c.App().TrackStream()
+		return c.SendStreamWriter(func(w *bufio.Writer) {
+			defer done()
+			defer h.unsubscribe(sub)
+			h.stream(w, sub, replay, drain)
+		})
+	}
+}
+
+// stream writes the events to the client until it disconnects, the subscriber is removed
+// or the app is drained.
+func (h *Hub) stream(w *bufio.Writer, sub *subscriber, replay []*Event, drain <-chan struct{}) {
+	if h.cfg.Retry > 0 {
+		_, _ = w.WriteString("retry: " + strconv.FormatInt(h.cfg.Retry.Milliseconds(), 10) + "\n\n") //nolint:errcheck // the error is returned by Flush
+	}
+	for _, event := range replay {
+		event.write(w)
+	}
+	if err := w.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.cfg.HeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event := <-sub.events:
+			event.write(w)
+			// the queued events are sent at once
+			for len(sub.events) > 0 {
+				(<-sub.events).write(w)
+			}
+		case <-heartbeat.C:
+			_, _ = w.WriteString(": heartbeat\n\n") //nolint:errcheck // the error is returned by Flush
+		case <-sub.done:
+			return
+		case <-drain:
+			if h.cfg.ShutdownEvent != nil {
+				h.cfg.ShutdownEvent.write(w)
+				_ = w.Flush() //nolint:errcheck // the stream ends anyway
+			}
+			return
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// subscribe adds the subscriber and returns the buffered events after the last event,
+// or all buffered events which match if the last event isn't buffered anymore.
+func (h *Hub) subscribe(sub *subscriber, lastEventID string) ([]*Event, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.closed {
+		return nil, false
+	}
+
+	var replay []*Event
+	if lastEventID != "" {
+		replay = h.replay.after(lastEventID, sub.matches)
+	}
+
+	for _, filter := range sub.filters {
+		if isPattern(filter) {
+			h.patterns[sub] = struct{}{}
+			continue
+		}
+		if h.topics[filter] == nil {
+			h.topics[filter] = make(map[*subscriber]struct{})
+		}
+		h.topics[filter][sub] = struct{}{}
+	}
+	h.count++
+	return replay, true
+}
+
+// unsubscribe removes the subscriber.
+func (h *Hub) unsubscribe(sub *subscriber) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.remove(sub)
+}
+
+// remove removes the subscriber and ends its stream, the hub must be locked.
+func (h *Hub) remove(sub *subscriber) {
+	sub.once.Do(func() {
+		for _, filter := range sub.filters {
+			delete(h.topics[filter], sub)
+			if len(h.topics[filter]) == 0 {
+				delete(h.topics, filter)
+			}
+		}
+		delete(h.patterns, sub)
+		h.count--
+		close(sub.done)
+	})
+}
+
+// dispatch buffers the event and queues it for the subscribers of its topic.
+// Subscribers whose queue is full are removed.
+func (h *Hub) dispatch(event *Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.replay.add(event)
+
+	send := func(sub *subscriber) {
+		select {
+		case <-sub.done:
+			// the subscriber was removed
+		case sub.events <- event:
+		default:
+			log.Warnw("sse: the subscriber fell behind and is disconnected", "topic", event.Topic)
+			h.remove(sub)
+		}
+	}
+	exact := h.topics[event.Topic]
+	for sub := range exact {
+		send(sub)
+	}
+	for sub := range h.patterns {
+		// the subscribers with the topic as filter received the event already
+		if _, ok := exact[sub]; !ok && sub.matches(event) {
+			send(sub)
+		}
+	}
+}
+
+// receive dispatches the events of the PubSub until the context is done.
+func (h *Hub) receive(ctx context.Context) {
+	for {
+		err := h.cfg.PubSub.Subscribe(ctx, h.cfg.Channel, func(message []byte) {
+			event := &Event{}
+			if err := json.Unmarshal(message, event); err != nil {
+				log.Errorw("sse: failed to decode the event", "error", err)
+				return
+			}
+			h.dispatch(event)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Errorw("sse: failed to subscribe to the events", "channel", h.cfg.Channel, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// matches returns true if the topic of the event matches a filter of the subscriber.
+func (sub *subscriber) matches(event *Event) bool {
+	for _, filter := range sub.filters {
+		if filter == event.Topic || (isPattern(filter) && match(filter, event.Topic)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ring buffers the last events.
+type ring struct {
+	events []*Event
+	next   int
+	full   bool
+}
+
+// newRing returns a ring of the size.
+func newRing(size int) *ring {
+	return &ring{events: make([]*Event, size)}
+}
+
+// add buffers the event, the oldest event is replaced if the ring is full.
+func (r *ring) add(event *Event) {
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// after returns the buffered events after the event with the ID which match, or all
+// buffered events which match if the event isn't buffered anymore.
+func (r *ring) after(id string, matches func(*Event) bool) []*Event {
+	var ordered []*Event
+	if r.full {
+		ordered = append(ordered, r.events[r.next:]...)
+	}
+	ordered = append(ordered, r.events[:r.next]...)
+
+	for i := len(ordered) - 1; i >= 0; i-- {
+		if ordered[i].ID == id {
+			ordered = ordered[i+1:]
+			break
+		}
+	}
+
+	var events []*Event
+	for _, event := range ordered {
+		if matches(event) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
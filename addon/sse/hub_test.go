@@ -0,0 +1,253 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// listen serves the app with an in-memory listener.
+func listen(t *testing.T, app *fiber.App) *fasthttputil.InmemoryListener {
+	t.Helper()
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln, fiber.ListenConfig{DisableStartupMessage: true}))
+	}()
+	return ln
+}
+
+// connect requests the event stream and returns its reader.
+func connect(t *testing.T, ln *fasthttputil.InmemoryListener, path, lastEventID string) *bufio.Reader {
+	t.Helper()
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		var err error
+		conn, err = ln.Dial()
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	t.Cleanup(func() {
+		_ = conn.Close() //nolint:errcheck // It's a test
+	})
+
+	request := "GET " + path + " HTTP/1.1\r\nHost: example.com\r\n"
+	if lastEventID != "" {
+		request += "Last-Event-ID: " + lastEventID + "\r\n"
+	}
+	_, err := conn.Write([]byte(request + "\r\n"))
+	require.NoError(t, err)
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get(fiber.HeaderContentType))
+	return bufio.NewReader(resp.Body)
+}
+
+// readEvent reads the next event of the stream, the comments are skipped.
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var event strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		switch {
+		case line == "\n" && event.Len() > 0:
+			return event.String()
+		case line == "\n", strings.HasPrefix(line, ":"):
+			continue
+		}
+		event.WriteString(line)
+	}
+}
+
+// go test -run Test_Hub
+func Test_Hub(t *testing.T) {
+	t.Parallel()
+	hub := New(Config{Retry: 3 * time.Second})
+	app := fiber.New()
+	app.Get("/events", hub.Handler())
+	app.Hooks().OnShutdown(hub.Close)
+	ln := listen(t, app)
+	defer app.Shutdown() //nolint:errcheck // It's a test
+
+	orders := connect(t, ln, "/events?topic=orders.*", "")
+	alerts := connect(t, ln, "/events?topic=alerts,orders.>", "")
+	require.Equal(t, "retry: 3000\n", readEvent(t, orders))
+	require.Eventually(t, func() bool {
+		return hub.Subscribers() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	ctx := context.Background()
+	require.NoError(t, hub.Publish(ctx, Event{Topic: "orders.created", ID: "1", Type: "order", Data: "{\"id\":1}\n{\"id\":2}"}))
+	require.NoError(t, hub.Publish(ctx, Event{Topic: "alerts", ID: "2", Data: "disk"}))
+	require.NoError(t, hub.Publish(ctx, Event{Topic: "orders.eu.created", ID: "3", Data: "eu"}))
+	require.ErrorIs(t, hub.Publish(ctx, Event{Data: "no topic"}), ErrTopicRequired)
+
+	require.Equal(t, "id: 1\nevent: order\ndata: {\"id\":1}\ndata: {\"id\":2}\n", readEvent(t, orders))
+	require.Equal(t, "retry: 3000\n", readEvent(t, alerts))
+	require.Equal(t, "id: 1\nevent: order\ndata: {\"id\":1}\ndata: {\"id\":2}\n", readEvent(t, alerts))
+	require.Equal(t, "id: 2\ndata: disk\n", readEvent(t, alerts))
+	require.Equal(t, "id: 3\ndata: eu\n", readEvent(t, alerts))
+
+	// the reconnecting subscribers catch up with the buffered events
+	replay := connect(t, ln, "/events?topic=alerts,orders.>", "1")
+	require.Equal(t, "retry: 3000\n", readEvent(t, replay))
+	require.Equal(t, "id: 2\ndata: disk\n", readEvent(t, replay))
+	require.Equal(t, "id: 3\ndata: eu\n", readEvent(t, replay))
+
+	// a generated ID
+	require.NoError(t, hub.Publish(ctx, Event{Topic: "alerts", Data: "cpu"}))
+	require.Regexp(t, `^id: [0-9a-f]{8}-1\ndata: cpu\n$`, readEvent(t, replay))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/events", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	require.NoError(t, hub.Close())
+	require.Equal(t, 0, hub.Subscribers())
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/events?topic=alerts", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+}
+
+// go test -run Test_Hub_ShutdownEvent
+func Test_Hub_ShutdownEvent(t *testing.T) {
+	t.Parallel()
+	hub := New(Config{Retry: time.Second, ShutdownEvent: &Event{Type: "shutdown", Data: "bye"}})
+	app := fiber.New()
+	app.Get("/events", hub.Handler())
+	app.Hooks().OnShutdown(hub.Close)
+	ln := listen(t, app)
+
+	events := connect(t, ln, "/events?topic=orders", "")
+	require.Equal(t, "retry: 1000\n", readEvent(t, events))
+	require.Eventually(t, func() bool {
+		return hub.Subscribers() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- app.Shutdown()
+	}()
+	// the event is sent without an ID, the clients keep the ID of the last event
+	require.Equal(t, "event: shutdown\ndata: bye\n", readEvent(t, events))
+	require.NoError(t, <-shutdown)
+	require.Equal(t, 0, hub.Subscribers())
+}
+
+// memoryPubSub is a PubSub of the instances of a process.
+type memoryPubSub struct {
+	subscribers map[string][]func(message []byte)
+	mutex       sync.Mutex
+}
+
+func (ps *memoryPubSub) Publish(_ context.Context, channel string, message []byte) error {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	for _, fn := range ps.subscribers[channel] {
+		fn(message)
+	}
+	return nil
+}
+
+func (ps *memoryPubSub) Subscribe(ctx context.Context, channel string, fn func(message []byte)) error {
+	ps.mutex.Lock()
+	ps.subscribers[channel] = append(ps.subscribers[channel], fn)
+	ps.mutex.Unlock()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// go test -run Test_Hub_PubSub
+func Test_Hub_PubSub(t *testing.T) {
+	t.Parallel()
+	pubsub := &memoryPubSub{subscribers: make(map[string][]func(message []byte))}
+	first, second := New(Config{PubSub: pubsub}), New(Config{PubSub: pubsub, BufferSize: 1})
+	defer first.Close()  //nolint:errcheck // It's a test
+	defer second.Close() //nolint:errcheck // It's a test
+	require.Eventually(t, func() bool {
+		pubsub.mutex.Lock()
+		defer pubsub.mutex.Unlock()
+		return len(pubsub.subscribers["fiber_sse"]) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	sub := &subscriber{filters: []string{"orders.*"}, events: make(chan *Event, 1), done: make(chan struct{})}
+	_, ok := second.subscribe(sub, "")
+	require.True(t, ok)
+
+	// the events of the first instance reach the subscribers of the second
+	require.NoError(t, first.Publish(context.Background(), Event{Topic: "orders.created", ID: "1", Data: "1"}))
+	event := <-sub.events
+	require.Equal(t, "orders.created", event.Topic)
+	require.Equal(t, "1", event.Data)
+
+	// a subscriber which falls behind is disconnected
+	require.NoError(t, first.Publish(context.Background(), Event{Topic: "orders.created", ID: "2", Data: "2"}))
+	require.NoError(t, first.Publish(context.Background(), Event{Topic: "orders.created", ID: "3", Data: "3"}))
+	<-sub.done
+	require.Equal(t, 0, second.Subscribers())
+
+	// both instances buffered the events
+	for _, hub := range []*Hub{first, second} {
+		hub.mutex.Lock()
+		replay := hub.replay.after("1", sub.matches)
+		hub.mutex.Unlock()
+		require.Len(t, replay, 2)
+		require.Equal(t, "3", replay[1].ID)
+	}
+}
+
+// go test -run Test_Ring
+func Test_Ring(t *testing.T) {
+	t.Parallel()
+	r := newRing(3)
+	all := func(*Event) bool { return true }
+	for _, id := range []string{"1", "2", "3", "4"} {
+		r.add(&Event{ID: id})
+	}
+	ids := func(events []*Event) []string {
+		var ids []string
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+		return ids
+	}
+	require.Equal(t, []string{"4"}, ids(r.after("3", all)))
+	require.Empty(t, ids(r.after("4", all)))
+	// the event isn't buffered anymore, all buffered events are replayed
+	require.Equal(t, []string{"2", "3", "4"}, ids(r.after("1", all)))
+}
+
+// go test -run Test_Match
+func Test_Match(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		filter string
+		topic  string
+		match  bool
+	}{
+		{filter: "orders", topic: "orders", match: true},
+		{filter: "orders", topic: "orders.created", match: false},
+		{filter: "orders.*", topic: "orders.created", match: true},
+		{filter: "orders.*", topic: "orders", match: false},
+		{filter: "orders.*", topic: "orders.eu.created", match: false},
+		{filter: "*.created", topic: "orders.created", match: true},
+		{filter: "orders.>", topic: "orders.eu.created", match: true},
+		{filter: "orders.>", topic: "orders", match: false},
+		{filter: ">", topic: "alerts", match: true},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.match, match(tt.filter, tt.topic), "%s %s", tt.filter, tt.topic)
+	}
+}
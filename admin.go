@@ -0,0 +1,253 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// NetworkUnix is the network of unix domain sockets, e.g. for the admin listener
+const NetworkUnix = "unix"
+
+// AdminConfig is a struct to configure the admin API of ListenAdmin.
+type AdminConfig struct {
+	// Auth authorizes the requests of the admin API, e.g. the basicauth or keyauth middleware.
+	//
+	// Default: nil (only requests from loopback addresses and unix sockets are allowed)
+	Auth Handler `json:"-"`
+
+	// Network of the admin listener, e.g. "tcp4" or "unix".
+	//
+	// Default: NetworkTCP4
+	Network string `json:"network"`
+
+	// ShutdownTimeout is the timeout of the graceful shutdown triggered by the admin API.
+	//
+	// Default: 10 * time.Second
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+
+	// Routes registers additional endpoints of the admin API, e.g. of the chaos middleware.
+	// They are registered after the endpoints of the app and are authorized by Auth.
+	//
+	// Default: nil
+	Routes func(router Router) `json:"-"`
+}
+
+// adminMaintenance is the body of the maintenance endpoint
+type adminMaintenance struct {
+	Enabled bool `json:"enabled"`
+}
+
+// adminLogLevel is the body of the log level endpoint
+type adminLogLevel struct {
+	Level string `json:"level"`
+}
+
+// adminThrottle is the body of the throttle endpoint, a nil override restores the automatic adjustment
+type adminThrottle struct {
+	Override *int `json:"override"`
+}
+
+// adminConnections is the response of the connections endpoint
+type adminConnections struct {
+	Connections []ConnInfo `json:"connections"`
+	Stats       ConnStats  `json:"stats"`
+}
+
+func adminConfigDefault(config ...AdminConfig) AdminConfig {
+	var cfg AdminConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Network == "" {
+		cfg.Network = NetworkTCP4
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		cfg.ShutdownTimeout = 10 * time.Second
+	}
+	if cfg.Auth == nil {
+		network := cfg.Network
+		cfg.Auth = func(c Ctx) error {
+			if network != NetworkUnix && !c.IsFromLocal() {
+				return ErrForbidden
+			}
+			return c.Next()
+		}
+	}
+	return cfg
+}
+
+// ListenAdmin serves the admin API of the app on a separate listener,
+// e.g. on another port or a unix socket. It blocks like Listen and is shut
+// down together with the app.
+//
+//	go app.ListenAdmin("127.0.0.1:9000")
+//	go app.ListenAdmin("/run/app/admin.sock", AdminConfig{Network: NetworkUnix})
+func (app *App) ListenAdmin(addr string, config ...AdminConfig) error {
+	cfg := adminConfigDefault(config...)
+
+	ln, err := net.Listen(cfg.Network, addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	admin := app.AdminApp(cfg)
+	app.Hooks().OnShutdown(func() error {
+		return admin.Shutdown()
+	})
+
+	return admin.Listener(ln, ListenConfig{DisableStartupMessage: true})
+}
+
+// AdminApp returns a new app which serves the admin API of the app:
+//
+//	GET  /routes       the registered routes
+//	GET  /routes/chain the handlers of a request, ?method=GET&path=/users/1, see DryRun
+//	GET  /config       the config of the app
+//	GET  /maintenance  the maintenance mode, {"enabled": true}
+//	PUT  /maintenance  enables or disables the maintenance mode, {"enabled": true}
+//	PUT  /log/level    changes the level of the global logger, {"level": "debug"}
+//	GET  /throttle     the state of the adaptive throttle
+//	PUT  /throttle     overrides the shed percentage of the throttle, {"override": 50}
+//	GET  /stats        the connection and request statistics, see Stats
+//	GET  /stats/children the stats of each prefork child, see PreforkChildren
+//	GET  /connections  the open connections and the connection counters
+//	GET  /requests     the recent requests of the flight recorder, see RecentRequests
+//	POST /drain        closes the keep-alive connections after their next response
+//	POST /shutdown     shuts the app down gracefully
+//
+// The endpoints of AdminConfig.Routes are added to them.
+func (app *App) AdminApp(config ...AdminConfig) *App {
+	cfg := adminConfigDefault(config...)
+	admin := New(Config{
+		AppName:     app.config.AppName + " admin",
+		JSONEncoder: app.config.JSONEncoder,
+		JSONDecoder: app.config.JSONDecoder,
+	})
+
+	admin.Use(cfg.Auth)
+
+	admin.Get("/routes", func(c Ctx) error {
+		return c.JSON(app.GetRoutes(true))
+	})
+
+	admin.Get("/routes/chain", func(c Ctx) error {
+		path := c.Query("path")
+		if path == "" {
+			return ErrBadRequest
+		}
+		return c.JSON(app.DryRun(c.Query("method", MethodGet), path))
+	})
+
+	admin.Get("/config", func(c Ctx) error {
+		return c.JSON(app.Config())
+	})
+
+	admin.Get("/maintenance", func(c Ctx) error {
+		return c.JSON(adminMaintenance{Enabled: app.MaintenanceMode()})
+	})
+
+	admin.Put("/maintenance", func(c Ctx) error {
+		var body adminMaintenance
+		if err := admin.config.JSONDecoder(c.Body(), &body); err != nil {
+			return ErrBadRequest
+		}
+		app.SetMaintenanceMode(body.Enabled)
+		app.logw(log.LevelInfo, "admin: maintenance mode changed", "enabled", body.Enabled)
+		return c.JSON(body)
+	})
+
+	admin.Get("/log", func(c Ctx) error {
+		return c.JSON(app.LogStats())
+	})
+
+	admin.Put("/log/level", func(c Ctx) error {
+		var body adminLogLevel
+		if err := admin.config.JSONDecoder(c.Body(), &body); err != nil {
+			return ErrBadRequest
+		}
+		level, err := log.ParseLevel(body.Level)
+		if err != nil {
+			return NewError(StatusBadRequest, err.Error())
+		}
+		app.SetLogLevel(level)
+		app.logw(log.LevelInfo, "admin: log level changed", "level", level.String())
+		return c.JSON(body)
+	})
+
+	admin.Get("/stats", func(c Ctx) error {
+		return c.JSON(app.Stats())
+	})
+
+	admin.Get("/stats/children", func(c Ctx) error {
+		return c.JSON(app.PreforkChildren())
+	})
+
+	admin.Get("/requests", func(c Ctx) error {
+		if app.flightRecorder == nil {
+			return NewError(StatusConflict, "the flight recorder is disabled")
+		}
+		return c.JSON(app.RecentRequests())
+	})
+
+	admin.Get("/throttle", func(c Ctx) error {
+		return c.JSON(app.ThrottleStats())
+	})
+
+	admin.Put("/throttle", func(c Ctx) error {
+		if app.throttle == nil {
+			return NewError(StatusConflict, "the throttle is disabled")
+		}
+		var body adminThrottle
+		if err := admin.config.JSONDecoder(c.Body(), &body); err != nil {
+			return ErrBadRequest
+		}
+		percent := -1
+		if body.Override != nil {
+			if *body.Override < 0 || *body.Override > 100 {
+				return NewError(StatusBadRequest, "the override must be between 0 and 100")
+			}
+			percent = *body.Override
+		}
+		app.SetThrottleOverride(percent)
+		return c.JSON(app.ThrottleStats())
+	})
+
+	admin.Get("/connections", func(c Ctx) error {
+		// the counters are aggregated in the prefork master process, the connections aren't
+		return c.JSON(adminConnections{
+			Connections: app.Connections(),
+			Stats:       app.Stats().Connections,
+		})
+	})
+
+	admin.Post("/drain", func(c Ctx) error {
+		keepAlive := app.KeepAlive()
+		keepAlive.CloseConnection = func(Ctx) bool { return true }
+		app.SetKeepAlive(keepAlive)
+		app.logw(log.LevelInfo, "admin: draining keep-alive connections")
+		return c.SendStatus(StatusAccepted)
+	})
+
+	admin.Post("/shutdown", func(c Ctx) error {
+		app.logw(log.LevelInfo, "admin: shutting down")
+		go func() {
+			if err := app.ShutdownWithTimeout(cfg.ShutdownTimeout); err != nil {
+				app.logw(log.LevelError, "admin: failed to shut down", "error", err)
+			}
+		}()
+		return c.SendStatus(StatusAccepted)
+	})
+
+	if cfg.Routes != nil {
+		cfg.Routes(admin)
+	}
+
+	return admin
+}
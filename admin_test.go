@@ -0,0 +1,162 @@
+package fiber
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+func testAdminRequest(t *testing.T, admin *App, method, path, body string) (int, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("X-Admin-Token", "secret")
+	resp, err := admin.Test(req)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, string(data)
+}
+
+// go test -run Test_App_AdminApp
+func Test_App_AdminApp(t *testing.T) {
+	t.Parallel()
+	app := New(Config{AppName: "api"})
+	app.Get("/users/:id", testSimpleHandler).Name("user")
+
+	admin := app.AdminApp(AdminConfig{
+		Auth: func(c Ctx) error {
+			if c.Get("X-Admin-Token") != "secret" {
+				return ErrUnauthorized
+			}
+			return c.Next()
+		},
+	})
+
+	resp, err := admin.Test(httptest.NewRequest(MethodGet, "/routes", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusUnauthorized, resp.StatusCode)
+
+	status, body := testAdminRequest(t, admin, MethodGet, "/routes", "")
+	require.Equal(t, StatusOK, status)
+	var routes []Route
+	require.NoError(t, json.Unmarshal([]byte(body), &routes))
+	require.Len(t, routes, 1)
+	require.Equal(t, "/users/:id", routes[0].Path)
+	require.Equal(t, "user", routes[0].Name)
+
+	status, body = testAdminRequest(t, admin, MethodGet, "/config", "")
+	require.Equal(t, StatusOK, status)
+	require.Contains(t, body, `"app_name":"api"`)
+
+	status, body = testAdminRequest(t, admin, MethodPut, "/maintenance", `{"enabled":true}`)
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, `{"enabled":true}`, body)
+	require.True(t, app.MaintenanceMode())
+	_, body = testAdminRequest(t, admin, MethodGet, "/maintenance", "")
+	require.Equal(t, `{"enabled":true}`, body)
+
+	status, _ = testAdminRequest(t, admin, MethodPut, "/maintenance", `{`)
+	require.Equal(t, StatusBadRequest, status)
+
+	status, _ = testAdminRequest(t, admin, MethodPut, "/log/level", `{"level":"verbose"}`)
+	require.Equal(t, StatusBadRequest, status)
+	status, _ = testAdminRequest(t, admin, MethodPut, "/log/level", `{"level":"warn"}`)
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, log.LevelWarn, app.LogLevel())
+	_, body = testAdminRequest(t, admin, MethodGet, "/log", "")
+	require.Equal(t, `{"level":"warn","dropped":0}`, body)
+
+	status, body = testAdminRequest(t, admin, MethodGet, "/stats", "")
+	require.Equal(t, StatusOK, status)
+	require.Contains(t, body, `"in_flight":0`)
+
+	status, body = testAdminRequest(t, admin, MethodGet, "/stats/children", "")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "null", body)
+
+	status, body = testAdminRequest(t, admin, MethodGet, "/connections", "")
+	require.Equal(t, StatusOK, status)
+	require.Contains(t, body, `"stats":{"open":0`)
+
+	status, _ = testAdminRequest(t, admin, MethodPost, "/drain", "")
+	require.Equal(t, StatusAccepted, status)
+	require.True(t, app.KeepAlive().CloseConnection(nil))
+}
+
+// go test -run Test_App_AdminApp_DefaultAuth
+func Test_App_AdminApp_DefaultAuth(t *testing.T) {
+	t.Parallel()
+	admin := New().AdminApp()
+
+	// app.Test doesn't use a loopback address
+	resp, err := admin.Test(httptest.NewRequest(MethodGet, "/routes", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusForbidden, resp.StatusCode)
+
+	resp, err = New().AdminApp(AdminConfig{Network: NetworkUnix}).Test(httptest.NewRequest(MethodGet, "/routes", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_App_AdminApp_Routes
+func Test_App_AdminApp_Routes(t *testing.T) {
+	t.Parallel()
+	admin := New().AdminApp(AdminConfig{
+		Auth: func(c Ctx) error {
+			if c.Get("X-Admin-Token") != "secret" {
+				return ErrUnauthorized
+			}
+			return c.Next()
+		},
+		Routes: func(router Router) {
+			router.Get("/custom", func(c Ctx) error {
+				return c.SendString("custom")
+			})
+		},
+	})
+
+	status, body := testAdminRequest(t, admin, MethodGet, "/custom", "")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "custom", body)
+
+	// the endpoints are authorized
+	resp, err := admin.Test(httptest.NewRequest(MethodGet, "/custom", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusUnauthorized, resp.StatusCode)
+}
+
+// go test -run Test_App_Connections
+func Test_App_Connections(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", testSimpleHandler)
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		_ = app.Listener(ln, ListenConfig{DisableStartupMessage: true}) //nolint:errcheck // not needed
+	}()
+	defer func() {
+		require.NoError(t, app.Shutdown())
+	}()
+
+	conn, err := ln.Dial()
+	require.NoError(t, err)
+	defer func(conn net.Conn) {
+		require.NoError(t, conn.Close())
+	}(conn)
+
+	require.Eventually(t, func() bool {
+		return len(app.Connections()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.NotEmpty(t, app.Connections()[0].RemoteAddr)
+}
@@ -0,0 +1,46 @@
+//go:build fiber_allocaudit
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"runtime"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// allocAuditEnabled enables the allocation audit of the request hot path.
+// It is enabled with the fiber_allocaudit build tag:
+//
+//	go run -tags fiber_allocaudit .
+//
+// The audit counts the heap allocations of the process from the start of a request
+// until the first handler is called, which includes the ctx reset, the header handling,
+// the routing and the param decoding. The counters are global, so the requests should
+// be sent one after another to get exact results.
+const allocAuditEnabled = true
+
+// reportAllocs is called for every request whose hot path allocated on the heap.
+var reportAllocs = func(c *DefaultCtx, allocs uint64) {
+	c.app.logw(log.LevelWarn, "heap allocations before the handler", "allocs", allocs, "method", c.Method(), "url", c.OriginalURL())
+}
+
+// heapAllocs returns the total number of heap allocations of the process.
+func heapAllocs() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.Mallocs
+}
+
+// auditAllocs reports the heap allocations since the start of the request.
+// It is only called once per request.
+func (*App) auditAllocs(c *DefaultCtx) {
+	allocs := heapAllocs() - c.allocAuditStart
+	c.allocAuditStart = 0
+	if allocs > 0 {
+		reportAllocs(c, allocs)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !fiber_allocaudit
+
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+// allocAuditEnabled is false without the fiber_allocaudit build tag,
+// so the audit is removed from the request hot path by the compiler.
+const allocAuditEnabled = false
+
+func heapAllocs() uint64 {
+	return 0
+}
+
+func (*App) auditAllocs(*DefaultCtx) {}
@@ -0,0 +1,53 @@
+//go:build fiber_allocaudit
+
+package fiber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -tags fiber_allocaudit -run Test_App_AllocAudit
+//
+//nolint:paralleltest // The reporter is replaced and the allocations of the whole process are counted
+func Test_App_AllocAudit(t *testing.T) {
+	var reports []uint64
+	defaultReporter := reportAllocs
+	reportAllocs = func(_ *DefaultCtx, allocs uint64) {
+		reports = append(reports, allocs)
+	}
+	defer func() {
+		reportAllocs = defaultReporter
+	}()
+
+	app := New()
+	app.Get("/:name", func(c Ctx) error {
+		return c.SendString(c.Params("name"))
+	})
+	appHandler := app.Handler()
+
+	c := &fasthttp.RequestCtx{}
+	c.Request.Header.SetMethod(MethodGet)
+	c.Request.SetRequestURI("/fiber")
+
+	// the first request allocates the buffers of the ctx
+	appHandler(c)
+	require.Equal(t, "fiber", string(c.Response.Body()))
+	require.NotEmpty(t, reports)
+
+	reports = reports[:0]
+	for i := 0; i < 10; i++ {
+		appHandler(c)
+	}
+	require.Empty(t, reports)
+
+	// allocations of the hot path are reported before the handler is called
+	ctx := app.AcquireCtx(c).(*DefaultCtx) //nolint:errcheck,forcetypeassert // not needed
+	ctx.allocAuditStart = heapAllocs()
+	_ = make([]byte, 1<<20) //nolint:makezero // allocate on the heap
+	app.auditAllocs(ctx)
+	require.Len(t, reports, 1)
+	require.Zero(t, ctx.allocAuditStart)
+}
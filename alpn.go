@@ -0,0 +1,116 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"crypto/tls"
+	"net"
+	"slices"
+	"sort"
+)
+
+// http1Proto is the ALPN protocol name of HTTP/1.1
+const http1Proto = "http/1.1"
+
+// HandleALPN registers a handler for the TLS connections which negotiated the protocol
+// with ALPN, e.g. "acme-tls/1" or "postgresql", so one port serves HTTP and the protocol.
+// Listen offers the protocols in the TLS config after the HTTP protocols, when using
+// Listener, the TLS config of the listener has to contain them in NextProtos.
+// A handler for "h2" or "http/1.1" replaces the HTTP server for the clients which negotiated
+// the protocol, the connections without a negotiated protocol are still served by the app.
+//
+// The connections are handled like the connections of ListenTCP: each in its own goroutine,
+// they are closed when the handler returns and awaited by Shutdown. The handshake is
+// completed before the handler is called. The counters of the protocols are reported in
+// the Companions of Stats. The handlers aren't supported with prefork.
+//
+//	app.HandleALPN("postgresql", func(conn net.Conn) {
+//	    proxyPostgres(conn)
+//	})
+func (app *App) HandleALPN(protocol string, handler ConnHandler) {
+	if protocol == "" {
+		panic("alpn: missing protocol")
+	}
+	if handler == nil {
+		panic("alpn: missing handler for protocol " + protocol)
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	if app.alpnHandlers == nil {
+		app.alpnHandlers = make(map[string]ConnHandler)
+	}
+	app.alpnHandlers[protocol] = handler
+}
+
+// alpnProtocols returns the sorted protocols of the ALPN handlers.
+func (app *App) alpnProtocols() []string {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+	protocols := make([]string, 0, len(app.alpnHandlers))
+	for protocol := range app.alpnHandlers {
+		protocols = append(protocols, protocol)
+	}
+	sort.Strings(protocols)
+	return protocols
+}
+
+// offerALPN adds the protocols of the ALPN handlers to the protocols of the TLS config.
+// They're added after the HTTP protocols, which are preferred if the client offers both.
+func (app *App) offerALPN(tlsConfig *tls.Config) {
+	protocols := app.alpnProtocols()
+	if len(protocols) == 0 {
+		return
+	}
+	// the HTTP/1.1 clients which offer protocols are still served
+	if len(tlsConfig.NextProtos) == 0 {
+		tlsConfig.NextProtos = []string{http1Proto}
+	}
+	for _, protocol := range protocols {
+		if !slices.Contains(tlsConfig.NextProtos, protocol) {
+			tlsConfig.NextProtos = append(tlsConfig.NextProtos, protocol)
+		}
+	}
+}
+
+// serveTLS serves the TLS listener with the protocols which are negotiated with ALPN:
+// the protocols of the ALPN handlers, HTTP/2 if it is enabled and HTTP/1.1, which is
+// served by fasthttp like the connections without a negotiated protocol.
+func (app *App) serveTLS(ln net.Listener, cfg ListenConfig) error {
+	http1 := newConnListener(ln)
+	protocols := make(map[string]*connListener)
+
+	app.mutex.Lock()
+	for protocol, handler := range app.alpnHandlers {
+		l := newConnListener(ln)
+		protocols[protocol] = l
+		cl := &companionListener{
+			listener: l,
+			network:  NetworkTCP,
+			addr:     ln.Addr().String(),
+			protocol: protocol,
+			conns:    make(map[net.Conn]struct{}),
+		}
+		app.companions.add(cl)
+		cl.acceptConns(app, handler)
+	}
+	app.mutex.Unlock()
+
+	if _, ok := protocols[http2Proto]; cfg.EnableHTTP2 && !ok {
+		protocols[http2Proto] = newConnListener(ln)
+		app.startHTTP2(protocols[http2Proto], cfg)
+	}
+	go dispatchConns(ln, http1, protocols)
+	return app.server.Serve(http1)
+}
+
+// servesTLS returns true if the TLS connections of the listener have to be dispatched
+// by the protocol which was negotiated with ALPN.
+func (app *App) servesTLS(ln net.Listener, cfg ListenConfig) bool {
+	if getTLSConfig(ln) == nil {
+		return false
+	}
+	return cfg.EnableHTTP2 || len(app.alpnProtocols()) > 0
+}
@@ -0,0 +1,103 @@
+package fiber
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// go test -run Test_App_HandleALPN
+func Test_App_HandleALPN(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.SendString(c.Protocol())
+	})
+	app.HandleALPN("echo/1", func(conn net.Conn) {
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("echo " + line)) //nolint:errcheck // It's a test
+	})
+
+	cert, err := tls.LoadX509KeyPair("./.github/testdata/ssl.pem", "./.github/testdata/ssl.key")
+	require.NoError(t, err)
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	app.offerALPN(tlsConfig)
+	require.Equal(t, []string{http1Proto, "echo/1"}, tlsConfig.NextProtos)
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(tls.NewListener(ln, tlsConfig), ListenConfig{DisableStartupMessage: true}))
+	}()
+
+	dial := func(protos ...string) *tls.Conn {
+		var conn net.Conn
+		require.Eventually(t, func() bool {
+			conn, err = ln.Dial()
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+		tlsConn := tls.Client(conn, &tls.Config{
+			InsecureSkipVerify: true, //nolint:gosec // self signed test certificate
+			NextProtos:         protos,
+		})
+		require.NoError(t, tlsConn.Handshake())
+		return tlsConn
+	}
+
+	// the negotiated protocol is served by the handler
+	conn := dial("echo/1")
+	_, err = conn.Write([]byte("ping\n"))
+	require.NoError(t, err)
+	reply, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	require.Equal(t, "echo ping\n", string(reply))
+
+	// HTTP is still served on the same port, with and without ALPN
+	for _, protos := range [][]string{{http1Proto, "echo/1"}, nil} {
+		conn = dial(protos...)
+		_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\nConnection: close\r\n\r\n"))
+		require.NoError(t, err)
+		reply, err = io.ReadAll(conn)
+		require.NoError(t, err)
+		require.Contains(t, string(reply), "HTTP/1.1 200 OK")
+	}
+
+	companions := app.Stats().Companions
+	require.Len(t, companions, 1)
+	require.Equal(t, "echo/1", companions[0].Protocol)
+	require.Equal(t, uint64(1), companions[0].Accepted)
+	require.Equal(t, uint64(5), companions[0].BytesIn)
+
+	require.NoError(t, app.Shutdown())
+}
+
+// go test -run Test_App_HandleALPN_Protocols
+func Test_App_HandleALPN_Protocols(t *testing.T) {
+	t.Parallel()
+	app := New()
+	require.Panics(t, func() {
+		app.HandleALPN("", func(net.Conn) {})
+	})
+	require.Panics(t, func() {
+		app.HandleALPN("echo/1", nil)
+	})
+
+	// the protocols are added after the configured protocols
+	app.HandleALPN("b", func(net.Conn) {})
+	app.HandleALPN("a", func(net.Conn) {})
+	tlsConfig := &tls.Config{NextProtos: []string{http2Proto, http1Proto, "b"}} //nolint:gosec // It's a test
+	app.offerALPN(tlsConfig)
+	require.Equal(t, []string{http2Proto, http1Proto, "b", "a"}, tlsConfig.NextProtos)
+}
@@ -14,6 +14,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httputil"
@@ -21,6 +22,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v3/log"
@@ -91,6 +93,10 @@ type App struct {
 	stack [][]*Route
 	// Route stack divided by HTTP methods and route prefixes
 	treeStack []map[string][]*Route
+	// Matcher of the compiled route tree, nil if the routes aren't compiled
+	routeMatcher *routeMatcher
+	// Route tree which is used to serve new requests, published after the tree was built
+	activeTree atomic.Pointer[routeTree]
 	// contains the information if the route stack has been changed to build the optimized tree
 	routesRefreshed bool
 	// Amount of registered routes
@@ -115,6 +121,8 @@ type App struct {
 	newCtxFunc func(app *App) CustomCtx
 	// custom binders
 	customBinders []CustomBinder
+	// renderers of Negotiate
+	renderers []Renderer
 	// TLS handler
 	tlsHandler *TLSHandler
 	// Mount fields
@@ -123,6 +131,80 @@ type App struct {
 	configured Config
 	// customConstraints is a list of external constraints
 	customConstraints []CustomConstraint
+	// Plugin fields
+	pluginFields pluginFields
+	// Indicates if the app rejects all requests with 503 Service Unavailable
+	maintenance atomic.Bool
+	// net/http server which serves HTTP/2 connections
+	http2Server *http.Server
+	// handlers of the protocols which are negotiated with ALPN
+	alpnHandlers map[string]ConnHandler
+	// Connection counters of the listeners
+	connStats connStats
+	// Request counters, see Stats
+	requestStats requestStats
+	// Error counters by category, see ErrorStats
+	errorStats errorStats
+	// Reporter of the errors which result in a 5xx response, nil if disabled
+	errorReporter *errorReporter
+	// Scheduler of the requests, nil if disabled
+	scheduler *scheduler
+	// Adaptive throttle of the requests, nil if disabled
+	throttle *throttle
+	// Middlewares of the route tags, see UseTag
+	tagMiddlewares []tagMiddleware
+	// Long-lived streams which are drained by the graceful shutdown, see TrackStream
+	streams streamTracker
+	// State of the warm-up hooks, see Ready
+	warmup warmupState
+	// Limits of the container, applied with ListenConfig.EnableContainerLimits
+	containerLimits ContainerLimits
+	// Keep-alive config which is used for new requests and connections
+	keepAlive atomic.Pointer[KeepAliveConfig]
+	// Indicates if the keep-alive config closes connections after requests, see closeConnectionIfNeeded
+	keepAlivePolicy atomic.Bool
+	// Settings which are reloaded by WatchConfig, see requestConfig
+	reloadable atomic.Pointer[reloadableConfig]
+	// Pool of the buffers which are used to encode responses
+	bufferPool *bufferPool
+	// Indicates if encoding/json is used, which can encode into pooled buffers
+	defaultJSONEncoder bool
+	// Cache of interned header names and values, nil if disabled
+	interner *interner
+	// Minimum level of the log entries of the framework
+	logLevel atomic.Int32
+	// Sampler of the log entries of the framework
+	logSampler *logSampler
+	// Watchdog of the requests which exceed the SlowRequestThreshold, nil if disabled
+	slowRequests *slowRequestWatchdog
+	// Recorder of the recent requests, nil if the FlightRecorderSize and the CrashDumpDir aren't set
+	flightRecorder *flightRecorder
+	// Tracker of the responses with OnCommitted hooks
+	commits commitTracker
+	// Indicates if the discovery endpoint responds, see EnableDiscovery
+	discoveryEnabled atomic.Bool
+	// Path of the discovery endpoint, which responds in maintenance mode
+	discoveryPath string
+	// Build info of the app, see BuildInfo
+	buildInfo atomic.Pointer[BuildInfo]
+	// Indicates if a route has a maximum response size
+	responseSizeLimits bool
+	// Indicates if a route has a default Cache-Control policy
+	cacheControlPolicies bool
+	// Indicates if the app or a route has a header policy
+	headerPolicies bool
+	// Indicates if the profile was selected with the config or the environment variable
+	profileSelected bool
+	// TCP and UDP listeners next to the HTTP server, see ListenTCP
+	companions companionListeners
+	// Violations of the TLS policy, reported in the startup message
+	tlsPolicyReport []string
+	// Parsed IP ranges of Config.StrictHeadersAllowlist
+	strictHeadersAllowlist []*net.IPNet
+	// Counters of the header violations of the strict header mode
+	headerViolations headerViolations
+	// Indicates if the request paths are normalized, see Config.PathNormalization
+	pathNormalization bool
 }
 
 // Config is a struct holding the server settings.
@@ -132,12 +214,27 @@ type Config struct {
 	// Default: ""
 	ServerHeader string `json:"server_header"`
 
+	// HeaderPolicy is applied to the headers of all responses before they're sent, before
+	// the policies of the routes, see App.HeaderPolicy.
+	//
+	// Optional. Default: HeaderPolicy{}
+	HeaderPolicy HeaderPolicy `json:"header_policy"`
+
 	// When set to true, the router treats "/foo" and "/foo/" as different.
 	// By default this is disabled and both "/foo" and "/foo/" will execute the same handler.
 	//
 	// Default: false
 	StrictRouting bool `json:"strict_routing"`
 
+	// When the number of routes of all methods exceeds this threshold, the router
+	// is compiled: the routes are divided by their static prefixes, which are
+	// matched with a radix tree, instead of the first three characters of the path.
+	// This reduces the number of routes which are traversed for apps with
+	// thousands of routes.
+	//
+	// Default: 0 (disabled)
+	RouterCompileThreshold int `json:"router_compile_threshold"`
+
 	// When set to true, enables case sensitive routing.
 	// E.g. "/FoO" and "/foo" are treated as different routes.
 	// By default this is disabled and both "/FoO" and "/foo" will execute the same handler.
@@ -153,6 +250,24 @@ type Config struct {
 	// Default: false
 	Immutable bool `json:"immutable"`
 
+	// When set to true, the values returned by the ctx (e.g. c.Params, c.Query,
+	// c.Get and c.Cookies) are copied into an arena of the request, so they stay
+	// valid after the handler returned. The arena is allocated in chunks of at
+	// least 4KB and dropped after the response was written. A chunk is freed by
+	// the GC when none of its values is referenced anymore.
+	//
+	// Default: false
+	ImmutableArena bool `json:"immutable_arena"`
+
+	// InternCacheSize is the maximum number of header names and values which are
+	// interned. Interned headers are returned by c.Get, c.GetRespHeader, c.GetReqHeaders
+	// and c.GetRespHeaders without an allocation or a reference to the request buffers.
+	// Headers longer than 128 bytes aren't interned. When the cache is full, new headers
+	// aren't added anymore.
+	//
+	// Default: 0 (disabled)
+	InternCacheSize int `json:"intern_cache_size"`
+
 	// When set to true, converts all encoded characters in the route back
 	// before setting the path for the context, so that the routing,
 	// the returning of the current url from the context `ctx.Path()`
@@ -206,6 +321,81 @@ type Config struct {
 	// Default: unlimited
 	IdleTimeout time.Duration `json:"idle_timeout"`
 
+	// RequestTimeout is the maximum duration of the handlers of a request. After it,
+	// the user context of the request is canceled with context.DeadlineExceeded, so
+	// database and HTTP calls which use it stop. It can be shortened per route with Timeout.
+	// Set to 0 to disable the timeout.
+	//
+	// Default: 0
+	RequestTimeout time.Duration `json:"request_timeout"`
+
+	// DisconnectCheckInterval is the interval in which the connection of a request is
+	// checked while the handlers are running. If the client closed the connection,
+	// the user context of the request is canceled with ErrClientDisconnected as cause.
+	// The check is only supported for TCP connections on unix systems.
+	// Set to 0 to disable the check.
+	//
+	// Default: 0
+	DisconnectCheckInterval time.Duration `json:"disconnect_check_interval"`
+
+	// CrashDumpDir is the directory of the crash reports, which are written when a panic of a
+	// handler crashes the process with PanicPolicyRepanic or the serving of a prefork child
+	// failed. A report contains the stacks of all goroutines, the config with redacted secrets
+	// and the recent requests.
+	//
+	// Default: ""
+	CrashDumpDir string `json:"crash_dump_dir"`
+
+	// CrashDumpRequests is the number of the recent requests of the flight recorder in the crash reports.
+	//
+	// Default: DefaultCrashDumpRequests
+	CrashDumpRequests int `json:"crash_dump_requests"`
+
+	// FlightRecorderSize is the number of the recent requests whose summaries are kept in memory
+	// for debugging, see RecentRequests. The flight recorder is also enabled by the CrashDumpDir.
+	//
+	// Default: 0
+	FlightRecorderSize int `json:"flight_recorder_size"`
+
+	// FlightRecorderSampling is the fraction of the successful requests which are recorded,
+	// between 0 and 1. The requests with errors and server errors are always recorded.
+	//
+	// Default: 1
+	FlightRecorderSampling float64 `json:"flight_recorder_sampling"`
+
+	// FlightRecorderRedact is called with each record of the flight recorder before it is
+	// stored, e.g. to remove personal data. The record is dropped if it returns false.
+	//
+	// Default: nil
+	FlightRecorderRedact func(record *RequestRecord) bool `json:"-"`
+
+	// Clock is the source of the time of the timeouts of the requests, the routes, the graceful
+	// shutdown and its hooks, the drain of the streams, the MaxConnAge of the keep-alive
+	// connections and the throttle, e.g. a ManualClock in tests.
+	//
+	// Default: SystemClock()
+	Clock Clock `json:"-"`
+
+	// Rand is the source of the random numbers of the app, e.g. of the sampling of the
+	// flight recorder, e.g. NewRand(1) in tests.
+	//
+	// Default: SystemRand()
+	Rand Rand `json:"-"`
+
+	// SlowRequestThreshold is the duration after which a request which is still
+	// handled is reported as slow with a warning and the OnSlowRequest hooks.
+	// The request isn't canceled. Set to 0 to disable the detection.
+	//
+	// Default: 0
+	SlowRequestThreshold time.Duration `json:"slow_request_threshold"`
+
+	// When set to true, the stack of the goroutine which handles a slow request
+	// is captured and passed to the OnSlowRequest hooks.
+	// Capturing the stack stops the world for a short time.
+	//
+	// Default: false
+	SlowRequestStack bool `json:"slow_request_stack"`
+
 	// Per-connection buffer size for requests' reading.
 	// This also limits the maximum header size.
 	// Increase this buffer if your clients send multi-KB RequestURIs
@@ -228,6 +418,7 @@ type Config struct {
 	// ProxyHeader will enable c.IP() to return the value of the given header key
 	// By default c.IP() will return the Remote IP from the TCP connection
 	// This property can be useful if you are behind a load balancer: X-Forwarded-*
+	// With HeaderForwarded, c.IP() returns the IP of the first "for" node of the Forwarded header (RFC 7239)
 	// NOTE: headers are easily spoofed and the detected IP addresses are unreliable.
 	//
 	// Default: ""
@@ -246,12 +437,99 @@ type Config struct {
 	// Default: DefaultErrorHandler
 	ErrorHandler ErrorHandler `json:"-"`
 
+	// PanicPolicy defines how panics of handlers are treated, if they are
+	// not recovered by a middleware. It can be overwritten per route.
+	//
+	// Default: PanicPolicyRepanic
+	PanicPolicy PanicPolicy `json:"panic_policy"`
+
+	// ErrorReporter receives the events of the errors which result in a 5xx response
+	// and of the panics of handlers, e.g. to send them to an APM service.
+	//
+	// Default: nil
+	ErrorReporter ErrorReporter `json:"-"`
+
+	// ErrorReportLimit is the maximum number of events which are passed to the
+	// ErrorReporter per second, further events are dropped.
+	// Set to -1 to disable the limit.
+	//
+	// Default: 10
+	ErrorReportLimit int `json:"error_report_limit"`
+
+	// ErrorReportScrubbedHeaders are the request headers whose values are replaced
+	// in the events of the ErrorReporter.
+	//
+	// Default: DefaultScrubbedHeaders
+	ErrorReportScrubbedHeaders []string `json:"error_report_scrubbed_headers"`
+
+	// ErrorReportScrubber is called with each event before it is passed to the
+	// ErrorReporter, e.g. to remove personal data. The event is dropped if it returns false.
+	//
+	// Default: nil
+	ErrorReportScrubber func(event *ErrorEvent) bool `json:"-"`
+
+	// Logger is used for the log entries of the framework, like failed hooks,
+	// shutdown errors and recovered panics. The framework never exits the process.
+	//
+	// Default: log.DefaultLogger()
+	Logger log.CommonLogger `json:"-"`
+
+	// ErrorLog is the writer of the log entries of the framework if Logger isn't set,
+	// e.g. a log.File which is rotated. Use it to separate them from the access log.
+	//
+	// Default: nil
+	ErrorLog io.Writer `json:"-"`
+
+	// AccessLog is the writer of the access log, it is the default output of the logger
+	// middleware, e.g. a log.File which is rotated.
+	//
+	// Default: nil
+	AccessLog io.Writer `json:"-"`
+
+	// LogLevel is the minimum level of the log entries of the framework.
+	// It can be changed at runtime with app.SetLogLevel.
+	//
+	// Default: log.LevelTrace
+	LogLevel log.Level `json:"log_level"`
+
+	// LogSampling is the maximum number of log entries with the same message
+	// which are written per second, further entries are dropped.
+	// Set to 0 to disable the sampling.
+	//
+	// Default: 0
+	LogSampling int `json:"log_sampling"`
+
 	// When set to true, disables keep-alive connections.
 	// The server will close incoming connections after sending the first response to client.
 	//
 	// Default: false
 	DisableKeepalive bool `json:"disable_keepalive"`
 
+	// Scheduler limits the number of concurrently handled requests and queues
+	// or sheds the further requests by the priority of their routes.
+	//
+	// Default: SchedulerConfig{}
+	Scheduler SchedulerConfig `json:"scheduler"`
+
+	// Throttle sheds a percentage of the low-priority requests while the error budget
+	// of the SLO burns too fast, and arrests spikes of the request rate.
+	//
+	// Default: ThrottleConfig{}
+	Throttle ThrottleConfig `json:"throttle"`
+
+	// KeepAlive configures the lifecycle of keep-alive connections.
+	// It can be changed at runtime with SetKeepAlive.
+	//
+	// Default: KeepAliveConfig{}
+	KeepAlive KeepAliveConfig `json:"keep_alive"`
+
+	// DrainTimeout is the maximum duration the graceful shutdown waits for the long-lived
+	// streams of TrackStream, e.g. WebSocket and SSE connections, to send their goodbye
+	// and end, before the server is shut down.
+	//
+	// Default: 10 * time.Second
+	DrainTimeout time.Duration `json:"drain_timeout"`
+
 	// When set to true, causes the default date header to be excluded from the response.
 	//
 	// Default: false
@@ -311,6 +589,20 @@ type Config struct {
 	// Default: json.Marshal
 	JSONEncoder utils.JSONMarshal `json:"-"`
 
+	// JSONEscapeHTML escapes <, >, & and the line terminators U+2028 and U+2029
+	// in the JSON responses of a custom JSONEncoder, so they are safe to embed
+	// into HTML, e.g. into a <script> element. The default encoder always escapes them.
+	//
+	// Default: false
+	JSONEscapeHTML bool `json:"json_escape_html"`
+
+	// ResponseBufferSizes are the size classes of the buffer pool which is used
+	// to encode responses with the default JSON encoder. Buffers are returned to
+	// the largest size class which fits into their capacity.
+	//
+	// Default: DefaultResponseBufferSizes
+	ResponseBufferSizes []int `json:"response_buffer_sizes"`
+
 	// When set by an external client of Fiber it will use the provided implementation of a
 	// JSONUnmarshal
 	//
@@ -383,6 +675,136 @@ type Config struct {
 	//
 	// Optional. Default: false
 	EnableSplittingOnParsers bool `json:"enable_splitting_on_parsers"`
+
+	// StatsTags are the tags of the routes, see App.Tag, whose requests, errors and latencies are
+	// counted separately in the Tags of App.Stats, e.g. to slice the metrics by business domain.
+	// It requires EnableRequestStats. New panics for more than 32 tags or a tag which isn't made of
+	// up to 64 letters, digits and "_:.-", so the tags are bounded and valid labels of metrics.
+	//
+	// Optional. Default: nil
+	StatsTags []string `json:"stats_tags"`
+
+	// EnableCommitHooks tracks the responses until they're written to the connection,
+	// to call the hooks of Ctx.OnCommitted. Without it, the hooks are discarded.
+	//
+	// Optional. Default: false
+	EnableCommitHooks bool `json:"enable_commit_hooks"`
+
+	// EnableRequestStats counts the requests in flight, the requests per second and the
+	// responses by status code, which are returned by App.Stats. The connection, byte and
+	// error counters don't need it.
+	//
+	// Optional. Default: false
+	EnableRequestStats bool `json:"enable_request_stats"`
+
+	// PaginationDefaultLimit is the limit of c.Pagination if the request has no limit.
+	//
+	// Optional. Default: DefaultPaginationLimit
+	PaginationDefaultLimit int `json:"pagination_default_limit"`
+
+	// PaginationMaxLimit caps the limit of c.Pagination, larger limits are reduced to it.
+	//
+	// Optional. Default: DefaultPaginationMaxLimit
+	PaginationMaxLimit int `json:"pagination_max_limit"`
+
+	// MaxResponseCookies is the maximum number of the cookies of a response which are set
+	// with c.SetCookies.
+	//
+	// Optional. Default: DefaultMaxResponseCookies
+	MaxResponseCookies int `json:"max_response_cookies"`
+
+	// MaxCookieSize is the maximum size in bytes of a cookie including its attributes
+	// which is set with c.SetCookies.
+	//
+	// Optional. Default: DefaultMaxCookieSize
+	MaxCookieSize int `json:"max_cookie_size"`
+
+	// RequirePreconditions makes c.CheckPreconditions return ErrPreconditionRequired (428)
+	// for PUT, PATCH and DELETE requests without If-Match or If-Unmodified-Since header.
+	//
+	// Optional. Default: false
+	RequirePreconditions bool `json:"require_preconditions"`
+
+	// RenderCache caches the pages rendered by c.Render for the templates with a rule
+	// and sends them with ETag and Last-Modified headers.
+	//
+	// Optional. Default: nil
+	RenderCache *RenderCache `json:"-"`
+
+	// VersionHeader sends the version of the build info of the app, see App.BuildInfo,
+	// in the X-App-Version header of every response.
+	//
+	// Optional. Default: false
+	VersionHeader bool `json:"version_header"`
+
+	// Profile is the environment the app runs in, which changes the defaults of the config
+	// and the listen config, see Profile. It is read from the FIBER_PROFILE environment
+	// variable if it is empty. The error page of the development profile is only shown
+	// if the profile is selected explicitly.
+	//
+	// Optional. Default: ProfileDevelopment
+	Profile Profile `json:"profile"`
+
+	// SecurityHeaders are sent with every response, handlers can overwrite them.
+	// An empty map disables the security headers of the staging and production profiles.
+	//
+	// Optional. Default: nil, DefaultSecurityHeaders in the staging and production profiles
+	SecurityHeaders map[string]string `json:"security_headers"`
+
+	// MaxResponseSize is the maximum size of the response bodies in bytes, which protects the app
+	// against accidentally huge responses, e.g. serializations of unbounded queries.
+	// The limit can be overwritten per route with MaxResponseSize. 0 disables the limit.
+	//
+	// Optional. Default: 0
+	MaxResponseSize int `json:"max_response_size"`
+
+	// ResponseSizePolicy is the behavior when a response exceeds the MaxResponseSize.
+	//
+	// Optional. Default: ResponseSizeError
+	ResponseSizePolicy ResponseSizePolicy `json:"response_size_policy"`
+
+	// When set to true, requests whose headers could be used for request smuggling or are
+	// malformed are rejected: both Content-Length and Transfer-Encoding, obsolete line folding,
+	// NUL bytes and more headers than MaxRequestHeaders. The violations are counted in HeaderViolations.
+	//
+	// Optional. Default: false
+	StrictHeaders bool `json:"strict_headers"`
+
+	// MaxRequestHeaders is the maximum number of request headers of the strict header mode.
+	//
+	// Optional. Default: 100
+	MaxRequestHeaders int `json:"max_request_headers"`
+
+	// StrictHeadersAllowlist contains the IPs and IP ranges of legacy clients, whose requests
+	// aren't rejected by the strict header mode. Their violations are still counted.
+	//
+	// Optional. Default: nil
+	StrictHeadersAllowlist []string `json:"strict_headers_allowlist"`
+
+	// PathNormalization configures the normalization of the request paths before the routing:
+	// percent-decoding, unicode normalization, duplicate slashes and dot segments.
+	// With PathNormalization.Strict, requests with suspicious encodings in the path are rejected.
+	//
+	// Optional. Default: PathNormalizationConfig{}
+	PathNormalization PathNormalizationConfig `json:"path_normalization"`
+
+	// PolicyDecider evaluates the permissions which are required by the routes, see Require.
+	//
+	// Optional. Default: DefaultPolicyDecider
+	PolicyDecider PolicyDecider `json:"-"`
+
+	// PolicyDeniedHandler renders the responses of the requests which were denied by the
+	// authorization policy, the *PolicyDenial is passed as error. Returned errors are passed
+	// to the ErrorHandler.
+	//
+	// Optional. Default: nil (the *PolicyDenial is passed to the ErrorHandler)
+	PolicyDeniedHandler ErrorHandler `json:"-"`
+
+	// PrincipalKey is the key of the authenticated principal in the locals,
+	// which is set by an authentication middleware.
+	//
+	// Optional. Default: DefaultPrincipalKey
+	PrincipalKey any `json:"-"`
 }
 
 // Static defines configuration options when defining static assets.
@@ -446,6 +868,13 @@ const (
 	DefaultReadBufferSize       = 4096
 	DefaultWriteBufferSize      = 4096
 	DefaultCompressedFileSuffix = ".fiber.gz"
+	DefaultErrorReportLimit     = 10
+	DefaultPaginationLimit      = 20
+	DefaultPaginationMaxLimit   = 100
+	DefaultMaxRequestHeaders    = 100
+	DefaultMaxResponseCookies   = 50
+	DefaultMaxCookieSize        = 4096
+	DefaultDrainTimeout         = 10 * time.Second
 )
 
 // HTTP methods enabled by default
@@ -468,8 +897,20 @@ func DefaultErrorHandler(c Ctx, err error) error {
 	if errors.As(err, &e) {
 		code = e.Code
 	}
+	message := err.Error()
+	switch c.App().Profile() {
+	case ProfileDevelopment:
+		if c.App().profileSelected && code >= StatusInternalServerError && strings.Contains(c.Get(HeaderAccept), MIMETextHTML) {
+			return developmentErrorPage(c, code, err)
+		}
+	case ProfileProduction:
+		// hide the details of internal errors
+		if e == nil {
+			message = utils.StatusMessage(code)
+		}
+	}
 	c.Set(HeaderContentType, MIMETextPlainCharsetUTF8)
-	return c.Status(code).SendString(err.Error())
+	return c.Status(code).SendString(message)
 }
 
 // New creates a new Fiber named instance.
@@ -492,6 +933,7 @@ func New(config ...Config) *App {
 		latestRoute:   &Route{},
 		customBinders: []CustomBinder{},
 	}
+	app.commits.app = app
 
 	// Create Ctx pool
 	app.pool = sync.Pool{
@@ -514,6 +956,9 @@ func New(config ...Config) *App {
 	// Initialize configured before defaults are set
 	app.configured = app.config
 
+	// Set the defaults of the profile
+	profileErr := app.applyProfile()
+
 	// Override default values
 	if app.config.BodyLimit == 0 {
 		app.config.BodyLimit = DefaultBodyLimit
@@ -530,6 +975,31 @@ func New(config ...Config) *App {
 	if app.config.CompressedFileSuffix == "" {
 		app.config.CompressedFileSuffix = DefaultCompressedFileSuffix
 	}
+	if app.config.PaginationMaxLimit <= 0 {
+		app.config.PaginationMaxLimit = DefaultPaginationMaxLimit
+	}
+	if app.config.PaginationDefaultLimit <= 0 {
+		app.config.PaginationDefaultLimit = min(DefaultPaginationLimit, app.config.PaginationMaxLimit)
+	}
+	app.headerPolicies = !app.config.HeaderPolicy.isZero()
+	if app.config.MaxResponseCookies <= 0 {
+		app.config.MaxResponseCookies = DefaultMaxResponseCookies
+	}
+	if app.config.MaxCookieSize <= 0 {
+		app.config.MaxCookieSize = DefaultMaxCookieSize
+	}
+	if app.config.PolicyDecider == nil {
+		app.config.PolicyDecider = DefaultPolicyDecider
+	}
+	if app.config.PrincipalKey == nil {
+		app.config.PrincipalKey = DefaultPrincipalKey
+	}
+	if app.config.MaxRequestHeaders <= 0 {
+		app.config.MaxRequestHeaders = DefaultMaxRequestHeaders
+	}
+	if app.config.DrainTimeout <= 0 {
+		app.config.DrainTimeout = DefaultDrainTimeout
+	}
 	if app.config.Immutable {
 		app.getBytes, app.getString = getBytesImmutable, getStringImmutable
 	}
@@ -538,8 +1008,68 @@ func New(config ...Config) *App {
 		app.config.ErrorHandler = DefaultErrorHandler
 	}
 
+	if app.config.Logger == nil && app.config.ErrorLog != nil {
+		app.config.Logger = log.New(app.config.ErrorLog)
+	}
+	app.logLevel.Store(int32(app.config.LogLevel))
+	app.logSampler = newLogSampler(app.config.LogSampling)
+	if profileErr != nil {
+		app.logw(log.LevelWarn, "ignoring the profile of the environment variable", "env", ProfileEnv, "error", profileErr)
+	}
+
+	app.SetKeepAlive(app.config.KeepAlive)
+	app.reloadable.Store(&reloadableConfig{
+		bodyLimit:    app.config.BodyLimit,
+		readTimeout:  app.config.ReadTimeout,
+		writeTimeout: app.config.WriteTimeout,
+	})
+
 	if app.config.JSONEncoder == nil {
 		app.config.JSONEncoder = json.Marshal
+		app.defaultJSONEncoder = true
+	}
+	if len(app.config.ResponseBufferSizes) == 0 {
+		app.config.ResponseBufferSizes = DefaultResponseBufferSizes
+	}
+	app.bufferPool = newBufferPool(app.config.ResponseBufferSizes)
+	if app.config.InternCacheSize > 0 {
+		app.interner = newInterner(app.config.InternCacheSize)
+	}
+	if app.config.Scheduler.MaxInFlight > 0 {
+		app.scheduler = newScheduler(app.config.Scheduler)
+	}
+	if app.config.Clock == nil {
+		app.config.Clock = SystemClock()
+	}
+	if app.config.Throttle.SLO > 0 || app.config.Throttle.MaxRate > 0 {
+		app.throttle = newThrottle(app.config.Throttle, app.config.Clock, app.logw)
+	}
+	if app.config.ErrorReporter != nil {
+		if app.config.ErrorReportLimit == 0 {
+			app.config.ErrorReportLimit = DefaultErrorReportLimit
+		}
+		if app.config.ErrorReportScrubbedHeaders == nil {
+			app.config.ErrorReportScrubbedHeaders = DefaultScrubbedHeaders
+		}
+		app.errorReporter = newErrorReporter(app.config)
+	}
+	if app.config.SlowRequestThreshold > 0 {
+		app.slowRequests = newSlowRequestWatchdog(app)
+	}
+	if app.config.EnableRequestStats {
+		app.requestStats.tags = newStatsTags(app.config.StatsTags)
+	}
+	if app.config.Rand == nil {
+		app.config.Rand = SystemRand()
+	}
+	if app.config.CrashDumpDir != "" && app.config.CrashDumpRequests <= 0 {
+		app.config.CrashDumpRequests = DefaultCrashDumpRequests
+	}
+	if app.config.FlightRecorderSampling <= 0 || app.config.FlightRecorderSampling > 1 {
+		app.config.FlightRecorderSampling = 1
+	}
+	if app.config.FlightRecorderSize > 0 || app.config.CrashDumpDir != "" {
+		app.flightRecorder = newFlightRecorder(app.config)
 	}
 	if app.config.JSONDecoder == nil {
 		app.config.JSONDecoder = json.Unmarshal
@@ -555,6 +1085,8 @@ func New(config ...Config) *App {
 	for _, ipAddress := range app.config.TrustedProxies {
 		app.handleTrustedProxy(ipAddress)
 	}
+	app.handleStrictHeadersAllowlist()
+	app.pathNormalization = app.config.PathNormalization.enabled()
 
 	// Create router stack
 	app.stack = make([][]*Route, len(app.config.RequestMethods))
@@ -575,7 +1107,7 @@ func (app *App) handleTrustedProxy(ipAddress string) {
 	if strings.Contains(ipAddress, "/") {
 		_, ipNet, err := net.ParseCIDR(ipAddress)
 		if err != nil {
-			log.Warnf("IP range %q could not be parsed: %v", ipAddress, err)
+			app.logw(log.LevelWarn, "IP range could not be parsed", "range", ipAddress, "error", err)
 		} else {
 			app.config.trustedProxyRanges = append(app.config.trustedProxyRanges, ipNet)
 		}
@@ -686,12 +1218,23 @@ func (app *App) GetRoutes(filterUseOption ...bool) []Route {
 //	 	subApp := fiber.New()
 //		app.Use("/mounted-path", subApp)
 //
+// Plugins can be registered the same way. They are registered immediately
+// and started/stopped together with the server.
+//
+//	app.Use(metricsPlugin)
+//
+// The handlers can be skipped for some of the requests with the conditions Skip and Only.
+//
+//	app.Use(fiber.Skip(skip.Paths("/health")), logger.New())
+//
 // This method will match all HTTP verbs: GET, POST, PUT, HEAD etc...
 func (app *App) Use(args ...any) Router {
 	var prefix string
 	var subApp *App
 	var prefixes []string
 	var handlers []Handler
+	var conditions []Condition
+	var plugins []Plugin
 
 	for i := 0; i < len(args); i++ {
 		switch arg := args[i].(type) {
@@ -703,11 +1246,24 @@ func (app *App) Use(args ...any) Router {
 			prefixes = arg
 		case Handler:
 			handlers = append(handlers, arg)
+		case Condition:
+			conditions = append(conditions, arg)
+		case Plugin:
+			plugins = append(plugins, arg)
 		default:
 			panic(fmt.Sprintf("use: invalid handler %v\n", reflect.TypeOf(arg)))
 		}
 	}
 
+	for _, plugin := range plugins {
+		app.registerPlugin(plugin)
+	}
+	if len(plugins) > 0 && len(handlers) == 0 && subApp == nil {
+		return app
+	}
+
+	handlers = applyConditions(handlers, conditions)
+
 	if len(prefixes) == 0 {
 		prefixes = append(prefixes, prefix)
 	}
@@ -838,9 +1394,37 @@ func NewError(code int, message ...string) *Error {
 	return err
 }
 
+// Clock returns the Clock of the config, e.g. for the timeouts of custom middlewares.
+func (app *App) Clock() Clock {
+	return app.config.Clock
+}
+
+// Rand returns the Rand of the config.
+func (app *App) Rand() Rand {
+	return app.config.Rand
+}
+
 // Config returns the app config as value ( read-only ).
 func (app *App) Config() Config {
-	return app.config
+	cfg := app.config
+	// the settings which can be changed at runtime are published separately
+	reloadable := app.reloadable.Load()
+	cfg.BodyLimit = reloadable.bodyLimit
+	cfg.ReadTimeout = reloadable.readTimeout
+	cfg.WriteTimeout = reloadable.writeTimeout
+	cfg.KeepAlive = *app.keepAlive.Load()
+	return cfg
+}
+
+// SetMaintenanceMode enables or disables the maintenance mode.
+// While enabled, every request is answered with ErrServiceUnavailable through the ErrorHandler.
+func (app *App) SetMaintenanceMode(enabled bool) {
+	app.maintenance.Store(enabled)
+}
+
+// MaintenanceMode returns true if the maintenance mode is enabled.
+func (app *App) MaintenanceMode() bool {
+	return app.maintenance.Load()
 }
 
 // Handler returns the server handler.
@@ -878,7 +1462,7 @@ func (app *App) Shutdown() error {
 //
 // ShutdownWithTimeout does not close keepalive connections so its recommended to set ReadTimeout to something else than 0.
 func (app *App) ShutdownWithTimeout(timeout time.Duration) error {
-	ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
+	ctx, cancelFunc := withTimeout(context.Background(), app.config.Clock, timeout)
 	defer cancelFunc()
 	return app.ShutdownWithContext(ctx)
 }
@@ -889,17 +1473,47 @@ func (app *App) ShutdownWithTimeout(timeout time.Duration) error {
 //
 // ShutdownWithContext does not close keepalive connections so its recommended to set ReadTimeout to something else than 0.
 func (app *App) ShutdownWithContext(ctx context.Context) error {
+	// Signal the long-lived streams to send their goodbye and wait for them to end
+	app.drainStreams(ctx)
+
 	if app.hooks != nil {
 		// TODO: check should be defered?
 		app.hooks.executeOnShutdownHooks()
 	}
 
+	// Stop the companion listeners from accepting, their connections are awaited with the server
+	app.companions.close()
+
 	app.mutex.Lock()
-	defer app.mutex.Unlock()
 	if app.server == nil {
+		app.mutex.Unlock()
 		return ErrNotRunning
 	}
-	return app.server.ShutdownWithContext(ctx)
+	err := app.server.ShutdownWithContext(ctx)
+	if app.http2Server != nil {
+		if http2Err := app.http2Server.Shutdown(ctx); http2Err != nil && err == nil {
+			err = http2Err
+		}
+	}
+	app.mutex.Unlock()
+	if companionErr := app.companions.wait(ctx); companionErr != nil && err == nil {
+		err = companionErr
+	}
+
+	// Execute the named shutdown hooks in dependency order after the server has been shut down
+	var hooksErr error
+	if app.hooks != nil {
+		hooksErr = app.hooks.executeOnShutdownNamedHooks(ctx)
+	}
+
+	// Stop plugins in reverse order after the server has been shut down
+	pluginErr := app.stopPlugins(ctx)
+
+	if hooksErr != nil || pluginErr != nil {
+		return errors.Join(err, hooksErr, pluginErr)
+	}
+
+	return err
 }
 
 // Server returns the underlying fasthttp server
@@ -998,7 +1612,7 @@ func (app *App) init() *App {
 	// Only load templates if a view engine is specified
 	if app.config.Views != nil {
 		if err := app.config.Views.Load(); err != nil {
-			log.Warnf("failed to load views: %v", err)
+			app.logw(log.LevelWarn, "failed to load views", "error", err)
 		}
 	}
 
@@ -1028,6 +1642,10 @@ func (app *App) init() *App {
 	app.server.ReduceMemoryUsage = app.config.ReduceMemoryUsage
 	app.server.StreamRequestBody = app.config.StreamRequestBody
 	app.server.DisablePreParseMultipartForm = app.config.DisablePreParseMultipartForm
+	if app.config.EnableCommitHooks {
+		app.server.ConnState = app.commits.connState
+	}
+	app.server.HeaderReceived = app.requestConfig
 
 	// unlock application
 	app.mutex.Unlock()
@@ -1040,6 +1658,11 @@ func (app *App) init() *App {
 // error handler. Otherwise it uses the configured error handler for
 // the app, which if not set is the DefaultErrorHandler.
 func (app *App) ErrorHandler(ctx Ctx, err error) error {
+	app.errorStats.countError(err)
+	if len(app.requestStats.tags) > 0 {
+		app.requestStats.countTagError(ctx)
+	}
+
 	var (
 		mountedErrHandler  ErrorHandler
 		mountedPrefixParts int
@@ -1058,11 +1681,15 @@ func (app *App) ErrorHandler(ctx Ctx, err error) error {
 		}
 	}
 
+	handler := app.config.ErrorHandler
 	if mountedErrHandler != nil {
-		return mountedErrHandler(ctx, err)
+		handler = mountedErrHandler
 	}
 
-	return app.config.ErrorHandler(ctx, err)
+	handlerErr := handler(ctx, err)
+	app.reportError(ctx, err, handlerErr)
+
+	return handlerErr
 }
 
 // serverErrorHandler is a wrapper around the application's error handler method
@@ -1096,7 +1723,7 @@ func (app *App) serverErrorHandler(fctx *fasthttp.RequestCtx, err error) {
 	}
 
 	if catch := app.ErrorHandler(c, err); catch != nil {
-		log.Errorf("serverErrorHandler: failed to call ErrorHandler: %v", catch)
+		app.logw(log.LevelError, "failed to call ErrorHandler", "error", catch)
 		_ = c.SendStatus(StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
 		return
 	}
@@ -2017,3 +2017,25 @@ func Benchmark_Ctx_AcquireReleaseFlow(b *testing.B) {
 		}
 	})
 }
+
+// go test -run Test_App_MaintenanceMode
+func Test_App_MaintenanceMode(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.SendString("ok")
+	})
+
+	app.SetMaintenanceMode(true)
+	require.True(t, app.MaintenanceMode())
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusServiceUnavailable, resp.StatusCode)
+
+	app.SetMaintenanceMode(false)
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
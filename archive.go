@@ -0,0 +1,396 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat is the format of the archives of SendArchive.
+type ArchiveFormat uint8
+
+const (
+	// ArchiveZip is a zip archive whose files are compressed with deflate.
+	ArchiveZip ArchiveFormat = iota
+	// ArchiveZipStore is a zip archive whose files are stored without compression, e.g. media
+	// which is compressed already. Its Content-Length is set if the sizes of all files are known.
+	ArchiveZipStore
+	// ArchiveTarGz is a gzip compressed tar archive.
+	ArchiveTarGz
+)
+
+// The content types of the archive formats.
+const (
+	mimeApplicationZip  = "application/zip"
+	mimeApplicationGzip = "application/gzip"
+)
+
+// The sizes of the records of a zip archive which are written by archive/zip, without zip64 extensions.
+const (
+	zipLocalHeaderSize     = 30
+	zipCentralHeaderSize   = 46
+	zipDataDescriptorSize  = 16
+	zipEndOfCentralDirSize = 22
+	// zipTimestampSize is the size of the extended timestamp of a modification time.
+	zipTimestampSize = 9
+	// zipMaxSize is the size from which archive/zip writes zip64 records.
+	zipMaxSize = 1<<32 - 1
+	// zipMaxFiles is the number of files from which archive/zip writes zip64 records.
+	zipMaxFiles = 1<<16 - 1
+)
+
+// ArchiveEntry is a file of an archive of SendArchive, its content is read from the Reader,
+// or the file or directory at the Path of the FS is added, directories with all their files.
+type ArchiveEntry struct {
+	// ModTime is the modification time of the Reader, the current time by default.
+	ModTime time.Time
+	// Reader is the content of the file. It's closed after it was read if it is an io.Closer.
+	Reader io.Reader
+	// FS is the file system of the Path.
+	FS fs.FS
+	// Name is the path of the file in the archive, e.g. "reports/2024.csv". For a directory of the FS,
+	// it's the directory which contains its files in the archive. Defaults to the Path.
+	Name string
+	// Path is the slash-separated path of the file or directory in the FS.
+	Path string
+	// Size is the size of the Reader. If it's 0, the size is detected with the Len, Size or Stat
+	// method of the Reader, otherwise it is unknown. The content of tar entries with an unknown
+	// size is buffered in memory, because tar headers contain the size.
+	Size int64
+	// Mode is the file mode of the Reader, 0o644 by default.
+	Mode fs.FileMode
+}
+
+// ArchiveEntryHandler is called by SendArchive after each file was written to the archive, with the
+// number of written bytes, or with the error which aborted the archive. It's called after the handler
+// returned, while the response is sent, so it must not access the Ctx.
+type ArchiveEntryHandler func(name string, written int64, err error)
+
+// archiveFile is a resolved file or directory of an archive.
+type archiveFile struct {
+	modTime time.Time
+	open    func() (io.Reader, error)
+	name    string
+	size    int64 // -1 if the size is unknown
+	mode    fs.FileMode
+	dir     bool
+}
+
+// SendArchive streams a zip or tar.gz archive of the entries, which is built while it's sent, without
+// temporary files. The Content-Length is set for ArchiveZipStore archives if the sizes of all files are
+// known, the other archives are sent chunked. The entries of an FS are resolved before SendArchive
+// returns, their files and the Readers are read after the handler returned. Errors while the archive
+// is written are logged, the connection is closed then, so the client doesn't receive a truncated
+// archive as complete. Set the file name of the download with Attachment.
+//
+//	c.Attachment("reports.zip")
+//	return c.SendArchive([]fiber.ArchiveEntry{
+//	    {Name: "summary.csv", Reader: bytes.NewReader(summary)},
+//	    {Name: "reports", FS: os.DirFS("/var/reports"), Path: "2024"},
+//	}, fiber.ArchiveZip)
+func (c *DefaultCtx) SendArchive(entries []ArchiveEntry, format ArchiveFormat, onEntry ...ArchiveEntryHandler) error {
+	files, err := resolveArchiveEntries(entries, time.Now())
+	if err != nil {
+		return err
+	}
+	var handler ArchiveEntryHandler
+	if len(onEntry) > 0 {
+		handler = onEntry[0]
+	}
+
+	size := int64(-1)
+	var write func(w io.Writer) error
+	switch format {
+	case ArchiveZip, ArchiveZipStore:
+		c.fasthttp.Response.Header.SetContentType(mimeApplicationZip)
+		method := zip.Deflate
+		if format == ArchiveZipStore {
+			method = zip.Store
+			size = zipStoreSize(files)
+		}
+		write = func(w io.Writer) error {
+			return writeZip(w, files, method, handler)
+		}
+	case ArchiveTarGz:
+		c.fasthttp.Response.Header.SetContentType(mimeApplicationGzip)
+		write = func(w io.Writer) error {
+			return writeTarGz(w, files, handler)
+		}
+	default:
+		return ErrArchiveFormat
+	}
+
+	c.setBodyWriter(size, "archive: failed to write the archive", write)
+	return nil
+}
+
+// resolveArchiveEntries returns the files of the entries, the directories of the file systems are walked.
+func resolveArchiveEntries(entries []ArchiveEntry, now time.Time) ([]archiveFile, error) {
+	files := make([]archiveFile, 0, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		name := entry.Name
+		if name == "" {
+			name = entry.Path
+		}
+		name = path.Clean("/" + name)[1:]
+
+		switch {
+		case entry.Reader != nil && name != "":
+			modTime, mode := entry.ModTime, entry.Mode
+			if modTime.IsZero() {
+				modTime = now
+			}
+			if mode == 0 {
+				mode = 0o644
+			}
+			reader := entry.Reader
+			files = append(files, archiveFile{
+				name:    name,
+				size:    readerSize(reader, entry.Size),
+				modTime: modTime,
+				mode:    mode,
+				open: func() (io.Reader, error) {
+					return reader, nil
+				},
+			})
+		case entry.FS != nil:
+			fsFiles, err := resolveArchiveFS(entry.FS, entry.Path, name)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, fsFiles...)
+		default:
+			return nil, ErrArchiveEntryInvalid
+		}
+	}
+	return files, nil
+}
+
+// resolveArchiveFS returns the file or the files of the directory at the path of the file system.
+// Other files than regular files and directories, e.g. symbolic links, are skipped.
+func resolveArchiveFS(fsys fs.FS, root, name string) ([]archiveFile, error) {
+	if root == "" {
+		root = "."
+	}
+	var files []archiveFile
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err //nolint:wrapcheck // the error is wrapped below
+		}
+
+		fileName := name
+		if p != root {
+			rel := p
+			if root != "." {
+				rel = strings.TrimPrefix(p, root+"/")
+			}
+			fileName = path.Join(name, rel)
+		}
+		if fileName == "" {
+			// the root directory of the archive has no entry
+			return nil
+		}
+
+		switch {
+		case d.IsDir():
+			files = append(files, archiveFile{name: fileName, modTime: info.ModTime(), mode: info.Mode(), dir: true})
+		case info.Mode().IsRegular():
+			filePath := p
+			files = append(files, archiveFile{
+				name:    fileName,
+				size:    info.Size(),
+				modTime: info.ModTime(),
+				mode:    info.Mode(),
+				open: func() (io.Reader, error) {
+					return fsys.Open(filePath)
+				},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("archive: failed to read %s: %w", root, err)
+	}
+	return files, nil
+}
+
+// readerSize returns the size of the reader, or -1 if it's unknown.
+func readerSize(r io.Reader, size int64) int64 {
+	if size > 0 {
+		return size
+	}
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case interface{ Size() int64 }:
+		return r.Size()
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if info, err := r.Stat(); err == nil && info.Mode().IsRegular() {
+			return info.Size()
+		}
+	}
+	return -1
+}
+
+// copyArchiveFile copies the content of the file, files with a known size are copied up to their size.
+func copyArchiveFile(w io.Writer, file *archiveFile, r io.Reader) (int64, error) {
+	if file.size < 0 {
+		return io.Copy(w, r) //nolint:wrapcheck // the error is wrapped by the caller
+	}
+	n, err := io.CopyN(w, r, file.size)
+	if err == io.EOF { //nolint:errorlint // io.CopyN returns io.EOF unwrapped
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err //nolint:wrapcheck // the error is wrapped by the caller
+}
+
+// openArchiveFile opens the file, the returned function closes it.
+func openArchiveFile(file *archiveFile) (io.Reader, func(), error) {
+	r, err := file.open()
+	if err != nil {
+		return nil, nil, fmt.Errorf("archive: failed to open %s: %w", file.name, err)
+	}
+	return r, func() {
+		if closer, ok := r.(io.Closer); ok {
+			_ = closer.Close() //nolint:errcheck // the file was read
+		}
+	}, nil
+}
+
+// writeZip writes the zip archive of the files.
+func writeZip(w io.Writer, files []archiveFile, method uint16, onEntry ArchiveEntryHandler) error {
+	zw := zip.NewWriter(w)
+	for i := range files {
+		file := &files[i]
+		header := &zip.FileHeader{Name: file.name, Modified: file.modTime, Method: method}
+		header.SetMode(file.mode)
+		if file.dir {
+			header.Name += "/"
+			if _, err := zw.CreateHeader(header); err != nil {
+				return fmt.Errorf("archive: failed to write %s: %w", file.name, err)
+			}
+			continue
+		}
+
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return fmt.Errorf("archive: failed to write %s: %w", file.name, err)
+		}
+		r, closeFile, err := openArchiveFile(file)
+		if err != nil {
+			return err
+		}
+		n, err := copyArchiveFile(fw, file, r)
+		closeFile()
+		if onEntry != nil {
+			onEntry(file.name, n, err)
+		}
+		if err != nil {
+			return fmt.Errorf("archive: failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("archive: failed to write the zip archive: %w", err)
+	}
+	return nil
+}
+
+// writeTarGz writes the gzip compressed tar archive of the files.
+func writeTarGz(w io.Writer, files []archiveFile, onEntry ArchiveEntryHandler) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for i := range files {
+		file := &files[i]
+		header := &tar.Header{Name: file.name, ModTime: file.modTime, Mode: int64(file.mode.Perm())}
+		if file.dir {
+			header.Name += "/"
+			header.Typeflag = tar.TypeDir
+			if err := tw.WriteHeader(header); err != nil {
+				return fmt.Errorf("archive: failed to write %s: %w", file.name, err)
+			}
+			continue
+		}
+
+		r, closeFile, err := openArchiveFile(file)
+		if err != nil {
+			return err
+		}
+		// the header contains the size, so a content of unknown size is buffered
+		if file.size < 0 {
+			var buf bytes.Buffer
+			_, err := buf.ReadFrom(r)
+			if err != nil {
+				closeFile()
+				return fmt.Errorf("archive: failed to read %s: %w", file.name, err)
+			}
+			r, file.size = &buf, int64(buf.Len())
+		}
+		header.Typeflag, header.Size = tar.TypeReg, file.size
+		if err := tw.WriteHeader(header); err != nil {
+			closeFile()
+			return fmt.Errorf("archive: failed to write %s: %w", file.name, err)
+		}
+		n, err := copyArchiveFile(tw, file, r)
+		closeFile()
+		if onEntry != nil {
+			onEntry(file.name, n, err)
+		}
+		if err != nil {
+			return fmt.Errorf("archive: failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("archive: failed to write the tar archive: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("archive: failed to write the tar archive: %w", err)
+	}
+	return nil
+}
+
+// zipStoreSize returns the size of the zip archive of the uncompressed files as written by
+// archive/zip, or -1 if a size is unknown or the archive needs zip64 records.
+func zipStoreSize(files []archiveFile) int64 {
+	if len(files) >= zipMaxFiles {
+		return -1
+	}
+	var size int64 = zipEndOfCentralDirSize
+	for i := range files {
+		file := &files[i]
+		// the name and the extra fields are written in the local and the central header
+		fields := int64(len(file.name))
+		if file.dir {
+			// the directories are written with a trailing slash and without data
+			fields++
+		} else {
+			if file.size < 0 {
+				return -1
+			}
+			size += file.size + zipDataDescriptorSize
+		}
+		if !file.modTime.IsZero() {
+			fields += zipTimestampSize
+		}
+		size += zipLocalHeaderSize + zipCentralHeaderSize + 2*fields
+		if size >= zipMaxSize {
+			return -1
+		}
+	}
+	return size
+}
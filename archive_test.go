@@ -0,0 +1,136 @@
+package fiber
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Ctx_SendArchive_Zip
+func Test_Ctx_SendArchive_Zip(t *testing.T) {
+	t.Parallel()
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	files := fstest.MapFS{
+		"reports/2024/q1.csv":     {Data: []byte("a,b\n1,2\n"), ModTime: modified},
+		"reports/2024/sub/q2.csv": {Data: []byte("c,d\n"), ModTime: modified},
+		"reports/readme.md":       {Data: []byte("# reports")},
+	}
+
+	for _, format := range []ArchiveFormat{ArchiveZip, ArchiveZipStore} {
+		var mu sync.Mutex
+		var written []string
+		app := New()
+		app.Get("/", func(c Ctx) error {
+			c.Attachment("reports.zip")
+			return c.SendArchive([]ArchiveEntry{
+				{Name: "summary.txt", Reader: strings.NewReader("summary"), ModTime: modified},
+				{Name: "reports", FS: files, Path: "reports/2024"},
+				{FS: files, Path: "reports/readme.md"},
+			}, format, func(name string, n int64, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				require.NoError(t, err)
+				written = append(written, name+":"+string(rune('0'+n)))
+			})
+		})
+
+		resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+		require.NoError(t, err)
+		require.Equal(t, StatusOK, resp.StatusCode)
+		require.Equal(t, mimeApplicationZip, resp.Header.Get(HeaderContentType))
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if format == ArchiveZipStore {
+			// the size of the archive is estimated exactly
+			require.Equal(t, int64(len(body)), resp.ContentLength)
+		} else {
+			require.Equal(t, int64(-1), resp.ContentLength)
+		}
+
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err)
+		names := make([]string, 0, len(archive.File))
+		for _, f := range archive.File {
+			names = append(names, f.Name)
+		}
+		require.Equal(t, []string{
+			"summary.txt", "reports/", "reports/q1.csv", "reports/sub/", "reports/sub/q2.csv", "reports/readme.md",
+		}, names)
+		f, err := archive.Open("reports/sub/q2.csv")
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.Equal(t, "c,d\n", string(content))
+		require.True(t, archive.File[0].Modified.Equal(modified))
+
+		mu.Lock()
+		require.Equal(t, []string{"summary.txt:7", "reports/q1.csv:8", "reports/sub/q2.csv:4", "reports/readme.md:9"}, written)
+		mu.Unlock()
+	}
+}
+
+// go test -run Test_Ctx_SendArchive_TarGz
+func Test_Ctx_SendArchive_TarGz(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.SendArchive([]ArchiveEntry{
+			// the size of the reader is unknown, so it's buffered
+			{Name: "/../notes.txt", Reader: io.MultiReader(strings.NewReader("a"), strings.NewReader("bc")), Mode: 0o600},
+			{Name: "data", FS: fstest.MapFS{"x.bin": {Data: []byte{1, 2}}}},
+		}, ArchiveTarGz)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, mimeApplicationGzip, resp.Header.Get(HeaderContentType))
+	gr, err := gzip.NewReader(resp.Body)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	var entries []string
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		entries = append(entries, header.Name+"="+string(content))
+		if header.Name == "notes.txt" {
+			require.Equal(t, int64(0o600), header.Mode)
+		}
+	}
+	require.Equal(t, []string{"notes.txt=abc", "data/=", "data/x.bin=\x01\x02"}, entries)
+}
+
+// go test -run Test_Ctx_SendArchive_Invalid
+func Test_Ctx_SendArchive_Invalid(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	require.ErrorIs(t, c.SendArchive([]ArchiveEntry{{Reader: strings.NewReader("a")}}, ArchiveZip), ErrArchiveEntryInvalid)
+	require.ErrorIs(t, c.SendArchive([]ArchiveEntry{{Name: "a"}}, ArchiveZip), ErrArchiveEntryInvalid)
+	require.ErrorIs(t, c.SendArchive(nil, ArchiveFormat(42)), ErrArchiveFormat)
+	require.Error(t, c.SendArchive([]ArchiveEntry{{FS: fstest.MapFS{}, Path: "missing"}}, ArchiveZip))
+
+	// the size is unknown if a reader has no size
+	files, err := resolveArchiveEntries([]ArchiveEntry{{Name: "a", Reader: io.MultiReader()}}, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), zipStoreSize(files))
+}
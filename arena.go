@@ -0,0 +1,48 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"github.com/gofiber/utils/v2"
+)
+
+// arenaChunkSize is the minimum size of the memory chunks of the ctx arena
+const arenaChunkSize = 4 * 1024
+
+// ctxArena copies the values of a request into chunks of memory, so they
+// stay valid after the handler returned. The chunks are never reused,
+// they are dropped when the ctx is released and freed by the GC as soon
+// as no value of the request is referenced anymore.
+type ctxArena struct {
+	buf []byte
+}
+
+// copyString copies b into the arena and returns it as string.
+func (a *ctxArena) copyString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	if cap(a.buf)-len(a.buf) < len(b) {
+		a.buf = make([]byte, 0, max(arenaChunkSize, len(b)))
+	}
+	start := len(a.buf)
+	a.buf = append(a.buf, b...)
+
+	return utils.UnsafeString(a.buf[start:len(a.buf):len(a.buf)])
+}
+
+// reset drops the current chunk, the values which were copied into it stay valid.
+func (a *ctxArena) reset() {
+	a.buf = nil
+}
+
+// getString converts the bytes of the request or response into a string.
+// With the ImmutableArena setting, the bytes are copied into the arena of the ctx.
+func (c *DefaultCtx) getString(b []byte) string {
+	if c.app.config.ImmutableArena {
+		return c.arena.copyString(b)
+	}
+	return c.app.getString(b)
+}
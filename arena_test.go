@@ -0,0 +1,64 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_CtxArena
+func Test_CtxArena(t *testing.T) {
+	t.Parallel()
+	var arena ctxArena
+
+	b := []byte("fiber")
+	s := arena.copyString(b)
+	b[0] = 'F'
+	require.Equal(t, "fiber", s)
+	require.Empty(t, arena.copyString(nil))
+
+	// values bigger than a chunk get their own chunk
+	big := make([]byte, arenaChunkSize+1)
+	require.Len(t, arena.copyString(big), arenaChunkSize+1)
+
+	arena.reset()
+	require.Nil(t, arena.buf)
+	require.Equal(t, "fiber", s)
+}
+
+// go test -run Test_App_ImmutableArena
+func Test_App_ImmutableArena(t *testing.T) {
+	t.Parallel()
+	app := New(Config{ImmutableArena: true})
+
+	var name, query, header, cookie string
+	app.Get("/:name", func(c Ctx) error {
+		name = c.Params("name")
+		query = c.Query("q")
+		header = c.Get("X-Test")
+		cookie = c.Cookies("session")
+		return nil
+	})
+
+	req := httptest.NewRequest(MethodGet, "/john?q=search", nil)
+	req.Header.Set("X-Test", "header")
+	req.Header.Set(HeaderCookie, "session=cookie")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+
+	require.Equal(t, "john", name)
+	require.Equal(t, "search", query)
+	require.Equal(t, "header", header)
+	require.Equal(t, "cookie", cookie)
+
+	// the value isn't changed when the buffer of the header is reused
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	c.Request().Header.Set("X-Test", "value")
+	value := c.Get("X-Test")
+	c.Request().Header.Set("X-Test", "other")
+	require.Equal(t, "value", value)
+}
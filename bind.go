@@ -50,8 +50,13 @@ func (b *Bind) returnErr(err error) error {
 	return err
 }
 
-// Struct validation.
+// Struct sanitization and validation.
+// The fields with a sanitize tag are sanitized before the struct is validated.
 func (b *Bind) validateStruct(out any) error {
+	if err := binder.Sanitize(out); err != nil {
+		return err
+	}
+
 	validator := b.ctx.app.config.StructValidator
 	if validator != nil {
 		return validator.ValidateStruct(out)
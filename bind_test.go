@@ -1583,6 +1583,48 @@ func Test_Bind_StructValidator(t *testing.T) {
 	require.NoError(t, c.Bind().Query(rq))
 }
 
+// go test -run Test_Bind_Sanitize
+func Test_Bind_Sanitize(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	type Author struct {
+		Bio string `json:"bio" sanitize:"ugc"`
+	}
+	type Comment struct {
+		Title  string   `json:"title" sanitize:"strict"`
+		Body   string   `json:"body" sanitize:"ugc"`
+		Tags   []string `json:"tags" sanitize:"strict"`
+		Raw    string   `json:"raw"`
+		Author *Author  `json:"author"`
+	}
+
+	c.Request().Header.SetContentType(MIMEApplicationJSON)
+	c.Request().SetBody([]byte(`{
+		"title": "<b>Hi</b> & bye",
+		"body": "<p onclick=\"x()\">Hello <script>alert(1)</script><a href=\"javascript:alert(1)\">link</a>",
+		"tags": ["<i>go</i>"],
+		"raw": "<b>raw</b>",
+		"author": {"bio": "<img src=x onerror=alert(1)>"}
+	}`))
+
+	comment := new(Comment)
+	require.NoError(t, c.Bind().Body(comment))
+	require.Equal(t, "Hi &amp; bye", comment.Title)
+	require.Equal(t, `<p>Hello <a rel="nofollow noopener">link</a></p>`, comment.Body)
+	require.Equal(t, []string{"go"}, comment.Tags)
+	require.Equal(t, "<b>raw</b>", comment.Raw)
+	require.Equal(t, `<img src="x">`, comment.Author.Bio)
+
+	type Unknown struct {
+		Body string `json:"body" sanitize:"unknown"`
+	}
+	c.Request().SetBody([]byte(`{"body": "body"}`))
+	require.ErrorIs(t, c.Bind().Body(new(Unknown)), binder.ErrSanitizePolicyNotFound)
+}
+
 // go test -run Test_Bind_RepeatParserWithSameStruct -v
 func Test_Bind_RepeatParserWithSameStruct(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,520 @@
+package binder
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrSanitizePolicyNotFound is returned by Sanitize if the policy of a sanitize tag isn't registered.
+var ErrSanitizePolicyNotFound = errors.New("binder: sanitize policy not found")
+
+// sanitizeTag is the struct tag which selects the policy of a field.
+const sanitizeTag = "sanitize"
+
+// SanitizePolicy defines the HTML elements and attributes which are kept by the sanitizer.
+// All other elements are removed, their text is kept and escaped, except the content of
+// elements like script and style, which is removed. Comments are removed.
+type SanitizePolicy struct {
+	// Elements maps the allowed elements to their allowed attributes.
+	// Event handler attributes (on*) and style are never kept.
+	Elements map[string][]string
+	// URLSchemes are the allowed schemes of the URLs of the href, src and cite attributes.
+	// Relative URLs are always allowed.
+	URLSchemes []string
+	// RelNoFollow adds rel="nofollow noopener" to the links.
+	RelNoFollow bool
+}
+
+var (
+	// StrictPolicy removes all HTML elements and escapes the text.
+	StrictPolicy = &SanitizePolicy{}
+
+	// UGCPolicy keeps the formatting elements of user generated content, like
+	// the rich-text fields of comments: paragraphs, emphasis, lists, quotes, code,
+	// links and images.
+	UGCPolicy = &SanitizePolicy{
+		Elements: map[string][]string{
+			"a":          {"href", "title"},
+			"b":          nil,
+			"blockquote": {"cite"},
+			"br":         nil,
+			"code":       nil,
+			"del":        nil,
+			"em":         nil,
+			"h1":         nil,
+			"h2":         nil,
+			"h3":         nil,
+			"h4":         nil,
+			"h5":         nil,
+			"h6":         nil,
+			"hr":         nil,
+			"i":          nil,
+			"img":        {"src", "alt", "title", "width", "height"},
+			"li":         nil,
+			"ol":         nil,
+			"p":          nil,
+			"pre":        nil,
+			"s":          nil,
+			"strong":     nil,
+			"sub":        nil,
+			"sup":        nil,
+			"u":          nil,
+			"ul":         nil,
+		},
+		URLSchemes:  []string{"http", "https", "mailto"},
+		RelNoFollow: true,
+	}
+)
+
+var (
+	sanitizePoliciesMutex sync.RWMutex
+	sanitizePolicies      = map[string]*SanitizePolicy{
+		"strict": StrictPolicy,
+		"ugc":    UGCPolicy,
+	}
+)
+
+// RegisterSanitizePolicy registers a policy for the sanitize struct tag.
+// The policies "strict" and "ugc" are built in.
+//
+//	binder.RegisterSanitizePolicy("comment", &binder.SanitizePolicy{
+//	    Elements: map[string][]string{"b": nil, "i": nil, "a": {"href"}},
+//	    URLSchemes: []string{"https"},
+//	})
+func RegisterSanitizePolicy(name string, policy *SanitizePolicy) {
+	sanitizePoliciesMutex.Lock()
+	sanitizePolicies[name] = policy
+	sanitizePoliciesMutex.Unlock()
+}
+
+// lookupSanitizePolicy returns the registered policy with the name.
+func lookupSanitizePolicy(name string) (*SanitizePolicy, error) {
+	sanitizePoliciesMutex.RLock()
+	policy, ok := sanitizePolicies[name]
+	sanitizePoliciesMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrSanitizePolicyNotFound, name)
+	}
+	return policy, nil
+}
+
+// Sanitize sanitizes the string fields of the struct which have a sanitize tag with
+// the policy of the tag, e.g. `sanitize:"ugc"`. Fields of the types string, *string
+// and []string are supported, nested structs are sanitized too.
+func Sanitize(out any) error {
+	v := reflect.ValueOf(out)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || !v.CanSet() {
+		return nil
+	}
+	return sanitizeStruct(v)
+}
+
+// sanitizeField is a field of a struct which is sanitized.
+type sanitizeField struct {
+	index  int
+	policy string // empty for nested structs
+}
+
+// sanitizeFieldsCache contains the sanitized fields by struct type.
+var sanitizeFieldsCache sync.Map
+
+// sanitizeFields returns the fields of the struct type which are sanitized.
+func sanitizeFields(t reflect.Type) []sanitizeField {
+	if cached, ok := sanitizeFieldsCache.Load(t); ok {
+		return cached.([]sanitizeField) //nolint:forcetypeassert // The cache only contains []sanitizeField
+	}
+	return computeSanitizeFields(t, make(map[reflect.Type]struct{}))
+}
+
+// computeSanitizeFields computes and caches the sanitized fields of the struct type.
+// Nested structs whose type is visited, e.g. of recursive types, are always sanitized.
+func computeSanitizeFields(t reflect.Type, visiting map[reflect.Type]struct{}) []sanitizeField {
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
+
+	var fields []sanitizeField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if policy, ok := field.Tag.Lookup(sanitizeTag); ok && policy != "" && policy != "-" {
+			fields = append(fields, sanitizeField{index: i, policy: policy})
+			continue
+		}
+		nested := structType(field.Type)
+		if nested == nil {
+			continue
+		}
+		if _, ok := visiting[nested]; ok {
+			fields = append(fields, sanitizeField{index: i})
+			continue
+		}
+		var nestedFields []sanitizeField
+		if cached, ok := sanitizeFieldsCache.Load(nested); ok {
+			nestedFields = cached.([]sanitizeField) //nolint:forcetypeassert // The cache only contains []sanitizeField
+		} else {
+			nestedFields = computeSanitizeFields(nested, visiting)
+		}
+		if len(nestedFields) > 0 {
+			fields = append(fields, sanitizeField{index: i})
+		}
+	}
+	sanitizeFieldsCache.Store(t, fields)
+	return fields
+}
+
+// structType returns the struct type of a struct, a pointer or a slice of structs, or nil.
+func structType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		return t
+	}
+	return nil
+}
+
+// sanitizeStruct sanitizes the fields of the struct value.
+func sanitizeStruct(v reflect.Value) error {
+	for _, field := range sanitizeFields(v.Type()) {
+		fv := v.Field(field.index)
+		if field.policy == "" {
+			if err := sanitizeNested(fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		policy, err := lookupSanitizePolicy(field.policy)
+		if err != nil {
+			return err
+		}
+		if err := sanitizeValue(fv, policy); err != nil {
+			return fmt.Errorf("binder: failed to sanitize field %s: %w", v.Type().Field(field.index).Name, err)
+		}
+	}
+	return nil
+}
+
+// sanitizeNested sanitizes a nested struct, a pointer or a slice of structs.
+func sanitizeNested(v reflect.Value) error {
+	switch v.Kind() { //nolint:exhaustive // Other kinds don't contain structs
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return sanitizeNested(v.Elem())
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := sanitizeNested(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return sanitizeStruct(v)
+	}
+	return nil
+}
+
+// sanitizeValue sanitizes a string, a pointer to a string or a slice of strings.
+func sanitizeValue(v reflect.Value, policy *SanitizePolicy) error {
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(policy.Sanitize(v.String()))
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.String:
+		if !v.IsNil() {
+			v.Elem().SetString(policy.Sanitize(v.Elem().String()))
+		}
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			v.Index(i).SetString(policy.Sanitize(v.Index(i).String()))
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// rawTextElements are the elements whose content is removed with the element.
+var rawTextElements = map[string]struct{}{
+	"script":   {},
+	"style":    {},
+	"iframe":   {},
+	"noscript": {},
+	"noembed":  {},
+	"noframes": {},
+	"template": {},
+	"textarea": {},
+	"title":    {},
+	"xmp":      {},
+}
+
+// voidElements are the elements without a closing tag.
+var voidElements = map[string]struct{}{
+	"br":  {},
+	"hr":  {},
+	"img": {},
+	"wbr": {},
+}
+
+// htmlAttr is an attribute of a tag.
+type htmlAttr struct {
+	name  string
+	value string
+}
+
+// htmlTag is a parsed start or end tag.
+type htmlTag struct {
+	name    string
+	attrs   []htmlAttr
+	closing bool
+}
+
+// Sanitize returns the HTML with the elements and attributes of the policy.
+// The text is escaped, the elements which aren't closed are closed at the end.
+func (p *SanitizePolicy) Sanitize(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	var open []string
+
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			writeText(&sb, s)
+			break
+		}
+		writeText(&sb, s[:lt])
+		s = s[lt:]
+
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			// comment
+			if end := strings.Index(s[4:], "-->"); end >= 0 {
+				s = s[4+end+3:]
+			} else {
+				s = ""
+			}
+		case len(s) > 1 && (s[1] == '!' || s[1] == '?'):
+			// doctype or processing instruction
+			if end := strings.IndexByte(s, '>'); end >= 0 {
+				s = s[end+1:]
+			} else {
+				s = ""
+			}
+		default:
+			tag, n := parseTag(s)
+			if n == 0 {
+				// not a tag, e.g. "a < b"
+				writeText(&sb, "<")
+				s = s[1:]
+				continue
+			}
+			s = s[n:]
+
+			if _, ok := rawTextElements[tag.name]; ok && !tag.closing {
+				s = skipRawText(s, tag.name)
+				continue
+			}
+			allowed, ok := p.Elements[tag.name]
+			if !ok {
+				continue
+			}
+
+			if tag.closing {
+				open = p.closeElement(&sb, open, tag.name)
+				continue
+			}
+			p.writeStartTag(&sb, tag, allowed)
+			if _, void := voidElements[tag.name]; !void {
+				open = append(open, tag.name)
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		sb.WriteString("</" + open[i] + ">")
+	}
+	return sb.String()
+}
+
+// writeText writes the escaped text, the entities of the text are normalized.
+func writeText(sb *strings.Builder, text string) {
+	if text != "" {
+		sb.WriteString(html.EscapeString(html.UnescapeString(text)))
+	}
+}
+
+// closeElement closes the element and the elements which were opened inside of it.
+// End tags of elements which aren't open are removed.
+func (*SanitizePolicy) closeElement(sb *strings.Builder, open []string, name string) []string {
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] != name {
+			continue
+		}
+		for j := len(open) - 1; j >= i; j-- {
+			sb.WriteString("</" + open[j] + ">")
+		}
+		return open[:i]
+	}
+	return open
+}
+
+// writeStartTag writes the start tag with the allowed attributes.
+func (p *SanitizePolicy) writeStartTag(sb *strings.Builder, tag htmlTag, allowed []string) {
+	sb.WriteString("<" + tag.name)
+	for _, attr := range tag.attrs {
+		if !p.allowAttr(attr, allowed) {
+			continue
+		}
+		sb.WriteString(" " + attr.name + `="` + html.EscapeString(attr.value) + `"`)
+	}
+	if p.RelNoFollow && tag.name == "a" {
+		sb.WriteString(` rel="nofollow noopener"`)
+	}
+	sb.WriteByte('>')
+}
+
+// allowAttr reports if the attribute is allowed and its URL has an allowed scheme.
+func (p *SanitizePolicy) allowAttr(attr htmlAttr, allowed []string) bool {
+	if strings.HasPrefix(attr.name, "on") || attr.name == "style" || (p.RelNoFollow && attr.name == "rel") {
+		return false
+	}
+	for _, name := range allowed {
+		if name != attr.name {
+			continue
+		}
+		switch attr.name {
+		case "href", "src", "cite":
+			return p.allowURL(attr.value)
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// allowURL reports if the URL is relative or has an allowed scheme.
+func (p *SanitizePolicy) allowURL(rawURL string) bool {
+	// browsers ignore whitespace and control characters in the scheme, e.g. "java\tscript:"
+	u := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, rawURL)
+
+	colon := strings.IndexByte(u, ':')
+	if colon < 0 || strings.ContainsAny(u[:colon], "/?#") {
+		return true
+	}
+	scheme := strings.ToLower(u[:colon])
+	for _, allowed := range p.URLSchemes {
+		if scheme == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// skipRawText skips the content of a raw text element and its end tag.
+func skipRawText(s, name string) string {
+	end := "</" + name
+	lower := strings.ToLower(s)
+	for offset := 0; ; {
+		i := strings.Index(lower[offset:], end)
+		if i < 0 {
+			return ""
+		}
+		i += offset + len(end)
+		if i == len(s) || s[i] == '>' || s[i] == '/' || isHTMLSpace(s[i]) {
+			if gt := strings.IndexByte(s[i:], '>'); gt >= 0 {
+				return s[i+gt+1:]
+			}
+			return ""
+		}
+		offset = i
+	}
+}
+
+// parseTag parses the start or end tag at the beginning of s. It returns the number
+// of consumed bytes, 0 if s doesn't start with a tag. An unterminated tag consumes s.
+func parseTag(s string) (htmlTag, int) {
+	var tag htmlTag
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		tag.closing = true
+		i++
+	}
+	if i >= len(s) || !isASCIILetter(s[i]) {
+		return tag, 0
+	}
+
+	start := i
+	for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '/' && s[i] != '>' {
+		i++
+	}
+	tag.name = strings.ToLower(s[start:i])
+
+	for {
+		for i < len(s) && (isHTMLSpace(s[i]) || s[i] == '/') {
+			i++
+		}
+		if i >= len(s) {
+			return htmlTag{}, len(s)
+		}
+		if s[i] == '>' {
+			return tag, i + 1
+		}
+
+		start = i
+		for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '/' && s[i] != '>' && (s[i] != '=' || i == start) {
+			i++
+		}
+		attr := htmlAttr{name: strings.ToLower(s[start:i])}
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isHTMLSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				end := strings.IndexByte(s[i+1:], s[i])
+				if end < 0 {
+					return htmlTag{}, len(s)
+				}
+				attr.value = html.UnescapeString(s[i+1 : i+1+end])
+				i += end + 2
+			} else {
+				start = i
+				for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				attr.value = html.UnescapeString(s[start:i])
+			}
+		}
+		if !tag.closing {
+			tag.attrs = append(tag.attrs, attr)
+		}
+	}
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isHTMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
@@ -0,0 +1,90 @@
+package binder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SanitizePolicy_Sanitize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy *SanitizePolicy
+		in     string
+		want   string
+	}{
+		{name: "text", policy: UGCPolicy, in: "a < b && c > d", want: "a &lt; b &amp;&amp; c &gt; d"},
+		{name: "entities", policy: UGCPolicy, in: "&lt;b&gt; &amp; &quot;", want: "&lt;b&gt; &amp; &#34;"},
+		{name: "strict", policy: StrictPolicy, in: "<p>Hello <b>World</b></p>", want: "Hello World"},
+		{name: "allowed", policy: UGCPolicy, in: "<p>Hello <B>World</B><br/></p>", want: "<p>Hello <b>World</b><br></p>"},
+		{name: "removed element", policy: UGCPolicy, in: "<div><span>text</span></div>", want: "text"},
+		{name: "raw text", policy: UGCPolicy, in: "a<script>alert('</b>')</script >b<STYLE>*{}</style>c", want: "abc"},
+		{name: "unterminated raw text", policy: UGCPolicy, in: "a<script>alert(1)", want: "a"},
+		{name: "comment", policy: UGCPolicy, in: "a<!-- <b>b</b> -->c<!DOCTYPE html>d", want: "acd"},
+		{name: "attributes", policy: UGCPolicy, in: `<img src="/a.png" alt='a "b"' onerror=alert(1) style="x" width=10>`, want: `<img src="/a.png" alt="a &#34;b&#34;" width="10">`},
+		{name: "link", policy: UGCPolicy, in: `<a href="https://example.com?a=1&amp;b=2" rel="x" title=t>x</a>`, want: `<a href="https://example.com?a=1&amp;b=2" title="t" rel="nofollow noopener">x</a>`},
+		{name: "javascript url", policy: UGCPolicy, in: `<a href=" JaVa&#x09;Script:alert(1)">x</a>`, want: `<a rel="nofollow noopener">x</a>`},
+		{name: "data url", policy: UGCPolicy, in: `<img src="data:image/png;base64,AA">`, want: `<img>`},
+		{name: "relative url", policy: UGCPolicy, in: `<a href="/path:with/colon">x</a>`, want: `<a href="/path:with/colon" rel="nofollow noopener">x</a>`},
+		{name: "unclosed", policy: UGCPolicy, in: "<ul><li><b>a", want: "<ul><li><b>a</b></li></ul>"},
+		{name: "misnested", policy: UGCPolicy, in: "<p><b>a</p>b</b>", want: "<p><b>a</b></p>b"},
+		{name: "unterminated tag", policy: UGCPolicy, in: `a<img src="x`, want: "a"},
+		{name: "not a tag", policy: UGCPolicy, in: "1 <2 and </ 3", want: "1 &lt;2 and &lt;/ 3"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, tt.policy.Sanitize(tt.in))
+		})
+	}
+}
+
+func Test_Sanitize(t *testing.T) {
+	t.Parallel()
+
+	RegisterSanitizePolicy("test-bold", &SanitizePolicy{Elements: map[string][]string{"b": nil}})
+
+	type node struct {
+		Text     string `sanitize:"test-bold"`
+		Children []*node
+	}
+	type form struct {
+		Title    *string `sanitize:"strict"`
+		Nil      *string `sanitize:"strict"`
+		Skipped  string  `sanitize:"-"`
+		Tree     node
+		unexport string
+	}
+
+	title := "<i>title</i>"
+	out := &form{
+		Title:   &title,
+		Skipped: "<i>skipped</i>",
+		Tree: node{
+			Text:     "<b>a</b><i>b</i>",
+			Children: []*node{{Text: "<p>c</p>"}, nil},
+		},
+		unexport: "<i>unexported</i>",
+	}
+	require.NoError(t, Sanitize(out))
+	require.Equal(t, "title", *out.Title)
+	require.Nil(t, out.Nil)
+	require.Equal(t, "<i>skipped</i>", out.Skipped)
+	require.Equal(t, "<b>a</b>b", out.Tree.Text)
+	require.Equal(t, "c", out.Tree.Children[0].Text)
+	require.Equal(t, "<i>unexported</i>", out.unexport)
+
+	type unsupported struct {
+		Count int `sanitize:"strict"`
+	}
+	require.ErrorContains(t, Sanitize(&unsupported{}), "unsupported type int")
+
+	// maps and values which aren't addressable are ignored
+	require.NoError(t, Sanitize(map[string]string{"a": "<b>"}))
+	require.NoError(t, Sanitize(node{}))
+	require.NoError(t, Sanitize((*node)(nil)))
+}
@@ -0,0 +1,123 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultResponseBufferSizes are the default size classes of the response buffer pool
+var DefaultResponseBufferSizes = []int{512, 4 * 1024, 32 * 1024, 256 * 1024}
+
+// zeroBuffer is used to grow the response body buffer
+var zeroBuffer [4 * 1024]byte
+
+// BufferPoolStats contains the counters of the response buffer pool.
+type BufferPoolStats struct {
+	// Hits is the number of buffers which were reused.
+	Hits uint64 `json:"hits"`
+	// Misses is the number of buffers which had to be allocated.
+	Misses uint64 `json:"misses"`
+}
+
+// bufferPool is a pool of buffers divided into size classes.
+// Buffers are returned to the largest size class which fits into their capacity,
+// so buffers which grew while they were used move to a larger class.
+type bufferPool struct {
+	sizes  []int
+	pools  []sync.Pool
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newBufferPool(sizes []int) *bufferPool {
+	sizes = append([]int(nil), sizes...)
+	sort.Ints(sizes)
+
+	return &bufferPool{
+		sizes: sizes,
+		pools: make([]sync.Pool, len(sizes)),
+	}
+}
+
+// get returns a buffer with a capacity of at least n bytes.
+func (p *bufferPool) get(n int) *bytes.Buffer {
+	for i, size := range p.sizes {
+		if size < n {
+			continue
+		}
+		if buf, ok := p.pools[i].Get().(*bytes.Buffer); ok {
+			p.hits.Add(1)
+			return buf
+		}
+		p.misses.Add(1)
+		return bytes.NewBuffer(make([]byte, 0, size))
+	}
+
+	// bigger than the largest size class
+	p.misses.Add(1)
+	return bytes.NewBuffer(make([]byte, 0, n))
+}
+
+// put returns the buffer to the pool, buffers smaller than the
+// smallest size class or twice as big as the largest size class are dropped.
+func (p *bufferPool) put(buf *bytes.Buffer) {
+	capacity := buf.Cap()
+	if len(p.sizes) == 0 || capacity > 2*p.sizes[len(p.sizes)-1] {
+		return
+	}
+
+	for i := len(p.sizes) - 1; i >= 0; i-- {
+		if p.sizes[i] <= capacity {
+			buf.Reset()
+			p.pools[i].Put(buf)
+			return
+		}
+	}
+}
+
+// BufferPoolStats returns the counters of the response buffer pool.
+func (app *App) BufferPoolStats() BufferPoolStats {
+	return BufferPoolStats{
+		Hits:   app.bufferPool.hits.Load(),
+		Misses: app.bufferPool.misses.Load(),
+	}
+}
+
+// encodeJSON encodes the data with the default JSON encoder into a pooled buffer
+// and copies it into the response body.
+func (c *DefaultCtx) encodeJSON(data any) error {
+	buf := c.app.bufferPool.get(c.responseSizeHint)
+	defer c.app.bufferPool.put(buf)
+
+	if err := json.NewEncoder(buf).Encode(data); err != nil {
+		return err //nolint:wrapcheck // This must not be wrapped
+	}
+	// json.Encoder appends a newline, json.Marshal doesn't
+	c.fasthttp.Response.SetBody(bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}))
+
+	return nil
+}
+
+// PreallocateResponse is a hint that the response body will have about n bytes.
+// The response body buffer is grown to n bytes, so it doesn't have to be grown
+// while the body is written. It has to be called before the body is written.
+func (c *DefaultCtx) PreallocateResponse(n int) {
+	c.responseSizeHint = n
+	if n <= 0 || len(c.fasthttp.Response.Body()) > 0 {
+		return
+	}
+
+	w := c.fasthttp.Response.BodyWriter()
+	for remaining := n; remaining > 0; remaining -= len(zeroBuffer) {
+		_, _ = w.Write(zeroBuffer[:min(remaining, len(zeroBuffer))]) //nolint:errcheck // Always return nil
+	}
+	// SetBody keeps the capacity of the body buffer, ResetBody may release it
+	c.fasthttp.Response.SetBody(nil)
+}
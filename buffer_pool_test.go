@@ -0,0 +1,63 @@
+package fiber
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_BufferPool
+func Test_BufferPool(t *testing.T) {
+	t.Parallel()
+	pool := newBufferPool([]int{1024, 64})
+
+	buf := pool.get(10)
+	require.Equal(t, 64, buf.Cap())
+	pool.put(buf)
+
+	buf = pool.get(100)
+	require.Equal(t, 1024, buf.Cap())
+	pool.put(buf)
+
+	buf = pool.get(4096)
+	require.Equal(t, 4096, buf.Cap())
+	pool.put(buf)
+
+	require.Equal(t, uint64(3), pool.misses.Load())
+	require.Equal(t, uint64(0), pool.hits.Load())
+}
+
+// go test -run Test_Ctx_JSON_BufferPool
+func Test_Ctx_JSON_BufferPool(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	data := Map{"name": "<fiber>", "list": []int{1, 2, 3}}
+	require.NoError(t, c.JSON(data))
+
+	expected, err := json.Marshal(data)
+	require.NoError(t, err)
+	require.Equal(t, expected, c.Response().Body())
+
+	require.Error(t, c.JSON(complex(1, 1)))
+
+	stats := app.BufferPoolStats()
+	require.Equal(t, uint64(2), stats.Hits+stats.Misses)
+}
+
+// go test -run Test_Ctx_PreallocateResponse
+func Test_Ctx_PreallocateResponse(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	c.PreallocateResponse(10000)
+	require.Empty(t, c.Response().Body())
+	require.GreaterOrEqual(t, cap(c.Response().Body()), 10000)
+
+	require.NoError(t, c.JSON(Map{"key": "value"}))
+	require.Equal(t, `{"key":"value"}`, string(c.Response().Body()))
+}
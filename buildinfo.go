@@ -0,0 +1,109 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// The build info of the binary, set with the linker flags of BuildLDFlags.
+var (
+	buildVersion string
+	buildCommit  string
+	buildDate    string
+)
+
+// BuildInfo is the build info of the binary, see ReadBuildInfo and App.BuildInfo.
+type BuildInfo struct {
+	Version   string `json:"version,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// BuildLDFlags returns the linker flags which inject the build info into the binary, e.g. in a build script:
+//
+//	go build -ldflags "$(go run ./cmd/ldflags)" .
+//
+//	// cmd/ldflags/main.go
+//	fmt.Print(fiber.BuildLDFlags(os.Getenv("VERSION"), os.Getenv("COMMIT"), time.Now().UTC().Format(time.RFC3339)))
+func BuildLDFlags(version, commit, date string) string {
+	const pkg = "github.com/gofiber/fiber/v3"
+	var flags []string
+	for _, v := range [][2]string{{"buildVersion", version}, {"buildCommit", commit}, {"buildDate", date}} {
+		if v[1] != "" {
+			flags = append(flags, "-X '"+pkg+"."+v[0]+"="+v[1]+"'")
+		}
+	}
+	return strings.Join(flags, " ")
+}
+
+// ReadBuildInfo returns the build info injected with BuildLDFlags. Missing values are read
+// from the build info of the Go toolchain, i.e. the version of the main module and the
+// VCS revision and time.
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   buildVersion,
+		Commit:    buildCommit,
+		Date:      buildDate,
+		GoVersion: runtime.Version(),
+	}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "" && build.Main.Version != "(devel)" {
+		info.Version = build.Main.Version
+	}
+	for _, setting := range build.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = setting.Value
+		case setting.Key == "vcs.time" && info.Date == "":
+			info.Date = setting.Value
+		}
+	}
+	return info
+}
+
+// BuildInfo sets the build info of the app, which is shown in the startup message, listed by the
+// discovery endpoint, sent by VersionHandler and in the X-App-Version header if Config.VersionHeader
+// is enabled. Empty values are taken from ReadBuildInfo, which is the default build info of the app.
+//
+//	app.BuildInfo(fiber.BuildInfo{Version: "1.4.0", Commit: commit, Date: date})
+func (app *App) BuildInfo(info BuildInfo) {
+	build := ReadBuildInfo()
+	if info.Version == "" {
+		info.Version = build.Version
+	}
+	if info.Commit == "" {
+		info.Commit = build.Commit
+	}
+	if info.Date == "" {
+		info.Date = build.Date
+	}
+	info.GoVersion = build.GoVersion
+	app.buildInfo.Store(&info)
+}
+
+// GetBuildInfo returns the build info of the app, see BuildInfo.
+func (app *App) GetBuildInfo() BuildInfo {
+	if info := app.buildInfo.Load(); info != nil {
+		return *info
+	}
+	info := ReadBuildInfo()
+	app.buildInfo.CompareAndSwap(nil, &info)
+	return *app.buildInfo.Load()
+}
+
+// VersionHandler responds with the build info of the app as JSON, see App.BuildInfo.
+//
+//	app.Get("/version", fiber.VersionHandler)
+//	{"version": "1.4.0", "commit": "4f2c9e1", "date": "2024-05-01T10:00:00Z", "go_version": "go1.22.2"}
+func VersionHandler(c Ctx) error {
+	return c.JSON(c.App().GetBuildInfo())
+}
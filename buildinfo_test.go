@@ -0,0 +1,67 @@
+package fiber
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_BuildLDFlags
+func Test_BuildLDFlags(t *testing.T) {
+	t.Parallel()
+	require.Equal(t,
+		"-X 'github.com/gofiber/fiber/v3.buildVersion=1.4.0' -X 'github.com/gofiber/fiber/v3.buildDate=2024-05-01T10:00:00Z'",
+		BuildLDFlags("1.4.0", "", "2024-05-01T10:00:00Z"))
+	require.Empty(t, BuildLDFlags("", "", ""))
+}
+
+// go test -run Test_App_BuildInfo
+func Test_App_BuildInfo(t *testing.T) {
+	t.Parallel()
+	app := New(Config{VersionHeader: true})
+	require.Equal(t, ReadBuildInfo(), app.GetBuildInfo())
+
+	app.BuildInfo(BuildInfo{Version: "1.4.0", Commit: "4f2c9e1", Date: "2024-05-01T10:00:00Z", GoVersion: "go1.0"})
+	require.Equal(t, BuildInfo{
+		Version:   "1.4.0",
+		Commit:    "4f2c9e1",
+		Date:      "2024-05-01T10:00:00Z",
+		GoVersion: runtime.Version(),
+	}, app.GetBuildInfo())
+
+	app.Get("/version", VersionHandler)
+	app.EnableDiscovery()
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/version", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, "1.4.0", resp.Header.Get(HeaderXAppVersion))
+	var build BuildInfo
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&build))
+	require.Equal(t, app.GetBuildInfo(), build)
+
+	// the version is sent with every response
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/missing", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusNotFound, resp.StatusCode)
+	require.Equal(t, "1.4.0", resp.Header.Get(HeaderXAppVersion))
+
+	_, discovery := testDiscovery(t, app, DefaultDiscoveryPath)
+	require.Equal(t, "1.4.0", discovery.Version)
+	require.Equal(t, app.GetBuildInfo(), discovery.Build)
+}
+
+// go test -run Test_App_BuildInfo_NoVersionHeader
+func Test_App_BuildInfo_NoVersionHeader(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.BuildInfo(BuildInfo{Version: "1.4.0"})
+	app.Get("/", testEmptyHandler)
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Empty(t, resp.Header.Get(HeaderXAppVersion))
+}
@@ -0,0 +1,96 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"github.com/gofiber/fiber/v3/cachecontrol"
+)
+
+// CacheControl sets the default Cache-Control policy of the latest registered route, which is
+// set on its successful responses unless the handler sets the Cache-Control header itself.
+// The CDN-Cache-Control and Surrogate-Control headers of the policy are set the same way.
+// If it is set for a middleware, e.g. of a group, it is used for all requests which are
+// handled by the middleware, unless the handler route has its own policy.
+//
+//	app.Get("/products", handler).CacheControl(cachecontrol.Public().MaxAge(time.Minute))
+func (app *App) CacheControl(policy cachecontrol.Policy) Router {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	app.cacheControlPolicies = true
+	for _, routes := range app.stack {
+		for _, route := range routes {
+			isMethodValid := route.Method == app.latestRoute.Method || app.latestRoute.use ||
+				(app.latestRoute.Method == MethodGet && route.Method == MethodHead)
+
+			// middlewares with the same path keep their policy
+			if route.Path == app.latestRoute.Path && route.use == app.latestRoute.use && isMethodValid {
+				route.cacheControl = &policy
+			}
+		}
+	}
+
+	return app
+}
+
+// CacheControl sets the default Cache-Control policy of the latest registered route.
+func (grp *Group) CacheControl(policy cachecontrol.Policy) Router {
+	grp.app.CacheControl(policy)
+
+	return grp
+}
+
+// cacheControlPolicy returns the policy of the last matching route with a policy, the
+// middlewares before the first matching handler are included.
+func (app *App) cacheControlPolicy(c CustomCtx) *cachecontrol.Policy {
+	tree, ok := c.getTreeStack()[c.getMethodINT()][c.getTreePath()]
+	if !ok {
+		tree = c.getTreeStack()[c.getMethodINT()][""]
+	}
+
+	var policy *cachecontrol.Policy
+	for _, route := range tree {
+		if !route.match(c.getDetectionPath(), c.Path(), c.getValues()) {
+			continue
+		}
+		if route.cacheControl != nil {
+			policy = route.cacheControl
+		}
+		if !route.use {
+			break
+		}
+	}
+
+	return policy
+}
+
+// setCacheControl sets the headers of the Cache-Control policy of the route, the headers
+// which are set by the handlers and the responses of errors are kept.
+func (app *App) setCacheControl(c CustomCtx) {
+	if c.Response().StatusCode() >= StatusBadRequest || c.Hijacked() {
+		return
+	}
+	policy := app.cacheControlPolicy(c)
+	if policy == nil {
+		return
+	}
+
+	header := &c.Response().Header
+	if len(header.Peek(HeaderCacheControl)) == 0 {
+		if value := policy.String(); value != "" {
+			header.Set(HeaderCacheControl, value)
+		}
+	}
+	if len(header.Peek(HeaderCDNCacheControl)) == 0 {
+		if value := policy.CDNCacheControl(); value != "" {
+			header.Set(HeaderCDNCacheControl, value)
+		}
+	}
+	if len(header.Peek(HeaderSurrogateControl)) == 0 {
+		if value := policy.SurrogateControl(); value != "" {
+			header.Set(HeaderSurrogateControl, value)
+		}
+	}
+}
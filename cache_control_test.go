@@ -0,0 +1,63 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3/cachecontrol"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_Route_CacheControl
+func Test_Route_CacheControl(t *testing.T) {
+	t.Parallel()
+	app := New()
+	api := app.Group("/api", func(c Ctx) error {
+		return c.Next()
+	}).CacheControl(cachecontrol.Private().NoCache())
+	api.Get("/users", func(c Ctx) error {
+		return c.SendString("users")
+	})
+	api.Get("/products", func(c Ctx) error {
+		return c.SendString("products")
+	}).CacheControl(cachecontrol.Public().MaxAge(time.Minute).StaleWhileRevalidate(time.Hour).
+		CDN(cachecontrol.Public().MaxAge(time.Hour)).Surrogate(cachecontrol.MaxAge(24 * time.Hour)))
+	api.Get("/override", func(c Ctx) error {
+		c.Set(HeaderCacheControl, "no-store")
+		return c.SendString("override")
+	})
+	api.Get("/error", func(_ Ctx) error {
+		return ErrBadRequest
+	})
+	app.Get("/public", func(c Ctx) error {
+		return c.SendString("public")
+	})
+
+	testCases := []struct {
+		target       string
+		cacheControl string
+		cdn          string
+		surrogate    string
+	}{
+		{target: "/api/users", cacheControl: "private, no-cache"},
+		{
+			target:       "/api/products",
+			cacheControl: "public, max-age=60, stale-while-revalidate=3600",
+			cdn:          "public, max-age=3600",
+			surrogate:    "max-age=86400",
+		},
+		{target: "/api/override", cacheControl: "no-store"},
+		{target: "/api/error"},
+		{target: "/public"},
+		{target: "/missing"},
+	}
+
+	for _, tc := range testCases {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, tc.target, nil))
+		require.NoError(t, err)
+		require.Equal(t, tc.cacheControl, resp.Header.Get(HeaderCacheControl), tc.target)
+		require.Equal(t, tc.cdn, resp.Header.Get(HeaderCDNCacheControl), tc.target)
+		require.Equal(t, tc.surrogate, resp.Header.Get(HeaderSurrogateControl), tc.target)
+	}
+}
@@ -0,0 +1,227 @@
+// Package cachecontrol builds the values of the Cache-Control header (RFC 9111), and of the
+// CDN-Cache-Control (RFC 9213) and Surrogate-Control headers of the CDNs.
+//
+//	policy := cachecontrol.Public().MaxAge(time.Minute).StaleWhileRevalidate(time.Hour)
+//	policy.String() // "public, max-age=60, stale-while-revalidate=3600"
+//
+// The policies are values, every method returns a copy, so a shared policy can be
+// extended without changing it.
+package cachecontrol
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// directive is a flag of the directives without a value.
+type directive uint16
+
+const (
+	public directive = 1 << iota
+	private
+	noCache
+	noStore
+	mustRevalidate
+	proxyRevalidate
+	immutable
+	noTransform
+
+	// the duration directives which are set, a duration of 0 is a valid value
+	maxAge
+	sMaxAge
+	staleWhileRevalidate
+	staleIfError
+)
+
+// Policy is a Cache-Control policy. The zero value has no directives.
+type Policy struct {
+	cdn                  *Policy
+	surrogate            *Policy
+	maxAge               time.Duration
+	sMaxAge              time.Duration
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+	directives           directive
+}
+
+// Public returns a policy with the public directive, the response may be stored by shared caches.
+func Public() Policy {
+	return Policy{}.Public()
+}
+
+// Private returns a policy with the private directive, the response may only be stored by the browser.
+func Private() Policy {
+	return Policy{}.Private()
+}
+
+// NoCache returns a policy with the no-cache directive, the response must be revalidated before it is used.
+func NoCache() Policy {
+	return Policy{}.NoCache()
+}
+
+// NoStore returns a policy with the no-store directive, the response must not be stored.
+func NoStore() Policy {
+	return Policy{}.NoStore()
+}
+
+// MaxAge returns a policy with the max-age directive.
+func MaxAge(d time.Duration) Policy {
+	return Policy{}.MaxAge(d)
+}
+
+// Public adds the public directive and removes the private directive.
+func (p Policy) Public() Policy {
+	p.directives = p.directives&^private | public
+	return p
+}
+
+// Private adds the private directive and removes the public directive.
+func (p Policy) Private() Policy {
+	p.directives = p.directives&^public | private
+	return p
+}
+
+// NoCache adds the no-cache directive.
+func (p Policy) NoCache() Policy {
+	p.directives |= noCache
+	return p
+}
+
+// NoStore adds the no-store directive.
+func (p Policy) NoStore() Policy {
+	p.directives |= noStore
+	return p
+}
+
+// MustRevalidate adds the must-revalidate directive, stale responses must not be used without revalidation.
+func (p Policy) MustRevalidate() Policy {
+	p.directives |= mustRevalidate
+	return p
+}
+
+// ProxyRevalidate adds the proxy-revalidate directive, which is must-revalidate for shared caches.
+func (p Policy) ProxyRevalidate() Policy {
+	p.directives |= proxyRevalidate
+	return p
+}
+
+// Immutable adds the immutable directive, the response doesn't change while it is fresh.
+func (p Policy) Immutable() Policy {
+	p.directives |= immutable
+	return p
+}
+
+// NoTransform adds the no-transform directive, intermediaries must not transform the response.
+func (p Policy) NoTransform() Policy {
+	p.directives |= noTransform
+	return p
+}
+
+// MaxAge sets the max-age directive, the duration for which the response is fresh.
+func (p Policy) MaxAge(d time.Duration) Policy {
+	p.maxAge = d
+	p.directives |= maxAge
+	return p
+}
+
+// SMaxAge sets the s-maxage directive, which overrides max-age for shared caches.
+func (p Policy) SMaxAge(d time.Duration) Policy {
+	p.sMaxAge = d
+	p.directives |= sMaxAge
+	return p
+}
+
+// StaleWhileRevalidate sets the stale-while-revalidate directive (RFC 5861), the duration for
+// which a stale response may be used while it is revalidated in the background.
+func (p Policy) StaleWhileRevalidate(d time.Duration) Policy {
+	p.staleWhileRevalidate = d
+	p.directives |= staleWhileRevalidate
+	return p
+}
+
+// StaleIfError sets the stale-if-error directive (RFC 5861), the duration for which a stale
+// response may be used if the revalidation fails.
+func (p Policy) StaleIfError(d time.Duration) Policy {
+	p.staleIfError = d
+	p.directives |= staleIfError
+	return p
+}
+
+// CDN sets the policy of the CDN-Cache-Control header, which is used by the CDNs instead of Cache-Control.
+func (p Policy) CDN(policy Policy) Policy {
+	p.cdn = &policy
+	return p
+}
+
+// Surrogate sets the policy of the Surrogate-Control header, which is used by the reverse proxies
+// and is removed before the response is sent to the client.
+func (p Policy) Surrogate(policy Policy) Policy {
+	p.surrogate = &policy
+	return p
+}
+
+// IsZero reports whether the policy has no directives.
+func (p Policy) IsZero() bool {
+	return p.directives == 0
+}
+
+// String returns the value of the Cache-Control header, e.g. "public, max-age=60".
+func (p Policy) String() string {
+	var sb strings.Builder
+	flag := func(d directive, name string) {
+		if p.directives&d == 0 {
+			return
+		}
+		if sb.Len() > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(name)
+	}
+	duration := func(d directive, name string, value time.Duration) {
+		if p.directives&d == 0 {
+			return
+		}
+		flag(d, name)
+		sb.WriteByte('=')
+		sb.WriteString(strconv.FormatInt(seconds(value), 10))
+	}
+
+	flag(public, "public")
+	flag(private, "private")
+	flag(noCache, "no-cache")
+	flag(noStore, "no-store")
+	duration(maxAge, "max-age", p.maxAge)
+	duration(sMaxAge, "s-maxage", p.sMaxAge)
+	duration(staleWhileRevalidate, "stale-while-revalidate", p.staleWhileRevalidate)
+	duration(staleIfError, "stale-if-error", p.staleIfError)
+	flag(mustRevalidate, "must-revalidate")
+	flag(proxyRevalidate, "proxy-revalidate")
+	flag(immutable, "immutable")
+	flag(noTransform, "no-transform")
+	return sb.String()
+}
+
+// CDNCacheControl returns the value of the CDN-Cache-Control header, or "" if it isn't set.
+func (p Policy) CDNCacheControl() string {
+	if p.cdn == nil {
+		return ""
+	}
+	return p.cdn.String()
+}
+
+// SurrogateControl returns the value of the Surrogate-Control header, or "" if it isn't set.
+func (p Policy) SurrogateControl() string {
+	if p.surrogate == nil {
+		return ""
+	}
+	return p.surrogate.String()
+}
+
+// seconds returns the duration in whole seconds, negative durations are 0.
+func seconds(d time.Duration) int64 {
+	if d < 0 {
+		return 0
+	}
+	return int64(d / time.Second)
+}
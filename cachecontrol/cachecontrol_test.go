@@ -0,0 +1,56 @@
+package cachecontrol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_Policy_String
+func Test_Policy_String(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		policy   Policy
+		expected string
+	}{
+		{policy: Policy{}, expected: ""},
+		{policy: Public().MaxAge(time.Minute), expected: "public, max-age=60"},
+		{policy: Private().MaxAge(0), expected: "private, max-age=0"},
+		{policy: NoStore(), expected: "no-store"},
+		{policy: NoCache().Private(), expected: "private, no-cache"},
+		{policy: Public().Private(), expected: "private"},
+		{
+			policy:   MaxAge(time.Minute).Public().SMaxAge(time.Hour).StaleWhileRevalidate(30 * time.Second).StaleIfError(24 * time.Hour),
+			expected: "public, max-age=60, s-maxage=3600, stale-while-revalidate=30, stale-if-error=86400",
+		},
+		{
+			policy:   Public().MaxAge(365 * 24 * time.Hour).Immutable().NoTransform(),
+			expected: "public, max-age=31536000, immutable, no-transform",
+		},
+		{policy: Public().MaxAge(1500 * time.Millisecond).MustRevalidate().ProxyRevalidate(), expected: "public, max-age=1, must-revalidate, proxy-revalidate"},
+		{policy: MaxAge(-time.Second), expected: "max-age=0"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, tc.policy.String())
+		require.Equal(t, tc.expected == "", tc.policy.IsZero())
+	}
+}
+
+// go test -run Test_Policy_CDN
+func Test_Policy_CDN(t *testing.T) {
+	t.Parallel()
+	base := Public().MaxAge(time.Minute)
+	policy := base.CDN(Public().MaxAge(time.Hour)).Surrogate(MaxAge(24 * time.Hour))
+
+	require.Equal(t, "public, max-age=60", policy.String())
+	require.Equal(t, "public, max-age=3600", policy.CDNCacheControl())
+	require.Equal(t, "max-age=86400", policy.SurrogateControl())
+
+	// the base policy isn't changed
+	require.Empty(t, base.CDNCacheControl())
+	require.Empty(t, base.SurrogateControl())
+	require.Equal(t, "public, max-age=60, immutable", base.Immutable().String())
+	require.Equal(t, "public, max-age=60", base.String())
+}
@@ -0,0 +1,167 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/utils/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// maxSiteFrames is the maximum number of the frames which are inspected for the registration site.
+const maxSiteFrames = 32
+
+// fiberDir is the directory of the source files of this package, whose frames aren't registration sites.
+var fiberDir = func() string {
+	_, file, _, _ := runtime.Caller(0) //nolint:dogsled // only the file is needed
+	return filepath.Dir(file)
+}()
+
+// ChainHandler is a handler of the chain of a request, see MiddlewareChain.
+type ChainHandler struct {
+	// Name is the name of the handler func, e.g. "github.com/gofiber/fiber/v3/middleware/logger.New.func1".
+	Name string `json:"name"`
+	// Site is the file and the line where the handler was registered, e.g. "/app/main.go:42".
+	Site string `json:"site"`
+	// Method is the method of the route of the handler.
+	Method string `json:"method"`
+	// Path is the registered path of the route of the handler.
+	Path string `json:"path"`
+	// Route is the name of the route of the handler.
+	Route string `json:"route,omitempty"`
+	// Use is true for the handlers which were registered with Use.
+	Use bool `json:"use"`
+}
+
+// DryRun is the result of the routing of a request without executing its handlers, see DryRun.
+type DryRun struct {
+	// Chain contains the handlers which the request traverses, in order.
+	Chain []ChainHandler `json:"chain"`
+	// Params contains the params of the route which handles the request.
+	Params map[string]string `json:"params,omitempty"`
+	// Status is StatusOK if a route handles the request, otherwise StatusNotFound,
+	// StatusMethodNotAllowed or StatusNotImplemented for unknown methods.
+	Status int `json:"status"`
+}
+
+// MiddlewareChain returns the handlers which a request with the method and the path traverses,
+// in order, with their names and registration sites, e.g. to find out why a middleware didn't run.
+// The chain ends with the handlers of the first route which isn't a middleware of Use, the routes
+// after it are only executed if its handlers call Next. See DryRun for the details of the routing.
+//
+//	for _, handler := range app.MiddlewareChain(fiber.MethodGet, "/users/1") {
+//	    fmt.Println(handler.Site, handler.Name)
+//	}
+func (app *App) MiddlewareChain(method, path string) []ChainHandler {
+	return app.DryRun(method, path).Chain
+}
+
+// DryRun routes a request with the method and the path like a request of a client, with the
+// settings of the router like CaseSensitive and StrictRouting, but doesn't execute the handlers.
+// The Next funcs of the middlewares aren't executed either, so a handler of the chain can still
+// skip itself for the request.
+func (app *App) DryRun(method, path string) DryRun {
+	app.startupProcess()
+
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.SetMethod(method)
+	fctx.Request.SetRequestURI(path)
+	c, ok := app.AcquireCtx(fctx).(CustomCtx)
+	if !ok {
+		panic(errors.New("DryRun: failed to type-assert to CustomCtx"))
+	}
+	defer app.ReleaseCtx(c)
+
+	result := DryRun{Chain: []ChainHandler{}, Status: StatusNotFound}
+	if c.getMethodINT() == -1 {
+		result.Status = StatusNotImplemented
+		return result
+	}
+
+	tree, ok := c.getTreeStack()[c.getMethodINT()][c.getTreePath()]
+	if !ok {
+		tree = c.getTreeStack()[c.getMethodINT()][""]
+	}
+	for _, route := range tree {
+		if route.mount || !route.match(c.getDetectionPath(), c.Path(), c.getValues()) {
+			continue
+		}
+
+		for i, handler := range route.Handlers {
+			result.Chain = append(result.Chain, ChainHandler{
+				Name:   handlerName(handler),
+				Site:   route.site(i),
+				Method: route.Method,
+				Path:   route.Path,
+				Route:  route.Name,
+				Use:    route.use,
+			})
+		}
+		if route.use {
+			continue
+		}
+
+		result.Status = StatusOK
+		if len(route.Params) > 0 {
+			result.Params = make(map[string]string, len(route.Params))
+			for i, param := range route.Params {
+				result.Params[param] = utils.CopyString(c.getValues()[i])
+			}
+		}
+		return result
+	}
+
+	if app.methodExistCustom(c) {
+		result.Status = StatusMethodNotAllowed
+	}
+	return result
+}
+
+// site returns the registration site of the handler with the index, empty if it is unknown.
+func (r *Route) site(index int) string {
+	if index < len(r.sites) {
+		return r.sites[index]
+	}
+	return ""
+}
+
+// handlerName returns the name of the func of the handler.
+func handlerName(handler Handler) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+// registrationSites returns the registration site of the handlers for each handler,
+// the first caller outside of this package.
+func registrationSites(handlers int) []string {
+	var pcs [maxSiteFrames]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+
+	site := ""
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != fiberDir || strings.HasSuffix(frame.File, "_test.go") {
+			site = frame.File + ":" + strconv.Itoa(frame.Line)
+			break
+		}
+		if !more {
+			break
+		}
+	}
+
+	sites := make([]string, handlers)
+	for i := range sites {
+		sites[i] = site
+	}
+	return sites
+}
@@ -0,0 +1,99 @@
+package fiber
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testChainMiddleware(c Ctx) error {
+	return c.Next()
+}
+
+// go test -run Test_App_MiddlewareChain
+func Test_App_MiddlewareChain(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Use(testChainMiddleware)
+	app.Use("/api", testChainMiddleware)
+	app.Use("/admin", testChainMiddleware)
+	app.Get("/api/users/:id", testSimpleHandler, testChainMiddleware).Name("user")
+	app.Get("/api/users/:id", testSimpleHandler)
+
+	chain := app.MiddlewareChain(MethodGet, "/api/users/1")
+	// the handlers of identical routes are merged into one route
+	require.Len(t, chain, 5)
+	for _, handler := range chain {
+		require.True(t, strings.HasSuffix(handler.Site[:strings.LastIndexByte(handler.Site, ':')], "chain_test.go"), handler.Site)
+	}
+	require.Equal(t, ChainHandler{
+		Name:   "github.com/gofiber/fiber/v3.testChainMiddleware",
+		Site:   chain[0].Site,
+		Method: MethodGet,
+		Path:   "/",
+		Use:    true,
+	}, chain[0])
+	require.Equal(t, "/api", chain[1].Path)
+	require.Equal(t, "github.com/gofiber/fiber/v3.testChainMiddleware", chain[2].Name)
+	require.Equal(t, ChainHandler{
+		Name:   "github.com/gofiber/fiber/v3.testSimpleHandler",
+		Site:   chain[3].Site,
+		Method: MethodGet,
+		Path:   "/api/users/:id",
+		Route:  "user",
+	}, chain[3])
+	require.NotEqual(t, chain[3].Site, chain[4].Site)
+}
+
+// go test -run Test_App_DryRun
+func Test_App_DryRun(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Use(testChainMiddleware)
+	app.Get("/users/:id", testSimpleHandler)
+
+	// the handlers aren't executed
+	executed := false
+	app.Post("/users", func(Ctx) error {
+		executed = true
+		return nil
+	})
+
+	result := app.DryRun(MethodGet, "/USERS/1/")
+	require.Equal(t, StatusOK, result.Status)
+	require.Equal(t, map[string]string{"id": "1"}, result.Params)
+	require.Len(t, result.Chain, 2)
+
+	result = app.DryRun(MethodPost, "/users")
+	require.Equal(t, StatusOK, result.Status)
+	require.False(t, executed)
+
+	result = app.DryRun(MethodPut, "/users/1")
+	require.Equal(t, StatusMethodNotAllowed, result.Status)
+	require.Len(t, result.Chain, 1)
+
+	result = app.DryRun(MethodGet, "/orders")
+	require.Equal(t, StatusNotFound, result.Status)
+	require.Len(t, result.Chain, 1)
+
+	require.Equal(t, StatusNotImplemented, app.DryRun("BREW", "/").Status)
+}
+
+// go test -run Test_App_AdminApp_RoutesChain
+func Test_App_AdminApp_RoutesChain(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/users/:id", testSimpleHandler)
+	admin := app.AdminApp(AdminConfig{
+		Auth: func(c Ctx) error {
+			return c.Next()
+		},
+	})
+
+	status, body := testAdminRequest(t, admin, MethodGet, "/routes/chain?path=/users/1", "")
+	require.Equal(t, StatusOK, status)
+	require.Contains(t, body, `"params":{"id":"1"},"status":200`)
+	status, _ = testAdminRequest(t, admin, MethodGet, "/routes/chain", "")
+	require.Equal(t, StatusBadRequest, status)
+}
@@ -270,3 +270,8 @@ var (
 	ErrBodyType             = errors.New("the body type should be []byte")
 	ErrNotSupportSaveMethod = errors.New("file path and io.Writer are supported")
 )
+
+// init registers the timeouts as upstream errors of the error stats of the apps.
+func init() {
+	fiber.RegisterUpstreamError(ErrTimeoutOrCancel)
+}
@@ -3,7 +3,9 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -133,6 +135,19 @@ func Test_Exec_Func(t *testing.T) {
 	})
 }
 
+func Test_ErrTimeoutOrCancel_ErrorStats(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Get("/", func(fiber.Ctx) error {
+		return fmt.Errorf("inventory: %w", ErrTimeoutOrCancel)
+	})
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	// the timeouts of the client are counted as upstream errors
+	require.Equal(t, uint64(1), app.ErrorStats().Categories[fiber.ErrorCategoryUpstream])
+}
+
 func Test_Execute(t *testing.T) {
 	t.Parallel()
 	ln := fasthttputil.NewInmemoryListener()
@@ -199,6 +199,19 @@ func parserRequestBody(c *Client, req *Request) error {
 		} else {
 			return ErrBodyType
 		}
+	case cloudEventBody:
+		ce, ok := req.body.(cloudEvent)
+		if !ok {
+			return ErrBodyType
+		}
+		header, body, err := ce.event.Encode(ce.data, ce.mode, c.jsonMarshal)
+		if err != nil {
+			return err
+		}
+		for key, value := range header {
+			req.RawRequest.Header.Set(key, value)
+		}
+		req.RawRequest.SetBody(body)
 	case noBody:
 		return nil
 	}
@@ -34,6 +34,7 @@ const (
 	formBody
 	filesBody
 	rawBody
+	cloudEventBody
 )
 
 var ErrClientNil = errors.New("client can not be nil")
@@ -156,6 +157,13 @@ func (r *Request) SetHeaders(h map[string]string) *Request {
 	return r
 }
 
+// AddForwarded method adds a Forwarded header (RFC 7239) with the given elements in the request instance,
+// e.g. to pass the client of a request on to another service.
+func (r *Request) AddForwarded(elements ...fiber.ForwardedElement) *Request {
+	r.header.Add(fiber.HeaderForwarded, fiber.FormatForwarded(elements...))
+	return r
+}
+
 // Param method returns params value via key,
 // this method will visit all field in the query param.
 func (r *Request) Param(key string) []string {
@@ -347,6 +355,26 @@ func (r *Request) SetRawBody(v []byte) *Request {
 	return r
 }
 
+// cloudEvent is the body of a request with a CloudEvent.
+type cloudEvent struct {
+	data  any
+	event fiber.CloudEvent
+	mode  fiber.CloudEventMode
+}
+
+// SetCloudEvent method sets a CloudEvent with the data as body in request, in the
+// binary mode by default. The data is encoded with the JSON marshal of the client
+// unless it is a []byte or a string, see fiber.CloudEvent.Encode.
+func (r *Request) SetCloudEvent(event fiber.CloudEvent, data any, mode ...fiber.CloudEventMode) *Request {
+	body := cloudEvent{event: event, data: data}
+	if len(mode) > 0 {
+		body.mode = mode[0]
+	}
+	r.body = body
+	r.bodyType = cloudEventBody
+	return r
+}
+
 // resetBody will clear body object and set bodyType
 // if body type is formBody and filesBody, the new body type will be ignored.
 func (r *Request) resetBody(t bodyType) {
@@ -154,6 +154,19 @@ func Test_Request_Header(t *testing.T) {
 		require.Len(t, res, 1)
 		require.Equal(t, "foo", res[0])
 	})
+
+	t.Run("add forwarded", func(t *testing.T) {
+		t.Parallel()
+		req := AcquireRequest()
+		req.AddForwarded(
+			fiber.ForwardedElement{For: "192.0.2.60", Proto: "https"},
+			fiber.ForwardedElement{For: "2001:db8::17", By: "unknown"},
+		)
+
+		res := req.Header(fiber.HeaderForwarded)
+		require.Len(t, res, 1)
+		require.Equal(t, `for=192.0.2.60;proto=https, for="[2001:db8::17]";by=unknown`, res[0])
+	})
 }
 
 func Test_Request_QueryParam(t *testing.T) {
@@ -1006,6 +1019,52 @@ func Test_Request_Body_With_Server(t *testing.T) {
 		)
 	})
 
+	t.Run("cloudevent body", func(t *testing.T) {
+		t.Parallel()
+		testRequest(t,
+			func(c fiber.Ctx) error {
+				var event fiber.CloudEvent
+				var data map[string]string
+				if err := c.Bind().CloudEvent(&event, &data); err != nil {
+					return err
+				}
+				require.Equal(t, "/orders", event.Source)
+				require.Equal(t, "com.example.order.created", event.Type)
+				require.NotEmpty(t, event.ID)
+				return c.SendString(c.Get("Ce-Type") + " " + data["order_id"])
+			},
+			func(agent *Request) {
+				agent.SetCloudEvent(fiber.CloudEvent{
+					Source: "/orders",
+					Type:   "com.example.order.created",
+				}, map[string]string{"order_id": "1"})
+			},
+			"com.example.order.created 1",
+		)
+	})
+
+	t.Run("structured cloudevent body", func(t *testing.T) {
+		t.Parallel()
+		testRequest(t,
+			func(c fiber.Ctx) error {
+				require.Equal(t, fiber.MIMEApplicationCloudEventsJSON, string(c.Request().Header.ContentType()))
+				var event fiber.CloudEvent
+				var data string
+				if err := c.Bind().CloudEvent(&event, &data); err != nil {
+					return err
+				}
+				return c.SendString(event.Type + " " + data)
+			},
+			func(agent *Request) {
+				agent.SetCloudEvent(fiber.CloudEvent{
+					Source: "/orders",
+					Type:   "com.example.order.created",
+				}, "hello", fiber.CloudEventStructured)
+			},
+			"com.example.order.created hello",
+		)
+	})
+
 	t.Run("formdata", func(t *testing.T) {
 		t.Parallel()
 		testRequest(t,
@@ -0,0 +1,98 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// spiffeScheme is the URI scheme of SPIFFE IDs, e.g. "spiffe://example.org/ns/prod/sa/orders".
+const spiffeScheme = "spiffe"
+
+// ClientCertificate is the identity of the verified client certificate of a mTLS request,
+// see Ctx.ClientCertificate.
+type ClientCertificate struct {
+	// Leaf is the client certificate.
+	Leaf *x509.Certificate `json:"-"`
+	// Chain is the verified chain, from the client certificate to the trusted root.
+	Chain []*x509.Certificate `json:"-"`
+	// SPIFFEID is the SPIFFE ID of the URI SAN, e.g. "spiffe://example.org/ns/prod/sa/orders",
+	// or empty if the certificate has none.
+	SPIFFEID string `json:"spiffe_id,omitempty"`
+	// Subject is the distinguished name of the certificate, e.g. "CN=orders,O=Example".
+	Subject string `json:"subject"`
+	// CommonName is the common name of the subject.
+	CommonName string `json:"common_name,omitempty"`
+	// Issuer is the distinguished name of the issuer.
+	Issuer string `json:"issuer"`
+	// SerialNumber is the serial number in hex.
+	SerialNumber string `json:"serial_number"`
+	// Fingerprint is the SHA-256 fingerprint of the certificate in hex.
+	Fingerprint string `json:"fingerprint"`
+	// DNSNames are the DNS SANs.
+	DNSNames []string `json:"dns_names,omitempty"`
+	// EmailAddresses are the email SANs.
+	EmailAddresses []string `json:"email_addresses,omitempty"`
+	// URIs are the URI SANs, including the SPIFFE ID.
+	URIs []string `json:"uris,omitempty"`
+}
+
+// NewClientCertificate returns the identity of the verified chain, the client certificate first.
+// It returns nil if the chain is empty.
+func NewClientCertificate(chain []*x509.Certificate) *ClientCertificate {
+	if len(chain) == 0 {
+		return nil
+	}
+	leaf := chain[0]
+	fingerprint := sha256.Sum256(leaf.Raw)
+	cert := &ClientCertificate{
+		Leaf:           leaf,
+		Chain:          chain,
+		Subject:        leaf.Subject.String(),
+		CommonName:     leaf.Subject.CommonName,
+		Issuer:         leaf.Issuer.String(),
+		SerialNumber:   leaf.SerialNumber.Text(16),
+		Fingerprint:    hex.EncodeToString(fingerprint[:]),
+		DNSNames:       leaf.DNSNames,
+		EmailAddresses: leaf.EmailAddresses,
+	}
+	for _, uri := range leaf.URIs {
+		cert.URIs = append(cert.URIs, uri.String())
+		// a SPIFFE ID has a trust domain and no query, fragment or user info
+		if cert.SPIFFEID == "" && uri.Scheme == spiffeScheme && uri.Host != "" &&
+			uri.User == nil && uri.RawQuery == "" && uri.Fragment == "" {
+			cert.SPIFFEID = uri.String()
+		}
+	}
+	return cert
+}
+
+// TrustDomain returns the trust domain of the SPIFFE ID, e.g. "example.org",
+// or empty if the certificate has no SPIFFE ID.
+func (cert *ClientCertificate) TrustDomain() string {
+	if cert.SPIFFEID == "" {
+		return ""
+	}
+	u, err := url.Parse(cert.SPIFFEID)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// ClientCertificate returns the identity of the verified client certificate of a mTLS request,
+// e.g. its SPIFFE ID, SANs and fingerprint. It returns nil if the request wasn't made over TLS
+// or the client certificate wasn't verified, e.g. because the TLS config only requests it.
+func (c *DefaultCtx) ClientCertificate() *ClientCertificate {
+	state := c.fasthttp.TLSConnectionState()
+	if state == nil || len(state.VerifiedChains) == 0 {
+		return nil
+	}
+	return NewClientCertificate(state.VerifiedChains[0])
+}
@@ -0,0 +1,143 @@
+package fiber
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// testClientCertificate issues a client certificate with the URI SAN by a new CA.
+func testClientCertificate(t *testing.T, uri string) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err = x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leaf := &x509.Certificate{
+		SerialNumber: big.NewInt(0xbeef),
+		Subject:      pkix.Name{CommonName: "orders", Organization: []string{"Example"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"orders.internal"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if uri != "" {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		leaf.URIs = []*url.URL{u}
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leaf, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return tls.Certificate{Certificate: [][]byte{leafDER}, PrivateKey: key}, pool
+}
+
+// go test -run Test_Ctx_ClientCertificate
+func Test_Ctx_ClientCertificate(t *testing.T) {
+	t.Parallel()
+	clientCert, pool := testClientCertificate(t, "spiffe://Example.org/ns/prod/sa/orders")
+
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		cert := c.ClientCertificate()
+		if cert == nil {
+			return c.SendString("anonymous")
+		}
+		return c.JSON(cert)
+	})
+
+	serverCert, err := tls.LoadX509KeyPair("./.github/testdata/ssl.pem", "./.github/testdata/ssl.key")
+	require.NoError(t, err)
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(tls.NewListener(ln, &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{serverCert},
+			ClientAuth:   tls.VerifyClientCertIfGiven,
+			ClientCAs:    pool,
+		}), ListenConfig{DisableStartupMessage: true}))
+	}()
+	defer app.Shutdown() //nolint:errcheck // It's a test
+
+	request := func(certs ...tls.Certificate) string {
+		var conn net.Conn
+		require.Eventually(t, func() bool {
+			conn, err = ln.Dial()
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+		tlsConn := tls.Client(conn, &tls.Config{
+			InsecureSkipVerify: true, //nolint:gosec // self signed test certificate
+			Certificates:       certs,
+		})
+		_, err = tlsConn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\nConnection: close\r\n\r\n"))
+		require.NoError(t, err)
+		body, err := io.ReadAll(tlsConn)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	body := request(clientCert)
+	require.Contains(t, body, `"spiffe_id":"spiffe://Example.org/ns/prod/sa/orders"`)
+	require.Contains(t, body, `"subject":"CN=orders,O=Example","common_name":"orders","issuer":"CN=Test CA","serial_number":"beef"`)
+	require.Contains(t, body, `"dns_names":["orders.internal"]`)
+	require.Contains(t, request(), "anonymous")
+
+	// the request isn't made over TLS
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "anonymous", string(b))
+}
+
+// go test -run Test_NewClientCertificate
+func Test_NewClientCertificate(t *testing.T) {
+	t.Parallel()
+	require.Nil(t, NewClientCertificate(nil))
+
+	clientCert, _ := testClientCertificate(t, "spiffe://Example.org/ns/prod/sa/orders")
+	leaf, err := x509.ParseCertificate(clientCert.Certificate[0])
+	require.NoError(t, err)
+	cert := NewClientCertificate([]*x509.Certificate{leaf})
+	require.Equal(t, "example.org", cert.TrustDomain())
+	require.Len(t, cert.Fingerprint, 64)
+	require.Equal(t, []string{"spiffe://Example.org/ns/prod/sa/orders"}, cert.URIs)
+
+	// URIs with a query aren't SPIFFE IDs
+	clientCert, _ = testClientCertificate(t, "spiffe://example.org/orders?x=1")
+	leaf, err = x509.ParseCertificate(clientCert.Certificate[0])
+	require.NoError(t, err)
+	cert = NewClientCertificate([]*x509.Certificate{leaf})
+	require.Empty(t, cert.SPIFFEID)
+	require.Empty(t, cert.TrustDomain())
+}
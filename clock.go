@@ -0,0 +1,293 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of the time of the app and the time-dependent middlewares, e.g. of
+// the timeouts and the expirations. Tests replace it with a ManualClock to fast-forward
+// the time deterministically instead of sleeping.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a timer which sends the time on its channel after the duration.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer of a Clock, like time.Timer.
+type Timer interface {
+	// C returns the channel of the timer.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, it returns false if the timer already fired or was stopped.
+	Stop() bool
+}
+
+// Rand is the source of the random numbers of the app and the middlewares, e.g. of the
+// sampling and the jitter. It must be safe for concurrent use. Tests replace it with
+// NewRand to get deterministic numbers.
+type Rand interface {
+	// Float64 returns a number in [0.0,1.0).
+	Float64() float64
+	// Int63n returns a non-negative number in [0,n), it panics if n <= 0.
+	Int63n(n int64) int64
+}
+
+// systemClock is the Clock of the system time.
+type systemClock struct{}
+
+// systemTimer is the Timer of the system clock.
+type systemTimer struct {
+	timer *time.Timer
+}
+
+// SystemClock returns the Clock of the system time, it's the default Clock.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// Now returns the current time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer creates a timer of the system time.
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{timer: time.NewTimer(d)}
+}
+
+// C returns the channel of the timer.
+func (t systemTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+// Stop stops the timer.
+func (t systemTimer) Stop() bool {
+	return t.timer.Stop()
+}
+
+// systemRand is the Rand of the global source of math/rand.
+type systemRand struct{}
+
+// SystemRand returns the Rand of the global source of math/rand, it's the default Rand.
+func SystemRand() Rand {
+	return systemRand{}
+}
+
+// Float64 returns a number in [0.0,1.0).
+func (systemRand) Float64() float64 {
+	return rand.Float64() //nolint:gosec // The numbers don't need to be secure
+}
+
+// Int63n returns a number in [0,n).
+func (systemRand) Int63n(n int64) int64 {
+	return rand.Int63n(n) //nolint:gosec // The numbers don't need to be secure
+}
+
+// lockedRand is a seeded Rand which is safe for concurrent use.
+type lockedRand struct {
+	rand  *rand.Rand
+	mutex sync.Mutex
+}
+
+// NewRand returns a Rand of the seed, which returns the same numbers for the same seed.
+func NewRand(seed int64) Rand {
+	return &lockedRand{rand: rand.New(rand.NewSource(seed))} //nolint:gosec // The numbers don't need to be secure
+}
+
+// Float64 returns a number in [0.0,1.0).
+func (r *lockedRand) Float64() float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.rand.Float64()
+}
+
+// Int63n returns a number in [0,n).
+func (r *lockedRand) Int63n(n int64) int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.rand.Int63n(n)
+}
+
+// ManualClock is a Clock whose time only changes with Advance and Set, for tests.
+// Its timers fire when the time reaches their deadline.
+//
+//	clock := fiber.NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	app := fiber.New(fiber.Config{Clock: clock})
+//	...
+//	clock.Advance(time.Hour)
+type ManualClock struct {
+	now    time.Time
+	timers []*manualTimer
+	// waiters are notified when a timer is created
+	waiters []chan struct{}
+	mutex   sync.Mutex
+}
+
+// manualTimer is a Timer of a ManualClock.
+type manualTimer struct {
+	clock    *ManualClock
+	c        chan time.Time
+	deadline time.Time
+}
+
+// NewManualClock returns a ManualClock at the time.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the time of the clock.
+func (m *ManualClock) Now() time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.now
+}
+
+// NewTimer creates a timer which fires when the time of the clock reaches the duration from now.
+func (m *ManualClock) NewTimer(d time.Duration) Timer {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	t := &manualTimer{clock: m, c: make(chan time.Time, 1), deadline: m.now.Add(d)}
+	if d <= 0 {
+		t.c <- m.now
+		return t
+	}
+	m.timers = append(m.timers, t)
+	for _, waiter := range m.waiters {
+		close(waiter)
+	}
+	m.waiters = nil
+	return t
+}
+
+// Advance moves the time of the clock forward and fires the timers which are due.
+func (m *ManualClock) Advance(d time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.set(m.now.Add(d))
+}
+
+// Set sets the time of the clock and fires the timers which are due.
+func (m *ManualClock) Set(now time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.set(now)
+}
+
+// set fires the due timers in the order of their deadlines, it must be called with the mutex held.
+func (m *ManualClock) set(now time.Time) {
+	m.now = now
+	sort.SliceStable(m.timers, func(i, j int) bool {
+		return m.timers[i].deadline.Before(m.timers[j].deadline)
+	})
+	pending := m.timers[:0]
+	for _, t := range m.timers {
+		if t.deadline.After(now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- now
+	}
+	clear(m.timers[len(pending):])
+	m.timers = pending
+}
+
+// Timers returns the number of the timers which didn't fire and weren't stopped.
+func (m *ManualClock) Timers() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return len(m.timers)
+}
+
+// WaitForTimers blocks until at least n timers are pending or the context is done, e.g. to
+// advance the clock after the code under test started to wait.
+func (m *ManualClock) WaitForTimers(ctx context.Context, n int) error {
+	for {
+		m.mutex.Lock()
+		if len(m.timers) >= n {
+			m.mutex.Unlock()
+			return nil
+		}
+		waiter := make(chan struct{})
+		m.waiters = append(m.waiters, waiter)
+		m.mutex.Unlock()
+
+		select {
+		case <-waiter:
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck // This must not be wrapped
+		}
+	}
+}
+
+// C returns the channel of the timer.
+func (t *manualTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop removes the timer from the clock.
+func (t *manualTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	for i, pending := range t.clock.timers {
+		if pending == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// clockContext is a context whose deadline is measured with a Clock.
+type clockContext struct {
+	context.Context //nolint:containedctx // The context is extended with the deadline
+	deadline        time.Time
+}
+
+// Deadline returns the deadline of the clock.
+func (c clockContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+// Err returns context.DeadlineExceeded when the deadline of the clock passed.
+func (c clockContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && errors.Is(context.Cause(c.Context), context.DeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+	return err //nolint:wrapcheck // This must not be wrapped
+}
+
+// withTimeout is context.WithTimeout with the timeout measured by the clock.
+func withTimeout(ctx context.Context, clock Clock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(systemClock); ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	deadline := clock.Now().Add(timeout)
+	if parent, ok := ctx.Deadline(); ok && parent.Before(deadline) {
+		deadline = parent
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	timer := clock.NewTimer(timeout)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-timer.C():
+			cancel(context.DeadlineExceeded)
+		case <-ctx.Done():
+		}
+	}()
+	return clockContext{Context: ctx, deadline: deadline}, func() {
+		cancel(context.Canceled)
+	}
+}
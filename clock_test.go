@@ -0,0 +1,132 @@
+package fiber
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_ManualClock
+func Test_ManualClock(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	require.Equal(t, start, clock.Now())
+
+	late := clock.NewTimer(2 * time.Second)
+	early := clock.NewTimer(time.Second)
+	stopped := clock.NewTimer(time.Second)
+	require.True(t, stopped.Stop())
+	require.Equal(t, 2, clock.Timers())
+
+	clock.Advance(time.Second)
+	require.Equal(t, start.Add(time.Second), <-early.C())
+	require.False(t, early.Stop())
+	select {
+	case <-late.C():
+		t.Fatal("the timer fired too early")
+	default:
+	}
+
+	clock.Set(start.Add(time.Minute))
+	require.Equal(t, start.Add(time.Minute), <-late.C())
+	require.Equal(t, 0, clock.Timers())
+
+	// timers without a duration fire at once
+	require.Equal(t, start.Add(time.Minute), <-clock.NewTimer(0).C())
+}
+
+// go test -run Test_ManualClock_WaitForTimers
+func Test_ManualClock_WaitForTimers(t *testing.T) {
+	t.Parallel()
+	clock := NewManualClock(time.Now())
+
+	go clock.NewTimer(time.Second)
+	require.NoError(t, clock.WaitForTimers(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, clock.WaitForTimers(ctx, 2), context.Canceled)
+}
+
+// go test -run Test_NewRand
+func Test_NewRand(t *testing.T) {
+	t.Parallel()
+	a, b := NewRand(1), NewRand(1)
+	for i := 0; i < 10; i++ {
+		require.Equal(t, a.Float64(), b.Float64()) //nolint:testifylint // The numbers must be identical
+		require.Equal(t, a.Int63n(100), b.Int63n(100))
+	}
+}
+
+// go test -run Test_WithTimeout_Clock
+func Test_WithTimeout_Clock(t *testing.T) {
+	t.Parallel()
+	start := time.Now()
+	clock := NewManualClock(start)
+
+	ctx, cancel := withTimeout(context.Background(), clock, time.Hour)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.Equal(t, start.Add(time.Hour), deadline)
+	require.NoError(t, ctx.Err())
+
+	require.NoError(t, clock.WaitForTimers(context.Background(), 1))
+	clock.Advance(time.Hour)
+	<-ctx.Done()
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+	ctx, cancel = withTimeout(context.Background(), clock, time.Hour)
+	cancel()
+	<-ctx.Done()
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+// go test -run Test_App_Clock_ShutdownHookTimeout
+func Test_App_Clock_ShutdownHookTimeout(t *testing.T) {
+	t.Parallel()
+	clock := NewManualClock(time.Now())
+	app := New(Config{Clock: clock})
+	require.Equal(t, clock, app.Clock())
+	require.Equal(t, SystemRand(), app.Rand())
+
+	app.Hooks().OnShutdownNamed("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}, ShutdownHookConfig{Timeout: time.Hour})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- app.Shutdown()
+	}()
+
+	// the hook waits for the clock instead of an hour
+	require.NoError(t, clock.WaitForTimers(context.Background(), 1))
+	clock.Advance(time.Hour)
+	err := <-done
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, `shutdown hook "slow"`)
+}
+
+// go test -run Test_App_Clock_DrainTimeout
+func Test_App_Clock_DrainTimeout(t *testing.T) {
+	t.Parallel()
+	clock := NewManualClock(time.Now())
+	app := New(Config{Clock: clock, DrainTimeout: time.Minute})
+
+	_, done := app.TrackStream()
+	defer done()
+
+	drained := make(chan struct{})
+	go func() {
+		app.drainStreams(context.Background())
+		close(drained)
+	}()
+
+	require.NoError(t, clock.WaitForTimers(context.Background(), 1))
+	clock.Advance(time.Minute)
+	<-drained
+}
@@ -0,0 +1,116 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"sync/atomic"
+)
+
+// Clone creates a new app with the same configuration, custom constraints,
+// custom binders and custom ctx function, but without routes, hooks and plugins.
+//
+// Together with SwapRoutes it can be used to build a new route tree in the
+// background and activate it transactionally:
+//
+//	next := app.Clone()
+//	next.Get("/generated", handler)
+//	if err := app.SwapRoutes(next); err != nil {
+//	    return err
+//	}
+func (app *App) Clone() *App {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	clone := New(app.configured)
+	clone.newCtxFunc = app.newCtxFunc
+	clone.customBinders = append(clone.customBinders, app.customBinders...)
+	clone.customConstraints = append(clone.customConstraints, app.customConstraints...)
+
+	return clone
+}
+
+// Snapshot returns a clone of the app which holds a copy of the current routes.
+// It can be passed to SwapRoutes later to roll back to the current routes.
+func (app *App) Snapshot() *App {
+	snapshot := app.Clone()
+	if err := snapshot.SwapRoutes(app); err != nil {
+		panic(err) // unreachable, the clone has the same request methods
+	}
+
+	return snapshot
+}
+
+// SwapRoutes atomically replaces the routes of the app with the routes of the given app.
+// Requests which are already running finish with the previous routes, all new requests
+// use the new routes. The apps must have the same request methods.
+//
+// The error handlers of apps which are mounted in the given app are not taken over.
+func (app *App) SwapRoutes(src *App) error {
+	if src == app {
+		return nil
+	}
+	if !equalMethods(app.config.RequestMethods, src.config.RequestMethods) {
+		return ErrRouteMethodsMismatch
+	}
+
+	// prepare the routes of the given app
+	src.startupProcess()
+
+	src.mutex.Lock()
+	stack := src.copyStack()
+	routesCount := atomic.LoadUint32(&src.routesCount)
+	handlersCount := atomic.LoadUint32(&src.handlersCount)
+	src.mutex.Unlock()
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	app.stack = stack
+	atomic.StoreUint32(&app.routesCount, routesCount)
+	atomic.StoreUint32(&app.handlersCount, handlersCount)
+	app.routesRefreshed = true
+	app.latestRoute = &Route{}
+
+	// build and publish the new tree
+	app.buildTree()
+
+	return nil
+}
+
+// copyStack returns a deep copy of the routes of the stack, so the routes which are changed
+// later, e.g. with Name or Tag, don't change the copy. A route which is in the stacks of
+// multiple methods is copied once. It must be called with the mutex held.
+func (app *App) copyStack() [][]*Route {
+	copied := make(map[*Route]*Route)
+	stack := make([][]*Route, len(app.stack))
+	for m := range app.stack {
+		stack[m] = make([]*Route, len(app.stack[m]))
+		for i, route := range app.stack[m] {
+			clone, ok := copied[route]
+			if !ok {
+				clone = app.copyRoute(route)
+				// the group and the states of the middlewares are kept
+				clone.group = route.group
+				clone.states = route.states
+				copied[route] = clone
+			}
+			stack[m][i] = clone
+		}
+	}
+	return stack
+}
+
+// equalMethods checks if both method lists are equal.
+func equalMethods(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
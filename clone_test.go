@@ -0,0 +1,132 @@
+package fiber
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testRequestBody(t *testing.T, app *App, path string) (int, string) {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, string(body)
+}
+
+// go test -run Test_App_Clone
+func Test_App_Clone(t *testing.T) {
+	t.Parallel()
+	app := New(Config{AppName: "blue", StrictRouting: true})
+	app.Get("/", testSimpleHandler)
+
+	clone := app.Clone()
+	require.Equal(t, "blue", clone.Config().AppName)
+	require.True(t, clone.Config().StrictRouting)
+	require.Empty(t, clone.GetRoutes())
+}
+
+// go test -run Test_App_SwapRoutes
+func Test_App_SwapRoutes(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.SendString("blue")
+	})
+
+	status, body := testRequestBody(t, app, "/")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "blue", body)
+
+	snapshot := app.Snapshot()
+
+	next := app.Clone()
+	next.Get("/", func(c Ctx) error {
+		return c.SendString("green")
+	})
+	next.Get("/new", testSimpleHandler)
+	require.NoError(t, app.SwapRoutes(next))
+
+	status, body = testRequestBody(t, app, "/")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "green", body)
+
+	status, _ = testRequestBody(t, app, "/new")
+	require.Equal(t, StatusOK, status)
+	require.Len(t, app.GetRoutes(true), 2)
+
+	// roll back to the snapshot
+	require.NoError(t, app.SwapRoutes(snapshot))
+
+	status, body = testRequestBody(t, app, "/")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "blue", body)
+
+	status, _ = testRequestBody(t, app, "/new")
+	require.Equal(t, StatusNotFound, status)
+}
+
+// go test -run Test_App_Snapshot_RouteChanges
+func Test_App_Snapshot_RouteChanges(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", testSimpleHandler).Name("home").Tag("v1")
+
+	snapshot := app.Snapshot()
+
+	// the changes of the routes after the snapshot don't change the snapshot
+	app.Name("start").Tag("v2")
+	require.Equal(t, "/", app.GetRoute("start").Path)
+	require.Equal(t, []string{"v1", "v2"}, app.GetRoute("start").Tags)
+
+	// the rollback restores the previous routes
+	require.NoError(t, app.SwapRoutes(snapshot))
+	route := app.GetRoute("home")
+	require.Equal(t, "/", route.Path)
+	require.Equal(t, []string{"v1"}, route.Tags)
+	require.Empty(t, app.GetRoute("start").Path)
+}
+
+// go test -run Test_App_SwapRoutes_InFlight
+func Test_App_SwapRoutes_InFlight(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	next := app.Clone()
+	next.Get("/", func(c Ctx) error {
+		return c.SendString("green")
+	})
+
+	app.Use(func(c Ctx) error {
+		// the running request keeps its tree
+		require.NoError(t, app.SwapRoutes(next))
+		return c.Next()
+	})
+	app.Get("/", func(c Ctx) error {
+		return c.SendString("blue")
+	})
+
+	status, body := testRequestBody(t, app, "/")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "blue", body)
+
+	status, body = testRequestBody(t, app, "/")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "green", body)
+}
+
+// go test -run Test_App_SwapRoutes_MethodsMismatch
+func Test_App_SwapRoutes_MethodsMismatch(t *testing.T) {
+	t.Parallel()
+	app := New()
+	other := New(Config{RequestMethods: []string{MethodGet}})
+
+	require.ErrorIs(t, app.SwapRoutes(other), ErrRouteMethodsMismatch)
+	require.NoError(t, app.SwapRoutes(app))
+}
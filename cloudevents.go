@@ -0,0 +1,403 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3/binder"
+	"github.com/gofiber/utils/v2"
+)
+
+// CloudEventsSpecVersion is the supported version of the CloudEvents specification.
+const CloudEventsSpecVersion = "1.0"
+
+// cloudEventHeaderPrefix is the prefix of the attribute headers of the binary mode.
+const cloudEventHeaderPrefix = "ce-"
+
+// CloudEventMode is the content mode of a CloudEvent in an HTTP message.
+type CloudEventMode uint8
+
+const (
+	// CloudEventBinary sends the attributes in ce- headers and the data as body.
+	CloudEventBinary CloudEventMode = iota
+	// CloudEventStructured sends the event with its data as application/cloudevents+json body.
+	CloudEventStructured
+)
+
+// CloudEvent holds the context attributes of a CloudEvent, see https://cloudevents.io.
+// The data of the event is bound or encoded separately, so it can be a typed struct.
+type CloudEvent struct {
+	// ID identifies the event, it is set to a UUID when the event is encoded without ID.
+	ID string
+	// Source identifies the context in which the event happened, e.g. "/orders". Required.
+	Source string
+	// SpecVersion is the version of the specification, it defaults to CloudEventsSpecVersion.
+	SpecVersion string
+	// Type is the type of the event, e.g. "com.example.order.created". Required.
+	Type string
+	// DataContentType is the content type of the data, e.g. "application/json".
+	DataContentType string
+	// DataSchema is the URI of the schema of the data.
+	DataSchema string
+	// Subject is the subject of the event in the context of the source.
+	Subject string
+	// Time is the time the event happened.
+	Time time.Time
+	// Extensions are the extension attributes, their names are lowercase alphanumeric.
+	Extensions map[string]string
+}
+
+// Encode encodes the event with the data for an HTTP message in the mode and returns the headers
+// and the body of the message. The data is encoded with the encoder unless it is a []byte or a string,
+// which are sent as they are. It is used by Ctx.SendCloudEvent and the client.
+func (e CloudEvent) Encode(data any, mode CloudEventMode, encoder utils.JSONMarshal) (map[string]string, []byte, error) {
+	if e.SpecVersion == "" {
+		e.SpecVersion = CloudEventsSpecVersion
+	}
+	if e.ID == "" {
+		e.ID = utils.UUIDv4()
+	}
+	if err := e.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	var body []byte
+	isJSON := false
+	switch v := data.(type) {
+	case nil:
+	case []byte:
+		body = v
+		if e.DataContentType == "" {
+			e.DataContentType = MIMEOctetStream
+		}
+	case string:
+		body = []byte(v)
+		if e.DataContentType == "" {
+			e.DataContentType = MIMETextPlainCharsetUTF8
+		}
+	default:
+		raw, err := encoder(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cloudevents: failed to encode data: %w", err)
+		}
+		body = raw
+		isJSON = true
+		if e.DataContentType == "" {
+			e.DataContentType = MIMEApplicationJSON
+		}
+	}
+
+	if mode == CloudEventStructured {
+		return e.encodeStructured(body, data != nil, isJSON || isCloudEventJSON(e.DataContentType), encoder)
+	}
+
+	header := make(map[string]string, 8+len(e.Extensions))
+	for name, value := range e.attributes() {
+		header[cloudEventHeaderPrefix+name] = cloudEventEscape(value)
+	}
+	if data != nil {
+		header[HeaderContentType] = e.DataContentType
+	}
+	return header, body, nil
+}
+
+// encodeStructured encodes the event for the structured mode.
+func (e CloudEvent) encodeStructured(body []byte, hasData, isJSON bool, encoder utils.JSONMarshal) (map[string]string, []byte, error) {
+	fields := make(map[string]any, 10+len(e.Extensions))
+	for name, value := range e.attributes() {
+		fields[name] = value
+	}
+	switch {
+	case !hasData:
+	case isJSON:
+		fields["data"] = json.RawMessage(body)
+	case strings.HasPrefix(e.DataContentType, "text/"):
+		fields["data"] = string(body)
+	default:
+		fields["data_base64"] = base64.StdEncoding.EncodeToString(body)
+	}
+
+	raw, err := encoder(fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cloudevents: failed to encode event: %w", err)
+	}
+	return map[string]string{HeaderContentType: MIMEApplicationCloudEventsJSON}, raw, nil
+}
+
+// attributes returns the non-empty attributes and the extensions of the event by their names.
+func (e CloudEvent) attributes() map[string]string {
+	attributes := make(map[string]string, 8+len(e.Extensions))
+	for name, value := range e.Extensions {
+		attributes[utils.ToLower(name)] = value
+	}
+	for name, value := range map[string]string{
+		"id":              e.ID,
+		"source":          e.Source,
+		"specversion":     e.SpecVersion,
+		"type":            e.Type,
+		"datacontenttype": e.DataContentType,
+		"dataschema":      e.DataSchema,
+		"subject":         e.Subject,
+	} {
+		if value != "" {
+			attributes[name] = value
+		}
+	}
+	if !e.Time.IsZero() {
+		attributes["time"] = e.Time.Format(time.RFC3339Nano)
+	}
+	return attributes
+}
+
+// set sets the attribute with the name, unknown attributes are extensions.
+func (e *CloudEvent) set(name, value string) error {
+	switch name {
+	case "id":
+		e.ID = value
+	case "source":
+		e.Source = value
+	case "specversion":
+		e.SpecVersion = value
+	case "type":
+		e.Type = value
+	case "datacontenttype":
+		e.DataContentType = value
+	case "dataschema":
+		e.DataSchema = value
+	case "subject":
+		e.Subject = value
+	case "time":
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("%w: invalid time %q", ErrCloudEventInvalid, value)
+		}
+		e.Time = t
+	default:
+		if e.Extensions == nil {
+			e.Extensions = make(map[string]string)
+		}
+		e.Extensions[name] = value
+	}
+	return nil
+}
+
+// validate checks the required attributes of the event.
+func (e CloudEvent) validate() error {
+	if e.SpecVersion != CloudEventsSpecVersion {
+		return fmt.Errorf("%w: unsupported specversion %q", ErrCloudEventInvalid, e.SpecVersion)
+	}
+	for name, value := range map[string]string{"id": e.ID, "source": e.Source, "type": e.Type} {
+		if value == "" {
+			return fmt.Errorf("%w: missing attribute %q", ErrCloudEventInvalid, name)
+		}
+	}
+	return nil
+}
+
+// SendCloudEvent sends the event with the data in the mode, CloudEventBinary by default.
+// The data is encoded with the JSONEncoder of the app unless it is a []byte or a string.
+//
+//	return c.SendCloudEvent(fiber.CloudEvent{Source: "/orders", Type: "com.example.order.created"}, order)
+func (c *DefaultCtx) SendCloudEvent(event CloudEvent, data any, mode ...CloudEventMode) error {
+	m := CloudEventBinary
+	if len(mode) > 0 {
+		m = mode[0]
+	}
+	header, body, err := event.Encode(data, m, c.app.config.JSONEncoder)
+	if err != nil {
+		return err
+	}
+	for key, value := range header {
+		c.fasthttp.Response.Header.Set(key, value)
+	}
+	c.fasthttp.Response.SetBodyRaw(body)
+	return nil
+}
+
+// CloudEvent binds the CloudEvent of the request into the event and its data into the data, which
+// may be a typed struct. Events in the binary mode, with ce- headers, and in the structured mode, with
+// an application/cloudevents+json body, are supported. JSON data is decoded with the JSONDecoder of
+// the app, XML data with encoding/xml, other data can be bound into a *[]byte or a *string. The data
+// is skipped if it is nil. ErrCloudEventMissing is returned if the request is no CloudEvent,
+// ErrCloudEventInvalid if the event is invalid.
+//
+//	var event fiber.CloudEvent
+//	var order Order
+//	if err := c.Bind().CloudEvent(&event, &order); err != nil {
+//	    return err
+//	}
+func (b *Bind) CloudEvent(event *CloudEvent, data any) error {
+	if err := b.returnErr(b.ctx.bindCloudEvent(event, data)); err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+	return b.validateStruct(data)
+}
+
+// bindCloudEvent parses the CloudEvent of the request.
+func (c *DefaultCtx) bindCloudEvent(event *CloudEvent, data any) error {
+	*event = CloudEvent{}
+	ctype := utils.ToLower(binder.FilterFlags(c.Get(HeaderContentType)))
+
+	var body []byte
+	switch {
+	case ctype == MIMEApplicationCloudEventsJSON:
+		var err error
+		if body, err = c.parseStructuredCloudEvent(event); err != nil {
+			return err
+		}
+	case len(c.fasthttp.Request.Header.Peek(cloudEventHeaderPrefix+"specversion")) > 0:
+		var err error
+		c.fasthttp.Request.Header.VisitAll(func(key, value []byte) {
+			name := utils.ToLower(c.app.getString(key))
+			if err != nil || !strings.HasPrefix(name, cloudEventHeaderPrefix) {
+				return
+			}
+			v := c.app.getString(value)
+			if unescaped, uerr := url.PathUnescape(v); uerr == nil {
+				v = unescaped
+			}
+			err = event.set(strings.TrimPrefix(name, cloudEventHeaderPrefix), utils.CopyString(v))
+		})
+		if err != nil {
+			return err
+		}
+		if ctype != "" {
+			event.DataContentType = utils.CopyString(c.Get(HeaderContentType))
+		}
+		body = c.Body()
+	default:
+		return ErrCloudEventMissing
+	}
+
+	if err := event.validate(); err != nil {
+		return err
+	}
+	if data == nil || len(body) == 0 {
+		return nil
+	}
+	return decodeCloudEventData(body, event.DataContentType, data, c.app.config.JSONDecoder)
+}
+
+// parseStructuredCloudEvent parses the attributes of a structured CloudEvent and returns its data.
+func (c *DefaultCtx) parseStructuredCloudEvent(event *CloudEvent) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := c.app.config.JSONDecoder(c.Body(), &fields); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCloudEventInvalid, err)
+	}
+
+	for name, raw := range fields {
+		if name == "data" || name == "data_base64" {
+			continue
+		}
+		value := string(raw)
+		if len(raw) > 0 && raw[0] == '"' {
+			if err := c.app.config.JSONDecoder(raw, &value); err != nil {
+				return nil, fmt.Errorf("%w: invalid attribute %q: %w", ErrCloudEventInvalid, name, err)
+			}
+		}
+		if err := event.set(utils.ToLower(name), value); err != nil {
+			return nil, err
+		}
+	}
+
+	if raw, ok := fields["data_base64"]; ok {
+		var encoded string
+		if err := c.app.config.JSONDecoder(raw, &encoded); err != nil {
+			return nil, fmt.Errorf("%w: invalid data_base64: %w", ErrCloudEventInvalid, err)
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid data_base64: %w", ErrCloudEventInvalid, err)
+		}
+		return data, nil
+	}
+	raw, ok := fields["data"]
+	if !ok || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+	// JSON data is embedded, other data is a string
+	if !isCloudEventJSON(event.DataContentType) && len(raw) > 0 && raw[0] == '"' {
+		var text string
+		if err := c.app.config.JSONDecoder(raw, &text); err != nil {
+			return nil, fmt.Errorf("%w: invalid data: %w", ErrCloudEventInvalid, err)
+		}
+		return []byte(text), nil
+	}
+	return raw, nil
+}
+
+// decodeCloudEventData decodes the data of a CloudEvent with the content type into the out value.
+func decodeCloudEventData(data []byte, contentType string, out any, decoder utils.JSONUnmarshal) error {
+	switch v := out.(type) {
+	case *[]byte:
+		*v = utils.CopyBytes(data)
+		return nil
+	case *string:
+		*v = string(data)
+		return nil
+	}
+
+	ctype := utils.ToLower(binder.FilterFlags(contentType))
+	switch {
+	case isCloudEventJSON(ctype):
+		if err := decoder(data, out); err != nil {
+			return fmt.Errorf("%w: invalid data: %w", ErrCloudEventInvalid, err)
+		}
+	case ctype == MIMEApplicationXML || ctype == MIMETextXML || strings.HasSuffix(ctype, "+xml"):
+		if err := xml.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("%w: invalid data: %w", ErrCloudEventInvalid, err)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported datacontenttype %q", ErrCloudEventInvalid, contentType)
+	}
+	return nil
+}
+
+// isCloudEventJSON reports if the content type of the data is JSON, which is the default.
+func isCloudEventJSON(contentType string) bool {
+	ctype := utils.ToLower(binder.FilterFlags(contentType))
+	return ctype == "" || ctype == MIMEApplicationJSON || ctype == "text/json" || strings.HasSuffix(ctype, "+json")
+}
+
+// cloudEventEscape percent-encodes the characters of a header value which must be
+// encoded in the binary mode: spaces, double quotes, percent signs and non-printable characters.
+func cloudEventEscape(value string) string {
+	escape := false
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c <= ' ' || c > '~' || c == '"' || c == '%' {
+			escape = true
+			break
+		}
+	}
+	if !escape {
+		return value
+	}
+
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	b.Grow(len(value) + 8)
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c <= ' ' || c > '~' || c == '"' || c == '%' {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0x0F])
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,250 @@
+package fiber
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type testOrderCreated struct {
+	OrderID string `json:"order_id" xml:"order_id"`
+	Total   int    `json:"total" xml:"total"`
+}
+
+// go test -run Test_Bind_CloudEvent_Binary
+func Test_Bind_CloudEvent_Binary(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.Set("Ce-Id", "1")
+	fctx.Request.Header.Set("Ce-Source", "/orders")
+	fctx.Request.Header.Set("Ce-Specversion", "1.0")
+	fctx.Request.Header.Set("Ce-Type", "com.example.order.created")
+	fctx.Request.Header.Set("Ce-Subject", "order%20%221%22")
+	fctx.Request.Header.Set("Ce-Time", "2024-05-01T10:00:00Z")
+	fctx.Request.Header.Set("Ce-Tenant", "acme")
+	fctx.Request.Header.SetContentType(MIMEApplicationJSONCharsetUTF8)
+	fctx.Request.SetBodyString(`{"order_id":"1","total":42}`)
+	c := app.AcquireCtx(fctx)
+	defer app.ReleaseCtx(c)
+
+	var event CloudEvent
+	var data testOrderCreated
+	require.NoError(t, c.Bind().CloudEvent(&event, &data))
+	require.Equal(t, CloudEvent{
+		ID:              "1",
+		Source:          "/orders",
+		SpecVersion:     "1.0",
+		Type:            "com.example.order.created",
+		DataContentType: MIMEApplicationJSONCharsetUTF8,
+		Subject:         `order "1"`,
+		Time:            time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Extensions:      map[string]string{"tenant": "acme"},
+	}, event)
+	require.Equal(t, testOrderCreated{OrderID: "1", Total: 42}, data)
+}
+
+// go test -run Test_Bind_CloudEvent_Structured
+func Test_Bind_CloudEvent_Structured(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	tests := []struct {
+		name string
+		body string
+		data any
+		out  any
+	}{
+		{
+			name: "json",
+			body: `{"specversion":"1.0","id":"1","source":"/orders","type":"created","priority":3,"data":{"order_id":"1","total":42}}`,
+			data: &testOrderCreated{},
+			out:  &testOrderCreated{OrderID: "1", Total: 42},
+		},
+		{
+			name: "xml",
+			body: `{"specversion":"1.0","id":"1","source":"/orders","type":"created","datacontenttype":"application/xml","priority":3,"data":"<order><order_id>1</order_id><total>42</total></order>"}`,
+			data: &testOrderCreated{},
+			out:  &testOrderCreated{OrderID: "1", Total: 42},
+		},
+		{
+			name: "base64",
+			body: `{"specversion":"1.0","id":"1","source":"/orders","type":"created","datacontenttype":"application/octet-stream","priority":3,"data_base64":"AQID"}`,
+			data: new([]byte),
+			out:  &[]byte{1, 2, 3},
+		},
+	}
+	for _, tt := range tests {
+		fctx := &fasthttp.RequestCtx{}
+		fctx.Request.Header.SetContentType(MIMEApplicationCloudEventsJSON + "; charset=utf-8")
+		fctx.Request.SetBodyString(tt.body)
+		c := app.AcquireCtx(fctx)
+
+		var event CloudEvent
+		require.NoError(t, c.Bind().CloudEvent(&event, tt.data), tt.name)
+		require.Equal(t, "/orders", event.Source, tt.name)
+		require.Equal(t, map[string]string{"priority": "3"}, event.Extensions, tt.name)
+		require.Equal(t, tt.out, tt.data, tt.name)
+		app.ReleaseCtx(c)
+	}
+}
+
+// go test -run Test_Bind_CloudEvent_Invalid
+func Test_Bind_CloudEvent_Invalid(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	tests := []struct {
+		header map[string]string
+		body   string
+		err    error
+	}{
+		{body: `{"order_id":"1"}`, header: map[string]string{HeaderContentType: MIMEApplicationJSON}, err: ErrCloudEventMissing},
+		{header: map[string]string{"Ce-Specversion": "0.3", "Ce-Id": "1", "Ce-Source": "/", "Ce-Type": "t"}, err: ErrCloudEventInvalid},
+		{header: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1", "Ce-Source": "/"}, err: ErrCloudEventInvalid},
+		{header: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1", "Ce-Source": "/", "Ce-Type": "t", "Ce-Time": "yesterday"}, err: ErrCloudEventInvalid},
+		{header: map[string]string{HeaderContentType: MIMEApplicationCloudEventsJSON}, body: `{"specversion":`, err: ErrCloudEventInvalid},
+		{
+			header: map[string]string{"Ce-Specversion": "1.0", "Ce-Id": "1", "Ce-Source": "/", "Ce-Type": "t", HeaderContentType: "image/png"},
+			body:   "png",
+			err:    ErrCloudEventInvalid,
+		},
+	}
+	for i, tt := range tests {
+		fctx := &fasthttp.RequestCtx{}
+		for key, value := range tt.header {
+			fctx.Request.Header.Set(key, value)
+		}
+		fctx.Request.SetBodyString(tt.body)
+		c := app.AcquireCtx(fctx)
+
+		var event CloudEvent
+		var data testOrderCreated
+		require.ErrorIs(t, c.Bind().Should().CloudEvent(&event, &data), tt.err, i)
+
+		// Must responds with 400 Bad Request
+		err := c.Bind().Must().CloudEvent(&event, &data)
+		var e *Error
+		require.ErrorAs(t, err, &e, i)
+		require.Equal(t, StatusBadRequest, e.Code, i)
+		app.ReleaseCtx(c)
+	}
+}
+
+// go test -run Test_Ctx_SendCloudEvent
+func Test_Ctx_SendCloudEvent(t *testing.T) {
+	t.Parallel()
+	app := New()
+	event := CloudEvent{
+		ID:         "1",
+		Source:     "/orders",
+		Type:       "com.example.order.created",
+		Subject:    "order 1",
+		Time:       time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Extensions: map[string]string{"Tenant": "acme"},
+	}
+	data := testOrderCreated{OrderID: "1", Total: 42}
+
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.NoError(t, c.SendCloudEvent(event, data))
+	require.Equal(t, "1", c.GetRespHeader("Ce-Id"))
+	require.Equal(t, "1.0", c.GetRespHeader("Ce-Specversion"))
+	require.Equal(t, "order%201", c.GetRespHeader("Ce-Subject"))
+	require.Equal(t, "2024-05-01T10:00:00Z", c.GetRespHeader("Ce-Time"))
+	require.Equal(t, "acme", c.GetRespHeader("Ce-Tenant"))
+	require.Equal(t, MIMEApplicationJSON, c.GetRespHeader(HeaderContentType))
+	require.JSONEq(t, `{"order_id":"1","total":42}`, string(c.Response().Body()))
+	app.ReleaseCtx(c)
+
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.NoError(t, c.SendCloudEvent(event, data, CloudEventStructured))
+	require.Equal(t, MIMEApplicationCloudEventsJSON, c.GetRespHeader(HeaderContentType))
+	require.JSONEq(t, `{
+		"specversion": "1.0",
+		"id": "1",
+		"source": "/orders",
+		"type": "com.example.order.created",
+		"subject": "order 1",
+		"time": "2024-05-01T10:00:00Z",
+		"datacontenttype": "application/json",
+		"tenant": "acme",
+		"data": {"order_id": "1", "total": 42}
+	}`, string(c.Response().Body()))
+	app.ReleaseCtx(c)
+
+	// binary data is base64 encoded in the structured mode
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.NoError(t, c.SendCloudEvent(CloudEvent{Source: "/", Type: "t"}, []byte{1, 2, 3}, CloudEventStructured))
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(c.Response().Body(), &fields))
+	require.Equal(t, "AQID", fields["data_base64"])
+	require.Equal(t, MIMEOctetStream, fields["datacontenttype"])
+	require.Len(t, fields["id"], 36)
+	app.ReleaseCtx(c)
+
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.ErrorIs(t, c.SendCloudEvent(CloudEvent{Source: "/"}, nil), ErrCloudEventInvalid)
+	app.ReleaseCtx(c)
+}
+
+// go test -run Test_CloudEvent_RoundTrip
+func Test_CloudEvent_RoundTrip(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Post("/events", func(c Ctx) error {
+		var event CloudEvent
+		var data testOrderCreated
+		if err := c.Bind().CloudEvent(&event, &data); err != nil {
+			return err
+		}
+		data.Total *= 2
+		reply := CloudEvent{Source: "/billing", Type: event.Type + ".billed", Subject: event.Subject}
+		if c.Is("json") {
+			return c.SendCloudEvent(reply, data)
+		}
+		return c.SendCloudEvent(reply, data, CloudEventStructured)
+	})
+
+	for _, mode := range []CloudEventMode{CloudEventBinary, CloudEventStructured} {
+		header, body, err := CloudEvent{Source: "/orders", Type: "created", Subject: "über"}.Encode(testOrderCreated{OrderID: "1", Total: 21}, mode, json.Marshal)
+		require.NoError(t, err)
+		req := httptest.NewRequest(MethodPost, "/events", strings.NewReader(string(body)))
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, StatusOK, resp.StatusCode)
+
+		fctx := &fasthttp.RequestCtx{}
+		for key, values := range resp.Header {
+			fctx.Request.Header.Set(key, values[0])
+		}
+		raw, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		fctx.Request.SetBody(raw)
+		c := app.AcquireCtx(fctx)
+		var event CloudEvent
+		var data testOrderCreated
+		require.NoError(t, c.Bind().CloudEvent(&event, &data))
+		require.Equal(t, "/billing", event.Source)
+		require.Equal(t, "created.billed", event.Type)
+		require.Equal(t, "über", event.Subject)
+		require.Equal(t, 42, data.Total)
+		app.ReleaseCtx(c)
+	}
+}
+
+// go test -run Test_CloudEventEscape
+func Test_CloudEventEscape(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "/orders/1", cloudEventEscape("/orders/1"))
+	require.Equal(t, "a%20%22b%22%2550%25%C3%BC", cloudEventEscape(`a "b"%50%ü`))
+}
@@ -0,0 +1,118 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net"
+	"sync"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/valyala/fasthttp"
+)
+
+// OnCommitted registers a hook which is called after the response was written to the
+// connection successfully, e.g. to publish the domain events of the request, so they
+// aren't published for requests which failed or whose response couldn't be sent.
+// The hooks of responses with a 4xx or 5xx status are discarded, and all hooks are
+// discarded if Config.EnableCommitHooks isn't set.
+//
+// The hooks are called in the order they were registered in a new goroutine, after
+// the handler returned, so they must not access the Ctx.
+func (c *DefaultCtx) OnCommitted(hook func()) {
+	c.committed = append(c.committed, hook)
+}
+
+// getCommitted returns the OnCommitted hooks of the request.
+func (c *DefaultCtx) getCommitted() []func() {
+	return c.committed
+}
+
+// commitTracker calls the OnCommitted hooks of the responses after they were written.
+type commitTracker struct {
+	app     *App
+	pending sync.Map // net.Conn -> *pendingCommit
+}
+
+// pendingCommit are the hooks of a response which isn't written yet.
+type pendingCommit struct {
+	hooks []func()
+	// closing reports if the connection is closed after the response
+	closing bool
+}
+
+// track registers the hooks of the request until its response is written.
+func (t *commitTracker) track(c CustomCtx) {
+	hooks := c.getCommitted()
+	fctx := c.Context()
+	// requests without connection, e.g. of app.Handler in tests, are never written
+	if len(hooks) == 0 || c.Response().StatusCode() >= StatusBadRequest || fctx.Conn() == nil {
+		return
+	}
+	t.pending.Store(fctx.Conn(), &pendingCommit{
+		hooks:   append([]func(){}, hooks...),
+		closing: t.app.config.DisableKeepalive || fctx.Response.ConnectionClose() || fctx.Request.Header.ConnectionClose(),
+	})
+}
+
+// connState is the fasthttp.Server.ConnState hook. A connection becomes idle or hijacked after
+// the response was written. A connection which is closed after a response was written without
+// error if it was closed because of "Connection: close", otherwise the response failed.
+func (t *commitTracker) connState(conn net.Conn, state fasthttp.ConnState) {
+	switch state {
+	case fasthttp.StateIdle, fasthttp.StateHijacked:
+		t.written(conn, true)
+	case fasthttp.StateClosed:
+		t.written(conn, false)
+	default:
+	}
+}
+
+// written calls the hooks of the response of the connection if it was written.
+func (t *commitTracker) written(conn net.Conn, ok bool) {
+	value, loaded := t.pending.LoadAndDelete(conn)
+	if !loaded {
+		return
+	}
+	commit := value.(*pendingCommit) //nolint:forcetypeassert,errcheck // The map only contains *pendingCommit
+	if !ok && (!commit.closing || writeFailed(conn)) {
+		return
+	}
+
+	go func() {
+		for _, hook := range commit.hooks {
+			t.run(hook)
+		}
+	}()
+}
+
+// discard discards the hooks of the response of the connection, which couldn't be written.
+func (t *commitTracker) discard(conn net.Conn) {
+	t.pending.Delete(conn)
+}
+
+// run calls the hook and logs its panic.
+func (t *commitTracker) run(hook func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.app.logw(log.LevelError, "panic in OnCommitted hook", "error", r)
+		}
+	}()
+	hook()
+}
+
+// writeFailed reports if a write to the connection failed. It returns false if the
+// connection wasn't accepted by a listener of the app.
+func writeFailed(conn net.Conn) bool {
+	for {
+		switch wrapped := conn.(type) {
+		case *limitConn:
+			return wrapped.writeFailed.Load()
+		case interface{ NetConn() net.Conn }:
+			conn = wrapped.NetConn()
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package fiber
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// newCommittedApp creates an app whose handler registers an OnCommitted hook,
+// the path is sent on the channel when the hook is called.
+func newCommittedApp(committed chan string, config ...Config) *App {
+	cfg := Config{EnableCommitHooks: true}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	app := New(cfg)
+	app.Get("/:status", func(c Ctx) error {
+		path := c.Path()
+		c.OnCommitted(func() {
+			committed <- path
+		})
+		if c.Params("status") == "error" {
+			return ErrBadRequest
+		}
+		if c.Params("status") == "slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		return c.SendString("ok")
+	})
+	return app
+}
+
+// requireCommitted requires that the hook of the path is called, or not if path is empty.
+func requireCommitted(t *testing.T, committed chan string, path string) {
+	t.Helper()
+	select {
+	case p := <-committed:
+		require.Equal(t, path, p)
+	case <-time.After(200 * time.Millisecond):
+		require.Empty(t, path, "the hook wasn't called")
+	}
+}
+
+// go test -run Test_Ctx_OnCommitted
+func Test_Ctx_OnCommitted(t *testing.T) {
+	t.Parallel()
+	committed := make(chan string, 10)
+	app := newCommittedApp(committed)
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/ok", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	requireCommitted(t, committed, "/ok")
+
+	// the hooks of failed requests are discarded
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/error", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusBadRequest, resp.StatusCode)
+	requireCommitted(t, committed, "")
+
+	// the hooks are discarded without EnableCommitHooks
+	app = newCommittedApp(committed, Config{})
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/ok", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	requireCommitted(t, committed, "")
+}
+
+// go test -run Test_Ctx_OnCommitted_Connection
+func Test_Ctx_OnCommitted_Connection(t *testing.T) {
+	t.Parallel()
+	committed := make(chan string, 10)
+	app := newCommittedApp(committed)
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+	}()
+
+	request := func(raw string) *http.Response {
+		conn, err := ln.Dial()
+		require.NoError(t, err)
+		defer conn.Close() //nolint:errcheck // It is fine to ignore the error here
+		_, err = conn.Write([]byte(raw))
+		require.NoError(t, err)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// keep-alive connection
+	resp := request("GET /ok HTTP/1.1\r\nHost: example.com\r\n\r\n")
+	require.Equal(t, StatusOK, resp.StatusCode)
+	requireCommitted(t, committed, "/ok")
+
+	// the connection is closed after the response
+	resp = request("GET /close HTTP/1.1\r\nHost: example.com\r\nConnection: close\r\n\r\n")
+	require.Equal(t, StatusOK, resp.StatusCode)
+	requireCommitted(t, committed, "/close")
+
+	// the client is gone before the response is written
+	conn, err := ln.Dial()
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("GET /slow HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	requireCommitted(t, committed, "")
+}
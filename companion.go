@@ -0,0 +1,333 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// companionPacketSize is the size of the read buffer of the UDP companion listeners,
+// the maximum size of a UDP packet.
+const companionPacketSize = 64 * 1024
+
+// ConnHandler handles a connection of a TCP companion listener, see ListenTCP.
+// The connection is closed when the handler returns.
+type ConnHandler func(conn net.Conn)
+
+// PacketHandler handles a packet of a UDP companion listener, see ListenUDP.
+// Replies are sent with conn.WriteTo(reply, addr). The packet is only valid until
+// the handler returns.
+type PacketHandler func(conn net.PacketConn, addr net.Addr, packet []byte)
+
+// CompanionStats contains the counters of a companion listener.
+type CompanionStats struct {
+	// Network is "tcp" or "udp".
+	Network string `json:"network"`
+	// Addr is the address of the listener.
+	Addr string `json:"addr"`
+	// Protocol is the ALPN protocol of the connections which are handled by HandleALPN.
+	Protocol string `json:"protocol,omitempty"`
+	// Open is the number of currently open TCP connections.
+	Open int64 `json:"open"`
+	// Accepted is the total number of accepted TCP connections.
+	Accepted uint64 `json:"accepted"`
+	// Packets is the total number of received UDP packets.
+	Packets uint64 `json:"packets"`
+	// BytesIn is the number of bytes which were received.
+	BytesIn uint64 `json:"bytes_in"`
+	// BytesOut is the number of bytes which were sent.
+	BytesOut uint64 `json:"bytes_out"`
+	// Panics is the number of panics of the handler, which were recovered.
+	Panics uint64 `json:"panics"`
+}
+
+// companionListener is a TCP or UDP listener which is managed by the app.
+type companionListener struct {
+	listener   net.Listener
+	packetConn net.PacketConn
+	conns      map[net.Conn]struct{}
+	network    string
+	addr       string
+	protocol   string
+	wg         sync.WaitGroup
+	mutex      sync.Mutex
+	open       atomic.Int64
+	accepted   atomic.Uint64
+	packets    atomic.Uint64
+	bytesIn    atomic.Uint64
+	bytesOut   atomic.Uint64
+	panics     atomic.Uint64
+	closed     atomic.Bool
+}
+
+// companionListeners are the companion listeners of the app.
+type companionListeners struct {
+	listeners []*companionListener
+	mutex     sync.Mutex
+}
+
+// ListenTCP starts a TCP listener next to the HTTP server, e.g. for TCP health probes or
+// custom line protocols. Each connection is handled by the handler in its own goroutine.
+// The listener shares the lifecycle of the app: it is closed by Shutdown, which waits for
+// the open connections until its context is done and closes them then. Panics of the
+// handler are recovered and logged, the counters are reported by Stats.
+//
+// It returns when the listener is bound, it doesn't block like Listen. In prefork child
+// processes it does nothing, the companion listeners run in the master process.
+//
+//	app.ListenTCP(":9000", func(conn net.Conn) {
+//	    _, _ = conn.Write([]byte("OK\n"))
+//	})
+func (app *App) ListenTCP(addr string, handler ConnHandler) error {
+	if IsChild() {
+		return nil
+	}
+	ln, err := net.Listen(NetworkTCP, addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	cl := &companionListener{listener: ln, network: NetworkTCP, addr: ln.Addr().String(), conns: make(map[net.Conn]struct{})}
+	app.companions.add(cl)
+	app.logw(log.LevelInfo, "companion listener started", "network", cl.network, "addr", cl.addr)
+
+	cl.acceptConns(app, handler)
+	return nil
+}
+
+// ListenUDP starts a UDP listener next to the HTTP server, e.g. for metrics or custom
+// protocols. The packets are handled by the handler one after the other, in the order
+// of their arrival. The listener shares the lifecycle of the app like the listeners of ListenTCP.
+//
+//	app.ListenUDP(":8125", func(conn net.PacketConn, addr net.Addr, packet []byte) {
+//	    _, _ = conn.WriteTo(packet, addr)
+//	})
+func (app *App) ListenUDP(addr string, handler PacketHandler) error {
+	if IsChild() {
+		return nil
+	}
+	pc, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	cl := &companionListener{packetConn: pc, network: "udp", addr: pc.LocalAddr().String()}
+	app.companions.add(cl)
+	app.logw(log.LevelInfo, "companion listener started", "network", cl.network, "addr", cl.addr)
+
+	conn := &companionPacketConn{PacketConn: pc, listener: cl}
+	cl.wg.Add(1)
+	go func() {
+		defer cl.wg.Done()
+		buf := make([]byte, companionPacketSize)
+		for {
+			n, from, err := pc.ReadFrom(buf)
+			if err != nil {
+				if cl.closed.Load() {
+					return
+				}
+				app.logw(log.LevelError, "companion listener failed to read a packet", "network", cl.network, "addr", cl.addr, "error", err)
+				continue
+			}
+			cl.packets.Add(1)
+			cl.bytesIn.Add(uint64(n))
+			cl.handle(app, func() {
+				handler(conn, from, buf[:n])
+			})
+		}
+	}()
+	return nil
+}
+
+// acceptConns accepts the connections of the listener in its own goroutine until it is closed.
+func (cl *companionListener) acceptConns(app *App, handler ConnHandler) {
+	cl.wg.Add(1)
+	go func() {
+		defer cl.wg.Done()
+		for {
+			conn, err := cl.listener.Accept()
+			if err != nil {
+				if !cl.closed.Load() {
+					app.logw(log.LevelError, "companion listener failed", "network", cl.network, "addr", cl.addr, "error", err)
+				}
+				return
+			}
+			cl.accepted.Add(1)
+			cl.serveConn(app, &companionConn{Conn: conn, listener: cl}, handler)
+		}
+	}()
+}
+
+// serveConn handles the connection in its own goroutine and closes it afterward.
+func (cl *companionListener) serveConn(app *App, conn net.Conn, handler ConnHandler) {
+	cl.mutex.Lock()
+	cl.conns[conn] = struct{}{}
+	cl.mutex.Unlock()
+	cl.open.Add(1)
+
+	cl.wg.Add(1)
+	go func() {
+		defer cl.wg.Done()
+		defer func() {
+			cl.mutex.Lock()
+			delete(cl.conns, conn)
+			cl.mutex.Unlock()
+			cl.open.Add(-1)
+			_ = conn.Close() //nolint:errcheck // the connection may be closed by the handler
+		}()
+		cl.handle(app, func() {
+			handler(conn)
+		})
+	}()
+}
+
+// handle calls the handler and recovers its panics.
+func (cl *companionListener) handle(app *App, handler func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			cl.panics.Add(1)
+			app.logw(log.LevelError, "companion handler panicked", "network", cl.network, "addr", cl.addr, "panic", r)
+		}
+	}()
+	handler()
+}
+
+// close stops accepting connections and packets.
+func (cl *companionListener) close() {
+	cl.closed.Store(true)
+	if cl.listener != nil {
+		_ = cl.listener.Close() //nolint:errcheck // the listener is closed anyway
+	}
+}
+
+// wait waits for the open connections until the context is done, then they're closed.
+// The UDP listeners are closed after the current packet is handled.
+func (cl *companionListener) wait(ctx context.Context) error {
+	if cl.packetConn != nil {
+		_ = cl.packetConn.Close() //nolint:errcheck // the listener is closed anyway
+	}
+
+	done := make(chan struct{})
+	go func() {
+		cl.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		cl.mutex.Lock()
+		for conn := range cl.conns {
+			_ = conn.Close() //nolint:errcheck // the connection is closed by force
+		}
+		cl.mutex.Unlock()
+		<-done
+		return fmt.Errorf("companion listener %s: %w", cl.addr, ctx.Err())
+	}
+}
+
+// stats returns the counters of the listener.
+func (cl *companionListener) stats() CompanionStats {
+	return CompanionStats{
+		Network:  cl.network,
+		Addr:     cl.addr,
+		Protocol: cl.protocol,
+		Open:     cl.open.Load(),
+		Accepted: cl.accepted.Load(),
+		Packets:  cl.packets.Load(),
+		BytesIn:  cl.bytesIn.Load(),
+		BytesOut: cl.bytesOut.Load(),
+		Panics:   cl.panics.Load(),
+	}
+}
+
+// add adds a started listener.
+func (cls *companionListeners) add(cl *companionListener) {
+	cls.mutex.Lock()
+	cls.listeners = append(cls.listeners, cl)
+	cls.mutex.Unlock()
+}
+
+// all returns the started listeners.
+func (cls *companionListeners) all() []*companionListener {
+	cls.mutex.Lock()
+	defer cls.mutex.Unlock()
+	return append([]*companionListener(nil), cls.listeners...)
+}
+
+// close stops all listeners from accepting connections and packets.
+func (cls *companionListeners) close() {
+	for _, cl := range cls.all() {
+		cl.close()
+	}
+}
+
+// wait waits for the connections of all listeners, the listeners are removed.
+func (cls *companionListeners) wait(ctx context.Context) error {
+	listeners := cls.all()
+	cls.mutex.Lock()
+	cls.listeners = nil
+	cls.mutex.Unlock()
+
+	var errs []error
+	for _, cl := range listeners {
+		errs = append(errs, cl.wait(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// stats returns the counters of all listeners.
+func (cls *companionListeners) stats() []CompanionStats {
+	listeners := cls.all()
+	if len(listeners) == 0 {
+		return nil
+	}
+	stats := make([]CompanionStats, 0, len(listeners))
+	for _, cl := range listeners {
+		stats = append(stats, cl.stats())
+	}
+	return stats
+}
+
+// companionConn counts the bytes of a TCP connection.
+type companionConn struct {
+	net.Conn
+	listener *companionListener
+}
+
+// Read reads from the connection and counts the received bytes.
+func (c *companionConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.listener.bytesIn.Add(uint64(n)) //nolint:gosec // n is never negative
+	return n, err                     //nolint:wrapcheck // the error of the connection is returned unchanged
+}
+
+// Write writes to the connection and counts the sent bytes.
+func (c *companionConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.listener.bytesOut.Add(uint64(n)) //nolint:gosec // n is never negative
+	return n, err                      //nolint:wrapcheck // the error of the connection is returned unchanged
+}
+
+// companionPacketConn counts the bytes which are sent by a UDP listener.
+type companionPacketConn struct {
+	net.PacketConn
+	listener *companionListener
+}
+
+// WriteTo writes the packet and counts the sent bytes.
+func (c *companionPacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	n, err := c.PacketConn.WriteTo(b, addr)
+	c.listener.bytesOut.Add(uint64(n)) //nolint:gosec // n is never negative
+	return n, err                      //nolint:wrapcheck // the error of the connection is returned unchanged
+}
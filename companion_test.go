@@ -0,0 +1,114 @@
+package fiber
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_App_ListenTCP
+func Test_App_ListenTCP(t *testing.T) {
+	t.Parallel()
+	app := New()
+	require.NoError(t, app.ListenTCP("127.0.0.1:0", func(conn net.Conn) {
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return
+		}
+		if line == "panic\n" {
+			panic("companion panic")
+		}
+		_, _ = conn.Write([]byte("echo: " + line)) //nolint:errcheck // It's a test
+	}))
+	addr := app.Stats().Companions[0].Addr
+
+	conn, err := net.Dial(NetworkTCP, addr)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("ping\n"))
+	require.NoError(t, err)
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "echo: ping\n", reply)
+	require.NoError(t, conn.Close())
+
+	// panics are recovered, the connection is closed
+	conn, err = net.Dial(NetworkTCP, addr)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("panic\n"))
+	require.NoError(t, err)
+	_, err = bufio.NewReader(conn).ReadString('\n')
+	require.Error(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return app.Stats().Companions[0].Open == 0
+	}, time.Second, 10*time.Millisecond)
+	stats := app.Stats().Companions[0]
+	require.Equal(t, "tcp", stats.Network)
+	require.Equal(t, uint64(2), stats.Accepted)
+	require.Equal(t, uint64(1), stats.Panics)
+	require.Equal(t, uint64(11), stats.BytesIn)
+	require.Equal(t, uint64(11), stats.BytesOut)
+
+	require.NoError(t, app.Shutdown())
+	_, err = net.Dial(NetworkTCP, addr)
+	require.Error(t, err)
+	require.Empty(t, app.Stats().Companions)
+}
+
+// go test -run Test_App_ListenTCP_Shutdown
+func Test_App_ListenTCP_Shutdown(t *testing.T) {
+	t.Parallel()
+	app := New()
+	accepted := make(chan struct{})
+	require.NoError(t, app.ListenTCP("127.0.0.1:0", func(conn net.Conn) {
+		close(accepted)
+		// the connection is blocked until it is closed by the shutdown
+		_, _ = conn.Read(make([]byte, 1)) //nolint:errcheck // It's a test
+	}))
+
+	conn, err := net.Dial(NetworkTCP, app.Stats().Companions[0].Addr)
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck // It's a test
+	<-accepted
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = app.ShutdownWithContext(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.Error(t, app.ListenTCP("invalid:address:0", func(net.Conn) {}))
+}
+
+// go test -run Test_App_ListenUDP
+func Test_App_ListenUDP(t *testing.T) {
+	t.Parallel()
+	app := New()
+	require.NoError(t, app.ListenUDP("127.0.0.1:0", func(conn net.PacketConn, addr net.Addr, packet []byte) {
+		_, _ = conn.WriteTo(append([]byte("echo: "), packet...), addr) //nolint:errcheck // It's a test
+	}))
+	stats := app.Stats().Companions[0]
+	require.Equal(t, "udp", stats.Network)
+
+	conn, err := net.Dial("udp", stats.Addr)
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck // It's a test
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	reply := make([]byte, 64)
+	n, err := conn.Read(reply)
+	require.NoError(t, err)
+	require.Equal(t, "echo: ping", string(reply[:n]))
+
+	stats = app.Stats().Companions[0]
+	require.Equal(t, uint64(1), stats.Packets)
+	require.Equal(t, uint64(4), stats.BytesIn)
+	require.Equal(t, uint64(10), stats.BytesOut)
+
+	require.NoError(t, app.Shutdown())
+}
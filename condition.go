@@ -0,0 +1,60 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+// Condition is an argument of Use which executes the handlers of the call only for some
+// of the requests, the other requests skip them. It is created with Skip or Only.
+//
+//	app.Use(fiber.Skip(skip.Paths("/health", "/metrics")), logger.New())
+type Condition struct {
+	predicate func(c Ctx) bool
+	skip      bool
+}
+
+// Skip returns a Condition of Use which skips the handlers of the call if the predicate is true.
+func Skip(predicate func(c Ctx) bool) Condition {
+	return Condition{predicate: predicate, skip: true}
+}
+
+// Only returns a Condition of Use which only executes the handlers of the call if the predicate is true.
+func Only(predicate func(c Ctx) bool) Condition {
+	return Condition{predicate: predicate}
+}
+
+// Skips reports if the handlers are skipped for the request.
+func (cond Condition) Skips(c Ctx) bool {
+	if cond.predicate == nil {
+		return false
+	}
+	return cond.predicate(c) == cond.skip
+}
+
+// Wrap returns a handler which executes the handler if the condition applies to the request,
+// otherwise it executes the next handler.
+func (cond Condition) Wrap(handler Handler) Handler {
+	if cond.predicate == nil {
+		return handler
+	}
+	return func(c Ctx) error {
+		if cond.Skips(c) {
+			return c.Next()
+		}
+		return handler(c)
+	}
+}
+
+// applyConditions wraps the handlers of a call of Use with its conditions.
+func applyConditions(handlers []Handler, conditions []Condition) []Handler {
+	if len(conditions) == 0 {
+		return handlers
+	}
+	for i, handler := range handlers {
+		for _, cond := range conditions {
+			handler = cond.Wrap(handler)
+		}
+		handlers[i] = handler
+	}
+	return handlers
+}
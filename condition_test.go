@@ -0,0 +1,62 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_App_Use_Condition
+func Test_App_Use_Condition(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	teapot := func(Ctx) error {
+		return ErrTeapot
+	}
+	isInternal := func(c Ctx) bool {
+		return c.Get("X-Internal") == "1"
+	}
+	app.Use(Skip(isInternal), teapot)
+	grp := app.Group("/api")
+	grp.Use(Only(isInternal), func(c Ctx) error {
+		c.Set("X-Only", "1")
+		return c.Next()
+	})
+	grp.Get("/", testSimpleHandler)
+
+	request := func(internal bool) (int, string) {
+		req := httptest.NewRequest(MethodGet, "/api", nil)
+		if internal {
+			req.Header.Set("X-Internal", "1")
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode, resp.Header.Get("X-Only")
+	}
+
+	status, only := request(false)
+	require.Equal(t, StatusTeapot, status)
+	require.Empty(t, only)
+
+	status, only = request(true)
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "1", only)
+}
+
+// go test -run Test_Condition_Skips
+func Test_Condition_Skips(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	yes := func(Ctx) bool { return true }
+	require.True(t, Skip(yes).Skips(c))
+	require.False(t, Only(yes).Skips(c))
+	require.True(t, Only(func(Ctx) bool { return false }).Skips(c))
+	require.False(t, Skip(nil).Skips(c))
+	require.False(t, Only(nil).Skips(c))
+}
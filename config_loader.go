@@ -0,0 +1,506 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/valyala/fasthttp"
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig holds the settings which can be loaded from a config file
+// and environment variables by LoadConfig.
+//
+//	{
+//	  "app": {"app_name": "api", "body_limit": 1048576, "read_timeout": "5s"},
+//	  "listen": {"enable_prefork": true},
+//	  "log_level": "info",
+//	  "maintenance": false
+//	}
+type FileConfig struct {
+	// App holds the settings for fiber.New.
+	App Config `json:"app"`
+
+	// Listen holds the settings for app.Listen.
+	Listen ListenConfig `json:"listen"`
+
+	// LogLevel is the level of the global logger and the app, e.g. "debug" or "warn".
+	//
+	// Default: ""
+	LogLevel string `json:"log_level"`
+
+	// Maintenance enables the maintenance mode of the app.
+	//
+	// Default: false
+	Maintenance bool `json:"maintenance"`
+
+	// Keys of the fields which are set by the file or the environment, e.g. "app.body_limit"
+	fields map[string]bool
+}
+
+// ConfigDecoder decodes the content of a config file into the given value.
+// The value is always a *map[string]any.
+type ConfigDecoder = func(data []byte, v any) error
+
+// EnvConfigPrefix is the prefix of the environment variables which override the
+// values of the config file. The name of the variable is built from the prefix,
+// the section and the key of the field, e.g. FIBER_APP_BODY_LIMIT,
+// FIBER_LISTEN_ENABLE_PREFORK or FIBER_LOG_LEVEL.
+const EnvConfigPrefix = "FIBER_"
+
+// DefaultConfigWatchInterval is the default interval in which WatchConfig checks the config file for changes.
+const DefaultConfigWatchInterval = time.Second
+
+// Config loader errors
+var (
+	// ErrConfigFormatNotSupported is returned when there is no decoder registered for the file extension.
+	ErrConfigFormatNotSupported = errors.New("config: no decoder registered for the file extension")
+	// ErrConfigFieldNotSupported is returned when a value is set for a field which can't be loaded from a file.
+	ErrConfigFieldNotSupported = errors.New("config: field can't be loaded from a file")
+	// ErrConfigFieldNotReloadable is returned when a watched config file changes a field which can't be changed at runtime.
+	ErrConfigFieldNotReloadable = errors.New("config: field can't be changed at runtime")
+)
+
+// reloadableConfigFields are the keys of the fields which WatchConfig applies at runtime.
+var reloadableConfigFields = map[string]bool{
+	"app.body_limit":                       true,
+	"app.read_timeout":                     true,
+	"app.write_timeout":                    true,
+	"app.keep_alive.tcp_keepalive_period":  true,
+	"app.keep_alive.max_requests_per_conn": true,
+	"app.keep_alive.max_conn_age":          true,
+	"log_level":                            true,
+	"maintenance":                          true,
+}
+
+// reloadableConfig holds the settings of the requests which can be changed at runtime.
+type reloadableConfig struct {
+	bodyLimit    int
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+}
+
+var (
+	configDecodersMutex sync.RWMutex
+	configDecoders      = map[string]ConfigDecoder{
+		".json": json.Unmarshal,
+		".yaml": yaml.Unmarshal,
+		".yml":  yaml.Unmarshal,
+		".toml": toml.Unmarshal,
+	}
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// RegisterConfigDecoder registers a decoder for config files with the given extension.
+// JSON (".json"), YAML (".yaml", ".yml") and TOML (".toml") are supported by default,
+// other formats can be added and the default decoders replaced:
+//
+//	fiber.RegisterConfigDecoder(".hcl", hclDecode)
+func RegisterConfigDecoder(ext string, decoder ConfigDecoder) {
+	configDecodersMutex.Lock()
+	configDecoders[strings.ToLower(ext)] = decoder
+	configDecodersMutex.Unlock()
+}
+
+// LoadConfig loads the config file from the given path and applies the
+// environment variable overrides afterwards. The decoder is chosen by the file extension.
+func LoadConfig(path string) (FileConfig, error) {
+	cfg := FileConfig{fields: make(map[string]bool)}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	configDecodersMutex.RLock()
+	decoder, ok := configDecoders[ext]
+	configDecodersMutex.RUnlock()
+	if !ok {
+		return cfg, fmt.Errorf("%w: %q", ErrConfigFormatNotSupported, ext)
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return cfg, fmt.Errorf("config: failed to read file: %w", err)
+	}
+
+	values := make(map[string]any)
+	if err := decoder(data, &values); err != nil {
+		return cfg, fmt.Errorf("config: failed to decode %q: %w", path, err)
+	}
+
+	target := reflect.ValueOf(&cfg).Elem()
+	if err := setConfigValues(target, values, "", cfg.fields); err != nil {
+		return cfg, err
+	}
+	if err := setConfigEnvValues(target, EnvConfigPrefix, "", cfg.fields); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// WatchConfig watches the config file at the given path and re-applies the
+// settings which are safe to change at runtime whenever the file changes:
+// BodyLimit, ReadTimeout, WriteTimeout, KeepAlive, the log level and the maintenance
+// mode. Only the fields which are set in the file are applied, the log level and the
+// maintenance mode only when their value in the file changed, so they don't revert the
+// changes of SetLogLevel and SetMaintenanceMode. A file which changes other fields
+// is rejected with ErrConfigFieldNotReloadable. The OnConfigChange hooks are executed
+// after each reload.
+//
+// The file is applied once before WatchConfig returns. The returned function stops
+// the watcher, it is also stopped automatically on shutdown.
+func (app *App) WatchConfig(path string, interval ...time.Duration) (func(), error) {
+	every := DefaultConfigWatchInterval
+	if len(interval) > 0 && interval[0] > 0 {
+		every = interval[0]
+	}
+
+	current, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := app.applyFileConfig(current, nil); err != nil {
+		return nil, err
+	}
+
+	modTime, size := configFileState(path)
+
+	done := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+
+	go func() {
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				newModTime, newSize := configFileState(path)
+				if newModTime.Equal(modTime) && newSize == size {
+					continue
+				}
+				modTime, size = newModTime, newSize
+
+				cfg, err := LoadConfig(path)
+				if err == nil {
+					err = app.applyFileConfig(cfg, &current)
+				}
+				if err != nil {
+					app.logw(log.LevelError, "config: failed to reload", "path", path, "error", err)
+					continue
+				}
+				current = cfg
+			}
+		}
+	}()
+
+	app.hooks.OnShutdown(func() error {
+		stop()
+		return nil
+	})
+
+	return stop, nil
+}
+
+// applyFileConfig applies the fields of the config which are set and safe to change at runtime.
+// The fields which can't be changed must be equal to the running config, the fields of the
+// listen section to the previous config. Nothing is applied if a field is rejected.
+func (app *App) applyFileConfig(cfg FileConfig, prev *FileConfig) error {
+	for key := range cfg.fields {
+		if reloadableConfigFields[key] {
+			continue
+		}
+		section, field, _ := strings.Cut(key, ".")
+		changed := false
+		switch {
+		case section == "app":
+			changed = !reflect.DeepEqual(configField(reflect.ValueOf(cfg.App), field), configField(reflect.ValueOf(app.config), field))
+		case section == "listen" && prev != nil:
+			changed = !reflect.DeepEqual(configField(reflect.ValueOf(cfg.Listen), field), configField(reflect.ValueOf(prev.Listen), field))
+		}
+		if changed {
+			return fmt.Errorf("%w: %q", ErrConfigFieldNotReloadable, key)
+		}
+	}
+
+	var level log.Level
+	if cfg.fields["log_level"] {
+		var err error
+		if level, err = log.ParseLevel(cfg.LogLevel); err != nil {
+			return fmt.Errorf("config: invalid log level: %w", err)
+		}
+	}
+
+	app.mutex.Lock()
+	before := app.Config()
+
+	reloadable := *app.reloadable.Load()
+	if cfg.fields["app.body_limit"] {
+		reloadable.bodyLimit = cfg.App.BodyLimit
+		if reloadable.bodyLimit == 0 {
+			reloadable.bodyLimit = DefaultBodyLimit
+		}
+	}
+	if cfg.fields["app.read_timeout"] {
+		reloadable.readTimeout = cfg.App.ReadTimeout
+	}
+	if cfg.fields["app.write_timeout"] {
+		reloadable.writeTimeout = cfg.App.WriteTimeout
+	}
+	app.reloadable.Store(&reloadable)
+
+	keepAlive := app.KeepAlive()
+	if cfg.fields["app.keep_alive.tcp_keepalive_period"] {
+		keepAlive.TCPKeepalivePeriod = cfg.App.KeepAlive.TCPKeepalivePeriod
+	}
+	if cfg.fields["app.keep_alive.max_requests_per_conn"] {
+		keepAlive.MaxRequestsPerConn = cfg.App.KeepAlive.MaxRequestsPerConn
+	}
+	if cfg.fields["app.keep_alive.max_conn_age"] {
+		keepAlive.MaxConnAge = cfg.App.KeepAlive.MaxConnAge
+	}
+	app.SetKeepAlive(keepAlive)
+
+	after := app.Config()
+	app.mutex.Unlock()
+
+	// the runtime changes of the log level and the maintenance mode are kept until the file changes them
+	if cfg.fields["log_level"] && (prev == nil || prev.LogLevel != cfg.LogLevel) {
+		log.SetLevel(level)
+		app.SetLogLevel(level)
+	}
+	if cfg.fields["maintenance"] && (prev == nil || prev.Maintenance != cfg.Maintenance) {
+		app.SetMaintenanceMode(cfg.Maintenance)
+	}
+
+	app.hooks.executeOnConfigChangeHooks(before, after)
+	return nil
+}
+
+// requestConfig returns the limits of a request which differ from the limits of the server,
+// because they were changed by WatchConfig. The zero values keep the limits of the server.
+func (app *App) requestConfig(*fasthttp.RequestHeader) fasthttp.RequestConfig {
+	var cfg fasthttp.RequestConfig
+	reloadable := app.reloadable.Load()
+	if reloadable.bodyLimit != app.config.BodyLimit {
+		cfg.MaxRequestBodySize = reloadable.bodyLimit
+	}
+	if reloadable.readTimeout != app.config.ReadTimeout {
+		cfg.ReadTimeout = reloadable.readTimeout
+	}
+	if reloadable.writeTimeout != app.config.WriteTimeout {
+		cfg.WriteTimeout = reloadable.writeTimeout
+	}
+	return cfg
+}
+
+// configField returns the value of the field of the struct with the given key, e.g. "keep_alive.max_conn_age".
+func configField(target reflect.Value, key string) any {
+	name, rest, nested := strings.Cut(key, ".")
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		if !field.IsExported() || configKey(field) != name {
+			continue
+		}
+		if nested {
+			return configField(target.Field(i), rest)
+		}
+		return target.Field(i).Interface()
+	}
+	return nil
+}
+
+// configFileState returns the modification time and the size of the file.
+func configFileState(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, -1
+	}
+	return info.ModTime(), info.Size()
+}
+
+// configKey returns the key of a struct field in a config file.
+// The json tag is used if present, otherwise the field name is converted to snake case.
+func configKey(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" {
+		return strings.Split(tag, ",")[0]
+	}
+
+	var b strings.Builder
+	for i, r := range field.Name {
+		if unicode.IsUpper(r) {
+			if i > 0 && !unicode.IsUpper(rune(field.Name[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// setConfigValues sets the fields of the given struct from the decoded values.
+// The keys of the fields which are set are added to the fields.
+func setConfigValues(target reflect.Value, values map[string]any, path string, fields map[string]bool) error {
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		key := configKey(field)
+		if !field.IsExported() || key == "-" {
+			continue
+		}
+
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		if err := setConfigValue(target.Field(i), value, path+key, fields); err != nil {
+			return err
+		}
+		if _, nested := value.(map[string]any); !nested {
+			fields[path+key] = true
+		}
+	}
+
+	return nil
+}
+
+// setConfigEnvValues sets the fields of the given struct from the environment variables.
+// The keys of the fields which are set are added to the fields.
+func setConfigEnvValues(target reflect.Value, prefix, path string, fields map[string]bool) error {
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		key := configKey(field)
+		if !field.IsExported() || key == "-" {
+			continue
+		}
+
+		name := prefix + strings.ToUpper(key)
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			if err := setConfigEnvValues(target.Field(i), name+"_", path+key+".", fields); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setConfigValue(target.Field(i), value, name, fields); err != nil {
+			return err
+		}
+		fields[path+key] = true
+	}
+
+	return nil
+}
+
+// setConfigValue converts the decoded value to the type of the field and sets it.
+func setConfigValue(field reflect.Value, value any, path string, fields map[string]bool) error {
+	invalid := func(err error) error {
+		return fmt.Errorf("config: invalid value %q for %q: %w", configString(value), path, err)
+	}
+
+	switch {
+	case field.Type() == durationType:
+		d, err := toDuration(value)
+		if err != nil {
+			return invalid(err)
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(configString(value))
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(configString(value))
+		if err != nil {
+			return invalid(err)
+		}
+		field.SetBool(b)
+	case field.CanInt():
+		n, err := strconv.ParseInt(configString(value), 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		field.SetInt(n)
+	case field.CanUint():
+		n, err := strconv.ParseUint(configString(value), 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		field.SetUint(n)
+	case field.CanFloat():
+		f, err := strconv.ParseFloat(configString(value), 64)
+		if err != nil {
+			return invalid(err)
+		}
+		field.SetFloat(f)
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		var items []string
+		switch v := value.(type) {
+		case []any:
+			for _, item := range v {
+				items = append(items, configString(item))
+			}
+		case string:
+			for _, item := range strings.Split(v, ",") {
+				items = append(items, strings.TrimSpace(item))
+			}
+		default:
+			return invalid(ErrConfigFieldNotSupported)
+		}
+		field.Set(reflect.ValueOf(items))
+	case field.Kind() == reflect.Struct:
+		values, ok := value.(map[string]any)
+		if !ok {
+			return invalid(ErrConfigFieldNotSupported)
+		}
+		return setConfigValues(field, values, path+".", fields)
+	default:
+		return fmt.Errorf("%w: %q", ErrConfigFieldNotSupported, path)
+	}
+
+	return nil
+}
+
+// configString converts a decoded value to its string representation.
+func configString(value any) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// toDuration converts a duration string like "5s" or a number of nanoseconds to time.Duration.
+func toDuration(value any) (time.Duration, error) {
+	s := configString(value)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(n), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse duration: %w", err)
+	}
+	return d, nil
+}
@@ -0,0 +1,270 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "fiber.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// go test -run Test_LoadConfig
+func Test_LoadConfig(t *testing.T) {
+	t.Parallel()
+
+	path := writeTestConfig(t, t.TempDir(), `{
+		"app": {
+			"app_name": "api",
+			"body_limit": 1048576,
+			"read_timeout": "5s",
+			"trusted_proxies": ["10.0.0.1", "10.0.0.2"],
+			"stream_request_body": true,
+			"color_scheme": {"red": "R"}
+		},
+		"listen": {"listener_network": "tcp6", "enable_prefork": true},
+		"log_level": "warn",
+		"maintenance": true
+	}`)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, "api", cfg.App.AppName)
+	require.Equal(t, 1048576, cfg.App.BodyLimit)
+	require.Equal(t, 5*time.Second, cfg.App.ReadTimeout)
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, cfg.App.TrustedProxies)
+	require.True(t, cfg.App.StreamRequestBody)
+	require.Equal(t, "R", cfg.App.ColorScheme.Red)
+	require.Equal(t, NetworkTCP6, cfg.Listen.ListenerNetwork)
+	require.True(t, cfg.Listen.EnablePrefork)
+	require.Equal(t, "warn", cfg.LogLevel)
+	require.True(t, cfg.Maintenance)
+}
+
+// go test -run Test_LoadConfig_Formats
+func Test_LoadConfig_Formats(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"fiber.yaml": `
+app:
+  app_name: api
+  body_limit: 1048576
+  read_timeout: 5s
+  trusted_proxies: [10.0.0.1, 10.0.0.2]
+  keep_alive:
+    max_requests_per_conn: 100
+listen:
+  enable_prefork: true
+log_level: warn
+maintenance: true
+`,
+		"fiber.toml": `
+log_level = "warn"
+maintenance = true
+
+[app]
+app_name = "api"
+body_limit = 1048576
+read_timeout = "5s"
+trusted_proxies = ["10.0.0.1", "10.0.0.2"]
+
+[app.keep_alive]
+max_requests_per_conn = 100
+
+[listen]
+enable_prefork = true
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		cfg, err := LoadConfig(path)
+		require.NoError(t, err, name)
+		require.Equal(t, "api", cfg.App.AppName, name)
+		require.Equal(t, 1048576, cfg.App.BodyLimit, name)
+		require.Equal(t, 5*time.Second, cfg.App.ReadTimeout, name)
+		require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, cfg.App.TrustedProxies, name)
+		require.Equal(t, 100, cfg.App.KeepAlive.MaxRequestsPerConn, name)
+		require.True(t, cfg.Listen.EnablePrefork, name)
+		require.Equal(t, "warn", cfg.LogLevel, name)
+		require.True(t, cfg.Maintenance, name)
+		require.True(t, cfg.fields["app.keep_alive.max_requests_per_conn"], name)
+	}
+}
+
+// go test -run Test_LoadConfig_Env
+func Test_LoadConfig_Env(t *testing.T) {
+	t.Setenv("FIBER_APP_BODY_LIMIT", "2048")
+	t.Setenv("FIBER_APP_IDLE_TIMEOUT", "1m")
+	t.Setenv("FIBER_APP_REQUEST_METHODS", "GET, POST")
+	t.Setenv("FIBER_LISTEN_DISABLE_STARTUP_MESSAGE", "true")
+
+	path := writeTestConfig(t, t.TempDir(), `{"app": {"body_limit": 1024}}`)
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, 2048, cfg.App.BodyLimit)
+	require.Equal(t, time.Minute, cfg.App.IdleTimeout)
+	require.Equal(t, []string{MethodGet, MethodPost}, cfg.App.RequestMethods)
+	require.True(t, cfg.Listen.DisableStartupMessage)
+
+	t.Setenv("FIBER_APP_BODY_LIMIT", "big")
+	_, err = LoadConfig(path)
+	require.ErrorContains(t, err, "FIBER_APP_BODY_LIMIT")
+}
+
+// go test -run Test_LoadConfig_Errors
+func Test_LoadConfig_Errors(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	_, err := LoadConfig(filepath.Join(dir, "fiber.ini"))
+	require.ErrorIs(t, err, ErrConfigFormatNotSupported)
+
+	_, err = LoadConfig(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+
+	path := writeTestConfig(t, dir, `{"app": {"struct_validator": "x"}}`)
+	_, err = LoadConfig(path)
+	require.ErrorIs(t, err, ErrConfigFieldNotSupported)
+
+	path = writeTestConfig(t, dir, `{"app": {"read_timeout": "soon"}}`)
+	_, err = LoadConfig(path)
+	require.ErrorContains(t, err, "app.read_timeout")
+}
+
+// go test -run Test_RegisterConfigDecoder
+func Test_RegisterConfigDecoder(t *testing.T) {
+	t.Parallel()
+
+	RegisterConfigDecoder(".test", func(_ []byte, v any) error {
+		values, ok := v.(*map[string]any)
+		require.True(t, ok)
+		(*values)["app"] = map[string]any{"app_name": "decoded"}
+		return nil
+	})
+
+	path := filepath.Join(t.TempDir(), "fiber.test")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, "decoded", cfg.App.AppName)
+}
+
+// go test -run Test_App_WatchConfig
+func Test_App_WatchConfig(t *testing.T) {
+	t.Parallel()
+	app := New()
+	dir := t.TempDir()
+
+	changes := make(chan Config, 2)
+	app.Hooks().OnConfigChange(func(_, next Config) error {
+		changes <- next
+		return nil
+	})
+
+	path := writeTestConfig(t, dir, `{"app": {"body_limit": 1024}}`)
+	stop, err := app.WatchConfig(path, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer stop()
+
+	require.Equal(t, 1024, (<-changes).BodyLimit)
+	require.False(t, app.MaintenanceMode())
+
+	writeTestConfig(t, dir, `{"app": {"body_limit": 4096, "read_timeout": "2s"}, "maintenance": true}`)
+
+	select {
+	case next := <-changes:
+		require.Equal(t, 4096, next.BodyLimit)
+		require.Equal(t, 2*time.Second, next.ReadTimeout)
+		require.True(t, app.MaintenanceMode())
+	case <-time.After(time.Second):
+		t.Fatal("config change was not detected")
+	}
+}
+
+// go test -run Test_App_WatchConfig_Fields
+func Test_App_WatchConfig_Fields(t *testing.T) {
+	t.Parallel()
+	app := New(Config{BodyLimit: 16, WriteTimeout: time.Second, AppName: "api"})
+	app.Post("/", func(c Ctx) error {
+		return c.SendStatus(StatusNoContent)
+	})
+	dir := t.TempDir()
+
+	changes := make(chan Config, 2)
+	app.Hooks().OnConfigChange(func(_, next Config) error {
+		changes <- next
+		return nil
+	})
+
+	// the fields which aren't reloadable may be set to the values of the running app
+	path := writeTestConfig(t, dir, `{"app": {"app_name": "api", "read_timeout": "2s"}, "maintenance": false}`)
+	stop, err := app.WatchConfig(path, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer stop()
+
+	// the fields which are missing in the file keep their values
+	next := <-changes
+	require.Equal(t, 16, next.BodyLimit)
+	require.Equal(t, time.Second, next.WriteTimeout)
+	require.Equal(t, 2*time.Second, next.ReadTimeout)
+	require.Equal(t, 2*time.Second, app.Config().ReadTimeout)
+
+	_, err = app.Test(httptest.NewRequest(MethodPost, "/", strings.NewReader(strings.Repeat("a", 32))))
+	require.EqualError(t, err, "body size exceeds the given limit")
+
+	// the maintenance mode of the admin isn't reverted by an unrelated change
+	app.SetMaintenanceMode(true)
+	writeTestConfig(t, dir, `{"app": {"app_name": "api", "body_limit": 64}, "maintenance": false}`)
+
+	select {
+	case next := <-changes:
+		require.Equal(t, 64, next.BodyLimit)
+		require.Equal(t, time.Second, next.WriteTimeout)
+		require.True(t, app.MaintenanceMode())
+	case <-time.After(time.Second):
+		t.Fatal("config change was not detected")
+	}
+	app.SetMaintenanceMode(false)
+
+	resp, err := app.Test(httptest.NewRequest(MethodPost, "/", strings.NewReader(strings.Repeat("a", 32))))
+	require.NoError(t, err)
+	require.Equal(t, StatusNoContent, resp.StatusCode)
+}
+
+// go test -run Test_App_WatchConfig_NotReloadable
+func Test_App_WatchConfig_NotReloadable(t *testing.T) {
+	t.Parallel()
+	app := New()
+	dir := t.TempDir()
+
+	_, err := app.WatchConfig(writeTestConfig(t, dir, `{"app": {"concurrency": 10}}`))
+	require.ErrorIs(t, err, ErrConfigFieldNotReloadable)
+	require.ErrorContains(t, err, `"app.concurrency"`)
+
+	cfg, err := LoadConfig(writeTestConfig(t, dir, `{"app": {"body_limit": 1024}, "listen": {"enable_prefork": true}}`))
+	require.NoError(t, err)
+	require.NoError(t, app.applyFileConfig(cfg, nil))
+
+	// a reload which changes the listen section is rejected and nothing is applied
+	next, err := LoadConfig(writeTestConfig(t, dir, `{"app": {"body_limit": 2048}, "listen": {"enable_prefork": false}}`))
+	require.NoError(t, err)
+	err = app.applyFileConfig(next, &cfg)
+	require.ErrorIs(t, err, ErrConfigFieldNotReloadable)
+	require.ErrorContains(t, err, `"listen.enable_prefork"`)
+	require.Equal(t, 1024, app.Config().BodyLimit)
+}
@@ -0,0 +1,266 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"io"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConnStats contains the connection counters of the listeners of the app.
+//
+// They can be exposed with the expvar middleware:
+//
+//	expvar.Publish("fiber_conns", expvar.Func(func() any {
+//	    return app.ConnStats()
+//	}))
+type ConnStats struct {
+	// Open is the number of currently open connections.
+	Open int64 `json:"open"`
+	// Accepted is the total number of accepted connections.
+	Accepted uint64 `json:"accepted"`
+	// RejectedMaxConns is the number of connections rejected by ListenConfig.MaxConns.
+	RejectedMaxConns uint64 `json:"rejected_max_conns"`
+	// RejectedMaxConnsPerIP is the number of connections rejected by ListenConfig.MaxConnsPerIP.
+	RejectedMaxConnsPerIP uint64 `json:"rejected_max_conns_per_ip"`
+	// RejectedAcceptRate is the number of connections rejected by ListenConfig.MaxAcceptRate.
+	RejectedAcceptRate uint64 `json:"rejected_accept_rate"`
+}
+
+// ConnInfo describes an open connection.
+type ConnInfo struct {
+	// RemoteAddr is the address of the client.
+	RemoteAddr string `json:"remote_addr"`
+	// LocalAddr is the address of the listener.
+	LocalAddr string `json:"local_addr"`
+	// Since is the time the connection was accepted.
+	Since time.Time `json:"since"`
+}
+
+type connStats struct {
+	active                sync.Map // open *limitConn
+	open                  atomic.Int64
+	accepted              atomic.Uint64
+	rejectedMaxConns      atomic.Uint64
+	rejectedMaxConnsPerIP atomic.Uint64
+	rejectedAcceptRate    atomic.Uint64
+	bytesIn               atomic.Uint64
+	bytesOut              atomic.Uint64
+}
+
+// ConnStats returns the connection counters of the listeners of the app.
+func (app *App) ConnStats() ConnStats {
+	return ConnStats{
+		Open:                  app.connStats.open.Load(),
+		Accepted:              app.connStats.accepted.Load(),
+		RejectedMaxConns:      app.connStats.rejectedMaxConns.Load(),
+		RejectedMaxConnsPerIP: app.connStats.rejectedMaxConnsPerIP.Load(),
+		RejectedAcceptRate:    app.connStats.rejectedAcceptRate.Load(),
+	}
+}
+
+// Connections returns the open connections of the listeners of the app, the oldest first.
+func (app *App) Connections() []ConnInfo {
+	var conns []ConnInfo
+	app.connStats.active.Range(func(key, _ any) bool {
+		if conn, ok := key.(*limitConn); ok {
+			conns = append(conns, conn.info())
+		}
+		return true
+	})
+	sort.Slice(conns, func(i, j int) bool { return conns[i].Since.Before(conns[j].Since) })
+
+	return conns
+}
+
+// limitListener wraps the listener to count the connections and to enforce the connection limits.
+// It must wrap the raw listener before TLS is applied, so connections are rejected before the TLS handshake.
+func (app *App) limitListener(ln net.Listener, cfg ListenConfig) net.Listener {
+	return &connLimitListener{
+		Listener:      ln,
+		app:           app,
+		stats:         &app.connStats,
+		maxConns:      int64(cfg.MaxConns),
+		maxConnsPerIP: cfg.MaxConnsPerIP,
+		maxAcceptRate: cfg.MaxAcceptRate,
+		conns:         make(map[string]int),
+	}
+}
+
+// connLimitListener is a net.Listener which closes the connections exceeding the limits directly after accepting.
+type connLimitListener struct {
+	net.Listener
+	app   *App
+	stats *connStats
+
+	maxConns      int64
+	maxConnsPerIP int
+	maxAcceptRate int
+
+	mutex       sync.Mutex
+	conns       map[string]int // open connections per ip
+	open        int64          // open connections of this listener
+	windowStart time.Time      // start of the current accept rate window
+	windowCount int            // accepted connections in the current window
+}
+
+// Accept waits for and returns the next connection which doesn't exceed the limits.
+func (l *connLimitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err //nolint:wrapcheck // This must not be wrapped
+		}
+
+		ip := connIP(conn)
+		if counter := l.acquire(ip); counter != nil {
+			counter.Add(1)
+			_ = conn.Close() //nolint:errcheck // It is fine to ignore the error here
+			continue
+		}
+
+		l.stats.accepted.Add(1)
+		l.stats.open.Add(1)
+		l.app.setTCPKeepalive(conn)
+		lc := &limitConn{Conn: conn, listener: l, ip: ip, accepted: time.Now()}
+		if err := l.app.hooks.executeOnConnOpenHooks(lc.info()); err != nil {
+			l.release(ip)
+			_ = conn.Close() //nolint:errcheck // It is fine to ignore the error here
+			continue
+		}
+		l.stats.active.Store(lc, struct{}{})
+		return lc, nil
+	}
+}
+
+// acquire reserves a connection slot for the ip,
+// or returns the counter of the exceeded limit.
+func (l *connLimitListener) acquire(ip string) *atomic.Uint64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.maxAcceptRate > 0 {
+		now := time.Now()
+		if now.Sub(l.windowStart) >= time.Second {
+			l.windowStart = now
+			l.windowCount = 0
+		}
+		if l.windowCount >= l.maxAcceptRate {
+			return &l.stats.rejectedAcceptRate
+		}
+		l.windowCount++
+	}
+	if l.maxConns > 0 && l.open >= l.maxConns {
+		return &l.stats.rejectedMaxConns
+	}
+	if l.maxConnsPerIP > 0 && l.conns[ip] >= l.maxConnsPerIP {
+		return &l.stats.rejectedMaxConnsPerIP
+	}
+
+	l.open++
+	l.conns[ip]++
+	return nil
+}
+
+func (l *connLimitListener) release(ip string) {
+	l.mutex.Lock()
+	l.open--
+	if l.conns[ip] <= 1 {
+		delete(l.conns, ip)
+	} else {
+		l.conns[ip]--
+	}
+	l.mutex.Unlock()
+
+	l.stats.open.Add(-1)
+}
+
+// limitConn releases its connection slot when it is closed.
+type limitConn struct {
+	net.Conn
+	listener *connLimitListener
+	ip       string
+	accepted time.Time
+	once     sync.Once
+	// writeFailed reports if a write failed, see OnCommitted
+	writeFailed atomic.Bool
+}
+
+// Read counts the received bytes of the connection.
+func (c *limitConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if c.listener != nil {
+		c.listener.stats.bytesIn.Add(uint64(n))
+	}
+	return n, err //nolint:wrapcheck // This must not be wrapped
+}
+
+// Write counts the sent bytes of the connection.
+func (c *limitConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if c.listener != nil {
+		c.listener.stats.bytesOut.Add(uint64(n))
+	}
+	if err != nil {
+		c.writeFailed.Store(true)
+	}
+	return n, err //nolint:wrapcheck // This must not be wrapped
+}
+
+func (c *limitConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() {
+		c.listener.stats.active.Delete(c)
+		c.listener.release(c.ip)
+		c.listener.app.hooks.executeOnConnCloseHooks(c.info())
+	})
+	return err //nolint:wrapcheck // This must not be wrapped
+}
+
+// NetConn returns the underlying connection, e.g. to set socket options of a hijacked connection.
+func (c *limitConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// info returns the description of the connection.
+func (c *limitConn) info() ConnInfo {
+	return ConnInfo{
+		RemoteAddr: c.RemoteAddr().String(),
+		LocalAddr:  c.LocalAddr().String(),
+		Since:      c.accepted,
+	}
+}
+
+// ReadFrom uses the ReadFrom method of the connection if available,
+// so files are still sent with sendfile.
+func (c *limitConn) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	var err error
+	if rf, ok := c.Conn.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(r)
+	} else {
+		n, err = io.Copy(struct{ io.Writer }{c.Conn}, r)
+	}
+	if c.listener != nil {
+		c.listener.stats.bytesOut.Add(uint64(n))
+	}
+	if err != nil {
+		c.writeFailed.Store(true)
+	}
+	return n, err //nolint:wrapcheck // This must not be wrapped
+}
+
+// connIP returns the ip of the remote address of the connection.
+func connIP(conn net.Conn) string {
+	addr := conn.RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
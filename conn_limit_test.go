@@ -0,0 +1,134 @@
+package fiber
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+func startConnLimitApp(t *testing.T, cfg ListenConfig) (*App, *fasthttputil.InmemoryListener) {
+	t.Helper()
+
+	app := New()
+	app.Get("/", testSimpleHandler)
+
+	ln := fasthttputil.NewInmemoryListener()
+	cfg.DisableStartupMessage = true
+	go func() {
+		assert.NoError(t, app.Listener(ln, cfg))
+	}()
+
+	return app, ln
+}
+
+// dialConnLimitApp sends a request on a new connection and returns the connection, if it was accepted.
+func dialConnLimitApp(t *testing.T, ln *fasthttputil.InmemoryListener) (net.Conn, bool) {
+	t.Helper()
+
+	conn, err := ln.Dial()
+	require.NoError(t, err)
+
+	// rejected connections are closed by the server
+	if _, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")); err != nil {
+		return nil, false
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		require.ErrorIs(t, err, io.EOF)
+		return nil, false
+	}
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	return conn, true
+}
+
+// go test -run Test_App_Listener_MaxConnsPerIP
+func Test_App_Listener_MaxConnsPerIP(t *testing.T) {
+	t.Parallel()
+	app, ln := startConnLimitApp(t, ListenConfig{MaxConnsPerIP: 1})
+
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		c, err := ln.Dial()
+		if err != nil {
+			return false
+		}
+		conn = c
+		return true
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool {
+		return app.ConnStats().Open == 0
+	}, time.Second, 10*time.Millisecond)
+
+	conn, ok := dialConnLimitApp(t, ln)
+	require.True(t, ok)
+
+	_, ok = dialConnLimitApp(t, ln)
+	require.False(t, ok)
+
+	stats := app.ConnStats()
+	require.Equal(t, int64(1), stats.Open)
+	require.Equal(t, uint64(2), stats.Accepted)
+	require.Equal(t, uint64(1), stats.RejectedMaxConnsPerIP)
+
+	// the slot is released after the connection was closed
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool {
+		return app.ConnStats().Open == 0
+	}, time.Second, 10*time.Millisecond)
+
+	conn, ok = dialConnLimitApp(t, ln)
+	require.True(t, ok)
+	require.NoError(t, conn.Close())
+	require.NoError(t, app.Shutdown())
+}
+
+// go test -run Test_ConnLimitListener_Acquire
+func Test_ConnLimitListener_Acquire(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	ln, ok := app.limitListener(fasthttputil.NewInmemoryListener(), ListenConfig{
+		MaxConns:      2,
+		MaxAcceptRate: 3,
+	}).(*connLimitListener)
+	require.True(t, ok)
+
+	require.Nil(t, ln.acquire("10.0.0.1"))
+	require.Nil(t, ln.acquire("10.0.0.2"))
+	require.Equal(t, &app.connStats.rejectedMaxConns, ln.acquire("10.0.0.3"))
+	require.Equal(t, &app.connStats.rejectedAcceptRate, ln.acquire("10.0.0.3"))
+
+	ln.release("10.0.0.1")
+	require.Empty(t, ln.conns["10.0.0.1"])
+	require.Equal(t, 1, ln.conns["10.0.0.2"])
+}
+
+// go test -run Test_LimitConn_ReadFrom
+func Test_LimitConn_ReadFrom(t *testing.T) {
+	t.Parallel()
+	client, server := net.Pipe()
+	conn := &limitConn{Conn: server}
+
+	go func() {
+		n, err := conn.ReadFrom(strings.NewReader("fallback"))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(8), n)
+		assert.NoError(t, server.Close())
+	}()
+
+	body, err := io.ReadAll(client)
+	require.NoError(t, err)
+	require.Equal(t, "fallback", string(body))
+}
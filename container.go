@@ -0,0 +1,136 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is the mount point of the cgroup file system
+var cgroupRoot = "/sys/fs/cgroup"
+
+// memoryLimitRatio is the share of the container memory limit which is used as soft
+// memory limit of the Go runtime, the rest is left for memory outside the Go heap.
+const memoryLimitRatio = 0.9
+
+// bufferSizeRatio is the ratio between the memory limit and the largest response buffer size class
+const bufferSizeRatio = 4096
+
+// ContainerLimits contains the CPU quota and the memory limit of the cgroup of the process.
+type ContainerLimits struct {
+	// CPUQuota is the number of CPUs the process may use, 0 if unlimited.
+	CPUQuota float64 `json:"cpu_quota"`
+	// MemoryLimit is the memory limit in bytes, 0 if unlimited.
+	MemoryLimit int64 `json:"memory_limit"`
+}
+
+// ContainerLimits returns the limits which were applied with ListenConfig.EnableContainerLimits.
+func (app *App) ContainerLimits() ContainerLimits {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	return app.containerLimits
+}
+
+// applyContainerLimits adjusts GOMAXPROCS, the soft memory limit and the size classes
+// of the response buffer pool to the limits of the container. The GOMAXPROCS and GOMEMLIMIT
+// environment variables take precedence. With prefork, the memory limit is divided between
+// the master and the child processes.
+func (app *App) applyContainerLimits(cfg ListenConfig) {
+	limits := readContainerLimits(cgroupRoot)
+
+	if limits.CPUQuota > 0 && os.Getenv("GOMAXPROCS") == "" {
+		runtime.GOMAXPROCS(max(1, int(limits.CPUQuota)))
+	}
+
+	if limits.MemoryLimit > 0 {
+		processes := int64(1)
+		if cfg.EnablePrefork {
+			processes += int64(runtime.GOMAXPROCS(0))
+		}
+		limit := int64(float64(limits.MemoryLimit)*memoryLimitRatio) / processes
+
+		if os.Getenv("GOMEMLIMIT") == "" {
+			debug.SetMemoryLimit(limit)
+		}
+		app.bufferPool = newBufferPool(limitBufferSizes(app.config.ResponseBufferSizes, limit))
+	}
+
+	app.mutex.Lock()
+	app.containerLimits = limits
+	app.mutex.Unlock()
+}
+
+// limitBufferSizes removes the size classes which are too big for the memory limit.
+// The smallest size class is always kept.
+func limitBufferSizes(sizes []int, memoryLimit int64) []int {
+	limited := make([]int, 0, len(sizes))
+	smallest := -1
+	for _, size := range sizes {
+		if int64(size) <= memoryLimit/bufferSizeRatio {
+			limited = append(limited, size)
+		}
+		if smallest == -1 || size < smallest {
+			smallest = size
+		}
+	}
+	if len(limited) == 0 && smallest != -1 {
+		limited = append(limited, smallest)
+	}
+
+	return limited
+}
+
+// readContainerLimits reads the limits of cgroup v2 or, if not available, of cgroup v1.
+// It expects the cgroup of the process to be mounted at the root, like in a container.
+func readContainerLimits(root string) ContainerLimits {
+	var limits ContainerLimits
+
+	// cgroup v2: "<quota> <period>" or "max <period>"
+	if fields := strings.Fields(readCgroupFile(root, "cpu.max")); len(fields) == 2 {
+		limits.CPUQuota = cpuQuota(fields[0], fields[1])
+	} else {
+		// cgroup v1 reports -1 as quota if unlimited
+		limits.CPUQuota = cpuQuota(readCgroupFile(root, "cpu", "cpu.cfs_quota_us"), readCgroupFile(root, "cpu", "cpu.cfs_period_us"))
+	}
+
+	memory := readCgroupFile(root, "memory.max")
+	if memory == "" {
+		memory = readCgroupFile(root, "memory", "memory.limit_in_bytes")
+	}
+	// cgroup v1 reports a huge number instead of "max"
+	if limit, err := strconv.ParseInt(memory, 10, 64); err == nil && limit > 0 && limit < 1<<62 {
+		limits.MemoryLimit = limit
+	}
+
+	return limits
+}
+
+// cpuQuota returns the number of CPUs of the quota and the period, 0 if unlimited.
+func cpuQuota(quota, period string) float64 {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
+
+// readCgroupFile returns the trimmed content of the file, or an empty string if it can't be read.
+func readCgroupFile(root string, elem ...string) string {
+	data, err := os.ReadFile(filepath.Join(append([]string{root}, elem...)...))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
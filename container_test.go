@@ -0,0 +1,62 @@
+package fiber
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeCgroupFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content+"\n"), 0o600))
+}
+
+// go test -run Test_ReadContainerLimits
+func Test_ReadContainerLimits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cgroup v2", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		writeCgroupFile(t, root, "cpu.max", "250000 100000")
+		writeCgroupFile(t, root, "memory.max", "536870912")
+
+		require.Equal(t, ContainerLimits{CPUQuota: 2.5, MemoryLimit: 512 << 20}, readContainerLimits(root))
+	})
+
+	t.Run("cgroup v2 unlimited", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		writeCgroupFile(t, root, "cpu.max", "max 100000")
+		writeCgroupFile(t, root, "memory.max", "max")
+
+		require.Equal(t, ContainerLimits{}, readContainerLimits(root))
+	})
+
+	t.Run("cgroup v1", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		writeCgroupFile(t, root, "cpu/cpu.cfs_quota_us", "50000")
+		writeCgroupFile(t, root, "cpu/cpu.cfs_period_us", "100000")
+		writeCgroupFile(t, root, "memory/memory.limit_in_bytes", "9223372036854771712")
+
+		require.Equal(t, ContainerLimits{CPUQuota: 0.5}, readContainerLimits(root))
+	})
+
+	t.Run("no cgroup", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, ContainerLimits{}, readContainerLimits(t.TempDir()))
+	})
+}
+
+// go test -run Test_LimitBufferSizes
+func Test_LimitBufferSizes(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, DefaultResponseBufferSizes, limitBufferSizes(DefaultResponseBufferSizes, 1<<30))
+	require.Equal(t, []int{512, 4 * 1024, 32 * 1024}, limitBufferSizes(DefaultResponseBufferSizes, 256<<20))
+	require.Equal(t, []int{512}, limitBufferSizes(DefaultResponseBufferSizes, 1<<20))
+}
@@ -0,0 +1,329 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentRange is the Content-Range of a partial upload, see Ctx.ContentRange.
+type ContentRange struct {
+	// Unit is the range unit, always "bytes".
+	Unit string
+	// Start is the position of the first byte.
+	Start int64
+	// End is the position of the last byte, inclusive.
+	End int64
+	// Size is the size of the complete content, or -1 if it is unknown.
+	Size int64
+}
+
+// Length returns the number of bytes of the range.
+func (r ContentRange) Length() int64 {
+	return r.End - r.Start + 1
+}
+
+// ContentRange parses and validates the Content-Range header of an upload, e.g.
+// "bytes 0-999/5000" or "bytes 0-999/*" if the size is unknown. ErrContentRangeMissing
+// is returned if the header is missing, ErrContentRangeMalformed if it is invalid or
+// doesn't match the length of the body.
+func (c *DefaultCtx) ContentRange() (ContentRange, error) {
+	header := c.Get(HeaderContentRange)
+	if header == "" {
+		return ContentRange{}, ErrContentRangeMissing
+	}
+	r, err := ParseContentRange(header)
+	if err != nil {
+		return r, err
+	}
+	if int64(len(c.Body())) != r.Length() {
+		return r, fmt.Errorf("%w: the body has %d bytes, the range %d", ErrContentRangeMalformed, len(c.Body()), r.Length())
+	}
+	return r, nil
+}
+
+// ParseContentRange parses a Content-Range header value of a byte range.
+func ParseContentRange(header string) (ContentRange, error) {
+	r := ContentRange{Size: -1}
+
+	unit, spec, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || unit != "bytes" {
+		return r, ErrContentRangeMalformed
+	}
+	r.Unit = unit
+
+	positions, size, ok := strings.Cut(spec, "/")
+	if !ok {
+		return r, ErrContentRangeMalformed
+	}
+	start, end, ok := strings.Cut(positions, "-")
+	if !ok {
+		return r, ErrContentRangeMalformed
+	}
+
+	var err error
+	if r.Start, err = strconv.ParseInt(start, 10, 64); err != nil || r.Start < 0 {
+		return r, ErrContentRangeMalformed
+	}
+	if r.End, err = strconv.ParseInt(end, 10, 64); err != nil || r.End < r.Start {
+		return r, ErrContentRangeMalformed
+	}
+	if size != "*" {
+		if r.Size, err = strconv.ParseInt(size, 10, 64); err != nil || r.End >= r.Size {
+			return r, ErrContentRangeMalformed
+		}
+	}
+	return r, nil
+}
+
+// UploadStatus is the status of an upload of an UploadAssembler.
+type UploadStatus struct {
+	// Received is the number of received bytes, ranges which were received
+	// several times are counted once.
+	Received int64
+	// Size is the size of the upload.
+	Size int64
+	// Complete reports if all bytes of the upload were received.
+	Complete bool
+	// Path is the path of the temporary file of the upload, the caller
+	// owns the file when the upload is complete.
+	Path string
+}
+
+// UploadAssembler assembles uploads whose chunks are sent in several requests with a
+// Content-Range, e.g. in parallel. The chunks are written into a sparse temporary file
+// at their position, the upload is complete when all bytes were received.
+//
+//	uploads := fiber.NewUploadAssembler(os.TempDir())
+//
+//	app.Put("/uploads/:id", func(c fiber.Ctx) error {
+//	    r, err := c.ContentRange()
+//	    if err != nil {
+//	        return err
+//	    }
+//	    status, err := uploads.Write(c.Params("id"), r, c.Body())
+//	    if err != nil {
+//	        return err
+//	    }
+//	    if !status.Complete {
+//	        return c.SendStatus(fiber.StatusAccepted)
+//	    }
+//	    return os.Rename(status.Path, "/data/"+c.Params("id"))
+//	})
+type UploadAssembler struct {
+	dir     string
+	mutex   sync.Mutex
+	uploads map[string]*assembly
+}
+
+// assembly is an upload of an UploadAssembler.
+type assembly struct {
+	mutex    sync.RWMutex // write locked to update the ranges, read locked to write chunks
+	file     *os.File
+	size     int64
+	ranges   [][2]int64 // received ranges [start, end), sorted and merged
+	complete bool
+	aborted  bool
+	updated  time.Time
+}
+
+// NewUploadAssembler creates an UploadAssembler which stores the temporary files in the directory.
+func NewUploadAssembler(dir string) *UploadAssembler {
+	return &UploadAssembler{dir: dir, uploads: make(map[string]*assembly)}
+}
+
+// Write writes the chunk of the range into the upload with the ID and returns the status of the
+// upload. The size of the range must be known. The first chunk creates the upload, the upload is
+// removed from the assembler when it is complete, the caller owns its file then.
+// ErrUploadAborted is returned if the upload was removed while the chunk was written. Chunks may be
+// sent in any order and several times. ErrUploadSizeMismatch is returned if the size differs from
+// the size of the upload.
+func (a *UploadAssembler) Write(id string, r ContentRange, chunk []byte) (UploadStatus, error) {
+	if r.Size < 0 || int64(len(chunk)) != r.Length() {
+		return UploadStatus{}, ErrContentRangeMalformed
+	}
+
+	upload, err := a.upload(id, r.Size)
+	if err != nil {
+		return UploadStatus{}, err
+	}
+
+	// chunks are written in parallel
+	upload.mutex.RLock()
+	if upload.aborted {
+		upload.mutex.RUnlock()
+		return UploadStatus{}, ErrUploadAborted
+	}
+	if upload.complete {
+		status := upload.statusLocked()
+		upload.mutex.RUnlock()
+		return status, nil
+	}
+	if upload.size != r.Size {
+		status := upload.statusLocked()
+		upload.mutex.RUnlock()
+		return status, ErrUploadSizeMismatch
+	}
+	_, err = upload.file.WriteAt(chunk, r.Start)
+	upload.mutex.RUnlock()
+	if err != nil {
+		return UploadStatus{}, fmt.Errorf("upload: failed to write chunk: %w", err)
+	}
+
+	upload.mutex.Lock()
+	defer upload.mutex.Unlock()
+	if upload.aborted {
+		return UploadStatus{}, ErrUploadAborted
+	}
+	if upload.complete {
+		return upload.statusLocked(), nil
+	}
+	upload.add(r.Start, r.End+1)
+	upload.updated = time.Now()
+	if upload.received() == upload.size {
+		upload.complete = true
+		a.mutex.Lock()
+		delete(a.uploads, id)
+		a.mutex.Unlock()
+		if err := upload.file.Close(); err != nil {
+			return upload.statusLocked(), fmt.Errorf("upload: failed to close file: %w", err)
+		}
+	}
+	return upload.statusLocked(), nil
+}
+
+// upload returns the upload with the ID, a new upload is created with the size.
+func (a *UploadAssembler) upload(id string, size int64) (*assembly, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if upload, ok := a.uploads[id]; ok {
+		return upload, nil
+	}
+
+	file, err := os.CreateTemp(a.dir, "fiber-upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("upload: failed to create file: %w", err)
+	}
+	// the file is sparse, the chunks are written at their position
+	if err := file.Truncate(size); err != nil {
+		_ = file.Close()           //nolint:errcheck // the truncate error is returned
+		_ = os.Remove(file.Name()) //nolint:errcheck // the truncate error is returned
+		return nil, fmt.Errorf("upload: failed to allocate file: %w", err)
+	}
+	upload := &assembly{file: file, size: size, updated: time.Now()}
+	a.uploads[id] = upload
+	return upload, nil
+}
+
+// Status returns the status of the upload with the ID and reports if it exists.
+// Complete uploads don't exist anymore.
+func (a *UploadAssembler) Status(id string) (UploadStatus, bool) {
+	a.mutex.Lock()
+	upload, ok := a.uploads[id]
+	a.mutex.Unlock()
+	if !ok {
+		return UploadStatus{}, false
+	}
+	return upload.status(), true
+}
+
+// Remove aborts the upload with the ID and removes its file, the following
+// chunks of the upload return ErrUploadAborted.
+func (a *UploadAssembler) Remove(id string) error {
+	a.mutex.Lock()
+	upload, ok := a.uploads[id]
+	delete(a.uploads, id)
+	a.mutex.Unlock()
+	if !ok {
+		return nil
+	}
+	return upload.remove()
+}
+
+// Expire aborts the uploads which didn't receive a chunk within the duration and
+// returns their number. Call it periodically to remove abandoned uploads.
+func (a *UploadAssembler) Expire(idle time.Duration) int {
+	a.mutex.Lock()
+	var expired []*assembly
+	for id, upload := range a.uploads {
+		upload.mutex.RLock()
+		if time.Since(upload.updated) > idle {
+			expired = append(expired, upload)
+			delete(a.uploads, id)
+		}
+		upload.mutex.RUnlock()
+	}
+	a.mutex.Unlock()
+
+	for _, upload := range expired {
+		_ = upload.remove() //nolint:errcheck // the upload is abandoned
+	}
+	return len(expired)
+}
+
+// remove closes and removes the file of the upload.
+func (u *assembly) remove() error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.aborted = true
+	if err := u.file.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return fmt.Errorf("upload: failed to close file: %w", err)
+	}
+	if err := os.Remove(u.file.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("upload: failed to remove file: %w", err)
+	}
+	return nil
+}
+
+// add adds the range [start, end) to the received ranges.
+func (u *assembly) add(start, end int64) {
+	ranges := append(u.ranges, [2]int64{start, end}) //nolint:gocritic // the ranges are replaced
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i][0] < ranges[j][0]
+	})
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	u.ranges = merged
+}
+
+// received returns the number of received bytes.
+func (u *assembly) received() int64 {
+	var n int64
+	for _, r := range u.ranges {
+		n += r[1] - r[0]
+	}
+	return n
+}
+
+// status returns the status of the upload.
+func (u *assembly) status() UploadStatus {
+	u.mutex.RLock()
+	defer u.mutex.RUnlock()
+	return u.statusLocked()
+}
+
+// statusLocked returns the status of the upload, the mutex must be locked.
+func (u *assembly) statusLocked() UploadStatus {
+	return UploadStatus{
+		Received: u.received(),
+		Size:     u.size,
+		Complete: u.complete,
+		Path:     u.file.Name(),
+	}
+}
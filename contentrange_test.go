@@ -0,0 +1,187 @@
+package fiber
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_ParseContentRange
+func Test_ParseContentRange(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		header string
+		r      ContentRange
+		err    bool
+	}{
+		{header: "bytes 0-999/5000", r: ContentRange{Unit: "bytes", Start: 0, End: 999, Size: 5000}},
+		{header: "bytes 1000-1999/*", r: ContentRange{Unit: "bytes", Start: 1000, End: 1999, Size: -1}},
+		{header: " bytes 4999-4999/5000 ", r: ContentRange{Unit: "bytes", Start: 4999, End: 4999, Size: 5000}},
+		{header: "items 0-9/10", err: true},
+		{header: "bytes 0-999", err: true},
+		{header: "bytes 999/5000", err: true},
+		{header: "bytes 10-9/5000", err: true},
+		{header: "bytes -1-9/5000", err: true},
+		{header: "bytes 0-5000/5000", err: true},
+		{header: "bytes a-b/c", err: true},
+		{header: "bytes */5000", err: true},
+	}
+	for _, tt := range tests {
+		r, err := ParseContentRange(tt.header)
+		if tt.err {
+			require.ErrorIs(t, err, ErrContentRangeMalformed, tt.header)
+			continue
+		}
+		require.NoError(t, err, tt.header)
+		require.Equal(t, tt.r, r, tt.header)
+	}
+}
+
+// go test -run Test_Ctx_ContentRange
+func Test_Ctx_ContentRange(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	fctx := &fasthttp.RequestCtx{}
+	c := app.AcquireCtx(fctx)
+	_, err := c.ContentRange()
+	require.ErrorIs(t, err, ErrContentRangeMissing)
+	app.ReleaseCtx(c)
+
+	fctx = &fasthttp.RequestCtx{}
+	fctx.Request.Header.Set(HeaderContentRange, "bytes 0-4/10")
+	fctx.Request.SetBodyString("hello")
+	c = app.AcquireCtx(fctx)
+	r, err := c.ContentRange()
+	require.NoError(t, err)
+	require.Equal(t, ContentRange{Unit: "bytes", Start: 0, End: 4, Size: 10}, r)
+	require.Equal(t, int64(5), r.Length())
+	app.ReleaseCtx(c)
+
+	fctx = &fasthttp.RequestCtx{}
+	fctx.Request.Header.Set(HeaderContentRange, "bytes 0-9/10")
+	fctx.Request.SetBodyString("hello")
+	c = app.AcquireCtx(fctx)
+	_, err = c.ContentRange()
+	require.ErrorIs(t, err, ErrContentRangeMalformed)
+	app.ReleaseCtx(c)
+}
+
+// go test -run Test_UploadAssembler
+func Test_UploadAssembler(t *testing.T) {
+	t.Parallel()
+	uploads := NewUploadAssembler(t.TempDir())
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	size := int64(len(content))
+
+	// the chunks are sent in parallel and the first chunk twice
+	var wg sync.WaitGroup
+	statuses := make(chan UploadStatus, 11)
+	for i := -1; i < 10; i++ {
+		start := int64(max(i, 0)) * 100
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := ContentRange{Unit: "bytes", Start: start, End: start + 99, Size: size}
+			status, err := uploads.Write("file", r, content[start:start+100])
+			if err == nil {
+				statuses <- status
+			}
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	var complete *UploadStatus
+	for status := range statuses {
+		require.Equal(t, size, status.Size)
+		if status.Complete {
+			complete = &status
+		}
+	}
+	require.NotNil(t, complete)
+	require.Equal(t, size, complete.Received)
+
+	_, ok := uploads.Status("file")
+	require.False(t, ok)
+
+	data, err := os.ReadFile(complete.Path)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
+
+// go test -run Test_UploadAssembler_Status
+func Test_UploadAssembler_Status(t *testing.T) {
+	t.Parallel()
+	uploads := NewUploadAssembler(t.TempDir())
+
+	status, err := uploads.Write("file", ContentRange{Unit: "bytes", Start: 5, End: 9, Size: 10}, []byte("56789"))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), status.Received)
+	require.False(t, status.Complete)
+
+	// overlapping ranges are counted once
+	status, err = uploads.Write("file", ContentRange{Unit: "bytes", Start: 3, End: 6, Size: 10}, []byte("3456"))
+	require.NoError(t, err)
+	require.Equal(t, int64(7), status.Received)
+
+	status, ok := uploads.Status("file")
+	require.True(t, ok)
+	require.Equal(t, UploadStatus{Received: 7, Size: 10, Path: status.Path}, status)
+
+	_, err = uploads.Write("file", ContentRange{Unit: "bytes", Start: 0, End: 2, Size: 20}, []byte("012"))
+	require.ErrorIs(t, err, ErrUploadSizeMismatch)
+
+	_, err = uploads.Write("file", ContentRange{Unit: "bytes", Start: 0, End: 2, Size: -1}, []byte("012"))
+	require.ErrorIs(t, err, ErrContentRangeMalformed)
+
+	status, err = uploads.Write("file", ContentRange{Unit: "bytes", Start: 0, End: 2, Size: 10}, []byte("012"))
+	require.NoError(t, err)
+	require.True(t, status.Complete)
+	data, err := os.ReadFile(status.Path)
+	require.NoError(t, err)
+	require.Equal(t, "0123456789", string(data))
+}
+
+// go test -run Test_UploadAssembler_Remove
+func Test_UploadAssembler_Remove(t *testing.T) {
+	t.Parallel()
+	uploads := NewUploadAssembler(t.TempDir())
+
+	status, err := uploads.Write("file", ContentRange{Unit: "bytes", Start: 0, End: 2, Size: 10}, []byte("012"))
+	require.NoError(t, err)
+	require.NoError(t, uploads.Remove("file"))
+	require.NoError(t, uploads.Remove("file"))
+
+	_, ok := uploads.Status("file")
+	require.False(t, ok)
+	_, err = os.Stat(status.Path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// go test -run Test_UploadAssembler_Expire
+func Test_UploadAssembler_Expire(t *testing.T) {
+	t.Parallel()
+	uploads := NewUploadAssembler(t.TempDir())
+
+	var paths []string
+	for i := 0; i < 2; i++ {
+		status, err := uploads.Write(fmt.Sprint(i), ContentRange{Unit: "bytes", Start: 0, End: 2, Size: 10}, []byte("012"))
+		require.NoError(t, err)
+		paths = append(paths, status.Path)
+	}
+	require.Equal(t, 0, uploads.Expire(time.Hour))
+
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, 2, uploads.Expire(5*time.Millisecond))
+	for _, path := range paths {
+		_, err := os.Stat(path)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	}
+}
@@ -0,0 +1,207 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gofiber/utils/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// The prefixes of the cookie names which browsers only accept with secure attributes,
+// see https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Set-Cookie#cookie_prefixes
+const (
+	// CookiePrefixHost requires Secure, Path "/" and no Domain.
+	CookiePrefixHost = "__Host-"
+	// CookiePrefixSecure requires Secure.
+	CookiePrefixSecure = "__Secure-"
+)
+
+// CookieProfile contains the defaults of the attributes of the cookies of a use case.
+type CookieProfile struct {
+	// SameSite is the SameSite attribute of the cookies.
+	SameSite string `json:"same_site"`
+	// Path is the path of the cookies.
+	Path string `json:"path"`
+	// MaxAge is the lifetime of the cookies in seconds, 0 for session cookies.
+	MaxAge int `json:"max_age"`
+	// Secure sends the cookies over HTTPS only.
+	Secure bool `json:"secure"`
+	// HTTPOnly hides the cookies from JavaScript.
+	HTTPOnly bool `json:"http_only"`
+	// Partitioned stores the cookies per top-level site (CHIPS).
+	Partitioned bool `json:"partitioned"`
+}
+
+// The profiles of the common use cases of cookies.
+var (
+	// CookieProfileFirstParty is the profile of the cookies of the own site, e.g. of sessions,
+	// which are sent with top-level navigations from other sites.
+	CookieProfileFirstParty = CookieProfile{
+		SameSite: CookieSameSiteLaxMode,
+		Path:     "/",
+		Secure:   true,
+		HTTPOnly: true,
+	}
+	// CookieProfileStrict is the profile of the cookies which are never sent with requests
+	// from other sites, e.g. of CSRF tokens.
+	CookieProfileStrict = CookieProfile{
+		SameSite: CookieSameSiteStrictMode,
+		Path:     "/",
+		Secure:   true,
+		HTTPOnly: true,
+	}
+	// CookieProfileEmbedded is the profile of the cookies of a site which is embedded into
+	// other sites, e.g. in an iframe. The cookies are partitioned per top-level site.
+	CookieProfileEmbedded = CookieProfile{
+		SameSite:    CookieSameSiteNoneMode,
+		Path:        "/",
+		Secure:      true,
+		HTTPOnly:    true,
+		Partitioned: true,
+	}
+)
+
+// Cookie returns a cookie with the attributes of the profile.
+//
+//	c.SetCookies(fiber.CookieProfileFirstParty.Cookie("__Host-session", id))
+func (p CookieProfile) Cookie(name, value string) *Cookie {
+	return &Cookie{
+		Name:        name,
+		Value:       value,
+		Path:        p.Path,
+		MaxAge:      p.MaxAge,
+		Secure:      p.Secure,
+		HTTPOnly:    p.HTTPOnly,
+		SameSite:    p.SameSite,
+		Partitioned: p.Partitioned,
+		SessionOnly: p.MaxAge == 0,
+	}
+}
+
+// Validate returns an error if browsers would reject the cookie: the name must be a token,
+// the prefixes __Host- and __Secure- and the attributes Partitioned and SameSite=None
+// require Secure, and __Host- requires Path "/" and no Domain.
+func (cookie *Cookie) Validate() error {
+	if cookie.Name == "" || strings.IndexFunc(cookie.Name, isInvalidCookieNameRune) >= 0 {
+		return ErrCookieName
+	}
+	if strings.HasPrefix(cookie.Name, CookiePrefixHost) &&
+		(!cookie.Secure || cookie.Path != "/" || cookie.Domain != "") {
+		return ErrCookieHostPrefix
+	}
+	if strings.HasPrefix(cookie.Name, CookiePrefixSecure) && !cookie.Secure {
+		return ErrCookieSecurePrefix
+	}
+	if cookie.Partitioned && !cookie.Secure {
+		return ErrCookiePartitioned
+	}
+	if utils.ToLower(cookie.SameSite) == CookieSameSiteNoneMode && !cookie.Secure {
+		return ErrCookieSameSiteNone
+	}
+	return nil
+}
+
+// isInvalidCookieNameRune reports whether the rune isn't allowed in the token of a cookie name.
+func isInvalidCookieNameRune(r rune) bool {
+	return r <= ' ' || r >= 0x7f || strings.ContainsRune(`()<>@,;:\"/[]?={}`, r)
+}
+
+// fasthttpCookie returns the cookie as a fasthttp cookie, it must be released with fasthttp.ReleaseCookie.
+func (cookie *Cookie) fasthttpCookie() *fasthttp.Cookie {
+	fcookie := fasthttp.AcquireCookie()
+	fcookie.SetKey(cookie.Name)
+	fcookie.SetValue(cookie.Value)
+	fcookie.SetPath(cookie.Path)
+	fcookie.SetDomain(cookie.Domain)
+	// only set max age and expiry when SessionOnly is false
+	// i.e. cookie supposed to last beyond browser session
+	// refer: https://developer.mozilla.org/en-US/docs/Web/HTTP/Cookies#define_the_lifetime_of_a_cookie
+	if !cookie.SessionOnly {
+		fcookie.SetMaxAge(cookie.MaxAge)
+		fcookie.SetExpire(cookie.Expires)
+	}
+	fcookie.SetSecure(cookie.Secure)
+	fcookie.SetHTTPOnly(cookie.HTTPOnly)
+
+	switch utils.ToLower(cookie.SameSite) {
+	case CookieSameSiteStrictMode:
+		fcookie.SetSameSite(fasthttp.CookieSameSiteStrictMode)
+	case CookieSameSiteNoneMode:
+		fcookie.SetSameSite(fasthttp.CookieSameSiteNoneMode)
+	case CookieSameSiteDisabled:
+		fcookie.SetSameSite(fasthttp.CookieSameSiteDisabled)
+	default:
+		fcookie.SetSameSite(fasthttp.CookieSameSiteLaxMode)
+	}
+	return fcookie
+}
+
+// header returns the value of the Set-Cookie header of the cookie.
+func (cookie *Cookie) header() string {
+	fcookie := cookie.fasthttpCookie()
+	defer fasthttp.ReleaseCookie(fcookie)
+	value := string(fcookie.Cookie())
+	// fasthttp doesn't support the Partitioned attribute
+	if cookie.Partitioned {
+		value += "; Partitioned"
+	}
+	return value
+}
+
+// setCookie sets the Set-Cookie header of the cookie, it replaces a cookie of the same name.
+func (c *DefaultCtx) setCookie(cookie *Cookie) {
+	c.fasthttp.Response.Header.DelCookie(cookie.Name)
+	c.fasthttp.Response.Header.Add(HeaderSetCookie, cookie.header())
+}
+
+// SetCookies validates and sets the cookies. It sets none of them and returns an error if
+// a cookie is invalid, see Cookie.Validate, if a cookie is larger than Config.MaxCookieSize
+// or if the response would have more cookies than Config.MaxResponseCookies.
+func (c *DefaultCtx) SetCookies(cookies ...*Cookie) error {
+	count := 0
+	c.fasthttp.Response.Header.VisitAllCookie(func(_, _ []byte) {
+		count++
+	})
+	headers := make([]string, len(cookies))
+	for i, cookie := range cookies {
+		if err := cookie.Validate(); err != nil {
+			return err
+		}
+		headers[i] = cookie.header()
+		if len(headers[i]) > c.app.config.MaxCookieSize {
+			return ErrCookieTooLarge
+		}
+		if c.fasthttp.Response.Header.PeekCookie(cookie.Name) == nil &&
+			!slices.ContainsFunc(cookies[:i], func(other *Cookie) bool { return other.Name == cookie.Name }) {
+			count++
+		}
+	}
+	if count > c.app.config.MaxResponseCookies {
+		return ErrCookieTooMany
+	}
+
+	for i, cookie := range cookies {
+		c.fasthttp.Response.Header.DelCookie(cookie.Name)
+		c.fasthttp.Response.Header.Add(HeaderSetCookie, headers[i])
+	}
+	return nil
+}
+
+// ClearCookies expires the cookies on the client side. Unlike ClearCookie, the cookies
+// are expired with their Path, Domain and attributes, which browsers require to match
+// the cookies, e.g. of partitioned cookies or cookies with the __Host- prefix.
+func (c *DefaultCtx) ClearCookies(cookies ...*Cookie) {
+	for _, cookie := range cookies {
+		expired := *cookie
+		expired.Value = ""
+		expired.MaxAge = 0
+		expired.Expires = fasthttp.CookieExpireDelete
+		expired.SessionOnly = false
+		c.setCookie(&expired)
+	}
+}
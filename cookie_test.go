@@ -0,0 +1,91 @@
+package fiber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Cookie_Validate
+func Test_Cookie_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		cookie Cookie
+		err    error
+	}{
+		{cookie: Cookie{Name: "session"}},
+		{cookie: Cookie{Name: ""}, err: ErrCookieName},
+		{cookie: Cookie{Name: "a b"}, err: ErrCookieName},
+		{cookie: Cookie{Name: "a;b"}, err: ErrCookieName},
+		{cookie: Cookie{Name: "__Host-id", Secure: true, Path: "/"}},
+		{cookie: Cookie{Name: "__Host-id", Secure: true}, err: ErrCookieHostPrefix},
+		{cookie: Cookie{Name: "__Host-id", Secure: true, Path: "/", Domain: "example.com"}, err: ErrCookieHostPrefix},
+		{cookie: Cookie{Name: "__Host-id", Path: "/"}, err: ErrCookieHostPrefix},
+		{cookie: Cookie{Name: "__Secure-id", Secure: true, Domain: "example.com"}},
+		{cookie: Cookie{Name: "__Secure-id"}, err: ErrCookieSecurePrefix},
+		{cookie: Cookie{Name: "id", Partitioned: true}, err: ErrCookiePartitioned},
+		{cookie: Cookie{Name: "id", SameSite: "None"}, err: ErrCookieSameSiteNone},
+		{cookie: Cookie{Name: "id", SameSite: CookieSameSiteNoneMode, Secure: true, Partitioned: true}},
+	}
+	for _, tt := range tests {
+		require.ErrorIs(t, tt.cookie.Validate(), tt.err, tt.cookie.Name)
+	}
+}
+
+// go test -run Test_CookieProfile
+func Test_CookieProfile(t *testing.T) {
+	t.Parallel()
+	cookie := CookieProfileEmbedded.Cookie("__Host-widget", "1")
+	require.NoError(t, cookie.Validate())
+	require.Equal(t, "__Host-widget=1; path=/; HttpOnly; secure; SameSite=None; Partitioned", cookie.header())
+
+	profile := CookieProfileStrict
+	profile.MaxAge = 60
+	cookie = profile.Cookie("csrf", "token")
+	require.False(t, cookie.SessionOnly)
+	require.Equal(t, "csrf=token; max-age=60; path=/; HttpOnly; secure; SameSite=Strict", cookie.header())
+}
+
+// go test -run Test_Ctx_SetCookies
+func Test_Ctx_SetCookies(t *testing.T) {
+	t.Parallel()
+	app := New(Config{MaxResponseCookies: 2, MaxCookieSize: 100})
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	session := CookieProfileFirstParty.Cookie("__Host-session", "abc")
+	c.Cookie(&Cookie{Name: "theme", Value: "dark"})
+	require.NoError(t, c.SetCookies(session, session))
+	require.Equal(t, "__Host-session=abc; path=/; HttpOnly; secure; SameSite=Lax", string(c.Response().Header.PeekCookie("__Host-session")))
+
+	// replacing a cookie doesn't count
+	require.NoError(t, c.SetCookies(&Cookie{Name: "theme", Value: "light"}))
+	require.ErrorIs(t, c.SetCookies(&Cookie{Name: "lang", Value: "en"}), ErrCookieTooMany)
+
+	// none of the cookies is set if one is invalid
+	err := c.SetCookies(&Cookie{Name: "theme", Value: "blue"}, &Cookie{Name: "__Secure-id"})
+	require.ErrorIs(t, err, ErrCookieSecurePrefix)
+	require.Equal(t, "theme=light; path=/; SameSite=Lax", string(c.Response().Header.PeekCookie("theme")))
+
+	err = c.SetCookies(&Cookie{Name: "theme", Value: string(make([]byte, 100))})
+	require.ErrorIs(t, err, ErrCookieTooLarge)
+}
+
+// go test -run Test_Ctx_ClearCookies
+func Test_Ctx_ClearCookies(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	session := CookieProfileEmbedded.Cookie("__Host-session", "abc")
+	session.MaxAge = 3600
+	session.SessionOnly = false
+	c.ClearCookies(session, &Cookie{Name: "theme", Path: "/docs", Domain: "example.com"})
+	require.Equal(t,
+		"__Host-session=; expires=Tue, 10 Nov 2009 23:00:00 GMT; path=/; HttpOnly; secure; SameSite=None; Partitioned",
+		string(c.Response().Header.PeekCookie("__Host-session")))
+	require.Equal(t,
+		"theme=; expires=Tue, 10 Nov 2009 23:00:00 GMT; domain=example.com; path=/docs; SameSite=Lax",
+		string(c.Response().Header.PeekCookie("theme")))
+	require.Equal(t, "abc", session.Value)
+}
@@ -0,0 +1,149 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// DefaultCrashDumpRequests is the default number of the recent requests in the crash reports.
+const DefaultCrashDumpRequests = 32
+
+// maxGoroutinesDump limits the size of the stacks of all goroutines in a crash report.
+const maxGoroutinesDump = 64 << 20
+
+// redactedValue replaces the values of the secrets in the config of a crash report.
+const redactedValue = "[REDACTED]"
+
+// secretConfigKeys are the parts of the keys of the config values which are redacted.
+var secretConfigKeys = []string{"key", "secret", "token", "password", "passwd", "credential", "auth", "cookie"}
+
+// CrashReport is the structured report which is written to the CrashDumpDir when the app crashes.
+type CrashReport struct {
+	// Time is the time of the crash.
+	Time time.Time `json:"time"`
+	// Reason is the panic value or the error which crashed the app.
+	Reason string `json:"reason"`
+	// Stack is the stack of the goroutine which crashed.
+	Stack string `json:"stack"`
+	// Request is the request which crashed the app, if the crash was caused by a handler.
+	Request *RequestRecord `json:"request,omitempty"`
+	// RecentRequests are the latest requests of the flight recorder, the newest last.
+	RecentRequests []RequestRecord `json:"recent_requests"`
+	// Goroutines are the stacks of all goroutines.
+	Goroutines string `json:"goroutines"`
+	// Config is the config of the app, the values of secrets are redacted.
+	Config map[string]any `json:"config"`
+	// Build is the build info of the app.
+	Build BuildInfo `json:"build"`
+	// GoVersion is the version of Go of the binary.
+	GoVersion string `json:"go_version"`
+	// PID is the process ID.
+	PID int `json:"pid"`
+	// PreforkChild reports if the process is a prefork child.
+	PreforkChild bool `json:"prefork_child"`
+}
+
+// WriteCrashReport writes a crash report with the reason to the CrashDumpDir and returns its path,
+// e.g. before the app exits because of a fatal error. The app writes the reports itself when a
+// panic of a handler crashes the process or the serving of a prefork child fails.
+func (app *App) WriteCrashReport(reason any) (string, error) {
+	return app.writeCrashReport(reason, debug.Stack(), nil)
+}
+
+// writeCrashReport writes the crash report of the reason, the ctx is the request which crashed the app or nil.
+func (app *App) writeCrashReport(reason any, stack []byte, c Ctx) (string, error) {
+	if app.config.CrashDumpDir == "" {
+		return "", ErrCrashDumpDisabled
+	}
+
+	report := CrashReport{
+		Time:           time.Now(),
+		Reason:         fmt.Sprint(reason),
+		Stack:          string(stack),
+		RecentRequests: app.flightRecorder.recent(app.config.CrashDumpRequests),
+		Goroutines:     goroutinesDump(),
+		Config:         redactedConfig(app.Config()),
+		Build:          app.GetBuildInfo(),
+		GoVersion:      runtime.Version(),
+		PID:            os.Getpid(),
+		PreforkChild:   IsChild(),
+	}
+	if c != nil {
+		req := requestRecord(c, c.Context().Time(), nil)
+		report.Request = &req
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("crash: failed to encode the report: %w", err)
+	}
+	if err := os.MkdirAll(app.config.CrashDumpDir, 0o750); err != nil {
+		return "", fmt.Errorf("crash: failed to create the directory: %w", err)
+	}
+	name := fmt.Sprintf("crash-%s-%d.json", report.Time.UTC().Format("20060102T150405.000"), report.PID)
+	path := filepath.Join(app.config.CrashDumpDir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("crash: failed to write the report: %w", err)
+	}
+
+	app.logw(log.LevelError, "crash: report written", "file", path, "reason", report.Reason)
+	return path, nil
+}
+
+// goroutinesDump returns the stacks of all goroutines.
+func goroutinesDump() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxGoroutinesDump {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// redactedConfig returns the JSON values of the config with the values of the secrets redacted.
+func redactedConfig(config Config) map[string]any {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return map[string]any{"error": err.Error()}
+	}
+	redactSecrets(values)
+	return values
+}
+
+// redactSecrets replaces the values of the keys which name secrets, also in nested objects.
+func redactSecrets(values map[string]any) {
+	for key, value := range values {
+		if nested, ok := value.(map[string]any); ok {
+			redactSecrets(nested)
+			continue
+		}
+		if value == nil || value == "" {
+			continue
+		}
+		lower := strings.ToLower(key)
+		for _, secret := range secretConfigKeys {
+			if strings.Contains(lower, secret) {
+				values[key] = redactedValue
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package fiber
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// readCrashReport reads the only crash report of the directory.
+func readCrashReport(t *testing.T, dir string) CrashReport {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "crash-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+
+	var report CrashReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	return report
+}
+
+// go test -run Test_App_WriteCrashReport
+func Test_App_WriteCrashReport(t *testing.T) {
+	t.Parallel()
+	_, err := New().WriteCrashReport("fatal")
+	require.ErrorIs(t, err, ErrCrashDumpDisabled)
+
+	dir := filepath.Join(t.TempDir(), "crashes")
+	app := New(Config{CrashDumpDir: dir, CrashDumpRequests: 2})
+	app.Get("/users/:id", func(c Ctx) error {
+		return c.SendString(c.Params("id"))
+	})
+	for _, path := range []string{"/users/1", "/users/2?token=secret", "/orders"} {
+		_, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		require.NoError(t, err)
+	}
+
+	path, err := app.WriteCrashReport("fatal")
+	require.NoError(t, err)
+	require.Equal(t, dir, filepath.Dir(path))
+
+	report := readCrashReport(t, dir)
+	require.Equal(t, "fatal", report.Reason)
+	require.Contains(t, report.Stack, "Test_App_WriteCrashReport")
+	require.Contains(t, report.Goroutines, "goroutine ")
+	require.Equal(t, os.Getpid(), report.PID)
+	require.Nil(t, report.Request)
+	require.Equal(t, dir, report.Config["crash_dump_dir"])
+
+	// the ring buffer keeps the latest requests without the query
+	require.Len(t, report.RecentRequests, 2)
+	require.Equal(t, "/users/2", report.RecentRequests[0].Path)
+	require.Equal(t, "/users/:id", report.RecentRequests[0].Route)
+	require.Equal(t, StatusOK, report.RecentRequests[0].Status)
+	require.Equal(t, "/orders", report.RecentRequests[1].Path)
+	require.Equal(t, StatusNotFound, report.RecentRequests[1].Status)
+}
+
+// go test -run Test_App_CrashReport_Panic
+func Test_App_CrashReport_Panic(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	app := New(Config{CrashDumpDir: dir})
+
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.SetRequestURI("/payments")
+	fctx.Request.Header.SetMethod(MethodPost)
+	c := app.AcquireCtx(fctx).(*DefaultCtx) //nolint:errcheck,forcetypeassert // not needed
+	defer app.ReleaseCtx(c)
+
+	// the panic of the handler crashes the process with the default panic policy
+	require.PanicsWithValue(t, "boom", func() {
+		defer app.recoverPanic(c)
+		panic("boom")
+	})
+
+	report := readCrashReport(t, dir)
+	require.Equal(t, "boom", report.Reason)
+	require.Contains(t, report.Stack, "recoverPanic")
+	require.NotNil(t, report.Request)
+	require.Equal(t, MethodPost, report.Request.Method)
+	require.Equal(t, "/payments", report.Request.Path)
+}
+
+// go test -run Test_RedactSecrets
+func Test_RedactSecrets(t *testing.T) {
+	t.Parallel()
+	values := map[string]any{
+		"app_name":      "shop",
+		"api_key":       "abc",
+		"session_token": "",
+		"limits":        map[string]any{"db_password": "pw", "max": float64(1)},
+		"Authorization": []any{"Bearer abc"},
+	}
+	redactSecrets(values)
+	require.Equal(t, map[string]any{
+		"app_name":      "shop",
+		"api_key":       redactedValue,
+		"session_token": "",
+		"limits":        map[string]any{"db_password": redactedValue, "max": float64(1)},
+		"Authorization": redactedValue,
+	}, values)
+}
@@ -5,11 +5,13 @@
 package fiber
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"mime/multipart"
 	"net"
@@ -21,6 +23,7 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/gofiber/fiber/v3/log"
 	"github.com/gofiber/utils/v2"
 	"github.com/valyala/bytebufferpool"
 	"github.com/valyala/fasthttp"
@@ -41,27 +44,47 @@ type contextKey int
 // userContextKey define the key name for storing context.Context in *fasthttp.RequestCtx
 const userContextKey contextKey = 0 // __local_user_context__
 
+// HijackHandler is called with the raw connection after the request handler returned.
+// The connection is closed after the HijackHandler returned.
+type HijackHandler = func(conn net.Conn)
+
+// TunnelDialer dials the target of a tunnel.
+type TunnelDialer = func(ctx context.Context, network, address string) (net.Conn, error)
+
+// tunnelDialTimeout is the timeout of the default TunnelDialer
+const tunnelDialTimeout = 10 * time.Second
+
+var tunnelEstablished = []byte("HTTP/1.1 200 Connection Established\r\n\r\n")
+
 type DefaultCtx struct {
-	app                 *App                 // Reference to *App
-	route               *Route               // Reference to *Route
-	indexRoute          int                  // Index of the current route
-	indexHandler        int                  // Index of the current handler
-	method              string               // HTTP method
-	methodINT           int                  // HTTP method INT equivalent
-	baseURI             string               // HTTP base uri
-	path                string               // HTTP path with the modifications by the configuration -> string copy from pathBuffer
-	pathBuffer          []byte               // HTTP path buffer
-	detectionPath       string               // Route detection path                                  -> string copy from detectionPathBuffer
-	detectionPathBuffer []byte               // HTTP detectionPath buffer
-	treePath            string               // Path for the search in the tree
-	pathOriginal        string               // Original HTTP path
-	values              [maxParams]string    // Route parameter values
-	fasthttp            *fasthttp.RequestCtx // Reference to *fasthttp.RequestCtx
-	matched             bool                 // Non use route matched
-	viewBindMap         sync.Map             // Default view map to bind template engine
-	bind                *Bind                // Default bind reference
-	redirect            *Redirect            // Default redirect reference
-	redirectionMessages []string             // Messages of the previous redirect
+	app                 *App                  // Reference to *App
+	route               *Route                // Reference to *Route
+	indexRoute          int                   // Index of the current route
+	indexHandler        int                   // Index of the current handler
+	method              string                // HTTP method
+	methodINT           int                   // HTTP method INT equivalent
+	baseURI             string                // HTTP base uri
+	path                string                // HTTP path with the modifications by the configuration -> string copy from pathBuffer
+	pathBuffer          []byte                // HTTP path buffer
+	detectionPath       string                // Route detection path                                  -> string copy from detectionPathBuffer
+	detectionPathBuffer []byte                // HTTP detectionPath buffer
+	treePath            string                // Path for the search in the tree
+	treeStack           []map[string][]*Route // Route tree which is used for this request
+	routeMatcher        *routeMatcher         // Matcher of the compiled route tree, nil if not compiled
+	pathOriginal        string                // Original HTTP path
+	values              [maxParams]string     // Route parameter values
+	fasthttp            *fasthttp.RequestCtx  // Reference to *fasthttp.RequestCtx
+	matched             bool                  // Non use route matched
+	viewBindMap         sync.Map              // Default view map to bind template engine
+	bind                *Bind                 // Default bind reference
+	redirect            *Redirect             // Default redirect reference
+	redirectionMessages []string              // Messages of the previous redirect
+	responseSizeHint    int                   // Expected size of the response body
+	allocAuditStart     uint64                // Heap allocations at the start of the request, used by the allocation audit
+	arena               ctxArena              // Copies of the request values, used by the ImmutableArena setting
+	abort               requestAbort          // Tracks if the client closed the connection
+	committed           []func()              // Hooks which are called after the response was written
+	vary                []string              // Request headers which the response depends on, see VaryBy
 }
 
 // TLSHandler object
@@ -101,6 +124,7 @@ type Cookie struct {
 	HTTPOnly    bool      `json:"http_only"`
 	SameSite    string    `json:"same_site"`
 	SessionOnly bool      `json:"session_only"`
+	Partitioned bool      `json:"partitioned"`
 }
 
 // Views is the interface that wraps the Render function.
@@ -325,34 +349,7 @@ func (c *DefaultCtx) SetUserContext(ctx context.Context) {
 
 // Cookie sets a cookie by passing a cookie struct.
 func (c *DefaultCtx) Cookie(cookie *Cookie) {
-	fcookie := fasthttp.AcquireCookie()
-	fcookie.SetKey(cookie.Name)
-	fcookie.SetValue(cookie.Value)
-	fcookie.SetPath(cookie.Path)
-	fcookie.SetDomain(cookie.Domain)
-	// only set max age and expiry when SessionOnly is false
-	// i.e. cookie supposed to last beyond browser session
-	// refer: https://developer.mozilla.org/en-US/docs/Web/HTTP/Cookies#define_the_lifetime_of_a_cookie
-	if !cookie.SessionOnly {
-		fcookie.SetMaxAge(cookie.MaxAge)
-		fcookie.SetExpire(cookie.Expires)
-	}
-	fcookie.SetSecure(cookie.Secure)
-	fcookie.SetHTTPOnly(cookie.HTTPOnly)
-
-	switch utils.ToLower(cookie.SameSite) {
-	case CookieSameSiteStrictMode:
-		fcookie.SetSameSite(fasthttp.CookieSameSiteStrictMode)
-	case CookieSameSiteNoneMode:
-		fcookie.SetSameSite(fasthttp.CookieSameSiteNoneMode)
-	case CookieSameSiteDisabled:
-		fcookie.SetSameSite(fasthttp.CookieSameSiteDisabled)
-	default:
-		fcookie.SetSameSite(fasthttp.CookieSameSiteLaxMode)
-	}
-
-	c.fasthttp.Response.Header.SetCookie(fcookie)
-	fasthttp.ReleaseCookie(fcookie)
+	c.setCookie(cookie)
 }
 
 // Cookies are used for getting a cookie value by key.
@@ -361,7 +358,7 @@ func (c *DefaultCtx) Cookie(cookie *Cookie) {
 // The returned value is only valid within the handler. Do not store any references.
 // Make copies or use the Immutable setting to use the value outside the Handler.
 func (c *DefaultCtx) Cookies(key string, defaultValue ...string) string {
-	return defaultString(c.app.getString(c.fasthttp.Request.Header.Cookie(key)), defaultValue)
+	return defaultString(c.getString(c.fasthttp.Request.Header.Cookie(key)), defaultValue)
 }
 
 // Download transfers the file from path as an attachment.
@@ -404,7 +401,7 @@ func (c *DefaultCtx) Format(handlers ...ResFmt) error {
 		return ErrNoHandlers
 	}
 
-	c.Vary(HeaderAccept)
+	c.VaryBy(HeaderAccept)
 
 	if c.Get(HeaderAccept) == "" {
 		c.Response().Header.SetContentType(handlers[0].MediaType)
@@ -467,7 +464,7 @@ func (c *DefaultCtx) AutoFormat(body any) error {
 	// Format based on the accept content type
 	switch accept {
 	case "html":
-		return c.SendString("<p>" + b + "</p>")
+		return c.SendString("<p>" + html.EscapeString(b) + "</p>")
 	case "json":
 		return c.JSON(body)
 	case "txt":
@@ -490,7 +487,7 @@ func (c *DefaultCtx) FormFile(key string) (*multipart.FileHeader, error) {
 // Returned value is only valid within the handler. Do not store any references.
 // Make copies or use the Immutable setting instead.
 func (c *DefaultCtx) FormValue(key string, defaultValue ...string) string {
-	return defaultString(c.app.getString(c.fasthttp.FormValue(key)), defaultValue)
+	return defaultString(c.getString(c.fasthttp.FormValue(key)), defaultValue)
 }
 
 // Fresh returns true when the response is still “fresh” in the client's cache,
@@ -557,7 +554,7 @@ func (c *DefaultCtx) Get(key string, defaultValue ...string) string {
 // This function is generic and can handle differnet headers type values.
 func GetReqHeader[V GenericType](c Ctx, key string, defaultValue ...V) V {
 	var v V
-	return genericParseType[V](c.App().getString(c.Request().Header.Peek(key)), v, defaultValue...)
+	return genericParseType[V](c.internString(c.Request().Header.Peek(key)), v, defaultValue...)
 }
 
 // GetRespHeader returns the HTTP response header specified by field.
@@ -565,7 +562,7 @@ func GetReqHeader[V GenericType](c Ctx, key string, defaultValue ...V) V {
 // Returned value is only valid within the handler. Do not store any references.
 // Make copies or use the Immutable setting instead.
 func (c *DefaultCtx) GetRespHeader(key string, defaultValue ...string) string {
-	return defaultString(c.app.getString(c.fasthttp.Response.Header.Peek(key)), defaultValue)
+	return defaultString(c.internString(c.fasthttp.Response.Header.Peek(key)), defaultValue)
 }
 
 // GetRespHeaders returns the HTTP response headers.
@@ -574,8 +571,8 @@ func (c *DefaultCtx) GetRespHeader(key string, defaultValue ...string) string {
 func (c *DefaultCtx) GetRespHeaders() map[string][]string {
 	headers := make(map[string][]string)
 	c.Response().Header.VisitAll(func(k, v []byte) {
-		key := c.app.getString(k)
-		headers[key] = append(headers[key], c.app.getString(v))
+		key := c.internString(k)
+		headers[key] = append(headers[key], c.internString(v))
 	})
 	return headers
 }
@@ -586,13 +583,78 @@ func (c *DefaultCtx) GetRespHeaders() map[string][]string {
 func (c *DefaultCtx) GetReqHeaders() map[string][]string {
 	headers := make(map[string][]string)
 	c.Request().Header.VisitAll(func(k, v []byte) {
-		key := c.app.getString(k)
-		headers[key] = append(headers[key], c.app.getString(v))
+		key := c.internString(k)
+		headers[key] = append(headers[key], c.internString(v))
 	})
 	return headers
 }
 
-// Host contains the host derived from the X-Forwarded-Host or Host HTTP header.
+// Hijack takes over the underlying connection to implement custom protocols,
+// CONNECT tunnels or protocol upgrades like WebSocket.
+// The handler is executed after the request handler returned, no HTTP response is sent.
+// The handler is responsible for the whole communication on the connection, e.g. writing
+// the "101 Switching Protocols" response, and must not use the Ctx, because it is already released.
+// The connection is closed after the handler returned, panics of the handler are logged.
+func (c *DefaultCtx) Hijack(handler HijackHandler) {
+	c.fasthttp.HijackSetNoResponse(true)
+	c.fasthttp.Hijack(func(conn net.Conn) {
+		defer func() {
+			if r := recover(); r != nil {
+				c.app.logw(log.LevelError, "hijack: recovered from panic", "panic", r)
+			}
+		}()
+		handler(conn)
+	})
+}
+
+// Hijacked returns true if the connection was taken over by Hijack.
+func (c *DefaultCtx) Hijacked() bool {
+	return c.fasthttp.Hijacked()
+}
+
+// Tunnel dials the target and connects it with the client after the handler returned.
+// It's used to handle CONNECT requests, e.g. to act as a forward proxy.
+// If the target is empty, the target of the CONNECT request is used.
+// A "200 Connection Established" response is sent to the client, when the target was dialed.
+func (c *DefaultCtx) Tunnel(target string, dialer ...TunnelDialer) error {
+	if target == "" {
+		target = c.OriginalURL()
+	}
+
+	dial := (&net.Dialer{Timeout: tunnelDialTimeout}).DialContext
+	if len(dialer) > 0 && dialer[0] != nil {
+		dial = dialer[0]
+	}
+
+	upstream, err := dial(c.UserContext(), "tcp", target)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBadGateway, err)
+	}
+
+	c.Hijack(func(conn net.Conn) {
+		defer upstream.Close() //nolint:errcheck // It is fine to ignore the error here
+
+		if _, err := conn.Write(tunnelEstablished); err != nil {
+			return
+		}
+
+		// stop the tunnel when one of the connections is closed
+		done := make(chan struct{}, 2)
+		go func() {
+			_, _ = io.Copy(upstream, conn) //nolint:errcheck // It is fine to ignore the error here
+			done <- struct{}{}
+		}()
+		go func() {
+			_, _ = io.Copy(conn, upstream) //nolint:errcheck // It is fine to ignore the error here
+			done <- struct{}{}
+		}()
+		<-done
+	})
+
+	return nil
+}
+
+// Host contains the host derived from the X-Forwarded-Host, Forwarded or Host HTTP header.
 // Returned value is only valid within the handler. Do not store any references.
 // Make copies or use the Immutable setting instead.
 // Please use Config.EnableTrustedProxyCheck to prevent header spoofing, in case when your app is behind the proxy.
@@ -605,8 +667,15 @@ func (c *DefaultCtx) Host() string {
 			}
 			return host
 		}
+		if c.fasthttp.Request.Header.Peek(HeaderForwarded) != nil {
+			for _, element := range c.Forwarded() {
+				if element.Host != "" {
+					return element.Host
+				}
+			}
+		}
 	}
-	return c.app.getString(c.fasthttp.Request.URI().Host())
+	return c.getString(c.fasthttp.Request.URI().Host())
 }
 
 // Hostname contains the hostname derived from the X-Forwarded-Host or Host HTTP header using the c.Host() method.
@@ -630,9 +699,13 @@ func (c *DefaultCtx) Port() string {
 
 // IP returns the remote IP address of the request.
 // If ProxyHeader and IP Validation is configured, it will parse that header and return the first valid IP address.
+// If ProxyHeader is the Forwarded header, the IP address of the first "for" node is returned.
 // Please use Config.EnableTrustedProxyCheck to prevent header spoofing, in case when your app is behind the proxy.
 func (c *DefaultCtx) IP() string {
 	if c.IsProxyTrusted() && len(c.app.config.ProxyHeader) > 0 {
+		if c.app.config.ProxyHeader == HeaderForwarded {
+			return c.extractIPFromForwarded()
+		}
 		return c.extractIPFromHeader(c.app.config.ProxyHeader)
 	}
 
@@ -752,9 +825,36 @@ func (c *DefaultCtx) extractIPFromHeader(header string) string {
 	return c.Get(c.app.config.ProxyHeader)
 }
 
-// IPs returns a string slice of IP addresses specified in the X-Forwarded-For request header.
+// extractIPFromForwarded returns the IP address of the first "for" node of the Forwarded header.
+// When IP validation is enabled, the first valid IP address is returned, like in extractIPFromHeader.
+func (c *DefaultCtx) extractIPFromForwarded() string {
+	for _, element := range c.Forwarded() {
+		if ip := element.IP(); ip != "" {
+			return ip
+		}
+		if !c.app.config.EnableIPValidation {
+			return element.For
+		}
+	}
+	return c.fasthttp.RemoteIP().String()
+}
+
+// IPs returns a string slice of IP addresses specified in the X-Forwarded-For request header,
+// or of the "for" nodes of the Forwarded header, if ProxyHeader is the Forwarded header.
 // When IP validation is enabled, only valid IPs are returned.
 func (c *DefaultCtx) IPs() []string {
+	if c.app.config.ProxyHeader == HeaderForwarded {
+		elements := c.Forwarded()
+		ips := make([]string, 0, len(elements))
+		for _, element := range elements {
+			if ip := element.IP(); ip != "" {
+				ips = append(ips, ip)
+			} else if !c.app.config.EnableIPValidation && element.For != "" {
+				ips = append(ips, element.For)
+			}
+		}
+		return ips
+	}
 	return c.extractIPsFromHeader(HeaderXForwardedFor)
 }
 
@@ -780,11 +880,20 @@ func (c *DefaultCtx) Is(extension string) bool {
 // Content-Type header equal to ctype. If ctype is not given,
 // The Content-Type header will be set to application/json.
 func (c *DefaultCtx) JSON(data any, ctype ...string) error {
-	raw, err := c.app.config.JSONEncoder(data)
-	if err != nil {
-		return err
+	if c.app.defaultJSONEncoder {
+		if err := c.encodeJSON(data); err != nil {
+			return err
+		}
+	} else {
+		raw, err := c.app.config.JSONEncoder(data)
+		if err != nil {
+			return err
+		}
+		if c.app.config.JSONEscapeHTML {
+			raw = escapeJSONHTML(raw)
+		}
+		c.fasthttp.Response.SetBodyRaw(raw)
 	}
-	c.fasthttp.Response.SetBodyRaw(raw)
 	if len(ctype) > 0 {
 		c.fasthttp.Response.Header.SetContentType(ctype[0])
 	} else {
@@ -801,6 +910,9 @@ func (c *DefaultCtx) JSONP(data any, callback ...string) error {
 	if err != nil {
 		return err
 	}
+	if c.app.config.JSONEscapeHTML {
+		raw = escapeJSONHTML(raw)
+	}
 
 	var result, cb string
 
@@ -953,7 +1065,7 @@ func (c *DefaultCtx) RestartRouting() error {
 // Returned value is only valid within the handler. Do not store any references.
 // Make copies or use the Immutable setting to use the value outside the Handler.
 func (c *DefaultCtx) OriginalURL() string {
-	return c.app.getString(c.fasthttp.Request.Header.RequestURI())
+	return c.getString(c.fasthttp.Request.Header.RequestURI())
 }
 
 // Params is used to get the route parameters.
@@ -962,8 +1074,12 @@ func (c *DefaultCtx) OriginalURL() string {
 // Returned value is only valid within the handler. Do not store any references.
 // Make copies or use the Immutable setting to use the value outside the Handler.
 func (c *DefaultCtx) Params(key string, defaultValue ...string) string {
-	if key == "*" || key == "+" {
-		key += "1"
+	// the names are constants, so no string is allocated
+	switch key {
+	case "*":
+		key = "*1"
+	case "+":
+		key = "+1"
 	}
 	for i := range c.route.Params {
 		if len(key) != len(c.route.Params[i]) {
@@ -1026,6 +1142,15 @@ func (c *DefaultCtx) Scheme() string {
 	scheme := schemeHTTP
 	const lenXHeaderName = 12
 	c.fasthttp.Request.Header.VisitAll(func(key, val []byte) {
+		if bytes.Equal(key, []byte(HeaderForwarded)) {
+			for _, element := range ParseForwarded(c.getString(val)) {
+				if element.Proto != "" {
+					scheme = element.Proto
+					return
+				}
+			}
+			return
+		}
 		if len(key) < lenXHeaderName {
 			return // Neither "X-Forwarded-" nor "X-Url-Scheme"
 		}
@@ -1033,7 +1158,7 @@ func (c *DefaultCtx) Scheme() string {
 		case bytes.HasPrefix(key, []byte("X-Forwarded-")):
 			if bytes.Equal(key, []byte(HeaderXForwardedProto)) ||
 				bytes.Equal(key, []byte(HeaderXForwardedProtocol)) {
-				v := c.app.getString(val)
+				v := c.getString(val)
 				commaPos := strings.Index(v, ",")
 				if commaPos != -1 {
 					scheme = v[:commaPos]
@@ -1045,7 +1170,7 @@ func (c *DefaultCtx) Scheme() string {
 			}
 
 		case bytes.Equal(key, []byte(HeaderXUrlScheme)):
-			scheme = c.app.getString(val)
+			scheme = c.getString(val)
 		}
 	})
 	return scheme
@@ -1089,7 +1214,7 @@ func (c *DefaultCtx) Query(key string, defaultValue ...string) string {
 func (c *DefaultCtx) Queries() map[string]string {
 	m := make(map[string]string, c.Context().QueryArgs().Len())
 	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
-		m[c.app.getString(key)] = c.app.getString(value)
+		m[c.getString(key)] = c.getString(value)
 	})
 	return m
 }
@@ -1117,7 +1242,7 @@ func (c *DefaultCtx) Queries() map[string]string {
 //	unknown := Query[string](c, "unknown", "default") // Returns "default" since the query parameter "unknown" is not found
 func Query[V GenericType](c Ctx, key string, defaultValue ...V) V {
 	var v V
-	q := c.App().getString(c.Context().QueryArgs().Peek(key))
+	q := c.getString(c.Context().QueryArgs().Peek(key))
 
 	return genericParseType[V](q, v, defaultValue...)
 }
@@ -1249,10 +1374,41 @@ func (c *DefaultCtx) GetRouteURL(routeName string, params Map) (string, error) {
 // Render a template with data and sends a text/html response.
 // We support the following engines: https://github.com/gofiber/template
 func (c *DefaultCtx) Render(name string, bind Map, layouts ...string) error {
+	// Render cached pages of the render cache
+	if cache := c.app.config.RenderCache; cache != nil {
+		if rule, ok := cache.rule(name); ok {
+			return c.renderCached(cache, rule, name, bind, layouts)
+		}
+	}
+
 	// Get new buffer from pool
 	buf := bytebufferpool.Get()
 	defer bytebufferpool.Put(buf)
 
+	if err := c.renderTemplate(buf, name, bind, layouts); err != nil {
+		return err
+	}
+
+	// Set Content-Type to text/html
+	c.fasthttp.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
+	// Set rendered template to body
+	c.fasthttp.Response.SetBody(buf.Bytes())
+
+	return nil
+}
+
+// renderTemplate renders the template into the writer.
+func (c *DefaultCtx) renderTemplate(w io.Writer, name string, bind Map, layouts []string) error {
+	execute, err := c.prepareRender(name, bind, layouts)
+	if err != nil {
+		return err
+	}
+	return execute(w)
+}
+
+// prepareRender resolves the view engine of the template and returns the function which
+// executes the template, it doesn't access the context, so it can be called after the handler.
+func (c *DefaultCtx) prepareRender(name string, bind Map, layouts []string) (func(w io.Writer) error, error) {
 	// Initialize empty bind map if bind is nil
 	if bind == nil {
 		bind = make(Map)
@@ -1261,7 +1417,6 @@ func (c *DefaultCtx) Render(name string, bind Map, layouts ...string) error {
 	// Pass-locals-to-views, bind, appListKeys
 	c.renderExtensions(bind)
 
-	var rendered bool
 	for i := len(c.app.mountFields.appListKeys) - 1; i >= 0; i-- {
 		prefix := c.app.mountFields.appListKeys[i]
 		app := c.app.mountFields.appList[prefix]
@@ -1273,41 +1428,35 @@ func (c *DefaultCtx) Render(name string, bind Map, layouts ...string) error {
 			}
 
 			// Render template from Views
-			if app.config.Views != nil {
-				if err := app.config.Views.Render(buf, name, bind, layouts...); err != nil {
-					return fmt.Errorf("failed to render: %w", err)
-				}
-
-				rendered = true
-				break
+			if views := app.config.Views; views != nil {
+				return func(w io.Writer) error {
+					if err := views.Render(w, name, bind, layouts...); err != nil {
+						return fmt.Errorf("failed to render: %w", err)
+					}
+					return nil
+				}, nil
 			}
 		}
 	}
 
-	if !rendered {
-		// Render raw template using 'name' as filepath if no engine is set
-		var tmpl *template.Template
-		if _, err := readContent(buf, name); err != nil {
-			return err
-		}
-		// Parse template
-		tmpl, err := template.New("").Parse(c.app.getString(buf.Bytes()))
-		if err != nil {
-			return fmt.Errorf("failed to parse: %w", err)
-		}
-		buf.Reset()
+	// Render raw template using 'name' as filepath if no engine is set
+	buf := bytebufferpool.Get()
+	defer bytebufferpool.Put(buf)
+	if _, err := readContent(buf, name); err != nil {
+		return nil, err
+	}
+	// Parse template
+	tmpl, err := template.New("").Funcs(StreamFuncMap()).Parse(buf.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse: %w", err)
+	}
+	return func(w io.Writer) error {
 		// Render template
-		if err := tmpl.Execute(buf, bind); err != nil {
+		if err := tmpl.Execute(w, bind); err != nil {
 			return fmt.Errorf("failed to execute: %w", err)
 		}
-	}
-
-	// Set Content-Type to text/html
-	c.fasthttp.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
-	// Set rendered template to body
-	c.fasthttp.Response.SetBody(buf.Bytes())
-
-	return nil
+		return nil
+	}, nil
 }
 
 func (c *DefaultCtx) renderExtensions(bind any) {
@@ -1495,16 +1644,27 @@ func (c *DefaultCtx) SendString(body string) error {
 }
 
 // SendStream sets response body stream and optional body size.
+// If the stream is a regular *os.File, the size is determined automatically,
+// so the file can be sent with sendfile.
 func (c *DefaultCtx) SendStream(stream io.Reader, size ...int) error {
 	if len(size) > 0 && size[0] >= 0 {
-		c.fasthttp.Response.SetBodyStream(stream, size[0])
+		c.setBodyStream(stream, size[0])
 	} else {
-		c.fasthttp.Response.SetBodyStream(stream, -1)
+		c.setBodyStream(stream, fileStreamSize(stream))
 	}
 
 	return nil
 }
 
+// SendStreamWriter sets the response body stream writer, which writes the body while
+// the response is sent, e.g. for server-sent events. It is like SetBodyStreamWriter
+// of fasthttp, but the body is limited by the maximum response size of the route.
+func (c *DefaultCtx) SendStreamWriter(streamWriter func(w *bufio.Writer)) error {
+	c.setBodyStream(fasthttp.NewStreamReader(streamWriter), -1)
+
+	return nil
+}
+
 // Set sets the response's HTTP header field to the specified key, value.
 func (c *DefaultCtx) Set(key, val string) {
 	c.fasthttp.Response.Header.Set(key, val)
@@ -1594,8 +1754,9 @@ func (c *DefaultCtx) Type(extension string, charset ...string) Ctx {
 
 // Vary adds the given header field to the Vary response header.
 // This will append the header, if not already listed, otherwise leaves it listed in the current location.
+// The fields are tracked like with VaryBy.
 func (c *DefaultCtx) Vary(fields ...string) {
-	c.Append(HeaderVary, fields...)
+	c.VaryBy(fields...)
 }
 
 // Write appends p into response body.
@@ -1626,11 +1787,15 @@ func (c *DefaultCtx) XHR() bool {
 // here the features for caseSensitive, decoded paths, strict paths are evaluated
 func (c *DefaultCtx) configDependentPaths() {
 	c.pathBuffer = append(c.pathBuffer[0:0], c.pathOriginal...)
+	// Normalize the path before the routing
+	if c.app.pathNormalization {
+		c.pathBuffer = c.app.config.PathNormalization.normalize(c.pathBuffer)
+	}
 	// If UnescapePath enabled, we decode the path and save it for the framework user
 	if c.app.config.UnescapePath {
 		c.pathBuffer = fasthttp.AppendUnquotedArg(c.pathBuffer[:0], c.pathBuffer)
 	}
-	c.path = c.app.getString(c.pathBuffer)
+	c.path = c.getString(c.pathBuffer)
 
 	// another path is specified which is for routing recognition only
 	// use the path that was changed by the previous configuration flags
@@ -1643,13 +1808,16 @@ func (c *DefaultCtx) configDependentPaths() {
 	if !c.app.config.StrictRouting && len(c.detectionPathBuffer) > 1 && c.detectionPathBuffer[len(c.detectionPathBuffer)-1] == '/' {
 		c.detectionPathBuffer = bytes.TrimRight(c.detectionPathBuffer, "/")
 	}
-	c.detectionPath = c.app.getString(c.detectionPathBuffer)
+	c.detectionPath = c.getString(c.detectionPathBuffer)
 
 	// Define the path for dividing routes into areas for fast tree detection, so that fewer routes need to be traversed,
 	// since the first three characters area select a list of routes
+	// compiled route trees are divided by the static prefixes of the routes
 	c.treePath = c.treePath[0:0]
 	const maxDetectionPaths = 3
-	if len(c.detectionPath) >= maxDetectionPaths {
+	if c.routeMatcher != nil {
+		c.treePath = c.routeMatcher.lookup(c.detectionPath)
+	} else if len(c.detectionPath) >= maxDetectionPaths {
 		c.treePath = c.detectionPath[:maxDetectionPaths]
 	}
 }
@@ -5,13 +5,16 @@
 package fiber
 
 import (
+	"bufio"
 	"context"
 	"crypto/tls"
 	"errors"
 	"io"
 	"mime/multipart"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/valyala/fasthttp"
 )
 
@@ -63,9 +66,23 @@ type Ctx interface {
 	// SetUserContext sets a context implementation by user.
 	SetUserContext(ctx context.Context)
 
+	// Done returns a channel which is closed when the client closed the connection
+	// or the request was handled.
+	Done() <-chan struct{}
+
+	// IsAborted reports if the client closed the connection of the request.
+	IsAborted() bool
+
 	// Cookie sets a cookie by passing a cookie struct.
 	Cookie(cookie *Cookie)
 
+	// SetCookies validates and sets the cookies, it sets none of them if a cookie is invalid,
+	// too large or if the response would have too many cookies.
+	SetCookies(cookies ...*Cookie) error
+
+	// ClearCookies expires the cookies on the client side with their Path, Domain and attributes.
+	ClearCookies(cookies ...*Cookie)
+
 	// Cookies is used for getting a cookie value by key.
 	// Defaults to the empty string "" if the cookie doesn't exist.
 	// If a default value is given, it will return that value if the cookie doesn't exist.
@@ -73,6 +90,9 @@ type Ctx interface {
 	// Make copies or use the Immutable setting to use the value outside the Handler.
 	Cookies(key string, defaultValue ...string) string
 
+	// CSVStream streams the header and the rows as CSV file, the rows are produced while the response is sent.
+	CSVStream(header []string, rows Rows, config ...CSVConfig) error
+
 	// Download transfers the file from path as an attachment.
 	// Typically, browsers will prompt the user for download.
 	// By default, the Content-Disposition header filename= parameter is the filepath (this typically appears in the browser dialog).
@@ -104,6 +124,10 @@ type Ctx interface {
 	// If the header is not specified or there is no proper format, text/plain is used.
 	AutoFormat(body any) error
 
+	// HTML sends a text/html response which is formatted like fmt.Sprintf.
+	// The arguments are HTML-escaped, except TrustedHTML values, numbers and booleans.
+	HTML(format string, args ...any) error
+
 	// FormFile returns the first file by key from a MultipartForm.
 	FormFile(key string) (*multipart.FileHeader, error)
 
@@ -128,6 +152,17 @@ type Ctx interface {
 	// Make copies or use the Immutable setting instead.
 	Get(key string, defaultValue ...string) string
 
+	// HeaderInt returns the request header as int, or a *ParamError which is returned
+	// by the handler as 400 Bad Request if it's invalid. A missing header returns the
+	// default value, or a *ParamError without a default value.
+	HeaderInt(key string, defaultValue ...int) (int, error)
+
+	// HeaderTime returns the request header as time of an HTTP date, see HeaderInt.
+	HeaderTime(key string, defaultValue ...time.Time) (time.Time, error)
+
+	// RequiredHeader returns the request header, or a *ParamError if it's missing or empty.
+	RequiredHeader(key string) (string, error)
+
 	// GetRespHeader returns the HTTP response header specified by field.
 	// Field names are case-insensitive
 	// Returned value is only valid within the handler. Do not store any references.
@@ -144,7 +179,40 @@ type Ctx interface {
 	// Make copies or use the Immutable setting instead.
 	GetReqHeaders() map[string][]string
 
-	// Host contains the host derived from the X-Forwarded-Host or Host HTTP header.
+	// Hijack takes over the underlying connection to implement custom protocols,
+	// CONNECT tunnels or protocol upgrades like WebSocket.
+	// The handler is executed after the request handler returned, no HTTP response is sent.
+	// The handler is responsible for the whole communication on the connection, e.g. writing
+	// the "101 Switching Protocols" response, and must not use the Ctx, because it is already released.
+	// The connection is closed after the handler returned, panics of the handler are logged.
+	Hijack(handler HijackHandler)
+
+	// Hijacked returns true if the connection was taken over by Hijack.
+	Hijacked() bool
+
+	// PreallocateResponse is a hint that the response body will have about n bytes.
+	// The response body buffer is grown to n bytes, so it doesn't have to be grown
+	// while the body is written. It has to be called before the body is written.
+	PreallocateResponse(n int)
+
+	// Push initiates an HTTP/2 server push of the target, e.g. a stylesheet which is needed
+	// to render the response. ErrPushNotSupported is returned, if the request wasn't made
+	// with HTTP/2 or the client disabled server push.
+	Push(target string) error
+
+	// Tunnel dials the target and connects it with the client after the handler returned.
+	// It's used to handle CONNECT requests, e.g. to act as a forward proxy.
+	// If the target is empty, the target of the CONNECT request is used.
+	// A "200 Connection Established" response is sent to the client, when the target was dialed.
+	Tunnel(target string, dialer ...TunnelDialer) error
+
+	// Forwarded returns the elements of the Forwarded request headers in the order of the proxies.
+	// The headers are ignored if the proxy isn't trusted, see Config.EnableTrustedProxyCheck.
+	// Returned value is only valid within the handler. Do not store any references.
+	// Make copies or use the Immutable setting instead.
+	Forwarded() []ForwardedElement
+
+	// Host contains the host derived from the X-Forwarded-Host, Forwarded or Host HTTP header.
 	// Returned value is only valid within the handler. Do not store any references.
 	// Make copies or use the Immutable setting instead.
 	// Please use Config.EnableTrustedProxyCheck to prevent header spoofing, in case when your app is behind the proxy.
@@ -163,7 +231,8 @@ type Ctx interface {
 	// Please use Config.EnableTrustedProxyCheck to prevent header spoofing, in case when your app is behind the proxy.
 	IP() string
 
-	// IPs returns an string slice of IP addresses specified in the X-Forwarded-For request header.
+	// IPs returns an string slice of IP addresses specified in the X-Forwarded-For request header,
+	// or of the "for" nodes of the Forwarded header, if ProxyHeader is the Forwarded header.
 	IPs() (ips []string)
 
 	// Is returns the matching content type,
@@ -184,6 +253,9 @@ type Ctx interface {
 	// By default, the callback name is simply callback.
 	JSONP(data any, callback ...string) error
 
+	// XLSXStream streams the header and the rows as Excel workbook, the rows are produced while the response is sent.
+	XLSXStream(header []string, rows Rows, config ...XLSXConfig) error
+
 	// XML converts any interface or string to XML.
 	// This method also sets the content header to application/xml.
 	XML(data any) error
@@ -224,6 +296,26 @@ type Ctx interface {
 	// Make copies or use the Immutable setting to use the value outside the Handler.
 	Params(key string, defaultValue ...string) string
 
+	// ParamsInt64 returns the route parameter as int64, or a *ParamError which is
+	// returned by the handler as 400 Bad Request if it's missing or invalid.
+	ParamsInt64(key string) (int64, error)
+
+	// ParamsUint returns the route parameter as uint64, or a *ParamError.
+	ParamsUint(key string) (uint64, error)
+
+	// ParamsFloat returns the route parameter as float64, or a *ParamError.
+	ParamsFloat(key string) (float64, error)
+
+	// ParamsBool returns the route parameter as bool, or a *ParamError.
+	ParamsBool(key string) (bool, error)
+
+	// ParamsUUID returns the route parameter as UUID, or a *ParamError.
+	ParamsUUID(key string) (uuid.UUID, error)
+
+	// ParamsTime returns the route parameter as time of the layout, time.RFC3339 by default,
+	// or a *ParamError.
+	ParamsTime(key string, layout ...string) (time.Time, error)
+
 	// Path returns the path part of the request URL.
 	// Optionally, you could override the path.
 	Path(override ...string) string
@@ -265,9 +357,51 @@ type Ctx interface {
 	// Make copies or use the Immutable setting to use the value outside the Handler.
 	Query(key string, defaultValue ...string) string
 
+	// QueryInt returns the query parameter as int, or a *ParamError which is returned
+	// by the handler as 400 Bad Request if it's invalid. A missing parameter returns
+	// the default value, or a *ParamError without a default value.
+	QueryInt(key string, defaultValue ...int) (int, error)
+
+	// QueryFloat returns the query parameter as float64, see QueryInt.
+	QueryFloat(key string, defaultValue ...float64) (float64, error)
+
+	// QueryBool returns the query parameter as bool, see QueryInt.
+	QueryBool(key string, defaultValue ...bool) (bool, error)
+
+	// RequiredQuery returns the query parameter, or a *ParamError if it's missing or empty.
+	RequiredQuery(key string) (string, error)
+
 	// Range returns a struct containing the type and a slice of ranges.
 	Range(size int) (rangeData Range, err error)
 
+	// Pagination parses the query params limit, offset, page and cursor, the limit
+	// defaults to Config.PaginationDefaultLimit and is capped by Config.PaginationMaxLimit.
+	Pagination() (Pagination, error)
+
+	// PaginationHeaders sets the Link header with the pages of the pagination
+	// and the X-Total-Count header. A negative total is unknown.
+	PaginationHeaders(p Pagination, total int)
+
+	// Sort parses the sort order of the query param sort, e.g. "sort=-created_at,name".
+	// Only the allowed fields can be sorted.
+	Sort(allowed ...string) ([]SortField, error)
+
+	// Filters parses the filter expressions of the query params of the allowed fields,
+	// e.g. "status=eq:open".
+	Filters(allowed ...string) ([]Filter, error)
+
+	// SetEntityVersion sets the ETag and Last-Modified headers of the version.
+	SetEntityVersion(version EntityVersion)
+
+	// CheckPreconditions evaluates the If-Match and If-Unmodified-Since headers of the request
+	// against the current version of the entity and returns ErrPreconditionFailed (412) or,
+	// if Config.RequirePreconditions is set, ErrPreconditionRequired (428).
+	CheckPreconditions(version EntityVersion) error
+
+	// ContentRange parses and validates the Content-Range header of an upload,
+	// e.g. "bytes 0-999/5000".
+	ContentRange() (ContentRange, error)
+
 	// Redirect returns the Redirect reference.
 	// Use Redirect().Status() to set custom redirection status code.
 	// If status is not specified, status defaults to 302 Found.
@@ -281,10 +415,22 @@ type Ctx interface {
 	// GetRouteURL generates URLs to named routes, with parameters. URLs are relative, for example: "/user/1831"
 	GetRouteURL(routeName string, params Map) (string, error)
 
+	// Negotiate responds with the data in the format which the Accept header prefers: the template
+	// rendered as HTML, the bind as JSON, or a document of a registered Renderer, e.g. a PDF.
+	Negotiate(name string, bind Map, layouts ...string) error
+
 	// Render a template with data and sends a text/html response.
 	// We support the following engines: https://github.com/gofiber/template
 	Render(name string, bind Map, layouts ...string) error
 
+	// RenderStream renders a template like Render, but sends the output up to
+	// each {{ flush }} point of the template progressively.
+	RenderStream(name string, bind Map, layouts ...string) error
+
+	// OnCommitted registers a hook which is called after the response was written
+	// to the connection successfully, e.g. to publish the domain events of the request.
+	OnCommitted(hook func())
+
 	// Route returns the matched Route struct.
 	Route() *Route
 
@@ -301,6 +447,14 @@ type Ctx interface {
 	// From this point onward the body argument must not be changed.
 	Send(body []byte) error
 
+	// SendArchive streams a zip or tar.gz archive of the entries, which is built while it's sent.
+	// The handler is called after each file was written.
+	SendArchive(entries []ArchiveEntry, format ArchiveFormat, onEntry ...ArchiveEntryHandler) error
+
+	// SendCloudEvent sends the event with the data in the mode, CloudEventBinary by default.
+	// The data is encoded with the JSONEncoder of the app unless it is a []byte or a string.
+	SendCloudEvent(event CloudEvent, data any, mode ...CloudEventMode) error
+
 	// SendFile transfers the file from the given path.
 	// The file is not compressed by default, enable this by passing a 'true' argument
 	// Sets the Content-Type response HTTP header field based on the filenames extension.
@@ -317,6 +471,9 @@ type Ctx interface {
 	// SendStream sets response body stream and optional body size.
 	SendStream(stream io.Reader, size ...int) error
 
+	// SendStreamWriter sets the response body stream writer, which writes the body while the response is sent.
+	SendStreamWriter(streamWriter func(w *bufio.Writer)) error
+
 	// Set sets the response's HTTP header field to the specified key, value.
 	Set(key, val string)
 
@@ -343,6 +500,11 @@ type Ctx interface {
 	// This will append the header, if not already listed, otherwise leaves it listed in the current location.
 	Vary(fields ...string)
 
+	// VaryBy records that the response depends on the request headers and adds them to the Vary header.
+	// After the handlers returned, the Vary header is emitted once with the fields of all VaryBy calls
+	// of the request without duplicates.
+	VaryBy(fields ...string)
+
 	// Write appends p into response body.
 	Write(p []byte) (int, error)
 
@@ -375,8 +537,21 @@ type Ctx interface {
 	// ClientHelloInfo return CHI from context
 	ClientHelloInfo() *tls.ClientHelloInfo
 
+	// ClientCertificate returns the identity of the verified client certificate of a mTLS request,
+	// e.g. its SPIFFE ID, SANs and fingerprint. It returns nil if the request wasn't made over TLS
+	// or the client certificate wasn't verified.
+	ClientCertificate() *ClientCertificate
+
 	// Release is a method to reset context fields when to use ReleaseCtx()
 	release()
+
+	// getString converts the bytes of the request or response into a string
+	// which follows the Immutable and ImmutableArena settings.
+	getString(b []byte) string
+
+	// internString converts the bytes of a header into a string, which is interned
+	// with the InternCacheSize setting.
+	internString(b []byte) string
 }
 
 type CustomCtx interface {
@@ -389,6 +564,7 @@ type CustomCtx interface {
 	getMethodINT() int
 	getIndexRoute() int
 	getTreePath() string
+	getTreeStack() []map[string][]*Route
 	getDetectionPath() string
 	getPathOriginal() string
 	getValues() *[maxParams]string
@@ -397,6 +573,9 @@ type CustomCtx interface {
 	setIndexRoute(route int)
 	setMatched(matched bool)
 	setRoute(route *Route)
+	abortState() *requestAbort
+	getCommitted() []func()
+	getVary() []string
 }
 
 func NewDefaultCtx(app *App) *DefaultCtx {
@@ -444,26 +623,44 @@ func (c *DefaultCtx) Reset(fctx *fasthttp.RequestCtx) {
 	c.indexHandler = 0
 	// Reset matched flag
 	c.matched = false
-	// Set paths
-	c.pathOriginal = c.app.getString(fctx.URI().PathOriginal())
 	// Set method
-	c.method = c.app.getString(fctx.Request.Header.Method())
+	c.method = c.getString(fctx.Request.Header.Method())
 	c.methodINT = c.app.methodInt(c.method)
+	// Set paths
+	c.pathOriginal = c.getString(fctx.URI().PathOriginal())
+	// CONNECT requests in authority-form (host:port) are routed as "/"
+	if c.method == MethodConnect && (c.pathOriginal == "" || c.pathOriginal[0] != '/') {
+		c.pathOriginal = "/"
+	}
 	// Attach *fasthttp.RequestCtx to ctx
 	c.fasthttp = fctx
+	// Use the currently active route tree for the whole request
+	tree := c.app.loadTree()
+	c.treeStack, c.routeMatcher = tree.stack, tree.matcher
 	// reset base uri
 	c.baseURI = ""
+	// reset response size hint
+	c.responseSizeHint = 0
+	c.allocAuditStart = 0
+	c.abort.reset()
 	// Prettify path
 	c.configDependentPaths()
 }
 
 // Release is a method to reset context fields when to use ReleaseCtx()
 func (c *DefaultCtx) release() {
+	// stop the watcher of the connection and close the done channel, if they were used
+	c.abort.finish()
 	c.route = nil
 	c.fasthttp = nil
+	c.treeStack = nil
+	c.routeMatcher = nil
 	c.bind = nil
+	c.arena.reset()
 	c.redirectionMessages = c.redirectionMessages[:0]
 	c.viewBindMap = sync.Map{}
+	c.committed = nil
+	c.vary = c.vary[:0]
 	if c.redirect != nil {
 		ReleaseRedirect(c.redirect)
 		c.redirect = nil
@@ -483,6 +680,10 @@ func (c *DefaultCtx) getTreePath() string {
 	return c.treePath
 }
 
+func (c *DefaultCtx) getTreeStack() []map[string][]*Route {
+	return c.treeStack
+}
+
 func (c *DefaultCtx) getDetectionPath() string {
 	return c.detectionPath
 }
@@ -29,9 +29,11 @@ import (
 
 	"github.com/gofiber/fiber/v3/internal/storage/memory"
 	"github.com/gofiber/utils/v2"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/bytebufferpool"
 	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
 )
 
 const epsilon = 0.001
@@ -1482,6 +1484,115 @@ func Test_Ctx_GetReqHeader(t *testing.T) {
 	require.Equal(t, "bar", GetReqHeader[string](c, "foo"))
 }
 
+// go test -run Test_Ctx_Hijack
+func Test_Ctx_Hijack(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		require.False(t, c.Hijacked())
+		c.Hijack(func(conn net.Conn) {
+			buf := make([]byte, 4)
+			if _, err := io.ReadFull(conn, buf); err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\n\r\n")) //nolint:errcheck // not needed
+			_, _ = conn.Write(buf)                                                                 //nolint:errcheck // not needed
+		})
+		require.True(t, c.Hijacked())
+		return c.SendString("ignored")
+	})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+	}()
+
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		var err error
+		conn, err = ln.Dial()
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\nping"))
+	require.NoError(t, err)
+
+	// the connection is closed after the hijack handler returned
+	data, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	require.Equal(t, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\n\r\nping", string(data))
+	require.NoError(t, app.Shutdown())
+}
+
+// go test -run Test_Ctx_Tunnel
+func Test_Ctx_Tunnel(t *testing.T) {
+	t.Parallel()
+
+	// echo server as target
+	target := fasthttputil.NewInmemoryListener()
+	defer target.Close() //nolint:errcheck // not needed
+	go func() {
+		conn, err := target.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()         //nolint:errcheck // not needed
+		_, _ = io.Copy(conn, conn) //nolint:errcheck // not needed
+	}()
+
+	var address string
+	app := New()
+	app.Connect("/", func(c Ctx) error {
+		return c.Tunnel("", func(_ context.Context, _, addr string) (net.Conn, error) {
+			address = addr
+			return target.Dial()
+		})
+	})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+	}()
+
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		var err error
+		conn, err = ln.Dial()
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, err := conn.Write([]byte("CONNECT example.com:443 HTTP/1.1\r\nHost: example.com:443\r\n\r\nping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, len(tunnelEstablished)+4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	require.Equal(t, "HTTP/1.1 200 Connection Established\r\n\r\nping", string(buf))
+	require.Equal(t, "example.com:443", address)
+
+	require.NoError(t, conn.Close())
+	require.NoError(t, app.Shutdown())
+}
+
+// go test -run Test_Ctx_Tunnel_DialError
+func Test_Ctx_Tunnel_DialError(t *testing.T) {
+	t.Parallel()
+	errDial := errors.New("dial failed")
+
+	app := New()
+	app.Connect("/", func(c Ctx) error {
+		err := c.Tunnel("", func(context.Context, string, string) (net.Conn, error) {
+			return nil, errDial
+		})
+		require.ErrorIs(t, err, errDial)
+		return err
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodConnect, "http://example.com:443", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusBadGateway, resp.StatusCode)
+}
+
 // go test -run Test_Ctx_Host
 func Test_Ctx_Host(t *testing.T) {
 	t.Parallel()
@@ -3959,6 +4070,32 @@ func Test_Ctx_SendStream(t *testing.T) {
 	require.Equal(t, "Hello bufio", string(c.Response().Body()))
 }
 
+// go test -run Test_Ctx_SendStream_File
+func Test_Ctx_SendStream_File(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		f, err := os.Open("./.github/testdata/index.html")
+		require.NoError(t, err)
+		// skip the first byte
+		_, err = f.Seek(1, io.SeekStart)
+		require.NoError(t, err)
+		return c.SendStream(f)
+	})
+
+	expected, err := os.ReadFile("./.github/testdata/index.html")
+	require.NoError(t, err)
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(expected)-1), resp.ContentLength)
+	require.Empty(t, resp.TransferEncoding)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, expected[1:], body)
+}
+
 // go test -run Test_Ctx_Set
 func Test_Ctx_Set(t *testing.T) {
 	t.Parallel()
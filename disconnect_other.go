@@ -0,0 +1,15 @@
+//go:build !unix
+
+package fiber
+
+import (
+	"net"
+)
+
+// disconnectCheckSupported reports if isConnClosed can detect disconnects.
+const disconnectCheckSupported = false
+
+// isConnClosed is not supported on this platform, the connection is always open.
+func isConnClosed(net.Conn) bool {
+	return false
+}
@@ -0,0 +1,43 @@
+//go:build unix
+
+package fiber
+
+import (
+	"net"
+	"syscall"
+)
+
+// disconnectCheckSupported reports if isConnClosed can detect disconnects.
+const disconnectCheckSupported = true
+
+// isConnClosed reports if the client closed the connection, without consuming
+// the data of pipelined requests.
+func isConnClosed(conn net.Conn) bool {
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return false
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return false
+	}
+
+	closed := false
+	var buf [1]byte
+	err = raw.Read(func(fd uintptr) bool {
+		n, _, err := syscall.Recvfrom(int(fd), buf[:], syscall.MSG_PEEK)
+		switch {
+		case err == syscall.EAGAIN || err == syscall.EWOULDBLOCK: //nolint:errorlint // syscall errors are not wrapped
+			// no data, the connection is open
+		case err != nil:
+			closed = true
+		case n == 0:
+			// EOF
+			closed = true
+		}
+		// don't wait until the connection is readable
+		return true
+	})
+
+	return closed || err != nil
+}
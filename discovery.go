@@ -0,0 +1,140 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"strings"
+
+	"github.com/gofiber/utils/v2"
+)
+
+// DefaultDiscoveryPath is the default path of the discovery endpoint.
+const DefaultDiscoveryPath = "/.well-known/fiber.json"
+
+// DiscoveryConfig is a struct to configure the discovery endpoint of EnableDiscovery.
+type DiscoveryConfig struct {
+	// Path of the discovery endpoint.
+	//
+	// Default: DefaultDiscoveryPath
+	Path string `json:"path"`
+
+	// Version of the service, e.g. the version of its API.
+	//
+	// Default: the version of the build info of the app, see App.BuildInfo
+	Version string `json:"version"`
+
+	// Health reports if the service is healthy, unhealthy services respond with 503.
+	//
+	// Default: nil (healthy unless the maintenance mode is enabled or the app isn't ready)
+	Health func() bool `json:"-"`
+
+	// Filter reports if a route is listed.
+	//
+	// Default: nil (all routes except the discovery endpoint)
+	Filter func(route Route) bool `json:"-"`
+}
+
+// Discovery is the response of the discovery endpoint.
+type Discovery struct {
+	Name         string           `json:"name,omitempty"`
+	Version      string           `json:"version,omitempty"`
+	FiberVersion string           `json:"fiber_version"`
+	Status       string           `json:"status"`
+	Build        BuildInfo        `json:"build"`
+	Routes       []DiscoveryRoute `json:"routes"`
+}
+
+// DiscoveryRoute is a route of the discovery endpoint.
+type DiscoveryRoute struct {
+	Method string   `json:"method"`
+	Path   string   `json:"path"`
+	Name   string   `json:"name,omitempty"`
+	Params []string `json:"params,omitempty"`
+}
+
+// The status of the discovery endpoint
+const (
+	DiscoveryStatusUp          = "up"
+	DiscoveryStatusDown        = "down"
+	DiscoveryStatusMaintenance = "maintenance"
+	DiscoveryStatusStarting    = "starting"
+	DiscoveryStatusDegraded    = "degraded"
+)
+
+// EnableDiscovery registers the discovery endpoint, which lists the routes, the versions, the
+// health status and the build info of the app for service catalogs and gateways. The routes
+// can be filtered with the query params "method" and "prefix", e.g. "?prefix=/api". The
+// endpoint responds in maintenance mode and can be turned off at runtime with SetDiscoveryEnabled.
+//
+//	GET /.well-known/fiber.json
+//	{"name": "orders", "version": "1.4.0", "status": "up", "routes": [{"method": "GET", "path": "/orders"}]}
+func (app *App) EnableDiscovery(config ...DiscoveryConfig) Router {
+	var cfg DiscoveryConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Path == "" {
+		cfg.Path = DefaultDiscoveryPath
+	}
+	app.discoveryEnabled.Store(true)
+	app.discoveryPath = cfg.Path
+
+	return app.Get(cfg.Path, func(c Ctx) error {
+		if !app.discoveryEnabled.Load() {
+			return ErrNotFound
+		}
+
+		build := app.GetBuildInfo()
+		discovery := Discovery{
+			Name:         app.config.AppName,
+			Version:      cfg.Version,
+			FiberVersion: Version,
+			Status:       DiscoveryStatusUp,
+			Build:        build,
+			Routes:       []DiscoveryRoute{},
+		}
+		if discovery.Version == "" {
+			discovery.Version = build.Version
+		}
+		switch {
+		case app.MaintenanceMode():
+			discovery.Status = DiscoveryStatusMaintenance
+		case !app.Ready():
+			discovery.Status = DiscoveryStatusStarting
+		case cfg.Health != nil && !cfg.Health():
+			discovery.Status = DiscoveryStatusDown
+		case app.WarmupErrors() != nil:
+			discovery.Status = DiscoveryStatusDegraded
+		}
+
+		method := utils.ToUpper(c.Query("method"))
+		prefix := c.Query("prefix")
+		for _, route := range app.GetRoutes(true) {
+			if route.Path == cfg.Path ||
+				(method != "" && route.Method != method) ||
+				!strings.HasPrefix(route.Path, prefix) ||
+				(cfg.Filter != nil && !cfg.Filter(route)) {
+				continue
+			}
+			discovery.Routes = append(discovery.Routes, DiscoveryRoute{
+				Method: route.Method,
+				Path:   route.Path,
+				Name:   route.Name,
+				Params: route.Params,
+			})
+		}
+
+		if discovery.Status != DiscoveryStatusUp && discovery.Status != DiscoveryStatusDegraded {
+			c.Status(StatusServiceUnavailable)
+		}
+		return c.JSON(discovery)
+	})
+}
+
+// SetDiscoveryEnabled turns the discovery endpoint on or off at runtime,
+// it responds with 404 Not Found while it is turned off.
+func (app *App) SetDiscoveryEnabled(enabled bool) {
+	app.discoveryEnabled.Store(enabled)
+}
@@ -0,0 +1,89 @@
+package fiber
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testDiscovery(t *testing.T, app *App, url string) (int, Discovery) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(MethodGet, url, nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var discovery Discovery
+	if resp.StatusCode != StatusNotFound {
+		require.NoError(t, json.Unmarshal(body, &discovery))
+	}
+	return resp.StatusCode, discovery
+}
+
+// go test -run Test_App_EnableDiscovery
+func Test_App_EnableDiscovery(t *testing.T) {
+	t.Parallel()
+	app := New(Config{AppName: "orders"})
+	app.Get("/orders/:id", testEmptyHandler).Name("order")
+	app.Post("/orders", testEmptyHandler)
+	app.Get("/internal/metrics", testEmptyHandler)
+	app.EnableDiscovery(DiscoveryConfig{
+		Version: "1.4.0",
+		Filter: func(route Route) bool {
+			return route.Method != MethodHead && !strings.HasPrefix(route.Path, "/internal")
+		},
+	})
+
+	status, discovery := testDiscovery(t, app, DefaultDiscoveryPath)
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "orders", discovery.Name)
+	require.Equal(t, "1.4.0", discovery.Version)
+	require.Equal(t, Version, discovery.FiberVersion)
+	require.Equal(t, DiscoveryStatusUp, discovery.Status)
+	require.Equal(t, runtime.Version(), discovery.Build.GoVersion)
+	require.Equal(t, []DiscoveryRoute{
+		{Method: MethodGet, Path: "/orders/:id", Name: "order", Params: []string{"id"}},
+		{Method: MethodPost, Path: "/orders"},
+	}, discovery.Routes)
+
+	// the routes are filtered by the query
+	_, discovery = testDiscovery(t, app, DefaultDiscoveryPath+"?method=post&prefix=/orders")
+	require.Equal(t, []DiscoveryRoute{{Method: MethodPost, Path: "/orders"}}, discovery.Routes)
+
+	app.SetMaintenanceMode(true)
+	status, discovery = testDiscovery(t, app, DefaultDiscoveryPath)
+	require.Equal(t, StatusServiceUnavailable, status)
+	require.Equal(t, DiscoveryStatusMaintenance, discovery.Status)
+	app.SetMaintenanceMode(false)
+
+	app.SetDiscoveryEnabled(false)
+	status, _ = testDiscovery(t, app, DefaultDiscoveryPath)
+	require.Equal(t, StatusNotFound, status)
+}
+
+// go test -run Test_App_EnableDiscovery_Health
+func Test_App_EnableDiscovery_Health(t *testing.T) {
+	t.Parallel()
+	app := New()
+	healthy := false
+	app.EnableDiscovery(DiscoveryConfig{
+		Path: "/discovery",
+		Health: func() bool {
+			return healthy
+		},
+	})
+
+	status, discovery := testDiscovery(t, app, "/discovery")
+	require.Equal(t, StatusServiceUnavailable, status)
+	require.Equal(t, DiscoveryStatusDown, discovery.Status)
+	require.Empty(t, discovery.Routes)
+
+	healthy = true
+	status, discovery = testDiscovery(t, app, "/discovery")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, DiscoveryStatusUp, discovery.Status)
+}
@@ -0,0 +1,108 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// streamTracker counts the long-lived streams and signals them when the graceful shutdown begins.
+type streamTracker struct {
+	mutex    sync.Mutex
+	drain    chan struct{} // closed when the drain begins
+	idle     chan struct{} // closed when all streams ended after the drain began
+	active   int
+	draining bool
+}
+
+// init creates the drain channel, it must be called with the mutex held.
+func (s *streamTracker) init() {
+	if s.drain == nil {
+		s.drain = make(chan struct{})
+	}
+}
+
+// TrackStream registers a long-lived stream, e.g. a WebSocket or SSE connection or a streamed
+// response, for the drain of the graceful shutdown. The returned channel is closed when the
+// shutdown begins, the stream should then send its goodbye, e.g. a close frame or a final
+// event, and end. done has to be called when the stream ended. The shutdown waits up to
+// Config.DrainTimeout for the streams before the server is shut down and the remaining
+// connections are cut. Streams which are tracked after the shutdown began are drained at once.
+//
+//	drain, done := c.App().TrackStream()
+//	c.SendStreamWriter(func(w *bufio.Writer) {
+//	    defer done()
+//	    for {
+//	        select {
+//	        case <-drain:
+//	            fmt.Fprint(w, "event: shutdown\ndata: bye\n\n")
+//	            w.Flush()
+//	            return
+//	        case msg := <-messages:
+//	            ...
+//	        }
+//	    }
+//	})
+func (app *App) TrackStream() (<-chan struct{}, func()) {
+	s := &app.streams
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.init()
+	if s.draining && s.active == 0 {
+		// idle is closed while no streams are active
+		s.idle = make(chan struct{})
+	}
+	s.active++
+
+	var once sync.Once
+	return s.drain, func() {
+		once.Do(func() {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			s.active--
+			if s.draining && s.active == 0 {
+				close(s.idle)
+			}
+		})
+	}
+}
+
+// drainStreams signals the tracked streams and waits until they ended, the DrainTimeout
+// elapsed or the context is done.
+func (app *App) drainStreams(ctx context.Context) {
+	s := &app.streams
+	s.mutex.Lock()
+	s.init()
+	if !s.draining {
+		s.draining = true
+		close(s.drain)
+		s.idle = make(chan struct{})
+		if s.active == 0 {
+			close(s.idle)
+		}
+	}
+	idle, active := s.idle, s.active
+	s.mutex.Unlock()
+	if active == 0 {
+		return
+	}
+
+	timer := app.config.Clock.NewTimer(app.config.DrainTimeout)
+	defer timer.Stop()
+	select {
+	case <-idle:
+		return
+	case <-timer.C():
+	case <-ctx.Done():
+	}
+
+	s.mutex.Lock()
+	active = s.active
+	s.mutex.Unlock()
+	app.logw(log.LevelWarn, "shutdown: the streams didn't end in time", "streams", active)
+}
@@ -0,0 +1,65 @@
+package fiber
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_App_TrackStream
+func Test_App_TrackStream(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	drain, done := app.TrackStream()
+	ended := make(chan struct{})
+	go func() {
+		<-drain
+		// the stream sends its goodbye
+		time.Sleep(50 * time.Millisecond)
+		done()
+		close(ended)
+	}()
+
+	require.NoError(t, app.Shutdown())
+	select {
+	case <-ended:
+	default:
+		t.Fatal("the shutdown didn't wait for the stream")
+	}
+	done()
+
+	// the streams which are tracked after the shutdown began are drained at once
+	drain, done = app.TrackStream()
+	defer done()
+	select {
+	case <-drain:
+	default:
+		t.Fatal("the stream wasn't drained")
+	}
+}
+
+// go test -run Test_App_TrackStream_Timeout
+func Test_App_TrackStream_Timeout(t *testing.T) {
+	t.Parallel()
+	app := New(Config{DrainTimeout: 50 * time.Millisecond})
+
+	_, done := app.TrackStream()
+	defer done()
+
+	start := time.Now()
+	require.NoError(t, app.Shutdown())
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// the context ends the drain before the timeout
+	app = New()
+	_, done = app.TrackStream()
+	defer done()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	app.drainStreams(ctx)
+	require.Less(t, time.Since(start), time.Second)
+}
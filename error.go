@@ -31,6 +31,38 @@ var (
 	ErrRangeUnsatisfiable = errors.New("range: unsatisfiable range")
 )
 
+// Upload errors
+var (
+	ErrContentRangeMissing   = NewError(StatusBadRequest, "upload: missing Content-Range header")
+	ErrContentRangeMalformed = NewError(StatusBadRequest, "upload: malformed Content-Range header")
+	ErrUploadSizeMismatch    = NewError(StatusBadRequest, "upload: the size differs from the size of the upload")
+	ErrUploadAborted         = NewError(StatusGone, "upload: the upload was aborted")
+)
+
+// Query helper errors
+var (
+	ErrPaginationMalformed = NewError(StatusBadRequest, "pagination: malformed limit, offset or page")
+	ErrSortNotAllowed      = NewError(StatusBadRequest, "sort: field is not allowed")
+	ErrFilterMalformed     = NewError(StatusBadRequest, "filter: malformed expression")
+)
+
+// Parameter errors
+var (
+	// ErrParamMissing is the cause of a ParamError when a required parameter is empty.
+	ErrParamMissing = errors.New("params: missing parameter")
+)
+
+// Cookie errors
+var (
+	ErrCookieName         = errors.New("cookie: invalid name")
+	ErrCookieHostPrefix   = errors.New("cookie: the __Host- prefix requires Secure, Path \"/\" and no Domain")
+	ErrCookieSecurePrefix = errors.New("cookie: the __Secure- prefix requires Secure")
+	ErrCookiePartitioned  = errors.New("cookie: Partitioned requires Secure")
+	ErrCookieSameSiteNone = errors.New("cookie: SameSite=None requires Secure")
+	ErrCookieTooLarge     = errors.New("cookie: the cookie is larger than the maximum size")
+	ErrCookieTooMany      = errors.New("cookie: the response has more than the maximum number of cookies")
+)
+
 // Binder errors
 var ErrCustomBinderNotFound = errors.New("binder: custom binder not found, please be sure to enter the right name")
 
@@ -40,6 +72,133 @@ var (
 	ErrNoHandlers = errors.New("format: at least one handler is required, but none were set")
 )
 
+// HTTP/2 errors
+var (
+	// ErrPushNotSupported is returned by c.Push if the request wasn't made with HTTP/2 or the client disabled server push.
+	ErrPushNotSupported = errors.New("push: server push is not supported by the connection")
+)
+
+// Plugin errors
+var (
+	// ErrPluginAlreadyRegistered is returned when a plugin with the same name is used twice.
+	ErrPluginAlreadyRegistered = errors.New("plugin: a plugin with the same name is already registered")
+)
+
+// Shutdown hook errors
+var (
+	// ErrShutdownHookUnknownDependency is returned when a named shutdown hook depends on a hook which doesn't exist.
+	ErrShutdownHookUnknownDependency = errors.New("shutdown: hook depends on an unknown hook")
+	// ErrShutdownHookCycle is returned when the named shutdown hooks have cyclic dependencies.
+	ErrShutdownHookCycle = errors.New("shutdown: hook is part of a dependency cycle")
+	// ErrShutdownHookSkipped is returned when a named shutdown hook is skipped, because a hook which
+	// depends on it didn't finish in time and may still use it.
+	ErrShutdownHookSkipped = errors.New("shutdown: hook skipped, a hook which depends on it didn't finish")
+)
+
+// Request context errors
+var (
+	// ErrClientDisconnected is the cause of the user context of a request when the client closed the connection.
+	ErrClientDisconnected = errors.New("request: client disconnected")
+)
+
+// Route swap errors
+var (
+	// ErrRouteMethodsMismatch is returned by SwapRoutes when the apps have different request methods.
+	ErrRouteMethodsMismatch = errors.New("swap: the apps must have the same request methods")
+)
+
+// Hypermedia errors
+var (
+	// ErrRouteNotFound is returned by LinkSet.Err if a link points to a route name which doesn't exist.
+	ErrRouteNotFound = errors.New("links: route not found")
+)
+
+// Secrets errors
+var (
+	// ErrSecretNotFound is returned by a SecretsProvider if the secret doesn't exist.
+	ErrSecretNotFound = errors.New("secrets: secret not found")
+)
+
+// CloudEvents errors
+var (
+	// ErrCloudEventMissing is returned by Bind.CloudEvent if the request is no CloudEvent.
+	ErrCloudEventMissing = NewError(StatusBadRequest, "cloudevents: the request is no CloudEvent")
+	// ErrCloudEventInvalid is returned if a CloudEvent is invalid.
+	ErrCloudEventInvalid = NewError(StatusBadRequest, "cloudevents: invalid event")
+)
+
+// Response size errors
+var (
+	// ErrResponseTooLarge is passed to the ErrorHandler if a response exceeds the maximum response size.
+	ErrResponseTooLarge = NewError(StatusInternalServerError, "response exceeds the maximum response size")
+)
+
+// Strict header errors
+var (
+	// ErrConflictingContentLength is passed to the ErrorHandler by the strict header mode
+	// if a request has both Content-Length and Transfer-Encoding.
+	ErrConflictingContentLength = NewError(StatusBadRequest, "headers: both Content-Length and Transfer-Encoding are set")
+	// ErrObsoleteLineFolding is passed to the ErrorHandler by the strict header mode
+	// if a header is folded over multiple lines.
+	ErrObsoleteLineFolding = NewError(StatusBadRequest, "headers: obsolete line folding is not allowed")
+	// ErrHeaderNULByte is passed to the ErrorHandler by the strict header mode if a header contains a NUL byte.
+	ErrHeaderNULByte = NewError(StatusBadRequest, "headers: NUL bytes are not allowed")
+	// ErrTooManyHeaders is passed to the ErrorHandler by the strict header mode
+	// if a request has more headers than Config.MaxRequestHeaders.
+	ErrTooManyHeaders = NewError(StatusRequestHeaderFieldsTooLarge, "headers: too many request headers")
+)
+
+// Path normalization errors
+var (
+	// ErrSuspiciousPath is passed to the ErrorHandler if the path of a request contains suspicious
+	// encodings, see PathNormalizationConfig.Strict.
+	ErrSuspiciousPath = NewError(StatusBadRequest, "path: suspicious encoding")
+)
+
+// TLS errors
+var (
+	// ErrUnknownTLSPolicy is returned by TLSPolicy.Apply for unknown policies.
+	ErrUnknownTLSPolicy = errors.New("tls: unknown TLS policy")
+	// ErrTLSKeyLogNotAllowed is returned by Listen if ListenConfig.TLSKeyLogWriter is set without InsecureTLSKeyLog.
+	ErrTLSKeyLogNotAllowed = errors.New("tls: TLSKeyLogWriter requires InsecureTLSKeyLog")
+	// ErrCrashDumpDisabled is returned by WriteCrashReport if the CrashDumpDir isn't set.
+	ErrCrashDumpDisabled = errors.New("crash: the crash dump directory isn't set")
+	// ErrECHNotSupported is returned by Listen if ECH keys are configured and the app is built with Go older than 1.24.
+	ErrECHNotSupported = errors.New("tls: ECH requires Go 1.24 or newer")
+)
+
+// Profile errors
+var (
+	// ErrUnknownProfile is returned by ParseProfile for unknown profile names.
+	ErrUnknownProfile = errors.New("profile: unknown profile")
+)
+
+// Stats errors
+var (
+	// ErrInvalidStatsTag is the error of the panic of New for invalid Config.StatsTags.
+	ErrInvalidStatsTag = errors.New("stats: invalid tag")
+)
+
+// Archive errors
+var (
+	// ErrArchiveEntryInvalid is returned by c.SendArchive for an entry without Reader and FS, or a Reader without name.
+	ErrArchiveEntryInvalid = errors.New("archive: the entry needs a Reader with a name or an FS")
+	// ErrArchiveFormat is returned by c.SendArchive for unknown formats.
+	ErrArchiveFormat = errors.New("archive: unknown format")
+)
+
+// Renderer errors
+var (
+	// ErrRenderNoTemplate is returned by CommandRenderer for handlers without template.
+	ErrRenderNoTemplate = errors.New("render: the renderer needs the HTML of a template")
+)
+
+// Export errors
+var (
+	// ErrXLSXSheetName is returned by c.XLSXStream for sheet names which are longer than 31 characters or contain []:*?/\.
+	ErrXLSXSheetName = errors.New("xlsx: invalid sheet name")
+)
+
 // gorilla/schema errors
 type (
 	// ConversionError Conversion error exposes the internal schema.ConversionError for public use.
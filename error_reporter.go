@@ -0,0 +1,183 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/gofiber/utils/v2"
+)
+
+// ScrubbedValue replaces the values of the scrubbed headers of an ErrorEvent.
+const ScrubbedValue = "[scrubbed]"
+
+// DefaultScrubbedHeaders are the request headers whose values are replaced by ScrubbedValue
+// in the events of the ErrorReporter.
+var DefaultScrubbedHeaders = []string{
+	HeaderAuthorization,
+	HeaderProxyAuthorization,
+	HeaderCookie,
+	"X-Csrf-Token",
+	"X-Api-Key",
+}
+
+// ErrorReporter receives the events of the errors which result in a 5xx response
+// and of the panics of handlers, e.g. to send them to an APM service.
+// ReportError is called by the goroutine which handles the request, so it should
+// not block. The event can be kept after ReportError returned.
+type ErrorReporter interface {
+	ReportError(event *ErrorEvent)
+}
+
+// ErrorReporterFunc is an adapter to use a function as ErrorReporter.
+type ErrorReporterFunc func(event *ErrorEvent)
+
+// ReportError calls f(event).
+func (f ErrorReporterFunc) ReportError(event *ErrorEvent) {
+	f(event)
+}
+
+// ErrorEvent is a snapshot of a request whose error was reported.
+type ErrorEvent struct {
+	// Time is the time when the error was reported.
+	Time time.Time
+	// Error is the error which was passed to the ErrorHandler, a *PanicError for panics.
+	Error error
+	// Panic reports if the error is a panic of a handler.
+	Panic bool
+	// Stack is the stack of the panic, or the stack where the error was handled.
+	Stack []byte
+	// Status is the status code of the response, 0 if the connection was closed without a response.
+	Status int
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the path of the request.
+	Path string
+	// Route is the path of the matched route, e.g. "/users/:id".
+	Route string
+	// Query is the query string of the request.
+	Query string
+	// IP is the remote IP address of the client.
+	IP string
+	// Headers contains the request headers, the values of the scrubbed headers are replaced by ScrubbedValue.
+	Headers map[string]string
+}
+
+// errorReporter applies the rate limit and the scrubbing to the events.
+type errorReporter struct {
+	reporter ErrorReporter
+	scrub    func(event *ErrorEvent) bool
+	scrubbed map[string]struct{}
+	limit    int
+
+	mutex  sync.Mutex
+	window int64
+	count  int
+}
+
+func newErrorReporter(cfg Config) *errorReporter {
+	r := &errorReporter{
+		reporter: cfg.ErrorReporter,
+		scrub:    cfg.ErrorReportScrubber,
+		limit:    cfg.ErrorReportLimit,
+		scrubbed: make(map[string]struct{}, len(cfg.ErrorReportScrubbedHeaders)),
+	}
+	for _, header := range cfg.ErrorReportScrubbedHeaders {
+		r.scrubbed[utils.ToLower(header)] = struct{}{}
+	}
+
+	return r
+}
+
+// allow reports if the event is within the rate limit of the current second.
+func (r *errorReporter) allow(now time.Time) bool {
+	if r.limit < 0 {
+		return true
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if sec := now.Unix(); sec != r.window {
+		r.window = sec
+		r.count = 0
+	}
+	if r.count >= r.limit {
+		return false
+	}
+	r.count++
+
+	return true
+}
+
+// report creates the event of the error and passes it to the reporter.
+func (r *errorReporter) report(c Ctx, err error, status int) {
+	now := time.Now()
+	if !r.allow(now) {
+		return
+	}
+
+	event := &ErrorEvent{
+		Time:   now,
+		Error:  err,
+		Status: status,
+		// copy the values, the ctx is reused after the request
+		Method:  utils.CopyString(c.Method()),
+		Path:    utils.CopyString(c.Path()),
+		Route:   c.Route().Path,
+		Query:   string(c.Request().URI().QueryString()),
+		IP:      utils.CopyString(c.IP()),
+		Headers: make(map[string]string),
+	}
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		event.Panic = true
+		event.Stack = panicErr.Stack
+	} else {
+		event.Stack = debug.Stack()
+	}
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		name := string(key)
+		if _, ok := r.scrubbed[utils.ToLower(name)]; ok {
+			event.Headers[name] = ScrubbedValue
+		} else {
+			event.Headers[name] = string(value)
+		}
+	})
+
+	if r.scrub != nil && !r.scrub(event) {
+		return
+	}
+	r.reporter.ReportError(event)
+}
+
+// reportError reports the error if it is a panic or resulted in a 5xx response.
+// handlerErr is the error of the ErrorHandler, which results in a 500 response.
+func (app *App) reportError(c Ctx, err, handlerErr error) {
+	if app.errorReporter == nil {
+		return
+	}
+
+	status := c.Response().StatusCode()
+	if handlerErr != nil {
+		status = StatusInternalServerError
+	}
+
+	var panicErr *PanicError
+	if status >= StatusInternalServerError || errors.As(err, &panicErr) {
+		app.errorReporter.report(c, err, status)
+	}
+}
+
+// reportPanic reports a panic which isn't passed to the ErrorHandler, no response is sent.
+func (app *App) reportPanic(c Ctx, r any) {
+	if app.errorReporter == nil {
+		return
+	}
+
+	app.errorReporter.report(c, &PanicError{Value: r, Stack: debug.Stack()}, 0)
+}
@@ -0,0 +1,172 @@
+package fiber
+
+import (
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testErrorReporter records the reported events.
+type testErrorReporter struct {
+	events []*ErrorEvent
+	mutex  sync.Mutex
+}
+
+func (r *testErrorReporter) ReportError(event *ErrorEvent) {
+	r.mutex.Lock()
+	r.events = append(r.events, event)
+	r.mutex.Unlock()
+}
+
+func (r *testErrorReporter) Events() []*ErrorEvent {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*ErrorEvent(nil), r.events...)
+}
+
+// go test -run Test_App_ErrorReporter
+func Test_App_ErrorReporter(t *testing.T) {
+	t.Parallel()
+	errFailed := errors.New("failed")
+	reporter := &testErrorReporter{}
+
+	app := New(Config{ErrorReporter: reporter})
+	app.Get("/users/:id", func(Ctx) error {
+		return errFailed
+	})
+	app.Get("/bad-request", func(Ctx) error {
+		return ErrBadRequest
+	})
+	app.Get("/unavailable", func(Ctx) error {
+		return ErrServiceUnavailable
+	})
+
+	req := httptest.NewRequest(MethodGet, "/users/42?page=2", nil)
+	req.Header.Set(HeaderAuthorization, "Bearer secret")
+	req.Header.Set(HeaderUserAgent, "test")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+
+	for _, path := range []string{"/bad-request", "/missing", "/unavailable"} {
+		_, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		require.NoError(t, err)
+	}
+
+	events := reporter.Events()
+	require.Len(t, events, 2)
+
+	event := events[0]
+	require.ErrorIs(t, event.Error, errFailed)
+	require.False(t, event.Panic)
+	require.NotEmpty(t, event.Stack)
+	require.Equal(t, StatusInternalServerError, event.Status)
+	require.Equal(t, MethodGet, event.Method)
+	require.Equal(t, "/users/42", event.Path)
+	require.Equal(t, "/users/:id", event.Route)
+	require.Equal(t, "page=2", event.Query)
+	require.Equal(t, ScrubbedValue, event.Headers[HeaderAuthorization])
+	require.Equal(t, "test", event.Headers[HeaderUserAgent])
+
+	require.ErrorIs(t, events[1].Error, ErrServiceUnavailable)
+	require.Equal(t, StatusServiceUnavailable, events[1].Status)
+}
+
+// go test -run Test_App_ErrorReporter_Panic
+func Test_App_ErrorReporter_Panic(t *testing.T) {
+	t.Parallel()
+	reporter := &testErrorReporter{}
+
+	app := New(Config{ErrorReporter: reporter, PanicPolicy: PanicPolicyErrorHandler})
+	app.Get("/panic", func(Ctx) error {
+		panic("boom")
+	})
+	app.Get("/close", func(Ctx) error {
+		panic("closed")
+	}).PanicPolicy(PanicPolicyCloseConnection)
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/panic", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+
+	_, err = app.Test(httptest.NewRequest(MethodGet, "/close", nil))
+	require.Error(t, err)
+
+	events := reporter.Events()
+	require.Len(t, events, 2)
+
+	var panicErr *PanicError
+	require.ErrorAs(t, events[0].Error, &panicErr)
+	require.Equal(t, "boom", panicErr.Value)
+	require.True(t, events[0].Panic)
+	require.Equal(t, panicErr.Stack, events[0].Stack)
+	require.Equal(t, StatusInternalServerError, events[0].Status)
+	require.Equal(t, "/panic", events[0].Route)
+
+	require.ErrorAs(t, events[1].Error, &panicErr)
+	require.Equal(t, "closed", panicErr.Value)
+	require.True(t, events[1].Panic)
+	require.Equal(t, 0, events[1].Status)
+	require.Equal(t, "/close", events[1].Route)
+}
+
+// go test -run Test_App_ErrorReporter_Scrubber
+func Test_App_ErrorReporter_Scrubber(t *testing.T) {
+	t.Parallel()
+	var events []*ErrorEvent
+
+	app := New(Config{
+		ErrorReporter: ErrorReporterFunc(func(event *ErrorEvent) {
+			events = append(events, event)
+		}),
+		ErrorReportScrubbedHeaders: []string{"x-session"},
+		ErrorReportScrubber: func(event *ErrorEvent) bool {
+			if event.Path == "/health" {
+				return false
+			}
+			event.IP = ""
+			return true
+		},
+	})
+	app.Get("/*", func(Ctx) error {
+		return errors.New("failed")
+	})
+
+	for _, path := range []string{"/health", "/users"} {
+		req := httptest.NewRequest(MethodGet, path, nil)
+		req.Header.Set("X-Session", "secret")
+		req.Header.Set(HeaderAuthorization, "Bearer secret")
+		_, err := app.Test(req)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, events, 1)
+	require.Equal(t, "/users", events[0].Path)
+	require.Empty(t, events[0].IP)
+	require.Equal(t, ScrubbedValue, events[0].Headers["X-Session"])
+	// the configured headers replace the default headers
+	require.Equal(t, "Bearer secret", events[0].Headers[HeaderAuthorization])
+}
+
+// go test -run Test_ErrorReporter_Limit
+func Test_ErrorReporter_Limit(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+
+	limited := newErrorReporter(Config{ErrorReportLimit: 2})
+	require.True(t, limited.allow(now))
+	require.True(t, limited.allow(now))
+	require.False(t, limited.allow(now))
+	// the limit is reset in the next second
+	require.True(t, limited.allow(now.Add(time.Second)))
+
+	// a negative limit disables the rate limit
+	unlimited := newErrorReporter(Config{ErrorReportLimit: -1})
+	for i := 0; i < 100; i++ {
+		require.True(t, unlimited.allow(now))
+	}
+}
@@ -0,0 +1,205 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/valyala/fasthttp"
+)
+
+// The built-in categories of the errors which are passed to the ErrorHandler.
+const (
+	// ErrorCategoryClient contains the errors with a 4xx status code, except timeouts.
+	ErrorCategoryClient = "client"
+	// ErrorCategoryTimeout contains the errors with the status code 408 or 504
+	// and the errors which wrap context.DeadlineExceeded.
+	ErrorCategoryTimeout = "timeout"
+	// ErrorCategoryPanic contains the panics of handlers which weren't recovered by a middleware.
+	ErrorCategoryPanic = "panic"
+	// ErrorCategoryUpstream contains the failed requests to upstream servers,
+	// e.g. of the proxy middleware and the client.
+	ErrorCategoryUpstream = "upstream"
+	// ErrorCategoryServer contains all other errors.
+	ErrorCategoryServer = "server"
+)
+
+// UpstreamError wraps an error of a request to an upstream server,
+// so it is counted in the ErrorCategoryUpstream.
+type UpstreamError struct {
+	Err error
+}
+
+// Error returns the message of the wrapped error.
+func (e *UpstreamError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorStats contains the counters of the errors which were passed to the ErrorHandler.
+type ErrorStats struct {
+	// Categories contains the number of errors by category, including the custom categories.
+	Categories map[string]uint64 `json:"categories"`
+	// Client contains the number of errors of the ErrorCategoryClient by status code.
+	Client map[int]uint64 `json:"client"`
+}
+
+// add adds the counters of the other stats.
+func (s *ErrorStats) add(other ErrorStats) {
+	for category, count := range other.Categories {
+		s.Categories[category] += count
+	}
+	for status, count := range other.Client {
+		s.Client[status] += count
+	}
+}
+
+// errorCategory is a custom category of errors.
+type errorCategory struct {
+	name  string
+	match func(err error) bool
+	count atomic.Uint64
+}
+
+// errorStats counts the errors by category.
+type errorStats struct {
+	custom   []*errorCategory
+	client   [100]atomic.Uint64 // by status code - 400
+	timeout  atomic.Uint64
+	panic    atomic.Uint64
+	upstream atomic.Uint64
+	server   atomic.Uint64
+}
+
+var (
+	upstreamErrorsMutex sync.RWMutex
+	// upstreamErrors are the errors of the clients, see RegisterUpstreamError.
+	upstreamErrors = []error{
+		fasthttp.ErrTimeout,
+		fasthttp.ErrDialTimeout,
+		fasthttp.ErrNoFreeConns,
+		fasthttp.ErrConnectionClosed,
+		fasthttp.ErrTLSHandshakeTimeout,
+	}
+)
+
+// RegisterUpstreamError registers errors of a client which are counted in the
+// ErrorCategoryUpstream, also if they are wrapped. The errors of the fasthttp client
+// are registered by default, the client package registers its ErrTimeoutOrCancel:
+//
+//	fiber.RegisterUpstreamError(ErrPaymentProviderDown)
+func RegisterUpstreamError(errs ...error) {
+	upstreamErrorsMutex.Lock()
+	upstreamErrors = append(upstreamErrors, errs...)
+	upstreamErrorsMutex.Unlock()
+}
+
+// RegisterErrorCategory registers a custom category of errors, e.g. for the errors of a
+// payment provider. The custom categories are checked in registration order before the
+// built-in categories, an error is counted in the first category which matches.
+// The categories must be registered before the app serves requests.
+//
+//	app.RegisterErrorCategory("payment", func(err error) bool {
+//	    return errors.Is(err, ErrPaymentDeclined)
+//	})
+func (app *App) RegisterErrorCategory(name string, match func(err error) bool) {
+	app.mutex.Lock()
+	app.errorStats.custom = append(app.errorStats.custom, &errorCategory{name: name, match: match})
+	app.mutex.Unlock()
+}
+
+// countError counts the error in its category.
+func (s *errorStats) countError(err error) {
+	for _, category := range s.custom {
+		if category.match(err) {
+			category.count.Add(1)
+			return
+		}
+	}
+
+	var panicErr *PanicError
+	var upstreamErr *UpstreamError
+	switch {
+	case errors.As(err, &panicErr):
+		s.panic.Add(1)
+		return
+	case errors.As(err, &upstreamErr) || isUpstreamError(err):
+		s.upstream.Add(1)
+		return
+	case errors.Is(err, context.DeadlineExceeded):
+		s.timeout.Add(1)
+		return
+	}
+
+	var fiberErr *Error
+	if !errors.As(err, &fiberErr) {
+		s.server.Add(1)
+		return
+	}
+	switch code := fiberErr.Code; {
+	case code == StatusRequestTimeout || code == StatusGatewayTimeout:
+		s.timeout.Add(1)
+	case code >= StatusBadRequest && code < StatusInternalServerError:
+		s.client[code-StatusBadRequest].Add(1)
+	default:
+		s.server.Add(1)
+	}
+}
+
+// isUpstreamError reports if the error is a registered error of a client or a failed dial.
+func isUpstreamError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	upstreamErrorsMutex.RLock()
+	defer upstreamErrorsMutex.RUnlock()
+	for _, upstreamErr := range upstreamErrors {
+		if errors.Is(err, upstreamErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorStats returns the counters of the errors which were passed to the ErrorHandler,
+// by category. Responses which were sent without returning an error aren't counted,
+// their status codes are counted in Stats.
+func (app *App) ErrorStats() ErrorStats {
+	s := &app.errorStats
+	stats := ErrorStats{
+		Categories: map[string]uint64{
+			ErrorCategoryTimeout:  s.timeout.Load(),
+			ErrorCategoryPanic:    s.panic.Load(),
+			ErrorCategoryUpstream: s.upstream.Load(),
+			ErrorCategoryServer:   s.server.Load(),
+		},
+		Client: make(map[int]uint64),
+	}
+
+	var client uint64
+	for i := range s.client {
+		if count := s.client[i].Load(); count > 0 {
+			stats.Client[StatusBadRequest+i] = count
+			client += count
+		}
+	}
+	stats.Categories[ErrorCategoryClient] = client
+
+	for _, category := range s.custom {
+		stats.Categories[category.name] += category.count.Load()
+	}
+
+	return stats
+}
@@ -0,0 +1,91 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_App_ErrorStats
+func Test_App_ErrorStats(t *testing.T) {
+	t.Parallel()
+	errPayment := errors.New("payment declined")
+	errInventory := errors.New("inventory unavailable")
+	RegisterUpstreamError(errInventory)
+
+	app := New(Config{PanicPolicy: PanicPolicyErrorHandler})
+	app.RegisterErrorCategory("payment", func(err error) bool {
+		return errors.Is(err, errPayment)
+	})
+
+	errs := map[string]error{
+		"/bad-request": ErrBadRequest,
+		"/forbidden":   ErrForbidden,
+		"/timeout":     ErrRequestTimeout,
+		"/deadline":    fmt.Errorf("query: %w", context.DeadlineExceeded),
+		"/upstream":    &UpstreamError{Err: errors.New("connection refused")},
+		"/dial":        fasthttp.ErrDialTimeout,
+		"/refused":     &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+		"/registered":  fmt.Errorf("reserve: %w", errInventory),
+		"/server":      errors.New("failed"),
+		"/payment":     fmt.Errorf("checkout: %w", errPayment),
+	}
+	for path, err := range errs {
+		err := err
+		app.Get(path, func(Ctx) error {
+			return err
+		})
+	}
+	app.Get("/panic", func(Ctx) error {
+		panic("boom")
+	})
+
+	for path := range errs {
+		_, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		require.NoError(t, err)
+	}
+	for _, path := range []string{"/panic", "/missing"} {
+		_, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		require.NoError(t, err)
+	}
+
+	stats := app.ErrorStats()
+	require.Equal(t, map[string]uint64{
+		ErrorCategoryClient:   3,
+		ErrorCategoryTimeout:  2,
+		ErrorCategoryPanic:    1,
+		ErrorCategoryUpstream: 4,
+		ErrorCategoryServer:   1,
+		"payment":             1,
+	}, stats.Categories)
+	require.Equal(t, map[int]uint64{
+		StatusBadRequest: 1,
+		StatusForbidden:  1,
+		StatusNotFound:   1,
+	}, stats.Client)
+	require.Equal(t, stats, app.Stats().Errors)
+}
+
+// go test -run Test_UpstreamError
+func Test_UpstreamError(t *testing.T) {
+	t.Parallel()
+	err := &UpstreamError{Err: fasthttp.ErrTimeout}
+
+	require.Equal(t, "timeout", err.Error())
+	require.ErrorIs(t, err, fasthttp.ErrTimeout)
+
+	// the default error handler responds with 500
+	app := New()
+	app.Get("/", func(Ctx) error {
+		return err
+	})
+	resp, testErr := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, testErr)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+}
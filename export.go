@@ -0,0 +1,376 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// defaultExportFlushRows is the number of rows after which CSVStream and XLSXStream send the buffered rows.
+const defaultExportFlushRows = 100
+
+// Rows produces the rows of CSVStream and XLSXStream. It calls write for each row and returns the
+// first error of write, which fails if the client disconnected. It's called after the handler
+// returned, while the response is sent, so it must not access the Ctx.
+//
+// The values of the rows are strings, numbers, booleans, times, nil for empty cells, or
+// other values which are formatted with fmt.
+type Rows func(write func(row ...any) error) error
+
+// CSVConfig configures CSVStream.
+type CSVConfig struct {
+	// Comma is the field delimiter, ',' by default.
+	Comma rune
+	// FlushRows is the number of rows after which the buffered rows are sent, 100 by default.
+	FlushRows int
+	// BOM writes a UTF-8 byte order mark, which Excel needs to detect the encoding of the file.
+	BOM bool
+	// UseCRLF ends the lines with \r\n instead of \n.
+	UseCRLF bool
+}
+
+// XLSXConfig configures XLSXStream.
+type XLSXConfig struct {
+	// Sheet is the name of the worksheet, "Sheet1" by default.
+	Sheet string
+	// FlushRows is the number of rows after which the buffered rows are sent, 100 by default.
+	FlushRows int
+}
+
+// CSVStream streams the header and the rows as CSV file, the rows are produced while the response
+// is sent, so exports of any size don't have to be built in memory. The rows are sent in batches of
+// FlushRows and the writes block while the client is slow. Errors of the rows are logged and close
+// the connection, so the client doesn't receive a truncated file as complete.
+//
+//	c.Attachment("orders.csv")
+//	return c.CSVStream([]string{"id", "total"}, func(write func(row ...any) error) error {
+//	    for rows.Next() {
+//	        ...
+//	        if err := write(id, total); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return rows.Err()
+//	})
+func (c *DefaultCtx) CSVStream(header []string, rows Rows, config ...CSVConfig) error {
+	var cfg CSVConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.FlushRows <= 0 {
+		cfg.FlushRows = defaultExportFlushRows
+	}
+
+	c.fasthttp.Response.Header.SetContentType(MIMETextCSVCharsetUTF8)
+	c.setBodyWriter(-1, "csv: failed to write the rows", func(w io.Writer) error {
+		return writeCSV(w, &cfg, header, rows)
+	})
+	return nil
+}
+
+// writeCSV writes the header and the rows as CSV.
+func writeCSV(w io.Writer, cfg *CSVConfig, header []string, rows Rows) error {
+	if cfg.BOM {
+		if _, err := io.WriteString(w, "\ufeff"); err != nil {
+			return fmt.Errorf("csv: failed to write the BOM: %w", err)
+		}
+	}
+	cw := csv.NewWriter(w)
+	if cfg.Comma != 0 {
+		cw.Comma = cfg.Comma
+	}
+	cw.UseCRLF = cfg.UseCRLF
+	if len(header) > 0 {
+		if err := cw.Write(header); err != nil {
+			return fmt.Errorf("csv: failed to write the header: %w", err)
+		}
+	}
+
+	record := make([]string, 0, len(header))
+	count := 0
+	err := rows(func(row ...any) error {
+		record = record[:0]
+		for _, value := range row {
+			record = append(record, formatExportValue(value))
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("csv: failed to write the row: %w", err)
+		}
+		if count++; count%cfg.FlushRows == 0 {
+			cw.Flush()
+			return cw.Error() //nolint:wrapcheck // the error of the client is returned unchanged
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error() //nolint:wrapcheck // the error of the client is returned unchanged
+}
+
+// XLSXStream streams the header and the rows as Excel workbook with one worksheet, the rows are
+// produced while the response is sent, like the rows of CSVStream. The header is bold, numbers,
+// booleans and times are written as typed cells, other values as strings.
+//
+//	c.Attachment("orders.xlsx")
+//	return c.XLSXStream([]string{"id", "total", "created"}, rows, fiber.XLSXConfig{Sheet: "Orders"})
+func (c *DefaultCtx) XLSXStream(header []string, rows Rows, config ...XLSXConfig) error {
+	var cfg XLSXConfig
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Sheet == "" {
+		cfg.Sheet = "Sheet1"
+	}
+	if utf8.RuneCountInString(cfg.Sheet) > 31 || strings.ContainsAny(cfg.Sheet, `[]:*?/\`) {
+		return ErrXLSXSheetName
+	}
+	if cfg.FlushRows <= 0 {
+		cfg.FlushRows = defaultExportFlushRows
+	}
+
+	c.fasthttp.Response.Header.SetContentType(MIMEApplicationXLSX)
+	c.setBodyWriter(-1, "xlsx: failed to write the rows", func(w io.Writer) error {
+		return writeXLSX(w, &cfg, header, rows)
+	})
+	return nil
+}
+
+// The parts of the workbooks of XLSXStream besides the worksheet.
+const (
+	xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+	xlsxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+	// the styles are the default, the bold header and the date time
+	xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="22" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+		`</styleSheet>`
+	xlsxSheetStart = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd   = `</sheetData></worksheet>`
+)
+
+// The styles of the cells of XLSXStream.
+const (
+	xlsxStyleHeader   = "1"
+	xlsxStyleDateTime = "2"
+)
+
+// writeXLSX writes the header and the rows as workbook.
+func writeXLSX(w io.Writer, cfg *XLSXConfig, header []string, rows Rows) error {
+	var workbook strings.Builder
+	workbook.WriteString(xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="`)
+	xml.EscapeText(&workbook, []byte(cfg.Sheet)) //nolint:errcheck // strings.Builder never fails
+	workbook.WriteString(`" sheetId="1" r:id="rId1"/></sheets></workbook>`)
+
+	zw := zip.NewWriter(w)
+	for _, part := range [...]struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	} {
+		fw, err := zw.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("xlsx: failed to create %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(fw, part.content); err != nil {
+			return fmt.Errorf("xlsx: failed to write %s: %w", part.name, err)
+		}
+	}
+
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("xlsx: failed to create the worksheet: %w", err)
+	}
+	sheet := &xlsxSheet{w: bufio.NewWriter(fw)}
+	sheet.w.WriteString(xlsxSheetStart) //nolint:errcheck // the error is returned by Flush
+	if len(header) > 0 {
+		cells := make([]any, len(header))
+		for i, name := range header {
+			cells[i] = name
+		}
+		sheet.writeRow(cells, xlsxStyleHeader)
+	}
+
+	count := 0
+	err = rows(func(row ...any) error {
+		sheet.writeRow(row, "")
+		if count++; count%cfg.FlushRows == 0 {
+			if err := sheet.w.Flush(); err != nil {
+				return err //nolint:wrapcheck // the error of the client is returned unchanged
+			}
+			return zw.Flush() //nolint:wrapcheck // the error of the client is returned unchanged
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sheet.w.WriteString(xlsxSheetEnd) //nolint:errcheck // the error is returned by Flush
+	if err := sheet.w.Flush(); err != nil {
+		return fmt.Errorf("xlsx: failed to write the worksheet: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("xlsx: failed to write the workbook: %w", err)
+	}
+	return nil
+}
+
+// xlsxSheet writes the rows of a worksheet, the errors of the writer are returned by its Flush.
+type xlsxSheet struct {
+	w   *bufio.Writer
+	buf []byte
+	row int
+}
+
+// writeRow writes the cells as next row, the strings are written with the style.
+func (s *xlsxSheet) writeRow(cells []any, style string) {
+	s.row++
+	s.buf = append(s.buf[:0], `<row r="`...)
+	s.buf = strconv.AppendInt(s.buf, int64(s.row), 10)
+	s.buf = append(s.buf, `">`...)
+	for i, value := range cells {
+		if value == nil {
+			continue
+		}
+		s.buf = append(s.buf, `<c r="`...)
+		s.buf = appendXLSXColumn(s.buf, i)
+		s.buf = strconv.AppendInt(s.buf, int64(s.row), 10)
+		s.buf = append(s.buf, '"')
+
+		switch v := value.(type) {
+		case bool:
+			s.buf = append(s.buf, ` t="b"><v>`...)
+			if v {
+				s.buf = append(s.buf, '1')
+			} else {
+				s.buf = append(s.buf, '0')
+			}
+			s.buf = append(s.buf, `</v></c>`...)
+		case time.Time:
+			// the serial date of Excel counts the days since 1899-12-30 in the time zone of the time
+			epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, v.Location())
+			s.buf = append(s.buf, ` s="`+xlsxStyleDateTime+`"><v>`...)
+			s.buf = strconv.AppendFloat(s.buf, float64(v.Sub(epoch))/float64(24*time.Hour), 'f', -1, 64)
+			s.buf = append(s.buf, `</v></c>`...)
+		default:
+			if number, ok := formatExportNumber(value); ok {
+				s.buf = append(s.buf, `><v>`...)
+				s.buf = append(s.buf, number...)
+				s.buf = append(s.buf, `</v></c>`...)
+				continue
+			}
+			if style != "" {
+				s.buf = append(s.buf, ` s="`+style+`"`...)
+			}
+			s.buf = append(s.buf, ` t="inlineStr"><is><t xml:space="preserve">`...)
+			s.w.Write(s.buf)                                      //nolint:errcheck // the error is returned by Flush
+			xml.EscapeText(s.w, []byte(formatExportValue(value))) //nolint:errcheck // the error is returned by Flush
+			s.buf = append(s.buf[:0], `</t></is></c>`...)
+		}
+	}
+	s.buf = append(s.buf, `</row>`...)
+	s.w.Write(s.buf) //nolint:errcheck // the error is returned by Flush
+}
+
+// appendXLSXColumn appends the name of the zero-based column, e.g. "A" or "AB".
+func appendXLSXColumn(dst []byte, column int) []byte {
+	var name [4]byte
+	i := len(name)
+	for column++; column > 0; column = (column - 1) / 26 {
+		i--
+		name[i] = byte('A' + (column-1)%26)
+	}
+	return append(dst, name[i:]...)
+}
+
+// formatExportValue formats a value of the rows of CSVStream and XLSXStream as string.
+func formatExportValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	}
+	if number, ok := formatExportNumber(value); ok {
+		return number
+	}
+	return fmt.Sprint(value)
+}
+
+// formatExportNumber formats an integer or a finite float, other values return false.
+func formatExportNumber(value any) (string, bool) {
+	switch v := value.(type) {
+	case int:
+		return strconv.FormatInt(int64(v), 10), true
+	case int8:
+		return strconv.FormatInt(int64(v), 10), true
+	case int16:
+		return strconv.FormatInt(int64(v), 10), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
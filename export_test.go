@@ -0,0 +1,149 @@
+package fiber
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Ctx_CSVStream
+func Test_Ctx_CSVStream(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		c.Attachment("orders.csv")
+		return c.CSVStream([]string{"id", "note", "total", "paid", "created"}, func(write func(row ...any) error) error {
+			created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			for i := 1; i <= 250; i++ {
+				if err := write(i, "a;b \"c\"", float64(i)/4, i%2 == 0, created); err != nil {
+					return err
+				}
+			}
+			return write(nil, []byte("last"))
+		}, CSVConfig{Comma: ';', BOM: true, UseCRLF: true, FlushRows: 100})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, MIMETextCSVCharsetUTF8, resp.Header.Get(HeaderContentType))
+	require.Equal(t, `attachment; filename="orders.csv"`, resp.Header.Get(HeaderContentDisposition))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	lines := strings.Split(string(body), "\r\n")
+	require.Len(t, lines, 253)
+	require.Equal(t, "\ufeffid;note;total;paid;created", lines[0])
+	require.Equal(t, `2;"a;b ""c""";0.5;true;2024-05-01T12:00:00Z`, lines[2])
+	require.Equal(t, ";last", lines[251])
+}
+
+// go test -run Test_Ctx_CSVStream_Error
+func Test_Ctx_CSVStream_Error(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.CSVStream(nil, func(write func(row ...any) error) error {
+			if err := write("a"); err != nil {
+				return err
+			}
+			return errors.New("database failed")
+		})
+	})
+
+	// the client doesn't receive the truncated file as complete
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	if err == nil {
+		_, err = io.ReadAll(resp.Body)
+	}
+	require.Error(t, err)
+}
+
+// go test -run Test_Ctx_XLSXStream
+func Test_Ctx_XLSXStream(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.XLSXStream([]string{"id", "note", "total", "paid", "created"}, func(write func(row ...any) error) error {
+			for i := 1; i <= 30; i++ {
+				if err := write(i, "<b> & \"c\"", 1.25, true, created); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, XLSXConfig{Sheet: "Orders & Co", FlushRows: 7})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, MIMEApplicationXLSX, resp.Header.Get(HeaderContentType))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	parts := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		parts[f.Name] = string(content)
+
+		// every part is well-formed
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			_, err := decoder.Token()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err, f.Name)
+		}
+	}
+	require.Contains(t, parts, "[Content_Types].xml")
+	require.Contains(t, parts["xl/workbook.xml"], `<sheet name="Orders &amp; Co" sheetId="1" r:id="rId1"/>`)
+
+	var sheet struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				R string `xml:"r,attr"`
+				T string `xml:"t,attr"`
+				S string `xml:"s,attr"`
+				V string `xml:"v"`
+				I string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(parts["xl/worksheets/sheet1.xml"]), &sheet))
+	require.Len(t, sheet.Rows, 31)
+	header := sheet.Rows[0].Cells
+	require.Equal(t, "E1", header[4].R)
+	require.Equal(t, "created", header[4].I)
+	require.Equal(t, xlsxStyleHeader, header[4].S)
+
+	row := sheet.Rows[30]
+	require.Equal(t, 31, row.R)
+	require.Equal(t, "30", row.Cells[0].V)
+	require.Equal(t, "<b> & \"c\"", row.Cells[1].I)
+	require.Equal(t, "1.25", row.Cells[2].V)
+	require.Equal(t, "b", row.Cells[3].T)
+	require.Equal(t, "1", row.Cells[3].V)
+	require.Equal(t, xlsxStyleDateTime, row.Cells[4].S)
+	require.Equal(t, "45293.5", row.Cells[4].V)
+
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	require.ErrorIs(t, c.XLSXStream(nil, nil, XLSXConfig{Sheet: "a/b"}), ErrXLSXSheetName)
+	require.Equal(t, "AB", string(appendXLSXColumn(nil, 27)))
+	require.Equal(t, "XFD", string(appendXLSXColumn(nil, 16383)))
+}
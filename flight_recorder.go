@@ -0,0 +1,109 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestRecord is the summary of a request of the flight recorder, without the query
+// and the headers which can contain secrets.
+type RequestRecord struct {
+	Time     time.Time     `json:"time"`
+	Method   string        `json:"method"`
+	Path     string        `json:"path"`
+	Route    string        `json:"route"`
+	IP       string        `json:"ip"`
+	Error    string        `json:"error,omitempty"`
+	Status   int           `json:"status"`
+	Duration time.Duration `json:"duration"`
+}
+
+// flightRecorder keeps the summaries of the recent requests in a ring buffer.
+type flightRecorder struct {
+	redact   func(record *RequestRecord) bool
+	rand     Rand
+	records  []RequestRecord
+	sampling float64
+	next     int
+	full     bool
+	mutex    sync.Mutex
+}
+
+func newFlightRecorder(config Config) *flightRecorder {
+	size := config.FlightRecorderSize
+	if config.CrashDumpDir != "" {
+		size = max(size, config.CrashDumpRequests)
+	}
+	return &flightRecorder{
+		redact:   config.FlightRecorderRedact,
+		rand:     config.Rand,
+		records:  make([]RequestRecord, size),
+		sampling: config.FlightRecorderSampling,
+	}
+}
+
+// requestRecord copies the request of the ctx, the ctx is reused after the request.
+func requestRecord(c Ctx, start time.Time, err error) RequestRecord {
+	record := RequestRecord{
+		Time:     start,
+		Method:   strings.Clone(c.Method()),
+		Path:     strings.Clone(c.Path()),
+		Route:    strings.Clone(c.Route().Path),
+		IP:       strings.Clone(c.IP()),
+		Status:   c.Response().StatusCode(),
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	return record
+}
+
+// record adds the completed request to the ring buffer. The successful requests are sampled,
+// the requests with errors are always recorded.
+func (r *flightRecorder) record(c Ctx, start time.Time, err error) {
+	failed := err != nil || c.Response().StatusCode() >= StatusInternalServerError
+	if !failed && r.sampling < 1 && r.rand.Float64() >= r.sampling {
+		return
+	}
+
+	record := requestRecord(c, start, err)
+	if r.redact != nil && !r.redact(&record) {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// recent returns up to n recorded requests, the newest last.
+func (r *flightRecorder) recent(n int) []RequestRecord {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	records := make([]RequestRecord, 0, len(r.records))
+	if r.full {
+		records = append(records, r.records[r.next:]...)
+	}
+	records = append(records, r.records[:r.next]...)
+	return records[max(0, len(records)-n):]
+}
+
+// RecentRequests returns the summaries of the recent requests of the flight recorder,
+// the newest last, or nil if the flight recorder is disabled.
+func (app *App) RecentRequests() []RequestRecord {
+	if app.flightRecorder == nil {
+		return nil
+	}
+	return app.flightRecorder.recent(len(app.flightRecorder.records))
+}
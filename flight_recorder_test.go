@@ -0,0 +1,106 @@
+package fiber
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_App_RecentRequests
+func Test_App_RecentRequests(t *testing.T) {
+	t.Parallel()
+	require.Nil(t, New().RecentRequests())
+
+	app := New(Config{
+		FlightRecorderSize: 3,
+		FlightRecorderRedact: func(record *RequestRecord) bool {
+			record.IP = "redacted"
+			return record.Route != "/health"
+		},
+	})
+	app.Get("/health", testSimpleHandler)
+	app.Get("/users/:id", testSimpleHandler)
+	app.Post("/orders", func(Ctx) error {
+		return errors.New("out of stock")
+	})
+
+	for _, req := range []struct{ method, path string }{
+		{MethodGet, "/users/1"},
+		{MethodGet, "/health"},
+		{MethodGet, "/users/2?token=secret"},
+		{MethodPost, "/orders"},
+		{MethodGet, "/missing"},
+	} {
+		_, err := app.Test(httptest.NewRequest(req.method, req.path, nil))
+		require.NoError(t, err)
+	}
+
+	// the ring buffer keeps the latest requests, the newest last
+	records := app.RecentRequests()
+	require.Len(t, records, 3)
+	require.Equal(t, "/users/2", records[0].Path)
+	require.Equal(t, "/users/:id", records[0].Route)
+	require.Equal(t, "redacted", records[0].IP)
+	require.Equal(t, StatusOK, records[0].Status)
+	require.Empty(t, records[0].Error)
+	require.Equal(t, MethodPost, records[1].Method)
+	require.Equal(t, StatusInternalServerError, records[1].Status)
+	require.Equal(t, "out of stock", records[1].Error)
+	require.Equal(t, StatusNotFound, records[2].Status)
+	require.Equal(t, "Cannot GET /missing", records[2].Error)
+}
+
+// go test -run Test_App_RecentRequests_Sampling
+func Test_App_RecentRequests_Sampling(t *testing.T) {
+	t.Parallel()
+	app := New(Config{FlightRecorderSize: 10, FlightRecorderSampling: 0.000001})
+	app.Get("/", testSimpleHandler)
+	app.Get("/fail", func(Ctx) error {
+		return ErrServiceUnavailable
+	})
+
+	for i := 0; i < 5; i++ {
+		_, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+		require.NoError(t, err)
+	}
+	_, err := app.Test(httptest.NewRequest(MethodGet, "/fail", nil))
+	require.NoError(t, err)
+
+	// the failed requests are always recorded
+	records := app.RecentRequests()
+	require.Len(t, records, 1)
+	require.Equal(t, "/fail", records[0].Path)
+	require.Equal(t, StatusServiceUnavailable, records[0].Status)
+}
+
+// go test -run Test_App_AdminApp_Requests
+func Test_App_AdminApp_Requests(t *testing.T) {
+	t.Parallel()
+	app := New(Config{FlightRecorderSize: 10})
+	app.Get("/users/:id", testSimpleHandler)
+	_, err := app.Test(httptest.NewRequest(MethodGet, "/users/1", nil))
+	require.NoError(t, err)
+
+	admin := app.AdminApp(AdminConfig{
+		Auth: func(c Ctx) error {
+			return c.Next()
+		},
+	})
+	status, body := testAdminRequest(t, admin, MethodGet, "/requests", "")
+	require.Equal(t, StatusOK, status)
+	var records []RequestRecord
+	require.NoError(t, json.Unmarshal([]byte(body), &records))
+	require.Len(t, records, 1)
+	require.Equal(t, "/users/:id", records[0].Route)
+
+	admin = New().AdminApp(AdminConfig{
+		Auth: func(c Ctx) error {
+			return c.Next()
+		},
+	})
+	status, _ = testAdminRequest(t, admin, MethodGet, "/requests", "")
+	require.Equal(t, StatusConflict, status)
+}
@@ -0,0 +1,214 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"strings"
+
+	"github.com/gofiber/utils/v2"
+)
+
+// ForwardedElement is an element of the Forwarded header defined by RFC 7239,
+// each proxy of the request appends an element to the header.
+//
+//	Forwarded: for=192.0.2.60;proto=http;by=203.0.113.43, for="[2001:db8:cafe::17]:4711"
+type ForwardedElement struct {
+	// For is the node which made the request to the proxy, e.g. "192.0.2.60", "[2001:db8::17]:4711" or "unknown".
+	For string
+	// By is the node of the proxy which received the request.
+	By string
+	// Host is the Host header of the request received by the proxy.
+	Host string
+	// Proto is the scheme of the request received by the proxy, e.g. "http" or "https".
+	Proto string
+}
+
+// String returns the element formatted for the Forwarded header,
+// the values are quoted if needed and IPv6 addresses are enclosed in brackets.
+func (e ForwardedElement) String() string {
+	var b strings.Builder
+	pairs := [...]struct{ key, value string }{
+		{key: "for", value: forwardedNode(e.For)},
+		{key: "by", value: forwardedNode(e.By)},
+		{key: "host", value: e.Host},
+		{key: "proto", value: e.Proto},
+	}
+	for _, pair := range pairs {
+		if pair.value == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(pair.key)
+		b.WriteByte('=')
+		b.WriteString(forwardedQuote(pair.value))
+	}
+	return b.String()
+}
+
+// IP returns the IP address of the For node without the brackets and the port,
+// an empty string is returned if the node isn't an IP address, e.g. "unknown" or an obfuscated identifier.
+func (e ForwardedElement) IP() string {
+	ip := e.For
+	if strings.HasPrefix(ip, "[") {
+		if end := strings.IndexByte(ip, ']'); end != -1 {
+			ip = ip[1:end]
+		}
+	} else if strings.Count(ip, ":") == 1 {
+		ip = ip[:strings.IndexByte(ip, ':')]
+	}
+	if !utils.IsIPv4(ip) && !utils.IsIPv6(ip) {
+		return ""
+	}
+	return ip
+}
+
+// FormatForwarded returns the value of a Forwarded header with the given elements.
+func FormatForwarded(elements ...ForwardedElement) string {
+	values := make([]string, 0, len(elements))
+	for _, element := range elements {
+		if value := element.String(); value != "" {
+			values = append(values, value)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// ParseForwarded parses the value of a Forwarded header in the order of the proxies.
+// Quoted values are unquoted, unknown parameters and malformed pairs are ignored.
+func ParseForwarded(header string) []ForwardedElement {
+	var elements []ForwardedElement
+	var element ForwardedElement
+	var found bool
+
+	for i := 0; i < len(header); {
+		// parameter name
+		start := i
+		for i < len(header) && header[i] != '=' && header[i] != ';' && header[i] != ',' {
+			i++
+		}
+		key := strings.Trim(header[start:i], " ")
+
+		// parameter value
+		var value string
+		if i < len(header) && header[i] == '=' {
+			i++
+			for i < len(header) && header[i] == ' ' {
+				i++
+			}
+			if i < len(header) && header[i] == '"' {
+				value, i = forwardedUnquote(header, i)
+			} else {
+				start = i
+				for i < len(header) && header[i] != ';' && header[i] != ',' {
+					i++
+				}
+				value = strings.TrimRight(header[start:i], " ")
+			}
+			// skip the garbage after a quoted value
+			for i < len(header) && header[i] != ';' && header[i] != ',' {
+				i++
+			}
+		}
+
+		switch {
+		case utils.EqualFold(key, "for"):
+			element.For, found = value, true
+		case utils.EqualFold(key, "by"):
+			element.By, found = value, true
+		case utils.EqualFold(key, "host"):
+			element.Host, found = value, true
+		case utils.EqualFold(key, "proto"):
+			element.Proto, found = value, true
+		}
+
+		if i >= len(header) || header[i] == ',' {
+			if found {
+				elements = append(elements, element)
+			}
+			element, found = ForwardedElement{}, false
+		}
+		i++
+	}
+
+	return elements
+}
+
+// Forwarded returns the elements of the Forwarded request headers in the order of the proxies.
+// The headers are ignored if the proxy isn't trusted, see Config.EnableTrustedProxyCheck.
+// Returned value is only valid within the handler. Do not store any references.
+// Make copies or use the Immutable setting instead.
+func (c *DefaultCtx) Forwarded() []ForwardedElement {
+	if !c.IsProxyTrusted() {
+		return nil
+	}
+	var elements []ForwardedElement
+	for _, header := range c.fasthttp.Request.Header.PeekAll(HeaderForwarded) {
+		elements = append(elements, ParseForwarded(c.getString(header))...)
+	}
+	return elements
+}
+
+// forwardedNode encloses IPv6 addresses in brackets, as required for the nodes of the Forwarded header.
+func forwardedNode(node string) string {
+	if utils.IsIPv6(node) {
+		return "[" + node + "]"
+	}
+	return node
+}
+
+// forwardedQuote quotes the value if it isn't a token.
+func forwardedQuote(value string) string {
+	for i := 0; i < len(value); i++ {
+		if !isForwardedTokenChar(value[i]) {
+			var b strings.Builder
+			b.WriteByte('"')
+			for j := 0; j < len(value); j++ {
+				if value[j] == '"' || value[j] == '\\' {
+					b.WriteByte('\\')
+				}
+				b.WriteByte(value[j])
+			}
+			b.WriteByte('"')
+			return b.String()
+		}
+	}
+	return value
+}
+
+// forwardedUnquote returns the unquoted value of the quoted string which starts at i
+// and the position after the closing quote.
+func forwardedUnquote(header string, i int) (string, int) {
+	i++ // opening quote
+	start := i
+	for i < len(header) && header[i] != '"' && header[i] != '\\' {
+		i++
+	}
+	if i >= len(header) || header[i] == '"' {
+		// no escaped chars, the value can be sliced
+		return header[start:i], i + 1
+	}
+
+	var b strings.Builder
+	b.WriteString(header[start:i])
+	for ; i < len(header) && header[i] != '"'; i++ {
+		if header[i] == '\\' && i+1 < len(header) {
+			i++
+		}
+		b.WriteByte(header[i])
+	}
+	return b.String(), i + 1
+}
+
+// isForwardedTokenChar reports whether c is a token char of RFC 7230.
+func isForwardedTokenChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	default:
+		return strings.IndexByte("!#$%&'*+-.^_`|~", c) != -1
+	}
+}
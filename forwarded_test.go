@@ -0,0 +1,148 @@
+package fiber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_ParseForwarded
+func Test_ParseForwarded(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		header   string
+		elements []ForwardedElement
+	}{
+		{header: "", elements: nil},
+		{header: "for=192.0.2.60", elements: []ForwardedElement{{For: "192.0.2.60"}}},
+		{
+			header: `For="[2001:db8:cafe::17]:4711";Proto=https;by=203.0.113.43;host=example.com, for=198.51.100.17`,
+			elements: []ForwardedElement{
+				{For: "[2001:db8:cafe::17]:4711", By: "203.0.113.43", Host: "example.com", Proto: "https"},
+				{For: "198.51.100.17"},
+			},
+		},
+		{
+			header:   ` for = unknown ; host="a.com,b.com;c" ; secret=x `,
+			elements: []ForwardedElement{{For: "unknown", Host: "a.com,b.com;c"}},
+		},
+		{header: `for="_hidden\"\\";proto`, elements: []ForwardedElement{{For: `_hidden"\`}}},
+		{header: `for="unterminated`, elements: []ForwardedElement{{For: "unterminated"}}},
+		{header: `, ;secret=1, for=1.1.1.1`, elements: []ForwardedElement{{For: "1.1.1.1"}}},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.elements, ParseForwarded(tt.header), tt.header)
+	}
+}
+
+// go test -run Test_FormatForwarded
+func Test_FormatForwarded(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "", FormatForwarded())
+	require.Equal(t, "for=192.0.2.60;proto=http", ForwardedElement{For: "192.0.2.60", Proto: "http"}.String())
+	require.Equal(t,
+		`for="[2001:db8::17]";by="[2001:db8::1]:80";host="example.com:8080", for="_a \"b\"", for=unknown`,
+		FormatForwarded(
+			ForwardedElement{For: "2001:db8::17", By: "[2001:db8::1]:80", Host: "example.com:8080"},
+			ForwardedElement{},
+			ForwardedElement{For: `_a "b"`},
+			ForwardedElement{For: "unknown"},
+		),
+	)
+
+	// the formatted header can be parsed
+	elements := []ForwardedElement{
+		{For: "[2001:db8::17]:4711", By: "_proxy", Host: "example.com", Proto: "https"},
+		{For: "192.0.2.60"},
+	}
+	require.Equal(t, elements, ParseForwarded(FormatForwarded(elements...)))
+}
+
+// go test -run Test_ForwardedElement_IP
+func Test_ForwardedElement_IP(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "192.0.2.60", ForwardedElement{For: "192.0.2.60"}.IP())
+	require.Equal(t, "192.0.2.60", ForwardedElement{For: "192.0.2.60:4711"}.IP())
+	require.Equal(t, "2001:db8::17", ForwardedElement{For: "[2001:db8::17]:4711"}.IP())
+	require.Equal(t, "2001:db8::17", ForwardedElement{For: "[2001:db8::17]"}.IP())
+	require.Equal(t, "2001:db8::17", ForwardedElement{For: "2001:db8::17"}.IP())
+	require.Equal(t, "", ForwardedElement{For: "unknown"}.IP())
+	require.Equal(t, "", ForwardedElement{For: "_hidden"}.IP())
+	require.Equal(t, "", ForwardedElement{}.IP())
+}
+
+// go test -run Test_Ctx_Forwarded
+func Test_Ctx_Forwarded(t *testing.T) {
+	t.Parallel()
+	app := New(Config{ProxyHeader: HeaderForwarded})
+
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.Add(HeaderForwarded, `for=unknown;proto=https;host=example.com`)
+	fctx.Request.Header.Add(HeaderForwarded, `for="[2001:db8::17]:4711", for=192.0.2.60;host=proxy.local`)
+	fctx.Request.Header.Set(HeaderHost, "backend.local")
+	c := app.AcquireCtx(fctx)
+	defer app.ReleaseCtx(c)
+
+	require.Len(t, c.Forwarded(), 3)
+	require.Equal(t, "unknown", c.IP())
+	require.Equal(t, []string{"unknown", "2001:db8::17", "192.0.2.60"}, c.IPs())
+	require.Equal(t, schemeHTTPS, c.Scheme())
+	require.Equal(t, "example.com", c.Host())
+	require.Equal(t, "example.com", c.Hostname())
+
+	// X-Forwarded-Host is preferred
+	c.Request().Header.Set(HeaderXForwardedHost, "forwarded.local")
+	require.Equal(t, "forwarded.local", c.Host())
+}
+
+// go test -run Test_Ctx_Forwarded_IPValidation
+func Test_Ctx_Forwarded_IPValidation(t *testing.T) {
+	t.Parallel()
+	app := New(Config{ProxyHeader: HeaderForwarded, EnableIPValidation: true})
+
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.Set(HeaderForwarded, `for=unknown, for=_hidden, for="[2001:db8::17]:4711", for=192.0.2.60`)
+	c := app.AcquireCtx(fctx)
+
+	require.Equal(t, "2001:db8::17", c.IP())
+	require.Equal(t, []string{"2001:db8::17", "192.0.2.60"}, c.IPs())
+	app.ReleaseCtx(c)
+
+	// the remote IP is returned if there is no valid IP
+	fctx = &fasthttp.RequestCtx{}
+	fctx.Request.Header.Set(HeaderForwarded, `for=unknown;proto=https`)
+	c = app.AcquireCtx(fctx)
+	require.Equal(t, "0.0.0.0", c.IP())
+	require.Empty(t, c.IPs())
+	app.ReleaseCtx(c)
+}
+
+// go test -run Test_Ctx_Forwarded_TrustedProxy
+func Test_Ctx_Forwarded_TrustedProxy(t *testing.T) {
+	t.Parallel()
+	header := `for=192.0.2.60;proto=https;host=example.com`
+
+	app := New(Config{ProxyHeader: HeaderForwarded, EnableTrustedProxyCheck: true, TrustedProxies: []string{"0.0.0.0"}})
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.Header.Set(HeaderForwarded, header)
+	fctx.Request.Header.Set(HeaderHost, "backend.local")
+	c := app.AcquireCtx(fctx)
+	require.Equal(t, "192.0.2.60", c.IP())
+	require.Equal(t, schemeHTTPS, c.Scheme())
+	require.Equal(t, "example.com", c.Host())
+	app.ReleaseCtx(c)
+
+	// the header is ignored if the proxy isn't trusted
+	app = New(Config{ProxyHeader: HeaderForwarded, EnableTrustedProxyCheck: true, TrustedProxies: []string{"0.8.0.1"}})
+	fctx = &fasthttp.RequestCtx{}
+	fctx.Request.Header.Set(HeaderForwarded, header)
+	fctx.Request.Header.Set(HeaderHost, "backend.local")
+	c = app.AcquireCtx(fctx)
+	require.Nil(t, c.Forwarded())
+	require.Equal(t, "0.0.0.0", c.IP())
+	require.Empty(t, c.IPs())
+	require.Equal(t, schemeHTTP, c.Scheme())
+	require.Equal(t, "backend.local", c.Host())
+	app.ReleaseCtx(c)
+}
@@ -65,12 +65,15 @@ func (grp *Group) Name(name string) Router {
 //	 	subApp := fiber.New()
 //		app.Use("/mounted-path", subApp)
 //
+// The handlers can be skipped for some of the requests with the conditions Skip and Only.
+//
 // This method will match all HTTP verbs: GET, POST, PUT, HEAD etc...
 func (grp *Group) Use(args ...any) Router {
 	var subApp *App
 	var prefix string
 	var prefixes []string
 	var handlers []Handler
+	var conditions []Condition
 
 	for i := 0; i < len(args); i++ {
 		switch arg := args[i].(type) {
@@ -82,11 +85,15 @@ func (grp *Group) Use(args ...any) Router {
 			prefixes = arg
 		case Handler:
 			handlers = append(handlers, arg)
+		case Condition:
+			conditions = append(conditions, arg)
 		default:
 			panic(fmt.Sprintf("use: invalid handler %v\n", reflect.TypeOf(arg)))
 		}
 	}
 
+	handlers = applyConditions(handlers, conditions)
+
 	if len(prefixes) == 0 {
 		prefixes = append(prefixes, prefix)
 	}
@@ -0,0 +1,124 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"github.com/valyala/fasthttp"
+)
+
+// HeaderPolicy declares how the headers of the responses are changed before they're sent,
+// e.g. to set the security headers or to strip the internal headers of the backends in
+// one place instead of in every handler. The rules are applied in the order Rename, Strip,
+// Default and Set.
+type HeaderPolicy struct {
+	// Rename renames the headers with their values, e.g. from "X-Internal-Id" to "X-Request-Id".
+	Rename map[string]string `json:"rename"`
+	// Strip removes the headers, e.g. "X-Powered-By" or the internal headers of the backends.
+	Strip []string `json:"strip"`
+	// Default sets the headers which the handlers didn't set, e.g. "X-Frame-Options".
+	Default map[string]string `json:"default"`
+	// Set sets the headers and overwrites the headers of the handlers, e.g. "Server".
+	Set map[string]string `json:"set"`
+}
+
+// isZero reports whether the policy has no rules.
+func (p *HeaderPolicy) isZero() bool {
+	return len(p.Rename) == 0 && len(p.Strip) == 0 && len(p.Default) == 0 && len(p.Set) == 0
+}
+
+// apply applies the rules of the policy to the headers.
+func (p *HeaderPolicy) apply(header *fasthttp.ResponseHeader) {
+	for from, to := range p.Rename {
+		values := header.PeekAll(from)
+		if len(values) == 0 {
+			continue
+		}
+		// the values are copied, because they are changed by Del
+		renamed := make([]string, len(values))
+		for i, value := range values {
+			renamed[i] = string(value)
+		}
+		header.Del(from)
+		header.Del(to)
+		for _, value := range renamed {
+			header.Add(to, value)
+		}
+	}
+	for _, key := range p.Strip {
+		header.Del(key)
+	}
+	for key, value := range p.Default {
+		if len(header.Peek(key)) == 0 {
+			header.Set(key, value)
+		}
+	}
+	for key, value := range p.Set {
+		header.Set(key, value)
+	}
+}
+
+// HeaderPolicy sets the header policy of the latest registered route, which is applied to
+// all of its responses, including the responses of errors. If it is set for a middleware,
+// e.g. of a group, it is applied to all requests which are handled by the middleware.
+// Unlike the other route settings, the policies of all matching routes are applied, those
+// of the middlewares first, after the Config.HeaderPolicy of the app.
+//
+//	app.Group("/admin", authHandler).HeaderPolicy(fiber.HeaderPolicy{
+//	    Set: map[string]string{fiber.HeaderXFrameOptions: "DENY"},
+//	})
+func (app *App) HeaderPolicy(policy HeaderPolicy) Router {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	app.headerPolicies = true
+	for _, routes := range app.stack {
+		for _, route := range routes {
+			isMethodValid := route.Method == app.latestRoute.Method || app.latestRoute.use ||
+				(app.latestRoute.Method == MethodGet && route.Method == MethodHead)
+
+			// middlewares with the same path keep their policy
+			if route.Path == app.latestRoute.Path && route.use == app.latestRoute.use && isMethodValid {
+				route.headerPolicy = &policy
+			}
+		}
+	}
+
+	return app
+}
+
+// HeaderPolicy sets the header policy of the latest registered route.
+func (grp *Group) HeaderPolicy(policy HeaderPolicy) Router {
+	grp.app.HeaderPolicy(policy)
+
+	return grp
+}
+
+// applyHeaderPolicies applies the header policy of the app and the policies of the matching
+// routes, the middlewares before the first matching handler are included.
+func (app *App) applyHeaderPolicies(c CustomCtx) {
+	if c.Hijacked() {
+		return
+	}
+
+	header := &c.Response().Header
+	app.config.HeaderPolicy.apply(header)
+
+	tree, ok := c.getTreeStack()[c.getMethodINT()][c.getTreePath()]
+	if !ok {
+		tree = c.getTreeStack()[c.getMethodINT()][""]
+	}
+
+	for _, route := range tree {
+		if !route.match(c.getDetectionPath(), c.Path(), c.getValues()) {
+			continue
+		}
+		if route.headerPolicy != nil {
+			route.headerPolicy.apply(header)
+		}
+		if !route.use {
+			break
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_Route_HeaderPolicy
+func Test_Route_HeaderPolicy(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		HeaderPolicy: HeaderPolicy{
+			Strip:   []string{"X-Powered-By"},
+			Default: map[string]string{HeaderXFrameOptions: "SAMEORIGIN"},
+		},
+	})
+	api := app.Group("/api", func(c Ctx) error {
+		return c.Next()
+	}).HeaderPolicy(HeaderPolicy{
+		Rename: map[string]string{"X-Internal-Id": HeaderXRequestID},
+		Strip:  []string{"X-Backend"},
+		Set:    map[string]string{HeaderXFrameOptions: "DENY"},
+	})
+	api.Get("/users", func(c Ctx) error {
+		c.Set("X-Powered-By", "php")
+		c.Set("X-Backend", "users-1")
+		c.Set("X-Internal-Id", "42")
+		c.Set(HeaderXFrameOptions, "ALLOWALL")
+		return c.SendString("users")
+	})
+	api.Get("/error", func(c Ctx) error {
+		c.Set("X-Backend", "users-1")
+		return ErrBadRequest
+	}).HeaderPolicy(HeaderPolicy{
+		Default: map[string]string{HeaderCacheControl: "no-store"},
+	})
+	app.Get("/public", func(c Ctx) error {
+		c.Set("X-Powered-By", "php")
+		c.Set("X-Backend", "public-1")
+		c.Set(HeaderXFrameOptions, "DENY")
+		return c.SendString("public")
+	})
+
+	testCases := []struct {
+		target   string
+		headers  map[string]string
+		stripped []string
+		status   int
+	}{
+		{
+			target:   "/api/users",
+			status:   StatusOK,
+			headers:  map[string]string{HeaderXFrameOptions: "DENY", HeaderXRequestID: "42"},
+			stripped: []string{"X-Powered-By", "X-Backend", "X-Internal-Id"},
+		},
+		{
+			target:   "/api/error",
+			status:   StatusBadRequest,
+			headers:  map[string]string{HeaderXFrameOptions: "DENY", HeaderCacheControl: "no-store"},
+			stripped: []string{"X-Backend"},
+		},
+		{
+			target:   "/public",
+			status:   StatusOK,
+			headers:  map[string]string{HeaderXFrameOptions: "DENY", "X-Backend": "public-1"},
+			stripped: []string{"X-Powered-By"},
+		},
+		{
+			target:  "/missing",
+			status:  StatusNotFound,
+			headers: map[string]string{HeaderXFrameOptions: "SAMEORIGIN"},
+		},
+	}
+
+	for _, tc := range testCases {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, tc.target, nil))
+		require.NoError(t, err)
+		require.Equal(t, tc.status, resp.StatusCode, tc.target)
+		for key, value := range tc.headers {
+			require.Equal(t, value, resp.Header.Get(key), tc.target+" "+key)
+		}
+		for _, key := range tc.stripped {
+			require.Empty(t, resp.Header.Values(key), tc.target+" "+key)
+		}
+	}
+}
+
+// go test -run Test_HeaderPolicy_Rename
+func Test_HeaderPolicy_Rename(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		HeaderPolicy: HeaderPolicy{Rename: map[string]string{"X-Upstream-Link": HeaderLink}},
+	})
+	app.Get("/", func(c Ctx) error {
+		c.Set(HeaderLink, "</old>; rel=prev")
+		c.Response().Header.Add("X-Upstream-Link", "</a>; rel=next")
+		c.Response().Header.Add("X-Upstream-Link", "</b>; rel=last")
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"</a>; rel=next", "</b>; rel=last"}, resp.Header.Values(HeaderLink))
+	require.Empty(t, resp.Header.Values("X-Upstream-Link"))
+}
@@ -39,6 +39,56 @@ type acceptedType struct {
 
 type headerParams map[string][]byte
 
+// setBodyWriter streams the response body, which is written by the function through a pipe while
+// the response is sent, so the writes block while the client is slow. The size is -1 if it's unknown.
+// Errors of the function are logged with the message and abort the body, so the connection is closed
+// and the client doesn't receive a truncated body as complete.
+func (c *DefaultCtx) setBodyWriter(size int64, msg string, write func(w io.Writer) error) {
+	app := c.app
+	pr, pw := io.Pipe()
+	c.setBodyStream(&pipeBodyReader{PipeReader: pr, start: func() {
+		go func() {
+			err := write(pw)
+			if err != nil {
+				app.logw(log.LevelError, msg, "error", err)
+			}
+			pw.CloseWithError(err) //nolint:errcheck // always returns nil
+		}()
+	}}, int(size))
+}
+
+// pipeBodyReader starts the writer of the pipe when it's read first, the pipe is closed by fasthttp.
+type pipeBodyReader struct {
+	*io.PipeReader
+	start func()
+	once  sync.Once
+}
+
+// Read starts the writer and reads the body.
+func (r *pipeBodyReader) Read(p []byte) (int, error) {
+	r.once.Do(r.start)
+	return r.PipeReader.Read(p) //nolint:wrapcheck // the error of the writer is returned unchanged
+}
+
+// fileStreamSize returns the remaining size of a regular file, or -1 for all other streams.
+func fileStreamSize(stream io.Reader) int {
+	file, ok := stream.(*os.File)
+	if !ok {
+		return -1
+	}
+
+	info, err := file.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return -1
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil || offset > info.Size() {
+		return -1
+	}
+
+	return int(info.Size() - offset)
+}
+
 // getTLSConfig returns a net listener's tls config
 func getTLSConfig(ln net.Listener) *tls.Config {
 	// Get listener type
@@ -112,9 +162,9 @@ func (app *App) methodExist(c *DefaultCtx) bool {
 		// Reset stack index
 		c.setIndexRoute(-1)
 
-		tree, ok := c.App().treeStack[i][c.getTreePath()]
+		tree, ok := c.getTreeStack()[i][c.getTreePath()]
 		if !ok {
-			tree = c.App().treeStack[i][""]
+			tree = c.getTreeStack()[i][""]
 		}
 		// Get stack length
 		lenr := len(tree) - 1
@@ -156,9 +206,9 @@ func (app *App) methodExistCustom(c CustomCtx) bool {
 		// Reset stack index
 		c.setIndexRoute(-1)
 
-		tree, ok := c.App().treeStack[i][c.getTreePath()]
+		tree, ok := c.getTreeStack()[i][c.getTreePath()]
 		if !ok {
-			tree = c.App().treeStack[i][""]
+			tree = c.getTreeStack()[i][""]
 		}
 		// Get stack length
 		lenr := len(tree) - 1
@@ -725,12 +775,17 @@ const (
 	MIMEOctetStream           = "application/octet-stream"
 	MIMEMultipartForm         = "multipart/form-data"
 
+	MIMEApplicationCloudEventsJSON = "application/cloudevents+json"
+	MIMETextCSV                    = "text/csv"
+	MIMEApplicationXLSX            = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
 	MIMETextXMLCharsetUTF8         = "text/xml; charset=utf-8"
 	MIMETextHTMLCharsetUTF8        = "text/html; charset=utf-8"
 	MIMETextPlainCharsetUTF8       = "text/plain; charset=utf-8"
 	MIMETextJavaScriptCharsetUTF8  = "text/javascript; charset=utf-8"
 	MIMEApplicationXMLCharsetUTF8  = "application/xml; charset=utf-8"
 	MIMEApplicationJSONCharsetUTF8 = "application/json; charset=utf-8"
+	MIMETextCSVCharsetUTF8         = "text/csv; charset=utf-8"
 	// Deprecated: use MIMETextJavaScriptCharsetUTF8 instead
 	MIMEApplicationJavaScriptCharsetUTF8 = "application/javascript; charset=utf-8"
 )
@@ -866,6 +921,8 @@ const (
 	HeaderExpires                            = "Expires"
 	HeaderPragma                             = "Pragma"
 	HeaderWarning                            = "Warning"
+	HeaderCDNCacheControl                    = "CDN-Cache-Control"
+	HeaderSurrogateControl                   = "Surrogate-Control"
 	HeaderAcceptCH                           = "Accept-CH"
 	HeaderAcceptCHLifetime                   = "Accept-CH-Lifetime"
 	HeaderContentDPR                         = "Content-DPR"
@@ -961,6 +1018,7 @@ const (
 	HeaderAcceptPushPolicy                   = "Accept-Push-Policy"
 	HeaderAcceptSignature                    = "Accept-Signature"
 	HeaderAltSvc                             = "Alt-Svc"
+	HeaderAvailableDictionary                = "Available-Dictionary"
 	HeaderDate                               = "Date"
 	HeaderIndex                              = "Index"
 	HeaderLargeAllocation                    = "Large-Allocation"
@@ -972,11 +1030,15 @@ const (
 	HeaderSignedHeaders                      = "Signed-Headers"
 	HeaderSourceMap                          = "SourceMap"
 	HeaderUpgrade                            = "Upgrade"
+	HeaderUseAsDictionary                    = "Use-As-Dictionary"
+	HeaderXAppVersion                        = "X-App-Version"
 	HeaderXDNSPrefetchControl                = "X-DNS-Prefetch-Control"
 	HeaderXPingback                          = "X-Pingback"
 	HeaderXRequestID                         = "X-Request-ID"
 	HeaderXRequestedWith                     = "X-Requested-With"
+	HeaderXResponseTruncated                 = "X-Response-Truncated"
 	HeaderXRobotsTag                         = "X-Robots-Tag"
+	HeaderXTotalCount                        = "X-Total-Count"
 	HeaderXUACompatible                      = "X-UA-Compatible"
 	HeaderAccessControlAllowPrivateNetwork   = "Access-Control-Allow-Private-Network"
 	HeaderAccessControlRequestPrivateNetwork = "Access-Control-Request-Private-Network"
@@ -1,19 +1,36 @@
 package fiber
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
 	"github.com/gofiber/fiber/v3/log"
 )
 
 // OnRouteHandler Handlers define a function to create hooks for Fiber.
 type (
-	OnRouteHandler     = func(Route) error
-	OnNameHandler      = OnRouteHandler
-	OnGroupHandler     = func(Group) error
-	OnGroupNameHandler = OnGroupHandler
-	OnListenHandler    = func(ListenData) error
-	OnShutdownHandler  = func() error
-	OnForkHandler      = func(int) error
-	OnMountHandler     = func(*App) error
+	OnRouteHandler         = func(Route) error
+	OnNameHandler          = OnRouteHandler
+	OnGroupHandler         = func(Group) error
+	OnGroupNameHandler     = OnGroupHandler
+	OnListenHandler        = func(ListenData) error
+	OnShutdownHandler      = func() error
+	OnForkHandler          = func(int) error
+	OnMountHandler         = func(*App) error
+	OnConfigChangeHandler  = func(prev, next Config) error
+	OnShutdownNamedHandler = func(ctx context.Context) error
+	OnSlowRequestHandler   = func(SlowRequest) error
+	OnAbortHandler         = func(Ctx) error
+	OnConnOpenHandler      = func(ConnInfo) error
+	OnConnCloseHandler     = OnConnOpenHandler
+	OnTLSHandshakeHandler  = func(TLSHandshake) error
+	OnALPNHandler          = func(ALPNSelection) error
+	OnWarmupHandler        = func(ctx context.Context) error
 )
 
 // Hooks is a struct to use it with App.
@@ -22,14 +39,76 @@ type Hooks struct {
 	app *App
 
 	// Hooks
-	onRoute     []OnRouteHandler
-	onName      []OnNameHandler
-	onGroup     []OnGroupHandler
-	onGroupName []OnGroupNameHandler
-	onListen    []OnListenHandler
-	onShutdown  []OnShutdownHandler
-	onFork      []OnForkHandler
-	onMount     []OnMountHandler
+	onRoute         []OnRouteHandler
+	onName          []OnNameHandler
+	onGroup         []OnGroupHandler
+	onGroupName     []OnGroupNameHandler
+	onListen        []OnListenHandler
+	onShutdown      []OnShutdownHandler
+	onFork          []OnForkHandler
+	onMount         []OnMountHandler
+	onConfigChange  []OnConfigChangeHandler
+	onShutdownNamed []shutdownHook
+	onSlowRequest   []OnSlowRequestHandler
+	onAbort         []OnAbortHandler
+	onConnOpen      []OnConnOpenHandler
+	onConnClose     []OnConnCloseHandler
+	onTLSHandshake  []OnTLSHandshakeHandler
+	onALPN          []OnALPNHandler
+	onWarmup        []warmupHook
+}
+
+// ShutdownHookConfig is a struct to use it with OnShutdownNamed
+type ShutdownHookConfig struct {
+	// DependsOn lists the names of the hooks which are still needed by this hook,
+	// e.g. a websocket hub which depends on the database pool.
+	// They are executed after this hook.
+	//
+	// Optional. Default: nil
+	DependsOn []string
+
+	// Timeout is the maximum duration of the hook.
+	// The hook is always limited by the context passed to ShutdownWithContext.
+	// A hook which didn't finish in time may still run, so the hooks it depends on
+	// are skipped with ErrShutdownHookSkipped unless it returned by their turn.
+	//
+	// Optional. Default: 0 (no timeout)
+	Timeout time.Duration
+}
+
+// WarmupPolicy defines what happens when a warm-up hook fails.
+type WarmupPolicy int
+
+const (
+	// WarmupAbort stops the start of the server, Listen returns the error of the hook.
+	WarmupAbort WarmupPolicy = iota
+	// WarmupDegrade logs the error and makes the app ready anyway, the error is reported by WarmupErrors.
+	WarmupDegrade
+)
+
+// WarmupConfig is a struct to use it with OnWarmup
+type WarmupConfig struct {
+	// Timeout is the maximum duration of the hook, it is exceeded like a failure.
+	//
+	// Optional. Default: 0 (no timeout)
+	Timeout time.Duration
+
+	// Policy defines if a failure of the hook stops the start or degrades the app.
+	//
+	// Optional. Default: WarmupAbort
+	Policy WarmupPolicy
+}
+
+type warmupHook struct {
+	name    string
+	handler OnWarmupHandler
+	config  WarmupConfig
+}
+
+type shutdownHook struct {
+	name    string
+	handler OnShutdownNamedHandler
+	config  ShutdownHookConfig
 }
 
 // ListenData is a struct to use it with OnListenHandler
@@ -39,17 +118,52 @@ type ListenData struct {
 	TLS  bool
 }
 
+// TLSHandshake is a struct to use it with OnTLSHandshakeHandler
+type TLSHandshake struct {
+	// Conn is the connection of the handshake.
+	Conn ConnInfo
+	// ServerName is the server name requested by the client with SNI.
+	ServerName string
+	// NegotiatedProtocol is the protocol selected with ALPN, e.g. "h2".
+	NegotiatedProtocol string
+	// PeerCertificates are the certificates sent by the client, the leaf first.
+	PeerCertificates []*x509.Certificate
+	// Version is the TLS version, e.g. tls.VersionTLS13.
+	Version uint16
+	// CipherSuite is the cipher suite, e.g. tls.TLS_AES_128_GCM_SHA256.
+	CipherSuite uint16
+	// DidResume reports whether a previous session was resumed.
+	DidResume bool
+}
+
+// ALPNSelection is a struct to use it with OnALPNHandler
+type ALPNSelection struct {
+	// Conn is the connection of the handshake.
+	Conn ConnInfo
+	// Protocol is the selected protocol, or empty if the server doesn't support ALPN.
+	Protocol string
+	// Offered are the protocols offered by the client, in its order of preference.
+	Offered []string
+}
+
 func newHooks(app *App) *Hooks {
 	return &Hooks{
-		app:         app,
-		onRoute:     make([]OnRouteHandler, 0),
-		onGroup:     make([]OnGroupHandler, 0),
-		onGroupName: make([]OnGroupNameHandler, 0),
-		onName:      make([]OnNameHandler, 0),
-		onListen:    make([]OnListenHandler, 0),
-		onShutdown:  make([]OnShutdownHandler, 0),
-		onFork:      make([]OnForkHandler, 0),
-		onMount:     make([]OnMountHandler, 0),
+		app:            app,
+		onRoute:        make([]OnRouteHandler, 0),
+		onGroup:        make([]OnGroupHandler, 0),
+		onGroupName:    make([]OnGroupNameHandler, 0),
+		onName:         make([]OnNameHandler, 0),
+		onListen:       make([]OnListenHandler, 0),
+		onShutdown:     make([]OnShutdownHandler, 0),
+		onFork:         make([]OnForkHandler, 0),
+		onMount:        make([]OnMountHandler, 0),
+		onConfigChange: make([]OnConfigChangeHandler, 0),
+		onSlowRequest:  make([]OnSlowRequestHandler, 0),
+		onAbort:        make([]OnAbortHandler, 0),
+		onConnOpen:     make([]OnConnOpenHandler, 0),
+		onConnClose:    make([]OnConnCloseHandler, 0),
+		onTLSHandshake: make([]OnTLSHandshakeHandler, 0),
+		onALPN:         make([]OnALPNHandler, 0),
 	}
 }
 
@@ -103,6 +217,28 @@ func (h *Hooks) OnShutdown(handler ...OnShutdownHandler) {
 	h.app.mutex.Unlock()
 }
 
+// OnShutdownNamed is a hook to execute a named user function after the server has been shut down.
+// The named hooks are executed in dependency order: a hook is executed before all hooks it depends on,
+// so components like queues and websocket hubs can be closed before the database pool they use.
+// Hooks without dependencies between them are executed in registration order.
+// Errors of all hooks are aggregated and returned by Shutdown.
+//
+//	app.Hooks().OnShutdownNamed("db", closeDB)
+//	app.Hooks().OnShutdownNamed("hub", closeHub, fiber.ShutdownHookConfig{
+//	    DependsOn: []string{"db"},
+//	    Timeout:   5 * time.Second,
+//	})
+func (h *Hooks) OnShutdownNamed(name string, handler OnShutdownNamedHandler, config ...ShutdownHookConfig) {
+	hook := shutdownHook{name: name, handler: handler}
+	if len(config) > 0 {
+		hook.config = config[0]
+	}
+
+	h.app.mutex.Lock()
+	h.onShutdownNamed = append(h.onShutdownNamed, hook)
+	h.app.mutex.Unlock()
+}
+
 // OnFork is a hook to execute user function after fork process.
 func (h *Hooks) OnFork(handler ...OnForkHandler) {
 	h.app.mutex.Lock()
@@ -119,6 +255,92 @@ func (h *Hooks) OnMount(handler ...OnMountHandler) {
 	h.app.mutex.Unlock()
 }
 
+// OnConfigChange is a hook to execute user functions after the config was reloaded by WatchConfig.
+// The previous and the new config are passed as parameters.
+func (h *Hooks) OnConfigChange(handler ...OnConfigChangeHandler) {
+	h.app.mutex.Lock()
+	h.onConfigChange = append(h.onConfigChange, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnSlowRequest is a hook to execute user functions when a request is still handled
+// after Config.SlowRequestThreshold. It is executed once per request by the watchdog
+// goroutine of the app, while the handler is still running.
+func (h *Hooks) OnSlowRequest(handler ...OnSlowRequestHandler) {
+	h.app.mutex.Lock()
+	h.onSlowRequest = append(h.onSlowRequest, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnAbort is a hook to execute user functions after the handlers of a request
+// whose client closed the connection, e.g. to count the aborted requests.
+// It is executed by the goroutine of the request, the response is discarded.
+func (h *Hooks) OnAbort(handler ...OnAbortHandler) {
+	h.app.mutex.Lock()
+	h.onAbort = append(h.onAbort, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnConnOpen is a hook to execute user functions when a connection is accepted,
+// before the TLS handshake. It is executed by the accept loop of the listener,
+// so it should return quickly. If a handler returns an error, the connection is closed,
+// e.g. to reject the clients of a blocklist.
+func (h *Hooks) OnConnOpen(handler ...OnConnOpenHandler) {
+	h.app.mutex.Lock()
+	h.onConnOpen = append(h.onConnOpen, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnConnClose is a hook to execute user functions when an accepted connection is closed,
+// e.g. to measure the duration of the connections.
+func (h *Hooks) OnConnClose(handler ...OnConnCloseHandler) {
+	h.app.mutex.Lock()
+	h.onConnClose = append(h.onConnClose, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnTLSHandshake is a hook to execute user functions after a TLS handshake was completed,
+// with the negotiated version, cipher suite, server name and client certificates.
+// If a handler returns an error, the handshake is aborted.
+// The hook has to be registered before Listen or Listener.
+func (h *Hooks) OnTLSHandshake(handler ...OnTLSHandshakeHandler) {
+	h.app.mutex.Lock()
+	h.onTLSHandshake = append(h.onTLSHandshake, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnALPN is a hook to execute user functions after the protocol of a TLS connection was
+// selected with ALPN, if the client offered protocols. If a handler returns an error,
+// the handshake is aborted. The hook has to be registered before Listen or Listener.
+func (h *Hooks) OnALPN(handler ...OnALPNHandler) {
+	h.app.mutex.Lock()
+	h.onALPN = append(h.onALPN, handler...)
+	h.app.mutex.Unlock()
+}
+
+// OnWarmup is a named hook to execute a user function after the listener was bound and before
+// the app is ready, e.g. to prime caches, parse templates or run test queries. The server already
+// responds while the hooks are executed, but Ready returns false, so readiness probes fail and
+// the app doesn't receive traffic before it is warm. The hooks are executed in registration order
+// by Listen and Listener. A failed hook stops the start with WarmupAbort, the default policy,
+// and degrades the app with WarmupDegrade.
+//
+//	app.Hooks().OnWarmup("templates", parseTemplates)
+//	app.Hooks().OnWarmup("cache", primeCache, fiber.WarmupConfig{
+//	    Timeout: 30 * time.Second,
+//	    Policy:  fiber.WarmupDegrade,
+//	})
+func (h *Hooks) OnWarmup(name string, handler OnWarmupHandler, config ...WarmupConfig) {
+	hook := warmupHook{name: name, handler: handler}
+	if len(config) > 0 {
+		hook.config = config[0]
+	}
+
+	h.app.mutex.Lock()
+	h.onWarmup = append(h.onWarmup, hook)
+	h.app.mutex.Unlock()
+}
+
 func (h *Hooks) executeOnRouteHooks(route Route) error {
 	// Check mounting
 	if h.app.mountFields.mountPath != "" {
@@ -194,7 +416,7 @@ func (h *Hooks) executeOnListenHooks(listenData ListenData) error {
 func (h *Hooks) executeOnShutdownHooks() {
 	for _, v := range h.onShutdown {
 		if err := v(); err != nil {
-			log.Errorf("failed to call shutdown hook: %v", err)
+			h.app.logw(log.LevelError, "failed to call shutdown hook", "error", err)
 		}
 	}
 }
@@ -202,7 +424,7 @@ func (h *Hooks) executeOnShutdownHooks() {
 func (h *Hooks) executeOnForkHooks(pid int) {
 	for _, v := range h.onFork {
 		if err := v(pid); err != nil {
-			log.Errorf("failed to call fork hook: %v", err)
+			h.app.logw(log.LevelError, "failed to call fork hook", "pid", pid, "error", err)
 		}
 	}
 }
@@ -216,3 +438,310 @@ func (h *Hooks) executeOnMountHooks(app *App) error {
 
 	return nil
 }
+
+func (h *Hooks) executeOnConfigChangeHooks(prev, next Config) {
+	for _, v := range h.onConfigChange {
+		if err := v(prev, next); err != nil {
+			h.app.logw(log.LevelError, "failed to call config change hook", "error", err)
+		}
+	}
+}
+
+func (h *Hooks) executeOnSlowRequestHooks(req SlowRequest) {
+	for _, v := range h.onSlowRequest {
+		if err := v(req); err != nil {
+			h.app.logw(log.LevelError, "failed to call slow request hook", "error", err)
+		}
+	}
+}
+
+func (h *Hooks) executeOnAbortHooks(c Ctx) {
+	for _, v := range h.onAbort {
+		if err := v(c); err != nil {
+			h.app.logw(log.LevelError, "failed to call abort hook", "error", err)
+		}
+	}
+}
+
+func (h *Hooks) executeOnConnOpenHooks(conn ConnInfo) error {
+	for _, v := range h.onConnOpen {
+		if err := v(conn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Hooks) executeOnConnCloseHooks(conn ConnInfo) {
+	for _, v := range h.onConnClose {
+		if err := v(conn); err != nil {
+			h.app.logw(log.LevelError, "failed to call connection close hook", "remote_addr", conn.RemoteAddr, "error", err)
+		}
+	}
+}
+
+func (h *Hooks) executeOnTLSHandshakeHooks(handshake TLSHandshake) error {
+	for _, v := range h.onTLSHandshake {
+		if err := v(handshake); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *Hooks) executeOnALPNHooks(selection ALPNSelection) error {
+	for _, v := range h.onALPN {
+		if err := v(selection); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hookTLSConfig returns a TLS config which executes the OnTLSHandshake and OnALPN hooks.
+// The config is cloned for each handshake, so it is only wrapped if the hooks are registered.
+func (app *App) hookTLSConfig(config *tls.Config) *tls.Config {
+	if len(app.hooks.onTLSHandshake) == 0 && len(app.hooks.onALPN) == 0 {
+		return config
+	}
+
+	hooked := config.Clone()
+	hooked.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		connConfig := config
+		if config.GetConfigForClient != nil {
+			custom, err := config.GetConfigForClient(hello)
+			if err != nil {
+				return nil, err
+			}
+			if custom != nil {
+				connConfig = custom
+			}
+		}
+		connConfig = connConfig.Clone()
+
+		conn := ConnInfo{Since: time.Now()}
+		if lc, ok := hello.Conn.(*limitConn); ok {
+			conn = lc.info()
+		} else if hello.Conn != nil {
+			conn.RemoteAddr, conn.LocalAddr = hello.Conn.RemoteAddr().String(), hello.Conn.LocalAddr().String()
+		}
+		offered := slices.Clone(hello.SupportedProtos)
+		verifyConnection := connConfig.VerifyConnection
+
+		connConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			if verifyConnection != nil {
+				if err := verifyConnection(state); err != nil {
+					return err
+				}
+			}
+			if err := app.hooks.executeOnTLSHandshakeHooks(TLSHandshake{
+				Conn:               conn,
+				ServerName:         state.ServerName,
+				NegotiatedProtocol: state.NegotiatedProtocol,
+				PeerCertificates:   state.PeerCertificates,
+				Version:            state.Version,
+				CipherSuite:        state.CipherSuite,
+				DidResume:          state.DidResume,
+			}); err != nil {
+				return err
+			}
+			if len(offered) == 0 {
+				return nil
+			}
+			return app.hooks.executeOnALPNHooks(ALPNSelection{
+				Conn:     conn,
+				Protocol: state.NegotiatedProtocol,
+				Offered:  offered,
+			})
+		}
+		return connConfig, nil
+	}
+
+	return hooked
+}
+
+// hookListenerTLSConfig installs the OnTLSHandshake and OnALPN hooks in the config of a TLS listener
+// passed to Listener, since the listener can't be wrapped with a new config.
+func (app *App) hookListenerTLSConfig(config *tls.Config) {
+	hooked := app.hookTLSConfig(config.Clone())
+	config.GetConfigForClient = hooked.GetConfigForClient
+}
+
+func (h *Hooks) executeOnShutdownNamedHooks(ctx context.Context) error {
+	h.app.mutex.Lock()
+	hooks := make([]shutdownHook, len(h.onShutdownNamed))
+	copy(hooks, h.onShutdownNamed)
+	h.app.mutex.Unlock()
+
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	order, err := shutdownHookOrder(hooks)
+	errs := []error{err}
+	// finished reports if the hooks returned, the hooks which timed out may still run
+	finished := make(map[string][]<-chan struct{}, len(order))
+	for _, hook := range order {
+		// the hooks which depend on this hook ran before, they must have returned
+		if dependent := runningDependent(order, hook.name, finished); dependent != "" {
+			errs = append(errs, fmt.Errorf("shutdown hook %q: %w: %q", hook.name, ErrShutdownHookSkipped, dependent))
+			finished[hook.name] = append(finished[hook.name], nil)
+			continue
+		}
+		done, err := hook.run(ctx, h.app.config.Clock)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook %q: %w", hook.name, err))
+		}
+		finished[hook.name] = append(finished[hook.name], done)
+	}
+
+	return errors.Join(errs...)
+}
+
+// runningDependent returns the name of a hook which depends on the hook and is still running
+// or was skipped, or an empty string.
+func runningDependent(order []shutdownHook, name string, finished map[string][]<-chan struct{}) string {
+	for _, hook := range order {
+		if !slices.Contains(hook.config.DependsOn, name) {
+			continue
+		}
+		for _, done := range finished[hook.name] {
+			if done == nil {
+				return hook.name
+			}
+			select {
+			case <-done:
+			default:
+				return hook.name
+			}
+		}
+	}
+	return ""
+}
+
+func (h *Hooks) executeOnWarmupHooks(ctx context.Context) error {
+	h.app.mutex.Lock()
+	hooks := make([]warmupHook, len(h.onWarmup))
+	copy(hooks, h.onWarmup)
+	h.app.mutex.Unlock()
+
+	for _, hook := range hooks {
+		err := runHook(ctx, h.app.config.Clock, hook.config.Timeout, hook.handler)
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("warmup hook %q: %w", hook.name, err)
+		if hook.config.Policy != WarmupDegrade {
+			return err
+		}
+
+		h.app.logw(log.LevelWarn, "warmup hook failed, the app is degraded", "hook", hook.name, "error", err)
+		h.app.warmup.mutex.Lock()
+		h.app.warmup.degraded = append(h.app.warmup.degraded, err)
+		h.app.warmup.mutex.Unlock()
+	}
+
+	return nil
+}
+
+// shutdownHookOrder sorts the hooks so that every hook is executed before the hooks it depends on.
+// Hooks with unknown dependencies or dependency cycles are still executed and reported as error.
+func shutdownHookOrder(hooks []shutdownHook) ([]shutdownHook, error) {
+	indexes := make(map[string][]int, len(hooks))
+	for i, hook := range hooks {
+		indexes[hook.name] = append(indexes[hook.name], i)
+	}
+
+	var errs []error
+	pending := make([]int, len(hooks)) // amount of hooks which have to be executed before
+	next := make([][]int, len(hooks))  // hooks which have to be executed after
+	for i, hook := range hooks {
+		for _, dep := range hook.config.DependsOn {
+			depIndexes, ok := indexes[dep]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%w: %q depends on %q", ErrShutdownHookUnknownDependency, hook.name, dep))
+				continue
+			}
+			for _, j := range depIndexes {
+				next[i] = append(next[i], j)
+				pending[j]++
+			}
+		}
+	}
+
+	order := make([]shutdownHook, 0, len(hooks))
+	done := make([]bool, len(hooks))
+	for len(order) < len(hooks) {
+		ready := -1
+		for i := range hooks {
+			if !done[i] && pending[i] == 0 {
+				ready = i
+				break
+			}
+		}
+
+		if ready == -1 {
+			// dependency cycle, execute the rest in registration order
+			for i := range hooks {
+				if !done[i] {
+					errs = append(errs, fmt.Errorf("%w: %q", ErrShutdownHookCycle, hooks[i].name))
+					order = append(order, hooks[i])
+					done[i] = true
+				}
+			}
+			break
+		}
+
+		done[ready] = true
+		order = append(order, hooks[ready])
+		for _, j := range next[ready] {
+			pending[j]--
+		}
+	}
+
+	return order, errors.Join(errs...)
+}
+
+// run executes the hook and stops waiting for it when the timeout is exceeded.
+// The returned channel is closed when the hook returned.
+func (hook shutdownHook) run(ctx context.Context, clock Clock) (<-chan struct{}, error) {
+	return startHook(ctx, clock, hook.config.Timeout, hook.handler)
+}
+
+// runHook executes the handler of a hook and stops waiting for it when the timeout of the clock
+// is exceeded or the context is done.
+func runHook(ctx context.Context, clock Clock, timeout time.Duration, handler func(ctx context.Context) error) error {
+	_, err := startHook(ctx, clock, timeout, handler)
+	return err
+}
+
+// startHook is runHook, the returned channel is closed when the handler returned, which
+// may be after startHook returned if the handler didn't finish in time.
+func startHook(
+	ctx context.Context, clock Clock, timeout time.Duration, handler func(ctx context.Context) error,
+) (<-chan struct{}, error) {
+	var cancel context.CancelFunc = func() {}
+	if timeout > 0 {
+		ctx, cancel = withTimeout(ctx, clock, timeout)
+	}
+
+	done := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		defer close(done)
+		// the timeout is canceled when the handler returned, even if nobody waits for it anymore
+		defer cancel()
+		result <- handler(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		<-done
+		return done, err
+	case <-ctx.Done():
+		return done, fmt.Errorf("failed to finish in time: %w", ctx.Err())
+	}
+}
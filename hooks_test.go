@@ -1,11 +1,17 @@
 package fiber
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gofiber/fiber/v3/internal/tlstest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/bytebufferpool"
@@ -199,6 +205,115 @@ func Test_Hook_OnShutdown(t *testing.T) {
 	require.Equal(t, "shutdowning", buf.String())
 }
 
+func Test_Hook_OnShutdownNamed(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var order []string
+	hook := func(name string) OnShutdownNamedHandler {
+		return func(context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	app.Hooks().OnShutdownNamed("db", hook("db"))
+	app.Hooks().OnShutdownNamed("cache", hook("cache"))
+	app.Hooks().OnShutdownNamed("hub", hook("hub"), ShutdownHookConfig{DependsOn: []string{"db", "cache"}})
+	app.Hooks().OnShutdownNamed("queue", hook("queue"), ShutdownHookConfig{DependsOn: []string{"hub"}})
+
+	require.NoError(t, app.Shutdown())
+	require.Equal(t, []string{"queue", "hub", "db", "cache"}, order)
+}
+
+func Test_Hook_OnShutdownNamed_Errors(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+	app.Hooks().OnShutdownNamed("db", func(context.Context) error {
+		record("db")
+		return errors.New("db failed")
+	}, ShutdownHookConfig{DependsOn: []string{"missing"}})
+	app.Hooks().OnShutdownNamed("slow", func(ctx context.Context) error {
+		record("slow")
+		<-ctx.Done()
+		return nil
+	}, ShutdownHookConfig{Timeout: 10 * time.Millisecond})
+	app.Hooks().OnShutdownNamed("cache", func(context.Context) error {
+		record("cache")
+		return nil
+	})
+
+	err := app.Shutdown()
+	require.ErrorIs(t, err, ErrShutdownHookUnknownDependency)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, `shutdown hook "db": db failed`)
+	require.ErrorContains(t, err, `shutdown hook "slow"`)
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"db", "slow", "cache"}, order)
+}
+
+func Test_Hook_OnShutdownNamed_Skipped(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	release := make(chan struct{})
+	var dbClosed, cacheClosed atomic.Bool
+	app.Hooks().OnShutdownNamed("db", func(context.Context) error {
+		dbClosed.Store(true)
+		return nil
+	})
+	app.Hooks().OnShutdownNamed("cache", func(context.Context) error {
+		cacheClosed.Store(true)
+		return nil
+	}, ShutdownHookConfig{DependsOn: []string{"db"}})
+	// hub ignores its timeout and still uses the cache and the db
+	app.Hooks().OnShutdownNamed("hub", func(context.Context) error {
+		<-release
+		return nil
+	}, ShutdownHookConfig{DependsOn: []string{"cache"}, Timeout: 10 * time.Millisecond})
+
+	err := app.Shutdown()
+	close(release)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, ErrShutdownHookSkipped)
+	require.ErrorContains(t, err, `shutdown hook "cache": shutdown: hook skipped, a hook which depends on it didn't finish: "hub"`)
+	require.ErrorContains(t, err, `shutdown hook "db": shutdown: hook skipped, a hook which depends on it didn't finish: "cache"`)
+	require.False(t, cacheClosed.Load())
+	require.False(t, dbClosed.Load())
+}
+
+func Test_Hook_OnShutdownNamed_Cycle(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var order []string
+	hook := func(name string) OnShutdownNamedHandler {
+		return func(context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	app.Hooks().OnShutdownNamed("a", hook("a"), ShutdownHookConfig{DependsOn: []string{"b"}})
+	app.Hooks().OnShutdownNamed("b", hook("b"), ShutdownHookConfig{DependsOn: []string{"a"}})
+	app.Hooks().OnShutdownNamed("c", hook("c"), ShutdownHookConfig{DependsOn: []string{"a"}})
+
+	err := app.Shutdown()
+	require.ErrorIs(t, err, ErrShutdownHookCycle)
+	require.Equal(t, []string{"c", "a", "b"}, order)
+}
+
 func Test_Hook_OnListen(t *testing.T) {
 	t.Parallel()
 
@@ -282,3 +397,171 @@ func Test_Hook_OnMount(t *testing.T) {
 
 	app.Use("/sub", subApp)
 }
+
+func Test_Hook_OnConn_OnTLSHandshake_OnALPN(t *testing.T) {
+	t.Parallel()
+
+	clientTLSConf := &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec // We're in a test so using old ciphers is fine
+		NextProtos:         []string{"h2", "http/1.1"},
+	}
+
+	app := New()
+
+	var (
+		mutex     sync.Mutex
+		events    []string
+		handshake TLSHandshake
+		selection ALPNSelection
+		reject    atomic.Bool
+	)
+	addEvent := func(event string) {
+		mutex.Lock()
+		events = append(events, event)
+		mutex.Unlock()
+	}
+
+	app.Hooks().OnConnOpen(func(conn ConnInfo) error {
+		if reject.Load() {
+			return errors.New("blocked")
+		}
+		require.NotEmpty(t, conn.RemoteAddr)
+		addEvent("open")
+		return nil
+	})
+	app.Hooks().OnConnClose(func(ConnInfo) error {
+		addEvent("close")
+		return nil
+	})
+	app.Hooks().OnTLSHandshake(func(h TLSHandshake) error {
+		mutex.Lock()
+		handshake = h
+		mutex.Unlock()
+		addEvent("handshake")
+		return nil
+	})
+	app.Hooks().OnALPN(func(s ALPNSelection) error {
+		mutex.Lock()
+		selection = s
+		mutex.Unlock()
+		addEvent("alpn")
+		return nil
+	})
+
+	addr := make(chan net.Addr, 1)
+	go func() {
+		assert.NoError(t, app.Listen("127.0.0.1:0", ListenConfig{
+			DisableStartupMessage: true,
+			ListenerNetwork:       NetworkTCP4,
+			CertFile:              "./.github/testdata/ssl.pem",
+			CertKeyFile:           "./.github/testdata/ssl.key",
+			TLSConfigFunc: func(tlsConfig *tls.Config) {
+				tlsConfig.NextProtos = []string{"http/1.1"}
+			},
+			ListenerAddrFunc: func(a net.Addr) {
+				addr <- a
+			},
+		}))
+	}()
+	defer func() {
+		require.NoError(t, app.Shutdown())
+	}()
+	ln := <-addr
+
+	conn, err := tls.Dial(NetworkTCP4, ln.String(), clientTLSConf)
+	require.NoError(t, err)
+	require.NoError(t, conn.Handshake())
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(events) == 4
+	}, time.Second, 10*time.Millisecond)
+
+	mutex.Lock()
+	require.Equal(t, []string{"open", "handshake", "alpn", "close"}, events)
+	require.Equal(t, uint16(tls.VersionTLS13), handshake.Version)
+	require.Equal(t, "http/1.1", handshake.NegotiatedProtocol)
+	require.Equal(t, conn.LocalAddr().String(), handshake.Conn.RemoteAddr)
+	require.Equal(t, "http/1.1", selection.Protocol)
+	require.Equal(t, []string{"h2", "http/1.1"}, selection.Offered)
+	mutex.Unlock()
+
+	// the connections are closed if an OnConnOpen hook returns an error
+	reject.Store(true)
+	_, err = tls.Dial(NetworkTCP4, ln.String(), clientTLSConf)
+	require.Error(t, err)
+}
+
+// go test -run Test_Hook_OnTLSHandshake_Listener
+func Test_Hook_OnTLSHandshake_Listener(t *testing.T) {
+	t.Parallel()
+
+	serverTLSConf, clientTLSConf, err := tlstest.GetTLSConfigs()
+	require.NoError(t, err)
+
+	ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+	require.NoError(t, err)
+	ln = tls.NewListener(ln, serverTLSConf)
+
+	app := New()
+	handshakes := make(chan TLSHandshake, 1)
+	app.Hooks().OnTLSHandshake(func(h TLSHandshake) error {
+		handshakes <- h
+		return nil
+	})
+
+	go func() {
+		assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+	}()
+	defer func() {
+		require.NoError(t, app.Shutdown())
+	}()
+
+	conn, err := tls.Dial(NetworkTCP4, ln.Addr().String(), clientTLSConf)
+	require.NoError(t, err)
+	require.NoError(t, conn.Handshake())
+	require.NoError(t, conn.Close())
+
+	select {
+	case h := <-handshakes:
+		require.Equal(t, conn.LocalAddr().String(), h.Conn.RemoteAddr)
+	case <-time.After(time.Second):
+		t.Fatal("the OnTLSHandshake hook wasn't executed")
+	}
+}
+
+func Test_Hook_OnTLSHandshake_Reject(t *testing.T) {
+	t.Parallel()
+
+	serverTLSConf, clientTLSConf, err := tlstest.GetTLSConfigs()
+	require.NoError(t, err)
+
+	app := New()
+	app.Hooks().OnTLSHandshake(func(TLSHandshake) error {
+		return errors.New("rejected")
+	})
+
+	ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+	require.NoError(t, err)
+	tlsLn := tls.NewListener(app.limitListener(ln, ListenConfig{}), app.hookTLSConfig(serverTLSConf))
+	defer func() {
+		require.NoError(t, tlsLn.Close())
+	}()
+
+	go func() {
+		conn, err := tlsLn.Accept()
+		if assert.NoError(t, err) {
+			assert.Error(t, conn.(*tls.Conn).Handshake()) //nolint:forcetypeassert,errcheck // It's a tls.Conn
+			assert.NoError(t, conn.Close())
+		}
+	}()
+
+	conn, err := tls.Dial(NetworkTCP4, ln.Addr().String(), clientTLSConf)
+	if err == nil {
+		// the client of TLS 1.3 completes the handshake before the server verified it
+		_, err = conn.Read(make([]byte, 1))
+	}
+	require.Error(t, err)
+}
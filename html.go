@@ -0,0 +1,67 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"reflect"
+)
+
+// TrustedHTML is an HTML fragment which is not escaped by c.HTML.
+// Only use it for HTML which doesn't contain user content, or which was sanitized.
+type TrustedHTML string
+
+// HTML sends a text/html response which is formatted like fmt.Sprintf.
+// The arguments are HTML-escaped, except TrustedHTML values, numbers and booleans, so user content
+// can be embedded into the format without a template engine.
+//
+//	c.HTML("<p>Hello, %s!</p>", name)
+func (c *DefaultCtx) HTML(format string, args ...any) error {
+	escaped := make([]any, len(args))
+	for i, arg := range args {
+		switch val := arg.(type) {
+		case TrustedHTML:
+			escaped[i] = string(val)
+		case string:
+			escaped[i] = html.EscapeString(val)
+		case []byte:
+			escaped[i] = html.EscapeString(string(val))
+		case fmt.Stringer:
+			escaped[i] = html.EscapeString(val.String())
+		case error:
+			escaped[i] = html.EscapeString(val.Error())
+		default:
+			// numbers and booleans are kept for the verbs like %d
+			if kind := reflect.ValueOf(val).Kind(); kind >= reflect.Bool && kind <= reflect.Complex128 {
+				escaped[i] = val
+			} else {
+				escaped[i] = html.EscapeString(fmt.Sprint(val))
+			}
+		}
+	}
+
+	c.fasthttp.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
+	return c.SendString(fmt.Sprintf(format, escaped...))
+}
+
+// jsonHTMLChars are the characters which are escaped by escapeJSONHTML.
+const jsonHTMLChars = "<>&\u2028\u2029"
+
+// escapeJSONHTML escapes <, >, & and the line terminators U+2028 and U+2029 in the
+// strings of the JSON, like encoding/json does, so the JSON can be embedded into HTML.
+// The characters can't be part of the JSON syntax, so the whole JSON is escaped.
+func escapeJSONHTML(raw []byte) []byte {
+	if !bytes.ContainsAny(raw, jsonHTMLChars) {
+		return raw
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(raw) + 16)
+	json.HTMLEscape(&buf, raw)
+	return buf.Bytes()
+}
@@ -0,0 +1,92 @@
+package fiber
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Ctx_HTML
+func Test_Ctx_HTML(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	err := c.HTML("<p>%s %s %v %d %t %s %s</p>%s",
+		`<script>alert("x")</script>`,
+		[]byte("a & b"),
+		errors.New("<err>"),
+		42,
+		true,
+		TrustedHTML("<b>bold</b>"),
+		Method("<GET>"),
+		TrustedHTML("<hr>"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, `<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; a &amp; b &lt;err&gt; 42 true <b>bold</b> &lt;GET&gt;</p><hr>`, string(c.Response().Body()))
+	require.Equal(t, MIMETextHTMLCharsetUTF8, string(c.Response().Header.ContentType()))
+}
+
+// Method is a fmt.Stringer of Test_Ctx_HTML.
+type Method string
+
+func (m Method) String() string {
+	return string(m)
+}
+
+// go test -run Test_Ctx_AutoFormat_EscapeHTML
+func Test_Ctx_AutoFormat_EscapeHTML(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().Header.Set(HeaderAccept, MIMETextHTML)
+	require.NoError(t, c.AutoFormat("<img src=x onerror=alert(1)>"))
+	require.Equal(t, "<p>&lt;img src=x onerror=alert(1)&gt;</p>", string(c.Response().Body()))
+}
+
+// go test -run Test_Ctx_JSON_EscapeHTML
+func Test_Ctx_JSON_EscapeHTML(t *testing.T) {
+	t.Parallel()
+
+	// an encoder which doesn't escape HTML, like many third party encoders
+	encoder := func(v any) ([]byte, error) {
+		s, ok := v.(string)
+		if !ok {
+			return json.Marshal(v)
+		}
+		return []byte(`"` + s + `"`), nil
+	}
+	data := "</script><b>a & b</b>\u2028"
+
+	app := New(Config{JSONEncoder: encoder})
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.NoError(t, c.JSON(data))
+	require.Equal(t, `"`+data+`"`, string(c.Response().Body()))
+	app.ReleaseCtx(c)
+
+	app = New(Config{JSONEncoder: encoder, JSONEscapeHTML: true})
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+	want := `"\u003c/script\u003e\u003cb\u003ea \u0026 b\u003c/b\u003e\u2028"`
+	require.NoError(t, c.JSON(data))
+	require.Equal(t, want, string(c.Response().Body()))
+	require.NoError(t, c.JSONP(data, "cb"))
+	require.Equal(t, "cb("+want+");", string(c.Response().Body()))
+
+	// the JSON without HTML characters is not copied
+	raw := []byte(`{"a":"b"}`)
+	require.Equal(t, &raw[0], &escapeJSONHTML(raw)[0])
+
+	// the default encoder escapes HTML
+	app = New()
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.NoError(t, c.JSON(data))
+	require.Equal(t, want, string(c.Response().Body()))
+	app.ReleaseCtx(c)
+}
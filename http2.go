@@ -0,0 +1,287 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/utils/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// http2Proto is the ALPN protocol name of HTTP/2
+const http2Proto = "h2"
+
+// http2HandshakeTimeout limits the TLS handshake which is needed to choose the protocol
+const http2HandshakeTimeout = 10 * time.Second
+
+// pusherContextKey is the key of the http.Pusher of HTTP/2 requests in *fasthttp.RequestCtx
+const pusherContextKey contextKey = 1
+
+// HTTP2Config is a struct to configure the HTTP/2 server.
+//
+// The settings are applied if the application is built with Go 1.24 or newer,
+// older versions use the defaults of net/http.
+type HTTP2Config struct {
+	// MaxConcurrentStreams is the number of concurrent streams
+	// that a client may have open at a time.
+	//
+	// Default: 0 (at least 100)
+	MaxConcurrentStreams int `json:"max_concurrent_streams"`
+
+	// MaxReadFrameSize is the largest frame the server is willing to read.
+	// A valid value is between 16KiB and 16MiB.
+	//
+	// Default: 0 (1MiB)
+	MaxReadFrameSize int `json:"max_read_frame_size"`
+
+	// MaxReceiveBufferPerConnection is the size of the flow control window
+	// for data received on a connection.
+	// A valid value is at least 64KiB and less than 4MiB.
+	//
+	// Default: 0 (1MiB)
+	MaxReceiveBufferPerConnection int `json:"max_receive_buffer_per_connection"`
+
+	// MaxReceiveBufferPerStream is the size of the flow control window
+	// for data received on a stream.
+	// A valid value is less than 4MiB.
+	//
+	// Default: 0 (1MiB)
+	MaxReceiveBufferPerStream int `json:"max_receive_buffer_per_stream"`
+}
+
+// startHTTP2 serves the HTTP/2 connections of the listener with net/http,
+// the requests are converted to fasthttp requests.
+func (app *App) startHTTP2(ln net.Listener, cfg ListenConfig) {
+	srv := &http.Server{
+		Handler:        http.HandlerFunc(app.serveHTTP),
+		ReadTimeout:    app.config.ReadTimeout,
+		WriteTimeout:   app.config.WriteTimeout,
+		IdleTimeout:    app.config.IdleTimeout,
+		MaxHeaderBytes: app.config.ReadBufferSize,
+	}
+	cfg.HTTP2.apply(srv)
+
+	app.mutex.Lock()
+	app.http2Server = srv
+	app.mutex.Unlock()
+
+	go func() {
+		_ = srv.Serve(ln) //nolint:errcheck // the error is returned by the fasthttp server
+	}()
+}
+
+// dispatchConns accepts the connections of the listener and passes them to the listener
+// of the protocol which was negotiated with ALPN, or to the fallback listener.
+func dispatchConns(ln net.Listener, fallback *connListener, protocols map[string]*connListener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			fallback.Close() //nolint:errcheck // Always returns nil
+			for _, l := range protocols {
+				l.Close() //nolint:errcheck // Always returns nil
+			}
+			return
+		}
+
+		go func() {
+			tlsConn, ok := conn.(*tls.Conn)
+			if !ok {
+				fallback.deliver(conn)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), http2HandshakeTimeout)
+			defer cancel()
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				_ = conn.Close() //nolint:errcheck // It is fine to ignore the error here
+				return
+			}
+
+			if l, ok := protocols[tlsConn.ConnectionState().NegotiatedProtocol]; ok {
+				l.deliver(conn)
+			} else {
+				fallback.deliver(conn)
+			}
+		}()
+	}
+}
+
+// serveHTTP converts the net/http request to a fasthttp request, serves it
+// with the handler of the server and writes the response back.
+func (app *App) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var fctx fasthttp.RequestCtx
+	fctx.Init2(newHTTP2Conn(r), nil, false)
+
+	// Convert net/http -> fasthttp request
+	req := &fctx.Request
+	req.Header.SetMethod(r.Method)
+	req.Header.SetProtocol(r.Proto)
+	req.SetRequestURI(r.RequestURI)
+	req.Header.SetHost(r.Host)
+	for key, values := range r.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if r.Body != nil {
+		n, err := io.Copy(req.BodyWriter(), io.LimitReader(r.Body, int64(app.config.BodyLimit)+1))
+		if err != nil {
+			http.Error(w, utils.StatusMessage(StatusBadRequest), StatusBadRequest)
+			return
+		}
+		if n > int64(app.config.BodyLimit) {
+			http.Error(w, utils.StatusMessage(StatusRequestEntityTooLarge), StatusRequestEntityTooLarge)
+			return
+		}
+		req.Header.SetContentLength(int(n))
+	}
+	if pusher, ok := w.(http.Pusher); ok {
+		fctx.SetUserValue(pusherContextKey, pusher)
+	}
+
+	app.server.Handler(&fctx)
+
+	// Convert fasthttp -> net/http response, connection specific headers are not allowed in HTTP/2
+	fctx.Response.Header.VisitAll(func(key, value []byte) {
+		switch k := string(key); k {
+		case HeaderConnection, HeaderTransferEncoding, HeaderKeepAlive, HeaderUpgrade:
+		default:
+			w.Header().Add(k, string(value))
+		}
+	})
+	w.WriteHeader(fctx.Response.StatusCode())
+	var err error
+	if r.Method != MethodHead {
+		err = fctx.Response.BodyWriteTo(w)
+	}
+	switch {
+	case !app.config.EnableCommitHooks:
+		// the responses aren't tracked
+	case err != nil:
+		app.commits.discard(fctx.Conn())
+	default:
+		app.commits.written(fctx.Conn(), true)
+	}
+	fctx.Response.Reset()
+}
+
+// Push initiates an HTTP/2 server push of the target, e.g. a stylesheet which is needed
+// to render the response. ErrPushNotSupported is returned, if the request wasn't made
+// with HTTP/2 or the client disabled server push.
+func (c *DefaultCtx) Push(target string) error {
+	pusher, ok := c.fasthttp.UserValue(pusherContextKey).(http.Pusher)
+	if !ok {
+		return ErrPushNotSupported
+	}
+
+	if err := pusher.Push(target, nil); err != nil {
+		if errors.Is(err, http.ErrNotSupported) {
+			return ErrPushNotSupported
+		}
+		return err //nolint:wrapcheck // This must not be wrapped
+	}
+
+	return nil
+}
+
+// http2Conn provides the addresses and the TLS state of HTTP/2 requests to the fasthttp.RequestCtx.
+type http2Conn struct {
+	net.Conn
+	localAddr  net.Addr
+	remoteAddr net.Addr
+	state      tls.ConnectionState
+}
+
+func newHTTP2Conn(r *http.Request) *http2Conn {
+	conn := &http2Conn{
+		localAddr:  &net.TCPAddr{},
+		remoteAddr: &net.TCPAddr{},
+	}
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		conn.localAddr = addr
+	}
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		conn.remoteAddr = addr
+	}
+	if r.TLS != nil {
+		conn.state = *r.TLS
+	}
+
+	return conn
+}
+
+func (c *http2Conn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+func (c *http2Conn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func (*http2Conn) Handshake() error {
+	return nil
+}
+
+func (c *http2Conn) ConnectionState() tls.ConnectionState {
+	return c.state
+}
+
+// connListener is a net.Listener which serves the connections passed to deliver.
+type connListener struct {
+	parent net.Listener
+	conns  chan net.Conn
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newConnListener(parent net.Listener) *connListener {
+	return &connListener{
+		parent: parent,
+		conns:  make(chan net.Conn),
+		done:   make(chan struct{}),
+	}
+}
+
+func (l *connListener) deliver(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		_ = conn.Close() //nolint:errcheck // It is fine to ignore the error here
+	}
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the listener and the parent listener
+func (l *connListener) Close() error {
+	l.once.Do(func() {
+		close(l.done)
+		_ = l.parent.Close() //nolint:errcheck // the parent is closed by both listeners
+	})
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return l.parent.Addr()
+}
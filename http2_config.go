@@ -0,0 +1,17 @@
+//go:build go1.24
+
+package fiber
+
+import (
+	"net/http"
+)
+
+// apply sets the HTTP/2 settings of the server
+func (cfg HTTP2Config) apply(srv *http.Server) {
+	srv.HTTP2 = &http.HTTP2Config{
+		MaxConcurrentStreams:          cfg.MaxConcurrentStreams,
+		MaxReadFrameSize:              cfg.MaxReadFrameSize,
+		MaxReceiveBufferPerConnection: cfg.MaxReceiveBufferPerConnection,
+		MaxReceiveBufferPerStream:     cfg.MaxReceiveBufferPerStream,
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !go1.24
+
+package fiber
+
+import (
+	"net/http"
+)
+
+// apply is a no-op, the HTTP/2 settings of net/http are only configurable since Go 1.24
+func (HTTP2Config) apply(*http.Server) {}
@@ -0,0 +1,112 @@
+package fiber
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+func newHTTP2TestClient(ln *fasthttputil.InmemoryListener, protos ...string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			ForceAttemptHTTP2: true,
+			DialTLSContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				conn, err := ln.Dial()
+				if err != nil {
+					return nil, err
+				}
+				tlsConn := tls.Client(conn, &tls.Config{
+					InsecureSkipVerify: true, //nolint:gosec // self signed test certificate
+					NextProtos:         protos,
+				})
+				return tlsConn, tlsConn.HandshakeContext(ctx)
+			},
+		},
+	}
+}
+
+// go test -run Test_App_Listener_HTTP2
+func Test_App_Listener_HTTP2(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Post("/", func(c Ctx) error {
+		require.ErrorIs(t, c.Push("/style.css"), ErrPushNotSupported)
+		c.Set("X-Proto", c.Protocol())
+		c.Set("X-Scheme", c.Scheme())
+		return c.SendString(c.Get(HeaderUserAgent) + ":" + string(c.Body()))
+	})
+
+	cert, err := tls.LoadX509KeyPair("./.github/testdata/ssl.pem", "./.github/testdata/ssl.key")
+	require.NoError(t, err)
+
+	ln := fasthttputil.NewInmemoryListener()
+	tlsLn := tls.NewListener(ln, &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{http2Proto, "http/1.1"},
+	})
+	go func() {
+		assert.NoError(t, app.Listener(tlsLn, ListenConfig{
+			DisableStartupMessage: true,
+			EnableHTTP2:           true,
+			HTTP2:                 HTTP2Config{MaxConcurrentStreams: 10},
+		}))
+	}()
+
+	testCases := []struct {
+		proto      string
+		protos     []string
+		protoMajor int
+	}{
+		{proto: "HTTP/2.0", protos: []string{http2Proto, "http/1.1"}, protoMajor: 2},
+		{proto: "HTTP/1.1", protos: []string{"http/1.1"}, protoMajor: 1},
+	}
+
+	for _, tc := range testCases {
+		client := newHTTP2TestClient(ln, tc.protos...)
+
+		var resp *http.Response
+		require.Eventually(t, func() bool {
+			req, err := http.NewRequestWithContext(context.Background(), MethodPost, "https://example.com/", strings.NewReader("body"))
+			require.NoError(t, err)
+			req.Header.Set(HeaderUserAgent, "fiber")
+			resp, err = client.Do(req)
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		require.Equal(t, StatusOK, resp.StatusCode)
+		require.Equal(t, tc.protoMajor, resp.ProtoMajor)
+		require.Equal(t, tc.proto, resp.Header.Get("X-Proto"))
+		require.Equal(t, "https", resp.Header.Get("X-Scheme"))
+		require.Equal(t, "fiber:body", string(body))
+	}
+
+	require.NoError(t, app.Shutdown())
+}
+
+// go test -run Test_Ctx_Push
+func Test_Ctx_Push(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.Push("/style.css")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+}
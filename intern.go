@@ -0,0 +1,98 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// maxInternLength is the maximum length of the strings which are interned
+const maxInternLength = 128
+
+// InternStats contains the counters of the string intern cache.
+type InternStats struct {
+	// Hits is the number of strings which were found in the cache.
+	Hits uint64 `json:"hits"`
+	// Misses is the number of strings which weren't found in the cache.
+	Misses uint64 `json:"misses"`
+	// Size is the number of strings in the cache.
+	Size int `json:"size"`
+}
+
+// interner is a bounded cache of immutable strings. When it is full, new strings
+// aren't added anymore, so the strings which were seen first stay in the cache.
+type interner struct {
+	mutex   sync.RWMutex
+	strings map[string]string
+	max     int
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+func newInterner(size int) *interner {
+	return &interner{
+		strings: make(map[string]string, size),
+		max:     size,
+	}
+}
+
+// intern returns the cached string for b. If it isn't cached yet, a copy is added
+// to the cache. It returns false if b is too long or the cache is full.
+func (in *interner) intern(b []byte) (string, bool) {
+	if len(b) > maxInternLength {
+		return "", false
+	}
+
+	in.mutex.RLock()
+	s, ok := in.strings[string(b)] // no allocation for the lookup
+	in.mutex.RUnlock()
+	if ok {
+		in.hits.Add(1)
+		return s, true
+	}
+	in.misses.Add(1)
+
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+	if s, ok = in.strings[string(b)]; ok {
+		return s, true
+	}
+	if len(in.strings) >= in.max {
+		return "", false
+	}
+	s = string(b)
+	in.strings[s] = s
+	return s, true
+}
+
+// InternStats returns the counters of the string intern cache.
+func (app *App) InternStats() InternStats {
+	if app.interner == nil {
+		return InternStats{}
+	}
+
+	app.interner.mutex.RLock()
+	size := len(app.interner.strings)
+	app.interner.mutex.RUnlock()
+
+	return InternStats{
+		Hits:   app.interner.hits.Load(),
+		Misses: app.interner.misses.Load(),
+		Size:   size,
+	}
+}
+
+// internString converts the bytes of a header into a string. With the InternCacheSize
+// setting, frequently seen headers are returned from the intern cache, which doesn't
+// allocate and returns strings which are valid after the handler returned.
+func (c *DefaultCtx) internString(b []byte) string {
+	if c.app.interner != nil {
+		if s, ok := c.app.interner.intern(b); ok {
+			return s
+		}
+	}
+	return c.getString(b)
+}
@@ -0,0 +1,58 @@
+package fiber
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Interner
+func Test_Interner(t *testing.T) {
+	t.Parallel()
+	in := newInterner(2)
+
+	b := []byte("application/json")
+	s, ok := in.intern(b)
+	require.True(t, ok)
+	b[0] = 'A'
+	require.Equal(t, "application/json", s)
+
+	s, ok = in.intern([]byte("application/json"))
+	require.True(t, ok)
+	require.Equal(t, "application/json", s)
+
+	_, ok = in.intern([]byte("text/html"))
+	require.True(t, ok)
+	// the cache is full
+	_, ok = in.intern([]byte("text/plain"))
+	require.False(t, ok)
+	// the string is too long
+	_, ok = in.intern([]byte(strings.Repeat("a", maxInternLength+1)))
+	require.False(t, ok)
+
+	require.Equal(t, uint64(1), in.hits.Load())
+	require.Equal(t, uint64(3), in.misses.Load())
+}
+
+// go test -run Test_Ctx_InternCacheSize
+func Test_Ctx_InternCacheSize(t *testing.T) {
+	t.Parallel()
+	app := New(Config{InternCacheSize: 100})
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().Header.Set(HeaderAccept, MIMEApplicationJSON)
+	accept := c.Get(HeaderAccept)
+	c.Request().Header.Set(HeaderAccept, MIMETextHTML)
+	require.Equal(t, MIMEApplicationJSON, accept)
+	require.Equal(t, MIMETextHTML, c.Get(HeaderAccept))
+	require.Equal(t, MIMETextHTML, c.GetReqHeaders()[HeaderAccept][0])
+
+	stats := app.InternStats()
+	require.Equal(t, uint64(1), stats.Hits)
+	require.Equal(t, 3, stats.Size)
+
+	require.Equal(t, InternStats{}, New().InternStats())
+}
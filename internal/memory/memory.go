@@ -11,7 +11,13 @@ import (
 
 type Storage struct {
 	sync.RWMutex
-	data map[string]item // data
+	data  map[string]item // data
+	clock Clock           // nil for the cached timestamp of the system time
+}
+
+// Clock is the source of the time, it's implemented by fiber.Clock
+type Clock interface {
+	Now() time.Time
 }
 
 type item struct {
@@ -20,21 +26,34 @@ type item struct {
 	v any    // val
 }
 
-func New() *Storage {
+// New creates a new storage whose expirations are measured with the clock,
+// nil uses the system time.
+func New(clock Clock) *Storage {
 	store := &Storage{
-		data: make(map[string]item),
+		data:  make(map[string]item),
+		clock: clock,
+	}
+	if clock == nil {
+		utils.StartTimeStampUpdater()
 	}
-	utils.StartTimeStampUpdater()
 	go store.gc(1 * time.Second)
 	return store
 }
 
+// timestamp returns the current unix time of the clock.
+func (s *Storage) timestamp() uint32 {
+	if s.clock == nil {
+		return utils.Timestamp()
+	}
+	return uint32(s.clock.Now().Unix())
+}
+
 // Get value by key
 func (s *Storage) Get(key string) any {
 	s.RLock()
 	v, ok := s.data[key]
 	s.RUnlock()
-	if !ok || v.e != 0 && v.e <= utils.Timestamp() {
+	if !ok || v.e != 0 && v.e <= s.timestamp() {
 		return nil
 	}
 	return v.v
@@ -44,7 +63,7 @@ func (s *Storage) Get(key string) any {
 func (s *Storage) Set(key string, val any, ttl time.Duration) {
 	var exp uint32
 	if ttl > 0 {
-		exp = uint32(ttl.Seconds()) + utils.Timestamp()
+		exp = uint32(ttl.Seconds()) + s.timestamp()
 	}
 	i := item{exp, val}
 	s.Lock()
@@ -73,7 +92,7 @@ func (s *Storage) gc(sleep time.Duration) {
 	var expired []string
 
 	for range ticker.C {
-		ts := utils.Timestamp()
+		ts := s.timestamp()
 		expired = expired[:0]
 		s.RLock()
 		for key, v := range s.data {
@@ -11,7 +11,7 @@ import (
 // go test -run Test_Memory -v -race
 func Test_Memory(t *testing.T) {
 	t.Parallel()
-	store := New()
+	store := New(nil)
 	var (
 		key     = "john-internal"
 		val any = []byte("doe")
@@ -66,7 +66,7 @@ func Benchmark_Memory(b *testing.B) {
 
 	ttl := 2 * time.Second
 	b.Run("fiber_memory", func(b *testing.B) {
-		d := New()
+		d := New(nil)
 		b.ReportAllocs()
 		b.ResetTimer()
 		for n := 0; n < b.N; n++ {
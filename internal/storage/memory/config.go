@@ -10,6 +10,16 @@ type Config struct {
 	//
 	// Default is 10 * time.Second
 	GCInterval time.Duration
+
+	// Clock measures the expirations, e.g. a fiber.ManualClock in tests
+	//
+	// Default is nil, the system time
+	Clock Clock
+}
+
+// Clock is the source of the time, it's implemented by fiber.Clock
+type Clock interface {
+	Now() time.Time
 }
 
 // ConfigDefault is the default config
@@ -15,6 +15,7 @@ type Storage struct {
 	db         map[string]entry
 	gcInterval time.Duration
 	done       chan struct{}
+	clock      Clock // nil for the cached timestamp of the system time
 }
 
 type entry struct {
@@ -33,15 +34,26 @@ func New(config ...Config) *Storage {
 		db:         make(map[string]entry),
 		gcInterval: cfg.GCInterval,
 		done:       make(chan struct{}),
+		clock:      cfg.Clock,
 	}
 
 	// Start garbage collector
-	utils.StartTimeStampUpdater()
+	if cfg.Clock == nil {
+		utils.StartTimeStampUpdater()
+	}
 	go store.gc()
 
 	return store
 }
 
+// timestamp returns the current unix time of the clock.
+func (s *Storage) timestamp() uint32 {
+	if s.clock == nil {
+		return utils.Timestamp()
+	}
+	return uint32(s.clock.Now().Unix())
+}
+
 // Get value by key
 func (s *Storage) Get(key string) ([]byte, error) {
 	if len(key) == 0 {
@@ -50,7 +62,7 @@ func (s *Storage) Get(key string) ([]byte, error) {
 	s.mux.RLock()
 	v, ok := s.db[key]
 	s.mux.RUnlock()
-	if !ok || v.expiry != 0 && v.expiry <= utils.Timestamp() {
+	if !ok || v.expiry != 0 && v.expiry <= s.timestamp() {
 		return nil, nil
 	}
 
@@ -66,7 +78,7 @@ func (s *Storage) Set(key string, val []byte, exp time.Duration) error {
 
 	var expire uint32
 	if exp != 0 {
-		expire = uint32(exp.Seconds()) + utils.Timestamp()
+		expire = uint32(exp.Seconds()) + s.timestamp()
 	}
 
 	e := entry{val, expire}
@@ -113,7 +125,7 @@ func (s *Storage) gc() {
 		case <-s.done:
 			return
 		case <-ticker.C:
-			ts := utils.Timestamp()
+			ts := s.timestamp()
 			expired = expired[:0]
 			s.mux.RLock()
 			for id, v := range s.db {
@@ -152,7 +164,7 @@ func (s *Storage) Keys() ([][]byte, error) {
 		return nil, nil
 	}
 
-	ts := utils.Timestamp()
+	ts := s.timestamp()
 	keys := make([][]byte, 0, len(s.db))
 	for key, v := range s.db {
 		// Filter out the expired keys
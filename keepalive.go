@@ -0,0 +1,78 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net"
+	"time"
+)
+
+// KeepAliveConfig is a struct to configure the lifecycle of keep-alive connections.
+type KeepAliveConfig struct {
+	// TCPKeepalivePeriod is the period of the TCP keep-alive probes of new connections.
+	// A negative value disables TCP keep-alive.
+	//
+	// Default: 0 (the default of the net package)
+	TCPKeepalivePeriod time.Duration `json:"tcp_keepalive_period"`
+
+	// MaxRequestsPerConn is the maximum number of requests per connection.
+	// "Connection: close" is sent with the last response.
+	//
+	// Default: 0 (unlimited)
+	MaxRequestsPerConn int `json:"max_requests_per_conn"`
+
+	// MaxConnAge is the maximum age of a connection. "Connection: close" is sent
+	// with the first response after the age was reached, so clients reconnect
+	// and the connections are rebalanced behind L4 load balancers.
+	//
+	// Default: 0 (unlimited)
+	MaxConnAge time.Duration `json:"max_conn_age"`
+
+	// CloseConnection is called after each request. If it returns true,
+	// "Connection: close" is sent with the response, e.g. while draining an instance.
+	//
+	// Default: nil
+	CloseConnection func(c Ctx) bool `json:"-"`
+}
+
+// SetKeepAlive changes the keep-alive config at runtime.
+// It is used for all following requests and new connections.
+func (app *App) SetKeepAlive(config KeepAliveConfig) {
+	app.keepAlive.Store(&config)
+	app.keepAlivePolicy.Store(config.MaxRequestsPerConn > 0 || config.MaxConnAge > 0 || config.CloseConnection != nil)
+}
+
+// KeepAlive returns the current keep-alive config.
+func (app *App) KeepAlive() KeepAliveConfig {
+	return *app.keepAlive.Load()
+}
+
+// closeConnectionIfNeeded adds "Connection: close" to the response if the keep-alive config requires it.
+func (app *App) closeConnectionIfNeeded(c CustomCtx) {
+	cfg := app.keepAlive.Load()
+	fctx := c.Context()
+
+	if (cfg.MaxRequestsPerConn > 0 && fctx.ConnRequestNum() >= uint64(cfg.MaxRequestsPerConn)) ||
+		(cfg.MaxConnAge > 0 && app.config.Clock.Now().Sub(fctx.ConnTime()) >= cfg.MaxConnAge) ||
+		(cfg.CloseConnection != nil && cfg.CloseConnection(c)) {
+		fctx.SetConnectionClose()
+	}
+}
+
+// setTCPKeepalive applies the TCP keep-alive period to a new connection.
+func (app *App) setTCPKeepalive(conn net.Conn) {
+	period := app.keepAlive.Load().TCPKeepalivePeriod
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok || period == 0 {
+		return
+	}
+
+	if period < 0 {
+		_ = tcpConn.SetKeepAlive(false) //nolint:errcheck // It is fine to ignore the error here
+		return
+	}
+	_ = tcpConn.SetKeepAlive(true)         //nolint:errcheck // It is fine to ignore the error here
+	_ = tcpConn.SetKeepAlivePeriod(period) //nolint:errcheck // It is fine to ignore the error here
+}
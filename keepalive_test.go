@@ -0,0 +1,77 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testConnectionClose(t *testing.T, app *App, path string) bool {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+	require.NoError(t, err)
+
+	return resp.Close
+}
+
+// go test -run Test_App_KeepAlive
+func Test_App_KeepAlive(t *testing.T) {
+	t.Parallel()
+	app := New(Config{
+		KeepAlive: KeepAliveConfig{
+			CloseConnection: func(c Ctx) bool {
+				return c.Response().StatusCode() >= StatusInternalServerError
+			},
+		},
+	})
+	app.Get("/", testSimpleHandler)
+	app.Get("/error", func(Ctx) error {
+		return ErrBadGateway
+	})
+
+	require.False(t, testConnectionClose(t, app, "/"))
+	require.True(t, testConnectionClose(t, app, "/error"))
+}
+
+// go test -run Test_App_KeepAlive_MaxConnAge_Clock
+func Test_App_KeepAlive_MaxConnAge_Clock(t *testing.T) {
+	t.Parallel()
+	clock := NewManualClock(time.Now())
+	app := New(Config{Clock: clock, KeepAlive: KeepAliveConfig{MaxConnAge: time.Hour}})
+	app.Get("/", testSimpleHandler)
+
+	require.False(t, testConnectionClose(t, app, "/"))
+
+	// the age of the connections is measured by the clock of the app
+	clock.Advance(2 * time.Hour)
+	require.True(t, testConnectionClose(t, app, "/"))
+}
+
+// go test -run Test_App_SetKeepAlive
+func Test_App_SetKeepAlive(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", testSimpleHandler)
+
+	require.False(t, testConnectionClose(t, app, "/"))
+
+	app.SetKeepAlive(KeepAliveConfig{MaxRequestsPerConn: 1})
+	require.Equal(t, 1, app.KeepAlive().MaxRequestsPerConn)
+	require.Equal(t, 1, app.Config().KeepAlive.MaxRequestsPerConn)
+	require.True(t, testConnectionClose(t, app, "/"))
+
+	app.SetKeepAlive(KeepAliveConfig{MaxConnAge: time.Nanosecond})
+	require.True(t, testConnectionClose(t, app, "/"))
+
+	app.SetKeepAlive(KeepAliveConfig{MaxConnAge: time.Hour, MaxRequestsPerConn: 2})
+	require.False(t, testConnectionClose(t, app, "/"))
+	require.True(t, app.keepAlivePolicy.Load())
+
+	// the policies aren't checked without a policy
+	app.SetKeepAlive(KeepAliveConfig{TCPKeepalivePeriod: time.Minute})
+	require.False(t, app.keepAlivePolicy.Load())
+	require.False(t, testConnectionClose(t, app, "/"))
+}
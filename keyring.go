@@ -0,0 +1,296 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// DefaultMaxRetiredKeys is the default number of retired keys of a Keyring.
+const DefaultMaxRetiredKeys = 2
+
+// Key is a versioned key of a Keyring.
+type Key struct {
+	// ID identifies the version of the key in signatures and encrypted values.
+	// It must not contain '.' or ':'.
+	ID string
+	// Value is the secret key.
+	Value []byte
+	// RetiredAt is the time when the key was replaced by a new active key.
+	RetiredAt time.Time
+}
+
+// GenerateKey creates a key with 32 random bytes, its ID is the current time
+// and a random suffix. It panics if the random generator fails.
+func GenerateKey() Key {
+	return generateKey(time.Now())
+}
+
+// generateKey creates a key with 32 random bytes, its ID is the time and a random suffix.
+func generateKey(now time.Time) Key {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		panic(err)
+	}
+	return Key{
+		ID:    now.UTC().Format("20060102T150405") + "-" + hex.EncodeToString(value[:2]),
+		Value: value,
+	}
+}
+
+// KeyringConfig defines the config of a Keyring.
+type KeyringConfig struct {
+	// Keys are the keys of the keyring, the first key is the active key,
+	// the other keys are retired keys which are only used for verification.
+	//
+	// Required.
+	Keys []Key
+
+	// MaxRetiredKeys is the number of retired keys which are kept for verification,
+	// the oldest keys are removed when the keys are rotated.
+	//
+	// Optional. Default: DefaultMaxRetiredKeys
+	MaxRetiredKeys int
+
+	// RetiredKeyTTL is the duration after which a retired key is removed.
+	//
+	// Optional. Default: 0 (kept until MaxRetiredKeys is exceeded)
+	RetiredKeyTTL time.Duration
+
+	// Clock is the source of the time of the IDs of the generated keys, the retirement
+	// of the keys and the interval of RotateEvery, e.g. a ManualClock in tests.
+	//
+	// Optional. Default: SystemClock()
+	Clock Clock
+}
+
+// Keyring contains an active key which signs and encrypts values and retired keys
+// which still verify and decrypt the values of the previous keys. It is shared by
+// the signing features like the session and CSRF middlewares, so the keys can be
+// rotated without invalidating all sessions and tokens at once.
+type Keyring struct {
+	mutex      sync.RWMutex
+	active     Key
+	retired    []Key // newest first
+	maxRetired int
+	ttl        time.Duration
+	clock      Clock
+	onRotate   []func(active, retired Key)
+}
+
+// NewKeyring creates a keyring. It panics if the config has no keys or an invalid key ID.
+//
+//	keyring := fiber.NewKeyring(fiber.KeyringConfig{
+//	    Keys: []fiber.Key{{ID: "v2", Value: keyV2}, {ID: "v1", Value: keyV1}},
+//	})
+func NewKeyring(config KeyringConfig) *Keyring {
+	if len(config.Keys) == 0 {
+		panic("keyring: at least one key is required")
+	}
+	for _, key := range config.Keys {
+		mustValidKey(key)
+	}
+
+	k := &Keyring{
+		active:     config.Keys[0],
+		retired:    append([]Key(nil), config.Keys[1:]...),
+		maxRetired: config.MaxRetiredKeys,
+		ttl:        config.RetiredKeyTTL,
+		clock:      config.Clock,
+	}
+	if k.maxRetired <= 0 {
+		k.maxRetired = DefaultMaxRetiredKeys
+	}
+	if k.clock == nil {
+		k.clock = SystemClock()
+	}
+	return k
+}
+
+// mustValidKey panics if the key can't be used in a keyring.
+func mustValidKey(key Key) {
+	if key.ID == "" || strings.ContainsAny(key.ID, ".:") {
+		panic("keyring: the key ID must not be empty or contain '.' or ':': " + key.ID)
+	}
+	if len(key.Value) == 0 {
+		panic("keyring: the value of key " + key.ID + " is empty")
+	}
+}
+
+// Active returns the active key.
+func (k *Keyring) Active() Key {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	return k.active
+}
+
+// Keys returns the active key and the retired keys which weren't removed, the active key first.
+func (k *Keyring) Keys() []Key {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	keys := []Key{k.active}
+	for _, key := range k.retired {
+		if !k.expired(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Lookup returns the active or retired key with the ID.
+func (k *Keyring) Lookup(id string) (Key, bool) {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	if k.active.ID == id {
+		return k.active, true
+	}
+	for _, key := range k.retired {
+		if key.ID == id && !k.expired(key) {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// expired reports if the retired key exceeded the RetiredKeyTTL.
+func (k *Keyring) expired(key Key) bool {
+	return k.ttl > 0 && !key.RetiredAt.IsZero() && k.clock.Now().Sub(key.RetiredAt) > k.ttl
+}
+
+// GenerateKey creates a key like GenerateKey, its ID is the current time of the Clock.
+func (k *Keyring) GenerateKey() Key {
+	return generateKey(k.clock.Now())
+}
+
+// Rotate makes the key the active key, the previous active key is retired.
+// The OnRotate callbacks are called after the rotation. It panics if the key is invalid.
+func (k *Keyring) Rotate(key Key) {
+	mustValidKey(key)
+
+	k.mutex.Lock()
+	retired := k.active
+	retired.RetiredAt = k.clock.Now()
+	k.active = key
+	k.retired = append([]Key{retired}, k.retired...)
+	if len(k.retired) > k.maxRetired {
+		k.retired = k.retired[:k.maxRetired]
+	}
+	callbacks := k.onRotate
+	k.mutex.Unlock()
+
+	for _, fn := range callbacks {
+		fn(key, retired)
+	}
+}
+
+// OnRotate registers a callback which is called with the new active key and the
+// retired key after each rotation, e.g. to store the keys or to re-sign values.
+func (k *Keyring) OnRotate(fn func(active, retired Key)) {
+	k.mutex.Lock()
+	k.onRotate = append(k.onRotate, fn)
+	k.mutex.Unlock()
+}
+
+// RotateEvery rotates the keys in the interval with the keys of generate until the
+// context is done. It blocks, so it is usually started in a goroutine. Errors of
+// generate are logged and the active key is kept until the next interval.
+//
+//	go keyring.RotateEvery(ctx, 24*time.Hour, func() (fiber.Key, error) {
+//	    return keyring.GenerateKey(), nil
+//	})
+func (k *Keyring) RotateEvery(ctx context.Context, interval time.Duration, generate func() (Key, error)) {
+	for {
+		timer := k.clock.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C():
+			key, err := generate()
+			if err != nil {
+				log.Warnw("keyring: failed to generate key, the active key is kept", "error", err)
+				continue
+			}
+			k.Rotate(key)
+		}
+	}
+}
+
+// Sign returns the signature of the data for the purpose with the active key, in the format
+// "<key ID>:<base64url HMAC-SHA256>". The purpose, e.g. "session", is mixed into the HMAC,
+// so a value signed for one use can't be replayed as another.
+func (k *Keyring) Sign(purpose string, data []byte) string {
+	active := k.Active()
+	return active.ID + ":" + base64.RawURLEncoding.EncodeToString(keyringMAC(active.Value, purpose, data))
+}
+
+// Verify reports if the signature of the data was created for the purpose by the active key
+// or a retired key.
+func (k *Keyring) Verify(purpose string, data []byte, signature string) bool {
+	id, mac, ok := strings.Cut(signature, ":")
+	if !ok {
+		return false
+	}
+	key, ok := k.Lookup(id)
+	if !ok {
+		return false
+	}
+	expected, err := base64.RawURLEncoding.DecodeString(mac)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, keyringMAC(key.Value, purpose, data))
+}
+
+// SignValue returns the value with its signature for the purpose, separated by a dot.
+func (k *Keyring) SignValue(purpose, value string) string {
+	return value + "." + k.Sign(purpose, []byte(value))
+}
+
+// VerifyValue returns the value of a signed value and reports if its signature is valid for the purpose.
+func (k *Keyring) VerifyValue(purpose, signed string) (string, bool) {
+	i := strings.LastIndexByte(signed, '.')
+	if i < 0 {
+		return "", false
+	}
+	value := signed[:i]
+	if !k.Verify(purpose, []byte(value), signed[i+1:]) {
+		return "", false
+	}
+	return value, true
+}
+
+// EncryptionKey returns a 32-byte key for the purpose which is derived from the value
+// of the key, e.g. for AES-256. It differs from the keys of the signatures and of the
+// other purposes, so the value of the key is never used for two algorithms.
+func (k Key) EncryptionKey(purpose string) []byte {
+	return deriveKey(k.Value, "fiber keyring encryption:"+purpose)
+}
+
+// keyringMAC returns the HMAC-SHA256 of the data with a key which is derived from
+// the key for the purpose.
+func keyringMAC(key []byte, purpose string, data []byte) []byte {
+	mac := hmac.New(sha256.New, deriveKey(key, "fiber keyring purpose:"+purpose))
+	mac.Write(data) //nolint:errcheck // Writing to a hash never fails
+	return mac.Sum(nil)
+}
+
+// deriveKey returns the HMAC-SHA256 of the label with the key, which is used as
+// the key of the label.
+func deriveKey(key []byte, label string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(label)) //nolint:errcheck // Writing to a hash never fails
+	return mac.Sum(nil)
+}
@@ -0,0 +1,160 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_NewKeyring
+func Test_NewKeyring(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		NewKeyring(KeyringConfig{})
+	})
+	require.Panics(t, func() {
+		NewKeyring(KeyringConfig{Keys: []Key{{ID: "v.1", Value: []byte("secret")}}})
+	})
+	require.Panics(t, func() {
+		NewKeyring(KeyringConfig{Keys: []Key{{ID: "v1"}}})
+	})
+
+	keyring := NewKeyring(KeyringConfig{Keys: []Key{
+		{ID: "v2", Value: []byte("secret-2")},
+		{ID: "v1", Value: []byte("secret-1")},
+	}})
+	require.Equal(t, "v2", keyring.Active().ID)
+	require.Len(t, keyring.Keys(), 2)
+
+	key, ok := keyring.Lookup("v1")
+	require.True(t, ok)
+	require.Equal(t, []byte("secret-1"), key.Value)
+	_, ok = keyring.Lookup("v0")
+	require.False(t, ok)
+}
+
+// go test -run Test_Keyring_Rotate
+func Test_Keyring_Rotate(t *testing.T) {
+	t.Parallel()
+	keyring := NewKeyring(KeyringConfig{Keys: []Key{{ID: "v1", Value: []byte("secret-1")}}})
+
+	var rotations []string
+	keyring.OnRotate(func(active, retired Key) {
+		rotations = append(rotations, retired.ID+"->"+active.ID)
+		require.False(t, retired.RetiredAt.IsZero())
+	})
+
+	keyring.Rotate(Key{ID: "v2", Value: []byte("secret-2")})
+	keyring.Rotate(Key{ID: "v3", Value: []byte("secret-3")})
+	keyring.Rotate(Key{ID: "v4", Value: []byte("secret-4")})
+	require.Equal(t, []string{"v1->v2", "v2->v3", "v3->v4"}, rotations)
+
+	// only DefaultMaxRetiredKeys retired keys are kept
+	keys := keyring.Keys()
+	require.Len(t, keys, 1+DefaultMaxRetiredKeys)
+	require.Equal(t, "v4", keys[0].ID)
+	require.Equal(t, "v3", keys[1].ID)
+	_, ok := keyring.Lookup("v1")
+	require.False(t, ok)
+
+	require.Panics(t, func() {
+		keyring.Rotate(Key{ID: "v:5", Value: []byte("secret-5")})
+	})
+}
+
+// go test -run Test_Keyring_RetiredKeyTTL
+func Test_Keyring_RetiredKeyTTL(t *testing.T) {
+	t.Parallel()
+	clock := NewManualClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	keyring := NewKeyring(KeyringConfig{
+		Keys:          []Key{{ID: "v1", Value: []byte("secret-1")}},
+		RetiredKeyTTL: time.Minute,
+		Clock:         clock,
+	})
+	signature := keyring.Sign("test", []byte("data"))
+
+	keyring.Rotate(keyring.GenerateKey())
+	require.True(t, strings.HasPrefix(keyring.Active().ID, "20240501T120000-"))
+	require.Equal(t, clock.Now(), keyring.Keys()[1].RetiredAt)
+	require.True(t, keyring.Verify("test", []byte("data"), signature))
+
+	// keys retired before the TTL are removed
+	clock.Advance(2 * time.Minute)
+	require.False(t, keyring.Verify("test", []byte("data"), signature))
+	require.Len(t, keyring.Keys(), 1)
+}
+
+// go test -run Test_Keyring_RotateEvery
+func Test_Keyring_RotateEvery(t *testing.T) {
+	t.Parallel()
+	keyring := NewKeyring(KeyringConfig{Keys: []Key{GenerateKey()}})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls atomic.Int32
+	done := make(chan struct{})
+	go func() {
+		keyring.RotateEvery(ctx, 10*time.Millisecond, func() (Key, error) {
+			if calls.Add(1) == 1 {
+				return Key{}, errors.New("unavailable")
+			}
+			return GenerateKey(), nil
+		})
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		return len(keyring.Keys()) > 1
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+	require.GreaterOrEqual(t, calls.Load(), int32(2))
+}
+
+// go test -run Test_Keyring_Sign
+func Test_Keyring_Sign(t *testing.T) {
+	t.Parallel()
+	keyring := NewKeyring(KeyringConfig{Keys: []Key{{ID: "v1", Value: []byte("secret-1")}}})
+
+	signature := keyring.Sign("test", []byte("data"))
+	require.Regexp(t, `^v1:[A-Za-z0-9_-]{43}$`, signature)
+	require.True(t, keyring.Verify("test", []byte("data"), signature))
+	require.False(t, keyring.Verify("test", []byte("other"), signature))
+	require.False(t, keyring.Verify("test", []byte("data"), "v1"))
+	require.False(t, keyring.Verify("test", []byte("data"), "v1:!"))
+	require.False(t, keyring.Verify("test", []byte("data"), "v0"+signature[2:]))
+
+	// a signature of one purpose isn't valid for another
+	require.False(t, keyring.Verify("other", []byte("data"), signature))
+
+	signed := keyring.SignValue("test", "a.b")
+	keyring.Rotate(Key{ID: "v2", Value: []byte("secret-2")})
+	value, ok := keyring.VerifyValue("test", signed)
+	require.True(t, ok)
+	require.Equal(t, "a.b", value)
+	require.Contains(t, keyring.SignValue("test", "a.b"), "a.b.v2:")
+
+	_, ok = keyring.VerifyValue("other", signed)
+	require.False(t, ok)
+	_, ok = keyring.VerifyValue("test", "a.b")
+	require.False(t, ok)
+	_, ok = keyring.VerifyValue("test", "nodot")
+	require.False(t, ok)
+}
+
+// go test -run Test_Key_EncryptionKey
+func Test_Key_EncryptionKey(t *testing.T) {
+	t.Parallel()
+	key := Key{ID: "v1", Value: []byte("secret-1")}
+
+	encryptionKey := key.EncryptionKey("cookies")
+	require.Len(t, encryptionKey, 32)
+	require.Equal(t, encryptionKey, key.EncryptionKey("cookies"))
+	require.NotEqual(t, encryptionKey, key.EncryptionKey("files"))
+	// the encryption key differs from the key of the signatures of the purpose
+	require.NotEqual(t, deriveKey(key.Value, "fiber keyring purpose:cookies"), encryptionKey)
+}
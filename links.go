@@ -0,0 +1,210 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Link is a link of a hypermedia response (RFC 8288).
+type Link struct {
+	// Rel is the relation type of the link, e.g. "self" or "next".
+	Rel string `json:"-"`
+	// Href is the URL of the link.
+	Href string `json:"href"`
+	// Title is the human-readable title of the link.
+	Title string `json:"title,omitempty"`
+	// Type is the media type of the target.
+	Type string `json:"type,omitempty"`
+}
+
+// LinkSet builds the links of a response from URLs, named routes and the pagination,
+// they are sent in the Link header or embedded into the body as HAL or JSON:API links.
+//
+//	links := fiber.NewLinkSet(c).
+//	    Self().
+//	    Route("author", "user", fiber.Map{"id": post.AuthorID}).
+//	    Pagination(p, total)
+//	if err := links.SetHeader(); err != nil {
+//	    return err
+//	}
+//	return c.JSON(fiber.Map{"_links": links.HAL(), "items": items})
+type LinkSet struct {
+	c     Ctx
+	links []Link
+	err   error
+}
+
+// NewLinkSet creates an empty LinkSet for the request.
+func NewLinkSet(c Ctx) *LinkSet {
+	return &LinkSet{c: c}
+}
+
+// Add adds the links.
+func (l *LinkSet) Add(links ...Link) *LinkSet {
+	l.links = append(l.links, links...)
+	return l
+}
+
+// Self adds the self link with the URL of the request.
+func (l *LinkSet) Self() *LinkSet {
+	href := l.c.BaseURL() + l.c.Path()
+	if query := l.c.Request().URI().QueryString(); len(query) > 0 {
+		href += "?" + string(query)
+	}
+	return l.Add(Link{Rel: "self", Href: href})
+}
+
+// Route adds a link to a named route with the params, see Ctx.GetRouteURL.
+// An unknown route is reported by Err.
+func (l *LinkSet) Route(rel, name string, params Map) *LinkSet {
+	if l.c.App().GetRoute(name).Name != name {
+		if l.err == nil {
+			l.err = fmt.Errorf("%w: %s", ErrRouteNotFound, name)
+		}
+		return l
+	}
+	location, err := l.c.GetRouteURL(name, params)
+	if err != nil {
+		if l.err == nil {
+			l.err = err
+		}
+		return l
+	}
+	return l.Add(Link{Rel: rel, Href: l.c.BaseURL() + location})
+}
+
+// Pagination adds the first, prev, next and last links of the pagination, see Ctx.PaginationHeaders.
+func (l *LinkSet) Pagination(p Pagination, total int) *LinkSet {
+	return l.Add(paginationLinks(l.c, p, total)...)
+}
+
+// Links returns the links in the order they were added.
+func (l *LinkSet) Links() []Link {
+	return l.links
+}
+
+// Err returns the first error of a route link.
+func (l *LinkSet) Err() error {
+	return l.err
+}
+
+// String returns the value of the Link header (RFC 8288).
+func (l *LinkSet) String() string {
+	var sb strings.Builder
+	for i, link := range l.links {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString("<" + link.Href + `>; rel="` + link.Rel + `"`)
+		if link.Title != "" {
+			sb.WriteString("; title=" + strconv.Quote(link.Title))
+		}
+		if link.Type != "" {
+			sb.WriteString(`; type="` + link.Type + `"`)
+		}
+	}
+	return sb.String()
+}
+
+// SetHeader sets the Link header of the response and returns Err.
+func (l *LinkSet) SetHeader() error {
+	if len(l.links) > 0 {
+		l.c.Set(HeaderLink, l.String())
+	}
+	return l.err
+}
+
+// HAL returns the links as "_links" object of HAL, the links are grouped by their
+// relation type, several links of a relation type are an array.
+//
+//	{"self": {"href": "..."}, "item": [{"href": "..."}, {"href": "..."}]}
+func (l *LinkSet) HAL() Map {
+	links := make(Map, len(l.links))
+	for _, link := range l.links {
+		switch existing := links[link.Rel].(type) {
+		case nil:
+			links[link.Rel] = link
+		case Link:
+			links[link.Rel] = []Link{existing, link}
+		case []Link:
+			links[link.Rel] = append(existing, link)
+		}
+	}
+	return links
+}
+
+// JSONAPI returns the links as "links" object of JSON:API. A link is its URL, or a link
+// object if it has a title or type. Only the first link of a relation type is used.
+//
+//	{"self": "...", "next": "..."}
+func (l *LinkSet) JSONAPI() Map {
+	links := make(Map, len(l.links))
+	for _, link := range l.links {
+		if _, ok := links[link.Rel]; ok {
+			continue
+		}
+		if link.Title == "" && link.Type == "" {
+			links[link.Rel] = link.Href
+		} else {
+			links[link.Rel] = link
+		}
+	}
+	return links
+}
+
+// paginationLinks returns the first, prev, next and last links of the pagination.
+// A negative total is unknown, the last link is omitted then. The links keep the
+// other query params of the request.
+func paginationLinks(c Ctx, p Pagination, total int) []Link {
+	base := c.BaseURL() + c.Path()
+	link := func(rel string, set func(args *fasthttp.Args)) Link {
+		args := fasthttp.AcquireArgs()
+		defer fasthttp.ReleaseArgs(args)
+		c.Request().URI().QueryArgs().CopyTo(args)
+		set(args)
+		if args.Len() == 0 {
+			return Link{Rel: rel, Href: base}
+		}
+		return Link{Rel: rel, Href: base + "?" + args.String()}
+	}
+
+	// cursor-based pagination
+	if p.Cursor != "" || p.NextCursor != "" {
+		links := []Link{link("first", func(args *fasthttp.Args) { args.Del("cursor") })}
+		if p.NextCursor != "" {
+			links = append(links, link("next", func(args *fasthttp.Args) { args.Set("cursor", p.NextCursor) }))
+		}
+		return links
+	}
+
+	// offset-based pagination, the links use pages if the request does
+	usePages := c.Query("offset") == "" && c.Query("page") != ""
+	offsetLink := func(rel string, offset int) Link {
+		return link(rel, func(args *fasthttp.Args) {
+			if usePages {
+				args.Set("page", strconv.Itoa(offset/p.Limit+1))
+				return
+			}
+			args.Set("offset", strconv.Itoa(offset))
+		})
+	}
+
+	links := []Link{offsetLink("first", 0)}
+	if p.Offset > 0 {
+		links = append(links, offsetLink("prev", max(p.Offset-p.Limit, 0)))
+	}
+	if total < 0 || p.Offset+p.Limit < total {
+		links = append(links, offsetLink("next", p.Offset+p.Limit))
+	}
+	if total >= 0 {
+		links = append(links, offsetLink("last", max(total-1, 0)/p.Limit*p.Limit))
+	}
+	return links
+}
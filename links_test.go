@@ -0,0 +1,86 @@
+package fiber
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_LinkSet
+func Test_LinkSet(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/users/:id", func(c Ctx) error {
+		return c.SendStatus(StatusOK)
+	}).Name("user")
+	app.Get("/posts", func(c Ctx) error {
+		p, err := c.Pagination()
+		if err != nil {
+			return err
+		}
+		links := NewLinkSet(c).
+			Self().
+			Route("author", "user", Map{"id": 7}).
+			Route("author", "user", Map{"id": 8}).
+			Add(Link{Rel: "help", Href: "https://example.com/help", Title: `The "help"`, Type: MIMETextHTML}).
+			Pagination(p, 25)
+		if err := links.SetHeader(); err != nil {
+			return err
+		}
+		require.Len(t, links.Links(), 7)
+		return c.JSON(Map{"_links": links.HAL(), "links": links.JSONAPI()})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "http://example.com/posts?limit=10", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, `<http://example.com/posts?limit=10>; rel="self",`+
+		`<http://example.com/users/7>; rel="author",`+
+		`<http://example.com/users/8>; rel="author",`+
+		`<https://example.com/help>; rel="help"; title="The \"help\""; type="text/html",`+
+		`<http://example.com/posts?limit=10&offset=0>; rel="first",`+
+		`<http://example.com/posts?limit=10&offset=10>; rel="next",`+
+		`<http://example.com/posts?limit=10&offset=20>; rel="last"`, resp.Header.Get(HeaderLink))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.Equal(t, map[string]any{
+		"self":   map[string]any{"href": "http://example.com/posts?limit=10"},
+		"author": []any{map[string]any{"href": "http://example.com/users/7"}, map[string]any{"href": "http://example.com/users/8"}},
+		"help":   map[string]any{"href": "https://example.com/help", "title": `The "help"`, "type": "text/html"},
+		"first":  map[string]any{"href": "http://example.com/posts?limit=10&offset=0"},
+		"next":   map[string]any{"href": "http://example.com/posts?limit=10&offset=10"},
+		"last":   map[string]any{"href": "http://example.com/posts?limit=10&offset=20"},
+	}, result["_links"])
+	require.Equal(t, map[string]any{
+		"self":   "http://example.com/posts?limit=10",
+		"author": "http://example.com/users/7",
+		"help":   map[string]any{"href": "https://example.com/help", "title": `The "help"`, "type": "text/html"},
+		"first":  "http://example.com/posts?limit=10&offset=0",
+		"next":   "http://example.com/posts?limit=10&offset=10",
+		"last":   "http://example.com/posts?limit=10&offset=20",
+	}, result["links"])
+}
+
+// go test -run Test_LinkSet_RouteNotFound
+func Test_LinkSet_RouteNotFound(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		links := NewLinkSet(c).Route("author", "missing", nil).Self()
+		err := links.SetHeader()
+		require.ErrorIs(t, err, ErrRouteNotFound)
+		require.ErrorIs(t, links.Err(), ErrRouteNotFound)
+		require.Len(t, links.Links(), 1)
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
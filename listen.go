@@ -8,7 +8,9 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
@@ -58,6 +60,22 @@ type ListenConfig struct {
 	// Default : ""
 	CertKeyFile string `json:"cert_key_file"`
 
+	// TLSSecrets loads the certificate, its private key and the client certificate
+	// in PEM format from the secrets provider instead of files. CertFile, CertKeyFile
+	// and CertClientFile are the names of the secrets. The certificate and its key
+	// are reloaded when one of their secrets changes, so they can be rotated without a restart.
+	//
+	// Default: nil
+	TLSSecrets SecretsProvider `json:"-"`
+
+	// CertificateSource provides the certificate and the roots of the client certificates
+	// instead of files, e.g. the X.509 SVIDs of the SPIFFE Workload API. They are requested
+	// from the source on each handshake, so they can be rotated without a restart.
+	// The client certificates are verified if the source returns roots.
+	//
+	// Default: nil
+	CertificateSource CertificateSource `json:"-"`
+
 	// CertClientFile is a path of client certficate.
 	// If you want to use mTLS, you have to enter this field.
 	//
@@ -69,6 +87,43 @@ type ListenConfig struct {
 	// Default: nil
 	GracefulContext context.Context `json:"graceful_context"` //nolint:containedctx // It's needed to set context inside Listen.
 
+	// TLSPolicy applies a preset of the TLS settings, TLSPolicyModern, TLSPolicyIntermediate
+	// or TLSPolicyFIPS, before TLSConfigFunc. The TLS config is validated with the policy
+	// after TLSConfigFunc and the violations are reported in the startup message.
+	// When using Listener, the TLS config of the listener is only validated.
+	//
+	// Default: "" (the defaults of crypto/tls)
+	TLSPolicy TLSPolicy `json:"tls_policy"`
+
+	// TLSKeyLogWriter receives the TLS session keys in the NSS key log format, so the traffic
+	// can be decrypted with Wireshark. It requires InsecureTLSKeyLog.
+	// When using Listener, set KeyLogWriter of the TLS config of the listener instead.
+	//
+	// Default: nil
+	TLSKeyLogWriter io.Writer `json:"-"`
+
+	// When set to true, the TLS session keys are written to TLSKeyLogWriter, or appended to
+	// the file of the SSLKEYLOGFILE environment variable if TLSKeyLogWriter isn't set.
+	// WARNING: Everyone with access to the keys can decrypt the traffic, never enable it in production.
+	//
+	// Default: false
+	InsecureTLSKeyLog bool `json:"insecure_tls_key_log"`
+
+	// ECHKeys enables Encrypted Client Hello (ECH) with the keys, so the server name which is
+	// requested by the clients isn't visible on the network. The configs of the keys have to be
+	// published in the HTTPS DNS record, see ECHConfigList. Keys are rotated by publishing the
+	// config of the new key while the old key is still served. It requires Go 1.24 or newer.
+	// When using Listener, set EncryptedClientHelloKeys of the TLS config of the listener instead.
+	//
+	// Default: nil
+	ECHKeys []ECHKey `json:"-"`
+
+	// ECHKeyFiles are paths of ECH key files in PEM format, see ParseECHKeyPEM.
+	// Their keys are used after the ECHKeys.
+	//
+	// Default: nil
+	ECHKeyFiles []string `json:"ech_key_files"`
+
 	// TLSConfigFunc allows customizing tls.Config as you want.
 	//
 	// Default: nil
@@ -94,6 +149,44 @@ type ListenConfig struct {
 	// Default: false
 	EnablePrefork bool `json:"enable_prefork"`
 
+	// When set to true, HTTP/2 is served over TLS, negotiated with ALPN.
+	// HTTP/1.1 clients are still served by fasthttp. HTTP/2 is not supported with prefork.
+	// When using Listener, the TLS config of the listener has to contain "h2" in NextProtos.
+	// The protocols of HandleALPN are served next to HTTP/2.
+	//
+	// Default: false
+	EnableHTTP2 bool `json:"enable_http2"`
+
+	// HTTP2 configures the HTTP/2 server, if EnableHTTP2 is true.
+	//
+	// Default: HTTP2Config{}
+	HTTP2 HTTP2Config `json:"http2"`
+
+	// MaxConns is the maximum number of open connections.
+	// Further connections are closed directly after they were accepted.
+	//
+	// Default: 0 (unlimited)
+	MaxConns int `json:"max_conns"`
+
+	// MaxConnsPerIP is the maximum number of open connections per client IP.
+	//
+	// Default: 0 (unlimited)
+	MaxConnsPerIP int `json:"max_conns_per_ip"`
+
+	// MaxAcceptRate is the maximum number of connections accepted per second.
+	//
+	// Default: 0 (unlimited)
+	MaxAcceptRate int `json:"max_accept_rate"`
+
+	// When set to true, GOMAXPROCS and the soft memory limit of the Go runtime are
+	// adjusted to the CPU quota and the memory limit of the container (cgroup v1 and v2),
+	// unless the GOMAXPROCS or GOMEMLIMIT environment variables are set. This also
+	// adjusts the number of prefork child processes and the size classes of the
+	// response buffer pool. The limits are reported in the startup message.
+	//
+	// Default: false
+	EnableContainerLimits bool `json:"enable_container_limits"`
+
 	// If set to true, will print all routes with their method, path and handler.
 	//
 	// Default: false
@@ -101,7 +194,7 @@ type ListenConfig struct {
 
 	// OnShutdownError allows to customize error behavior when to graceful shutdown server by given signal.
 	//
-	// Default: Print error with log.Fatalf()
+	// Default: Log the error with the logger of the app
 	OnShutdownError func(err error)
 
 	// OnShutdownSuccess allows to customize success behavior when to graceful shutdown server by given signal.
@@ -115,9 +208,6 @@ func listenConfigDefault(config ...ListenConfig) ListenConfig {
 	if len(config) < 1 {
 		return ListenConfig{
 			ListenerNetwork: NetworkTCP4,
-			OnShutdownError: func(err error) {
-				log.Fatalf("shutdown: %v", err) //nolint:revive // It's an optipn
-			},
 		}
 	}
 
@@ -126,12 +216,6 @@ func listenConfigDefault(config ...ListenConfig) ListenConfig {
 		cfg.ListenerNetwork = NetworkTCP4
 	}
 
-	if cfg.OnShutdownError == nil {
-		cfg.OnShutdownError = func(err error) {
-			log.Fatalf("shutdown: %v", err) //nolint:revive // It's an optipn
-		}
-	}
-
 	return cfg
 }
 
@@ -142,11 +226,26 @@ func listenConfigDefault(config ...ListenConfig) ListenConfig {
 //	app.Listen("127.0.0.1:8080")
 //	app.Listen(":8080", ListenConfig{EnablePrefork: true})
 func (app *App) Listen(addr string, config ...ListenConfig) error {
-	cfg := listenConfigDefault(config...)
+	cfg := app.profileListenConfig(listenConfigDefault(config...))
+
+	// Adjust the runtime to the limits of the container
+	if cfg.EnableContainerLimits {
+		app.applyContainerLimits(cfg)
+	}
 
 	// Configure TLS
 	var tlsConfig *tls.Config
-	if cfg.CertFile != "" && cfg.CertKeyFile != "" {
+	if cfg.CertificateSource != nil {
+		tlsConfig = app.sourceTLSConfig(cfg.CertificateSource)
+	} else if cfg.TLSSecrets != nil && cfg.CertFile != "" && cfg.CertKeyFile != "" {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var err error
+		if tlsConfig, err = app.secretsTLSConfig(ctx, cfg); err != nil {
+			return err
+		}
+	} else if cfg.CertFile != "" && cfg.CertKeyFile != "" {
 		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.CertKeyFile)
 		if err != nil {
 			return fmt.Errorf("tls: cannot load TLS key pair from certFile=%q and keyFile=%q: %w", cfg.CertFile, cfg.CertKeyFile, err)
@@ -178,9 +277,50 @@ func (app *App) Listen(addr string, config ...ListenConfig) error {
 		app.SetTLSHandler(tlsHandler)
 	}
 
+	// Offer HTTP/2 with ALPN
+	if cfg.EnableHTTP2 && tlsConfig != nil {
+		tlsConfig.NextProtos = []string{http2Proto, http1Proto}
+	}
+
+	// Offer the protocols of the ALPN handlers, prefork children only serve HTTP/1.1
+	if tlsConfig != nil && !cfg.EnablePrefork {
+		app.offerALPN(tlsConfig)
+	}
+
+	// Apply the TLS policy
+	if cfg.TLSPolicy != "" && tlsConfig != nil {
+		if err := cfg.TLSPolicy.Apply(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	// Enable Encrypted Client Hello
+	if tlsConfig != nil && (len(cfg.ECHKeys) > 0 || len(cfg.ECHKeyFiles) > 0) {
+		keys, err := echKeys(cfg)
+		if err != nil {
+			return err
+		}
+		if err := applyECHKeys(tlsConfig, keys); err != nil {
+			return err
+		}
+	}
+
+	// Log the TLS session keys for debugging
+	if tlsConfig != nil {
+		keyLog, keyLogFile, err := tlsKeyLogWriter(cfg)
+		if err != nil {
+			return err
+		}
+		if keyLogFile != nil {
+			defer keyLogFile.Close() //nolint:errcheck // It is fine to ignore the error here
+		}
+		tlsConfig.KeyLogWriter = keyLog
+	}
+
 	if cfg.TLSConfigFunc != nil {
 		cfg.TLSConfigFunc(tlsConfig)
 	}
+	app.validateTLSPolicy(tlsConfig, cfg)
 
 	// Graceful shutdown
 	if cfg.GracefulContext != nil {
@@ -204,6 +344,12 @@ func (app *App) Listen(addr string, config ...ListenConfig) error {
 	// prepare the server for the start
 	app.startupProcess()
 
+	// start plugins, the started plugins are stopped if one fails
+	if err := app.startPlugins(context.Background()); err != nil {
+		_ = ln.Close() //nolint:errcheck // It is fine to ignore the error here
+		return err
+	}
+
 	// run hooks
 	app.runOnListenHooks(app.prepareListenData(ln.Addr().String(), getTLSConfig(ln) != nil, cfg))
 
@@ -213,17 +359,44 @@ func (app *App) Listen(addr string, config ...ListenConfig) error {
 	// Serve
 	if cfg.BeforeServeFunc != nil {
 		if err := cfg.BeforeServeFunc(app); err != nil {
-			return err
+			_ = ln.Close() //nolint:errcheck // It is fine to ignore the error here
+			return errors.Join(err, app.stopPlugins(context.Background()))
 		}
 	}
 
-	return app.server.Serve(ln)
+	// Serve while the warm-up hooks are executed
+	return app.serveWarm(ln, func() error {
+		if app.servesTLS(ln, cfg) {
+			return app.serveTLS(ln, cfg)
+		}
+
+		return app.server.Serve(ln)
+	})
 }
 
 // Listener serves HTTP requests from the given listener.
 // You should enter custom ListenConfig to customize startup. (prefork, startup message, graceful shutdown...)
 func (app *App) Listener(ln net.Listener, config ...ListenConfig) error {
-	cfg := listenConfigDefault(config...)
+	cfg := app.profileListenConfig(listenConfigDefault(config...))
+
+	// Adjust the runtime to the limits of the container
+	if cfg.EnableContainerLimits {
+		app.applyContainerLimits(cfg)
+	}
+
+	// Validate the TLS config of the listener with the TLS policy
+	tlsConfig := getTLSConfig(ln)
+	app.validateTLSPolicy(tlsConfig, cfg)
+
+	// Count and limit the connections, TLS listeners can't be limited before the handshake
+	if tlsConfig == nil {
+		ln = app.limitListener(ln, cfg)
+	} else {
+		app.hookListenerTLSConfig(tlsConfig)
+		if cfg.MaxConns > 0 || cfg.MaxConnsPerIP > 0 || cfg.MaxAcceptRate > 0 {
+			app.logw(log.LevelWarn, "connection limits are ignored for TLS listeners, pass the raw listener to limit the connections")
+		}
+	}
 
 	// Graceful shutdown
 	if cfg.GracefulContext != nil {
@@ -236,6 +409,12 @@ func (app *App) Listener(ln net.Listener, config ...ListenConfig) error {
 	// prepare the server for the start
 	app.startupProcess()
 
+	// start plugins, the started plugins are stopped if one fails
+	if err := app.startPlugins(context.Background()); err != nil {
+		_ = ln.Close() //nolint:errcheck // It is fine to ignore the error here
+		return err
+	}
+
 	// run hooks
 	app.runOnListenHooks(app.prepareListenData(ln.Addr().String(), getTLSConfig(ln) != nil, cfg))
 
@@ -245,35 +424,42 @@ func (app *App) Listener(ln net.Listener, config ...ListenConfig) error {
 	// Serve
 	if cfg.BeforeServeFunc != nil {
 		if err := cfg.BeforeServeFunc(app); err != nil {
-			return err
+			_ = ln.Close() //nolint:errcheck // It is fine to ignore the error here
+			return errors.Join(err, app.stopPlugins(context.Background()))
 		}
 	}
 
 	// Prefork is not supported for custom listeners
 	if cfg.EnablePrefork {
-		log.Warn("Prefork isn't supported for custom listeners.")
+		app.logw(log.LevelWarn, "prefork isn't supported for custom listeners")
 	}
 
-	return app.server.Serve(ln)
+	// Serve while the warm-up hooks are executed
+	return app.serveWarm(ln, func() error {
+		if app.servesTLS(ln, cfg) {
+			return app.serveTLS(ln, cfg)
+		}
+
+		return app.server.Serve(ln)
+	})
 }
 
 // Create listener function.
-func (*App) createListener(addr string, tlsConfig *tls.Config, cfg ListenConfig) (net.Listener, error) {
-	var listener net.Listener
-	var err error
-
-	if tlsConfig != nil {
-		listener, err = tls.Listen(cfg.ListenerNetwork, addr, tlsConfig)
-	} else {
-		listener, err = net.Listen(cfg.ListenerNetwork, addr)
-	}
+func (app *App) createListener(addr string, tlsConfig *tls.Config, cfg ListenConfig) (net.Listener, error) {
+	listener, err := net.Listen(cfg.ListenerNetwork, addr)
 
 	// Check for error before using the listener
 	if err != nil {
-		// Wrap the error from tls.Listen/net.Listen
+		// Wrap the error from net.Listen
 		return nil, fmt.Errorf("failed to listen: %w", err)
 	}
 
+	// Count and limit the connections before the TLS handshake
+	listener = app.limitListener(listener, cfg)
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, app.hookTLSConfig(tlsConfig))
+	}
+
 	if cfg.ListenerAddrFunc != nil {
 		cfg.ListenerAddrFunc(listener.Addr())
 	}
@@ -366,6 +552,13 @@ func (app *App) startupMessage(addr string, isTLS bool, pids string, cfg ListenC
 	if app.config.AppName != "" {
 		_, _ = fmt.Fprintf(out, "%sINFO%s Application name: \t\t%s%s%s\n", colors.Green, colors.Reset, colors.Blue, app.config.AppName, colors.Reset)
 	}
+	if build := app.GetBuildInfo(); build.Version != "" {
+		version := build.Version
+		if build.Commit != "" {
+			version += " (" + build.Commit[:min(len(build.Commit), 7)] + ")"
+		}
+		_, _ = fmt.Fprintf(out, "%sINFO%s Application version: \t%s%s%s\n", colors.Green, colors.Reset, colors.Blue, version, colors.Reset)
+	}
 	_, _ = fmt.Fprintf(out,
 		"%sINFO%s Total handlers count: \t%s%s%s\n",
 		colors.Green, colors.Reset, colors.Blue, strconv.Itoa(int(app.handlersCount)), colors.Reset)
@@ -376,6 +569,37 @@ func (app *App) startupMessage(addr string, isTLS bool, pids string, cfg ListenC
 	}
 	_, _ = fmt.Fprintf(out, "%sINFO%s PID: \t\t\t%s%v%s\n", colors.Green, colors.Reset, colors.Blue, os.Getpid(), colors.Reset)
 	_, _ = fmt.Fprintf(out, "%sINFO%s Total process count: \t%s%s%s\n", colors.Green, colors.Reset, colors.Blue, procs, colors.Reset)
+	for _, companion := range app.companions.stats() {
+		_, _ = fmt.Fprintf(out, "%sINFO%s Companion listener: \t%s%s://%s%s\n", colors.Green, colors.Reset, colors.Blue, companion.Network, companion.Addr, colors.Reset)
+	}
+
+	if isTLS && cfg.TLSPolicy != "" {
+		_, _ = fmt.Fprintf(out, "%sINFO%s TLS policy: \t\t%s%s%s\n", colors.Green, colors.Reset, colors.Blue, cfg.TLSPolicy, colors.Reset)
+		for _, violation := range app.tlsPolicyReport {
+			_, _ = fmt.Fprintf(out, "%sWARN%s TLS policy: \t\t%s%s%s\n", colors.Yellow, colors.Reset, colors.Yellow, violation, colors.Reset)
+		}
+	}
+
+	if isTLS && (len(cfg.ECHKeys) > 0 || len(cfg.ECHKeyFiles) > 0) {
+		_, _ = fmt.Fprintf(out, "%sINFO%s ECH keys: \t\t%s%d%s\n", colors.Green, colors.Reset, colors.Blue, len(cfg.ECHKeys)+len(cfg.ECHKeyFiles), colors.Reset)
+	}
+
+	if isTLS && tlsKeyLogEnabled(cfg) {
+		_, _ = fmt.Fprintf(out, "%sWARN%s TLS key log: \t\t%sthe session keys are logged, never use it in production%s\n", colors.Yellow, colors.Reset, colors.Yellow, colors.Reset)
+	}
+
+	if cfg.EnableContainerLimits {
+		limits := app.ContainerLimits()
+		cpuQuota, memoryLimit := "Unlimited", "Unlimited"
+		if limits.CPUQuota > 0 {
+			cpuQuota = strconv.FormatFloat(limits.CPUQuota, 'f', -1, 64)
+		}
+		if limits.MemoryLimit > 0 {
+			memoryLimit = strconv.FormatInt(limits.MemoryLimit/(1024*1024), 10) + " MiB"
+		}
+		_, _ = fmt.Fprintf(out, "%sINFO%s CPU quota: \t\t\t%s%s (GOMAXPROCS %d)%s\n", colors.Green, colors.Reset, colors.Blue, cpuQuota, runtime.GOMAXPROCS(0), colors.Reset)
+		_, _ = fmt.Fprintf(out, "%sINFO%s Memory limit: \t\t%s%s%s\n", colors.Green, colors.Reset, colors.Blue, memoryLimit, colors.Reset)
+	}
 
 	if cfg.EnablePrefork {
 		// Turn the `pids` variable (in the form ",a,b,c,d,e,f,etc") into a slice of PIDs
@@ -462,7 +686,11 @@ func (app *App) gracefulShutdown(ctx context.Context, cfg ListenConfig) {
 	<-ctx.Done()
 
 	if err := app.Shutdown(); err != nil { //nolint:contextcheck // TODO: Implement it
-		cfg.OnShutdownError(err)
+		if cfg.OnShutdownError != nil {
+			cfg.OnShutdownError(err)
+		} else {
+			app.logw(log.LevelError, "shutdown: failed to shut down", "error", err)
+		}
 	}
 
 	if success := cfg.OnShutdownSuccess; success != nil {
@@ -381,6 +381,21 @@ func Test_Listen_Master_Process_Show_Startup_Message(t *testing.T) {
 	require.Contains(t, startupMessage, fmt.Sprintf("Prefork: \t\t\t%sEnabled%s", colors.Blue, colors.Reset))
 }
 
+// go test -run Test_Listen_Master_Process_Show_Startup_MessageWithContainerLimits
+func Test_Listen_Master_Process_Show_Startup_MessageWithContainerLimits(t *testing.T) {
+	cfg := ListenConfig{
+		EnableContainerLimits: true,
+	}
+
+	app := New()
+	app.containerLimits = ContainerLimits{CPUQuota: 1.5, MemoryLimit: 512 << 20}
+	startupMessage := captureOutput(func() {
+		app.startupMessage(":3000", false, "", cfg)
+	})
+	require.Contains(t, startupMessage, "CPU quota: \t\t\t1.5 (GOMAXPROCS")
+	require.Contains(t, startupMessage, "Memory limit: \t\t512 MiB")
+}
+
 // go test -run Test_Listen_Master_Process_Show_Startup_MessageWithAppName
 func Test_Listen_Master_Process_Show_Startup_MessageWithAppName(t *testing.T) {
 	cfg := ListenConfig{
@@ -395,6 +410,16 @@ func Test_Listen_Master_Process_Show_Startup_MessageWithAppName(t *testing.T) {
 	require.Contains(t, startupMessage, app.Config().AppName)
 }
 
+// go test -run Test_Listen_Master_Process_Show_Startup_MessageWithBuildInfo
+func Test_Listen_Master_Process_Show_Startup_MessageWithBuildInfo(t *testing.T) {
+	app := New()
+	app.BuildInfo(BuildInfo{Version: "1.4.0", Commit: "4f2c9e1b7d"})
+	startupMessage := captureOutput(func() {
+		app.startupMessage(":3000", false, "", ListenConfig{})
+	})
+	require.Contains(t, startupMessage, "Application version: \t1.4.0 (4f2c9e1)")
+}
+
 // go test -run Test_Listen_Master_Process_Show_Startup_MessageWithAppNameNonAscii
 func Test_Listen_Master_Process_Show_Startup_MessageWithAppNameNonAscii(t *testing.T) {
 	cfg := ListenConfig{
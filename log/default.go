@@ -95,7 +95,11 @@ func (l *defaultLogger) privateLogw(lv Level, format string, keysAndValues []any
 			}
 			buf.WriteString(keysAndValues[i].(string)) //nolint:forcetypeassert // Keys must be strings
 			buf.WriteByte('=')
-			buf.WriteString(utils.ToString(keysAndValues[i+1]))
+			if err, ok := keysAndValues[i+1].(error); ok {
+				buf.WriteString(err.Error())
+			} else {
+				buf.WriteString(utils.ToString(keysAndValues[i+1]))
+			}
 		}
 	}
 
@@ -214,3 +218,12 @@ func (l *defaultLogger) SetOutput(writer io.Writer) {
 func DefaultLogger() AllLogger {
 	return logger
 }
+
+// New creates a logger like the default logger which writes to the writer,
+// e.g. to the error log of an app.
+func New(w io.Writer) AllLogger {
+	return &defaultLogger{
+		stdlog: log.New(w, "", log.LstdFlags|log.Lshortfile|log.Lmicroseconds),
+		depth:  4,
+	}
+}
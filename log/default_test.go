@@ -159,6 +159,14 @@ func Test_LogfKeyAndValues(t *testing.T) {
 			keysAndValues: []any{"error", "not found", "id", 123},
 			wantOutput:    "[Warn] test error=not found id=123\n",
 		},
+		{
+			name:          "test logf with error value",
+			level:         LevelError,
+			format:        "failed",
+			fmtArgs:       nil,
+			keysAndValues: []any{"error", context.Canceled},
+			wantOutput:    "[Error] failed error=context canceled\n",
+		},
 		{
 			name:          "test logf with one key",
 			level:         LevelWarn,
@@ -69,3 +69,26 @@ func Test_Fiberlog_SetLevel(t *testing.T) {
 		})
 	}
 }
+
+func Test_ParseLevel(t *testing.T) {
+	t.Parallel()
+
+	lv, err := ParseLevel("debug")
+	require.NoError(t, err)
+	require.Equal(t, LevelDebug, lv)
+
+	lv, err = ParseLevel("WARN")
+	require.NoError(t, err)
+	require.Equal(t, LevelWarn, lv)
+
+	_, err = ParseLevel("verbose")
+	require.Error(t, err)
+}
+
+func Test_Level_String(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "debug", LevelDebug.String())
+	require.Equal(t, "panic", LevelPanic.String())
+	require.Equal(t, "Level(9)", Level(9).String())
+}
@@ -0,0 +1,284 @@
+package log
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the format of the timestamps in the names of the rotated files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// ErrFileClosed is returned by the writes to a closed File.
+var ErrFileClosed = errors.New("log: file is closed")
+
+// FileConfig defines the config of a log file.
+type FileConfig struct {
+	// Path is the path of the log file. The rotated files are written to its directory,
+	// with the time of the rotation in their names, e.g. "access-2024-05-01T00-00-00.000.log".
+	//
+	// Required.
+	Path string `json:"path"`
+
+	// MaxSize is the size in bytes after which the file is rotated.
+	//
+	// Optional. Default: 0, no rotation by size
+	MaxSize int64 `json:"max_size"`
+
+	// RotateInterval is the interval in which the file is rotated, e.g. 24 * time.Hour
+	// rotates it at midnight UTC.
+	//
+	// Optional. Default: 0, no rotation by time
+	RotateInterval time.Duration `json:"rotate_interval"`
+
+	// MaxBackups is the number of rotated files which are kept.
+	//
+	// Optional. Default: 0, all rotated files are kept
+	MaxBackups int `json:"max_backups"`
+
+	// MaxAge is the time after which the rotated files are removed.
+	//
+	// Optional. Default: 0, the rotated files are kept
+	MaxAge time.Duration `json:"max_age"`
+
+	// Compress compresses the rotated files with gzip.
+	//
+	// Optional. Default: false
+	Compress bool `json:"compress"`
+
+	// FileMode is the mode of the created log files.
+	//
+	// Optional. Default: 0o640
+	FileMode os.FileMode `json:"file_mode"`
+}
+
+// File is a log file which is rotated by size and time, e.g. the access log or the error
+// log of an app. The rotated files are compressed and removed after the retention in the
+// background. Reopen reopens the file after it was moved by an external tool like logrotate.
+type File struct {
+	config FileConfig
+
+	mutex        sync.Mutex
+	file         *os.File
+	size         int64
+	nextRotation time.Time
+
+	// the rotated files are compressed and pruned one after another
+	jobs sync.Mutex
+	wg   sync.WaitGroup
+}
+
+// OpenFile opens the log file and creates its directory if it doesn't exist.
+func OpenFile(config FileConfig) (*File, error) {
+	if config.Path == "" {
+		return nil, errors.New("log: the path of the file is empty")
+	}
+	if config.FileMode == 0 {
+		config.FileMode = 0o640
+	}
+
+	f := &File{config: config}
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0o750); err != nil {
+		return nil, fmt.Errorf("log: failed to create the directory of %q: %w", config.Path, err)
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the file for appending, it must be called with the mutex held.
+func (f *File) open() error {
+	file, err := os.OpenFile(f.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, f.config.FileMode)
+	if err != nil {
+		return fmt.Errorf("log: failed to open %q: %w", f.config.Path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close() //nolint:errcheck // The error of Stat is returned
+		return fmt.Errorf("log: failed to stat %q: %w", f.config.Path, err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	if f.config.RotateInterval > 0 {
+		f.nextRotation = time.Now().Truncate(f.config.RotateInterval).Add(f.config.RotateInterval)
+	}
+	return nil
+}
+
+// Write writes to the file and rotates it before when it exceeds the MaxSize or the RotateInterval elapsed.
+func (f *File) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.file == nil {
+		return 0, ErrFileClosed
+	}
+
+	exceeded := f.config.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.config.MaxSize
+	elapsed := f.config.RotateInterval > 0 && !time.Now().Before(f.nextRotation)
+	if exceeded || elapsed {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Rotate moves the file to a rotated file and opens a new file.
+func (f *File) Rotate() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.file == nil {
+		return ErrFileClosed
+	}
+	return f.rotate()
+}
+
+// rotate rotates the file, it must be called with the mutex held.
+func (f *File) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("log: failed to close %q: %w", f.config.Path, err)
+	}
+	f.file = nil
+
+	ext := filepath.Ext(f.config.Path)
+	backup := strings.TrimSuffix(f.config.Path, ext) + "-" + time.Now().UTC().Format(backupTimeFormat) + ext
+	if err := os.Rename(f.config.Path, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("log: failed to rotate %q: %w", f.config.Path, err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.jobs.Lock()
+		defer f.jobs.Unlock()
+		if f.config.Compress {
+			if err := compressFile(backup); err != nil {
+				Errorw("log: failed to compress the rotated file", "file", backup, "error", err)
+			}
+		}
+		if err := f.prune(); err != nil {
+			Errorw("log: failed to remove the rotated files", "file", f.config.Path, "error", err)
+		}
+	}()
+	return nil
+}
+
+// prune removes the rotated files which exceed the MaxBackups or the MaxAge.
+func (f *File) prune() error {
+	if f.config.MaxBackups <= 0 && f.config.MaxAge <= 0 {
+		return nil
+	}
+
+	ext := filepath.Ext(f.config.Path)
+	prefix := strings.TrimSuffix(filepath.Base(f.config.Path), ext) + "-"
+	entries, err := os.ReadDir(filepath.Dir(f.config.Path))
+	if err != nil {
+		return err
+	}
+
+	type backup struct {
+		name string
+		time time.Time
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		stamp, ok := strings.CutPrefix(name, prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		t, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{name: name, time: t})
+	}
+	// the newest files first
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+
+	var errs []error
+	for i, b := range backups {
+		tooMany := f.config.MaxBackups > 0 && i >= f.config.MaxBackups
+		tooOld := f.config.MaxAge > 0 && time.Since(b.time) > f.config.MaxAge
+		if tooMany || tooOld {
+			if err := os.Remove(filepath.Join(filepath.Dir(f.config.Path), b.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// compressFile compresses the file with gzip and removes it.
+func compressFile(path string) error {
+	src, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer src.Close() //nolint:errcheck // The file is only read
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	err = errors.Join(err, zw.Close(), dst.Close())
+	if err != nil {
+		_ = os.Remove(path + ".gz") //nolint:errcheck // The error of the compression is returned
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Reopen closes and reopens the file, e.g. after it was moved by logrotate. The file
+// isn't rotated, the rotation of logrotate and of the File shouldn't be combined.
+func (f *File) Reopen() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.file == nil {
+		return ErrFileClosed
+	}
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("log: failed to close %q: %w", f.config.Path, err)
+	}
+	f.file = nil
+	return f.open()
+}
+
+// Close closes the file and waits until the rotated files are compressed and pruned.
+func (f *File) Close() error {
+	f.mutex.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mutex.Unlock()
+
+	f.wg.Wait()
+	return err
+}
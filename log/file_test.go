@@ -0,0 +1,146 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rotatedFiles returns the names of the rotated files of the log file.
+func rotatedFiles(t *testing.T, path string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		if entry.Name() != filepath.Base(path) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+func Test_File_RotateBySize(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "logs", "access.log")
+	f, err := OpenFile(FileConfig{Path: path, MaxSize: 10, MaxBackups: 2})
+	require.NoError(t, err)
+
+	for _, line := range []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"} {
+		_, err = f.Write([]byte(line))
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond) // the rotated files are named by milliseconds
+	}
+	require.NoError(t, f.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "line 4\n", string(content))
+
+	// only the latest backups are kept
+	names := rotatedFiles(t, path)
+	require.Len(t, names, 2)
+	for _, name := range names {
+		require.True(t, strings.HasPrefix(name, "access-") && strings.HasSuffix(name, ".log"), name)
+	}
+	content, err = os.ReadFile(filepath.Join(filepath.Dir(path), names[1]))
+	require.NoError(t, err)
+	require.Equal(t, "line 3\n", string(content))
+
+	_, err = f.Write([]byte("closed"))
+	require.ErrorIs(t, err, ErrFileClosed)
+}
+
+func Test_File_RotateCompress(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "error.log")
+	require.NoError(t, os.WriteFile(path, []byte("previous\n"), 0o600))
+
+	f, err := OpenFile(FileConfig{Path: path, RotateInterval: time.Hour, Compress: true})
+	require.NoError(t, err)
+	_, err = f.Write([]byte("appended\n"))
+	require.NoError(t, err)
+
+	// the interval elapsed
+	f.nextRotation = time.Now()
+	_, err = f.Write([]byte("rotated\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	names := rotatedFiles(t, path)
+	require.Len(t, names, 1)
+	require.True(t, strings.HasSuffix(names[0], ".log.gz"), names[0])
+
+	gz, err := os.Open(filepath.Join(filepath.Dir(path), names[0]))
+	require.NoError(t, err)
+	defer gz.Close() //nolint:errcheck // It is fine to ignore the error here
+	zr, err := gzip.NewReader(gz)
+	require.NoError(t, err)
+	content, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	require.Equal(t, "previous\nappended\n", string(content))
+}
+
+func Test_File_MaxAge(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+	old := filepath.Join(dir, "access-"+time.Now().Add(-48*time.Hour).UTC().Format(backupTimeFormat)+".log.gz")
+	require.NoError(t, os.WriteFile(old, nil, 0o600))
+	other := filepath.Join(dir, "other.log")
+	require.NoError(t, os.WriteFile(other, nil, 0o600))
+
+	f, err := OpenFile(FileConfig{Path: path, MaxAge: 24 * time.Hour})
+	require.NoError(t, err)
+	_, err = f.Write([]byte("line\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+	require.NoError(t, f.Close())
+
+	require.NoFileExists(t, old)
+	require.FileExists(t, other)
+	require.Len(t, rotatedFiles(t, path), 2)
+}
+
+func Test_File_Reopen(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "access.log")
+	f, err := OpenFile(FileConfig{Path: path})
+	require.NoError(t, err)
+	defer f.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	_, err = f.Write([]byte("before\n"))
+	require.NoError(t, err)
+
+	// logrotate moves the file and signals the app
+	require.NoError(t, os.Rename(path, path+".1"))
+	_, err = f.Write([]byte("moved\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Reopen())
+	_, err = f.Write([]byte("after\n"))
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Equal(t, "before\nmoved\n", string(content))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "after\n", string(content))
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+	var w byteSliceWriter
+	l := New(&w)
+	l.SetLevel(LevelWarn)
+	l.Info("dropped")
+	l.Warnw("written", "key", "value")
+	require.Contains(t, string(w.b), "[Warn] written key=value\n")
+	require.NotContains(t, string(w.b), "dropped")
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 )
 
 var logger AllLogger = &defaultLogger{
@@ -92,6 +93,35 @@ var strs = []string{
 	"[Panic] ",
 }
 
+var levelNames = []string{
+	"trace",
+	"debug",
+	"info",
+	"warn",
+	"error",
+	"fatal",
+	"panic",
+}
+
+// ParseLevel parses a level name like "debug" or "warn" into a Level.
+// The name is case-insensitive.
+func ParseLevel(name string) (Level, error) {
+	for i, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return Level(i), nil
+		}
+	}
+	return LevelTrace, fmt.Errorf("log: unknown level %q", name)
+}
+
+// String returns the name of the level, like "debug" or "warn".
+func (lv Level) String() string {
+	if lv >= LevelTrace && lv <= LevelPanic {
+		return levelNames[lv]
+	}
+	return fmt.Sprintf("Level(%d)", int(lv))
+}
+
 func (lv Level) toString() string {
 	if lv >= LevelTrace && lv <= LevelPanic {
 		return strs[lv]
@@ -0,0 +1,204 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// LogStats contains the counters of the framework logger.
+type LogStats struct {
+	// Level is the current log level of the app.
+	Level string `json:"level"`
+	// Dropped is the number of log entries which were dropped by the sampling.
+	Dropped uint64 `json:"dropped"`
+}
+
+// logSampler limits the number of log entries with the same message per second.
+type logSampler struct {
+	mutex   sync.Mutex
+	max     int
+	window  int64
+	counts  map[string]int
+	dropped atomic.Uint64
+}
+
+func newLogSampler(limit int) *logSampler {
+	return &logSampler{
+		max:    limit,
+		counts: make(map[string]int),
+	}
+}
+
+// allow reports if an entry with the given message can be logged in the current second.
+func (s *logSampler) allow(msg string) bool {
+	if s.max <= 0 {
+		return true
+	}
+
+	now := time.Now().Unix()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if now != s.window {
+		// messages are constant, so the map stays small
+		clear(s.counts)
+		s.window = now
+	}
+	if s.counts[msg] >= s.max {
+		s.dropped.Add(1)
+		return false
+	}
+	s.counts[msg]++
+
+	return true
+}
+
+// SetLogLevel sets the minimum level of the log entries which are written
+// by the framework. It is safe to call while the app serves requests.
+func (app *App) SetLogLevel(level log.Level) {
+	app.logLevel.Store(int32(level))
+}
+
+// LogLevel returns the minimum level of the log entries which are written by the framework.
+func (app *App) LogLevel() log.Level {
+	return log.Level(app.logLevel.Load())
+}
+
+// LogStats returns the current level and the number of sampled log entries.
+func (app *App) LogStats() LogStats {
+	return LogStats{
+		Level:   app.LogLevel().String(),
+		Dropped: app.logSampler.dropped.Load(),
+	}
+}
+
+// SetLogLevelOnSignal switches the log level of the app to the given level when
+// one of the signals is received, the next signal switches back to the previous
+// level. The returned function stops listening for the signals.
+//
+//	stop := app.SetLogLevelOnSignal(log.LevelDebug, syscall.SIGUSR1)
+//	defer stop()
+func (app *App) SetLogLevelOnSignal(level log.Level, sig ...os.Signal) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sig...)
+
+	done := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+
+	go func() {
+		prev := app.LogLevel()
+		toggled := false
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				if toggled {
+					app.SetLogLevel(prev)
+				} else {
+					prev = app.LogLevel()
+					app.SetLogLevel(level)
+				}
+				toggled = !toggled
+				app.logw(log.LevelInfo, "log: level changed", "level", app.LogLevel().String())
+			}
+		}
+	}()
+
+	return stop
+}
+
+// ReopenLogs reopens the AccessLog and the ErrorLog which support it, like log.File,
+// e.g. after they were moved by logrotate.
+func (app *App) ReopenLogs() error {
+	writers := []io.Writer{app.config.AccessLog}
+	if app.config.ErrorLog != app.config.AccessLog {
+		writers = append(writers, app.config.ErrorLog)
+	}
+
+	var errs []error
+	for _, w := range writers {
+		if r, ok := w.(interface{ Reopen() error }); ok {
+			errs = append(errs, r.Reopen())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReopenLogsOnSignal reopens the logs with ReopenLogs when one of the signals is received,
+// e.g. SIGUSR1 of the postrotate script of logrotate. The returned function stops listening for the signals.
+//
+//	stop := app.ReopenLogsOnSignal(syscall.SIGUSR1)
+//	defer stop()
+func (app *App) ReopenLogsOnSignal(sig ...os.Signal) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sig...)
+
+	done := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				if err := app.ReopenLogs(); err != nil {
+					app.logw(log.LevelError, "log: failed to reopen the logs", "error", err)
+				}
+			}
+		}
+	}()
+
+	return stop
+}
+
+// logw writes a structured log entry of the framework to the logger of the app.
+// Entries with a lower level than the app's log level and sampled entries are dropped.
+// The framework never exits the process, fatal and panic entries are written as errors.
+func (app *App) logw(level log.Level, msg string, keysAndValues ...any) {
+	if level < app.LogLevel() || !app.logSampler.allow(msg) {
+		return
+	}
+
+	logger := app.config.Logger
+	if logger == nil {
+		logger = log.DefaultLogger()
+	}
+
+	switch level {
+	case log.LevelTrace:
+		logger.Tracew(msg, keysAndValues...)
+	case log.LevelDebug:
+		logger.Debugw(msg, keysAndValues...)
+	case log.LevelInfo:
+		logger.Infow(msg, keysAndValues...)
+	case log.LevelWarn:
+		logger.Warnw(msg, keysAndValues...)
+	default:
+		logger.Errorw(msg, keysAndValues...)
+	}
+}
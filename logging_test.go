@@ -0,0 +1,177 @@
+package fiber
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/stretchr/testify/require"
+)
+
+// testLogger records the structured log entries of the framework.
+type testLogger struct {
+	log.CommonLogger
+	mutex   sync.Mutex
+	entries []string
+}
+
+func (l *testLogger) record(level log.Level, msg string, keysAndValues []any) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries = append(l.entries, fmt.Sprintf("%s %s %v", level, msg, keysAndValues))
+}
+
+func (l *testLogger) Entries() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return append([]string(nil), l.entries...)
+}
+
+func (l *testLogger) Tracew(msg string, kv ...any) { l.record(log.LevelTrace, msg, kv) }
+func (l *testLogger) Debugw(msg string, kv ...any) { l.record(log.LevelDebug, msg, kv) }
+func (l *testLogger) Infow(msg string, kv ...any)  { l.record(log.LevelInfo, msg, kv) }
+func (l *testLogger) Warnw(msg string, kv ...any)  { l.record(log.LevelWarn, msg, kv) }
+func (l *testLogger) Errorw(msg string, kv ...any) { l.record(log.LevelError, msg, kv) }
+
+// go test -run Test_App_Logw
+func Test_App_Logw(t *testing.T) {
+	t.Parallel()
+	logger := &testLogger{}
+	app := New(Config{Logger: logger, LogLevel: log.LevelInfo})
+
+	app.logw(log.LevelDebug, "hidden")
+	app.logw(log.LevelInfo, "shown", "key", "value")
+	// the framework never exits the process
+	app.logw(log.LevelFatal, "fatal")
+	require.Equal(t, []string{"info shown [key value]", "error fatal []"}, logger.Entries())
+
+	app.SetLogLevel(log.LevelError)
+	require.Equal(t, log.LevelError, app.LogLevel())
+	app.logw(log.LevelWarn, "hidden")
+	require.Len(t, logger.Entries(), 2)
+	require.Equal(t, "error", app.LogStats().Level)
+}
+
+// go test -run Test_App_LogSampling
+func Test_App_LogSampling(t *testing.T) {
+	t.Parallel()
+	logger := &testLogger{}
+	app := New(Config{Logger: logger, LogSampling: 2})
+
+	for i := 0; i < 5; i++ {
+		app.logw(log.LevelError, "repeated")
+	}
+	app.logw(log.LevelError, "other")
+
+	// the window of the sampling can move during the loop
+	dropped := app.LogStats().Dropped
+	require.GreaterOrEqual(t, dropped, uint64(1))
+	require.Len(t, logger.Entries(), 6-int(dropped))
+}
+
+// go test -run Test_App_LogHooks
+func Test_App_LogHooks(t *testing.T) {
+	t.Parallel()
+	logger := &testLogger{}
+	app := New(Config{Logger: logger})
+
+	app.Hooks().OnShutdown(func() error {
+		return errors.New("hook failed")
+	})
+	app.hooks.executeOnShutdownHooks()
+
+	require.Equal(t, []string{"error failed to call shutdown hook [error hook failed]"}, logger.Entries())
+}
+
+// go test -run Test_App_LogPanic
+func Test_App_LogPanic(t *testing.T) {
+	t.Parallel()
+	logger := &testLogger{}
+	app := New(Config{Logger: logger, PanicPolicy: PanicPolicyErrorHandler})
+
+	app.Get("/", func(Ctx) error {
+		panic("boom")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+	require.Equal(t, []string{"error recovered from panic [panic boom method GET path /]"}, logger.Entries())
+}
+
+// go test -run Test_App_SetLogLevelOnSignal
+func Test_App_SetLogLevelOnSignal(t *testing.T) { //nolint:paralleltest // sends a signal to the process
+	if runtime.GOOS == "windows" {
+		t.Skip("signals can't be sent to the own process on windows")
+	}
+	logger := &testLogger{}
+	app := New(Config{Logger: logger, LogLevel: log.LevelWarn})
+
+	stop := app.SetLogLevelOnSignal(log.LevelDebug, os.Interrupt)
+	defer stop()
+
+	proc, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+
+	require.NoError(t, proc.Signal(os.Interrupt))
+	require.Eventually(t, func() bool {
+		return app.LogLevel() == log.LevelDebug
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, proc.Signal(os.Interrupt))
+	require.Eventually(t, func() bool {
+		return app.LogLevel() == log.LevelWarn
+	}, time.Second, 10*time.Millisecond)
+}
+
+// go test -run Test_App_ErrorLog
+func Test_App_ErrorLog(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "error.log")
+	errorLog, err := log.OpenFile(log.FileConfig{Path: path})
+	require.NoError(t, err)
+	defer errorLog.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	app := New(Config{ErrorLog: errorLog})
+	require.NotNil(t, app.config.Logger)
+	app.logw(log.LevelWarn, "shutdown: failed", "error", "timeout")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "[Warn] shutdown: failed error=timeout")
+}
+
+// go test -run Test_App_ReopenLogsOnSignal
+func Test_App_ReopenLogsOnSignal(t *testing.T) { //nolint:paralleltest // sends a signal to the process
+	if runtime.GOOS == "windows" {
+		t.Skip("signals can't be sent to the own process on windows")
+	}
+	path := filepath.Join(t.TempDir(), "access.log")
+	accessLog, err := log.OpenFile(log.FileConfig{Path: path})
+	require.NoError(t, err)
+	defer accessLog.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	app := New(Config{AccessLog: accessLog, ErrorLog: accessLog})
+	stop := app.ReopenLogsOnSignal(os.Interrupt)
+	defer stop()
+
+	// logrotate moves the file and signals the app
+	require.NoError(t, os.Rename(path, path+".1"))
+	proc, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, proc.Signal(os.Interrupt))
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, accessLog.Close())
+	require.ErrorIs(t, app.ReopenLogs(), log.ErrFileClosed)
+}
@@ -0,0 +1,131 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// MemoryListener is a net.Listener whose connections are created in-process by Dial,
+// so an app can be served to other Go code, e.g. tests, desktop apps or serverless
+// adapters, without TCP. The connections are synchronous in-memory pipes.
+//
+//	ln := fiber.NewMemoryListener()
+//	go app.Listener(ln, fiber.ListenConfig{DisableStartupMessage: true})
+//
+//	client := &http.Client{Transport: ln.Transport()}
+//	resp, err := client.Get("http://app/users")
+type MemoryListener struct {
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewMemoryListener creates a MemoryListener.
+func NewMemoryListener() *MemoryListener {
+	return &MemoryListener{
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+}
+
+// Accept waits for the next connection created by Dial.
+// net.ErrClosed is returned when the listener is closed.
+func (l *MemoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the listener, the accepted connections stay open.
+func (l *MemoryListener) Close() error {
+	l.once.Do(func() {
+		close(l.done)
+	})
+	return nil
+}
+
+// Addr returns the address of the listener, its network and address are "memory".
+func (*MemoryListener) Addr() net.Addr {
+	return memoryAddr{}
+}
+
+// Dial creates a connection to the listener, it blocks until the connection is accepted.
+// net.ErrClosed is returned if the listener is closed.
+func (l *MemoryListener) Dial() (net.Conn, error) {
+	return l.DialContext(context.Background(), "", "")
+}
+
+// DialContext creates a connection to the listener like Dial, the network and the address are ignored.
+// It has the signature of net.Dialer.DialContext, so it can be used to dial the listener with other clients.
+func (l *MemoryListener) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		_ = server.Close() //nolint:errcheck // closing a pipe doesn't fail
+		_ = client.Close() //nolint:errcheck // closing a pipe doesn't fail
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		_ = server.Close() //nolint:errcheck // closing a pipe doesn't fail
+		_ = client.Close() //nolint:errcheck // closing a pipe doesn't fail
+		return nil, ctx.Err()
+	}
+}
+
+// Transport returns an http.Transport which sends the requests to the listener,
+// the host of the request URLs is ignored. Use "http" URLs, unless the app serves TLS.
+func (l *MemoryListener) Transport() *http.Transport {
+	return &http.Transport{DialContext: l.DialContext}
+}
+
+// memoryAddr is the address of a MemoryListener.
+type memoryAddr struct{}
+
+// Network returns the network of the address.
+func (memoryAddr) Network() string {
+	return "memory"
+}
+
+// String returns the address.
+func (memoryAddr) String() string {
+	return "memory"
+}
+
+// Transport returns an http.Transport which serves the requests in-process by the app, like Handler
+// without a listener, so other Go code can invoke the full middleware pipeline without TCP. Each
+// connection of the transport is an in-memory pipe which is served like a connection of a listener,
+// the host of the request URLs is ignored. Unlike Test, the responses are streamed and connections
+// are kept alive. Like with Listen, the routes must be registered before the first request. Use a
+// MemoryListener to run the app with the lifecycle of a listener instead.
+//
+//	client := &http.Client{Transport: app.Transport()}
+//	resp, err := client.Get("http://app/users")
+func (app *App) Transport() *http.Transport {
+	return &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			// prepare the server for the start
+			app.startupProcess()
+
+			server, client := net.Pipe()
+			go func() {
+				defer server.Close()             //nolint:errcheck // closing a pipe doesn't fail
+				_ = app.server.ServeConn(server) //nolint:errcheck // the client sees the closed connection
+			}()
+			return client, nil
+		},
+	}
+}
@@ -0,0 +1,166 @@
+package fiber
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testMemoryGet(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+	resp, err := client.Get(url) //nolint:noctx // the request is served in-process
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // It is fine to ignore the error here
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+// go test -run Test_App_Transport
+func Test_App_Transport(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Use(func(c Ctx) error {
+		c.Set("X-Middleware", "1")
+		return c.Next()
+	})
+	app.Get("/users/:id", func(c Ctx) error {
+		return c.SendString("user " + c.Params("id"))
+	})
+	app.Post("/echo", func(c Ctx) error {
+		return c.Send(c.Body())
+	})
+
+	transport := app.Transport()
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get("http://app/users/1") //nolint:noctx // the request is served in-process
+	require.NoError(t, err)
+	require.Equal(t, "1", resp.Header.Get("X-Middleware"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "user 1", string(body))
+
+	resp, err = client.Post("http://app/echo", MIMETextPlain, strings.NewReader("hello")) //nolint:noctx // the request is served in-process
+	require.NoError(t, err)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "hello", string(body))
+}
+
+// go test -run Test_App_Transport_Stream
+func Test_App_Transport_Stream(t *testing.T) {
+	t.Parallel()
+	app := New()
+	next := make(chan struct{})
+	app.Get("/stream", func(c Ctx) error {
+		c.Response().SetBodyStreamWriter(func(w *bufio.Writer) {
+			_, _ = w.WriteString("first\n") //nolint:errcheck // the test reads the response
+			_ = w.Flush()                   //nolint:errcheck // the test reads the response
+			<-next
+			_, _ = w.WriteString("second\n") //nolint:errcheck // the test reads the response
+		})
+		return nil
+	})
+
+	transport := app.Transport()
+	defer transport.CloseIdleConnections()
+	resp, err := (&http.Client{Transport: transport}).Get("http://app/stream") //nolint:noctx // the request is served in-process
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	// the first chunk arrives before the handler finished
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "first\n", line)
+	close(next)
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "second\n", string(rest))
+}
+
+// go test -run Test_MemoryListener
+func Test_MemoryListener(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.SendString("memory")
+	})
+
+	ln := NewMemoryListener()
+	require.Equal(t, "memory", ln.Addr().Network())
+	require.Equal(t, "memory", ln.Addr().String())
+
+	served := make(chan error, 1)
+	go func() {
+		served <- app.Listener(ln, ListenConfig{DisableStartupMessage: true})
+	}()
+
+	transport := ln.Transport()
+	client := &http.Client{Transport: transport}
+	for i := 0; i < 3; i++ {
+		status, body := testMemoryGet(t, client, "http://app/")
+		require.Equal(t, StatusOK, status)
+		require.Equal(t, "memory", body)
+	}
+	transport.CloseIdleConnections()
+
+	require.NoError(t, app.Shutdown())
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("the app didn't stop serving the listener")
+	}
+
+	_, err := ln.Dial()
+	require.ErrorIs(t, err, net.ErrClosed)
+	_, err = ln.Accept()
+	require.ErrorIs(t, err, net.ErrClosed)
+}
+
+// go test -run Test_MemoryListener_DialContext
+func Test_MemoryListener_DialContext(t *testing.T) {
+	t.Parallel()
+	ln := NewMemoryListener()
+	defer ln.Close() //nolint:errcheck // closing the listener doesn't fail
+
+	// nobody accepts the connection
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := ln.DialContext(ctx, "tcp", "app:80")
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+	client, err := ln.Dial()
+	require.NoError(t, err)
+	server := <-accepted
+
+	go func() {
+		_, _ = client.Write([]byte("ping")) //nolint:errcheck // the test reads the data
+	}()
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(server, buf)
+	require.NoError(t, err)
+	require.Equal(t, "ping", string(buf))
+	require.NoError(t, client.Close())
+	require.NoError(t, server.Close())
+}
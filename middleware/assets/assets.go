@@ -0,0 +1,58 @@
+package assets
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/filesystem"
+)
+
+// New creates a new middleware handler which serves the files of the manifest. Fingerprinted
+// files are served with immutable cache headers, the files under their own names are
+// revalidated on every request.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	if cfg.Manifest == nil {
+		panic("assets: a manifest is required")
+	}
+
+	manifest := cfg.Manifest
+	immutable := "public, max-age=" + strconv.Itoa(cfg.MaxAge) + ", immutable"
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		// We only serve static assets on GET or HEAD methods
+		method := c.Method()
+		if method != fiber.MethodGet && method != fiber.MethodHead {
+			return c.Next()
+		}
+
+		name, ok := strings.CutPrefix(c.Path(), manifest.prefix+"/")
+		if !ok {
+			return c.Next()
+		}
+
+		cacheControl := immutable
+		if original, ok := manifest.names[name]; ok {
+			name = original
+		} else if _, ok := manifest.hashed[name]; ok {
+			cacheControl = "no-cache"
+		} else {
+			return c.Next()
+		}
+
+		if err := filesystem.SendFile(c, manifest.root, name); err != nil {
+			return err //nolint:wrapcheck // the error is returned as is
+		}
+		c.Set(fiber.HeaderCacheControl, cacheControl)
+		return nil
+	}
+}
@@ -0,0 +1,155 @@
+package assets
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func testManifest(t *testing.T) *Manifest {
+	t.Helper()
+	manifest, err := Fingerprint(fstest.MapFS{
+		"js/app.js":     {Data: []byte("console.log('app')")},
+		"css/app.css":   {Data: []byte("body { margin: 0 }")},
+		"robots.txt":    {Data: []byte("User-agent: *")},
+		".env":          {Data: []byte("SECRET=1")},
+		".git/HEAD":     {Data: []byte("ref: refs/heads/main")},
+		"img/empty.svg": {Data: []byte("<svg/>")},
+	}, "/assets")
+	require.NoError(t, err)
+	return manifest
+}
+
+// go test -run Test_Fingerprint
+func Test_Fingerprint(t *testing.T) {
+	t.Parallel()
+	manifest := testManifest(t)
+
+	files := manifest.Files()
+	require.Len(t, files, 4)
+	require.Regexp(t, `^js/app\.[0-9a-f]{10}\.js$`, files["js/app.js"])
+	require.Regexp(t, `^css/app\.[0-9a-f]{10}\.css$`, files["css/app.css"])
+	require.NotContains(t, files, ".env")
+	require.NotContains(t, files, ".git/HEAD")
+
+	require.Equal(t, "/assets/"+files["js/app.js"], manifest.URL("js/app.js"))
+	require.Equal(t, "/assets/"+files["js/app.js"], manifest.URL("/js/app.js"))
+	require.Equal(t, "/assets/missing.js", manifest.URL("missing.js"))
+
+	// the hash changes with the content
+	changed, err := Fingerprint(fstest.MapFS{"js/app.js": {Data: []byte("console.log('v2')")}}, "assets/")
+	require.NoError(t, err)
+	require.NotEqual(t, manifest.URL("js/app.js"), changed.URL("js/app.js"))
+	require.True(t, strings.HasPrefix(changed.URL("js/app.js"), "/assets/js/app."))
+}
+
+// go test -run Test_Manifest_WriteFile
+func Test_Manifest_WriteFile(t *testing.T) {
+	t.Parallel()
+	manifest := testManifest(t)
+
+	filename := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, manifest.WriteFile(filename))
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	var files map[string]string
+	require.NoError(t, json.Unmarshal(data, &files))
+	require.Equal(t, manifest.Files(), files)
+}
+
+// go test -run Test_Manifest_FuncMap
+func Test_Manifest_FuncMap(t *testing.T) {
+	t.Parallel()
+	manifest := testManifest(t)
+
+	tmpl := template.Must(template.New("").Funcs(manifest.FuncMap()).Parse(`<script src="{{ asset "js/app.js" }}"></script>`))
+	var sb strings.Builder
+	require.NoError(t, tmpl.Execute(&sb, nil))
+	require.Equal(t, `<script src="`+manifest.URL("js/app.js")+`"></script>`, sb.String())
+}
+
+// go test -run Test_Assets
+func Test_Assets(t *testing.T) {
+	t.Parallel()
+	manifest := testManifest(t)
+	app := fiber.New()
+	app.Use("/assets", New(Config{Manifest: manifest}))
+
+	// fingerprinted files are immutable
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, manifest.URL("js/app.js"), nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "public, max-age=31536000, immutable", resp.Header.Get(fiber.HeaderCacheControl))
+	require.Contains(t, resp.Header.Get(fiber.HeaderContentType), "javascript")
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "console.log('app')", string(body))
+
+	// files under their own names are revalidated
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/assets/css/app.css", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "no-cache", resp.Header.Get(fiber.HeaderCacheControl))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodHead, manifest.URL("robots.txt"), nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	for _, path := range []string{"/assets/js/app.0000000000.js", "/assets/.env", "/assets/.git/HEAD"} {
+		resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusNotFound, resp.StatusCode, path)
+	}
+
+}
+
+// go test -run Test_Assets_Method
+func Test_Assets_Method(t *testing.T) {
+	t.Parallel()
+	manifest := testManifest(t)
+	app := fiber.New()
+	app.Use(New(Config{Manifest: manifest}))
+	app.Post("/assets/*", func(c fiber.Ctx) error {
+		return c.SendString("next")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, manifest.URL("js/app.js"), nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "next", string(body))
+}
+
+// go test -run Test_Assets_Next
+func Test_Assets_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Manifest: testManifest(t),
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/assets/js/app.js", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// go test -run Test_Assets_NoManifest
+func Test_Assets_NoManifest(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New()
+	})
+}
@@ -0,0 +1,48 @@
+package assets
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Manifest contains the fingerprinted files. It is required.
+	//
+	// Required. Default: nil
+	Manifest *Manifest
+
+	// MaxAge is the max-age of the Cache-Control header of fingerprinted
+	// files in seconds, they are immutable.
+	//
+	// Optional. Default: 31536000 (1 year)
+	MaxAge int
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:     nil,
+	Manifest: nil,
+	MaxAge:   365 * 24 * 60 * 60,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = ConfigDefault.MaxAge
+	}
+	return cfg
+}
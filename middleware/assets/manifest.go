@@ -0,0 +1,140 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// hashLength is the number of hex digits of the content hash in the file names.
+const hashLength = 10
+
+// Manifest maps the names of the files of a filesystem to their fingerprinted names,
+// which contain the hash of their content, e.g. "js/app.js" to "js/app.3f2a1b9c0d.js".
+// The URL of a file changes with its content, so it can be cached forever.
+type Manifest struct {
+	root   fs.FS
+	prefix string
+	hashed map[string]string // name -> fingerprinted name
+	names  map[string]string // fingerprinted name -> name
+}
+
+// Fingerprint hashes the files of the filesystem and returns their manifest. The prefix
+// is the path the middleware is mounted at, e.g. "/assets". Hidden files and
+// directories, whose names start with a dot, are skipped.
+func Fingerprint(root fs.FS, prefix string) (*Manifest, error) {
+	m := &Manifest{
+		root:   root,
+		prefix: strings.TrimRight(prefix, "/"),
+		hashed: make(map[string]string),
+		names:  make(map[string]string),
+	}
+	if m.prefix != "" && !strings.HasPrefix(m.prefix, "/") {
+		m.prefix = "/" + m.prefix
+	}
+
+	err := fs.WalkDir(root, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name != "." && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		hash, err := hashFile(root, name)
+		if err != nil {
+			return err
+		}
+		ext := path.Ext(name)
+		hashed := strings.TrimSuffix(name, ext) + "." + hash + ext
+		m.hashed[name] = hashed
+		m.names[hashed] = name
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("assets: failed to fingerprint files: %w", err)
+	}
+	return m, nil
+}
+
+// hashFile returns the truncated SHA-256 hash of the content of the file.
+func hashFile(root fs.FS, name string) (string, error) {
+	file, err := root.Open(name)
+	if err != nil {
+		return "", err //nolint:wrapcheck // the error is wrapped by Fingerprint
+	}
+	defer file.Close() //nolint:errcheck // the file is only read
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:hashLength], nil
+}
+
+// URL returns the URL of the fingerprinted file, e.g. "/assets/js/app.3f2a1b9c0d.js" for
+// "js/app.js". The URL of the file itself is returned if it is not in the manifest.
+func (m *Manifest) URL(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if hashed, ok := m.hashed[name]; ok {
+		return m.prefix + "/" + hashed
+	}
+	return m.prefix + "/" + name
+}
+
+// Files returns a copy of the names of the files mapped to their fingerprinted names.
+func (m *Manifest) Files() map[string]string {
+	files := make(map[string]string, len(m.hashed))
+	for name, hashed := range m.hashed {
+		files[name] = hashed
+	}
+	return files
+}
+
+// MarshalJSON encodes the manifest as object of the names of the files and their
+// fingerprinted names, like the manifests of frontend bundlers.
+//
+//	{"css/app.css": "css/app.9c1f0e2d4b.css", "js/app.js": "js/app.3f2a1b9c0d.js"}
+func (m *Manifest) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(m.hashed)
+	if err != nil {
+		return nil, fmt.Errorf("assets: failed to encode manifest: %w", err)
+	}
+	return data, nil
+}
+
+// WriteFile writes the manifest as JSON into the file, e.g. for a CDN upload or other tools.
+func (m *Manifest) WriteFile(filename string) error {
+	data, err := m.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	//nolint:gosec // the manifest is public
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("assets: failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// FuncMap returns the template functions of the manifest, e.g. for the html template engine:
+//
+//	engine.AddFuncMap(manifest.FuncMap())
+//
+//	<script src="{{ asset "js/app.js" }}"></script>
+func (m *Manifest) FuncMap() map[string]any {
+	return map[string]any{
+		"asset": m.URL,
+	}
+}
@@ -0,0 +1,305 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// RedactedValue replaces the values of the redacted headers, query parameters and JSON fields.
+const RedactedValue = "[REDACTED]"
+
+// SignaturePurpose is the purpose of the signatures of the WebhookSink, see fiber.Keyring.Sign.
+const SignaturePurpose = "fiber/audit"
+
+var (
+	// ErrChainBroken is returned by Verify if a record was modified, removed or inserted.
+	ErrChainBroken = errors.New("audit: hash chain is broken")
+	// ErrQueueFull is passed to Config.OnDrop if a record is dropped because the queue of the sink is full.
+	ErrQueueFull = errors.New("audit: queue of the sink is full")
+)
+
+// Record is an audit record of a request. Each record contains the hash of the
+// previous record, so a modified, removed or inserted record breaks the chain.
+type Record struct {
+	// Sequence is the position of the record in the chain, starting at 1.
+	Sequence uint64 `json:"seq"`
+	// Time is the time when the request was received.
+	Time time.Time `json:"time"`
+	// Actor is who made the request, returned by Config.Actor.
+	Actor string `json:"actor,omitempty"`
+	// Action is what was done, returned by Config.Action.
+	Action string `json:"action"`
+	// IP is the remote address of the client.
+	IP string `json:"ip"`
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+	// Path is the path of the request.
+	Path string `json:"path"`
+	// Status is the status code of the response.
+	Status int `json:"status"`
+	// Query contains the query parameters of the request.
+	Query map[string]string `json:"query,omitempty"`
+	// Headers contains the request headers of Config.Headers.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the request body, if Config.Body is enabled.
+	Body string `json:"body,omitempty"`
+	// PrevHash is the hash of the previous record, empty for the first record.
+	PrevHash string `json:"prev_hash"`
+	// Hash is the SHA-256 hash of the previous hash and the record without its hash.
+	Hash string `json:"hash"`
+}
+
+// computeHash returns the hash of the record, which covers all fields except Hash.
+func (r Record) computeHash() (string, error) {
+	r.Hash = ""
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("audit: failed to encode record: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write([]byte(r.PrevHash))
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Verify checks the hash chain of the records, which must be ordered by their sequence.
+// The first record may continue a chain whose earlier records aren't passed.
+func Verify(records []Record) error {
+	for i, r := range records {
+		if i > 0 && (r.Sequence != records[i-1].Sequence+1 || r.PrevHash != records[i-1].Hash) {
+			return fmt.Errorf("%w: record %d doesn't follow record %d", ErrChainBroken, r.Sequence, records[i-1].Sequence)
+		}
+		hash, err := r.computeHash()
+		if err != nil {
+			return err
+		}
+		if hash != r.Hash {
+			return fmt.Errorf("%w: record %d was modified", ErrChainBroken, r.Sequence)
+		}
+	}
+
+	return nil
+}
+
+// queuedRecord is a record in the queue of the writer, or a marker which is closed
+// when the records before it were written.
+type queuedRecord struct {
+	record  Record
+	flushed chan struct{}
+}
+
+// chain links the records by their hashes and hands them to the writer of the sink,
+// so the requests don't wait for the sink.
+type chain struct {
+	mutex    sync.Mutex
+	cfg      Config
+	queue    chan queuedRecord
+	sequence uint64
+	lastHash string
+}
+
+// newChain creates a chain and starts the writer of the sink.
+func newChain(cfg Config) *chain {
+	ch := &chain{
+		cfg:   cfg,
+		queue: make(chan queuedRecord, cfg.QueueSize),
+	}
+	go ch.write()
+	return ch
+}
+
+// append sets the sequence and the hashes of the record and queues it for the sink.
+// The record is dropped if the queue is full.
+func (ch *chain) append(r *Record) error {
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+
+	r.Sequence = ch.sequence + 1
+	r.PrevHash = ch.lastHash
+	hash, err := r.computeHash()
+	if err != nil {
+		return err
+	}
+	r.Hash = hash
+	ch.sequence = r.Sequence
+	ch.lastHash = r.Hash
+
+	// the records are queued under the lock, so they are written in the order of the chain
+	select {
+	case ch.queue <- queuedRecord{record: *r}:
+	default:
+		ch.cfg.OnDrop(*r, ErrQueueFull)
+	}
+
+	return nil
+}
+
+// write writes the queued records to the sink, a record is retried with an exponential
+// backoff and dropped if it can't be written.
+func (ch *chain) write() {
+	for queued := range ch.queue {
+		if queued.flushed != nil {
+			close(queued.flushed)
+			continue
+		}
+
+		interval := ch.cfg.RetryInterval
+		err := ch.cfg.Sink.Write(queued.record)
+		for retry := 0; err != nil && retry < ch.cfg.Retries; retry++ {
+			time.Sleep(interval)
+			interval *= 2
+			err = ch.cfg.Sink.Write(queued.record)
+		}
+		if err != nil {
+			ch.cfg.OnDrop(queued.record, fmt.Errorf("audit: failed to write record %d: %w", queued.record.Sequence, err))
+		}
+	}
+}
+
+// flush waits until the records which were queued before were written or dropped.
+func (ch *chain) flush() {
+	flushed := make(chan struct{})
+	ch.queue <- queuedRecord{flushed: flushed}
+	<-flushed
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	redact := make(map[string]struct{}, len(cfg.Redact))
+	for _, name := range cfg.Redact {
+		redact[strings.ToLower(name)] = struct{}{}
+	}
+
+	// continue the chain of the sink
+	ch := newChain(cfg)
+	if tailer, ok := cfg.Sink.(Tailer); ok {
+		last, err := tailer.Last()
+		if err != nil {
+			panic(fmt.Sprintf("fiber: audit middleware failed to read the last record: %v", err))
+		}
+		if last != nil {
+			ch.sequence = last.Sequence
+			ch.lastHash = last.Hash
+		}
+	}
+
+	var once sync.Once
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		// write the queued records before the app stops
+		once.Do(func() {
+			c.App().Hooks().OnShutdown(func() error {
+				ch.flush()
+				return nil
+			})
+		})
+
+		// copy the values, the sinks may keep the records
+		record := Record{
+			Time:   time.Now().UTC(),
+			IP:     utils.CopyString(c.IP()),
+			Method: utils.CopyString(c.Method()),
+			Path:   utils.CopyString(c.Path()),
+		}
+		if len(cfg.Headers) > 0 {
+			record.Headers = make(map[string]string, len(cfg.Headers))
+			for _, name := range cfg.Headers {
+				if value := c.Get(name); value != "" {
+					record.Headers[name] = utils.CopyString(redactValue(redact, name, value))
+				}
+			}
+		}
+		c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+			if record.Query == nil {
+				record.Query = make(map[string]string)
+			}
+			record.Query[string(key)] = redactValue(redact, string(key), string(value))
+		})
+		if cfg.Body {
+			record.Body = redactBody(redact, c.Body())
+		}
+
+		// Handle request, the status is set by the error handler
+		chainErr := c.Next()
+		if chainErr != nil {
+			if err := c.App().ErrorHandler(c, chainErr); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
+			}
+		}
+
+		// the actor can be set by the handlers, e.g. after a login
+		if cfg.Actor != nil {
+			record.Actor = utils.CopyString(cfg.Actor(c))
+		}
+		record.Action = utils.CopyString(cfg.Action(c))
+		record.Status = c.Response().StatusCode()
+
+		if err := ch.append(&record); err != nil {
+			log.Errorw("audit: failed to append record", "action", record.Action, "error", err)
+		}
+
+		return nil
+	}
+}
+
+// redactValue returns the value, or RedactedValue if the name is redacted.
+func redactValue(redact map[string]struct{}, name, value string) string {
+	if _, ok := redact[strings.ToLower(name)]; ok {
+		return RedactedValue
+	}
+	return value
+}
+
+// redactBody returns the body with the redacted fields of a JSON body replaced.
+// Other bodies are returned unchanged.
+func redactBody(redact map[string]struct{}, body []byte) string {
+	var value any
+	if len(body) == 0 || json.Unmarshal(body, &value) != nil {
+		return string(body)
+	}
+
+	b, err := json.Marshal(redactJSON(redact, value))
+	if err != nil {
+		return string(body)
+	}
+	return string(b)
+}
+
+// redactJSON replaces the values of the redacted fields in the decoded JSON value.
+func redactJSON(redact map[string]struct{}, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if _, ok := redact[strings.ToLower(key)]; ok {
+				v[key] = RedactedValue
+			} else {
+				v[key] = redactJSON(redact, field)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactJSON(redact, item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,244 @@
+package audit
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySink keeps the records in memory.
+type memorySink struct {
+	mutex   sync.Mutex
+	records []Record
+}
+
+func (s *memorySink) Write(record Record) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *memorySink) Records() []Record {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Record(nil), s.records...)
+}
+
+// go test -run Test_Audit
+func Test_Audit(t *testing.T) {
+	t.Parallel()
+	sink := &memorySink{}
+	app := fiber.New()
+
+	app.Use(New(Config{
+		Sink: sink,
+		Actor: func(c fiber.Ctx) string {
+			return c.Get("X-User")
+		},
+		Headers: []string{"X-User", fiber.HeaderAuthorization},
+		Body:    true,
+	}))
+	app.Post("/users/:id", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+	app.Delete("/users/:id", func(_ fiber.Ctx) error {
+		return fiber.ErrForbidden
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/users/1?token=abc&page=2", strings.NewReader(`{"name":"john","password":"secret","keys":[{"secret":"x"}]}`))
+	req.Header.Set("X-User", "admin")
+	req.Header.Set(fiber.HeaderAuthorization, "Basic YWRtaW46c2VjcmV0")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodDelete, "/users/1", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	// the queued records are written on shutdown
+	require.NoError(t, app.Shutdown())
+	records := sink.Records()
+	require.Len(t, records, 2)
+
+	record := records[0]
+	require.Equal(t, uint64(1), record.Sequence)
+	require.Equal(t, "admin", record.Actor)
+	require.Equal(t, "POST /users/:id", record.Action)
+	require.Equal(t, "/users/1", record.Path)
+	require.Equal(t, fiber.StatusCreated, record.Status)
+	require.Equal(t, map[string]string{"token": RedactedValue, "page": "2"}, record.Query)
+	require.Equal(t, map[string]string{"X-User": "admin", fiber.HeaderAuthorization: RedactedValue}, record.Headers)
+	require.Equal(t, `{"keys":[{"secret":"[REDACTED]"}],"name":"john","password":"[REDACTED]"}`, record.Body)
+	require.Empty(t, record.PrevHash)
+
+	// the status of returned errors is set by the error handler
+	require.Equal(t, fiber.StatusForbidden, records[1].Status)
+	require.Equal(t, "DELETE /users/:id", records[1].Action)
+	require.Equal(t, record.Hash, records[1].PrevHash)
+
+	require.NoError(t, Verify(records))
+}
+
+// go test -run Test_Audit_Next
+func Test_Audit_Next(t *testing.T) {
+	t.Parallel()
+	sink := &memorySink{}
+	app := fiber.New()
+	app.Use(New(Config{
+		Sink: sink,
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	require.Empty(t, sink.Records())
+}
+
+// go test -run Test_Audit_RequiresSink
+func Test_Audit_RequiresSink(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New()
+	})
+	require.Panics(t, func() {
+		New(Config{})
+	})
+}
+
+// go test -run Test_Verify
+func Test_Verify(t *testing.T) {
+	t.Parallel()
+	sink := &memorySink{}
+	ch := newChain(configDefault(Config{Sink: sink}))
+	for _, action := range []string{"login", "update", "logout"} {
+		require.NoError(t, ch.append(&Record{Action: action}))
+	}
+	ch.flush()
+	records := sink.Records()
+	require.NoError(t, Verify(records))
+	// a part of the chain can be verified
+	require.NoError(t, Verify(records[1:]))
+
+	modified := append([]Record(nil), records...)
+	modified[1].Action = "delete"
+	require.ErrorIs(t, Verify(modified), ErrChainBroken)
+
+	removed := []Record{records[0], records[2]}
+	require.ErrorIs(t, Verify(removed), ErrChainBroken)
+
+	rehashed := append([]Record(nil), records...)
+	rehashed[1].Action = "delete"
+	hash, err := rehashed[1].computeHash()
+	require.NoError(t, err)
+	rehashed[1].Hash = hash
+	// the next record still contains the original hash
+	require.ErrorIs(t, Verify(rehashed), ErrChainBroken)
+}
+
+// failingSink fails to write the records until failures is zero.
+type failingSink struct {
+	memorySink
+	failures int
+}
+
+func (s *failingSink) Write(record Record) error {
+	s.mutex.Lock()
+	if s.failures != 0 {
+		s.failures--
+		s.mutex.Unlock()
+		return errors.New("sink unavailable")
+	}
+	s.mutex.Unlock()
+	return s.memorySink.Write(record)
+}
+
+// go test -run Test_Audit_Retries
+func Test_Audit_Retries(t *testing.T) {
+	t.Parallel()
+	var (
+		mutex   sync.Mutex
+		dropped []uint64
+	)
+	cfg := configDefault(Config{
+		Sink:          &failingSink{failures: 2},
+		RetryInterval: time.Millisecond,
+		Retries:       2,
+		OnDrop: func(record Record, err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			dropped = append(dropped, record.Sequence)
+			require.ErrorContains(t, err, "sink unavailable")
+		},
+	})
+
+	// the first record is written by the second retry
+	ch := newChain(cfg)
+	require.NoError(t, ch.append(&Record{Action: "login"}))
+	ch.flush()
+	require.Len(t, cfg.Sink.(*failingSink).Records(), 1) //nolint:forcetypeassert,errcheck // The sink is a failingSink
+
+	// the records which can't be written are dropped
+	cfg.Sink = &failingSink{failures: -1}
+	ch = newChain(cfg)
+	require.NoError(t, ch.append(&Record{Action: "login"}))
+	require.NoError(t, ch.append(&Record{Action: "logout"}))
+	ch.flush()
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Equal(t, []uint64{1, 2}, dropped)
+}
+
+// blockingSink blocks the writes until release is closed.
+type blockingSink struct {
+	memorySink
+	release chan struct{}
+}
+
+func (s *blockingSink) Write(record Record) error {
+	<-s.release
+	return s.memorySink.Write(record)
+}
+
+// go test -run Test_Audit_QueueFull
+func Test_Audit_QueueFull(t *testing.T) {
+	t.Parallel()
+	sink := &blockingSink{release: make(chan struct{})}
+	dropped := make(chan error, 10)
+	app := fiber.New()
+	app.Use(New(Config{
+		Sink:      sink,
+		QueueSize: 1,
+		OnDrop: func(_ Record, err error) {
+			dropped <- err
+		},
+	}))
+
+	// the requests don't wait for the sink, the records which don't fit into the queue are dropped
+	for i := 0; i < 5; i++ {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	}
+	close(sink.release)
+	require.NoError(t, app.Shutdown())
+	close(dropped)
+
+	count := 0
+	for err := range dropped {
+		require.ErrorIs(t, err, ErrQueueFull)
+		count++
+	}
+	require.NotZero(t, count)
+	require.Len(t, sink.Records(), 5-count)
+}
@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Sink stores the audit records. The records are written one after
+	// another in the order of the chain, by a goroutine in the background.
+	//
+	// Required. Default: nil
+	Sink Sink
+
+	// OnDrop is called for the records which are dropped, because the queue is
+	// full or the sink failed to write them after the retries. The chain has a gap
+	// afterwards, which is reported by Verify.
+	//
+	// Optional. Default: logs the dropped record as error
+	OnDrop func(record Record, err error)
+
+	// Actor returns who made the request, e.g. the username of the basicauth middleware.
+	//
+	// Optional. Default: nil
+	Actor func(c fiber.Ctx) string
+
+	// Action returns what was done, e.g. "user.delete".
+	//
+	// Optional. Default: the method and the route path, e.g. "DELETE /users/:id"
+	Action func(c fiber.Ctx) string
+
+	// Headers are the names of the request headers which are added to the records.
+	//
+	// Optional. Default: nil
+	Headers []string
+
+	// Body adds the request body to the records. Fields of JSON bodies are redacted.
+	//
+	// Optional. Default: false
+	Body bool
+
+	// Redact are the names of the headers, query parameters and JSON fields
+	// whose values are replaced by "[REDACTED]". The names are case-insensitive.
+	//
+	// Optional. Default: []string{"Authorization", "Cookie", "password", "secret", "token"}
+	Redact []string
+
+	// QueueSize is the maximum number of records which wait for the sink.
+	// The records are dropped while the queue is full.
+	//
+	// Optional. Default: 1024
+	QueueSize int
+
+	// Retries is the number of times a failed write of a record is retried.
+	// A negative value disables the retries.
+	//
+	// Optional. Default: 3
+	Retries int
+
+	// RetryInterval is the time before the first retry, it is doubled for each further retry.
+	//
+	// Optional. Default: 100 * time.Millisecond
+	RetryInterval time.Duration
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:          nil,
+	Sink:          nil,
+	OnDrop:        defaultOnDrop,
+	Actor:         nil,
+	Action:        defaultAction,
+	Redact:        []string{fiber.HeaderAuthorization, fiber.HeaderCookie, "password", "secret", "token"},
+	QueueSize:     1024,
+	Retries:       3,
+	RetryInterval: 100 * time.Millisecond,
+}
+
+// defaultOnDrop logs the dropped record.
+func defaultOnDrop(record Record, err error) {
+	log.Errorw("audit: dropped record", "seq", record.Sequence, "action", record.Action, "error", err)
+}
+
+// defaultAction returns the method and the path of the matched route.
+func defaultAction(c fiber.Ctx) string {
+	return c.Method() + " " + c.Route().Path
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// A sink is always required
+	if len(config) < 1 || config[0].Sink == nil {
+		panic("fiber: audit middleware requires a sink")
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Action == nil {
+		cfg.Action = ConfigDefault.Action
+	}
+	if cfg.Redact == nil {
+		cfg.Redact = ConfigDefault.Redact
+	}
+	if cfg.OnDrop == nil {
+		cfg.OnDrop = ConfigDefault.OnDrop
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = ConfigDefault.QueueSize
+	}
+	if cfg.Retries == 0 {
+		cfg.Retries = ConfigDefault.Retries
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = ConfigDefault.RetryInterval
+	}
+	return cfg
+}
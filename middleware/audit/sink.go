@@ -0,0 +1,241 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+)
+
+// Sink stores audit records. Write is called for one record at a time,
+// in the order of the chain.
+type Sink interface {
+	Write(record Record) error
+}
+
+// Tailer is implemented by sinks which can return their last record,
+// so the chain is continued after a restart.
+type Tailer interface {
+	// Last returns the last written record, or nil if the sink is empty.
+	Last() (*Record, error)
+}
+
+// FileSink appends the records as JSON lines to a file.
+type FileSink struct {
+	mutex sync.Mutex
+	file  *os.File
+	path  string
+}
+
+// NewFileSink opens or creates the file at the given path to append the records.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to open file: %w", err)
+	}
+
+	return &FileSink{file: file, path: path}, nil
+}
+
+// Write appends the record to the file and syncs it to the disk.
+func (s *FileSink) Write(record Record) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("audit: failed to encode record: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, err := s.file.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("audit: failed to write file: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("audit: failed to sync file: %w", err)
+	}
+
+	return nil
+}
+
+// Last returns the record of the last line of the file.
+func (s *FileSink) Last() (*Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	records, err := ReadFile(s.path)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return &records[len(records)-1], nil
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("audit: failed to close file: %w", err)
+	}
+	return nil
+}
+
+// ReadFile reads the records of a file which was written by a FileSink,
+// e.g. to check them with Verify.
+func ReadFile(path string) ([]Record, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to read file: %w", err)
+	}
+
+	var records []Record
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(nil, len(b)+1)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("audit: failed to decode record %d: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// StorageSink stores the records in a fiber.Storage. The records are stored
+// with the key prefix and their zero-padded sequence, the last record is also
+// stored with the key prefix and "last".
+type StorageSink struct {
+	Storage fiber.Storage
+	// Prefix of the keys.
+	//
+	// Optional. Default: "audit:"
+	Prefix string
+}
+
+// NewStorageSink creates a sink which stores the records in the storage.
+func NewStorageSink(storage fiber.Storage) *StorageSink {
+	return &StorageSink{Storage: storage, Prefix: "audit:"}
+}
+
+// Key returns the storage key of the record with the given sequence.
+func (s *StorageSink) Key(sequence uint64) string {
+	return fmt.Sprintf("%s%020d", s.Prefix, sequence)
+}
+
+// Write stores the record and updates the last record.
+func (s *StorageSink) Write(record Record) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("audit: failed to encode record: %w", err)
+	}
+	if err := s.Storage.Set(s.Key(record.Sequence), b, 0); err != nil {
+		return fmt.Errorf("audit: failed to store record: %w", err)
+	}
+	if err := s.Storage.Set(s.Prefix+"last", b, 0); err != nil {
+		return fmt.Errorf("audit: failed to store last record: %w", err)
+	}
+
+	return nil
+}
+
+// Last returns the last stored record.
+func (s *StorageSink) Last() (*Record, error) {
+	b, err := s.Storage.Get(s.Prefix + "last")
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to get last record: %w", err)
+	}
+	if b == nil {
+		return nil, nil //nolint:nilnil // The storage is empty
+	}
+
+	var record Record
+	if err := json.Unmarshal(b, &record); err != nil {
+		return nil, fmt.Errorf("audit: failed to decode last record: %w", err)
+	}
+	return &record, nil
+}
+
+// WebhookSink posts each record as JSON to a URL.
+type WebhookSink struct {
+	// URL which receives the records.
+	URL string
+	// Timeout of a request.
+	//
+	// Optional. Default: 5 * time.Second
+	Timeout time.Duration
+	// Client which sends the requests.
+	//
+	// Optional. Default: a new fasthttp.Client
+	Client *fasthttp.Client
+	// Keyring signs the body of the requests, the signature is sent in the
+	// SignatureHeader and can be verified by the receiver with Keyring.Verify and
+	// the SignaturePurpose.
+	//
+	// Optional. Default: nil
+	Keyring *fiber.Keyring
+	// SignatureHeader is the header of the signature.
+	//
+	// Optional. Default: "X-Signature"
+	SignatureHeader string
+}
+
+// NewWebhookSink creates a sink which posts the records to the URL.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		URL:     url,
+		Timeout: 5 * time.Second,
+		Client:  &fasthttp.Client{},
+	}
+}
+
+// Write posts the record, a response status other than 2xx is returned as error.
+func (s *WebhookSink) Write(record Record) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("audit: failed to encode record: %w", err)
+	}
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(s.URL)
+	req.Header.SetMethod(fiber.MethodPost)
+	req.Header.SetContentType(fiber.MIMEApplicationJSON)
+	req.SetBodyRaw(b)
+	if s.Keyring != nil {
+		header := s.SignatureHeader
+		if header == "" {
+			header = "X-Signature"
+		}
+		req.Header.Set(header, s.Keyring.Sign(SignaturePurpose, b))
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &fasthttp.Client{}
+	}
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	if err := client.DoTimeout(req, resp, timeout); err != nil {
+		return fmt.Errorf("audit: failed to post record: %w", err)
+	}
+	if status := resp.StatusCode(); status < fiber.StatusOK || status >= fiber.StatusMultipleChoices {
+		return fmt.Errorf("audit: webhook responded with status %s", strconv.Itoa(status))
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_FileSink
+func Test_FileSink(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	last, err := sink.Last()
+	require.NoError(t, err)
+	require.Nil(t, last)
+
+	app := fiber.New()
+	app.Use(New(Config{Sink: sink}))
+	for i := 0; i < 2; i++ {
+		_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Shutdown())
+	require.NoError(t, sink.Close())
+
+	// the chain is continued after a restart
+	sink, err = NewFileSink(path)
+	require.NoError(t, err)
+	app = fiber.New()
+	app.Use(New(Config{Sink: sink}))
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.NoError(t, app.Shutdown())
+	require.NoError(t, sink.Close())
+
+	records, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, uint64(3), records[2].Sequence)
+	require.NoError(t, Verify(records))
+}
+
+// go test -run Test_StorageSink
+func Test_StorageSink(t *testing.T) {
+	t.Parallel()
+	sink := NewStorageSink(memory.New())
+
+	ch := newChain(configDefault(Config{Sink: sink}))
+	require.NoError(t, ch.append(&Record{Action: "login"}))
+	require.NoError(t, ch.append(&Record{Action: "logout"}))
+	ch.flush()
+
+	last, err := sink.Last()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), last.Sequence)
+	require.Equal(t, "logout", last.Action)
+
+	b, err := sink.Storage.Get(sink.Key(1))
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"action":"login"`)
+	require.Equal(t, "audit:00000000000000000001", sink.Key(1))
+}
+
+// go test -run Test_WebhookSink
+func Test_WebhookSink(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan string, 1)
+	webhook := fiber.New()
+	webhook.Post("/audit", func(c fiber.Ctx) error {
+		received <- string(c.Body())
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	signatures := make(chan [2]string, 1)
+	webhook.Post("/signed", func(c fiber.Ctx) error {
+		signatures <- [2]string{string(c.Body()), c.Get("X-Audit-Signature")}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	webhook.Post("/fail", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusServiceUnavailable)
+	})
+
+	ln, err := net.Listen(fiber.NetworkTCP4, "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		assert.NoError(t, webhook.Listener(ln, fiber.ListenConfig{DisableStartupMessage: true}))
+	}()
+	defer func() {
+		require.NoError(t, webhook.Shutdown())
+	}()
+
+	sink := NewWebhookSink("http://" + ln.Addr().String() + "/audit")
+	require.NoError(t, sink.Write(Record{Sequence: 1, Action: "login"}))
+	require.Contains(t, <-received, `"action":"login"`)
+
+	keyring := fiber.NewKeyring(fiber.KeyringConfig{Keys: []fiber.Key{{ID: "v1", Value: []byte("secret")}}})
+	sink = NewWebhookSink("http://" + ln.Addr().String() + "/signed")
+	sink.Keyring = keyring
+	sink.SignatureHeader = "X-Audit-Signature"
+	require.NoError(t, sink.Write(Record{Sequence: 2, Action: "logout"}))
+	signed := <-signatures
+	require.True(t, strings.HasPrefix(signed[1], "v1:"))
+	require.True(t, keyring.Verify(SignaturePurpose, []byte(signed[0]), signed[1]))
+
+	sink = NewWebhookSink("http://" + ln.Addr().String() + "/fail")
+	require.ErrorContains(t, sink.Write(Record{Sequence: 1}), "status 503")
+}
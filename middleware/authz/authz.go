@@ -0,0 +1,186 @@
+package authz
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+// The keys for the values in context
+const (
+	decisionKey contextKey = iota
+)
+
+// Subject is an authenticated principal which is evaluated by the engine.
+// It is set in the locals with fiber.Config.PrincipalKey by an authentication middleware.
+type Subject struct {
+	// Attributes are the attributes of the subject for the conditions, e.g. "tenant".
+	Attributes map[string]any
+	// ID identifies the subject, e.g. the username.
+	ID string
+	// Roles are the assigned roles, the inherited roles are resolved by the policy.
+	Roles []string
+}
+
+// Decision is a decision of the engine.
+type Decision struct {
+	// Subject is the ID of the subject, or empty if the principal isn't a subject.
+	Subject string `json:"subject"`
+	// Reason explains the decision, e.g. `role "editor"`, "rule 2" or `missing permission "orders:write"`.
+	Reason string `json:"reason"`
+	// Permissions are the required permissions.
+	Permissions []string `json:"permissions"`
+	// Allowed reports whether the request was allowed.
+	Allowed bool `json:"allowed"`
+}
+
+// Engine decides the required permissions of the routes with the roles and
+// the attribute rules of a policy. It implements fiber.PolicyDecider.
+//
+//	app := fiber.New(fiber.Config{PolicyDecider: engine})
+//	app.Post("/orders", handler).Require("orders:write")
+type Engine struct {
+	policy    atomic.Pointer[compiledPolicy]
+	done      chan struct{}
+	cfg       Config
+	closeOnce sync.Once
+	// mutex serializes the reloads
+	mutex sync.Mutex
+	// modTime and size are the state of the File at the last load
+	modTime time.Time
+	size    int64
+}
+
+// NewEngine creates an engine and loads its policy. If Config.ReloadInterval is
+// set, the policy is reloaded in the background until Close is called.
+func NewEngine(config ...Config) (*Engine, error) {
+	// Set default config
+	cfg := configDefault(config...)
+
+	e := &Engine{cfg: cfg, done: make(chan struct{})}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+
+	if cfg.ReloadInterval > 0 && (cfg.File != "" || cfg.Loader != nil) {
+		go e.watch()
+	}
+
+	return e, nil
+}
+
+// Decide reports whether the principal has all permissions. The decision is
+// passed to Config.OnDecision and stored in the locals, see DecisionOf.
+func (e *Engine) Decide(c fiber.Ctx, principal any, permissions []string) (bool, error) {
+	decision := Decision{Permissions: permissions, Reason: "unknown subject"}
+
+	if subject, ok := e.cfg.Subject(principal); ok {
+		decision.Subject, decision.Reason = subject.ID, ""
+		policy := e.policy.Load()
+		roles := policy.expand(subject.Roles)
+		decision.Allowed = true
+		for _, permission := range permissions {
+			if decision.Allowed, decision.Reason = policy.decide(c, subject, roles, permission); !decision.Allowed {
+				break
+			}
+		}
+	}
+
+	c.Locals(decisionKey, decision)
+	if e.cfg.OnDecision != nil {
+		e.cfg.OnDecision(c, decision)
+	}
+	return decision.Allowed, nil
+}
+
+// Reload loads the policy from the File, the Loader or the Policy of the config.
+// If the new policy is invalid, the error is returned and the previous policy is kept.
+func (e *Engine) Reload() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var (
+		policy *Policy
+		err    error
+	)
+	switch {
+	case e.cfg.File != "":
+		e.modTime, e.size = fileState(e.cfg.File)
+		policy, err = LoadFile(e.cfg.File)
+	case e.cfg.Loader != nil:
+		policy, err = e.cfg.Loader()
+	default:
+		policy = e.cfg.Policy
+	}
+	if err != nil {
+		return err
+	}
+
+	compiled, err := compile(policy)
+	if err != nil {
+		return err
+	}
+	e.policy.Store(compiled)
+	return nil
+}
+
+// Close stops the reloading of the policy.
+func (e *Engine) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.done)
+	})
+	return nil
+}
+
+// watch reloads the policy every ReloadInterval, the File only if it was modified.
+func (e *Engine) watch() {
+	ticker := time.NewTicker(e.cfg.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+			if e.cfg.File != "" && !e.fileModified() {
+				continue
+			}
+			if err := e.Reload(); err != nil {
+				log.Errorw("authz: failed to reload the policy", "error", err)
+			}
+		}
+	}
+}
+
+// fileModified reports whether the File was modified since the last load.
+func (e *Engine) fileModified() bool {
+	modTime, size := fileState(e.cfg.File)
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return !modTime.Equal(e.modTime) || size != e.size
+}
+
+// fileState returns the modification time and the size of the file.
+func fileState(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
+
+// DecisionOf returns the last decision of the engine for the request,
+// e.g. for the Actor or the Action of the audit middleware.
+func DecisionOf(c fiber.Ctx) (Decision, bool) {
+	decision, ok := c.Locals(decisionKey).(Decision)
+	return decision, ok
+}
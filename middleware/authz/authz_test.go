@@ -0,0 +1,255 @@
+package authz
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// testPolicy has the role hierarchy admin > editor > viewer and attribute rules
+// for the tenants and the owners of the orders.
+var testPolicy = &Policy{
+	Roles: map[string]Role{
+		"admin":  {Inherits: []string{"editor"}, Permissions: []string{"users:*"}},
+		"editor": {Inherits: []string{"viewer"}, Permissions: []string{"orders:write"}},
+		"viewer": {Permissions: []string{"orders:read"}},
+	},
+	Rules: []Rule{
+		{
+			Permission: "*",
+			Effect:     EffectDeny,
+			Conditions: []Condition{{Attribute: "subject.tenant", Operator: OperatorNotEqual, ValueFrom: "param.tenant"}},
+		},
+		{
+			Permission: "orders:cancel",
+			Conditions: []Condition{{Attribute: "subject.id", ValueFrom: "query.owner"}},
+		},
+		{
+			Permission: "orders:refund",
+			Roles:      []string{"editor"},
+			Conditions: []Condition{{Attribute: "query.amount", Operator: OperatorLessThan, Value: 100}},
+		},
+	},
+}
+
+// testApp returns an app with the engine, which authenticates the subject of the header "X-User".
+func testApp(t *testing.T, engine *Engine) *fiber.App {
+	t.Helper()
+	subjects := map[string]*Subject{
+		"alice": {ID: "alice", Roles: []string{"admin"}, Attributes: map[string]any{"tenant": "acme"}},
+		"bob":   {ID: "bob", Roles: []string{"editor"}, Attributes: map[string]any{"tenant": "acme"}},
+		"carol": {ID: "carol", Roles: []string{"viewer"}, Attributes: map[string]any{"tenant": "acme"}},
+		"dave":  {ID: "dave", Roles: []string{"admin"}, Attributes: map[string]any{"tenant": "other"}},
+	}
+
+	app := fiber.New(fiber.Config{PolicyDecider: engine})
+	app.Use(func(c fiber.Ctx) error {
+		if subject, ok := subjects[c.Get("X-User")]; ok {
+			c.Locals(fiber.DefaultPrincipalKey, subject)
+		}
+		return c.Next()
+	})
+	ok := func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Get("/:tenant/orders", ok).Require("orders:read")
+	app.Post("/:tenant/orders", ok).Require("orders:write")
+	app.Delete("/:tenant/orders", ok).Require("orders:cancel")
+	app.Patch("/:tenant/orders", ok).Require("orders:refund")
+	app.Get("/:tenant/users", ok).Require("users:read", "role:admin")
+	return app
+}
+
+// testStatus returns the status of the request of the user.
+func testStatus(t *testing.T, app *fiber.App, method, target, user string) int {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+// go test -run Test_Engine_Roles
+func Test_Engine_Roles(t *testing.T) {
+	t.Parallel()
+	engine, err := NewEngine(Config{Policy: testPolicy})
+	require.NoError(t, err)
+	app := testApp(t, engine)
+
+	require.Equal(t, fiber.StatusUnauthorized, testStatus(t, app, fiber.MethodGet, "/acme/orders", ""))
+	require.Equal(t, fiber.StatusOK, testStatus(t, app, fiber.MethodGet, "/acme/orders", "carol"))
+	require.Equal(t, fiber.StatusForbidden, testStatus(t, app, fiber.MethodPost, "/acme/orders", "carol"))
+
+	// the permissions and the roles are inherited
+	require.Equal(t, fiber.StatusOK, testStatus(t, app, fiber.MethodPost, "/acme/orders", "bob"))
+	require.Equal(t, fiber.StatusOK, testStatus(t, app, fiber.MethodGet, "/acme/orders", "alice"))
+	require.Equal(t, fiber.StatusOK, testStatus(t, app, fiber.MethodGet, "/acme/users", "alice"))
+	require.Equal(t, fiber.StatusForbidden, testStatus(t, app, fiber.MethodGet, "/acme/users", "bob"))
+}
+
+// go test -run Test_Engine_Rules
+func Test_Engine_Rules(t *testing.T) {
+	t.Parallel()
+	engine, err := NewEngine(Config{Policy: testPolicy})
+	require.NoError(t, err)
+	app := testApp(t, engine)
+
+	// the deny rule takes precedence over the roles
+	require.Equal(t, fiber.StatusForbidden, testStatus(t, app, fiber.MethodGet, "/acme/orders", "dave"))
+	require.Equal(t, fiber.StatusOK, testStatus(t, app, fiber.MethodGet, "/other/orders", "dave"))
+
+	require.Equal(t, fiber.StatusOK, testStatus(t, app, fiber.MethodDelete, "/acme/orders?owner=carol", "carol"))
+	require.Equal(t, fiber.StatusForbidden, testStatus(t, app, fiber.MethodDelete, "/acme/orders?owner=bob", "carol"))
+	require.Equal(t, fiber.StatusForbidden, testStatus(t, app, fiber.MethodDelete, "/acme/orders", "carol"))
+
+	require.Equal(t, fiber.StatusOK, testStatus(t, app, fiber.MethodPatch, "/acme/orders?amount=50", "alice"))
+	require.Equal(t, fiber.StatusForbidden, testStatus(t, app, fiber.MethodPatch, "/acme/orders?amount=500", "bob"))
+	require.Equal(t, fiber.StatusForbidden, testStatus(t, app, fiber.MethodPatch, "/acme/orders?amount=50", "carol"))
+}
+
+// go test -run Test_Engine_Decision
+func Test_Engine_Decision(t *testing.T) {
+	t.Parallel()
+	var decisions []Decision
+	engine, err := NewEngine(Config{
+		Policy: testPolicy,
+		OnDecision: func(_ fiber.Ctx, decision Decision) {
+			decisions = append(decisions, decision)
+		},
+	})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		subject := Subject{ID: "bob", Roles: []string{"editor"}}
+		allowed, err := engine.Decide(c, subject, []string{"orders:read", "users:read"})
+		require.NoError(t, err)
+		require.False(t, allowed)
+
+		decision, ok := DecisionOf(c)
+		require.True(t, ok)
+		require.Equal(t, decisions[0], decision)
+		return c.SendStatus(fiber.StatusOK)
+	})
+	require.Equal(t, fiber.StatusOK, testStatus(t, app, fiber.MethodGet, "/", ""))
+
+	require.Equal(t, []Decision{{
+		Subject:     "bob",
+		Reason:      `missing permission "users:read"`,
+		Permissions: []string{"orders:read", "users:read"},
+	}}, decisions)
+}
+
+// go test -run Test_Engine_InvalidPolicy
+func Test_Engine_InvalidPolicy(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewEngine()
+	require.ErrorIs(t, err, ErrNoPolicy)
+
+	_, err = NewEngine(Config{Policy: &Policy{Roles: map[string]Role{
+		"a": {Inherits: []string{"b"}},
+		"b": {Inherits: []string{"a"}},
+	}}})
+	require.ErrorIs(t, err, ErrRoleCycle)
+
+	_, err = NewEngine(Config{Policy: &Policy{Roles: map[string]Role{
+		"a": {Inherits: []string{"missing"}},
+	}}})
+	require.ErrorIs(t, err, ErrUnknownRole)
+
+	for _, rule := range []Rule{
+		{},
+		{Permission: "a", Effect: "maybe"},
+		{Permission: "a", Roles: []string{"missing"}},
+		{Permission: "a", Conditions: []Condition{{Attribute: "subject.id", Operator: "like"}}},
+		{Permission: "a", Conditions: []Condition{{Attribute: "cookie.id"}}},
+		{Permission: "a", Conditions: []Condition{{Attribute: "subject.id", ValueFrom: "param."}}},
+	} {
+		_, err = NewEngine(Config{Policy: &Policy{Rules: []Rule{rule}}})
+		require.ErrorIs(t, err, ErrInvalidRule)
+	}
+}
+
+// go test -run Test_Engine_File
+func Test_Engine_File(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"roles": {"viewer": {"permissions": ["orders:read"]}}}`), 0o600))
+
+	engine, err := NewEngine(Config{File: path, ReloadInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, engine.Close()) }()
+
+	app := fiber.New(fiber.Config{PolicyDecider: engine})
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals(fiber.DefaultPrincipalKey, &Subject{ID: "carol", Roles: []string{"viewer"}})
+		return c.Next()
+	})
+	app.Post("/orders", func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }).Require("orders:write")
+	require.Equal(t, fiber.StatusForbidden, testStatus(t, app, fiber.MethodPost, "/orders", ""))
+
+	// an invalid policy keeps the previous policy
+	require.NoError(t, os.WriteFile(path, []byte(`{"roles": {`), 0o600))
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, fiber.StatusForbidden, testStatus(t, app, fiber.MethodPost, "/orders", ""))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"roles": {"viewer": {"permissions": ["orders:*"]}}}`), 0o600))
+	require.Eventually(t, func() bool {
+		return testStatus(t, app, fiber.MethodPost, "/orders", "") == fiber.StatusOK
+	}, time.Second, 10*time.Millisecond)
+}
+
+// go test -run Test_Engine_Loader
+func Test_Engine_Loader(t *testing.T) {
+	t.Parallel()
+	var permission atomic.Value
+	permission.Store("orders:read")
+
+	engine, err := NewEngine(Config{
+		Loader: func() (*Policy, error) {
+			perm, _ := permission.Load().(string) //nolint:errcheck // always a string
+			return &Policy{Roles: map[string]Role{"viewer": {Permissions: []string{perm}}}}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	var allowed bool
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		var err error
+		allowed, err = engine.Decide(c, &Subject{Roles: []string{"viewer"}}, []string{"orders:write"})
+		if err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+	decide := func() bool {
+		require.Equal(t, fiber.StatusOK, testStatus(t, app, fiber.MethodGet, "/", ""))
+		return allowed
+	}
+	require.False(t, decide())
+
+	permission.Store("*")
+	require.False(t, decide())
+	require.NoError(t, engine.Reload())
+	require.True(t, decide())
+}
+
+// go test -run Test_MatchPermission
+func Test_MatchPermission(t *testing.T) {
+	t.Parallel()
+	require.True(t, matchPermission("*", "orders:read"))
+	require.True(t, matchPermission("orders:*", "orders:read"))
+	require.True(t, matchPermission("orders:read", "orders:read"))
+	require.False(t, matchPermission("orders:*", "ordersx"))
+	require.False(t, matchPermission("orders*", "orders:read"))
+	require.False(t, matchPermission("orders:read", "orders:write"))
+}
@@ -0,0 +1,87 @@
+package authz
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for the engine.
+type Config struct {
+	// Policy is a static policy. One of Policy, File or Loader is required.
+	//
+	// Optional. Default: nil
+	Policy *Policy
+
+	// File is the path of a JSON policy file. It is reloaded when it was
+	// modified, if ReloadInterval is set.
+	//
+	// Optional. Default: ""
+	File string
+
+	// Loader loads the policy from a provider, e.g. a database or a policy service.
+	// It is called by NewEngine, by Reload and every ReloadInterval.
+	//
+	// Optional. Default: nil
+	Loader func() (*Policy, error)
+
+	// ReloadInterval is the interval to check the File for modifications or to call
+	// the Loader. If the new policy is invalid, the previous policy is kept.
+	//
+	// Optional. Default: 0 (disabled)
+	ReloadInterval time.Duration
+
+	// Subject returns the subject of the authenticated principal in the locals.
+	// The principal is denied if false is returned.
+	//
+	// Optional. Default: the principal must be a Subject or *Subject
+	Subject func(principal any) (*Subject, bool)
+
+	// OnDecision is called with each decision of the engine, e.g. to write it to
+	// an audit log. The last decision of a request is also returned by DecisionOf.
+	//
+	// Optional. Default: nil
+	OnDecision func(c fiber.Ctx, decision Decision)
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Policy:         nil,
+	File:           "",
+	Loader:         nil,
+	ReloadInterval: 0,
+	Subject:        defaultSubject,
+	OnDecision:     nil,
+}
+
+// defaultSubject returns the principal if it is a Subject.
+func defaultSubject(principal any) (*Subject, bool) {
+	switch s := principal.(type) {
+	case *Subject:
+		return s, s != nil
+	case Subject:
+		return &s, true
+	default:
+		return nil, false
+	}
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Subject == nil {
+		cfg.Subject = ConfigDefault.Subject
+	}
+	if cfg.ReloadInterval < 0 {
+		cfg.ReloadInterval = ConfigDefault.ReloadInterval
+	}
+	return cfg
+}
@@ -0,0 +1,381 @@
+package authz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// RolePrefix is the prefix of the required permissions which are roles,
+// e.g. Require("role:admin") requires the role "admin" or a role which inherits it.
+const RolePrefix = "role:"
+
+// The effects of the rules
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// The operators of the conditions
+const (
+	OperatorEqual       = "eq"
+	OperatorNotEqual    = "ne"
+	OperatorIn          = "in"
+	OperatorNotIn       = "not_in"
+	OperatorContains    = "contains"
+	OperatorGreaterThan = "gt"
+	OperatorLessThan    = "lt"
+)
+
+// The errors of invalid policies
+var (
+	ErrNoPolicy    = errors.New("authz: a policy, file or loader is required")
+	ErrUnknownRole = errors.New("authz: unknown role")
+	ErrRoleCycle   = errors.New("authz: role hierarchy contains a cycle")
+	ErrInvalidRule = errors.New("authz: invalid rule")
+)
+
+// Policy contains the roles and the attribute rules of the engine.
+type Policy struct {
+	// Roles are the roles by their name.
+	Roles map[string]Role `json:"roles"`
+	// Rules are the attribute rules, which are evaluated in their order.
+	Rules []Rule `json:"rules"`
+}
+
+// Role grants permissions to the subjects with the role.
+type Role struct {
+	// Inherits are the roles whose permissions are inherited, e.g. "admin" inherits "editor".
+	Inherits []string `json:"inherits,omitempty"`
+	// Permissions are the granted permissions, e.g. "orders:write", "orders:*" or "*".
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// Rule grants or denies a permission if all conditions are met.
+// Deny rules take precedence over the roles and the allow rules.
+type Rule struct {
+	// Permission is the permission of the rule, e.g. "orders:write", "orders:*" or "*".
+	Permission string `json:"permission"`
+	// Effect is EffectAllow or EffectDeny. Default: EffectAllow
+	Effect string `json:"effect,omitempty"`
+	// Roles restrict the rule to the subjects with one of the roles, including inherited roles.
+	Roles []string `json:"roles,omitempty"`
+	// Conditions must all be met.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// Condition compares an attribute of the subject or the request with a value
+// or another attribute. The attributes are:
+//
+//   - "subject.id", "subject.roles" and "subject.<name>" for Subject.Attributes
+//   - "param.<name>", "query.<name>" and "header.<name>"
+//   - "request.method", "request.path" and "request.ip"
+//
+// A condition with a missing attribute isn't met.
+type Condition struct {
+	// Attribute is the compared attribute, e.g. "subject.tenant".
+	Attribute string `json:"attribute"`
+	// Operator is one of the operators, e.g. OperatorEqual. Default: OperatorEqual
+	Operator string `json:"operator,omitempty"`
+	// Value is the value which the attribute is compared with, a list for OperatorIn and OperatorNotIn.
+	Value any `json:"value,omitempty"`
+	// ValueFrom is an attribute which the attribute is compared with instead of Value, e.g. "param.tenant".
+	ValueFrom string `json:"value_from,omitempty"`
+}
+
+// LoadFile reads a JSON policy from the file.
+func LoadFile(path string) (*Policy, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("authz: failed to read policy: %w", err)
+	}
+	var policy Policy
+	if err := json.Unmarshal(b, &policy); err != nil {
+		return nil, fmt.Errorf("authz: failed to decode policy %q: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// compiledPolicy is a validated policy with the resolved role hierarchy.
+type compiledPolicy struct {
+	// roles are the roles and their inherited roles by the name of the role.
+	roles map[string][]string
+	// permissions are the permissions of the roles, including the inherited permissions.
+	permissions map[string][]string
+	rules       []Rule
+}
+
+// compile validates the policy and resolves the role hierarchy.
+func compile(policy *Policy) (*compiledPolicy, error) {
+	if policy == nil {
+		return nil, ErrNoPolicy
+	}
+
+	compiled := &compiledPolicy{
+		roles:       make(map[string][]string, len(policy.Roles)),
+		permissions: make(map[string][]string, len(policy.Roles)),
+		rules:       slices.Clone(policy.Rules),
+	}
+
+	// visiting contains the roles on the current path of the hierarchy
+	visiting := make(map[string]bool)
+	var resolve func(name string) error
+	resolve = func(name string) error {
+		if _, ok := compiled.roles[name]; ok {
+			return nil
+		}
+		role, ok := policy.Roles[name]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownRole, name)
+		}
+		if visiting[name] {
+			return fmt.Errorf("%w: %q", ErrRoleCycle, name)
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		roles := []string{name}
+		permissions := slices.Clone(role.Permissions)
+		for _, parent := range role.Inherits {
+			if err := resolve(parent); err != nil {
+				return err
+			}
+			roles = append(roles, compiled.roles[parent]...)
+			permissions = append(permissions, compiled.permissions[parent]...)
+		}
+		slices.Sort(roles)
+		slices.Sort(permissions)
+		compiled.roles[name] = slices.Compact(roles)
+		compiled.permissions[name] = slices.Compact(permissions)
+		return nil
+	}
+	for name := range policy.Roles {
+		if err := resolve(name); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range compiled.rules {
+		if err := compiled.validateRule(&compiled.rules[i]); err != nil {
+			return nil, fmt.Errorf("%w %d: %w", ErrInvalidRule, i, err)
+		}
+	}
+
+	return compiled, nil
+}
+
+// validateRule validates the effect, the roles and the conditions of the rule.
+func (p *compiledPolicy) validateRule(rule *Rule) error {
+	if rule.Permission == "" {
+		return errors.New("permission is required")
+	}
+	if rule.Effect != "" && rule.Effect != EffectAllow && rule.Effect != EffectDeny {
+		return fmt.Errorf("unknown effect %q", rule.Effect)
+	}
+	for _, role := range rule.Roles {
+		if _, ok := p.roles[role]; !ok {
+			return fmt.Errorf("unknown role %q", role)
+		}
+	}
+	for _, cond := range rule.Conditions {
+		switch cond.Operator {
+		case "", OperatorEqual, OperatorNotEqual, OperatorIn, OperatorNotIn, OperatorContains, OperatorGreaterThan, OperatorLessThan:
+		default:
+			return fmt.Errorf("unknown operator %q", cond.Operator)
+		}
+		if !validAttribute(cond.Attribute) {
+			return fmt.Errorf("unknown attribute %q", cond.Attribute)
+		}
+		if cond.ValueFrom != "" && !validAttribute(cond.ValueFrom) {
+			return fmt.Errorf("unknown attribute %q", cond.ValueFrom)
+		}
+	}
+	return nil
+}
+
+// expand returns the roles of the subject and their inherited roles.
+// Unknown roles of the subject are ignored.
+func (p *compiledPolicy) expand(subjectRoles []string) []string {
+	var roles []string
+	for _, role := range subjectRoles {
+		roles = append(roles, p.roles[role]...)
+	}
+	slices.Sort(roles)
+	return slices.Compact(roles)
+}
+
+// decide decides whether the subject with the expanded roles has the permission
+// and returns the reason of the decision.
+func (p *compiledPolicy) decide(c fiber.Ctx, subject *Subject, roles []string, permission string) (bool, string) {
+	for i := range p.rules {
+		if p.rules[i].Effect == EffectDeny && p.ruleApplies(c, subject, roles, &p.rules[i], permission) {
+			return false, fmt.Sprintf("denied by rule %d", i)
+		}
+	}
+
+	if role, ok := strings.CutPrefix(permission, RolePrefix); ok {
+		if slices.Contains(roles, role) {
+			return true, fmt.Sprintf("role %q", role)
+		}
+	}
+	for _, role := range roles {
+		for _, granted := range p.permissions[role] {
+			if matchPermission(granted, permission) {
+				return true, fmt.Sprintf("role %q", role)
+			}
+		}
+	}
+
+	for i := range p.rules {
+		if p.rules[i].Effect != EffectDeny && p.ruleApplies(c, subject, roles, &p.rules[i], permission) {
+			return true, fmt.Sprintf("rule %d", i)
+		}
+	}
+
+	return false, fmt.Sprintf("missing permission %q", permission)
+}
+
+// ruleApplies reports whether the rule matches the permission and the roles
+// of the subject and all of its conditions are met.
+func (*compiledPolicy) ruleApplies(c fiber.Ctx, subject *Subject, roles []string, rule *Rule, permission string) bool {
+	if !matchPermission(rule.Permission, permission) {
+		return false
+	}
+	if len(rule.Roles) > 0 && !slices.ContainsFunc(rule.Roles, func(role string) bool {
+		return slices.Contains(roles, role)
+	}) {
+		return false
+	}
+	for i := range rule.Conditions {
+		if !rule.Conditions[i].met(c, subject, roles) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPermission reports whether the granted permission, which may end with
+// the wildcard ":*" or be "*", matches the required permission.
+func matchPermission(granted, required string) bool {
+	if granted == "*" || granted == required {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(granted, "*"); ok && strings.HasSuffix(prefix, ":") {
+		return strings.HasPrefix(required, prefix)
+	}
+	return false
+}
+
+// met reports whether the condition is met for the subject and the request.
+func (cond *Condition) met(c fiber.Ctx, subject *Subject, roles []string) bool {
+	value, ok := attribute(c, subject, roles, cond.Attribute)
+	if !ok {
+		return false
+	}
+	expected := cond.Value
+	if cond.ValueFrom != "" {
+		if expected, ok = attribute(c, subject, roles, cond.ValueFrom); !ok {
+			return false
+		}
+	}
+
+	switch cond.Operator {
+	case OperatorNotEqual:
+		return !equal(value, expected)
+	case OperatorIn:
+		return slices.ContainsFunc(toList(expected), func(v any) bool { return equal(value, v) })
+	case OperatorNotIn:
+		return !slices.ContainsFunc(toList(expected), func(v any) bool { return equal(value, v) })
+	case OperatorContains:
+		return slices.ContainsFunc(toList(value), func(v any) bool { return equal(v, expected) })
+	case OperatorGreaterThan, OperatorLessThan:
+		a, errA := strconv.ParseFloat(fmt.Sprint(value), 64)
+		b, errB := strconv.ParseFloat(fmt.Sprint(expected), 64)
+		if errA != nil || errB != nil {
+			return false
+		}
+		if cond.Operator == OperatorGreaterThan {
+			return a > b
+		}
+		return a < b
+	default:
+		return equal(value, expected)
+	}
+}
+
+// attributeSources are the prefixes of the attributes with a name.
+var attributeSources = []string{"subject.", "param.", "query.", "header."}
+
+// validAttribute reports whether the attribute is known.
+func validAttribute(name string) bool {
+	switch name {
+	case "request.method", "request.path", "request.ip":
+		return true
+	}
+	for _, source := range attributeSources {
+		if len(name) > len(source) && strings.HasPrefix(name, source) {
+			return true
+		}
+	}
+	return false
+}
+
+// attribute returns the value of the attribute of the subject or the request.
+func attribute(c fiber.Ctx, subject *Subject, roles []string, name string) (any, bool) {
+	switch name {
+	case "subject.id":
+		return subject.ID, subject.ID != ""
+	case "subject.roles":
+		return roles, true
+	case "request.method":
+		return c.Method(), true
+	case "request.path":
+		return c.Path(), true
+	case "request.ip":
+		return c.IP(), true
+	}
+
+	source, key, _ := strings.Cut(name, ".")
+	var value string
+	switch source {
+	case "subject":
+		v, ok := subject.Attributes[key]
+		return v, ok
+	case "param":
+		value = c.Params(key)
+	case "query":
+		value = c.Query(key)
+	case "header":
+		value = c.Get(key)
+	}
+	return value, value != ""
+}
+
+// equal compares the values by their string representation, so the numbers
+// of a JSON policy are equal to the numbers of the parameters.
+func equal(a, b any) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// toList returns the elements of a list, or nil if the value isn't a list.
+func toList(value any) []any {
+	switch v := value.(type) {
+	case []any:
+		return v
+	case []string:
+		list := make([]any, len(v))
+		for i := range v {
+			list[i] = v[i]
+		}
+		return list
+	default:
+		return nil
+	}
+}
@@ -65,19 +65,21 @@ func New(config ...Config) fiber.Handler {
 		timestamp = uint64(time.Now().Unix())
 	)
 	// Create manager to simplify storage operations ( see manager.go )
-	manager := newManager(cfg.Storage)
+	manager := newManager(cfg.Storage, cfg.Clock)
 	// Create indexed heap for tracking expirations ( see heap.go )
 	heap := &indexedHeap{}
 	// count stored bytes (sizes of response bodies)
 	var storedBytes uint
 
-	// Update timestamp in the configured interval
-	go func() {
-		for {
-			atomic.StoreUint64(&timestamp, uint64(time.Now().Unix()))
-			time.Sleep(timestampUpdatePeriod)
-		}
-	}()
+	// Update timestamp in the configured interval, the time of a clock is read per request
+	if cfg.Clock == nil {
+		go func() {
+			for {
+				atomic.StoreUint64(&timestamp, uint64(time.Now().Unix()))
+				time.Sleep(timestampUpdatePeriod)
+			}
+		}()
+	}
 
 	// Delete key from both manager and storage
 	deleteKey := func(dkey string) {
@@ -120,6 +122,9 @@ func New(config ...Config) fiber.Handler {
 
 		// Get timestamp
 		ts := atomic.LoadUint64(&timestamp)
+		if cfg.Clock != nil {
+			ts = uint64(cfg.Clock.Now().Unix())
+		}
 
 		// Check if entry is expired
 		if e.exp != 0 && ts >= e.exp {
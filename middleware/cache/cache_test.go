@@ -80,6 +80,30 @@ func Test_Cache_Expired(t *testing.T) {
 	}
 }
 
+// go test -run Test_Cache_Clock
+func Test_Cache_Clock(t *testing.T) {
+	t.Parallel()
+	clock := fiber.NewManualClock(time.Now())
+	app := fiber.New()
+	app.Use(New(Config{Expiration: time.Hour, Clock: clock}))
+
+	count := 0
+	app.Get("/", func(c fiber.Ctx) error {
+		count++
+		return c.SendString(strconv.Itoa(count))
+	})
+
+	for _, advance := range []time.Duration{0, 59 * time.Minute, time.Minute} {
+		clock.Advance(advance)
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	// the cache expired after an hour of the clock
+	require.Equal(t, 2, count)
+}
+
 func Test_Cache(t *testing.T) {
 	t.Parallel()
 
@@ -48,6 +48,12 @@ type Config struct {
 	// Default: an in memory store for this process only
 	Storage fiber.Storage
 
+	// Clock is the source of the time of the expirations and of the in memory store,
+	// e.g. a fiber.ManualClock in tests.
+	//
+	// Default: nil, the system time
+	Clock fiber.Clock
+
 	// allows you to store additional headers generated by next middlewares & handler
 	//
 	// Default: false
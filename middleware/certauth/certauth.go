@@ -0,0 +1,76 @@
+package certauth
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/authz"
+)
+
+// New creates a new middleware handler, which authenticates the clients of mTLS requests with
+// their verified client certificates and sets their principals for the authorization, see
+// fiber.App.Require and the authz engine.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	trustDomains := make([]string, len(cfg.TrustDomains))
+	for i, domain := range cfg.TrustDomains {
+		trustDomains[i] = strings.ToLower(domain)
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		cert := c.ClientCertificate()
+		if cert == nil {
+			if cfg.Optional {
+				return c.Next()
+			}
+			return fiber.ErrUnauthorized
+		}
+		if len(trustDomains) > 0 && !slices.Contains(trustDomains, cert.TrustDomain()) {
+			return fiber.ErrForbidden
+		}
+		identity := cfg.Identity(cert)
+		if identity == "" {
+			return fiber.ErrForbidden
+		}
+
+		var principal any
+		if cfg.Principal != nil {
+			var err error
+			if principal, err = cfg.Principal(c, cert, identity); err != nil {
+				var fiberErr *fiber.Error
+				if errors.As(err, &fiberErr) {
+					return err
+				}
+				return fiber.ErrForbidden
+			}
+		} else {
+			principal = defaultPrincipal(&cfg, cert, identity)
+		}
+		c.Locals(c.App().Config().PrincipalKey, principal)
+
+		return c.Next()
+	}
+}
+
+// defaultPrincipal returns the subject of the identity for the authz engine.
+func defaultPrincipal(cfg *Config, cert *fiber.ClientCertificate, identity string) *authz.Subject {
+	return &authz.Subject{
+		ID:    identity,
+		Roles: cfg.Roles[identity],
+		Attributes: map[string]any{
+			"spiffe_id":    cert.SPIFFEID,
+			"trust_domain": cert.TrustDomain(),
+			"fingerprint":  cert.Fingerprint,
+		},
+	}
+}
@@ -0,0 +1,202 @@
+package certauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/authz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// testCA issues client certificates for the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// issue issues a client certificate with the common name and the URI SAN.
+func (ca *testCA) issue(t *testing.T, commonName, uri string) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if uri != "" {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		template.URIs = []*url.URL{u}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// serve serves the app with mTLS and returns a function which sends a request with the certificates.
+func serve(t *testing.T, app *fiber.App, ca *testCA) func(path string, certs ...tls.Certificate) (int, string) {
+	t.Helper()
+	serverCert, err := tls.LoadX509KeyPair("../../.github/testdata/ssl.pem", "../../.github/testdata/ssl.key")
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(tls.NewListener(ln, &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{serverCert},
+			ClientAuth:   tls.VerifyClientCertIfGiven,
+			ClientCAs:    pool,
+		}), fiber.ListenConfig{DisableStartupMessage: true}))
+	}()
+	t.Cleanup(func() {
+		_ = app.Shutdown() //nolint:errcheck // It's a test
+	})
+
+	return func(path string, certs ...tls.Certificate) (int, string) {
+		var conn net.Conn
+		require.Eventually(t, func() bool {
+			conn, err = ln.Dial()
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+		defer conn.Close() //nolint:errcheck // It's a test
+
+		client := &http.Client{Transport: &http.Transport{
+			DialTLS: func(string, string) (net.Conn, error) {
+				return tls.Client(conn, &tls.Config{
+					InsecureSkipVerify: true, //nolint:gosec // self signed test certificate
+					Certificates:       certs,
+				}), nil
+			},
+		}}
+		resp, err := client.Get("https://example.com" + path) //nolint:noctx // It's a test
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck // It's a test
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+}
+
+// go test -run Test_CertAuth
+func Test_CertAuth(t *testing.T) {
+	t.Parallel()
+	engine, err := authz.NewEngine(authz.Config{Policy: &authz.Policy{
+		Roles: map[string]authz.Role{"reader": {Permissions: []string{"orders:read"}}},
+	}})
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{PolicyDecider: engine})
+	app.Use(New(Config{
+		TrustDomains: []string{"Example.org"},
+		Roles:        map[string][]string{"spiffe://example.org/orders": {"reader"}},
+	}))
+	app.Get("/orders", func(c fiber.Ctx) error {
+		subject, ok := c.Locals(fiber.DefaultPrincipalKey).(*authz.Subject)
+		require.True(t, ok)
+		return c.SendString(subject.ID + " " + subject.Attributes["trust_domain"].(string)) //nolint:forcetypeassert // It's a test
+	}).Require("orders:read")
+
+	ca := newTestCA(t)
+	request := serve(t, app, ca)
+
+	status, body := request("/orders", ca.issue(t, "orders", "spiffe://example.org/orders"))
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "spiffe://example.org/orders example.org", body)
+
+	// the identity has no role with the permission
+	status, _ = request("/orders", ca.issue(t, "billing", "spiffe://example.org/billing"))
+	require.Equal(t, fiber.StatusForbidden, status)
+
+	// other trust domains and certificates without SPIFFE ID are rejected
+	status, _ = request("/orders", ca.issue(t, "orders", "spiffe://other.org/orders"))
+	require.Equal(t, fiber.StatusForbidden, status)
+	status, _ = request("/orders", ca.issue(t, "orders", ""))
+	require.Equal(t, fiber.StatusForbidden, status)
+
+	// requests without client certificate
+	status, _ = request("/orders")
+	require.Equal(t, fiber.StatusUnauthorized, status)
+}
+
+// go test -run Test_CertAuth_Principal
+func Test_CertAuth_Principal(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Optional: true,
+		Principal: func(_ fiber.Ctx, cert *fiber.ClientCertificate, identity string) (any, error) {
+			if identity == "revoked" {
+				return nil, errors.New("revoked")
+			}
+			if identity == "unknown" {
+				return nil, fiber.NewError(fiber.StatusUnauthorized, "unknown service")
+			}
+			return identity + " " + cert.Fingerprint[:8], nil
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		principal, _ := c.Locals(fiber.DefaultPrincipalKey).(string) //nolint:errcheck // It's a test
+		return c.SendString("principal:" + principal)
+	})
+
+	ca := newTestCA(t)
+	request := serve(t, app, ca)
+
+	cert := ca.issue(t, "orders", "")
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	status, body := request("/", cert)
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "principal:orders "+fiber.NewClientCertificate([]*x509.Certificate{leaf}).Fingerprint[:8], body)
+
+	status, _ = request("/", ca.issue(t, "revoked", ""))
+	require.Equal(t, fiber.StatusForbidden, status)
+	status, body = request("/", ca.issue(t, "unknown", ""))
+	require.Equal(t, fiber.StatusUnauthorized, status)
+	require.Equal(t, "unknown service", body)
+
+	// the optional requests pass without a principal
+	status, body = request("/")
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "principal:", body)
+}
@@ -0,0 +1,79 @@
+package certauth
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Identity returns the identity of the client certificate, e.g. its SPIFFE ID.
+	// Certificates without an identity are rejected with 403 Forbidden.
+	//
+	// Optional. Default: the SPIFFE ID, the first DNS SAN or the common name
+	Identity func(cert *fiber.ClientCertificate) string
+
+	// TrustDomains are the SPIFFE trust domains of the allowed clients, e.g. "example.org".
+	// The clients without a SPIFFE ID of the trust domains are rejected with 403 Forbidden.
+	//
+	// Optional. Default: nil, all verified clients are allowed
+	TrustDomains []string
+
+	// Roles are the roles of the identities, which are assigned to the default principals.
+	//
+	// Optional. Default: nil
+	Roles map[string][]string
+
+	// Principal maps the client certificate and its identity to the principal, which is set in
+	// the locals with fiber.Config.PrincipalKey, e.g. a service account of the database. If it
+	// returns an error, the request is rejected with 403 Forbidden, unless the error is a *fiber.Error.
+	//
+	// Optional. Default: an *authz.Subject with the identity as ID, its Roles and the
+	// "spiffe_id", "trust_domain" and "fingerprint" of the certificate as attributes
+	Principal func(c fiber.Ctx, cert *fiber.ClientCertificate, identity string) (any, error)
+
+	// Optional passes the requests without a verified client certificate without a principal,
+	// the permissions of the routes are still required, see fiber.App.Require.
+	//
+	// Optional. Default: false, the requests are rejected with 401 Unauthorized
+	Optional bool
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:     nil,
+	Identity: defaultIdentity,
+}
+
+// defaultIdentity returns the SPIFFE ID, the first DNS SAN or the common name of the certificate.
+func defaultIdentity(cert *fiber.ClientCertificate) string {
+	switch {
+	case cert.SPIFFEID != "":
+		return cert.SPIFFEID
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	default:
+		return cert.CommonName
+	}
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Identity == nil {
+		cfg.Identity = ConfigDefault.Identity
+	}
+	return cfg
+}
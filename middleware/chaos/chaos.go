@@ -0,0 +1,257 @@
+package chaos
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+var (
+	// ErrUnknownFault is returned by SetFault for a fault which isn't configured.
+	ErrUnknownFault = errors.New("chaos: unknown fault")
+	// ErrInvalidFault is returned by New and SetFault for invalid settings of a fault.
+	ErrInvalidFault = errors.New("chaos: invalid fault")
+)
+
+// State is the state of the injected faults, it's the body of the admin API.
+type State struct {
+	// Faults are the configured faults.
+	Faults []Fault `json:"faults"`
+	// Enabled reports if the faults are injected.
+	Enabled bool `json:"enabled"`
+}
+
+// Chaos injects faults into a percentage of the requests for resilience testing,
+// e.g. in a staging environment. The faults can be changed at runtime.
+type Chaos struct {
+	next  func(c fiber.Ctx) bool
+	clock fiber.Clock
+	rand  fiber.Rand
+	state atomic.Pointer[State]
+	// mutex serializes the changes of the state
+	mutex sync.Mutex
+}
+
+// New creates a new chaos middleware, it panics if a fault is invalid.
+func New(config ...Config) *Chaos {
+	// Set default config
+	cfg := configDefault(config...)
+
+	for _, fault := range cfg.Faults {
+		if err := validateFault(fault); err != nil {
+			panic(err)
+		}
+	}
+
+	ch := &Chaos{next: cfg.Next, clock: cfg.Clock, rand: cfg.Rand}
+	ch.state.Store(&State{Faults: cfg.Faults, Enabled: cfg.Enabled})
+	return ch
+}
+
+// Handler returns the middleware handler, which injects the faults into the requests.
+func (ch *Chaos) Handler() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if ch.next != nil && ch.next(c) {
+			return c.Next()
+		}
+
+		state := ch.state.Load()
+		if !state.Enabled {
+			return c.Next()
+		}
+
+		var (
+			delay   time.Duration
+			status  int
+			reset   bool
+			partial float64
+		)
+		for i := range state.Faults {
+			fault := &state.Faults[i]
+			if !ch.inject(c, fault) {
+				continue
+			}
+			delay += fault.Latency
+			if fault.Jitter > 0 {
+				delay += time.Duration(ch.rand.Int63n(int64(fault.Jitter) + 1))
+			}
+			if status == 0 {
+				status = fault.Status
+			}
+			if partial == 0 {
+				partial = fault.Partial
+			}
+			reset = reset || fault.Reset
+		}
+
+		if delay > 0 {
+			timer := ch.clock.NewTimer(delay)
+			select {
+			case <-timer.C():
+			case <-c.Context().Done():
+				// the server is shut down
+				timer.Stop()
+			}
+		}
+
+		if reset {
+			c.Hijack(resetConn)
+			return nil
+		}
+		if status != 0 {
+			return fiber.NewError(status)
+		}
+
+		err := c.Next()
+		if err != nil || partial == 0 || c.Hijacked() {
+			return err
+		}
+		sendPartial(c, partial)
+		return nil
+	}
+}
+
+// inject reports if the fault is injected into the request.
+func (ch *Chaos) inject(c fiber.Ctx, fault *Fault) bool {
+	if fault.Percentage <= 0 || (fault.Match != nil && !fault.Match(c)) {
+		return false
+	}
+	return fault.Percentage >= 100 || ch.rand.Float64()*100 < fault.Percentage
+}
+
+// sendPartial sends the headers of the whole response with a fraction of its body
+// and closes the connection.
+func sendPartial(c fiber.Ctx, partial float64) {
+	res := c.Response()
+	body := res.Body()
+	size := len(body)
+	part := append([]byte(nil), body[:int(float64(size)*partial)]...)
+
+	res.Header.SetContentLength(size)
+	header := append([]byte(nil), res.Header.Header()...)
+	c.Hijack(func(conn net.Conn) {
+		if _, err := conn.Write(header); err != nil {
+			return
+		}
+		_, _ = conn.Write(part) //nolint:errcheck // The connection is closed anyway
+	})
+}
+
+// resetConn closes the connection with a TCP reset instead of a graceful close.
+func resetConn(conn net.Conn) {
+	for {
+		switch wrapped := conn.(type) {
+		case *net.TCPConn:
+			_ = wrapped.SetLinger(0) //nolint:errcheck // The connection is closed anyway
+			return
+		case interface{ NetConn() net.Conn }:
+			conn = wrapped.NetConn()
+		default:
+			return
+		}
+	}
+}
+
+// validateFault checks the settings of the fault.
+func validateFault(fault Fault) error {
+	switch {
+	case fault.Percentage < 0 || fault.Percentage > 100:
+		return fmt.Errorf("%w: the percentage of %q must be between 0 and 100", ErrInvalidFault, fault.Name)
+	case fault.Partial < 0 || fault.Partial > 1:
+		return fmt.Errorf("%w: the partial of %q must be between 0 and 1", ErrInvalidFault, fault.Name)
+	case fault.Latency < 0 || fault.Jitter < 0:
+		return fmt.Errorf("%w: the latency of %q must not be negative", ErrInvalidFault, fault.Name)
+	case fault.Status != 0 && (fault.Status < fiber.StatusBadRequest || fault.Status > 599):
+		return fmt.Errorf("%w: the status of %q must be an error status", ErrInvalidFault, fault.Name)
+	}
+	return nil
+}
+
+// State returns the current state of the faults.
+func (ch *Chaos) State() State {
+	state := ch.state.Load()
+	return State{
+		Faults:  append([]Fault(nil), state.Faults...),
+		Enabled: state.Enabled,
+	}
+}
+
+// SetEnabled enables or disables the injection of the faults.
+func (ch *Chaos) SetEnabled(enabled bool) {
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+
+	state := ch.state.Load()
+	ch.state.Store(&State{Faults: state.Faults, Enabled: enabled})
+}
+
+// SetFault changes the settings of the configured fault with the name of the fault.
+// Its Match predicate is kept.
+func (ch *Chaos) SetFault(fault Fault) error {
+	if err := validateFault(fault); err != nil {
+		return err
+	}
+
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+
+	state := ch.state.Load()
+	for i := range state.Faults {
+		if state.Faults[i].Name != fault.Name {
+			continue
+		}
+		faults := append([]Fault(nil), state.Faults...)
+		fault.Match = faults[i].Match
+		faults[i] = fault
+		ch.state.Store(&State{Faults: faults, Enabled: state.Enabled})
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownFault, fault.Name)
+}
+
+// AdminRoutes registers the endpoints of the faults, e.g. for fiber.AdminConfig.Routes:
+//
+//	GET /chaos              the state of the faults
+//	PUT /chaos              enables or disables the faults, {"enabled": true}
+//	PUT /chaos/faults/:name changes the settings of a fault, {"percentage": 10, "status": 503}
+func (ch *Chaos) AdminRoutes(router fiber.Router) {
+	router.Get("/chaos", func(c fiber.Ctx) error {
+		return c.JSON(ch.State())
+	})
+
+	router.Put("/chaos", func(c fiber.Ctx) error {
+		var body struct {
+			Enabled bool `json:"enabled"`
+		}
+		if err := c.App().Config().JSONDecoder(c.Body(), &body); err != nil {
+			return fiber.ErrBadRequest
+		}
+		ch.SetEnabled(body.Enabled)
+		log.Infow("chaos: faults changed", "enabled", body.Enabled)
+		return c.JSON(ch.State())
+	})
+
+	router.Put("/chaos/faults/:name", func(c fiber.Ctx) error {
+		var fault Fault
+		if err := c.App().Config().JSONDecoder(c.Body(), &fault); err != nil {
+			return fiber.ErrBadRequest
+		}
+		fault.Name = utils.CopyString(c.Params("name"))
+		if err := ch.SetFault(fault); err != nil {
+			if errors.Is(err, ErrUnknownFault) {
+				return fiber.NewError(fiber.StatusNotFound, err.Error())
+			}
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		log.Infow("chaos: fault changed", "fault", fault.Name, "percentage", fault.Percentage)
+		return c.JSON(ch.State())
+	})
+}
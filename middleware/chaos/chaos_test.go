@@ -0,0 +1,233 @@
+package chaos
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/skip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+func testApp(ch *Chaos) *fiber.App {
+	app := fiber.New()
+	app.Use(ch.Handler())
+	app.Get("/*", func(c fiber.Ctx) error {
+		return c.SendString("0123456789")
+	})
+	return app
+}
+
+// go test -run Test_Chaos_Disabled
+func Test_Chaos_Disabled(t *testing.T) {
+	t.Parallel()
+	ch := New(Config{
+		Faults: []Fault{{Percentage: 100, Status: fiber.StatusServiceUnavailable}},
+	})
+	app := testApp(ch)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	ch.SetEnabled(true)
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+}
+
+// go test -run Test_Chaos_Faults
+func Test_Chaos_Faults(t *testing.T) {
+	t.Parallel()
+	app := testApp(New(Config{
+		Enabled: true,
+		Faults: []Fault{
+			{Match: skip.Paths("/slow"), Percentage: 100, Latency: 50 * time.Millisecond},
+			{Match: skip.Paths("/error"), Percentage: 100, Status: fiber.StatusBadGateway},
+			{Match: skip.Paths("/reset"), Percentage: 100, Reset: true},
+			{Match: skip.Paths("/partial"), Percentage: 100, Partial: 0.5},
+			{Match: skip.Paths("/never"), Percentage: 0, Status: fiber.StatusBadGateway},
+		},
+	}))
+
+	start := time.Now()
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/slow", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/error", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadGateway, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/never", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// app.Test can't read the hijacked connections
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln, fiber.ListenConfig{DisableStartupMessage: true}))
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, app.Shutdown())
+	})
+	request := func(path string) (*http.Response, error) {
+		var conn net.Conn
+		require.Eventually(t, func() bool {
+			var err error
+			conn, err = ln.Dial()
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+		defer conn.Close() //nolint:errcheck // It is fine to ignore the error here
+		_, err := conn.Write([]byte("GET " + path + " HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+		require.NoError(t, err)
+		return http.ReadResponse(bufio.NewReader(conn), nil)
+	}
+
+	// the connection is closed without a response
+	_, err = request("/reset")
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// the body is shorter than its length
+	resp, err = request("/partial")
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, int64(10), resp.ContentLength)
+	body, err := io.ReadAll(resp.Body)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.Equal(t, "01234", string(body))
+}
+
+// go test -run Test_Chaos_Rand
+func Test_Chaos_Rand(t *testing.T) {
+	t.Parallel()
+	statuses := func() []int {
+		app := testApp(New(Config{
+			Enabled: true,
+			Faults:  []Fault{{Percentage: 50, Status: fiber.StatusBadGateway}},
+			Rand:    fiber.NewRand(1),
+		}))
+		var statuses []int
+		for i := 0; i < 20; i++ {
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			require.NoError(t, err)
+			statuses = append(statuses, resp.StatusCode)
+		}
+		return statuses
+	}
+
+	// the faults of the same seed are injected into the same requests
+	first := statuses()
+	require.Contains(t, first, fiber.StatusOK)
+	require.Contains(t, first, fiber.StatusBadGateway)
+	require.Equal(t, first, statuses())
+}
+
+// go test -run Test_Chaos_Clock
+func Test_Chaos_Clock(t *testing.T) {
+	t.Parallel()
+	clock := fiber.NewManualClock(time.Now())
+	app := testApp(New(Config{
+		Enabled: true,
+		Faults:  []Fault{{Percentage: 100, Latency: time.Hour}},
+		Clock:   clock,
+	}))
+
+	done := make(chan int, 1)
+	go func() {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil), -1)
+		assert.NoError(t, err)
+		done <- resp.StatusCode
+	}()
+
+	// the latency waits for the clock instead of an hour
+	require.NoError(t, clock.WaitForTimers(context.Background(), 1))
+	clock.Advance(time.Hour)
+	require.Equal(t, fiber.StatusOK, <-done)
+}
+
+// go test -run Test_Chaos_Next
+func Test_Chaos_Next(t *testing.T) {
+	t.Parallel()
+	app := testApp(New(Config{
+		Next: func(fiber.Ctx) bool {
+			return true
+		},
+		Enabled: true,
+		Faults:  []Fault{{Percentage: 100, Status: fiber.StatusBadGateway}},
+	}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_Chaos_InvalidFault
+func Test_Chaos_InvalidFault(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New(Config{Faults: []Fault{{Percentage: 120}}})
+	})
+
+	ch := New(Config{Faults: []Fault{{Name: "errors"}}})
+	require.ErrorIs(t, ch.SetFault(Fault{Name: "errors", Partial: 2}), ErrInvalidFault)
+	require.ErrorIs(t, ch.SetFault(Fault{Name: "errors", Status: fiber.StatusOK}), ErrInvalidFault)
+	require.ErrorIs(t, ch.SetFault(Fault{Name: "latency"}), ErrUnknownFault)
+}
+
+// go test -run Test_Chaos_AdminRoutes
+func Test_Chaos_AdminRoutes(t *testing.T) {
+	t.Parallel()
+	ch := New(Config{
+		Faults: []Fault{{Name: "errors", Match: skip.Paths("/api/*")}},
+	})
+	app := testApp(ch)
+	admin := app.AdminApp(fiber.AdminConfig{
+		Network: fiber.NetworkUnix,
+		Routes:  ch.AdminRoutes,
+	})
+	request := func(method, path, body string) (int, string) {
+		resp, err := admin.Test(httptest.NewRequest(method, path, strings.NewReader(body)))
+		require.NoError(t, err)
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := request(fiber.MethodGet, "/chaos", "")
+	require.Equal(t, fiber.StatusOK, status)
+	require.Contains(t, body, `"enabled":false`)
+	require.Contains(t, body, `"name":"errors","percentage":0`)
+
+	status, _ = request(fiber.MethodPut, "/chaos/faults/errors", `{"percentage":100,"status":503}`)
+	require.Equal(t, fiber.StatusOK, status)
+	status, body = request(fiber.MethodPut, "/chaos", `{"enabled":true}`)
+	require.Equal(t, fiber.StatusOK, status)
+	require.Contains(t, body, `"enabled":true`)
+	require.Contains(t, body, `"percentage":100`)
+
+	// the predicate of the fault is kept
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/users", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/health", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	status, _ = request(fiber.MethodPut, "/chaos/faults/latency", `{"percentage":10}`)
+	require.Equal(t, fiber.StatusNotFound, status)
+	status, _ = request(fiber.MethodPut, "/chaos/faults/errors", `{"percentage":-1}`)
+	require.Equal(t, fiber.StatusBadRequest, status)
+	status, _ = request(fiber.MethodPut, "/chaos", `{`)
+	require.Equal(t, fiber.StatusBadRequest, status)
+}
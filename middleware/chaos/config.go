@@ -0,0 +1,122 @@
+package chaos
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Faults are the faults which are injected into the requests.
+	//
+	// Optional. Default: nil
+	Faults []Fault
+
+	// Enabled injects the faults from the start, otherwise they are injected
+	// after they were enabled, e.g. with the admin API.
+	//
+	// Optional. Default: false
+	Enabled bool
+
+	// Clock is the source of the time of the latencies, e.g. a fiber.ManualClock in tests.
+	//
+	// Optional. Default: fiber.SystemClock()
+	Clock fiber.Clock
+
+	// Rand decides which requests the faults are injected into, e.g. fiber.NewRand in tests.
+	//
+	// Optional. Default: fiber.SystemRand()
+	Rand fiber.Rand
+}
+
+// Fault is a fault which is injected into a percentage of the matching requests.
+// The latency is injected before the request is handled, the error and the reset
+// replace the handlers, the partial response truncates the response of the handlers.
+type Fault struct {
+	// Match returns true if the fault is injected into the request,
+	// e.g. skip.Paths("/api/*"). Its settings can't be changed with the admin API.
+	//
+	// Optional. Default: nil (all requests)
+	Match func(c fiber.Ctx) bool `json:"-"`
+
+	// Name identifies the fault in the admin API.
+	//
+	// Optional. Default: "fault-<index>"
+	Name string `json:"name"`
+
+	// Percentage of the matching requests the fault is injected into, from 0 to 100.
+	//
+	// Optional. Default: 0
+	Percentage float64 `json:"percentage"`
+
+	// Latency delays the request.
+	//
+	// Optional. Default: 0
+	Latency time.Duration `json:"latency"`
+
+	// Jitter adds a random delay of up to Jitter to the Latency.
+	//
+	// Optional. Default: 0
+	Jitter time.Duration `json:"jitter"`
+
+	// Status responds with an error of the status code instead of handling the request, e.g. 503.
+	//
+	// Optional. Default: 0
+	Status int `json:"status"`
+
+	// Reset closes the connection without a response instead of handling the request.
+	//
+	// Optional. Default: false
+	Reset bool `json:"reset"`
+
+	// Partial sends the fraction of the response body, from 0 to 1, with the headers of the
+	// whole response and closes the connection, like a connection which broke during the transfer.
+	//
+	// Optional. Default: 0
+	Partial float64 `json:"partial"`
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:    nil,
+	Faults:  nil,
+	Enabled: false,
+	Clock:   fiber.SystemClock(),
+	Rand:    fiber.SystemRand(),
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Clock == nil {
+		cfg.Clock = ConfigDefault.Clock
+	}
+	if cfg.Rand == nil {
+		cfg.Rand = ConfigDefault.Rand
+	}
+
+	// Copy the faults, they are changed at runtime
+	cfg.Faults = append([]Fault(nil), cfg.Faults...)
+	for i := range cfg.Faults {
+		if cfg.Faults[i].Name == "" {
+			cfg.Faults[i].Name = "fault-" + strconv.Itoa(i)
+		}
+	}
+
+	return cfg
+}
@@ -2,6 +2,7 @@ package compress
 
 import (
 	"github.com/gofiber/fiber/v3"
+	"github.com/klauspost/compress/zstd"
 	"github.com/valyala/fasthttp"
 )
 
@@ -43,6 +44,16 @@ func New(config ...Config) fiber.Handler {
 		}
 	}
 
+	// Setup zstd encoders
+	var zstdEncoder *zstd.Encoder
+	if cfg.Zstd {
+		zstdEncoder = newZstdEncoder(cfg)
+	}
+	dicts := make([]dictionary, len(cfg.Dictionaries))
+	for i, dict := range cfg.Dictionaries {
+		dicts[i] = newDictionary(cfg, dict)
+	}
+
 	// Return new handler
 	return func(c fiber.Ctx) error {
 		// Don't execute middleware if Next returns true
@@ -56,6 +67,9 @@ func New(config ...Config) fiber.Handler {
 		}
 
 		// Compress response
+		if (zstdEncoder != nil || len(dicts) > 0) && compressZstd(c, zstdEncoder, dicts) {
+			return nil
+		}
 		compressor(c.Context())
 
 		// Return from handler
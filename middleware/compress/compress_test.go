@@ -1,15 +1,19 @@
 package compress
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/require"
 )
 
@@ -191,3 +195,126 @@ func Test_Compress_Next(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
 }
+
+// go test -run Test_Compress_Zstd
+func Test_Compress_Zstd(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	app.Use(New(Config{Zstd: true, ZstdWindowSize: 1 << 16}))
+
+	app.Get("/", func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+		return c.Send(filedata)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br, zstd")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err, "app.Test(req)")
+	require.Equal(t, 200, resp.StatusCode, "Status code")
+	require.Equal(t, "zstd", resp.Header.Get(fiber.HeaderContentEncoding))
+	require.Equal(t, fiber.HeaderAcceptEncoding, resp.Header.Get(fiber.HeaderVary))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Less(t, len(body), len(filedata))
+
+	// The window doesn't exceed the configured size
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxWindow(1<<16))
+	require.NoError(t, err)
+	defer decoder.Close()
+	decoded, err := decoder.DecodeAll(body, nil)
+	require.NoError(t, err)
+	require.Equal(t, filedata, decoded)
+
+	// brotli is used if zstd isn't accepted
+	req = httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip, br")
+	resp, err = app.Test(req)
+	require.NoError(t, err, "app.Test(req)")
+	require.Equal(t, "br", resp.Header.Get(fiber.HeaderContentEncoding))
+}
+
+// go test -run Test_Compress_Dictionary
+func Test_Compress_Dictionary(t *testing.T) {
+	t.Parallel()
+	dict := Dictionary{
+		ContentTypes: []string{fiber.MIMEApplicationJSON},
+		Data:         []byte(`{"id":0,"name":"","email":"","created_at":"2024-01-01T00:00:00Z","roles":["admin","user"]}`),
+		Match:        "/api/*",
+	}
+	hash := sha256.Sum256(dict.Data)
+	availableDictionary := ":" + base64.StdEncoding.EncodeToString(hash[:]) + ":"
+	payload := []byte(`[` + strings.Repeat(`{"id":1,"name":"john","email":"john@example.com","created_at":"2024-05-01T10:00:00Z","roles":["user"]},`, 3) + `{}]`)
+
+	app := fiber.New()
+	app.Use(New(Config{Dictionaries: []Dictionary{dict}}))
+	app.Get("/dictionaries/api", DictionaryHandler(dict))
+	app.Get("/api/users", func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return c.Send(payload)
+	})
+
+	// The dictionary is served with the Use-As-Dictionary header
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/dictionaries/api", nil))
+	require.NoError(t, err, "app.Test(req)")
+	require.Equal(t, `match="/api/*"`, resp.Header.Get(fiber.HeaderUseAsDictionary))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, dict.Data, body)
+
+	// The response is compressed with the dictionary, if the client has it
+	req := httptest.NewRequest(fiber.MethodGet, "/api/users", nil)
+	req.Header.Set(fiber.HeaderAcceptEncoding, "gzip, br, zstd, dcz")
+	req.Header.Set(fiber.HeaderAvailableDictionary, availableDictionary)
+	resp, err = app.Test(req)
+	require.NoError(t, err, "app.Test(req)")
+	require.Equal(t, "dcz", resp.Header.Get(fiber.HeaderContentEncoding))
+	require.Equal(t, "Accept-Encoding, Available-Dictionary", resp.Header.Get(fiber.HeaderVary))
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x5e, 0x2a, 0x4d, 0x18, 0x20, 0, 0, 0}, body[:8])
+	require.Equal(t, hash[:], body[8:40])
+
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderDictRaw(0, dict.Data))
+	require.NoError(t, err)
+	defer decoder.Close()
+	decoded, err := decoder.DecodeAll(body[40:], nil)
+	require.NoError(t, err)
+	require.Equal(t, payload, decoded)
+
+	// The dictionary improves the compression
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	require.Less(t, len(body[40:]), len(encoder.EncodeAll(payload, nil)))
+
+	// Other dictionaries and encodings fall back to brotli
+	for _, header := range []map[string]string{
+		{fiber.HeaderAcceptEncoding: "br, dcz", fiber.HeaderAvailableDictionary: ":AAAA:"},
+		{fiber.HeaderAcceptEncoding: "br", fiber.HeaderAvailableDictionary: availableDictionary},
+	} {
+		req = httptest.NewRequest(fiber.MethodGet, "/api/users", nil)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		resp, err = app.Test(req)
+		require.NoError(t, err, "app.Test(req)")
+		require.Equal(t, "br", resp.Header.Get(fiber.HeaderContentEncoding))
+	}
+}
+
+// go test -run Test_Compress_Config_Panic
+func Test_Compress_Config_Panic(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New(Config{Zstd: true, ZstdWindowSize: 16 << 20})
+	})
+	require.Panics(t, func() {
+		New(Config{Zstd: true, ZstdWindowSize: 3000})
+	})
+	require.Panics(t, func() {
+		New(Config{Dictionaries: []Dictionary{{ContentTypes: []string{fiber.MIMEApplicationJSON}}}})
+	})
+}
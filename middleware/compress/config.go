@@ -2,6 +2,7 @@ package compress
 
 import (
 	"github.com/gofiber/fiber/v3"
+	"github.com/klauspost/compress/zstd"
 )
 
 // Config defines the config for middleware.
@@ -19,6 +20,50 @@ type Config struct {
 	// LevelBestSpeed:        1
 	// LevelBestCompression:  2
 	Level Level
+
+	// Zstd enables the zstd encoding (RFC 8878). If the client accepts it,
+	// zstd is preferred over brotli, gzip and deflate.
+	//
+	// Optional. Default: false
+	Zstd bool
+
+	// ZstdWindowSize is the maximum window size of the zstd encoder in bytes,
+	// a power of two between 1 KB and 8 MB. Clients only have to support windows
+	// up to 8 MB (RFC 9659), smaller windows reduce the memory used to decode the responses.
+	//
+	// Optional. Default: 8 MB
+	ZstdWindowSize int
+
+	// Dictionaries are pre-trained shared dictionaries, which are selected by the content type
+	// of the response. If the client announces the hash of a dictionary with the
+	// Available-Dictionary header and accepts the "dcz" encoding, the response is compressed
+	// with zstd and the dictionary (RFC 9842). The dictionaries are served to the clients
+	// with DictionaryHandler.
+	//
+	// Optional. Default: nil
+	Dictionaries []Dictionary
+}
+
+// Dictionary is a shared compression dictionary, e.g. a sample of typical responses
+// of an API. Repetitive payloads, e.g. small JSON responses with the same keys,
+// are compressed much better with a dictionary.
+type Dictionary struct {
+	// ContentTypes of the responses which are compressed with the dictionary, e.g. "application/json".
+	// The parameters of the content types are ignored.
+	//
+	// Required
+	ContentTypes []string
+
+	// Data is the raw content of the dictionary.
+	//
+	// Required
+	Data []byte
+
+	// Match is the URL pattern of the requests which can use the dictionary, e.g. "/api/*".
+	// It's sent with the Use-As-Dictionary header by DictionaryHandler.
+	//
+	// Optional. Default: "/*"
+	Match string
 }
 
 // Level is numeric representation of compression level
@@ -34,10 +79,14 @@ const (
 
 // ConfigDefault is the default config
 var ConfigDefault = Config{
-	Next:  nil,
-	Level: LevelDefault,
+	Next:           nil,
+	Level:          LevelDefault,
+	ZstdWindowSize: maxZstdWindowSize,
 }
 
+// maxZstdWindowSize is the maximum window size clients have to support for the zstd encoding.
+const maxZstdWindowSize = 8 << 20
+
 // Helper function to set default values
 func configDefault(config ...Config) Config {
 	// Return default config if nothing provided
@@ -52,5 +101,17 @@ func configDefault(config ...Config) Config {
 	if cfg.Level < LevelDisabled || cfg.Level > LevelBestCompression {
 		cfg.Level = ConfigDefault.Level
 	}
+	if cfg.ZstdWindowSize == 0 {
+		cfg.ZstdWindowSize = ConfigDefault.ZstdWindowSize
+	}
+	if cfg.ZstdWindowSize < zstd.MinWindowSize || cfg.ZstdWindowSize > maxZstdWindowSize ||
+		cfg.ZstdWindowSize&(cfg.ZstdWindowSize-1) != 0 {
+		panic("compress: ZstdWindowSize must be a power of two between 1 KB and 8 MB")
+	}
+	for _, dict := range cfg.Dictionaries {
+		if len(dict.ContentTypes) == 0 || len(dict.Data) == 0 {
+			panic("compress: the ContentTypes and the Data of a dictionary cannot be empty")
+		}
+	}
 	return cfg
 }
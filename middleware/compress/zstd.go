@@ -0,0 +1,156 @@
+package compress
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	encodingZstd = "zstd"
+	encodingDCZ  = "dcz"
+)
+
+// minCompressLen is the minimum length of the compressed bodies,
+// smaller bodies would hardly be smaller when they're compressed, like in fasthttp.
+const minCompressLen = 200
+
+// dczMagic is the start of the skippable zstd frame which prefixes the "dcz" encoded bodies
+// with the SHA-256 hash of the dictionary (RFC 9842).
+var dczMagic = []byte{0x5e, 0x2a, 0x4d, 0x18, 0x20, 0x00, 0x00, 0x00}
+
+// dictionary is a Dictionary with its encoder.
+type dictionary struct {
+	encoder      *zstd.Encoder
+	contentTypes []string
+	// hash is the value of the Available-Dictionary header which announces the dictionary
+	hash string
+	// header is the prefix of the compressed bodies
+	header []byte
+}
+
+// newZstdEncoder creates a zstd encoder for the config and the encoder options.
+func newZstdEncoder(cfg Config, opts ...zstd.EOption) *zstd.Encoder {
+	level := zstd.SpeedDefault
+	switch cfg.Level {
+	case LevelBestSpeed:
+		level = zstd.SpeedFastest
+	case LevelBestCompression:
+		level = zstd.SpeedBestCompression
+	default:
+	}
+
+	encoder, err := zstd.NewWriter(nil, append([]zstd.EOption{
+		zstd.WithEncoderLevel(level),
+		zstd.WithWindowSize(cfg.ZstdWindowSize),
+	}, opts...)...)
+	if err != nil {
+		panic(err)
+	}
+	return encoder
+}
+
+// newDictionary creates the encoder of the dictionary.
+func newDictionary(cfg Config, dict Dictionary) dictionary {
+	hash := sha256.Sum256(dict.Data)
+	contentTypes := make([]string, len(dict.ContentTypes))
+	for i, contentType := range dict.ContentTypes {
+		contentTypes[i] = mediaType(contentType)
+	}
+
+	return dictionary{
+		encoder:      newZstdEncoder(cfg, zstd.WithEncoderDictRaw(0, dict.Data)),
+		contentTypes: contentTypes,
+		hash:         ":" + base64.StdEncoding.EncodeToString(hash[:]) + ":",
+		header:       append(append([]byte{}, dczMagic...), hash[:]...),
+	}
+}
+
+// compressZstd compresses the response with a dictionary or zstd, if the client accepts it.
+// It returns false if the response wasn't compressed.
+func compressZstd(c fiber.Ctx, encoder *zstd.Encoder, dicts []dictionary) bool {
+	res := c.Response()
+	if len(res.Header.ContentEncoding()) > 0 || res.IsBodyStream() || len(res.Body()) < minCompressLen {
+		return false
+	}
+	contentType := mediaType(c.GetRespHeader(fiber.HeaderContentType))
+
+	// Compress with a shared dictionary
+	for _, dict := range dicts {
+		if !dict.matches(contentType) {
+			continue
+		}
+		c.VaryBy(fiber.HeaderAcceptEncoding, fiber.HeaderAvailableDictionary)
+		if !c.Request().Header.HasAcceptEncoding(encodingDCZ) ||
+			strings.TrimSpace(c.Get(fiber.HeaderAvailableDictionary)) != dict.hash {
+			continue
+		}
+		setCompressedBody(c, dict.encoder.EncodeAll(res.Body(), dict.header), encodingDCZ)
+		return true
+	}
+
+	// Compress with zstd
+	if encoder == nil || !c.Request().Header.HasAcceptEncoding(encodingZstd) || !isCompressible(contentType) {
+		return false
+	}
+	c.VaryBy(fiber.HeaderAcceptEncoding)
+	setCompressedBody(c, encoder.EncodeAll(res.Body(), nil), encodingZstd)
+	return true
+}
+
+// setCompressedBody replaces the body of the response with the compressed body.
+func setCompressedBody(c fiber.Ctx, body []byte, encoding string) {
+	c.Response().SetBodyRaw(body)
+	c.Response().Header.SetContentEncoding(encoding)
+}
+
+// matches reports whether the dictionary is used for the content type.
+func (d *dictionary) matches(contentType string) bool {
+	for _, t := range d.contentTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaType returns the lowercase media type of the content type without the parameters.
+func mediaType(contentType string) string {
+	if i := strings.IndexByte(contentType, ';'); i != -1 {
+		contentType = contentType[:i]
+	}
+	return utils.ToLower(strings.TrimSpace(contentType))
+}
+
+// isCompressible reports whether the content type is compressed, like in fasthttp.
+func isCompressible(contentType string) bool {
+	for _, prefix := range [...]string{"text/", "application/", "image/svg", "image/x-icon", "font/", "multipart/"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DictionaryHandler serves the dictionary to the clients. The Use-As-Dictionary header
+// tells the clients to announce the dictionary for the requests matching dict.Match.
+// The clients keep the dictionary in the HTTP cache, so the response should be cacheable.
+//
+//	app.Get("/dictionaries/api", compress.DictionaryHandler(dict))
+func DictionaryHandler(dict Dictionary) fiber.Handler {
+	match := dict.Match
+	if match == "" {
+		match = "/*"
+	}
+	useAsDictionary := `match="` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(match) + `"`
+
+	return func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderUseAsDictionary, useAsDictionary)
+		c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+		return c.Send(dict.Data)
+	}
+}
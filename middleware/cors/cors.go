@@ -174,7 +174,7 @@ func New(config ...Config) fiber.Handler {
 			// See https://fetch.spec.whatwg.org/#cors-protocol-and-http-caches
 			// Unless all origins are allowed, we include the Vary header to cache the response correctly
 			if !allowAllOrigins {
-				c.Vary(fiber.HeaderOrigin)
+				c.VaryBy(fiber.HeaderOrigin)
 			}
 
 			return c.Next()
@@ -186,7 +186,7 @@ func New(config ...Config) fiber.Handler {
 			// some caching can be configured to cache such responses.
 			// To Avoid poisoning the cache, we include the Vary header
 			// for non-CORS OPTIONS requests:
-			c.Vary(fiber.HeaderOrigin)
+			c.VaryBy(fiber.HeaderOrigin)
 			return c.Next()
 		}
 
@@ -228,7 +228,7 @@ func New(config ...Config) fiber.Handler {
 		if c.Method() != fiber.MethodOptions {
 			if !allowAllOrigins {
 				// See https://fetch.spec.whatwg.org/#cors-protocol-and-http-caches
-				c.Vary(fiber.HeaderOrigin)
+				c.VaryBy(fiber.HeaderOrigin)
 			}
 			setCORSHeaders(c, allowOrigin, "", "", exposeHeaders, maxAge, cfg)
 			return c.Next()
@@ -240,13 +240,13 @@ func New(config ...Config) fiber.Handler {
 		// some caching can be configured to cache such responses.
 		// To Avoid poisoning the cache, we include the Vary header
 		// of preflight responses:
-		c.Vary(fiber.HeaderAccessControlRequestMethod)
-		c.Vary(fiber.HeaderAccessControlRequestHeaders)
+		c.VaryBy(fiber.HeaderAccessControlRequestMethod)
+		c.VaryBy(fiber.HeaderAccessControlRequestHeaders)
 		if cfg.AllowPrivateNetwork && c.Get(fiber.HeaderAccessControlRequestPrivateNetwork) == "true" {
-			c.Vary(fiber.HeaderAccessControlRequestPrivateNetwork)
+			c.VaryBy(fiber.HeaderAccessControlRequestPrivateNetwork)
 			c.Set(fiber.HeaderAccessControlAllowPrivateNetwork, "true")
 		}
-		c.Vary(fiber.HeaderOrigin)
+		c.VaryBy(fiber.HeaderOrigin)
 
 		setCORSHeaders(c, allowOrigin, allowMethods, allowHeaders, exposeHeaders, maxAge, cfg)
 
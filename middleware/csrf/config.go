@@ -107,6 +107,12 @@ type Config struct {
 	// Optional. Default: utils.UUID
 	KeyGenerator func() string
 
+	// Keyring signs the tokens, so forged tokens are rejected before the storage
+	// is accessed. The tokens of the retired keys stay valid until they expire.
+	//
+	// Optional. Default: nil
+	Keyring *fiber.Keyring
+
 	// ErrorHandler is executed when an error is returned from fiber.Handler.
 	//
 	// Optional. Default: DefaultErrorHandler
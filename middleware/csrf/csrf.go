@@ -29,6 +29,9 @@ type Handler struct {
 	storageManager *storageManager
 }
 
+// keyringPurpose separates the signatures of the tokens from the other signatures of the keyring.
+const keyringPurpose = "fiber/csrf"
+
 // The contextKey type is unexported to prevent collisions with context keys defined in
 // other packages.
 type contextKey int
@@ -103,7 +106,7 @@ func New(config ...Config) fiber.Handler {
 		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
 			cookieToken := c.Cookies(cfg.CookieName)
 
-			if cookieToken != "" {
+			if cookieToken != "" && validSignature(cfg, cookieToken) {
 				raw := getRawFromStorage(c, cookieToken, cfg, sessionManager, storageManager)
 
 				if raw != nil {
@@ -141,6 +144,10 @@ func New(config ...Config) fiber.Handler {
 				return cfg.ErrorHandler(c, ErrTokenNotFound)
 			}
 
+			if !validSignature(cfg, extractedToken) {
+				return cfg.ErrorHandler(c, ErrTokenInvalid)
+			}
+
 			// If not using FromCookie extractor, check that the token matches the cookie
 			// This is to prevent CSRF attacks by using a Double Submit Cookie method
 			// Useful when we do not have access to the users Session
@@ -168,6 +175,9 @@ func New(config ...Config) fiber.Handler {
 		if token == "" {
 			// And generate a new token
 			token = cfg.KeyGenerator()
+			if cfg.Keyring != nil {
+				token = cfg.Keyring.SignValue(keyringPurpose, token)
+			}
 		}
 
 		// Create or extend the token in the storage
@@ -177,7 +187,7 @@ func New(config ...Config) fiber.Handler {
 		updateCSRFCookie(c, cfg, token)
 
 		// Tell the browser that a new header value is generated
-		c.Vary(fiber.HeaderCookie)
+		c.VaryBy(fiber.HeaderCookie)
 
 		// Store the token in the context
 		c.Locals(tokenKey, token)
@@ -187,6 +197,15 @@ func New(config ...Config) fiber.Handler {
 	}
 }
 
+// validSignature reports if the token is signed by the keyring, if it is set.
+func validSignature(cfg Config, token string) bool {
+	if cfg.Keyring == nil {
+		return true
+	}
+	_, ok := cfg.Keyring.VerifyValue(keyringPurpose, token)
+	return ok
+}
+
 // TokenFromContext returns the token found in the context
 // returns an empty string if the token does not exist
 func TokenFromContext(c fiber.Ctx) string {
@@ -66,6 +66,48 @@ func Test_CSRF(t *testing.T) {
 	}
 }
 
+// go test -run Test_CSRF_Keyring
+func Test_CSRF_Keyring(t *testing.T) {
+	t.Parallel()
+	keyring := fiber.NewKeyring(fiber.KeyringConfig{Keys: []fiber.Key{{ID: "v1", Value: []byte("secret-1")}}})
+	app := fiber.New()
+	app.Use(New(Config{Keyring: keyring}))
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	h := app.Handler()
+	ctx := &fasthttp.RequestCtx{}
+
+	// Generate a signed CSRF token
+	ctx.Request.Header.SetMethod(fiber.MethodGet)
+	h(ctx)
+	token := string(ctx.Response.Header.Peek(fiber.HeaderSetCookie))
+	token = strings.Split(strings.Split(token, ";")[0], "=")[1]
+	_, ok := keyring.VerifyValue(keyringPurpose, token)
+	require.True(t, ok)
+
+	// The token of the retired key is valid
+	keyring.Rotate(fiber.Key{ID: "v2", Value: []byte("secret-2")})
+	ctx.Request.Reset()
+	ctx.Response.Reset()
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	ctx.Request.Header.Set(HeaderName, token)
+	ctx.Request.Header.SetCookie(ConfigDefault.CookieName, token)
+	h(ctx)
+	require.Equal(t, 200, ctx.Response.StatusCode())
+
+	// A forged token is rejected
+	forged := strings.Split(token, ".")[0] + ".v2:forged"
+	ctx.Request.Reset()
+	ctx.Response.Reset()
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	ctx.Request.Header.Set(HeaderName, forged)
+	ctx.Request.Header.SetCookie(ConfigDefault.CookieName, forged)
+	h(ctx)
+	require.Equal(t, 403, ctx.Response.StatusCode())
+}
+
 func Test_CSRF_WithSession(t *testing.T) {
 	t.Parallel()
 
@@ -34,7 +34,7 @@ func newStorageManager(storage fiber.Storage) *storageManager {
 		storageManager.storage = storage
 	} else {
 		// Fallback too memory storage
-		storageManager.memory = memory.New()
+		storageManager.memory = memory.New(nil)
 	}
 	return storageManager
 }
@@ -22,6 +22,26 @@ type Config struct {
 	// You may use `encryptcookie.GenerateKey()` to generate a new key.
 	Key string
 
+	// Secrets provides the key, which is rotated when the secret changes.
+	// The cookies which were encrypted with the previous key are still decrypted.
+	//
+	// Optional. Default: nil
+	Secrets fiber.SecretsProvider
+
+	// KeySecret is the name of the secret of the key, if Secrets is set.
+	// Key is ignored if it is set.
+	//
+	// Optional. Default: ""
+	KeySecret string
+
+	// Keyring provides the keys, the cookies are encrypted with a 32-byte key which is
+	// derived from the active key and decrypted with the key of their ID, so the cookies
+	// of the retired keys are still decrypted.
+	// Key and Secrets are ignored if it is set.
+	//
+	// Optional. Default: nil
+	Keyring *fiber.Keyring
+
 	// Custom function to encrypt cookies.
 	//
 	// Optional. Default: EncryptCookie
@@ -70,7 +90,7 @@ func configDefault(config ...Config) Config {
 		}
 	}
 
-	if cfg.Key == "" {
+	if cfg.Key == "" && (cfg.Secrets == nil || cfg.KeySecret == "") && cfg.Keyring == nil {
 		panic("fiber: encrypt cookie middleware requires key")
 	}
 
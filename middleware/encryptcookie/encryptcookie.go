@@ -1,15 +1,74 @@
 package encryptcookie
 
 import (
+	"encoding/base64"
+	"errors"
+	"strings"
+
 	"github.com/gofiber/fiber/v3"
 	"github.com/valyala/fasthttp"
 )
 
+// errKeyNotFound is returned if the key of an encrypted cookie isn't in the keyring.
+var errKeyNotFound = errors.New("encryptcookie: key not found")
+
+// keyringPurpose separates the encryption keys of the cookies from the other keys of the keyring.
+const keyringPurpose = "fiber/encryptcookie"
+
 // New creates a new middleware handler
 func New(config ...Config) fiber.Handler {
 	// Set default config
 	cfg := configDefault(config...)
 
+	// The current and the previous key
+	keys := func() (string, string) {
+		return cfg.Key, ""
+	}
+	if cfg.Secrets != nil && cfg.KeySecret != "" {
+		secret := fiber.MustLoadSecret(cfg.Secrets, cfg.KeySecret)
+		keys = func() (string, string) {
+			return string(secret.Value()), string(secret.Previous())
+		}
+	}
+
+	encrypt := func(value string) (string, error) {
+		currentKey, _ := keys()
+		return cfg.Encryptor(value, currentKey)
+	}
+	decrypt := func(value string) (string, error) {
+		currentKey, previousKey := keys()
+		decryptedValue, err := cfg.Decryptor(value, currentKey)
+		if err != nil && previousKey != "" {
+			// the cookie was encrypted before the key was rotated
+			decryptedValue, err = cfg.Decryptor(value, previousKey)
+		}
+		return decryptedValue, err
+	}
+
+	// The values are prefixed with the ID of the key of the keyring,
+	// they are encrypted with a key which is derived from the key
+	if cfg.Keyring != nil {
+		encrypt = func(value string) (string, error) {
+			key := cfg.Keyring.Active()
+			encryptedValue, err := cfg.Encryptor(value, base64.StdEncoding.EncodeToString(key.EncryptionKey(keyringPurpose)))
+			if err != nil {
+				return "", err
+			}
+			return key.ID + ":" + encryptedValue, nil
+		}
+		decrypt = func(value string) (string, error) {
+			id, encryptedValue, ok := strings.Cut(value, ":")
+			if !ok {
+				return "", errKeyNotFound
+			}
+			key, ok := cfg.Keyring.Lookup(id)
+			if !ok {
+				return "", errKeyNotFound
+			}
+			return cfg.Decryptor(encryptedValue, base64.StdEncoding.EncodeToString(key.EncryptionKey(keyringPurpose)))
+		}
+	}
+
 	// Return new handler
 	return func(c fiber.Ctx) error {
 		// Don't execute middleware if Next returns true
@@ -21,7 +80,7 @@ func New(config ...Config) fiber.Handler {
 		c.Request().Header.VisitAllCookie(func(key, value []byte) {
 			keyString := string(key)
 			if !isDisabled(keyString, cfg.Except) {
-				decryptedValue, err := cfg.Decryptor(string(value), cfg.Key)
+				decryptedValue, err := decrypt(string(value))
 				if err != nil {
 					c.Request().Header.SetCookieBytesKV(key, nil)
 				} else {
@@ -40,7 +99,7 @@ func New(config ...Config) fiber.Handler {
 				cookieValue := fasthttp.Cookie{}
 				cookieValue.SetKeyBytes(key)
 				if c.Response().Header.Cookie(&cookieValue) {
-					encryptedValue, err := cfg.Encryptor(string(cookieValue.Value()), cfg.Key)
+					encryptedValue, err := encrypt(string(cookieValue.Value()))
 					if err != nil {
 						panic(err)
 					}
@@ -1,9 +1,13 @@
 package encryptcookie
 
 import (
+	"context"
 	"encoding/base64"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/stretchr/testify/require"
@@ -189,3 +193,157 @@ func Test_Encrypt_Cookie_Custom_Encryptor(t *testing.T) {
 	require.Equal(t, 200, ctx.Response.StatusCode())
 	require.Equal(t, "value=SomeThing", string(ctx.Response.Body()))
 }
+
+// testSecrets is a fiber.SecretsProvider whose key can be rotated.
+type testSecrets struct {
+	mutex   sync.Mutex
+	key     []byte
+	watcher func(value []byte)
+}
+
+func (s *testSecrets) Get(_ context.Context, _ string) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.key, nil
+}
+
+func (s *testSecrets) Watch(ctx context.Context, _ string, fn func(value []byte)) error {
+	s.mutex.Lock()
+	s.watcher = fn
+	s.mutex.Unlock()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (s *testSecrets) rotate(t *testing.T, key string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return s.watcher != nil
+	}, time.Second, time.Millisecond)
+
+	s.mutex.Lock()
+	s.key = []byte(key)
+	watcher := s.watcher
+	s.mutex.Unlock()
+	watcher([]byte(key))
+}
+
+func Test_Encrypt_Cookie_Secrets(t *testing.T) {
+	t.Parallel()
+	oldKey, newKey := GenerateKey(), GenerateKey()
+	provider := &testSecrets{key: []byte(oldKey)}
+
+	app := fiber.New()
+	app.Use(New(Config{
+		Secrets:   provider,
+		KeySecret: "cookie_key",
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString("value=" + c.Cookies("test"))
+	})
+	app.Post("/", func(c fiber.Ctx) error {
+		c.Cookie(&fiber.Cookie{Name: "test", Value: "SomeThing"})
+		return nil
+	})
+	h := app.Handler()
+
+	// the cookie is encrypted with the old key
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	h(ctx)
+	oldCookie := fasthttp.Cookie{}
+	oldCookie.SetKey("test")
+	require.True(t, ctx.Response.Header.Cookie(&oldCookie))
+	_, err := DecryptCookie(string(oldCookie.Value()), oldKey)
+	require.NoError(t, err)
+
+	provider.rotate(t, newKey)
+
+	// the new cookies are encrypted with the new key
+	ctx = &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodPost)
+	h(ctx)
+	newCookie := fasthttp.Cookie{}
+	newCookie.SetKey("test")
+	require.True(t, ctx.Response.Header.Cookie(&newCookie))
+	_, err = DecryptCookie(string(newCookie.Value()), newKey)
+	require.NoError(t, err)
+
+	// the cookies of both keys are decrypted
+	for _, value := range []string{string(oldCookie.Value()), string(newCookie.Value())} {
+		ctx = &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fiber.MethodGet)
+		ctx.Request.Header.SetCookie("test", value)
+		h(ctx)
+		require.Equal(t, "value=SomeThing", string(ctx.Response.Body()))
+	}
+
+	// the cookies of older keys are removed
+	provider.rotate(t, GenerateKey())
+	ctx = &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodGet)
+	ctx.Request.Header.SetCookie("test", string(oldCookie.Value()))
+	h(ctx)
+	require.Equal(t, "value=", string(ctx.Response.Body()))
+}
+
+// go test -run Test_Encrypt_Cookie_Keyring
+func Test_Encrypt_Cookie_Keyring(t *testing.T) {
+	t.Parallel()
+	keyring := fiber.NewKeyring(fiber.KeyringConfig{
+		Keys:           []fiber.Key{{ID: "v1", Value: make([]byte, 32)}},
+		MaxRetiredKeys: 1,
+	})
+
+	app := fiber.New()
+	app.Use(New(Config{Keyring: keyring}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString("value=" + c.Cookies("test"))
+	})
+	app.Post("/", func(c fiber.Ctx) error {
+		c.Cookie(&fiber.Cookie{Name: "test", Value: "SomeThing"})
+		return nil
+	})
+	h := app.Handler()
+
+	encrypt := func() string {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fiber.MethodPost)
+		h(ctx)
+		cookie := fasthttp.Cookie{}
+		cookie.SetKey("test")
+		require.True(t, ctx.Response.Header.Cookie(&cookie))
+		return string(cookie.Value())
+	}
+	decrypt := func(value string) string {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fiber.MethodGet)
+		ctx.Request.Header.SetCookie("test", value)
+		h(ctx)
+		return string(ctx.Response.Body())
+	}
+
+	// the cookie is prefixed with the ID of the active key
+	oldValue := encrypt()
+	require.True(t, strings.HasPrefix(oldValue, "v1:"))
+
+	keyring.Rotate(fiber.Key{ID: "v2", Value: []byte(strings.Repeat("k", 32))})
+	newValue := encrypt()
+	require.True(t, strings.HasPrefix(newValue, "v2:"))
+
+	// the cookies are encrypted with a key which is derived from the key
+	_, err := DecryptCookie(strings.TrimPrefix(newValue, "v2:"), base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	require.Error(t, err)
+
+	// the cookies of the active and the retired key are decrypted
+	require.Equal(t, "value=SomeThing", decrypt(oldValue))
+	require.Equal(t, "value=SomeThing", decrypt(newValue))
+
+	// the cookies of removed or unknown keys and without key are removed
+	keyring.Rotate(fiber.Key{ID: "v3", Value: []byte(strings.Repeat("x", 32))})
+	require.Equal(t, "value=", decrypt(oldValue))
+	require.Equal(t, "value=", decrypt("v9:"+strings.TrimPrefix(newValue, "v2:")))
+	require.Equal(t, "value=", decrypt(strings.TrimPrefix(newValue, "v2:")))
+}
@@ -0,0 +1,89 @@
+package esi
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/client"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Client fetches the fragments.
+	//
+	// Optional. Default: client.New()
+	Client *client.Client
+
+	// Storage caches the fragments.
+	//
+	// Optional. Default: an in-memory storage for this process only
+	Storage fiber.Storage
+
+	// BaseURL is the URL which the relative sources of the includes are resolved against,
+	// e.g. "http://fragments.internal:8080".
+	//
+	// Optional. Default: the scheme and the host of the request
+	BaseURL string
+
+	// ForwardHeaders are the headers of the request which are forwarded to the fragments.
+	//
+	// Optional. Default: []string{"Cookie", "Accept-Language"}
+	ForwardHeaders []string
+
+	// Timeout is the timeout to fetch a fragment.
+	//
+	// Optional. Default: 2 * time.Second
+	Timeout time.Duration
+
+	// TTL is the duration for which the fragments without a max-age in their Cache-Control
+	// header are cached. Fragments with the private, no-cache or no-store directives are never cached.
+	//
+	// Optional. Default: 0
+	TTL time.Duration
+
+	// MaxDepth is the maximum depth of the nested includes, the includes of deeper fragments are removed.
+	//
+	// Optional. Default: 3
+	MaxDepth int
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:           nil,
+	ForwardHeaders: []string{fiber.HeaderCookie, fiber.HeaderAcceptLanguage},
+	Timeout:        2 * time.Second,
+	MaxDepth:       3,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	cfg := ConfigDefault
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Set default values
+	if cfg.Client == nil {
+		cfg.Client = client.New()
+	}
+	if cfg.Storage == nil {
+		cfg.Storage = memory.New()
+	}
+	if cfg.ForwardHeaders == nil {
+		cfg.ForwardHeaders = ConfigDefault.ForwardHeaders
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = ConfigDefault.Timeout
+	}
+	if cfg.MaxDepth <= 0 {
+		cfg.MaxDepth = ConfigDefault.MaxDepth
+	}
+	return cfg
+}
@@ -0,0 +1,216 @@
+package esi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// HeaderSurrogateCapability is the header of the surrogates, e.g. Varnish or a CDN, which
+// announces that they process ESI (Edge Architecture Specification).
+const HeaderSurrogateCapability = "Surrogate-Capability"
+
+// surrogateContent is the Surrogate-Control directive of the responses which the surrogate assembles.
+const surrogateContent = `content="ESI/1.0"`
+
+// request is the data of the request which the fragments are fetched with, it is
+// copied from the context because the fragments are fetched concurrently.
+type request struct {
+	ctx     context.Context //nolint:containedctx // the context of the request is passed to the fragments
+	base    *url.URL
+	headers map[string]string
+}
+
+// New creates a new middleware handler, which assembles the HTML responses with
+// <esi:include> tags, unless an ESI capable surrogate in front of the app assembles them.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		res := c.Response()
+		if res.IsBodyStream() || len(res.Header.Peek(fiber.HeaderContentEncoding)) > 0 ||
+			!strings.HasPrefix(utils.ToLower(utils.UnsafeString(res.Header.ContentType())), fiber.MIMETextHTML) ||
+			!hasTags(res.Body()) {
+			return nil
+		}
+
+		// the surrogate assembles the response, which can be cached with the tags
+		if strings.Contains(c.Get(HeaderSurrogateCapability), "ESI/1.0") {
+			if control := c.GetRespHeader(fiber.HeaderSurrogateControl); control != "" {
+				c.Set(fiber.HeaderSurrogateControl, control+", "+surrogateContent)
+			} else {
+				c.Set(fiber.HeaderSurrogateControl, surrogateContent)
+			}
+			return nil
+		}
+
+		base := cfg.BaseURL
+		if base == "" {
+			base = c.Scheme() + "://" + c.Host()
+		}
+		baseURL, err := url.Parse(base)
+		if err != nil {
+			return fmt.Errorf("esi: invalid base url %q: %w", base, err)
+		}
+		req := request{ctx: c.UserContext(), base: baseURL, headers: make(map[string]string, len(cfg.ForwardHeaders))}
+		for _, header := range cfg.ForwardHeaders {
+			if value := c.Get(header); value != "" {
+				req.headers[header] = utils.CopyString(value)
+			}
+		}
+
+		body, err := assemble(&cfg, req, res.Body(), 1)
+		if err != nil {
+			return err
+		}
+		res.SetBody(body)
+		return nil
+	}
+}
+
+// assemble replaces the includes of the document with their fragments, which are fetched concurrently.
+func assemble(cfg *Config, req request, document []byte, depth int) ([]byte, error) {
+	segments := parse(document)
+	fragments := make([][]byte, len(segments))
+	errs := make([]error, len(segments))
+
+	var wg sync.WaitGroup
+	for i, seg := range segments {
+		// the includes of the fragments which are too deep are removed
+		if seg.include == nil || depth > cfg.MaxDepth {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, inc *include) {
+			defer wg.Done()
+			fragments[i], errs[i] = includeFragment(cfg, req, inc, depth)
+		}(i, seg.include)
+	}
+	wg.Wait()
+
+	var buf bytes.Buffer
+	buf.Grow(len(document))
+	for i, seg := range segments {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if seg.include == nil {
+			buf.Write(seg.content)
+		} else {
+			buf.Write(fragments[i])
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// includeFragment returns the fragment of the source of the include, or of its alternative source
+// if the source fails. If both fail, the include is removed if onerror is "continue", otherwise
+// the response fails with 502 Bad Gateway.
+func includeFragment(cfg *Config, req request, inc *include, depth int) ([]byte, error) {
+	fragment, err := fetch(cfg, req, inc.src, depth)
+	if err != nil && inc.alt != "" {
+		log.Errorw("esi: failed to include the fragment, the alternative is included", "src", inc.src, "error", err)
+		fragment, err = fetch(cfg, req, inc.alt, depth)
+	}
+	if err == nil {
+		return fragment, nil
+	}
+
+	log.Errorw("esi: failed to include the fragment", "src", inc.src, "error", err)
+	if inc.onErrorContinue {
+		return nil, nil
+	}
+	return nil, fiber.NewError(fiber.StatusBadGateway, "failed to include a fragment")
+}
+
+// fetch returns the fragment of the source from the storage, or fetches it with the client.
+// The includes of the fragment are assembled with the next depth.
+func fetch(cfg *Config, req request, src string, depth int) ([]byte, error) {
+	ref, err := url.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("esi: invalid source %q: %w", src, err)
+	}
+	target := req.base.ResolveReference(ref).String()
+	key := "esi_" + target
+
+	fragment, err := cfg.Storage.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("esi: failed to get the fragment from the storage: %w", err)
+	}
+	if fragment == nil {
+		r := cfg.Client.R().SetContext(req.ctx).SetTimeout(cfg.Timeout)
+		for header, value := range req.headers {
+			r.SetHeader(header, value)
+		}
+		resp, err := r.Get(target)
+		if err != nil {
+			return nil, fmt.Errorf("esi: failed to fetch %s: %w", target, err)
+		}
+		status, cacheControl := resp.StatusCode(), resp.Header(fiber.HeaderCacheControl)
+		fragment = append([]byte{}, resp.Body()...)
+		resp.Close()
+		if status >= fiber.StatusBadRequest {
+			return nil, fmt.Errorf("esi: failed to fetch %s: status %d", target, status)
+		}
+
+		if ttl := fragmentTTL(cacheControl, cfg.TTL); ttl > 0 {
+			if err := cfg.Storage.Set(key, fragment, ttl); err != nil {
+				return nil, fmt.Errorf("esi: failed to store the fragment: %w", err)
+			}
+		}
+	}
+
+	if hasTags(fragment) {
+		return assemble(cfg, req, fragment, depth+1)
+	}
+	return fragment, nil
+}
+
+// fragmentTTL returns the duration for which a fragment is cached, the s-maxage or max-age of its
+// Cache-Control header, or the TTL of the config. Private fragments aren't cached.
+func fragmentTTL(cacheControl string, ttl time.Duration) time.Duration {
+	maxAge, sMaxAge := -1, -1
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch utils.ToLower(name) {
+		case "private", "no-cache", "no-store":
+			return 0
+		case "max-age":
+			if seconds, err := strconv.Atoi(value); err == nil {
+				maxAge = seconds
+			}
+		case "s-maxage":
+			if seconds, err := strconv.Atoi(value); err == nil {
+				sMaxAge = seconds
+			}
+		}
+	}
+	switch {
+	case sMaxAge >= 0:
+		return time.Duration(sMaxAge) * time.Second
+	case maxAge >= 0:
+		return time.Duration(maxAge) * time.Second
+	default:
+		return ttl
+	}
+}
@@ -0,0 +1,154 @@
+package esi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// fragmentServer serves the fragments and counts the requests of each path.
+func fragmentServer(t *testing.T) (*httptest.Server, map[string]*atomic.Int32) {
+	t.Helper()
+	fragments := map[string]struct {
+		cacheControl string
+		body         string
+	}{
+		"/header":   {cacheControl: "public, max-age=60", body: "<header>Shop</header>"},
+		"/nested":   {cacheControl: "max-age=60", body: `<nav><esi:include src="/header"/></nav>`},
+		"/loop":     {body: `[<esi:include src="/loop"/>]`},
+		"/private":  {cacheControl: "private, max-age=60", body: "<p>private</p>"},
+		"/fallback": {body: "<p>fallback</p>"},
+	}
+	counts := make(map[string]*atomic.Int32, len(fragments)+2)
+	for path := range fragments {
+		counts[path] = &atomic.Int32{}
+	}
+	counts["/user"], counts["/fail"] = &atomic.Int32{}, &atomic.Int32{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count, ok := counts[r.URL.Path]; ok {
+			count.Add(1)
+		}
+		switch r.URL.Path {
+		case "/user":
+			_, _ = w.Write([]byte("<p>" + r.Header.Get(fiber.HeaderCookie) + "</p>")) //nolint:errcheck // It's a test
+			return
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fragment, ok := fragments[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if fragment.cacheControl != "" {
+			w.Header().Set(fiber.HeaderCacheControl, fragment.cacheControl)
+		}
+		_, _ = w.Write([]byte(fragment.body)) //nolint:errcheck // It's a test
+	}))
+	t.Cleanup(server.Close)
+	return server, counts
+}
+
+// testPage sends a request of the page and returns the response and its body.
+func testPage(t *testing.T, app *fiber.App, req *http.Request) (*http.Response, string) {
+	t.Helper()
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+// go test -run Test_ESI
+func Test_ESI(t *testing.T) {
+	t.Parallel()
+	server, counts := fragmentServer(t)
+	app := fiber.New()
+	app.Use(New(Config{BaseURL: server.URL, Timeout: time.Second}))
+	page := func(document string) fiber.Handler {
+		return func(c fiber.Ctx) error {
+			c.Type("html")
+			return c.SendString(document)
+		}
+	}
+	app.Get("/", page(`<html><esi:include src="/header"/><esi:remove><a href="/header">Header</a></esi:remove>`+
+		`<!--esi <esi:include src="/user"></esi:include> --><esi:comment text="footer"/><esi:vars>$(HTTP_HOST)</esi:vars></html>`))
+	app.Get("/nested", page(`<esi:include src="/nested"/>|<esi:include src="/loop"/>`))
+	app.Get("/private", page(`<esi:include src="/private"/>`))
+	app.Get("/fallback", page(`<esi:include src="/fail" alt="/fallback"/><esi:include src="/missing" onerror="continue"/>`))
+	app.Get("/fail", page(`<esi:include src="/fail"/>`))
+	app.Get("/json", func(c fiber.Ctx) error {
+		return c.JSON(fiber.Map{"html": `<esi:include src="/header"/>`})
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderCookie, "user=alice")
+	resp, body := testPage(t, app, req)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, `<html><header>Shop</header> <p>user=alice</p> <esi:vars>$(HTTP_HOST)</esi:vars></html>`, body)
+
+	// the cached fragments are fetched once
+	_, body = testPage(t, app, httptest.NewRequest(fiber.MethodGet, "/nested", nil))
+	require.Equal(t, `<nav><header>Shop</header></nav>|[[[]]]`, body)
+	require.Equal(t, int32(1), counts["/header"].Load())
+	require.Equal(t, int32(3), counts["/loop"].Load())
+
+	for i := 0; i < 2; i++ {
+		_, body = testPage(t, app, httptest.NewRequest(fiber.MethodGet, "/private", nil))
+		require.Equal(t, `<p>private</p>`, body)
+	}
+	require.Equal(t, int32(2), counts["/private"].Load())
+
+	_, body = testPage(t, app, httptest.NewRequest(fiber.MethodGet, "/fallback", nil))
+	require.Equal(t, `<p>fallback</p>`, body)
+
+	resp, _ = testPage(t, app, httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+	require.Equal(t, fiber.StatusBadGateway, resp.StatusCode)
+
+	_, body = testPage(t, app, httptest.NewRequest(fiber.MethodGet, "/json", nil))
+	require.JSONEq(t, `{"html":"<esi:include src=\"/header\"/>"}`, body)
+}
+
+// go test -run Test_ESI_Surrogate
+func Test_ESI_Surrogate(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", func(c fiber.Ctx) error {
+		c.Type("html")
+		c.Set(fiber.HeaderSurrogateControl, "max-age=60")
+		return c.SendString(`<esi:include src="/header"/>`)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(HeaderSurrogateCapability, `varnish="ESI/1.0"`)
+	resp, body := testPage(t, app, req)
+	require.Equal(t, `<esi:include src="/header"/>`, body)
+	require.Equal(t, `max-age=60, content="ESI/1.0"`, resp.Header.Get(fiber.HeaderSurrogateControl))
+}
+
+// go test -run Test_ESI_Next
+func Test_ESI_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		c.Type("html")
+		return c.SendString(`<esi:include src="/header"/>`)
+	})
+
+	_, body := testPage(t, app, httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.Equal(t, `<esi:include src="/header"/>`, body)
+}
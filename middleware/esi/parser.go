@@ -0,0 +1,129 @@
+package esi
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// segment is a part of a document, either literal content or an include.
+type segment struct {
+	include *include
+	content []byte
+}
+
+// include is an <esi:include> tag.
+type include struct {
+	src             string
+	alt             string
+	onErrorContinue bool
+}
+
+var (
+	esiComment    = []byte("<!--esi")
+	esiTag        = []byte("<esi:")
+	commentEnd    = []byte("-->")
+	removeEnd     = []byte("</esi:remove>")
+	includeEnd    = []byte("</esi:include>")
+	tagAttributes = regexp.MustCompile(`([a-zA-Z]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// hasTags reports whether the document contains ESI tags or comments.
+func hasTags(document []byte) bool {
+	return bytes.Contains(document, esiTag) || bytes.Contains(document, esiComment)
+}
+
+// parse splits the document into literal content and includes. The content of <!--esi ...-->
+// comments is kept, <esi:remove> and <esi:comment> are removed. Other tags are kept as they are.
+func parse(document []byte) []segment {
+	var segments []segment
+	literal := func(content []byte) {
+		if len(content) > 0 {
+			segments = append(segments, segment{content: content})
+		}
+	}
+
+	for len(document) > 0 {
+		start := indexTag(document)
+		if start < 0 {
+			literal(document)
+			break
+		}
+		literal(document[:start])
+		rest := document[start:]
+
+		switch {
+		case bytes.HasPrefix(rest, esiComment):
+			end := bytes.Index(rest, commentEnd)
+			if end < 0 {
+				literal(rest)
+				return segments
+			}
+			// the content of the comment is processed like the document
+			segments = append(segments, parse(rest[len(esiComment):end])...)
+			document = rest[end+len(commentEnd):]
+		case bytes.HasPrefix(rest, []byte("<esi:remove")):
+			end := bytes.Index(rest, removeEnd)
+			if end < 0 {
+				return segments
+			}
+			document = rest[end+len(removeEnd):]
+		case bytes.HasPrefix(rest, []byte("<esi:comment")), bytes.HasPrefix(rest, []byte("<esi:include")):
+			end := bytes.IndexByte(rest, '>')
+			if end < 0 {
+				literal(rest)
+				return segments
+			}
+			tag := rest[:end+1]
+			document = rest[end+1:]
+			if !bytes.HasPrefix(tag, []byte("<esi:include")) {
+				continue
+			}
+			// the closing tag of a non-empty include is removed
+			if !bytes.HasSuffix(tag, []byte("/>")) {
+				if closing := bytes.Index(document, includeEnd); closing >= 0 {
+					document = document[closing+len(includeEnd):]
+				}
+			}
+			if inc := parseInclude(tag); inc != nil {
+				segments = append(segments, segment{include: inc})
+			}
+		default:
+			// tags which aren't supported are kept
+			literal(rest[:len(esiTag)])
+			document = rest[len(esiTag):]
+		}
+	}
+	return segments
+}
+
+// indexTag returns the index of the next ESI tag or comment, or -1.
+func indexTag(document []byte) int {
+	tag, comment := bytes.Index(document, esiTag), bytes.Index(document, esiComment)
+	if tag < 0 || (comment >= 0 && comment < tag) {
+		return comment
+	}
+	return tag
+}
+
+// parseInclude parses the attributes of an include tag, it returns nil if it has no source.
+func parseInclude(tag []byte) *include {
+	inc := &include{}
+	for _, match := range tagAttributes.FindAllSubmatch(tag, -1) {
+		value := string(match[2])
+		if len(match[3]) > 0 {
+			value = string(match[3])
+		}
+		switch string(bytes.ToLower(match[1])) {
+		case "src":
+			inc.src = value
+		case "alt":
+			inc.alt = value
+		case "onerror":
+			inc.onErrorContinue = value == "continue"
+		}
+	}
+	if inc.src == "" {
+		return nil
+	}
+	return inc
+}
@@ -0,0 +1,89 @@
+package filebrowser
+
+import (
+	"io/fs"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Action is an action of the file browser, which is checked by the Authorize function of the config.
+type Action string
+
+// The actions of the file browser.
+const (
+	ActionList     Action = "list"
+	ActionDownload Action = "download"
+	ActionUpload   Action = "upload"
+	ActionDelete   Action = "delete"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Root is the file system which is browsed. It must implement WritableFS
+	// if Upload or Delete is enabled, e.g. a directory of DirFS.
+	//
+	// Required. Default: nil
+	Root fs.FS
+
+	// Authorize is called before every action with the slash-separated path of the file or directory,
+	// "." is the root. The action is denied if it returns an error, which is passed to the error handler.
+	// The uploads and deletes are sent as simple form posts with the cookies of the user, so Authorize
+	// must guard ActionUpload and ActionDelete against cross-site requests if they are enabled,
+	// e.g. by checking the Sec-Fetch-Site or Origin header.
+	//
+	// Optional. Default: nil, every enabled action is allowed
+	Authorize func(c fiber.Ctx, action Action, name string) error
+
+	// Title is the title of the pages and the name of the root in the breadcrumbs.
+	//
+	// Optional. Default: "Files"
+	Title string
+
+	// Upload enables the upload of files into the directories.
+	//
+	// Optional. Default: false
+	Upload bool
+
+	// Delete enables the deletion of files and directories.
+	//
+	// Optional. Default: false
+	Delete bool
+
+	// ShowHidden shows the files and directories whose names start with a dot,
+	// otherwise they aren't listed and can't be accessed.
+	//
+	// Optional. Default: false
+	ShowHidden bool
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:  nil,
+	Title: "Files",
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	cfg := ConfigDefault
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Set default values
+	if cfg.Root == nil {
+		panic("filebrowser: Root cannot be nil")
+	}
+	if _, ok := cfg.Root.(WritableFS); !ok && (cfg.Upload || cfg.Delete) {
+		panic("filebrowser: Root must implement WritableFS to upload or delete files")
+	}
+	if cfg.Title == "" {
+		cfg.Title = ConfigDefault.Title
+	}
+	return cfg
+}
@@ -0,0 +1,47 @@
+package filebrowser
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WritableFS is a file system which supports the upload and the deletion of files.
+type WritableFS interface {
+	fs.FS
+
+	// Create creates a new file, fs.ErrExist is returned if it exists already.
+	Create(name string) (io.WriteCloser, error)
+
+	// RemoveAll removes the file, or the directory with its content.
+	RemoveAll(name string) error
+}
+
+// dirFS is a directory of the operating system.
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+// DirFS returns the directory of the operating system as a WritableFS. Like os.DirFS, it
+// rejects the names which aren't valid paths of fs.ValidPath, but follows symbolic links.
+func DirFS(dir string) WritableFS {
+	return &dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+// Create creates a new file.
+func (d *dirFS) Create(name string) (io.WriteCloser, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrInvalid}
+	}
+	return os.OpenFile(filepath.Join(d.dir, filepath.FromSlash(name)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644) //nolint:wrapcheck // the error of the file system is returned unchanged
+}
+
+// RemoveAll removes the file or the directory, the root can't be removed.
+func (d *dirFS) RemoveAll(name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+	return os.RemoveAll(filepath.Join(d.dir, filepath.FromSlash(name))) //nolint:wrapcheck // the error of the file system is returned unchanged
+}
@@ -0,0 +1,363 @@
+package filebrowser
+
+import (
+	"archive/zip"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/fiber/v3/middleware/filesystem"
+)
+
+// New creates a new middleware handler, which is mounted on a prefix with app.Use.
+// It lists the directories, downloads the files and the directories as zip archives
+// and, if enabled, uploads and deletes files.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		// the path is only decoded if the app didn't unescape it already
+		prefix := strings.TrimRight(c.Route().Path, "/")
+		rel := strings.TrimPrefix(c.Path(), prefix)
+		if !c.App().Config().UnescapePath {
+			var err error
+			if rel, err = url.PathUnescape(rel); err != nil {
+				return fiber.ErrBadRequest
+			}
+		}
+		name := strings.TrimPrefix(path.Clean("/"+rel), "/")
+		if name == "" {
+			name = "."
+		}
+		if !cfg.ShowHidden && isHidden(name) {
+			return fiber.ErrNotFound
+		}
+
+		b := &browser{cfg: &cfg, c: c, prefix: prefix, name: name}
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead:
+			return b.get()
+		case fiber.MethodPost:
+			return b.post()
+		case fiber.MethodDelete:
+			if !cfg.Delete {
+				return fiber.ErrMethodNotAllowed
+			}
+			if err := b.remove(name); err != nil {
+				return err
+			}
+			return c.SendStatus(fiber.StatusNoContent)
+		default:
+			return c.Next()
+		}
+	}
+}
+
+// browser handles a request of the file browser.
+type browser struct {
+	cfg    *Config
+	c      fiber.Ctx
+	prefix string
+	name   string
+}
+
+// authorize checks the action with the Authorize function of the config.
+func (b *browser) authorize(action Action, name string) error {
+	if b.cfg.Authorize == nil {
+		return nil
+	}
+	return b.cfg.Authorize(b.c, action, name)
+}
+
+// get lists a directory, or downloads a file or a directory as zip archive.
+func (b *browser) get() error {
+	stat, err := fs.Stat(b.cfg.Root, b.name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fiber.ErrNotFound
+		}
+		return fmt.Errorf("filebrowser: failed to stat %s: %w", b.name, err)
+	}
+
+	if !stat.IsDir() {
+		if err := b.authorize(ActionDownload, b.name); err != nil {
+			return err
+		}
+		return filesystem.SendFile(b.c, b.cfg.Root, b.name) //nolint:wrapcheck // the error is returned as is
+	}
+	if b.c.Query("download") == "zip" {
+		if err := b.authorize(ActionDownload, b.name); err != nil {
+			return err
+		}
+		return b.zip()
+	}
+	if err := b.authorize(ActionList, b.name); err != nil {
+		return err
+	}
+	return b.list()
+}
+
+// post uploads the files of the multipart form into the directory, or deletes the entry
+// of the form's "delete" field. The browser is redirected to the directory afterward.
+func (b *browser) post() error {
+	if remove := b.c.FormValue("delete"); remove != "" {
+		if !b.cfg.Delete {
+			return fiber.ErrMethodNotAllowed
+		}
+		if err := b.remove(path.Join(b.name, path.Base(remove))); err != nil {
+			return err
+		}
+		return b.c.Redirect().Status(fiber.StatusSeeOther).To(b.url(b.name))
+	}
+
+	if !b.cfg.Upload {
+		return fiber.ErrMethodNotAllowed
+	}
+	if stat, err := fs.Stat(b.cfg.Root, b.name); err != nil || !stat.IsDir() {
+		return fiber.ErrNotFound
+	}
+	form, err := b.c.MultipartForm()
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	root := b.cfg.Root.(WritableFS) //nolint:forcetypeassert,errcheck // checked by the config
+	for _, header := range form.File["file"] {
+		name := path.Join(b.name, path.Base(header.Filename))
+		if !fs.ValidPath(name) || name == b.name || (!b.cfg.ShowHidden && isHidden(name)) {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid file name")
+		}
+		if err := b.authorize(ActionUpload, name); err != nil {
+			return err
+		}
+		if err := upload(root, name, header); err != nil {
+			if errors.Is(err, fs.ErrExist) {
+				return fiber.NewError(fiber.StatusConflict, "the file exists already")
+			}
+			return err
+		}
+	}
+	return b.c.Redirect().Status(fiber.StatusSeeOther).To(b.url(b.name))
+}
+
+// upload copies the uploaded file into a new file of the file system.
+func upload(root WritableFS, name string, header *multipart.FileHeader) error {
+	src, err := header.Open()
+	if err != nil {
+		return fmt.Errorf("filebrowser: failed to open the upload: %w", err)
+	}
+	defer src.Close() //nolint:errcheck // the upload is only read
+
+	dst, err := root.Create(name)
+	if err != nil {
+		return fmt.Errorf("filebrowser: failed to create %s: %w", name, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close() //nolint:errcheck // the copy failed already
+		return fmt.Errorf("filebrowser: failed to write %s: %w", name, err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("filebrowser: failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// remove deletes the file or the directory, the root can't be deleted.
+func (b *browser) remove(name string) error {
+	if name == "." || (!b.cfg.ShowHidden && isHidden(name)) {
+		return fiber.ErrForbidden
+	}
+	if _, err := fs.Stat(b.cfg.Root, name); err != nil {
+		return fiber.ErrNotFound
+	}
+	if err := b.authorize(ActionDelete, name); err != nil {
+		return err
+	}
+	if err := b.cfg.Root.(WritableFS).RemoveAll(name); err != nil { //nolint:forcetypeassert,errcheck // checked by the config
+		return fmt.Errorf("filebrowser: failed to delete %s: %w", name, err)
+	}
+	return nil
+}
+
+// zip streams the directory as zip archive, the hidden files are skipped.
+func (b *browser) zip() error {
+	archive := b.cfg.Title
+	if b.name != "." {
+		archive = path.Base(b.name)
+	}
+	b.c.Attachment(archive + ".zip")
+
+	root, dir, showHidden := b.cfg.Root, b.name, b.cfg.ShowHidden
+	return b.c.SendStreamWriter(func(w *bufio.Writer) {
+		zw := zip.NewWriter(w)
+		err := fs.WalkDir(root, dir, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if name == dir {
+				return nil
+			}
+			if !showHidden && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			return addFile(zw, root, dir, name, d)
+		})
+		if err == nil {
+			err = zw.Close()
+		}
+		if err != nil {
+			log.Errorw("filebrowser: failed to zip the directory", "dir", dir, "error", err)
+		}
+	})
+}
+
+// addFile adds the file or the directory to the zip archive, with its path relative to the zipped directory.
+func addFile(zw *zip.Writer, root fs.FS, dir, name string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err //nolint:wrapcheck // the error is logged
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err //nolint:wrapcheck // the error is logged
+	}
+	header.Name = name
+	if dir != "." {
+		header.Name = strings.TrimPrefix(name, dir+"/")
+	}
+	if d.IsDir() {
+		header.Name += "/"
+		_, err = zw.CreateHeader(header)
+		return err //nolint:wrapcheck // the error is logged
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	header.Method = zip.Deflate
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err //nolint:wrapcheck // the error is logged
+	}
+	f, err := root.Open(name)
+	if err != nil {
+		return err //nolint:wrapcheck // the error is logged
+	}
+	defer f.Close() //nolint:errcheck // the file is only read
+	_, err = io.Copy(w, f)
+	return err //nolint:wrapcheck // the error is logged
+}
+
+// list renders the listing of the directory.
+func (b *browser) list() error {
+	entries, err := fs.ReadDir(b.cfg.Root, b.name)
+	if err != nil {
+		return fmt.Errorf("filebrowser: failed to read %s: %w", b.name, err)
+	}
+
+	page := listing{
+		Title:   b.cfg.Title,
+		Sort:    b.c.Query("sort", "name"),
+		Desc:    b.c.Query("order") == "desc",
+		URL:     b.url(b.name),
+		Upload:  b.cfg.Upload,
+		Delete:  b.cfg.Delete,
+		Entries: make([]entry, 0, len(entries)),
+	}
+	page.Breadcrumbs = append(page.Breadcrumbs, breadcrumb{Name: b.cfg.Title, URL: b.url(".")})
+	if b.name != "." {
+		segments := strings.Split(b.name, "/")
+		for i, segment := range segments {
+			page.Breadcrumbs = append(page.Breadcrumbs, breadcrumb{Name: segment, URL: b.url(path.Join(segments[:i+1]...))})
+		}
+	}
+
+	for _, d := range entries {
+		if !b.cfg.ShowHidden && strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("filebrowser: failed to stat %s: %w", d.Name(), err)
+		}
+		page.Entries = append(page.Entries, entry{
+			Name:     d.Name(),
+			URL:      b.url(path.Join(b.name, d.Name())),
+			IsDir:    d.IsDir(),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+	sortEntries(page.Entries, page.Sort, page.Desc)
+
+	b.c.Type("html")
+	if err := listingTemplate.Execute(b.c, page); err != nil {
+		return fmt.Errorf("filebrowser: failed to render the listing: %w", err)
+	}
+	return nil
+}
+
+// url returns the escaped URL of the file or directory, the URL of the root ends with a slash.
+func (b *browser) url(name string) string {
+	if name == "." {
+		return b.prefix + "/"
+	}
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return b.prefix + "/" + strings.Join(segments, "/")
+}
+
+// sortEntries sorts the entries by the name, size or modification time, the directories come first.
+func sortEntries(entries []entry, by string, desc bool) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.IsDir != b.IsDir {
+			return a.IsDir
+		}
+		if desc {
+			a, b = b, a
+		}
+		switch by {
+		case "size":
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		case "modified":
+			if !a.Modified.Equal(b.Modified) {
+				return a.Modified.Before(b.Modified)
+			}
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
+
+// isHidden returns true if a segment of the path starts with a dot.
+func isHidden(name string) bool {
+	for _, segment := range strings.Split(name, "/") {
+		if strings.HasPrefix(segment, ".") && segment != "." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package filebrowser
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// send sends the request and returns the response and its body.
+func send(t *testing.T, app *fiber.App, req *http.Request) (*http.Response, string) {
+	t.Helper()
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+// go test -run Test_FileBrowser
+func Test_FileBrowser(t *testing.T) {
+	t.Parallel()
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	app := fiber.New()
+	app.Use("/files", New(Config{
+		Root: fstest.MapFS{
+			"docs/a.txt":         {Data: []byte("aaa"), ModTime: modified},
+			"docs/b.txt":         {Data: []byte("b"), ModTime: modified.Add(time.Hour)},
+			"docs/my report.txt": {Data: []byte("report"), ModTime: modified},
+			"docs/.secret":       {Data: []byte("secret")},
+			"docs/sub/c.txt":     {Data: []byte("c")},
+			"readme.md":          {Data: []byte("# readme")},
+		},
+	}))
+
+	resp, body := send(t, app, httptest.NewRequest(fiber.MethodGet, "/files/docs?sort=size&order=desc", nil))
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Contains(t, body, `<a href="/files/">Files</a> / <a href="/files/docs">docs</a>`)
+	require.NotContains(t, body, ".secret")
+	require.NotContains(t, body, "Upload")
+	// the directories come first, the files are sorted by size
+	sub, report, a, b := strings.Index(body, "sub/"), strings.Index(body, "/files/docs/my%20report.txt"),
+		strings.Index(body, "a.txt"), strings.Index(body, "b.txt")
+	require.True(t, sub < report && report < a && a < b)
+	require.Contains(t, body, `href="?sort=size&amp;order=asc"`)
+
+	_, body = send(t, app, httptest.NewRequest(fiber.MethodGet, "/files/docs?sort=modified", nil))
+	require.True(t, strings.Index(body, "a.txt") < strings.Index(body, "b.txt"))
+	require.Contains(t, body, "2024-05-01 12:00:00")
+
+	resp, body = send(t, app, httptest.NewRequest(fiber.MethodGet, "/files/docs/my%20report.txt", nil))
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "report", body)
+
+	resp, _ = send(t, app, httptest.NewRequest(fiber.MethodGet, "/files/docs/.secret", nil))
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	resp, _ = send(t, app, httptest.NewRequest(fiber.MethodGet, "/files/missing", nil))
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	resp, _ = send(t, app, httptest.NewRequest(fiber.MethodDelete, "/files/readme.md", nil))
+	require.Equal(t, fiber.StatusMethodNotAllowed, resp.StatusCode)
+
+	// the directories are downloaded as zip archives
+	resp, body = send(t, app, httptest.NewRequest(fiber.MethodGet, "/files/docs?download=zip", nil))
+	require.Equal(t, `attachment; filename="docs.zip"`, resp.Header.Get(fiber.HeaderContentDisposition))
+	archive, err := zip.NewReader(strings.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	names := make([]string, 0, len(archive.File))
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"a.txt", "b.txt", "my report.txt", "sub/", "sub/c.txt"}, names)
+	f, err := archive.Open("sub/c.txt")
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, "c", string(content))
+}
+
+// go test -run Test_FileBrowser_UnescapePath
+func Test_FileBrowser_UnescapePath(t *testing.T) {
+	t.Parallel()
+	root := fstest.MapFS{
+		"100%.txt":   {Data: []byte("percent")},
+		"100%25.txt": {Data: []byte("escaped")},
+	}
+
+	// the path is decoded once, whether the app unescaped it or not
+	for _, unescape := range []bool{false, true} {
+		app := fiber.New(fiber.Config{UnescapePath: unescape})
+		app.Use("/files", New(Config{Root: root}))
+
+		resp, body := send(t, app, httptest.NewRequest(fiber.MethodGet, "/files/100%25.txt", nil))
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		require.Equal(t, "percent", body)
+		resp, body = send(t, app, httptest.NewRequest(fiber.MethodGet, "/files/100%2525.txt", nil))
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		require.Equal(t, "escaped", body)
+	}
+}
+
+// go test -run Test_FileBrowser_Write
+func Test_FileBrowser_Write(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "uploads", "old"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "uploads", "old", "x.txt"), []byte("x"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "keep.txt"), []byte("keep"), 0o600))
+
+	var actions []string
+	app := fiber.New()
+	app.Use("/admin/files", New(Config{
+		Root:   DirFS(dir),
+		Upload: true,
+		Delete: true,
+		Authorize: func(_ fiber.Ctx, action Action, name string) error {
+			if name == "keep.txt" && action == ActionDelete {
+				return fiber.ErrForbidden
+			}
+			actions = append(actions, string(action)+" "+name)
+			return nil
+		},
+	}))
+
+	upload := func(name, content string) *http.Response {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		part, err := w.CreateFormFile("file", name)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		req := httptest.NewRequest(fiber.MethodPost, "/admin/files/uploads", &buf)
+		req.Header.Set(fiber.HeaderContentType, w.FormDataContentType())
+		resp, _ := send(t, app, req)
+		return resp
+	}
+
+	_, body := send(t, app, httptest.NewRequest(fiber.MethodGet, "/admin/files/uploads", nil))
+	require.Contains(t, body, `enctype="multipart/form-data"`)
+	require.Contains(t, body, `name="delete" value="old"`)
+
+	resp := upload("../report.csv", "a,b")
+	require.Equal(t, fiber.StatusSeeOther, resp.StatusCode)
+	require.Equal(t, "/admin/files/uploads", resp.Header.Get(fiber.HeaderLocation))
+	content, err := os.ReadFile(filepath.Join(dir, "uploads", "report.csv"))
+	require.NoError(t, err)
+	require.Equal(t, "a,b", string(content))
+
+	require.Equal(t, fiber.StatusConflict, upload("report.csv", "c,d").StatusCode)
+	require.Equal(t, fiber.StatusBadRequest, upload(".env", "SECRET=1").StatusCode)
+
+	// the form of the listing deletes an entry of the directory
+	req := httptest.NewRequest(fiber.MethodPost, "/admin/files/uploads", strings.NewReader(url.Values{"delete": {"old"}}.Encode()))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+	resp, _ = send(t, app, req)
+	require.Equal(t, fiber.StatusSeeOther, resp.StatusCode)
+	_, err = os.Stat(filepath.Join(dir, "uploads", "old"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	resp, _ = send(t, app, httptest.NewRequest(fiber.MethodDelete, "/admin/files/uploads/report.csv", nil))
+	require.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	resp, _ = send(t, app, httptest.NewRequest(fiber.MethodDelete, "/admin/files/keep.txt", nil))
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	resp, _ = send(t, app, httptest.NewRequest(fiber.MethodDelete, "/admin/files/", nil))
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	require.Equal(t, []string{
+		"list uploads", "upload uploads/report.csv", "upload uploads/report.csv",
+		"delete uploads/old", "delete uploads/report.csv",
+	}, actions)
+
+	require.Panics(t, func() {
+		New(Config{Root: fstest.MapFS{}, Upload: true})
+	})
+}
@@ -0,0 +1,102 @@
+package filebrowser
+
+import (
+	"html/template"
+	"strconv"
+	"time"
+)
+
+// listing is the data of the listing template.
+type listing struct {
+	Title       string
+	URL         string
+	Sort        string
+	Breadcrumbs []breadcrumb
+	Entries     []entry
+	Desc        bool
+	Upload      bool
+	Delete      bool
+}
+
+// breadcrumb is a link to a parent directory.
+type breadcrumb struct {
+	Name string
+	URL  string
+}
+
+// entry is a file or directory of the listing.
+type entry struct {
+	Modified time.Time
+	Name     string
+	URL      string
+	Size     int64
+	IsDir    bool
+}
+
+// SortURL returns the query of the column, the order is reversed if the listing is sorted by it.
+func (l listing) SortURL(column string) string {
+	order := "asc"
+	if l.Sort == column && !l.Desc {
+		order = "desc"
+	}
+	return "?sort=" + column + "&order=" + order
+}
+
+// HumanSize returns the size with a binary unit, e.g. "1.5 KiB".
+func (e entry) HumanSize() string {
+	if e.IsDir {
+		return "-"
+	}
+	const unit = 1024
+	if e.Size < unit {
+		return strconv.FormatInt(e.Size, 10) + " B"
+	}
+	size, exp := float64(e.Size)/unit, 0
+	for size >= unit && exp < 4 {
+		size /= unit
+		exp++
+	}
+	return strconv.FormatFloat(size, 'f', 1, 64) + " " + string("KMGTP"[exp]) + "iB"
+}
+
+// listingTemplate renders the listing of a directory.
+var listingTemplate = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222 }
+nav a, td a { text-decoration: none }
+table { border-collapse: collapse; width: 100%; margin: 1rem 0 }
+th, td { text-align: left; padding: .4rem .8rem; border-bottom: 1px solid #eee }
+td.size, th.size { text-align: right }
+.dir { font-weight: bold }
+form.inline { display: inline }
+</style>
+</head>
+<body>
+<nav>{{ range $i, $crumb := .Breadcrumbs }}{{ if $i }} / {{ end }}<a href="{{ $crumb.URL }}">{{ $crumb.Name }}</a>{{ end }}</nav>
+<table>
+<thead><tr>
+<th><a href="{{ .SortURL "name" }}">Name</a></th>
+<th class="size"><a href="{{ .SortURL "size" }}">Size</a></th>
+<th><a href="{{ .SortURL "modified" }}">Modified</a></th>
+{{ if .Delete }}<th></th>{{ end }}
+</tr></thead>
+<tbody>
+{{ range .Entries }}<tr>
+<td>{{ if .IsDir }}<a class="dir" href="{{ .URL }}/">{{ .Name }}/</a>{{ else }}<a href="{{ .URL }}">{{ .Name }}</a>{{ end }}</td>
+<td class="size">{{ .HumanSize }}</td>
+<td>{{ .Modified.UTC.Format "2006-01-02 15:04:05" }}</td>
+{{ if $.Delete }}<td><form class="inline" method="post" action="{{ $.URL }}"><input type="hidden" name="delete" value="{{ .Name }}"><button type="submit">Delete</button></form></td>{{ end }}
+</tr>
+{{ else }}<tr><td colspan="4">This directory is empty.</td></tr>
+{{ end }}</tbody>
+</table>
+<p><a href="?download=zip">Download as zip</a></p>
+{{ if .Upload }}<form method="post" action="{{ .URL }}" enctype="multipart/form-data"><input type="file" name="file" multiple required> <button type="submit">Upload</button></form>{{ end }}
+</body>
+</html>
+`))
@@ -0,0 +1,45 @@
+package form
+
+import (
+	"os"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// TempDir is the directory of the temporary files of TempFile.
+	//
+	// Optional. Default: os.TempDir()
+	TempDir string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:    nil,
+	TempDir: "",
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		cfg := ConfigDefault
+		cfg.TempDir = os.TempDir()
+		return cfg
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.TempDir == "" {
+		cfg.TempDir = os.TempDir()
+	}
+	return cfg
+}
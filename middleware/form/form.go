@@ -0,0 +1,275 @@
+package form
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+// The keys for the values in context
+const (
+	tempFilesKey contextKey = iota
+)
+
+// ErrMiddlewareMissing is returned by TempFile if the middleware wasn't executed,
+// so the temporary file wouldn't be removed.
+var ErrMiddlewareMissing = errors.New("form: the middleware is required for temporary files")
+
+// tempFiles are the temporary files of a request.
+type tempFiles struct {
+	dir   string
+	paths []string
+}
+
+// New creates a new middleware handler, which removes the temporary files
+// of TempFile after the handlers of the request.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		files := &tempFiles{dir: cfg.TempDir}
+		c.Locals(tempFilesKey, files)
+		defer func() {
+			for _, path := range files.paths {
+				if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+					log.Warnw("form: failed to remove temporary file", "path", path, "error", err)
+				}
+			}
+		}()
+
+		return c.Next()
+	}
+}
+
+// TempFile copies the upload to a temporary file, which is removed after the
+// handlers of the request. Use it to process an upload after the request body
+// was released, e.g. in a goroutine which is awaited by the handler.
+func TempFile(c fiber.Ctx, upload *Upload) (string, error) {
+	files, ok := c.Locals(tempFilesKey).(*tempFiles)
+	if !ok {
+		return "", ErrMiddlewareMissing
+	}
+
+	src, err := upload.Open()
+	if err != nil {
+		return "", fmt.Errorf("form: failed to open upload: %w", err)
+	}
+	defer src.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	dst, err := os.CreateTemp(files.dir, "fiber-form-*")
+	if err != nil {
+		return "", fmt.Errorf("form: failed to create temporary file: %w", err)
+	}
+	files.paths = append(files.paths, dst.Name())
+
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close() //nolint:errcheck // The copy error is returned
+		return "", fmt.Errorf("form: failed to write temporary file: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return "", fmt.Errorf("form: failed to close temporary file: %w", err)
+	}
+	return dst.Name(), nil
+}
+
+// Field is a form field with its validation rules.
+type Field struct {
+	Name  string
+	Rules []Rule
+}
+
+// Schema contains the fields of a form.
+type Schema struct {
+	fields []Field
+}
+
+// NewSchema creates a schema with the fields of a form.
+//
+//	signup := form.NewSchema(
+//	    form.Field{Name: "email", Rules: []form.Rule{form.Required(), form.MaxLength(254)}},
+//	    form.Field{Name: "avatar", Rules: []form.Rule{form.MaxSize(2 << 20), form.MIME("image/png", "image/jpeg")}},
+//	)
+func NewSchema(fields ...Field) *Schema {
+	return &Schema{fields: fields}
+}
+
+// Form contains the values and the uploads of a validated form.
+// The values are copies, they stay valid after the handler returned.
+type Form struct {
+	values map[string][]string
+	files  map[string][]*Upload
+}
+
+// Value returns the first value of the field, or an empty string.
+func (f *Form) Value(name string) string {
+	if values := f.values[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Values returns all values of the field.
+func (f *Form) Values(name string) []string {
+	return f.values[name]
+}
+
+// File returns the first upload of the field, or nil.
+func (f *Form) File(name string) *Upload {
+	if files := f.files[name]; len(files) > 0 {
+		return files[0]
+	}
+	return nil
+}
+
+// Files returns all uploads of the field.
+func (f *Form) Files(name string) []*Upload {
+	return f.files[name]
+}
+
+// Validate parses the url-encoded or multipart form of the request and validates
+// its fields. If a rule failed, the form and *Errors are returned, the errors
+// contain the submitted values to re-render the form.
+func (s *Schema) Validate(c fiber.Ctx) (*Form, error) {
+	f := &Form{
+		values: make(map[string][]string),
+		files:  make(map[string][]*Upload),
+	}
+
+	if strings.HasPrefix(utils.ToLower(c.Get(fiber.HeaderContentType)), fiber.MIMEMultipartForm) {
+		mf, err := c.MultipartForm()
+		if err != nil {
+			return nil, fmt.Errorf("form: failed to parse multipart form: %w", err)
+		}
+		for name, values := range mf.Value {
+			f.values[name] = append([]string(nil), values...)
+		}
+		for name, headers := range mf.File {
+			for _, header := range headers {
+				f.files[name] = append(f.files[name], newUpload(header))
+			}
+		}
+	} else {
+		c.Request().PostArgs().VisitAll(func(key, value []byte) {
+			name := string(key)
+			f.values[name] = append(f.values[name], string(value))
+		})
+	}
+
+	errs := &Errors{Values: make(map[string]string)}
+	for _, field := range s.fields {
+		value := &Value{Name: field.Name, Values: f.values[field.Name], Files: f.files[field.Name]}
+		if len(value.Files) == 0 {
+			errs.Values[field.Name] = f.Value(field.Name)
+		}
+		for _, rule := range field.Rules {
+			if err := rule(value); err != nil {
+				errs.add(field.Name, err)
+				break
+			}
+		}
+	}
+
+	if len(errs.Fields) > 0 {
+		return f, errs
+	}
+	return f, nil
+}
+
+// Value is the submitted value of a field, which is passed to the rules.
+type Value struct {
+	Name   string
+	Values []string
+	Files  []*Upload
+}
+
+// empty reports if the field has neither a value nor an upload.
+func (v *Value) empty() bool {
+	for _, value := range v.Values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return len(v.Files) == 0
+}
+
+// FieldError is a failed rule of a field.
+type FieldError struct {
+	// Code identifies the rule, e.g. "required", to translate the message.
+	Code string `json:"code"`
+	// Message describes the error in English.
+	Message string `json:"message"`
+}
+
+// Error returns the message.
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// Errors contains the errors of all invalid fields of a form.
+type Errors struct {
+	// Fields contains the errors by field name.
+	Fields map[string][]FieldError `json:"fields"`
+	// Values contains the first submitted value of each field of the schema,
+	// except the file fields, to re-render the form.
+	Values map[string]string `json:"values"`
+}
+
+// add adds the error of a rule to the field.
+func (e *Errors) add(name string, err error) {
+	if e.Fields == nil {
+		e.Fields = make(map[string][]FieldError)
+	}
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		fieldErr = &FieldError{Code: "invalid", Message: err.Error()}
+	}
+	e.Fields[name] = append(e.Fields[name], *fieldErr)
+}
+
+// Error returns the errors of the fields.
+func (e *Errors) Error() string {
+	var sb strings.Builder
+	sb.WriteString("form: invalid fields:")
+	for name, errs := range e.Fields {
+		for _, err := range errs {
+			sb.WriteString(" " + name + " " + err.Message + ";")
+		}
+	}
+	return strings.TrimSuffix(sb.String(), ";")
+}
+
+// Has reports if the field is invalid.
+func (e *Errors) Has(name string) bool {
+	return len(e.Fields[name]) > 0
+}
+
+// First returns the message of the first error of the field, or an empty string.
+func (e *Errors) First(name string) string {
+	if errs := e.Fields[name]; len(errs) > 0 {
+		return errs[0].Message
+	}
+	return ""
+}
+
+// newUpload creates the upload of the file header.
+func newUpload(header *multipart.FileHeader) *Upload {
+	return &Upload{FileHeader: header}
+}
@@ -0,0 +1,311 @@
+package form
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// testPNG returns a PNG image with the given dimensions.
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.White)
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// multipartBody returns a multipart body with the values and the files.
+func multipartBody(t *testing.T, values map[string]string, files map[string][]byte) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for name, value := range values {
+		require.NoError(t, w.WriteField(name, value))
+	}
+	for name, content := range files {
+		// the content type of the request is not trusted
+		fw, err := w.CreateFormFile(name, name+".png")
+		require.NoError(t, err)
+		_, err = fw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return body, w.FormDataContentType()
+}
+
+// go test -run Test_Form_Validate_URLEncoded
+func Test_Form_Validate_URLEncoded(t *testing.T) {
+	t.Parallel()
+	schema := NewSchema(
+		Field{Name: "name", Rules: []Rule{Required(), MinLength(2), MaxLength(5)}},
+		Field{Name: "email", Rules: []Rule{Required(), Pattern(regexp.MustCompile(`^[^@]+@[^@]+$`))}},
+		Field{Name: "plan", Rules: []Rule{OneOf("free", "pro")}},
+		Field{Name: "tags", Rules: []Rule{MaxLength(3)}},
+	)
+
+	app := fiber.New()
+	app.Post("/", func(c fiber.Ctx) error {
+		f, err := schema.Validate(c)
+		var errs *Errors
+		if errors.As(err, &errs) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(errs)
+		}
+		if err != nil {
+			return err
+		}
+		return c.SendString(f.Value("name") + " " + strings.Join(f.Values("tags"), ","))
+	})
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{
+			name:   "valid",
+			body:   "name=John&email=john@example.com&plan=pro&tags=a&tags=b",
+			status: fiber.StatusOK,
+			want:   "John a,b",
+		},
+		{
+			name:   "required",
+			body:   "name=+&email=john@example.com",
+			status: fiber.StatusUnprocessableEntity,
+			want:   `{"fields":{"name":[{"code":"required","message":"is required"}]},"values":{"email":"john@example.com","name":" ","plan":"","tags":""}}`,
+		},
+		{
+			name:   "invalid",
+			body:   "name=Johnny&email=john&plan=max&tags=a&tags=abcd",
+			status: fiber.StatusUnprocessableEntity,
+			want: `{"fields":{"email":[{"code":"pattern","message":"has an invalid format"}],` +
+				`"name":[{"code":"max_length","message":"must be at most 5 characters"}],` +
+				`"plan":[{"code":"one_of","message":"must be one of free, pro"}],` +
+				`"tags":[{"code":"max_length","message":"must be at most 3 characters"}]},` +
+				`"values":{"email":"john","name":"Johnny","plan":"max","tags":"a"}}`,
+		},
+		{
+			name:   "min length",
+			body:   "name=J&email=john@example.com",
+			status: fiber.StatusUnprocessableEntity,
+			want:   `{"fields":{"name":[{"code":"min_length","message":"must be at least 2 characters"}]},"values":{"email":"john@example.com","name":"J","plan":"","tags":""}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.status, resp.StatusCode)
+			require.Equal(t, tt.want, string(body))
+		})
+	}
+}
+
+// go test -run Test_Form_Validate_Files
+func Test_Form_Validate_Files(t *testing.T) {
+	t.Parallel()
+	schema := NewSchema(
+		Field{Name: "title", Rules: []Rule{Required()}},
+		Field{Name: "avatar", Rules: []Rule{Required(), MaxFiles(1), MaxSize(1 << 20), MIME("image/png", "image/jpeg"), MaxDimensions(20, 10)}},
+		Field{Name: "banner", Rules: []Rule{Image(func(cfg image.Config, format string) error {
+			if cfg.Width < 2*cfg.Height {
+				return errors.New("must be a wide " + format)
+			}
+			return nil
+		})}},
+	)
+
+	app := fiber.New()
+	app.Post("/", func(c fiber.Ctx) error {
+		f, err := schema.Validate(c)
+		var errs *Errors
+		if errors.As(err, &errs) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(errs.Fields)
+		}
+		if err != nil {
+			return err
+		}
+		avatar := f.File("avatar")
+		contentType, err := avatar.ContentType()
+		if err != nil {
+			return err
+		}
+		return c.SendString(fmt.Sprintf("%s %s %d", f.Value("title"), contentType, len(f.Files("banner"))))
+	})
+
+	tests := []struct {
+		name   string
+		files  map[string][]byte
+		status int
+		want   string
+	}{
+		{
+			name:   "valid",
+			files:  map[string][]byte{"avatar": testPNG(t, 20, 10), "banner": testPNG(t, 40, 10)},
+			status: fiber.StatusOK,
+			want:   "Hello image/png 1",
+		},
+		{
+			name:   "required",
+			files:  map[string][]byte{},
+			status: fiber.StatusUnprocessableEntity,
+			want:   `{"avatar":[{"code":"required","message":"is required"}]}`,
+		},
+		{
+			name:   "mime",
+			files:  map[string][]byte{"avatar": []byte("<html><body>not an image</body></html>")},
+			status: fiber.StatusUnprocessableEntity,
+			want:   `{"avatar":[{"code":"mime","message":"must be of type image/png, image/jpeg"}]}`,
+		},
+		{
+			name:   "dimensions",
+			files:  map[string][]byte{"avatar": testPNG(t, 21, 10), "banner": testPNG(t, 10, 10)},
+			status: fiber.StatusUnprocessableEntity,
+			want:   `{"avatar":[{"code":"max_dimensions","message":"must be at most 20x10 pixels"}],"banner":[{"code":"invalid","message":"must be a wide png"}]}`,
+		},
+		{
+			name:   "max size",
+			files:  map[string][]byte{"avatar": append(testPNG(t, 1, 1), make([]byte, 1<<20)...)},
+			status: fiber.StatusUnprocessableEntity,
+			want:   `{"avatar":[{"code":"max_size","message":"must be at most 1048576 bytes"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			body, contentType := multipartBody(t, map[string]string{"title": "Hello"}, tt.files)
+			req := httptest.NewRequest(fiber.MethodPost, "/", body)
+			req.Header.Set(fiber.HeaderContentType, contentType)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.status, resp.StatusCode)
+			require.Equal(t, tt.want, string(respBody))
+		})
+	}
+}
+
+// go test -run Test_Form_Errors
+func Test_Form_Errors(t *testing.T) {
+	t.Parallel()
+	errs := &Errors{}
+	errs.add("name", &FieldError{Code: "required", Message: "is required"})
+	errs.add("name", errors.New("is taken"))
+
+	require.True(t, errs.Has("name"))
+	require.False(t, errs.Has("email"))
+	require.Equal(t, "is required", errs.First("name"))
+	require.Equal(t, "", errs.First("email"))
+	require.Equal(t, []FieldError{{Code: "required", Message: "is required"}, {Code: "invalid", Message: "is taken"}}, errs.Fields["name"])
+	require.Equal(t, "form: invalid fields: name is required; name is taken", errs.Error())
+}
+
+// go test -run Test_Form_TempFile
+func Test_Form_TempFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	schema := NewSchema(Field{Name: "avatar", Rules: []Rule{Required()}})
+
+	var path string
+	app := fiber.New()
+	app.Use(New(Config{TempDir: dir}))
+	app.Post("/", func(c fiber.Ctx) error {
+		f, err := schema.Validate(c)
+		if err != nil {
+			return err
+		}
+		path, err = TempFile(c, f.File("avatar"))
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return c.Send(content)
+	})
+
+	body, contentType := multipartBody(t, nil, map[string][]byte{"avatar": []byte("avatar")})
+	req := httptest.NewRequest(fiber.MethodPost, "/", body)
+	req.Header.Set(fiber.HeaderContentType, contentType)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "avatar", string(respBody))
+
+	// the temporary file is removed after the handler
+	require.Equal(t, dir, filepath.Dir(path))
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// go test -run Test_Form_TempFile_MiddlewareMissing
+func Test_Form_TempFile_MiddlewareMissing(t *testing.T) {
+	t.Parallel()
+	schema := NewSchema(Field{Name: "avatar", Rules: []Rule{Required()}})
+
+	app := fiber.New()
+	app.Post("/", func(c fiber.Ctx) error {
+		f, err := schema.Validate(c)
+		if err != nil {
+			return err
+		}
+		_, err = TempFile(c, f.File("avatar"))
+		require.ErrorIs(t, err, ErrMiddlewareMissing)
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	body, contentType := multipartBody(t, nil, map[string][]byte{"avatar": []byte("avatar")})
+	req := httptest.NewRequest(fiber.MethodPost, "/", body)
+	req.Header.Set(fiber.HeaderContentType, contentType)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+}
+
+// go test -run Test_Form_Next
+func Test_Form_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		_, err := TempFile(c, &Upload{})
+		require.ErrorIs(t, err, ErrMiddlewareMissing)
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
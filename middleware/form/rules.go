@@ -0,0 +1,153 @@
+package form
+
+import (
+	"fmt"
+	"image"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rule validates the submitted value of a field. It returns a *FieldError,
+// other errors are reported with the code "invalid".
+type Rule func(v *Value) error
+
+// Required fails if the field has neither a value nor an upload.
+// The other rules succeed for empty fields, so optional fields can have rules.
+func Required() Rule {
+	return func(v *Value) error {
+		if v.empty() {
+			return &FieldError{Code: "required", Message: "is required"}
+		}
+		return nil
+	}
+}
+
+// MinLength fails if a value has fewer characters.
+func MinLength(n int) Rule {
+	return eachValue(func(value string) error {
+		if value != "" && utf8.RuneCountInString(value) < n {
+			return &FieldError{Code: "min_length", Message: fmt.Sprintf("must be at least %d characters", n)}
+		}
+		return nil
+	})
+}
+
+// MaxLength fails if a value has more characters.
+func MaxLength(n int) Rule {
+	return eachValue(func(value string) error {
+		if utf8.RuneCountInString(value) > n {
+			return &FieldError{Code: "max_length", Message: fmt.Sprintf("must be at most %d characters", n)}
+		}
+		return nil
+	})
+}
+
+// Pattern fails if a value doesn't match the regular expression.
+func Pattern(re *regexp.Regexp) Rule {
+	return eachValue(func(value string) error {
+		if value != "" && !re.MatchString(value) {
+			return &FieldError{Code: "pattern", Message: "has an invalid format"}
+		}
+		return nil
+	})
+}
+
+// OneOf fails if a value isn't one of the options.
+func OneOf(options ...string) Rule {
+	return eachValue(func(value string) error {
+		if value == "" {
+			return nil
+		}
+		for _, option := range options {
+			if value == option {
+				return nil
+			}
+		}
+		return &FieldError{Code: "one_of", Message: "must be one of " + strings.Join(options, ", ")}
+	})
+}
+
+// MaxFiles fails if the field has more uploads.
+func MaxFiles(n int) Rule {
+	return func(v *Value) error {
+		if len(v.Files) > n {
+			return &FieldError{Code: "max_files", Message: fmt.Sprintf("must have at most %d files", n)}
+		}
+		return nil
+	}
+}
+
+// MaxSize fails if an upload is larger than the given number of bytes.
+func MaxSize(size int64) Rule {
+	return eachFile(func(upload *Upload) error {
+		if upload.Size > size {
+			return &FieldError{Code: "max_size", Message: fmt.Sprintf("must be at most %d bytes", size)}
+		}
+		return nil
+	})
+}
+
+// MIME fails if the content type of an upload, which is detected from its content,
+// isn't one of the types, e.g. "image/png". The content type of the request isn't trusted.
+func MIME(types ...string) Rule {
+	return eachFile(func(upload *Upload) error {
+		contentType, err := upload.ContentType()
+		if err != nil {
+			return err
+		}
+		mediaType, _, _ := strings.Cut(contentType, ";")
+		for _, t := range types {
+			if mediaType == t {
+				return nil
+			}
+		}
+		return &FieldError{Code: "mime", Message: "must be of type " + strings.Join(types, ", ")}
+	})
+}
+
+// Image calls the check with the dimensions and the format of each upload,
+// e.g. to validate the aspect ratio. It fails if an upload isn't an image.
+func Image(check func(cfg image.Config, format string) error) Rule {
+	return eachFile(func(upload *Upload) error {
+		cfg, format, err := upload.Image()
+		if err != nil {
+			return &FieldError{Code: "image", Message: "must be an image"}
+		}
+		return check(cfg, format)
+	})
+}
+
+// MaxDimensions fails if an upload isn't an image or is wider or higher.
+func MaxDimensions(width, height int) Rule {
+	return Image(func(cfg image.Config, _ string) error {
+		if cfg.Width > width || cfg.Height > height {
+			return &FieldError{Code: "max_dimensions", Message: fmt.Sprintf("must be at most %dx%d pixels", width, height)}
+		}
+		return nil
+	})
+}
+
+// eachValue applies the check to each value of the field.
+func eachValue(check func(value string) error) Rule {
+	return func(v *Value) error {
+		for _, value := range v.Values {
+			if err := check(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// eachFile applies the check to each upload of the field.
+func eachFile(check func(upload *Upload) error) Rule {
+	return func(v *Value) error {
+		for _, upload := range v.Files {
+			if err := check(upload); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,69 @@
+package form
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	// Register the decoders of the image rules
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// sniffLen is the number of bytes used to detect the content type.
+const sniffLen = 512
+
+// Upload is an uploaded file of a form.
+type Upload struct {
+	*multipart.FileHeader
+
+	contentType string
+	image       *image.Config
+	imageFormat string
+}
+
+// ContentType returns the content type which is detected from the content of the file,
+// the content type of the request isn't trusted.
+func (u *Upload) ContentType() (string, error) {
+	if u.contentType != "" {
+		return u.contentType, nil
+	}
+
+	f, err := u.Open()
+	if err != nil {
+		return "", fmt.Errorf("form: failed to open upload: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF { //nolint:errorlint // io.ReadFull returns the errors unwrapped
+		return "", fmt.Errorf("form: failed to read upload: %w", err)
+	}
+	u.contentType = http.DetectContentType(buf[:n])
+	return u.contentType, nil
+}
+
+// Image returns the dimensions and the format, e.g. "png", of an image upload.
+// GIF, JPEG and PNG are supported, other formats can be registered with image.RegisterFormat.
+func (u *Upload) Image() (image.Config, string, error) {
+	if u.image != nil {
+		return *u.image, u.imageFormat, nil
+	}
+
+	f, err := u.Open()
+	if err != nil {
+		return image.Config{}, "", fmt.Errorf("form: failed to open upload: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	cfg, format, err := image.DecodeConfig(f)
+	if err != nil {
+		return image.Config{}, "", fmt.Errorf("form: failed to decode image: %w", err)
+	}
+	u.image, u.imageFormat = &cfg, format
+	return cfg, format, nil
+}
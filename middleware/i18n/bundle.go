@@ -0,0 +1,394 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Decoder decodes a message catalog into the map.
+type Decoder func(data []byte, v any) error
+
+// ErrFormatNotSupported is returned when there is no decoder registered for the file extension.
+var ErrFormatNotSupported = errors.New("i18n: no decoder registered for the file extension")
+
+var (
+	decodersMutex sync.RWMutex
+	decoders      = map[string]Decoder{
+		".json": json.Unmarshal,
+	}
+)
+
+// RegisterDecoder registers a decoder for message catalogs with the given extension.
+// JSON is supported by default. TOML or YAML can be added with the library of your choice:
+//
+//	i18n.RegisterDecoder(".toml", toml.Unmarshal)
+func RegisterDecoder(ext string, decoder Decoder) {
+	decodersMutex.Lock()
+	decoders[strings.ToLower(ext)] = decoder
+	decodersMutex.Unlock()
+}
+
+// Params are the values of the placeholders of a message.
+type Params map[string]any
+
+// message is a message of a catalog, plural messages have a text per plural category.
+type message struct {
+	plural map[string]string
+	text   string
+}
+
+// Bundle contains the message catalogs and the formats of the locales.
+type Bundle struct {
+	messages      map[string]map[string]message // locale -> key -> message
+	formats       map[string]Format
+	defaultLocale string
+	mutex         sync.RWMutex
+}
+
+// NewBundle creates an empty bundle. The default locale is used for requests
+// without a supported locale and for missing messages.
+func NewBundle(defaultLocale string) *Bundle {
+	return &Bundle{
+		defaultLocale: normalizeLocale(defaultLocale),
+		messages:      make(map[string]map[string]message),
+		formats:       make(map[string]Format),
+	}
+}
+
+// DefaultLocale returns the default locale of the bundle.
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale
+}
+
+// AddMessages adds the messages of the locale. Nested objects are flattened,
+// e.g. {"cart": {"title": "Cart"}} is added with the key "cart.title". Objects
+// whose keys are plural categories, e.g. {"one": "...", "other": "..."}, are plural messages.
+func (b *Bundle) AddMessages(locale string, messages map[string]any) error {
+	flat := make(map[string]message)
+	if err := flatten(flat, "", messages); err != nil {
+		return fmt.Errorf("i18n: invalid messages of %q: %w", locale, err)
+	}
+
+	locale = normalizeLocale(locale)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.messages[locale] == nil {
+		b.messages[locale] = make(map[string]message, len(flat))
+	}
+	for key, msg := range flat {
+		b.messages[locale][key] = msg
+	}
+	return nil
+}
+
+// LoadFS loads the message catalogs of the directory, e.g. of an embed.FS.
+// The name of each file is its locale, e.g. "de.json" or "en-US.toml".
+// Files without a registered decoder are skipped.
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("i18n: failed to read directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(entry.Name()))
+		decodersMutex.RLock()
+		decoder, ok := decoders[ext]
+		decodersMutex.RUnlock()
+		if !ok {
+			continue
+		}
+
+		if err := b.loadFile(fsys, path.Join(dir, entry.Name()), decoder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFile loads the message catalog from the file, its name is its locale.
+func (b *Bundle) LoadFile(fsys fs.FS, name string) error {
+	ext := strings.ToLower(path.Ext(name))
+	decodersMutex.RLock()
+	decoder, ok := decoders[ext]
+	decodersMutex.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrFormatNotSupported, ext)
+	}
+	return b.loadFile(fsys, name, decoder)
+}
+
+func (b *Bundle) loadFile(fsys fs.FS, name string, decoder Decoder) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("i18n: failed to read file: %w", err)
+	}
+
+	messages := make(map[string]any)
+	if err := decoder(data, &messages); err != nil {
+		return fmt.Errorf("i18n: failed to decode %q: %w", name, err)
+	}
+
+	base := path.Base(name)
+	return b.AddMessages(strings.TrimSuffix(base, path.Ext(base)), messages)
+}
+
+// Locales returns the sorted locales of the bundle.
+func (b *Bundle) Locales() []string {
+	b.mutex.RLock()
+	locales := make([]string, 0, len(b.messages))
+	for locale := range b.messages {
+		locales = append(locales, locale)
+	}
+	b.mutex.RUnlock()
+
+	sort.Strings(locales)
+	return locales
+}
+
+// Match returns the first supported locale of the language tags, e.g. "de" for "de-CH"
+// if only "de" is supported, or an empty string if no tag is supported.
+func (b *Bundle) Match(tags ...string) string {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		tag = normalizeLocale(tag)
+		if _, ok := b.messages[tag]; ok {
+			return tag
+		}
+		if base := baseLanguage(tag); base != tag {
+			if _, ok := b.messages[base]; ok {
+				return base
+			}
+		}
+	}
+	return ""
+}
+
+// Translate returns the message with the key in the locale, see T.
+// Missing messages are looked up in the base language and the default locale.
+func (b *Bundle) Translate(locale, key string, params ...Params) string {
+	var p Params
+	if len(params) > 0 {
+		p = params[0]
+	}
+
+	locale = normalizeLocale(locale)
+	b.mutex.RLock()
+	msg, ok := b.messages[locale][key]
+	if !ok {
+		msg, ok = b.messages[baseLanguage(locale)][key]
+	}
+	if !ok {
+		locale = b.defaultLocale
+		msg, ok = b.messages[locale][key]
+	}
+	b.mutex.RUnlock()
+	if !ok {
+		return key
+	}
+
+	text := msg.text
+	if msg.plural != nil {
+		text = msg.pluralText(locale, p)
+	}
+	return interpolate(text, p)
+}
+
+// pluralText returns the text of the plural category of the "count" param.
+func (m message) pluralText(locale string, params Params) string {
+	count, ok := toInt(params["count"])
+	if !ok {
+		return m.plural[PluralOther]
+	}
+	if text, ok := m.plural[PluralZero]; ok && count == 0 {
+		return text
+	}
+	if text, ok := m.plural[pluralRule(locale)(count)]; ok {
+		return text
+	}
+	return m.plural[PluralOther]
+}
+
+// flatten adds the messages with the prefixed keys.
+func flatten(flat map[string]message, prefix string, messages map[string]any) error {
+	for key, value := range messages {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case string:
+			flat[key] = message{text: v}
+		case map[string]any:
+			if isPlural(v) {
+				msg := message{plural: make(map[string]string, len(v))}
+				for category, text := range v {
+					s, ok := text.(string)
+					if !ok {
+						return fmt.Errorf("plural form %q of %q is not a string", category, key)
+					}
+					msg.plural[category] = s
+				}
+				flat[key] = msg
+				continue
+			}
+			if err := flatten(flat, key, v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q is not a string or an object", key)
+		}
+	}
+	return nil
+}
+
+// isPlural reports if all keys of the object are plural categories.
+func isPlural(v map[string]any) bool {
+	if len(v) == 0 {
+		return false
+	}
+	for key := range v {
+		switch key {
+		case PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// interpolate replaces the placeholders of the text, e.g. "{name}", with the params.
+// Placeholders without a param are kept.
+func interpolate(text string, params Params) string {
+	if len(params) == 0 || strings.IndexByte(text, '{') < 0 {
+		return text
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		sb.WriteString(text[:start])
+		if value, ok := params[text[start+1:end]]; ok {
+			sb.WriteString(fmt.Sprint(value))
+		} else {
+			sb.WriteString(text[start : end+1])
+		}
+		text = text[end+1:]
+	}
+	sb.WriteString(text)
+	return sb.String()
+}
+
+// toInt converts the count param to an int.
+func toInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int8:
+		return int(n), true
+	case int16:
+		return int(n), true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case uint:
+		return int(n), true //nolint:gosec // Counts are small
+	case uint8:
+		return int(n), true
+	case uint16:
+		return int(n), true
+	case uint32:
+		return int(n), true
+	case uint64:
+		return int(n), true //nolint:gosec // Counts are small
+	case float32:
+		return int(n), true
+	case float64:
+		return int(n), true
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// normalizeLocale returns the locale in the form "en-US".
+func normalizeLocale(locale string) string {
+	locale = strings.TrimSpace(strings.ReplaceAll(locale, "_", "-"))
+	base, region, ok := strings.Cut(locale, "-")
+	if !ok {
+		return strings.ToLower(base)
+	}
+	return strings.ToLower(base) + "-" + strings.ToUpper(region)
+}
+
+// baseLanguage returns the language of the locale, e.g. "en" for "en-US".
+func baseLanguage(locale string) string {
+	base, _, _ := strings.Cut(locale, "-")
+	return base
+}
+
+// parseAcceptLanguage returns the language tags of the Accept-Language header,
+// sorted by their quality.
+func parseAcceptLanguage(header string) []string {
+	if header == "" {
+		return nil
+	}
+
+	type tag struct {
+		name    string
+		quality float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.TrimSpace(name)
+		if name == "" || name == "*" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = v
+			}
+		}
+		if quality > 0 {
+			tags = append(tags, tag{name: name, quality: quality})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.name
+	}
+	return names
+}
@@ -0,0 +1,58 @@
+package i18n
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Bundle contains the message catalogs. It is required.
+	//
+	// Required. Default: nil
+	Bundle *Bundle
+
+	// QueryKey is the query parameter which selects the locale, e.g. "?lang=de".
+	// Set to "-" to ignore the query.
+	//
+	// Optional. Default: "lang"
+	QueryKey string
+
+	// CookieName is the cookie which selects the locale.
+	// Set to "-" to ignore the cookies.
+	//
+	// Optional. Default: "lang"
+	CookieName string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:       nil,
+	Bundle:     nil,
+	QueryKey:   "lang",
+	CookieName: "lang",
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.QueryKey == "" {
+		cfg.QueryKey = ConfigDefault.QueryKey
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = ConfigDefault.CookieName
+	}
+	return cfg
+}
@@ -0,0 +1,115 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format contains the formatting conventions of a locale.
+type Format struct {
+	// Decimal is the decimal separator, e.g. "." or ",".
+	Decimal string
+	// Group is the separator of the groups of thousands, e.g. "," or ".".
+	Group string
+	// Date is the time layout of dates, e.g. "01/02/2006".
+	Date string
+}
+
+// DefaultFormat is used for locales without a format.
+var DefaultFormat = Format{Decimal: ".", Group: ",", Date: "2006-01-02"}
+
+// formats are the built-in formats by locale or language.
+var formats = map[string]Format{
+	"en":    {Decimal: ".", Group: ",", Date: "01/02/2006"},
+	"en-GB": {Decimal: ".", Group: ",", Date: "02/01/2006"},
+	"de":    {Decimal: ",", Group: ".", Date: "02.01.2006"},
+	"es":    {Decimal: ",", Group: ".", Date: "02/01/2006"},
+	"fr":    {Decimal: ",", Group: " ", Date: "02/01/2006"},
+	"it":    {Decimal: ",", Group: ".", Date: "02/01/2006"},
+	"nl":    {Decimal: ",", Group: ".", Date: "02-01-2006"},
+	"pt":    {Decimal: ",", Group: ".", Date: "02/01/2006"},
+	"ru":    {Decimal: ",", Group: " ", Date: "02.01.2006"},
+	"pl":    {Decimal: ",", Group: " ", Date: "02.01.2006"},
+	"ja":    {Decimal: ".", Group: ",", Date: "2006/01/02"},
+	"zh":    {Decimal: ".", Group: ",", Date: "2006/01/02"},
+}
+
+// SetFormat sets the format of a locale or language, e.g. "de-CH".
+func (b *Bundle) SetFormat(locale string, format Format) {
+	b.mutex.Lock()
+	b.formats[normalizeLocale(locale)] = format
+	b.mutex.Unlock()
+}
+
+// Format returns the format of the locale. The formats of the bundle are checked
+// before the built-in formats, the locale before its language.
+func (b *Bundle) Format(locale string) Format {
+	locale = normalizeLocale(locale)
+	base := baseLanguage(locale)
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for _, lookup := range []map[string]Format{b.formats, formats} {
+		if format, ok := lookup[locale]; ok {
+			return format
+		}
+		if format, ok := lookup[base]; ok {
+			return format
+		}
+	}
+	return DefaultFormat
+}
+
+// Number formats the number with the separators of the format.
+func (f Format) Number(n float64, decimals int) string {
+	s := strconv.FormatFloat(n, 'f', decimals, 64)
+
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	integer, fraction, _ := strings.Cut(s, ".")
+
+	var sb strings.Builder
+	sb.WriteString(sign)
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(f.Group)
+		}
+		sb.WriteRune(digit)
+	}
+	if fraction != "" {
+		sb.WriteString(f.Decimal)
+		sb.WriteString(fraction)
+	}
+	return sb.String()
+}
+
+// FuncMap returns the template functions of the bundle, which take the locale
+// as first argument, e.g. for the html template engine:
+//
+//	engine.AddFuncMap(bundle.FuncMap())
+//
+//	{{ t .Locale "cart.items" "count" 3 }}
+//	{{ number .Locale .Total 2 }}
+//	{{ date .Locale .CreatedAt }}
+func (b *Bundle) FuncMap() map[string]any {
+	return map[string]any{
+		"t": func(locale, key string, pairs ...any) string {
+			params := make(Params, len(pairs)/2)
+			for i := 0; i+1 < len(pairs); i += 2 {
+				if name, ok := pairs[i].(string); ok {
+					params[name] = pairs[i+1]
+				}
+			}
+			return b.Translate(locale, key, params)
+		},
+		"number": func(locale string, n float64, decimals int) string {
+			return b.Format(locale).Number(n, decimals)
+		},
+		"date": func(locale string, t time.Time) string {
+			return t.Format(b.Format(locale).Date)
+		},
+	}
+}
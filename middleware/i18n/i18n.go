@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+// The keys for the values in context
+const (
+	localeKey contextKey = iota
+	bundleKey
+)
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	if cfg.Bundle == nil {
+		panic("i18n: a bundle is required")
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		locale := negotiate(c, &cfg)
+		c.Locals(localeKey, locale)
+		c.Locals(bundleKey, cfg.Bundle)
+		c.Set(fiber.HeaderContentLanguage, locale)
+		c.VaryBy(fiber.HeaderAcceptLanguage)
+
+		return c.Next()
+	}
+}
+
+// negotiate returns the locale of the request: the locale of the query,
+// the locale of the cookie, the best locale of the Accept-Language header
+// or the default locale of the bundle.
+func negotiate(c fiber.Ctx, cfg *Config) string {
+	if cfg.QueryKey != "-" {
+		if locale := cfg.Bundle.Match(c.Query(cfg.QueryKey)); locale != "" {
+			return locale
+		}
+	}
+	if cfg.CookieName != "-" {
+		if locale := cfg.Bundle.Match(c.Cookies(cfg.CookieName)); locale != "" {
+			return locale
+		}
+	}
+	if locale := cfg.Bundle.Match(parseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))...); locale != "" {
+		return locale
+	}
+	return cfg.Bundle.DefaultLocale()
+}
+
+// Locale returns the negotiated locale of the request.
+// If the middleware wasn't executed, an empty string is returned.
+func Locale(c fiber.Ctx) string {
+	if locale, ok := c.Locals(localeKey).(string); ok {
+		return locale
+	}
+	return ""
+}
+
+// bundle returns the bundle of the middleware, or nil if it wasn't executed.
+func bundle(c fiber.Ctx) *Bundle {
+	if b, ok := c.Locals(bundleKey).(*Bundle); ok {
+		return b
+	}
+	return nil
+}
+
+// T returns the message with the key in the locale of the request. The placeholders,
+// e.g. "{name}", are replaced with the params. If the params contain "count", the
+// plural form of the message is chosen by the plural rule of the locale.
+// If the message doesn't exist, the key is returned.
+//
+//	i18n.T(c, "cart.items", i18n.Params{"count": 3})
+func T(c fiber.Ctx, key string, params ...Params) string {
+	b := bundle(c)
+	if b == nil {
+		return key
+	}
+	return b.Translate(Locale(c), key, params...)
+}
+
+// Number formats the number with the decimal and group separators of the locale of the request.
+func Number(c fiber.Ctx, n float64, decimals int) string {
+	format := DefaultFormat
+	if b := bundle(c); b != nil {
+		format = b.Format(Locale(c))
+	}
+	return format.Number(n, decimals)
+}
+
+// Date formats the date with the date layout of the locale of the request.
+func Date(c fiber.Ctx, t time.Time) string {
+	format := DefaultFormat
+	if b := bundle(c); b != nil {
+		format = b.Format(Locale(c))
+	}
+	return t.Format(format.Date)
+}
@@ -0,0 +1,239 @@
+package i18n
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// testBundle returns a bundle with English, German and Russian messages.
+func testBundle(t *testing.T) *Bundle {
+	t.Helper()
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{
+			"hello": "Hello, {name}!",
+			"cart": {
+				"title": "Cart",
+				"items": {"zero": "Your cart is empty", "one": "{count} item", "other": "{count} items"}
+			},
+			"only_en": "Only in English"
+		}`)},
+		"locales/de.json": {Data: []byte(`{
+			"hello": "Hallo, {name}!",
+			"cart": {"title": "Warenkorb", "items": {"one": "{count} Artikel", "other": "{count} Artikel"}}
+		}`)},
+		"locales/ru.json": {Data: []byte(`{
+			"cart": {"items": {"one": "{count} товар", "few": "{count} товара", "many": "{count} товаров"}}
+		}`)},
+		"locales/README.md": {Data: []byte("skipped")},
+	}
+
+	b := NewBundle("en")
+	require.NoError(t, b.LoadFS(fsys, "locales"))
+	return b
+}
+
+// go test -run Test_I18n
+func Test_I18n(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Bundle: testBundle(t)}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(Locale(c) + ": " + T(c, "hello", Params{"name": "John"}))
+	})
+
+	tests := []struct {
+		name   string
+		target string
+		header string
+		cookie string
+		want   string
+	}{
+		{name: "default", target: "/", want: "en: Hello, John!"},
+		{name: "accept-language", target: "/", header: "fr;q=0.9, de-CH;q=0.8, en;q=0.5", want: "de: Hallo, John!"},
+		{name: "unsupported", target: "/", header: "fr, it", want: "en: Hello, John!"},
+		{name: "cookie", target: "/", header: "en", cookie: "de", want: "de: Hallo, John!"},
+		{name: "query", target: "/?lang=de", header: "en", cookie: "en", want: "de: Hallo, John!"},
+		{name: "unsupported query", target: "/?lang=xx", header: "de", want: "de: Hallo, John!"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, tt.target, nil)
+		if tt.header != "" {
+			req.Header.Set(fiber.HeaderAcceptLanguage, tt.header)
+		}
+		if tt.cookie != "" {
+			req.Header.Set(fiber.HeaderCookie, "lang="+tt.cookie)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, string(body), tt.name)
+		require.Equal(t, tt.want[:2], resp.Header.Get(fiber.HeaderContentLanguage), tt.name)
+	}
+}
+
+// go test -run Test_I18n_Next
+func Test_I18n_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Bundle: testBundle(t),
+		Next: func(fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString(Locale(c) + T(c, "hello") + Number(c, 1234.5, 1) + Date(c, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "hello1,234.52024-03-01", string(body))
+}
+
+// go test -run Test_I18n_RequiresBundle
+func Test_I18n_RequiresBundle(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New()
+	})
+}
+
+// go test -run Test_Bundle_Translate
+func Test_Bundle_Translate(t *testing.T) {
+	t.Parallel()
+	b := testBundle(t)
+
+	require.Equal(t, []string{"de", "en", "ru"}, b.Locales())
+	require.Equal(t, "Warenkorb", b.Translate("de", "cart.title"))
+	// the region falls back to the language
+	require.Equal(t, "Warenkorb", b.Translate("de-AT", "cart.title"))
+	// missing messages fall back to the default locale
+	require.Equal(t, "Only in English", b.Translate("de", "only_en"))
+	// missing keys are returned
+	require.Equal(t, "missing.key", b.Translate("de", "missing.key"))
+	// placeholders without params are kept
+	require.Equal(t, "Hello, {name}!", b.Translate("en", "hello"))
+
+	require.Equal(t, "Your cart is empty", b.Translate("en", "cart.items", Params{"count": 0}))
+	require.Equal(t, "1 item", b.Translate("en", "cart.items", Params{"count": 1}))
+	require.Equal(t, "5 items", b.Translate("en", "cart.items", Params{"count": int64(5)}))
+	require.Equal(t, "{count} items", b.Translate("en", "cart.items"))
+	require.Equal(t, "0 Artikel", b.Translate("de", "cart.items", Params{"count": 0}))
+
+	for count, want := range map[int]string{
+		1:  "1 товар",
+		3:  "3 товара",
+		5:  "5 товаров",
+		11: "11 товаров",
+		21: "21 товар",
+		22: "22 товара",
+	} {
+		require.Equal(t, want, b.Translate("ru", "cart.items", Params{"count": count}))
+	}
+}
+
+// go test -run Test_Bundle_AddMessages
+func Test_Bundle_AddMessages(t *testing.T) {
+	t.Parallel()
+	b := NewBundle("en_US")
+	require.Equal(t, "en-US", b.DefaultLocale())
+
+	require.NoError(t, b.AddMessages("en_us", map[string]any{"a": map[string]any{"b": "c"}}))
+	require.Equal(t, "c", b.Translate("en-US", "a.b"))
+	require.Equal(t, "en-US", b.Match("EN-us"))
+	require.Equal(t, "", b.Match("en"))
+
+	require.Error(t, b.AddMessages("en", map[string]any{"a": 1}))
+	require.Error(t, b.AddMessages("en", map[string]any{"a": map[string]any{"one": 1}}))
+
+	require.ErrorIs(t, b.LoadFile(fstest.MapFS{"de.toml": {}}, "de.toml"), ErrFormatNotSupported)
+}
+
+// go test -run Test_RegisterDecoder
+func Test_RegisterDecoder(t *testing.T) {
+	t.Parallel()
+	RegisterDecoder(".TEST", func(data []byte, v any) error {
+		messages, ok := v.(*map[string]any)
+		require.True(t, ok)
+		(*messages)["greeting"] = string(data)
+		return nil
+	})
+
+	b := NewBundle("en")
+	require.NoError(t, b.LoadFile(fstest.MapFS{"dir/fr.test": {Data: []byte("Bonjour")}}, "dir/fr.test"))
+	require.Equal(t, "Bonjour", b.Translate("fr", "greeting"))
+}
+
+// go test -run Test_RegisterPluralRule
+func Test_RegisterPluralRule(t *testing.T) {
+	t.Parallel()
+	RegisterPluralRule("x-test", func(n int) string {
+		if n == 2 {
+			return PluralTwo
+		}
+		return PluralOther
+	})
+
+	b := NewBundle("en")
+	require.NoError(t, b.AddMessages("x-test", map[string]any{
+		"n": map[string]any{"two": "two", "other": "other"},
+	}))
+	require.Equal(t, "two", b.Translate("x-TEST", "n", Params{"count": 2}))
+	require.Equal(t, "other", b.Translate("x-TEST", "n", Params{"count": "1"}))
+}
+
+// go test -run Test_Format
+func Test_Format(t *testing.T) {
+	t.Parallel()
+	b := NewBundle("en")
+	b.SetFormat("de-CH", Format{Decimal: ".", Group: "'", Date: "02.01.2006"})
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale string
+		number string
+		date   string
+	}{
+		{locale: "en", number: "-1,234,567.89", date: "03/01/2024"},
+		{locale: "en-GB", number: "-1,234,567.89", date: "01/03/2024"},
+		{locale: "de-AT", number: "-1.234.567,89", date: "01.03.2024"},
+		{locale: "de-CH", number: "-1'234'567.89", date: "01.03.2024"},
+		{locale: "xx", number: "-1,234,567.89", date: "2024-03-01"},
+	}
+	for _, tt := range tests {
+		format := b.Format(tt.locale)
+		require.Equal(t, tt.number, format.Number(-1234567.891, 2), tt.locale)
+		require.Equal(t, tt.date, date.Format(format.Date), tt.locale)
+	}
+
+	require.Equal(t, "123", DefaultFormat.Number(123, 0))
+	require.Equal(t, "1,000", DefaultFormat.Number(999.9, 0))
+}
+
+// go test -run Test_Bundle_FuncMap
+func Test_Bundle_FuncMap(t *testing.T) {
+	t.Parallel()
+	funcs := testBundle(t).FuncMap()
+
+	translate, ok := funcs["t"].(func(string, string, ...any) string)
+	require.True(t, ok)
+	require.Equal(t, "2 Artikel", translate("de", "cart.items", "count", 2))
+
+	number, ok := funcs["number"].(func(string, float64, int) string)
+	require.True(t, ok)
+	require.Equal(t, "1.000,50", number("de", 1000.5, 2))
+
+	date, ok := funcs["date"].(func(string, time.Time) string)
+	require.True(t, ok)
+	require.Equal(t, "01.03.2024", date("de", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+}
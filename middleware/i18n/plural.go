@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"sync"
+)
+
+// The plural categories of the Unicode CLDR.
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralRule returns the plural category of the count.
+type PluralRule func(n int) string
+
+var (
+	pluralRulesMutex sync.RWMutex
+	pluralRules      = map[string]PluralRule{
+		"fr": pluralOneUpToOne,
+		"ru": pluralEastSlavic,
+		"uk": pluralEastSlavic,
+		"be": pluralEastSlavic,
+		"pl": pluralPolish,
+		"cs": pluralCzech,
+		"sk": pluralCzech,
+		"ja": pluralNone,
+		"ko": pluralNone,
+		"zh": pluralNone,
+		"vi": pluralNone,
+		"th": pluralNone,
+		"id": pluralNone,
+	}
+)
+
+// RegisterPluralRule registers the plural rule of a language or locale,
+// e.g. "ar" or "pt-BR". Languages without a rule use the English rule:
+// "one" for 1 and "other" for all other counts.
+func RegisterPluralRule(locale string, rule PluralRule) {
+	pluralRulesMutex.Lock()
+	pluralRules[normalizeLocale(locale)] = rule
+	pluralRulesMutex.Unlock()
+}
+
+// pluralRule returns the plural rule of the locale.
+func pluralRule(locale string) PluralRule {
+	pluralRulesMutex.RLock()
+	defer pluralRulesMutex.RUnlock()
+
+	if rule, ok := pluralRules[locale]; ok {
+		return rule
+	}
+	if rule, ok := pluralRules[baseLanguage(locale)]; ok {
+		return rule
+	}
+	return pluralEnglish
+}
+
+func pluralEnglish(n int) string {
+	if n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralOneUpToOne(n int) string {
+	if n == 0 || n == 1 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func pluralNone(int) string {
+	return PluralOther
+}
+
+func pluralEastSlavic(n int) string {
+	switch mod10, mod100 := n%10, n%100; {
+	case mod10 == 1 && mod100 != 11:
+		return PluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+func pluralPolish(n int) string {
+	switch mod10, mod100 := n%10, n%100; {
+	case n == 1:
+		return PluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+func pluralCzech(n int) string {
+	switch {
+	case n == 1:
+		return PluralOne
+	case n >= 2 && n <= 4:
+		return PluralFew
+	default:
+		return PluralOther
+	}
+}
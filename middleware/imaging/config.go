@@ -0,0 +1,116 @@
+package imaging
+
+import (
+	"io/fs"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Root is the file system of the source images.
+	//
+	// Required. Default: nil
+	Root fs.FS
+
+	// Transformer decodes, transforms and encodes the images. A transformer which
+	// supports "webp" or "avif" enables the negotiation of these formats.
+	//
+	// Optional. Default: a transformer of the standard library for JPEG, PNG and GIF
+	Transformer Transformer
+
+	// Storage caches the transformed images.
+	//
+	// Optional. Default: an in-memory storage for this process only.
+	Storage fiber.Storage
+
+	// Key is the secret key of the signed URLs, see Sign. Requests without a valid
+	// signature are rejected, so clients can't request arbitrary transformations.
+	//
+	// Required unless AllowUnsigned is set. Default: nil
+	Key []byte
+
+	// AllowUnsigned allows requests without signature, e.g. behind an authenticated route.
+	//
+	// Optional. Default: false
+	AllowUnsigned bool
+
+	// Expiration is the expiration of the cached images in the Storage.
+	//
+	// Optional. Default: 24 * time.Hour
+	Expiration time.Duration
+
+	// MaxAge is the max-age of the Cache-Control header in seconds.
+	//
+	// Optional. Default: 86400
+	MaxAge int
+
+	// MaxWidth and MaxHeight limit the size of the transformed images.
+	//
+	// Optional. Default: 4096
+	MaxWidth  int
+	MaxHeight int
+
+	// Quality is the quality of lossy formats if the URL doesn't contain one, from 1 to 100.
+	//
+	// Optional. Default: 80
+	Quality int
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:       nil,
+	Expiration: 24 * time.Hour,
+	MaxAge:     86400,
+	MaxWidth:   4096,
+	MaxHeight:  4096,
+	Quality:    80,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	cfg := ConfigDefault
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Set default values
+	if cfg.Root == nil {
+		panic("imaging: Root cannot be nil")
+	}
+	if len(cfg.Key) == 0 && !cfg.AllowUnsigned {
+		panic("imaging: Key cannot be empty unless AllowUnsigned is set")
+	}
+	if cfg.Transformer == nil {
+		cfg.Transformer = stdTransformer{}
+	}
+	if cfg.Expiration <= 0 {
+		cfg.Expiration = ConfigDefault.Expiration
+	}
+	if cfg.Storage == nil {
+		cfg.Storage = memory.New(memory.Config{
+			GCInterval: cfg.Expiration / 2,
+		})
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = ConfigDefault.MaxAge
+	}
+	if cfg.MaxWidth <= 0 {
+		cfg.MaxWidth = ConfigDefault.MaxWidth
+	}
+	if cfg.MaxHeight <= 0 {
+		cfg.MaxHeight = ConfigDefault.MaxHeight
+	}
+	if cfg.Quality <= 0 || cfg.Quality > 100 {
+		cfg.Quality = ConfigDefault.Quality
+	}
+	return cfg
+}
@@ -0,0 +1,154 @@
+package imaging
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// negotiatedFormats are the formats which are negotiated with the Accept header, in the order of preference.
+var negotiatedFormats = []string{FormatAVIF, FormatWebP}
+
+// New creates a new middleware handler, which is mounted on a prefix with app.Use.
+// It serves the images of the Root transformed with the options of the query, see Sign.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+	formats := cfg.Transformer.Formats()
+	if len(formats) == 0 {
+		panic("imaging: the Transformer supports no formats")
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+
+		prefix := strings.TrimRight(c.Route().Path, "/")
+		rel, err := url.PathUnescape(strings.TrimPrefix(c.Path(), prefix))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		name := strings.TrimPrefix(path.Clean("/"+rel), "/")
+
+		query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		opts, err := parseOptions(query)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid image options")
+		}
+		if !cfg.AllowUnsigned && !hmac.Equal([]byte(query.Get(paramSignature)), []byte(signature(cfg.Key, name, opts.query()))) {
+			return fiber.ErrForbidden
+		}
+		if opts.Width > cfg.MaxWidth || opts.Height > cfg.MaxHeight {
+			return fiber.NewError(fiber.StatusBadRequest, "image size exceeds the limit")
+		}
+
+		stat, err := fs.Stat(cfg.Root, name)
+		if err != nil || stat.IsDir() {
+			return fiber.ErrNotFound
+		}
+
+		// the format is negotiated, or the format of the source is kept if the transformer supports it
+		if opts.Format == "" {
+			c.VaryBy(fiber.HeaderAccept)
+			opts.Format = negotiateFormat(c.Get(fiber.HeaderAccept), formats)
+			if opts.Format == "" {
+				opts.Format = sourceFormat(name)
+			}
+			if !slices.Contains(formats, opts.Format) {
+				opts.Format = formats[0]
+			}
+		} else if !slices.Contains(formats, opts.Format) {
+			return fiber.NewError(fiber.StatusBadRequest, "unsupported image format")
+		}
+		if opts.Quality == 0 {
+			opts.Quality = cfg.Quality
+		}
+		if opts.Fit == "" {
+			opts.Fit = FitContain
+		}
+
+		// the modification time and the size of the source invalidate the cached images
+		sum := sha256.Sum256([]byte(name + "\x00" + strconv.FormatInt(stat.ModTime().UnixNano(), 10) + "\x00" +
+			strconv.FormatInt(stat.Size(), 10) + "\x00" + opts.query().Encode()))
+		key := "imaging_" + hex.EncodeToString(sum[:])
+
+		data, err := cfg.Storage.Get(key)
+		if err != nil {
+			log.Errorw("imaging: failed to get the cached image", "error", err)
+		}
+		if data == nil {
+			if data, err = transform(&cfg, name, opts); err != nil {
+				return err
+			}
+			if err := cfg.Storage.Set(key, data, cfg.Expiration); err != nil {
+				log.Errorw("imaging: failed to cache the image", "error", err)
+			}
+		}
+
+		c.Set(fiber.HeaderContentType, "image/"+opts.Format)
+		c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(cfg.MaxAge))
+		return c.Send(data)
+	}
+}
+
+// transform transforms the image of the Root with the Transformer of the config.
+func transform(cfg *Config, name string, opts Options) ([]byte, error) {
+	src, err := cfg.Root.Open(name)
+	if err != nil {
+		return nil, fiber.ErrNotFound
+	}
+	defer src.Close() //nolint:errcheck // the image is only read
+
+	var dst bytes.Buffer
+	if err := cfg.Transformer.Transform(&dst, src, opts); err != nil {
+		switch {
+		case errors.Is(err, ErrUnsupportedImage):
+			return nil, fiber.ErrUnsupportedMediaType
+		case errors.Is(err, ErrImageTooLarge):
+			return nil, fiber.ErrUnprocessableEntity
+		}
+		return nil, fmt.Errorf("imaging: failed to transform %s: %w", name, err)
+	}
+	return dst.Bytes(), nil
+}
+
+// negotiateFormat returns the preferred format of the Accept header which the transformer supports.
+func negotiateFormat(accept string, formats []string) string {
+	for _, format := range negotiatedFormats {
+		if slices.Contains(formats, format) && strings.Contains(accept, "image/"+format) {
+			return format
+		}
+	}
+	return ""
+}
+
+// sourceFormat returns the format of the extension of the image.
+func sourceFormat(name string) string {
+	switch ext := strings.ToLower(path.Ext(name)); ext {
+	case ".jpg", ".jpeg":
+		return FormatJPEG
+	default:
+		return strings.TrimPrefix(ext, ".")
+	}
+}
@@ -0,0 +1,162 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte("secret")
+
+// testPNG returns a PNG image of the size, whose left half is red and right half is blue.
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// countingTransformer counts the transformations and encodes WebP images as PNG.
+type countingTransformer struct {
+	calls int
+}
+
+func (*countingTransformer) Formats() []string {
+	return []string{FormatJPEG, FormatPNG, FormatWebP}
+}
+
+func (t *countingTransformer) Transform(dst io.Writer, src io.Reader, opts Options) error {
+	t.calls++
+	if opts.Format == FormatWebP {
+		opts.Format = FormatPNG
+	}
+	return stdTransformer{}.Transform(dst, src, opts)
+}
+
+// get requests the image and returns the response and the decoded image.
+func get(t *testing.T, app *fiber.App, target, accept string) (*http.Response, image.Image) {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set(fiber.HeaderAccept, accept)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	if resp.StatusCode != fiber.StatusOK {
+		return resp, nil
+	}
+	img, _, err := image.Decode(resp.Body)
+	require.NoError(t, err)
+	return resp, img
+}
+
+// go test -run Test_Imaging
+func Test_Imaging(t *testing.T) {
+	t.Parallel()
+	transformer := &countingTransformer{}
+	app := fiber.New()
+	app.Use("/images", New(Config{
+		Root: fstest.MapFS{
+			"photos/wide.png": {Data: testPNG(t, 200, 100)},
+			"broken.jpg":      {Data: []byte("not an image")},
+		},
+		Key:         testKey,
+		Transformer: transformer,
+		MaxAge:      60,
+	}))
+
+	// the aspect ratio is kept
+	resp, img := get(t, app, "/images/photos/wide.png?"+Sign(testKey, "photos/wide.png", Options{Width: 50}), "")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "image/png", resp.Header.Get(fiber.HeaderContentType))
+	require.Equal(t, "public, max-age=60", resp.Header.Get(fiber.HeaderCacheControl))
+	require.Equal(t, image.Rect(0, 0, 50, 25), img.Bounds())
+
+	// the image is contained in the size, or covers it and is cropped in the center
+	_, img = get(t, app, "/images/photos/wide.png?"+Sign(testKey, "photos/wide.png", Options{Width: 40, Height: 40}), "")
+	require.Equal(t, image.Rect(0, 0, 40, 20), img.Bounds())
+	_, img = get(t, app, "/images/photos/wide.png?"+Sign(testKey, "photos/wide.png", Options{Width: 40, Height: 40, Fit: FitCover, Format: FormatJPEG}), "")
+	require.Equal(t, image.Rect(0, 0, 40, 40), img.Bounds())
+	r, _, b, _ := img.At(5, 20).RGBA()
+	require.Greater(t, r, b)
+	r, _, b, _ = img.At(35, 20).RGBA()
+	require.Greater(t, b, r)
+	_, img = get(t, app, "/images/photos/wide.png?"+Sign(testKey, "photos/wide.png", Options{Width: 40, Height: 40, Fit: FitFill}), "")
+	require.Equal(t, image.Rect(0, 0, 40, 40), img.Bounds())
+	require.Equal(t, 4, transformer.calls)
+
+	// WebP is negotiated and cached separately
+	opts := Sign(testKey, "photos/wide.png", Options{Width: 20})
+	resp, _ = get(t, app, "/images/photos/wide.png?"+opts, "image/avif,image/webp,*/*")
+	require.Equal(t, "image/webp", resp.Header.Get(fiber.HeaderContentType))
+	require.Equal(t, fiber.HeaderAccept, resp.Header.Get(fiber.HeaderVary))
+	resp, _ = get(t, app, "/images/photos/wide.png?"+opts, "image/avif,image/webp,*/*")
+	require.Equal(t, "image/webp", resp.Header.Get(fiber.HeaderContentType))
+	resp, _ = get(t, app, "/images/photos/wide.png?"+opts, "*/*")
+	require.Equal(t, "image/png", resp.Header.Get(fiber.HeaderContentType))
+	require.Equal(t, 6, transformer.calls)
+
+	// the signature is required and covers the options
+	resp, _ = get(t, app, "/images/photos/wide.png?w=20", "")
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	signed, err := url.ParseQuery(opts)
+	require.NoError(t, err)
+	resp, _ = get(t, app, "/images/photos/wide.png?w=2000&s="+signed.Get("s"), "")
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	resp, _ = get(t, app, "/images/photos/other.png?"+opts, "")
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	resp, _ = get(t, app, "/images/photos/wide.png?"+Sign(testKey, "photos/wide.png", Options{Width: 5000}), "")
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	resp, _ = get(t, app, "/images/photos/wide.png?"+Sign(testKey, "photos/wide.png", Options{Format: FormatAVIF}), "")
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	resp, _ = get(t, app, "/images/photos/wide.png?w=abc", "")
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	resp, _ = get(t, app, "/images/missing.png?"+Sign(testKey, "missing.png", Options{}), "")
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	resp, _ = get(t, app, "/images/broken.jpg?"+Sign(testKey, "broken.jpg", Options{}), "")
+	require.Equal(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+}
+
+// go test -run Test_Imaging_Unsigned
+func Test_Imaging_Unsigned(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use("/thumbs", New(Config{
+		Root:          fstest.MapFS{"a.jpg": {Data: testPNG(t, 64, 32)}},
+		AllowUnsigned: true,
+	}))
+
+	// the format of the source is kept, the standard transformer encodes no WebP
+	resp, img := get(t, app, "/thumbs/a.jpg?h=16&q=50", "image/webp")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "image/jpeg", resp.Header.Get(fiber.HeaderContentType))
+	require.Equal(t, image.Rect(0, 0, 32, 16), img.Bounds())
+
+	require.Panics(t, func() {
+		New(Config{Root: fstest.MapFS{}})
+	})
+	require.Panics(t, func() {
+		New(Config{Key: testKey})
+	})
+}
@@ -0,0 +1,143 @@
+package imaging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Fit is how an image is fitted into the width and the height.
+type Fit string
+
+// The fits of the images, if both the width and the height are set.
+const (
+	// FitContain scales the image into the width and the height, keeping its aspect ratio.
+	FitContain Fit = "contain"
+	// FitCover scales the image to cover the width and the height and crops it in the center.
+	FitCover Fit = "cover"
+	// FitFill stretches the image to the width and the height.
+	FitFill Fit = "fill"
+)
+
+// The formats of the images.
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+	FormatWebP = "webp"
+	FormatAVIF = "avif"
+)
+
+// The query parameters of the transformations.
+const (
+	paramWidth     = "w"
+	paramHeight    = "h"
+	paramFit       = "fit"
+	paramQuality   = "q"
+	paramFormat    = "fmt"
+	paramSignature = "s"
+)
+
+// Options are the transformations of an image. If only the width or the height
+// is set, the other one is scaled with the aspect ratio of the image.
+type Options struct {
+	// Fit is the fit of the image, FitContain by default.
+	Fit Fit
+	// Format is the format of the transformed image. If it's empty, WebP or AVIF
+	// is negotiated with the Accept header, otherwise the format of the source is kept.
+	Format string
+	// Width and Height are the size of the transformed image in pixels, 0 to keep it.
+	Width  int
+	Height int
+	// Quality is the quality of lossy formats from 1 to 100, the Quality of the config by default.
+	Quality int
+}
+
+var errInvalidOptions = errors.New("imaging: invalid options")
+
+// Sign returns the query of the options for the image with the name, which is the
+// slash-separated path of the image in the Root, with the signature of the key.
+//
+//	src := "/images/photos/cat.jpg?" + imaging.Sign(key, "photos/cat.jpg", imaging.Options{Width: 320})
+func Sign(key []byte, name string, opts Options) string {
+	query := opts.query()
+	query.Set(paramSignature, signature(key, name, query))
+	return query.Encode()
+}
+
+// query returns the query parameters of the options which are set.
+func (o Options) query() url.Values {
+	query := url.Values{}
+	if o.Width > 0 {
+		query.Set(paramWidth, strconv.Itoa(o.Width))
+	}
+	if o.Height > 0 {
+		query.Set(paramHeight, strconv.Itoa(o.Height))
+	}
+	if o.Fit != "" {
+		query.Set(paramFit, string(o.Fit))
+	}
+	if o.Quality > 0 {
+		query.Set(paramQuality, strconv.Itoa(o.Quality))
+	}
+	if o.Format != "" {
+		query.Set(paramFormat, o.Format)
+	}
+	return query
+}
+
+// signature returns the HMAC-SHA256 of the name and the encoded query without signature.
+func signature(key []byte, name string, query url.Values) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name + "?" + query.Encode())) //nolint:errcheck // hash writes never fail
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// parseOptions parses the options of the query parameters.
+func parseOptions(query url.Values) (Options, error) {
+	var opts Options
+	var err error
+	if opts.Width, err = parseInt(query, paramWidth, 0); err != nil {
+		return opts, err
+	}
+	if opts.Height, err = parseInt(query, paramHeight, 0); err != nil {
+		return opts, err
+	}
+	if opts.Quality, err = parseInt(query, paramQuality, 100); err != nil {
+		return opts, err
+	}
+
+	switch fit := Fit(query.Get(paramFit)); fit {
+	case "", FitContain, FitCover, FitFill:
+		opts.Fit = fit
+	default:
+		return opts, errInvalidOptions
+	}
+
+	switch format := strings.ToLower(query.Get(paramFormat)); format {
+	case "", FormatJPEG, FormatPNG, FormatGIF, FormatWebP, FormatAVIF:
+		opts.Format = format
+	case "jpg":
+		opts.Format = FormatJPEG
+	default:
+		return opts, errInvalidOptions
+	}
+	return opts, nil
+}
+
+// parseInt parses the positive integer of the query parameter, up to the limit if it's not 0.
+func parseInt(query url.Values, param string, limit int) (int, error) {
+	value := query.Get(param)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 || (limit > 0 && n > limit) {
+		return 0, errInvalidOptions
+	}
+	return n, nil
+}
@@ -0,0 +1,144 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+)
+
+var (
+	// ErrUnsupportedImage is returned by transformers for sources which they can't decode
+	// and for formats which they can't encode. It's sent as 415 Unsupported Media Type.
+	ErrUnsupportedImage = errors.New("imaging: unsupported image")
+	// ErrImageTooLarge is returned by transformers for sources which are too large to be
+	// decoded. It's sent as 422 Unprocessable Entity.
+	ErrImageTooLarge = errors.New("imaging: image too large")
+)
+
+// Transformer transforms images, e.g. with the standard library, libvips or an image service.
+type Transformer interface {
+	// Formats returns the formats which the transformer encodes, e.g. FormatJPEG and FormatWebP.
+	Formats() []string
+	// Transform decodes the source, transforms it with the options and writes it in the
+	// format of the options, which is always set, to the destination.
+	Transform(dst io.Writer, src io.Reader, opts Options) error
+}
+
+// maxSourcePixels limits the size of the sources of stdTransformer, which are decoded into memory.
+const maxSourcePixels = 50_000_000
+
+// stdTransformer transforms JPEG, PNG and GIF images with the standard library.
+type stdTransformer struct{}
+
+// Formats returns the formats of the standard library.
+func (stdTransformer) Formats() []string {
+	return []string{FormatJPEG, FormatPNG, FormatGIF}
+}
+
+// Transform decodes, scales and encodes the image. Only the first frame of animated GIFs is kept.
+func (stdTransformer) Transform(dst io.Writer, src io.Reader, opts Options) error {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return fmt.Errorf("imaging: failed to read the image: %w", err)
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnsupportedImage, err)
+	}
+	if config.Width*config.Height > maxSourcePixels {
+		return ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnsupportedImage, err)
+	}
+
+	width, height, crop := geometry(img.Bounds(), opts)
+	if crop != img.Bounds() || width != crop.Dx() || height != crop.Dy() {
+		img = scale(img, crop, width, height)
+	}
+
+	switch opts.Format {
+	case FormatJPEG:
+		err = jpeg.Encode(dst, img, &jpeg.Options{Quality: opts.Quality})
+	case FormatPNG:
+		err = png.Encode(dst, img)
+	case FormatGIF:
+		err = gif.Encode(dst, img, nil)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedImage, opts.Format)
+	}
+	if err != nil {
+		return fmt.Errorf("imaging: failed to encode the image: %w", err)
+	}
+	return nil
+}
+
+// geometry returns the size of the transformed image and the area of the source which is scaled into it.
+func geometry(bounds image.Rectangle, opts Options) (width, height int, crop image.Rectangle) {
+	sw, sh := bounds.Dx(), bounds.Dy()
+	width, height, crop = opts.Width, opts.Height, bounds
+	switch {
+	case width == 0 && height == 0:
+		return sw, sh, crop
+	case width == 0:
+		width = max(1, int(math.Round(float64(sw*height)/float64(sh))))
+	case height == 0:
+		height = max(1, int(math.Round(float64(sh*width)/float64(sw))))
+	case opts.Fit == FitCover:
+		// crop the center of the source with the aspect ratio of the size
+		cw, ch := sw, sh
+		if sw*height > sh*width {
+			cw = sh * width / height
+		} else {
+			ch = sw * height / width
+		}
+		x, y := bounds.Min.X+(sw-cw)/2, bounds.Min.Y+(sh-ch)/2
+		crop = image.Rect(x, y, x+cw, y+ch)
+	case opts.Fit != FitFill:
+		ratio := math.Min(float64(width)/float64(sw), float64(height)/float64(sh))
+		width = max(1, int(math.Round(float64(sw)*ratio)))
+		height = max(1, int(math.Round(float64(sh)*ratio)))
+	}
+	return width, height, crop
+}
+
+// scale scales the area of the image to the size, the pixels of the area which
+// are covered by a pixel of the scaled image are averaged.
+func scale(img image.Image, area image.Rectangle, width, height int) *image.RGBA {
+	src, ok := img.(*image.RGBA)
+	if !ok {
+		src = image.NewRGBA(img.Bounds())
+		draw.Draw(src, src.Bounds(), img, img.Bounds().Min, draw.Src)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	sw, sh := area.Dx(), area.Dy()
+	for y := 0; y < height; y++ {
+		y0 := area.Min.Y + y*sh/height
+		y1 := max(area.Min.Y+(y+1)*sh/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := area.Min.X + x*sw/width
+			x1 := max(area.Min.X+(x+1)*sw/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := src.RGBAAt(sx, sy)
+					r, g, b, a = r+uint64(c.R), g+uint64(c.G), b+uint64(c.B), a+uint64(c.A)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,64 @@
+package jsonschema
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Schema is the compiled JSON Schema of the request body.
+	//
+	// Required.
+	Schema *Schema
+
+	// ErrorHandler is executed when the request body can't be parsed or doesn't
+	// match the schema, the error of an invalid body is Errors.
+	//
+	// Optional. Default: DefaultErrorHandler
+	ErrorHandler fiber.ErrorHandler
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:         nil,
+	Schema:       nil,
+	ErrorHandler: DefaultErrorHandler,
+}
+
+// DefaultErrorHandler responds with the status 422 and the errors of an invalid
+// body as JSON, e.g. {"errors":[{"pointer":"/name","keyword":"required","message":"is required"}]}.
+// Other errors are returned to the error handler of the app.
+func DefaultErrorHandler(c fiber.Ctx, err error) error {
+	var errs Errors
+	if errors.As(err, &errs) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"errors": errs})
+	}
+	return err
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		panic("jsonschema: the Schema is required")
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Schema == nil {
+		panic("jsonschema: the Schema is required")
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = ConfigDefault.ErrorHandler
+	}
+	return cfg
+}
@@ -0,0 +1,67 @@
+package jsonschema
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FormatChecker reports if a string has a format.
+type FormatChecker func(value string) bool
+
+var (
+	formatsMutex sync.RWMutex
+	formats      = map[string]FormatChecker{
+		"date-time": func(value string) bool {
+			_, err := time.Parse(time.RFC3339, value)
+			return err == nil
+		},
+		"date": func(value string) bool {
+			_, err := time.Parse(time.DateOnly, value)
+			return err == nil
+		},
+		"time": func(value string) bool {
+			_, err := time.Parse("15:04:05Z07:00", value)
+			return err == nil
+		},
+		"email": func(value string) bool {
+			address, err := mail.ParseAddress(value)
+			return err == nil && address.Address == value
+		},
+		"uri": func(value string) bool {
+			u, err := url.Parse(value)
+			return err == nil && u.Scheme != ""
+		},
+		"uuid": uuidPattern.MatchString,
+		"ipv4": func(value string) bool {
+			ip := net.ParseIP(value)
+			return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+		},
+		"ipv6": func(value string) bool {
+			return net.ParseIP(value) != nil && strings.Contains(value, ":")
+		},
+	}
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// RegisterFormat registers the checker of a format, it replaces a built-in checker
+// of the same name. Formats without checker aren't validated.
+//
+//	jsonschema.RegisterFormat("slug", regexp.MustCompile(`^[a-z0-9-]+$`).MatchString)
+func RegisterFormat(name string, checker FormatChecker) {
+	formatsMutex.Lock()
+	formats[name] = checker
+	formatsMutex.Unlock()
+}
+
+// lookupFormat returns the checker of the format or nil.
+func lookupFormat(name string) FormatChecker {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+	return formats[name]
+}
@@ -0,0 +1,122 @@
+package jsonschema
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/binder"
+	"github.com/gofiber/utils/v2"
+)
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+// The keys for the values in context
+const (
+	bodyKey contextKey = iota
+)
+
+// New creates a new middleware handler, which validates the request body against
+// the schema and coerces its values to the types of the schema. It is usually
+// registered per route:
+//
+//	app.Post("/users", handler, jsonschema.New(jsonschema.Config{Schema: userSchema}))
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		ctype := utils.ToLower(utils.UnsafeString(c.Request().Header.ContentType()))
+		ctype = binder.FilterFlags(utils.ParseVendorSpecificContentType(ctype))
+
+		var (
+			value any
+			err   error
+		)
+		switch ctype {
+		case fiber.MIMEApplicationJSON:
+			value, err = parseJSON(c)
+		case fiber.MIMEApplicationForm, fiber.MIMEMultipartForm:
+			value, err = parseForm(c, ctype)
+		default:
+			err = fiber.ErrUnsupportedMediaType
+		}
+		if err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+
+		value, err = cfg.Schema.Coerce(value)
+		if err != nil {
+			return cfg.ErrorHandler(c, err)
+		}
+
+		// The coerced JSON body is bound by c.Bind().Body
+		if ctype == fiber.MIMEApplicationJSON {
+			body, err := c.App().Config().JSONEncoder(value)
+			if err != nil {
+				return fmt.Errorf("jsonschema: failed to encode body: %w", err)
+			}
+			c.Request().SetBodyRaw(body)
+		}
+		c.Locals(bodyKey, value)
+
+		return c.Next()
+	}
+}
+
+// Body returns the validated and coerced request body: a map[string]any for objects,
+// []any for arrays, string, float64 for numbers, int64 for integers, bool or nil.
+// It returns nil if the middleware wasn't executed.
+func Body(c fiber.Ctx) any {
+	return c.Locals(bodyKey)
+}
+
+// parseJSON decodes the JSON body, an empty body is null.
+func parseJSON(c fiber.Ctx) (any, error) {
+	body := c.Body()
+	if len(body) == 0 {
+		return nil, nil
+	}
+	var value any
+	if err := c.App().Config().JSONDecoder(body, &value); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "jsonschema: invalid JSON body: "+err.Error())
+	}
+	return value, nil
+}
+
+// parseForm returns the form values as object, a field with several values is an array.
+func parseForm(c fiber.Ctx, ctype string) (any, error) {
+	values := make(map[string][]string)
+	if ctype == fiber.MIMEMultipartForm {
+		form, err := c.Request().MultipartForm()
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "jsonschema: invalid multipart body: "+err.Error())
+		}
+		values = form.Value
+	} else {
+		c.Request().PostArgs().VisitAll(func(key, value []byte) {
+			values[string(key)] = append(values[string(key)], string(value))
+		})
+	}
+
+	object := make(map[string]any, len(values))
+	for key, fieldValues := range values {
+		if len(fieldValues) == 1 {
+			object[key] = fieldValues[0]
+			continue
+		}
+		items := make([]any, len(fieldValues))
+		for i, fieldValue := range fieldValues {
+			items[i] = fieldValue
+		}
+		object[key] = items
+	}
+	return object, nil
+}
@@ -0,0 +1,332 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["name", "email"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 2},
+		"email": {"type": "string", "format": "email"},
+		"age": {"type": "integer", "minimum": 18},
+		"admin": {"type": "boolean", "default": false},
+		"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+		"address": {"$ref": "#/$defs/address"}
+	},
+	"$defs": {
+		"address": {
+			"type": "object",
+			"required": ["city"],
+			"properties": {"city": {"type": "string"}, "zip": {"type": "string", "pattern": "^[0-9]{5}$"}}
+		}
+	}
+}`
+
+// go test -run Test_JSONSchema
+func Test_JSONSchema(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Post("/users", func(c fiber.Ctx) error {
+		var user struct {
+			Name  string `json:"name"`
+			Age   int    `json:"age"`
+			Admin bool   `json:"admin"`
+		}
+		if err := c.Bind().Body(&user); err != nil {
+			return err
+		}
+		body, ok := Body(c).(map[string]any)
+		require.True(t, ok)
+		require.Equal(t, user.Admin, body["admin"])
+		return c.JSON(user)
+	}, New(Config{Schema: MustCompile([]byte(userSchema))}))
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{
+			name:   "valid",
+			body:   `{"name":"John","email":"john@example.com","age":"42","tags":["a","b"]}`,
+			status: fiber.StatusOK,
+			want:   `{"name":"John","age":42,"admin":false}`,
+		},
+		{
+			name:   "invalid",
+			body:   `{"name":"J","email":"john","age":17.5,"tags":["a","a"],"address":{"zip":"1"},"role":"admin"}`,
+			status: fiber.StatusUnprocessableEntity,
+			want: `{"errors":[` +
+				`{"pointer":"/address/city","keyword":"required","message":"is required"},` +
+				`{"pointer":"/address/zip","keyword":"pattern","message":"must match the pattern ^[0-9]{5}$"},` +
+				`{"pointer":"/age","keyword":"type","message":"must be integer"},` +
+				`{"pointer":"/email","keyword":"format","message":"must be a valid email"},` +
+				`{"pointer":"/name","keyword":"minLength","message":"must be at least 2 characters long"},` +
+				`{"pointer":"/tags/1","keyword":"uniqueItems","message":"must be unique"},` +
+				`{"pointer":"/role","keyword":"additionalProperties","message":"is not allowed"}]}`,
+		},
+		{
+			name:   "empty",
+			body:   ``,
+			status: fiber.StatusUnprocessableEntity,
+			want:   `{"errors":[{"pointer":"","keyword":"type","message":"must be object"}]}`,
+		},
+		{
+			name:   "syntax",
+			body:   `{"name":`,
+			status: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(fiber.MethodPost, "/users", strings.NewReader(tt.body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, tt.status, resp.StatusCode, tt.name)
+		if tt.want != "" {
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(body), tt.name)
+		}
+	}
+
+	req := httptest.NewRequest(fiber.MethodPost, "/users", strings.NewReader(`<user/>`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationXML)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+}
+
+// go test -run Test_JSONSchema_Form
+func Test_JSONSchema_Form(t *testing.T) {
+	t.Parallel()
+	schema := MustCompile([]byte(`{
+		"type": "object",
+		"properties": {
+			"page": {"type": "integer", "minimum": 1},
+			"ids": {"type": "array", "items": {"type": "integer"}},
+			"draft": {"type": "boolean"}
+		}
+	}`))
+
+	app := fiber.New()
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.JSON(Body(c))
+	}, New(Config{Schema: schema}))
+
+	req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader("page=2&ids=1&draft=true"))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"page":2,"ids":[1],"draft":true}`, string(body))
+
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	require.NoError(t, writer.WriteField("page", "0"))
+	require.NoError(t, writer.WriteField("ids", "1"))
+	require.NoError(t, writer.WriteField("ids", "x"))
+	require.NoError(t, writer.Close())
+	req = httptest.NewRequest(fiber.MethodPost, "/", buf)
+	req.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"errors":[`+
+		`{"pointer":"/ids/1","keyword":"type","message":"must be integer"},`+
+		`{"pointer":"/page","keyword":"minimum","message":"must be >= 1"}]}`, string(body))
+}
+
+// go test -run Test_JSONSchema_Next
+func Test_JSONSchema_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Schema: MustCompile([]byte(`false`)),
+		Next: func(fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Post("/", func(c fiber.Ctx) error {
+		require.Nil(t, Body(c))
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+}
+
+// go test -run Test_JSONSchema_ErrorHandler
+func Test_JSONSchema_ErrorHandler(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Post("/", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	}, New(Config{
+		Schema: MustCompile([]byte(`{"type": "object", "required": ["id"]}`)),
+		ErrorHandler: func(c fiber.Ctx, err error) error {
+			var errs Errors
+			require.ErrorAs(t, err, &errs)
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		},
+	}))
+
+	req := httptest.NewRequest(fiber.MethodPost, "/", strings.NewReader(`{}`))
+	req.Header.Set(fiber.HeaderContentType, "application/vnd.api+json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "jsonschema: #/id: is required", string(body))
+}
+
+// go test -run Test_JSONSchema_RequiresSchema
+func Test_JSONSchema_RequiresSchema(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New()
+	})
+	require.Panics(t, func() {
+		New(Config{})
+	})
+}
+
+// go test -run Test_Compile
+func Test_Compile(t *testing.T) {
+	t.Parallel()
+	for _, document := range []string{
+		`{`,
+		`1`,
+		`{"type": "int"}`,
+		`{"type": 1}`,
+		`{"pattern": "("}`,
+		`{"minLength": -1}`,
+		`{"maximum": "1"}`,
+		`{"anyOf": []}`,
+		`{"required": "id"}`,
+		`{"$ref": "https://example.com/schema.json"}`,
+		`{"$ref": "#/$defs/missing"}`,
+	} {
+		_, err := Compile([]byte(document))
+		require.ErrorIs(t, err, ErrInvalidSchema, document)
+	}
+
+	require.Panics(t, func() {
+		MustCompile([]byte(`{`))
+	})
+}
+
+// go test -run Test_Schema_Validate
+func Test_Schema_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		schema string
+		value  string
+		errors []string
+	}{
+		{schema: `true`, value: `1`},
+		{schema: `{"type": ["string", "null"]}`, value: `null`},
+		{schema: `{"type": "integer"}`, value: `"1"`, errors: []string{"type"}},
+		{schema: `{"type": "integer"}`, value: `1.0`},
+		{schema: `{"const": {"a": [1]}}`, value: `{"a": [1.0]}`},
+		{schema: `{"const": 1}`, value: `2`, errors: []string{"const"}},
+		{schema: `{"enum": ["a", 1]}`, value: `"b"`, errors: []string{"enum"}},
+		{schema: `{"exclusiveMinimum": 1, "exclusiveMaximum": 3}`, value: `3`, errors: []string{"exclusiveMaximum"}},
+		{schema: `{"minimum": 1, "exclusiveMinimum": true}`, value: `1`, errors: []string{"exclusiveMinimum"}},
+		{schema: `{"multipleOf": 0.5}`, value: `1.25`, errors: []string{"multipleOf"}},
+		{schema: `{"maxLength": 2}`, value: `"äöü"`, errors: []string{"maxLength"}},
+		{schema: `{"minItems": 2, "maxItems": 0}`, value: `[1]`, errors: []string{"minItems", "maxItems"}},
+		{schema: `{"minProperties": 1}`, value: `{}`, errors: []string{"minProperties"}},
+		{schema: `{"format": "date-time"}`, value: `"2024-01-02T03:04:05Z"`},
+		{schema: `{"format": "uuid"}`, value: `"nope"`, errors: []string{"format"}},
+		{schema: `{"format": "unknown"}`, value: `"anything"`},
+		{schema: `{"allOf": [{"minimum": 1}, {"maximum": 2}]}`, value: `3`, errors: []string{"maximum"}},
+		{schema: `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`, value: `true`, errors: []string{"anyOf"}},
+		{schema: `{"oneOf": [{"type": "number"}, {"type": "integer"}]}`, value: `1`, errors: []string{"oneOf"}},
+		{schema: `{"not": {"type": "null"}}`, value: `null`, errors: []string{"not"}},
+		{schema: `{"additionalProperties": {"type": "integer"}}`, value: `{"a": 1, "b": "2"}`, errors: []string{"type"}},
+		{
+			schema: `{"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}}}}, "$ref": "#/$defs/node"}`,
+			value:  `{"next": {"next": {"next": 1}}}`,
+			errors: []string{"type"},
+		},
+	}
+
+	for _, tt := range tests {
+		var value any
+		require.NoError(t, json.Unmarshal([]byte(tt.value), &value))
+		err := MustCompile([]byte(tt.schema)).Validate(value)
+		if len(tt.errors) == 0 {
+			require.NoError(t, err, tt.schema)
+			continue
+		}
+		var errs Errors
+		require.ErrorAs(t, err, &errs, tt.schema)
+		keywords := make([]string, len(errs))
+		for i, e := range errs {
+			keywords[i] = e.Keyword
+		}
+		require.Equal(t, tt.errors, keywords, tt.schema)
+	}
+}
+
+// go test -run Test_Schema_Coerce
+func Test_Schema_Coerce(t *testing.T) {
+	t.Parallel()
+	schema := MustCompile([]byte(`{
+		"type": "object",
+		"properties": {
+			"a~/b": {"type": "string"},
+			"flag": {"type": "boolean"},
+			"n": {"type": ["null", "number"]},
+			"count": {"type": "integer", "default": 10},
+			"either": {"oneOf": [{"type": "integer"}, {"type": "string", "minLength": 5}]}
+		}
+	}`))
+
+	value, err := schema.Coerce(map[string]any{"a~/b": 1.5, "flag": "false", "n": "", "either": "7"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"a~/b":   "1.5",
+		"flag":   false,
+		"n":      nil,
+		"count":  int64(10),
+		"either": int64(7),
+	}, value)
+
+	_, err = schema.Coerce(map[string]any{"a~/b": []any{}, "flag": "yes"})
+	require.EqualError(t, err, `jsonschema: #/a~0~1b: must be string; #/flag: must be boolean`)
+
+	// Validate doesn't coerce
+	require.Error(t, schema.Validate(map[string]any{"flag": "true"}))
+	require.NoError(t, schema.Validate(map[string]any{"flag": true, "count": 3}))
+}
+
+// go test -run Test_RegisterFormat
+func Test_RegisterFormat(t *testing.T) {
+	t.Parallel()
+	RegisterFormat("x-even-length", func(value string) bool {
+		return len(value)%2 == 0
+	})
+	schema := MustCompile([]byte(`{"format": "x-even-length"}`))
+	require.NoError(t, schema.Validate("ab"))
+	require.Error(t, schema.Validate("abc"))
+}
@@ -0,0 +1,746 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidSchema is returned by Compile if the schema document is invalid or
+// uses a keyword which isn't supported, like a remote $ref.
+var ErrInvalidSchema = errors.New("jsonschema: invalid schema")
+
+// Error is a violation of a keyword of the schema.
+type Error struct {
+	// Pointer is the JSON pointer (RFC 6901) of the invalid value, e.g. "/items/0/name".
+	// It is empty for the root value.
+	Pointer string `json:"pointer"`
+	// Keyword is the violated keyword of the schema, e.g. "required" or "minLength".
+	Keyword string `json:"keyword"`
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+// Errors are the violations of a value, in the order of the value.
+type Errors []Error
+
+// Error returns the violations with the URI fragments of their pointers.
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = "#" + err.Pointer + ": " + err.Message
+	}
+	return "jsonschema: " + strings.Join(messages, "; ")
+}
+
+// add appends a violation.
+func (e *Errors) add(pointer, keyword, format string, args ...any) {
+	*e = append(*e, Error{Pointer: pointer, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+}
+
+// Schema is a compiled JSON Schema. The keywords of the validation vocabulary of
+// draft 2020-12 and draft-07 are supported, $ref can point to the schema itself,
+// e.g. "#/$defs/address".
+type Schema struct {
+	boolean *bool
+	ref     *Schema
+
+	types         []string
+	enum          []any
+	constant      any
+	hasConstant   bool
+	defaultValue  any
+	hasDefault    bool
+	format        string
+	pattern       *regexp.Regexp
+	minLength     *int
+	maxLength     *int
+	minimum       *float64
+	maximum       *float64
+	exclusiveMin  *float64
+	exclusiveMax  *float64
+	multipleOf    *float64
+	items         *Schema
+	minItems      *int
+	maxItems      *int
+	uniqueItems   bool
+	properties    map[string]*Schema
+	propertyNames []string // sorted
+	required      []string
+	additional    *Schema
+	minProperties *int
+	maxProperties *int
+	allOf         []*Schema
+	anyOf         []*Schema
+	oneOf         []*Schema
+	not           *Schema
+}
+
+// Compile compiles a JSON Schema document.
+func Compile(document []byte) (*Schema, error) {
+	var root any
+	if err := json.Unmarshal(document, &root); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchema, err)
+	}
+	c := &compiler{root: root, schemas: make(map[string]*Schema)}
+	return c.compile(root, "")
+}
+
+// MustCompile is like Compile but panics if the document is invalid.
+func MustCompile(document []byte) *Schema {
+	schema, err := Compile(document)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// compiler compiles the schemas of a document, the schemas are cached by
+// their pointer, so recursive references are compiled once.
+type compiler struct {
+	root    any
+	schemas map[string]*Schema
+}
+
+func (c *compiler) compile(node any, pointer string) (*Schema, error) {
+	if schema, ok := c.schemas[pointer]; ok {
+		return schema, nil
+	}
+	s := &Schema{}
+	c.schemas[pointer] = s
+
+	var n map[string]any
+	switch node := node.(type) {
+	case bool:
+		s.boolean = &node
+		return s, nil
+	case map[string]any:
+		n = node
+	default:
+		return nil, fmt.Errorf("%w: #%s must be an object or a boolean", ErrInvalidSchema, pointer)
+	}
+
+	var err error
+	if ref, ok := n["$ref"].(string); ok {
+		if s.ref, err = c.resolve(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	switch types := n["type"].(type) {
+	case nil:
+	case string:
+		s.types = []string{types}
+	case []any:
+		for _, t := range types {
+			name, ok := t.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: #%s/type must contain strings", ErrInvalidSchema, pointer)
+			}
+			s.types = append(s.types, name)
+		}
+	default:
+		return nil, fmt.Errorf("%w: #%s/type must be a string or an array", ErrInvalidSchema, pointer)
+	}
+	for _, t := range s.types {
+		switch t {
+		case "null", "boolean", "object", "array", "number", "integer", "string":
+		default:
+			return nil, fmt.Errorf("%w: #%s/type %q is unknown", ErrInvalidSchema, pointer, t)
+		}
+	}
+
+	if enum, ok := n["enum"]; ok {
+		if s.enum, ok = enum.([]any); !ok {
+			return nil, fmt.Errorf("%w: #%s/enum must be an array", ErrInvalidSchema, pointer)
+		}
+	}
+	s.constant, s.hasConstant = n["const"]
+	s.defaultValue, s.hasDefault = n["default"]
+	s.format, _ = n["format"].(string) //nolint:errcheck // the format is optional
+
+	if pattern, ok := n["pattern"].(string); ok {
+		if s.pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("%w: #%s/pattern: %w", ErrInvalidSchema, pointer, err)
+		}
+	}
+
+	counts := map[string]**int{
+		"minLength": &s.minLength, "maxLength": &s.maxLength,
+		"minItems": &s.minItems, "maxItems": &s.maxItems,
+		"minProperties": &s.minProperties, "maxProperties": &s.maxProperties,
+	}
+	for keyword, field := range counts {
+		if *field, err = count(n, keyword, pointer); err != nil {
+			return nil, err
+		}
+	}
+	numbers := map[string]**float64{
+		"minimum": &s.minimum, "maximum": &s.maximum, "multipleOf": &s.multipleOf,
+	}
+	for keyword, field := range numbers {
+		if *field, err = number(n, keyword, pointer); err != nil {
+			return nil, err
+		}
+	}
+	// draft-07 uses numbers, draft-04 booleans for the exclusive limits
+	if s.exclusiveMin, err = exclusive(n, "exclusiveMinimum", pointer, s.minimum); err != nil {
+		return nil, err
+	}
+	if s.exclusiveMax, err = exclusive(n, "exclusiveMaximum", pointer, s.maximum); err != nil {
+		return nil, err
+	}
+	s.uniqueItems, _ = n["uniqueItems"].(bool) //nolint:errcheck // uniqueItems is optional
+
+	if items, ok := n["items"]; ok {
+		if s.items, err = c.compile(items, pointer+"/items"); err != nil {
+			return nil, err
+		}
+	}
+	if additional, ok := n["additionalProperties"]; ok {
+		if s.additional, err = c.compile(additional, pointer+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+	if not, ok := n["not"]; ok {
+		if s.not, err = c.compile(not, pointer+"/not"); err != nil {
+			return nil, err
+		}
+	}
+
+	if properties, ok := n["properties"]; ok {
+		props, ok := properties.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: #%s/properties must be an object", ErrInvalidSchema, pointer)
+		}
+		s.properties = make(map[string]*Schema, len(props))
+		for name, prop := range props {
+			if s.properties[name], err = c.compile(prop, pointer+"/properties/"+escape(name)); err != nil {
+				return nil, err
+			}
+			s.propertyNames = append(s.propertyNames, name)
+		}
+		sort.Strings(s.propertyNames)
+	}
+	if required, ok := n["required"]; ok {
+		names, ok := required.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: #%s/required must be an array", ErrInvalidSchema, pointer)
+		}
+		for _, name := range names {
+			prop, ok := name.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: #%s/required must contain strings", ErrInvalidSchema, pointer)
+			}
+			s.required = append(s.required, prop)
+		}
+	}
+
+	lists := map[string]*[]*Schema{"allOf": &s.allOf, "anyOf": &s.anyOf, "oneOf": &s.oneOf}
+	for keyword, field := range lists {
+		list, ok := n[keyword]
+		if !ok {
+			continue
+		}
+		schemas, ok := list.([]any)
+		if !ok || len(schemas) == 0 {
+			return nil, fmt.Errorf("%w: #%s/%s must be a non-empty array", ErrInvalidSchema, pointer, keyword)
+		}
+		for i, schema := range schemas {
+			compiled, err := c.compile(schema, pointer+"/"+keyword+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			*field = append(*field, compiled)
+		}
+	}
+
+	return s, nil
+}
+
+// resolve compiles the schema of a reference within the document.
+func (c *compiler) resolve(ref string) (*Schema, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("%w: $ref %q must point into the document", ErrInvalidSchema, ref)
+	}
+	pointer, err := url.PathUnescape(ref[1:])
+	if err != nil {
+		return nil, fmt.Errorf("%w: $ref %q: %w", ErrInvalidSchema, ref, err)
+	}
+
+	node := c.root
+	if pointer != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			object, ok := node.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%w: $ref %q not found", ErrInvalidSchema, ref)
+			}
+			if node, ok = object[unescape(token)]; !ok {
+				return nil, fmt.Errorf("%w: $ref %q not found", ErrInvalidSchema, ref)
+			}
+		}
+	}
+	return c.compile(node, pointer)
+}
+
+// count returns the non-negative integer of the keyword.
+func count(n map[string]any, keyword, pointer string) (*int, error) {
+	value, ok := n[keyword]
+	if !ok {
+		return nil, nil
+	}
+	f, ok := value.(float64)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, fmt.Errorf("%w: #%s/%s must be a non-negative integer", ErrInvalidSchema, pointer, keyword)
+	}
+	i := int(f)
+	return &i, nil
+}
+
+// number returns the number of the keyword.
+func number(n map[string]any, keyword, pointer string) (*float64, error) {
+	value, ok := n[keyword]
+	if !ok {
+		return nil, nil
+	}
+	f, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%w: #%s/%s must be a number", ErrInvalidSchema, pointer, keyword)
+	}
+	return &f, nil
+}
+
+// exclusive returns the exclusive limit of the keyword, a boolean makes the limit exclusive.
+func exclusive(n map[string]any, keyword, pointer string, limit *float64) (*float64, error) {
+	if value, ok := n[keyword].(bool); ok {
+		if value {
+			return limit, nil
+		}
+		return nil, nil
+	}
+	return number(n, keyword, pointer)
+}
+
+// Validate reports the violations of the value as Errors, the value isn't coerced.
+// Objects are map[string]any, arrays []any and numbers float64 or integer types,
+// like the values decoded by encoding/json.
+func (s *Schema) Validate(value any) error {
+	var errs Errors
+	s.validate(clone(value), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Coerce converts the value to the types of the schema and validates it. Strings
+// are converted to numbers, integers, booleans and null, numbers and booleans to
+// strings, single values to arrays and missing properties are set to their default.
+// Integers are returned as int64. The maps and slices of the value are modified.
+func (s *Schema) Coerce(value any) (any, error) {
+	var errs Errors
+	value = s.coerce(value, "", &errs)
+	if len(errs) > 0 {
+		return value, errs
+	}
+	return value, nil
+}
+
+// validate checks the value without coercion.
+func (s *Schema) validate(v any, pointer string, errs *Errors) {
+	s.apply(v, pointer, errs, false)
+}
+
+// coerce checks the value and returns the coerced value.
+func (s *Schema) coerce(v any, pointer string, errs *Errors) any {
+	return s.apply(v, pointer, errs, true)
+}
+
+//nolint:gocyclo // The keywords are checked one after another
+func (s *Schema) apply(v any, pointer string, errs *Errors, coerce bool) any {
+	if s.boolean != nil {
+		if !*s.boolean {
+			errs.add(pointer, "false", "is not allowed")
+		}
+		return v
+	}
+	if s.ref != nil {
+		v = s.ref.apply(v, pointer, errs, coerce)
+	}
+
+	if len(s.types) > 0 {
+		converted, ok := convert(v, s.types, coerce)
+		if !ok {
+			errs.add(pointer, "type", "must be %s", strings.Join(s.types, " or "))
+			return v
+		}
+		v = converted
+	}
+	if s.hasConstant && !equal(v, s.constant) {
+		errs.add(pointer, "const", "must be %s", display(s.constant))
+	}
+	if len(s.enum) > 0 && !contains(s.enum, v) {
+		values := make([]string, len(s.enum))
+		for i, value := range s.enum {
+			values[i] = display(value)
+		}
+		errs.add(pointer, "enum", "must be one of %s", strings.Join(values, ", "))
+	}
+
+	switch value := v.(type) {
+	case string:
+		s.applyString(value, pointer, errs)
+	case []any:
+		v = s.applyArray(value, pointer, errs, coerce)
+	case map[string]any:
+		v = s.applyObject(value, pointer, errs, coerce)
+	default:
+		if f, ok := toFloat(v); ok {
+			s.applyNumber(f, pointer, errs)
+		}
+	}
+
+	for _, schema := range s.allOf {
+		v = schema.apply(v, pointer, errs, coerce)
+	}
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, schema := range s.anyOf {
+			if result, ok := schema.try(v, pointer, coerce); ok {
+				v, matched = result, true
+				break
+			}
+		}
+		if !matched {
+			errs.add(pointer, "anyOf", "must match at least one schema")
+		}
+	}
+	if len(s.oneOf) > 0 {
+		matches := 0
+		var match any
+		for _, schema := range s.oneOf {
+			if result, ok := schema.try(v, pointer, coerce); ok {
+				if matches == 0 {
+					match = result
+				}
+				matches++
+			}
+		}
+		if matches == 1 {
+			v = match
+		} else {
+			errs.add(pointer, "oneOf", "must match exactly one schema, matched %d", matches)
+		}
+	}
+	if s.not != nil {
+		if _, ok := s.not.try(v, pointer, false); ok {
+			errs.add(pointer, "not", "must not match the schema")
+		}
+	}
+	return v
+}
+
+// try applies the schema to a copy of the value and reports if it matches.
+func (s *Schema) try(v any, pointer string, coerce bool) (any, bool) {
+	var errs Errors
+	result := s.apply(clone(v), pointer, &errs, coerce)
+	return result, len(errs) == 0
+}
+
+func (s *Schema) applyString(value, pointer string, errs *Errors) {
+	length := utf8.RuneCountInString(value)
+	if s.minLength != nil && length < *s.minLength {
+		errs.add(pointer, "minLength", "must be at least %d characters long", *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		errs.add(pointer, "maxLength", "must be at most %d characters long", *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		errs.add(pointer, "pattern", "must match the pattern %s", s.pattern)
+	}
+	if s.format != "" {
+		if check := lookupFormat(s.format); check != nil && !check(value) {
+			errs.add(pointer, "format", "must be a valid %s", s.format)
+		}
+	}
+}
+
+func (s *Schema) applyNumber(value float64, pointer string, errs *Errors) {
+	if s.minimum != nil && value < *s.minimum {
+		errs.add(pointer, "minimum", "must be >= %s", display(*s.minimum))
+	}
+	if s.maximum != nil && value > *s.maximum {
+		errs.add(pointer, "maximum", "must be <= %s", display(*s.maximum))
+	}
+	if s.exclusiveMin != nil && value <= *s.exclusiveMin {
+		errs.add(pointer, "exclusiveMinimum", "must be > %s", display(*s.exclusiveMin))
+	}
+	if s.exclusiveMax != nil && value >= *s.exclusiveMax {
+		errs.add(pointer, "exclusiveMaximum", "must be < %s", display(*s.exclusiveMax))
+	}
+	if s.multipleOf != nil && *s.multipleOf > 0 {
+		if q := value / *s.multipleOf; q != math.Trunc(q) {
+			errs.add(pointer, "multipleOf", "must be a multiple of %s", display(*s.multipleOf))
+		}
+	}
+}
+
+func (s *Schema) applyArray(value []any, pointer string, errs *Errors, coerce bool) []any {
+	if s.minItems != nil && len(value) < *s.minItems {
+		errs.add(pointer, "minItems", "must have at least %d items", *s.minItems)
+	}
+	if s.maxItems != nil && len(value) > *s.maxItems {
+		errs.add(pointer, "maxItems", "must have at most %d items", *s.maxItems)
+	}
+	if s.items != nil {
+		for i, item := range value {
+			value[i] = s.items.apply(item, pointer+"/"+strconv.Itoa(i), errs, coerce)
+		}
+	}
+	if s.uniqueItems {
+		for i := 1; i < len(value); i++ {
+			if contains(value[:i], value[i]) {
+				errs.add(pointer+"/"+strconv.Itoa(i), "uniqueItems", "must be unique")
+			}
+		}
+	}
+	return value
+}
+
+func (s *Schema) applyObject(value map[string]any, pointer string, errs *Errors, coerce bool) map[string]any {
+	if coerce {
+		for _, name := range s.propertyNames {
+			if prop := s.properties[name]; prop.hasDefault {
+				if _, ok := value[name]; !ok {
+					value[name] = clone(prop.defaultValue)
+				}
+			}
+		}
+	}
+
+	for _, name := range s.required {
+		if _, ok := value[name]; !ok {
+			errs.add(pointer+"/"+escape(name), "required", "is required")
+		}
+	}
+	if s.minProperties != nil && len(value) < *s.minProperties {
+		errs.add(pointer, "minProperties", "must have at least %d properties", *s.minProperties)
+	}
+	if s.maxProperties != nil && len(value) > *s.maxProperties {
+		errs.add(pointer, "maxProperties", "must have at most %d properties", *s.maxProperties)
+	}
+
+	for _, name := range s.propertyNames {
+		if prop, ok := value[name]; ok {
+			value[name] = s.properties[name].apply(prop, pointer+"/"+escape(name), errs, coerce)
+		}
+	}
+	if s.additional != nil {
+		names := make([]string, 0, len(value))
+		for name := range value {
+			if _, ok := s.properties[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if s.additional.boolean != nil && !*s.additional.boolean {
+				errs.add(pointer+"/"+escape(name), "additionalProperties", "is not allowed")
+				continue
+			}
+			value[name] = s.additional.apply(value[name], pointer+"/"+escape(name), errs, coerce)
+		}
+	}
+	return value
+}
+
+// convert returns the value as one of the types, coercion converts the value
+// if it doesn't have one of the types.
+func convert(v any, types []string, coerce bool) (any, bool) {
+	for _, t := range types {
+		if is(v, t) {
+			if t == "integer" && coerce {
+				if f, ok := v.(float64); ok && math.Abs(f) < 1<<53 {
+					return int64(f), true
+				}
+			}
+			return v, true
+		}
+	}
+	if !coerce {
+		return v, false
+	}
+
+	for _, t := range types {
+		switch value := v.(type) {
+		case string:
+			switch t {
+			case "integer":
+				if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+					return i, true
+				}
+			case "number":
+				if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+					return f, true
+				}
+			case "boolean":
+				if value == "true" || value == "false" {
+					return value == "true", true
+				}
+			case "null":
+				if value == "" {
+					return nil, true
+				}
+			}
+		case bool:
+			if t == "string" {
+				return strconv.FormatBool(value), true
+			}
+		case nil:
+		default:
+			if f, ok := toFloat(v); ok && t == "string" {
+				return strconv.FormatFloat(f, 'f', -1, 64), true
+			}
+		}
+		if _, ok := v.([]any); !ok && t == "array" && v != nil {
+			return []any{v}, true
+		}
+	}
+	return v, false
+}
+
+// is reports if the value has the JSON type.
+func is(v any, t string) bool {
+	switch t {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "number":
+		_, ok := toFloat(v)
+		return ok
+	case "integer":
+		f, ok := toFloat(v)
+		return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+	}
+	return false
+}
+
+// toFloat returns the number as float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// equal reports if the JSON values are equal, numbers are compared by their value.
+func equal(a, b any) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	switch a := a.(type) {
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// contains reports if one of the values equals the value.
+func contains(values []any, v any) bool {
+	for _, value := range values {
+		if equal(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a deep copy of the maps and slices of the value.
+func clone(v any) any {
+	switch value := v.(type) {
+	case []any:
+		items := make([]any, len(value))
+		for i, item := range value {
+			items[i] = clone(item)
+		}
+		return items
+	case map[string]any:
+		object := make(map[string]any, len(value))
+		for key, item := range value {
+			object[key] = clone(item)
+		}
+		return object
+	}
+	return v
+}
+
+// display returns the value as JSON for the messages.
+func display(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// escape escapes a token of a JSON pointer.
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// unescape unescapes a token of a JSON pointer.
+func unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
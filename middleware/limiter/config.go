@@ -25,6 +25,14 @@ type Config struct {
 	// }
 	KeyGenerator func(fiber.Ctx) string
 
+	// Cost returns how many hits of the Max budget a request consumes, so expensive
+	// endpoints can consume more of the budget than cheap ones. A cost of 0 doesn't
+	// consume the budget. When Cost is set, rejected requests don't consume the budget
+	// and the responses have a X-RateLimit-Cost header.
+	//
+	// Optional. Default: nil, every request costs 1
+	Cost func(fiber.Ctx) int
+
 	// Expiration is the time on how long to keep records of requests in memory
 	//
 	// Default: 1 * time.Minute
@@ -52,6 +60,12 @@ type Config struct {
 	// Default: an in memory store for this process only
 	Storage fiber.Storage
 
+	// Clock is the source of the time of the windows and of the in memory store,
+	// e.g. a fiber.ManualClock in tests.
+	//
+	// Default: nil, the system time
+	Clock fiber.Clock
+
 	// LimiterMiddleware is the struct that implements a limiter middleware.
 	//
 	// Default: a new Fixed Window Rate Limiter
@@ -1,7 +1,10 @@
 package limiter
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
 )
 
 const (
@@ -9,6 +12,7 @@ const (
 	xRateLimitLimit     = "X-RateLimit-Limit"
 	xRateLimitRemaining = "X-RateLimit-Remaining"
 	xRateLimitReset     = "X-RateLimit-Reset"
+	xRateLimitCost      = "X-RateLimit-Cost"
 )
 
 type Handler interface {
@@ -23,3 +27,42 @@ func New(config ...Config) fiber.Handler {
 	// Return the specified middleware handler.
 	return cfg.LimiterMiddleware.New(cfg)
 }
+
+// StaticCost returns a Cost function of a fixed cost, e.g. for a limiter of an expensive route.
+func StaticCost(cost int) func(fiber.Ctx) int {
+	return func(fiber.Ctx) int {
+		return cost
+	}
+}
+
+// requestCost returns the cost of the request, 1 if the config has no Cost function.
+func requestCost(cfg Config, c fiber.Ctx) int {
+	if cfg.Cost == nil {
+		return 1
+	}
+	if cost := cfg.Cost(c); cost > 0 {
+		return cost
+	}
+	return 0
+}
+
+// timestamp returns the current unix time of the clock, or the cached time of the system.
+func timestamp(clock fiber.Clock) uint64 {
+	if clock == nil {
+		return uint64(utils.Timestamp())
+	}
+	return uint64(clock.Now().Unix())
+}
+
+// setHeaders sets the RateLimit headers of the remaining budget.
+func setHeaders(c fiber.Ctx, cfg Config, limit string, remaining, cost int, resetInSec uint64) {
+	if remaining < 0 {
+		remaining = 0
+	}
+	c.Set(xRateLimitLimit, limit)
+	c.Set(xRateLimitRemaining, strconv.Itoa(remaining))
+	c.Set(xRateLimitReset, strconv.FormatUint(resetInSec, 10))
+	if cfg.Cost != nil {
+		c.Set(xRateLimitCost, strconv.Itoa(cost))
+	}
+}
@@ -20,7 +20,7 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 	)
 
 	// Create manager to simplify storage operations ( see manager.go )
-	manager := newManager(cfg.Storage)
+	manager := newManager(cfg.Storage, cfg.Clock)
 
 	// Update timestamp every second
 	utils.StartTimeStampUpdater()
@@ -35,6 +35,9 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 		// Get key from request
 		key := cfg.KeyGenerator(c)
 
+		// Get the cost of the request
+		cost := requestCost(cfg, c)
+
 		// Lock entry
 		mux.Lock()
 
@@ -42,7 +45,7 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 		e := manager.get(key)
 
 		// Get timestamp
-		ts := uint64(utils.Timestamp())
+		ts := timestamp(cfg.Clock)
 
 		// Set expiration if entry does not exist
 		if e.exp == 0 {
@@ -53,8 +56,8 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 			e.exp = ts + expiration
 		}
 
-		// Increment hits
-		e.currHits++
+		// Increment hits by the cost of the request
+		e.currHits += cost
 
 		// Calculate when it resets in seconds
 		resetInSec := e.exp - ts
@@ -62,6 +65,11 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 		// Set how many hits we have left
 		remaining := cfg.Max - e.currHits
 
+		// Rejected requests of a costed budget don't consume it, so cheaper requests can still pass
+		if remaining < 0 && cfg.Cost != nil {
+			e.currHits -= cost
+		}
+
 		// Update storage
 		manager.set(key, e, cfg.Expiration)
 
@@ -73,6 +81,9 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 			// Return response with Retry-After header
 			// https://tools.ietf.org/html/rfc6584
 			c.Set(fiber.HeaderRetryAfter, strconv.FormatUint(resetInSec, 10))
+			if cfg.Cost != nil {
+				setHeaders(c, cfg, max, remaining+cost, cost, resetInSec)
+			}
 
 			// Call LimitReached handler
 			return cfg.LimitReached(c)
@@ -88,17 +99,15 @@ func (FixedWindow) New(cfg Config) fiber.Handler {
 			// Lock entry
 			mux.Lock()
 			e = manager.get(key)
-			e.currHits--
-			remaining++
+			e.currHits -= cost
+			remaining += cost
 			manager.set(key, e, cfg.Expiration)
 			// Unlock entry
 			mux.Unlock()
 		}
 
 		// We can continue, update RateLimit headers
-		c.Set(xRateLimitLimit, max)
-		c.Set(xRateLimitRemaining, strconv.Itoa(remaining))
-		c.Set(xRateLimitReset, strconv.FormatUint(resetInSec, 10))
+		setHeaders(c, cfg, max, remaining, cost, resetInSec)
 
 		return err
 	}
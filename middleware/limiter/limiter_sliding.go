@@ -21,7 +21,7 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 	)
 
 	// Create manager to simplify storage operations ( see manager.go )
-	manager := newManager(cfg.Storage)
+	manager := newManager(cfg.Storage, cfg.Clock)
 
 	// Update timestamp every second
 	utils.StartTimeStampUpdater()
@@ -36,6 +36,9 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 		// Get key from request
 		key := cfg.KeyGenerator(c)
 
+		// Get the cost of the request
+		cost := requestCost(cfg, c)
+
 		// Lock entry
 		mux.Lock()
 
@@ -43,7 +46,7 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 		e := manager.get(key)
 
 		// Get timestamp
-		ts := uint64(utils.Timestamp())
+		ts := timestamp(cfg.Clock)
 
 		// Set expiration if entry does not exist
 		if e.exp == 0 {
@@ -67,8 +70,8 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 			}
 		}
 
-		// Increment hits
-		e.currHits++
+		// Increment hits by the cost of the request
+		e.currHits += cost
 
 		// Calculate when it resets in seconds
 		resetInSec := e.exp - ts
@@ -82,6 +85,11 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 		// Calculate how many hits can be made based on the current rate
 		remaining := cfg.Max - rate
 
+		// Rejected requests of a costed budget don't consume it, so cheaper requests can still pass
+		if remaining < 0 && cfg.Cost != nil {
+			e.currHits -= cost
+		}
+
 		// Update storage. Garbage collect when the next window ends.
 		// |--------------------------|--------------------------|
 		//               ^            ^               ^          ^
@@ -104,6 +112,9 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 			// Return response with Retry-After header
 			// https://tools.ietf.org/html/rfc6584
 			c.Set(fiber.HeaderRetryAfter, strconv.FormatUint(resetInSec, 10))
+			if cfg.Cost != nil {
+				setHeaders(c, cfg, max, remaining+cost, cost, resetInSec)
+			}
 
 			// Call LimitReached handler
 			return cfg.LimitReached(c)
@@ -119,17 +130,15 @@ func (SlidingWindow) New(cfg Config) fiber.Handler {
 			// Lock entry
 			mux.Lock()
 			e = manager.get(key)
-			e.currHits--
-			remaining++
+			e.currHits -= cost
+			remaining += cost
 			manager.set(key, e, cfg.Expiration)
 			// Unlock entry
 			mux.Unlock()
 		}
 
 		// We can continue, update RateLimit headers
-		c.Set(xRateLimitLimit, max)
-		c.Set(xRateLimitRemaining, strconv.Itoa(remaining))
-		c.Set(xRateLimitReset, strconv.FormatUint(resetInSec, 10))
+		setHeaders(c, cfg, max, remaining, cost, resetInSec)
 
 		return err
 	}
@@ -14,6 +14,38 @@ import (
 	"github.com/valyala/fasthttp"
 )
 
+// go test -run Test_Limiter_Clock
+func Test_Limiter_Clock(t *testing.T) {
+	t.Parallel()
+	for _, middleware := range []Handler{FixedWindow{}, SlidingWindow{}} {
+		clock := fiber.NewManualClock(time.Now())
+		app := fiber.New()
+		app.Use(New(Config{
+			Max:               1,
+			Expiration:        time.Minute,
+			Clock:             clock,
+			LimiterMiddleware: middleware,
+		}))
+		app.Get("/", func(c fiber.Ctx) error {
+			return c.SendString("Hello tester!")
+		})
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+		require.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+
+		// the window of the clock ends without sleeping
+		clock.Advance(2 * time.Minute)
+		resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	}
+}
+
 // go test -run Test_Limiter_Concurrency_Store -race -v
 func Test_Limiter_Concurrency_Store(t *testing.T) {
 	t.Parallel()
@@ -637,6 +669,84 @@ func Test_Limiter_Headers(t *testing.T) {
 	}
 }
 
+// go test -run Test_Limiter_Cost
+func Test_Limiter_Cost(t *testing.T) {
+	t.Parallel()
+	for _, middleware := range []Handler{FixedWindow{}, SlidingWindow{}} {
+		app := fiber.New()
+		app.Use(New(Config{
+			Max:        10,
+			Expiration: time.Minute,
+			Cost: func(c fiber.Ctx) int {
+				if c.Path() == "/export" {
+					return 4
+				}
+				return 1
+			},
+			LimiterMiddleware: middleware,
+		}))
+		app.Get("/*", func(c fiber.Ctx) error {
+			return c.SendString("Hello tester!")
+		})
+
+		request := func(path string, status int, remaining, cost string) {
+			t.Helper()
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+			require.NoError(t, err)
+			require.Equal(t, status, resp.StatusCode)
+			require.Equal(t, "10", resp.Header.Get("X-RateLimit-Limit"))
+			require.Equal(t, remaining, resp.Header.Get("X-RateLimit-Remaining"))
+			require.Equal(t, cost, resp.Header.Get("X-RateLimit-Cost"))
+		}
+
+		request("/export", fiber.StatusOK, "6", "4")
+		request("/export", fiber.StatusOK, "2", "4")
+		// the rejected request doesn't consume the budget
+		request("/export", fiber.StatusTooManyRequests, "2", "4")
+		request("/cheap", fiber.StatusOK, "1", "1")
+		request("/cheap", fiber.StatusOK, "0", "1")
+		request("/cheap", fiber.StatusTooManyRequests, "0", "1")
+	}
+}
+
+// go test -run Test_Limiter_StaticCost
+func Test_Limiter_StaticCost(t *testing.T) {
+	t.Parallel()
+	// the limiters of the routes share the budget with a shared storage
+	storage := memory.New()
+	app := fiber.New()
+	handler := func(c fiber.Ctx) error {
+		return c.SendString("Hello tester!")
+	}
+	app.Get("/search", handler, New(Config{Max: 5, Storage: storage, Cost: StaticCost(3)}))
+	app.Get("/free", handler, New(Config{Max: 5, Storage: storage, Cost: StaticCost(0)}))
+	app.Get("/fail", func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}, New(Config{Max: 5, Storage: storage, Cost: StaticCost(2), SkipFailedRequests: true}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/search", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "2", resp.Header.Get("X-RateLimit-Remaining"))
+
+	// failed requests are refunded with their cost
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	require.Equal(t, "2", resp.Header.Get("X-RateLimit-Remaining"))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/free", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "2", resp.Header.Get("X-RateLimit-Remaining"))
+	require.Equal(t, "0", resp.Header.Get("X-RateLimit-Cost"))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/search", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	require.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
 // go test -v -run=^$ -bench=Benchmark_Limiter -benchmem -count=4
 func Benchmark_Limiter(b *testing.B) {
 	app := fiber.New()
@@ -23,7 +23,7 @@ type manager struct {
 	storage fiber.Storage
 }
 
-func newManager(storage fiber.Storage) *manager {
+func newManager(storage fiber.Storage, clock fiber.Clock) *manager {
 	// Create new storage handler
 	manager := &manager{
 		pool: sync.Pool{
@@ -37,7 +37,7 @@ func newManager(storage fiber.Storage) *manager {
 		manager.storage = storage
 	} else {
 		// Fallback too memory storage
-		manager.memory = memory.New()
+		manager.memory = memory.New(clock)
 	}
 	return manager
 }
@@ -15,6 +15,7 @@ import (
 func New(config ...Config) fiber.Handler {
 	// Set default config
 	cfg := configDefault(config...)
+	outputSet := len(config) > 0 && config[0].Output != nil
 
 	// Get timezone location
 	tz, err := time.LoadLocation(cfg.TimeZone)
@@ -88,6 +89,11 @@ func New(config ...Config) fiber.Handler {
 			}
 			// override error handler
 			errHandler = c.App().ErrorHandler
+			// write to the access log of the app if no output is given
+			if accessLog := c.App().Config().AccessLog; accessLog != nil && !outputSet {
+				cfg.Output = accessLog
+				cfg.enableColors = false
+			}
 		})
 
 		// Logger data
@@ -169,6 +169,31 @@ func Test_Logger_ErrorOutput_WithoutColor(t *testing.T) {
 	require.EqualValues(t, 2, *o)
 }
 
+// go test -run Test_Logger_AccessLog
+func Test_Logger_AccessLog(t *testing.T) {
+	t.Parallel()
+	accessLog := bytebufferpool.Get()
+	defer bytebufferpool.Put(accessLog)
+	app := fiber.New(fiber.Config{AccessLog: accessLog})
+
+	app.Use(New(Config{Format: "${method} ${path}\n"}))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/orders", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	require.Equal(t, "GET /orders\n", accessLog.String())
+
+	// the output of the config is preferred
+	output := bytebufferpool.Get()
+	defer bytebufferpool.Put(output)
+	app = fiber.New(fiber.Config{AccessLog: accessLog})
+	app.Use(New(Config{Format: "${path}\n", Output: output}))
+	_, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/users", nil))
+	require.NoError(t, err)
+	require.Equal(t, "/users\n", output.String())
+	require.Equal(t, "GET /orders\n", accessLog.String())
+}
+
 // go test -run Test_Logger_ErrorOutput
 func Test_Logger_ErrorOutput(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,108 @@
+package metering
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+	"github.com/gofiber/fiber/v3/middleware/tenant"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Key returns the key whose usage is recorded, e.g. the API key or the tenant.
+	// Requests with an empty key aren't recorded.
+	//
+	// Optional. Default: the ID of the tenant of the tenant middleware, or c.IP()
+	Key func(c fiber.Ctx) string
+
+	// Tag returns the tag of the route, the usage of a key is recorded per tag.
+	//
+	// Optional. Default: the name of the route, or its method and path, e.g. "GET /users/:id"
+	Tag func(c fiber.Ctx) string
+
+	// Storage stores the aggregated usage, it's shared by the instances of the app.
+	//
+	// Optional. Default: an in memory store for this process only
+	Storage fiber.Storage
+
+	// Period is the length of the periods the usage is aggregated into,
+	// e.g. time.Hour for hourly usage.
+	//
+	// Optional. Default: 1 * time.Hour
+	Period time.Duration
+
+	// FlushInterval is the interval in which the recorded usage is added to the Storage.
+	//
+	// Optional. Default: 1 * time.Minute
+	FlushInterval time.Duration
+
+	// Retention is how long the usage of a period is kept in the Storage.
+	//
+	// Optional. Default: 90 * 24 * time.Hour
+	Retention time.Duration
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:          nil,
+	Key:           defaultKey,
+	Tag:           defaultTag,
+	Period:        1 * time.Hour,
+	FlushInterval: 1 * time.Minute,
+	Retention:     90 * 24 * time.Hour,
+}
+
+// defaultKey returns the ID of the tenant of the request, or the IP address.
+func defaultKey(c fiber.Ctx) string {
+	if t := tenant.FromContext(c); t != nil {
+		return t.ID
+	}
+	return c.IP()
+}
+
+// defaultTag returns the name of the matched route, or its method and path.
+func defaultTag(c fiber.Ctx) string {
+	route := c.Route()
+	if route.Name != "" {
+		return route.Name
+	}
+	return c.Method() + " " + route.Path
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	cfg := ConfigDefault
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Set default values
+	if cfg.Key == nil {
+		cfg.Key = ConfigDefault.Key
+	}
+	if cfg.Tag == nil {
+		cfg.Tag = ConfigDefault.Tag
+	}
+	if cfg.Period <= 0 {
+		cfg.Period = ConfigDefault.Period
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = ConfigDefault.FlushInterval
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = ConfigDefault.Retention
+	}
+	if cfg.Storage == nil {
+		cfg.Storage = memory.New(memory.Config{
+			GCInterval: cfg.FlushInterval,
+		})
+	}
+	return cfg
+}
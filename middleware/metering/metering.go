@@ -0,0 +1,327 @@
+package metering
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// maxQueryPeriods is the maximum number of periods of a query.
+const maxQueryPeriods = 10000
+
+// ErrRangeTooLarge is returned by Query for ranges of more than 10000 periods.
+var ErrRangeTooLarge = errors.New("metering: the range has too many periods")
+
+// Usage is the usage of a key in a period.
+type Usage struct {
+	// Calls is the number of requests.
+	Calls int64 `json:"calls"`
+	// BytesIn is the size of the request bodies.
+	BytesIn int64 `json:"bytes_in"`
+	// BytesOut is the size of the response bodies.
+	BytesOut int64 `json:"bytes_out"`
+}
+
+// Add adds the usage of o to u.
+func (u *Usage) Add(o Usage) {
+	u.Calls += o.Calls
+	u.BytesIn += o.BytesIn
+	u.BytesOut += o.BytesOut
+}
+
+// Period is the usage of a key in a period, per tag.
+type Period struct {
+	// Start is the start of the period.
+	Start time.Time `json:"start"`
+	// Tags contains the usage of the tags which were called in the period.
+	Tags map[string]Usage `json:"tags"`
+}
+
+// Meter records the usage of the keys and aggregates it into the Storage
+// in the FlushInterval, so billing and dashboards can query it.
+type Meter struct {
+	cfg     Config
+	done    chan struct{}
+	pending map[pendingKey]map[string]*Usage
+
+	mutex sync.Mutex
+	// flushMutex serializes the flushes, whose updates of the storage aren't atomic
+	flushMutex sync.Mutex
+	once       sync.Once
+}
+
+// pendingKey identifies the recorded usage of a key in a period.
+type pendingKey struct {
+	key   string
+	start int64
+}
+
+// New creates a new meter, which flushes the recorded usage until Close is called.
+func New(config ...Config) *Meter {
+	// Set default config
+	cfg := configDefault(config...)
+
+	m := &Meter{
+		cfg:     cfg,
+		done:    make(chan struct{}),
+		pending: make(map[pendingKey]map[string]*Usage),
+	}
+	go m.flushLoop()
+	return m
+}
+
+// Handler returns the middleware handler, which records the usage of the requests.
+func (m *Meter) Handler() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if m.cfg.Next != nil && m.cfg.Next(c) {
+			return c.Next()
+		}
+
+		err := c.Next()
+
+		key := m.cfg.Key(c)
+		if key == "" {
+			return err
+		}
+		m.record(utils.CopyString(key), utils.CopyString(m.cfg.Tag(c)), time.Now(), Usage{
+			Calls:    1,
+			BytesIn:  requestSize(c),
+			BytesOut: responseSize(c),
+		})
+		return err
+	}
+}
+
+// record adds the usage to the pending usage of the key.
+func (m *Meter) record(key, tag string, now time.Time, usage Usage) {
+	pk := pendingKey{key: key, start: now.Truncate(m.cfg.Period).Unix()}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	tags, ok := m.pending[pk]
+	if !ok {
+		tags = make(map[string]*Usage)
+		m.pending[pk] = tags
+	}
+	u, ok := tags[tag]
+	if !ok {
+		u = &Usage{}
+		tags[tag] = u
+	}
+	u.Add(usage)
+}
+
+// requestSize returns the size of the request body, without reading a streamed body.
+func requestSize(c fiber.Ctx) int64 {
+	if c.Request().IsBodyStream() {
+		return int64(max(c.Request().Header.ContentLength(), 0))
+	}
+	return int64(len(c.Request().Body()))
+}
+
+// responseSize returns the size of the response body, without reading a streamed body.
+func responseSize(c fiber.Ctx) int64 {
+	if c.Response().IsBodyStream() {
+		return int64(max(c.Response().Header.ContentLength(), 0))
+	}
+	return int64(len(c.Response().Body()))
+}
+
+// flushLoop flushes the recorded usage in the FlushInterval until the meter is closed.
+func (m *Meter) flushLoop() {
+	ticker := time.NewTicker(m.cfg.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := m.Flush(); err != nil {
+				log.Errorw("metering: failed to flush the usage", "error", err)
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// Flush adds the recorded usage to the Storage. The usage which couldn't be
+// stored is kept for the next flush.
+func (m *Meter) Flush() error {
+	m.flushMutex.Lock()
+	defer m.flushMutex.Unlock()
+
+	m.mutex.Lock()
+	pending := m.pending
+	m.pending = make(map[pendingKey]map[string]*Usage)
+	m.mutex.Unlock()
+
+	var errs []error
+	now := time.Now()
+	for pk, tags := range pending {
+		if err := m.store(pk, tags, now); err != nil {
+			errs = append(errs, err)
+			for tag, usage := range tags {
+				m.record(pk.key, tag, time.Unix(pk.start, 0), *usage)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// store adds the usage of the tags to the usage of the period in the Storage.
+func (m *Meter) store(pk pendingKey, tags map[string]*Usage, now time.Time) error {
+	exp := time.Unix(pk.start, 0).Add(m.cfg.Period + m.cfg.Retention).Sub(now)
+	if exp <= 0 {
+		return nil
+	}
+
+	storageKey := m.storageKey(pk.key, pk.start)
+	stored, err := m.get(storageKey)
+	if err != nil {
+		return err
+	}
+	for tag, usage := range tags {
+		u := stored[tag]
+		u.Add(*usage)
+		stored[tag] = u
+	}
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("metering: failed to encode the usage: %w", err)
+	}
+	if err := m.cfg.Storage.Set(storageKey, raw, exp); err != nil {
+		return fmt.Errorf("metering: failed to store the usage of %s: %w", pk.key, err)
+	}
+	return nil
+}
+
+// get returns the stored usage of a period, per tag.
+func (m *Meter) get(storageKey string) (map[string]Usage, error) {
+	raw, err := m.cfg.Storage.Get(storageKey)
+	if err != nil {
+		return nil, fmt.Errorf("metering: failed to get the usage: %w", err)
+	}
+	stored := make(map[string]Usage)
+	if len(raw) == 0 {
+		return stored, nil
+	}
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, fmt.Errorf("metering: failed to decode the usage: %w", err)
+	}
+	return stored, nil
+}
+
+// storageKey returns the storage key of the usage of a key in a period.
+func (*Meter) storageKey(key string, start int64) string {
+	return "metering:" + url.PathEscape(key) + ":" + strconv.FormatInt(start, 10)
+}
+
+// Close stops flushing the recorded usage in the FlushInterval and flushes it a last time,
+// it should be called by an OnShutdown hook.
+func (m *Meter) Close() error {
+	m.once.Do(func() {
+		close(m.done)
+	})
+	return m.Flush()
+}
+
+// Query returns the usage of the key in the periods from the period of from until to,
+// without the periods without usage. The recorded usage is flushed first.
+func (m *Meter) Query(key string, from, to time.Time) ([]Period, error) {
+	start := from.Truncate(m.cfg.Period)
+	if to.Sub(start)/m.cfg.Period > maxQueryPeriods {
+		return nil, ErrRangeTooLarge
+	}
+	if err := m.Flush(); err != nil {
+		return nil, err
+	}
+
+	var periods []Period
+	for ; start.Before(to); start = start.Add(m.cfg.Period) {
+		stored, err := m.get(m.storageKey(key, start.Unix()))
+		if err != nil {
+			return nil, err
+		}
+		if len(stored) > 0 {
+			periods = append(periods, Period{Start: start, Tags: stored})
+		}
+	}
+	return periods, nil
+}
+
+// Totals returns the usage of the key in the periods from the period of from until to, per tag,
+// e.g. for billing.
+func (m *Meter) Totals(key string, from, to time.Time) (map[string]Usage, error) {
+	periods, err := m.Query(key, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return sum(periods), nil
+}
+
+// sum returns the usage of the periods, per tag.
+func sum(periods []Period) map[string]Usage {
+	totals := make(map[string]Usage)
+	for _, period := range periods {
+		for tag, usage := range period.Tags {
+			u := totals[tag]
+			u.Add(usage)
+			totals[tag] = u
+		}
+	}
+	return totals
+}
+
+// QueryHandler returns a handler which sends the usage of a key as JSON, e.g. for dashboards.
+// The key is the "key" query parameter, the range is given with the "from" and "to" query
+// parameters in RFC 3339 format and defaults to the last 24 hours. The handler has to be
+// protected, e.g. with the keyauth middleware, and the keys of the users be checked.
+func (m *Meter) QueryHandler() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		key := c.Query("key")
+		if key == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "metering: the key is required")
+		}
+		to, err := parseTime(c.Query("to"), time.Now())
+		if err != nil {
+			return err
+		}
+		from, err := parseTime(c.Query("from"), to.Add(-24*time.Hour))
+		if err != nil {
+			return err
+		}
+
+		periods, err := m.Query(key, from, to)
+		if errors.Is(err, ErrRangeTooLarge) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{
+			"key":     key,
+			"periods": periods,
+			"totals":  sum(periods),
+		})
+	}
+}
+
+// parseTime parses a time in RFC 3339 format, the empty string is the default.
+func parseTime(value string, def time.Time) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fiber.NewError(fiber.StatusBadRequest, "metering: invalid time "+strconv.Quote(value))
+	}
+	return t, nil
+}
@@ -0,0 +1,160 @@
+package metering
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_Metering
+func Test_Metering(t *testing.T) {
+	t.Parallel()
+	meter := New(Config{
+		Key: func(c fiber.Ctx) string {
+			return c.Get("X-API-Key")
+		},
+	})
+	t.Cleanup(func() {
+		require.NoError(t, meter.Close())
+	})
+
+	app := fiber.New()
+	app.Use(meter.Handler())
+	app.Post("/upload", func(c fiber.Ctx) error {
+		return c.SendString("ok")
+	}).Name("upload")
+	app.Get("/users/:id", func(c fiber.Ctx) error {
+		return c.SendString("user " + c.Params("id"))
+	})
+
+	request := func(method, path, key, body string) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		_, err := app.Test(req)
+		require.NoError(t, err)
+	}
+	request(fiber.MethodPost, "/upload", "acme", "0123456789")
+	request(fiber.MethodPost, "/upload", "acme", "01234")
+	request(fiber.MethodGet, "/users/1", "acme", "")
+	request(fiber.MethodGet, "/users/22", "globex", "")
+	// requests without key aren't recorded
+	request(fiber.MethodGet, "/users/3", "", "")
+
+	now := time.Now()
+	totals, err := meter.Totals("acme", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	require.Equal(t, map[string]Usage{
+		"upload":         {Calls: 2, BytesIn: 15, BytesOut: 4},
+		"GET /users/:id": {Calls: 1, BytesOut: 6},
+	}, totals)
+
+	// the recorded usage was flushed, new usage is added to the stored usage
+	request(fiber.MethodPost, "/upload", "acme", "0")
+	periods, err := meter.Query("acme", now.Add(-time.Hour), now.Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, periods, 1)
+	require.Equal(t, now.Truncate(time.Hour), periods[0].Start)
+	require.Equal(t, Usage{Calls: 3, BytesIn: 16, BytesOut: 6}, periods[0].Tags["upload"])
+
+	periods, err = meter.Query("acme", now.Add(-48*time.Hour), now.Add(-47*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, periods)
+	_, err = meter.Query("acme", now.Add(-2*366*24*time.Hour), now)
+	require.ErrorIs(t, err, ErrRangeTooLarge)
+
+	totals, err = meter.Totals("globex", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	require.Equal(t, map[string]Usage{"GET /users/:id": {Calls: 1, BytesOut: 7}}, totals)
+}
+
+// go test -run Test_Metering_QueryHandler
+func Test_Metering_QueryHandler(t *testing.T) {
+	t.Parallel()
+	meter := New()
+	t.Cleanup(func() {
+		require.NoError(t, meter.Close())
+	})
+
+	app := fiber.New()
+	app.Get("/usage", meter.QueryHandler())
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString("Hello tester!")
+	}, meter.Handler())
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/usage?key=0.0.0.0", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var result struct {
+		Totals  map[string]Usage `json:"totals"`
+		Key     string           `json:"key"`
+		Periods []Period         `json:"periods"`
+	}
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.Equal(t, "0.0.0.0", result.Key)
+	require.Len(t, result.Periods, 1)
+	require.Equal(t, map[string]Usage{"GET /": {Calls: 1, BytesOut: 13}}, result.Totals)
+
+	for _, target := range []string{"/usage", "/usage?key=a&from=yesterday", "/usage?key=a&from=2000-01-01T00:00:00Z"} {
+		resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusBadRequest, resp.StatusCode, target)
+	}
+}
+
+// failingStorage fails to set the values while failing is true.
+type failingStorage struct {
+	*memory.Storage
+	failing bool
+}
+
+func (s *failingStorage) Set(key string, val []byte, exp time.Duration) error {
+	if s.failing {
+		return errors.New("storage is down")
+	}
+	return s.Storage.Set(key, val, exp)
+}
+
+// go test -run Test_Metering_Flush
+func Test_Metering_Flush(t *testing.T) {
+	t.Parallel()
+	storage := &failingStorage{Storage: memory.New(), failing: true}
+	meter := New(Config{Storage: storage, FlushInterval: time.Hour})
+	now := time.Now()
+	meter.record("acme", "search", now, Usage{Calls: 1})
+
+	// the usage is kept until it was stored
+	require.Error(t, meter.Flush())
+	meter.record("acme", "search", now, Usage{Calls: 1})
+	storage.failing = false
+	require.NoError(t, meter.Close())
+
+	reader := New(Config{Storage: storage})
+	totals, err := reader.Totals("acme", now, now.Add(time.Second))
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, map[string]Usage{"search": {Calls: 2}}, totals)
+
+	// the usage of periods after the retention isn't stored
+	meter = New(Config{Storage: storage, Retention: time.Hour})
+	meter.record("acme", "search", now.Add(-3*time.Hour), Usage{Calls: 1})
+	require.NoError(t, meter.Close())
+	raw, err := storage.Get(meter.storageKey("acme", now.Add(-3*time.Hour).Truncate(time.Hour).Unix()))
+	require.NoError(t, err)
+	require.Nil(t, raw)
+}
@@ -0,0 +1,131 @@
+package mqttws
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Credentials are the username and the password of the CONNECT packet which is sent to the broker.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Broker is an in-process MQTT broker, which serves the bridged connections
+// instead of a broker which is dialed over TCP.
+type Broker interface {
+	// ServeConn serves the MQTT connection until it is closed.
+	ServeConn(conn net.Conn)
+}
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Address is the TCP address of the broker, e.g. "mosquitto:1883".
+	//
+	// Required, unless Broker or Dial is set.
+	Address string
+
+	// Broker is an in-process broker, which serves the bridged connections.
+	//
+	// Optional. Default: nil
+	Broker Broker
+
+	// Dial connects to the broker, e.g. with TLS.
+	//
+	// Optional. Default: a TCP dial of the Address
+	Dial func(ctx context.Context) (net.Conn, error)
+
+	// Credentials maps the request, e.g. its session or JWT, to the credentials of the CONNECT
+	// packet, which replace the credentials of the client. If it returns an error, the upgrade
+	// is rejected with 401 Unauthorized, unless the error is a *fiber.Error.
+	//
+	// Optional. Default: nil, the credentials of the client are forwarded
+	Credentials func(c fiber.Ctx) (*Credentials, error)
+
+	// AllowOrigins are the origins of the pages which are allowed to connect. The browsers send
+	// the cookies with WebSocket upgrades of other origins, so it should be set with cookie auth.
+	//
+	// Optional. Default: nil, all origins are allowed
+	AllowOrigins []string
+
+	// DialTimeout is the timeout to connect to the broker.
+	//
+	// Optional. Default: 5 * time.Second
+	DialTimeout time.Duration
+
+	// PingInterval is the interval of the WebSocket pings, which keep the connection
+	// open through proxies and load balancers.
+	//
+	// Optional. Default: 30 * time.Second
+	PingInterval time.Duration
+
+	// MaxMessageSize is the maximum size of a WebSocket message from the client.
+	//
+	// Optional. Default: 1024 * 1024
+	MaxMessageSize int
+
+	// ShutdownCloseCode is the status code of the close frame which is sent to the clients
+	// when the graceful shutdown of the app begins, see fiber.Config.DrainTimeout.
+	//
+	// Optional. Default: 1001, going away
+	ShutdownCloseCode uint16
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:              nil,
+	DialTimeout:       5 * time.Second,
+	PingInterval:      30 * time.Second,
+	MaxMessageSize:    1024 * 1024,
+	ShutdownCloseCode: closeGoingAway,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	cfg := ConfigDefault
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Set default values
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = ConfigDefault.DialTimeout
+	}
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = ConfigDefault.PingInterval
+	}
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = ConfigDefault.MaxMessageSize
+	}
+	if cfg.ShutdownCloseCode == 0 {
+		cfg.ShutdownCloseCode = ConfigDefault.ShutdownCloseCode
+	}
+	if cfg.Dial == nil {
+		switch {
+		case cfg.Broker != nil:
+			broker := cfg.Broker
+			cfg.Dial = func(_ context.Context) (net.Conn, error) {
+				client, server := net.Pipe()
+				go broker.ServeConn(server)
+				return client, nil
+			}
+		case cfg.Address != "":
+			dialer := &net.Dialer{}
+			cfg.Dial = func(ctx context.Context) (net.Conn, error) {
+				return dialer.DialContext(ctx, "tcp", cfg.Address)
+			}
+		default:
+			panic("mqttws: Address, Broker or Dial is required")
+		}
+	}
+	return cfg
+}
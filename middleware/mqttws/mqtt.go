@@ -0,0 +1,185 @@
+package mqttws
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// packetConnect is the type of the CONNECT packet, see MQTT 3.1.1, 2.2.1.
+const packetConnect = 1
+
+// The flags of the CONNECT packet, see MQTT 3.1.1, 3.1.2.3.
+const (
+	flagWill     = 0x04
+	flagPassword = 0x40
+	flagUsername = 0x80
+)
+
+// mqttVersion5 is the protocol level of MQTT 5.
+const mqttVersion5 = 5
+
+// errMalformedPacket is returned for a CONNECT packet which can't be parsed.
+var errMalformedPacket = errors.New("mqttws: malformed MQTT packet")
+
+// readPacket reads an MQTT control packet, the fixed header and the remaining bytes.
+func readPacket(r io.Reader, maxSize int) ([]byte, error) {
+	header := make([]byte, 1, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err //nolint:wrapcheck // the error of the connection is returned unchanged
+	}
+
+	// the remaining length is a variable byte integer of up to four bytes
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return nil, errMalformedPacket
+		}
+		var b [1]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err //nolint:wrapcheck // the error of the connection is returned unchanged
+		}
+		header = append(header, b[0])
+		length += int(b[0]&0x7F) * multiplier
+		multiplier *= 128
+		if b[0]&0x80 == 0 {
+			break
+		}
+	}
+	if length > maxSize {
+		return nil, errMessageTooBig
+	}
+
+	packet := make([]byte, len(header)+length)
+	copy(packet, header)
+	if _, err := io.ReadFull(r, packet[len(header):]); err != nil {
+		return nil, err //nolint:wrapcheck // the error of the connection is returned unchanged
+	}
+	return packet, nil
+}
+
+// setCredentials returns the CONNECT packet with the username and the password of the
+// credentials, which replace the credentials of the client. Empty credentials are removed.
+func setCredentials(packet []byte, creds *Credentials) ([]byte, error) {
+	if len(packet) == 0 || packet[0]>>4 != packetConnect {
+		return nil, errConnectMissing
+	}
+	// skip the remaining length
+	pos := 1
+	for pos < len(packet) && packet[pos]&0x80 != 0 {
+		pos++
+	}
+	if pos >= len(packet) {
+		return nil, errMalformedPacket
+	}
+	body := packet[pos+1:]
+
+	// the variable header: the protocol name, level, flags and keep alive
+	r := &reader{buf: body}
+	r.bytes()
+	level := r.byte()
+	flagsPos := r.pos
+	flags := r.byte()
+	r.skip(2)
+	if level == mqttVersion5 {
+		r.skip(r.varint())
+	}
+
+	// the payload: the client identifier, the will and the credentials of the client
+	r.bytes()
+	if flags&flagWill != 0 {
+		if level == mqttVersion5 {
+			r.skip(r.varint())
+		}
+		r.bytes()
+		r.bytes()
+	}
+	end := r.pos
+	if flags&flagUsername != 0 {
+		r.bytes()
+	}
+	if flags&flagPassword != 0 {
+		r.bytes()
+	}
+	if r.err || r.pos != len(body) {
+		return nil, errMalformedPacket
+	}
+
+	rest := make([]byte, 0, end+len(creds.Username)+len(creds.Password)+4)
+	rest = append(rest, body[:end]...)
+	rest[flagsPos] &^= flagUsername | flagPassword
+	if creds.Username != "" {
+		rest[flagsPos] |= flagUsername
+		rest = appendString(rest, creds.Username)
+	}
+	if creds.Password != "" {
+		rest[flagsPos] |= flagPassword
+		rest = appendString(rest, creds.Password)
+	}
+
+	result := []byte{packet[0]}
+	for length := len(rest); ; {
+		b := byte(length & 0x7F)
+		length >>= 7
+		if length == 0 {
+			result = append(result, b)
+			break
+		}
+		result = append(result, b|0x80)
+	}
+	return append(result, rest...), nil
+}
+
+// appendString appends a length-prefixed string of MQTT.
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s))) //nolint:gosec // the credentials are short
+	return append(b, s...)
+}
+
+// reader reads the fields of an MQTT packet, err is set if the packet is too short.
+type reader struct {
+	buf []byte
+	pos int
+	err bool
+}
+
+// skip skips n bytes.
+func (r *reader) skip(n int) {
+	if r.err || n < 0 || r.pos+n > len(r.buf) {
+		r.err = true
+		return
+	}
+	r.pos += n
+}
+
+// byte reads a byte.
+func (r *reader) byte() byte {
+	r.skip(1)
+	if r.err {
+		return 0
+	}
+	return r.buf[r.pos-1]
+}
+
+// bytes skips a length-prefixed string or binary data.
+func (r *reader) bytes() {
+	r.skip(2)
+	if r.err {
+		return
+	}
+	r.skip(int(binary.BigEndian.Uint16(r.buf[r.pos-2:])))
+}
+
+// varint reads a variable byte integer.
+func (r *reader) varint() int {
+	value := 0
+	for i := 0; i < 4; i++ {
+		b := r.byte()
+		value |= int(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			return value
+		}
+	}
+	r.err = true
+	return 0
+}
@@ -0,0 +1,181 @@
+package mqttws
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// New creates a new middleware handler, which upgrades the WebSocket requests and bridges
+// them to the MQTT broker. Requests which aren't WebSocket upgrades are passed to the next handler,
+// so the dashboard can be served on the same path.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if !isUpgrade(c) {
+			return c.Next()
+		}
+
+		if c.Get(fiber.HeaderSecWebSocketVersion) != "13" {
+			c.Set(fiber.HeaderSecWebSocketVersion, "13")
+			return fiber.ErrUpgradeRequired
+		}
+		key := c.Get(fiber.HeaderSecWebSocketKey)
+		if key == "" {
+			return fiber.ErrBadRequest
+		}
+		if !originAllowed(cfg.AllowOrigins, c.Get(fiber.HeaderOrigin)) {
+			return fiber.ErrForbidden
+		}
+		protocol, ok := selectSubprotocol(c.Get(fiber.HeaderSecWebSocketProtocol))
+		if !ok {
+			return fiber.NewError(fiber.StatusBadRequest, "unsupported websocket subprotocol")
+		}
+
+		var creds *Credentials
+		if cfg.Credentials != nil {
+			var err error
+			if creds, err = cfg.Credentials(c); err != nil {
+				var fiberErr *fiber.Error
+				if errors.As(err, &fiberErr) {
+					return err
+				}
+				return fiber.ErrUnauthorized
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), cfg.DialTimeout)
+		broker, err := cfg.Dial(ctx)
+		cancel()
+		if err != nil {
+			log.Errorw("mqttws: failed to connect to the broker", "error", err)
+			return fiber.ErrBadGateway
+		}
+
+		accept := acceptKey(key)
+		app := c.App()
+		c.Hijack(func(conn net.Conn) {
+			// the hijacked connections aren't closed by the shutdown of the server,
+			// they're closed with a close frame when the drain begins
+			drain, done := app.TrackStream()
+			defer done()
+			bridge(&cfg, conn, broker, handshake(accept, protocol), creds, drain)
+		})
+		return nil
+	}
+}
+
+// originAllowed returns true if the origin is allowed to connect.
+func originAllowed(allowOrigins []string, origin string) bool {
+	if len(allowOrigins) == 0 {
+		return true
+	}
+	for _, allowed := range allowOrigins {
+		if utils.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// bridge sends the handshake and copies the MQTT packets between the WebSocket connection
+// and the broker until one of them is closed or the app is drained. It returns after all of
+// its goroutines returned, because fasthttp reuses the hijacked connection afterward.
+func bridge(cfg *Config, conn, broker net.Conn, response []byte, creds *Credentials, drain <-chan struct{}) {
+	if _, err := conn.Write(response); err != nil {
+		_ = broker.Close() //nolint:errcheck // the connection is closed anyway
+		return
+	}
+	ws := newWSConn(conn, cfg)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	defer func() {
+		// close both connections to unblock the goroutines, and wait for them before
+		// the connection is released
+		close(stop)
+		ws.shutdown()
+		_ = broker.Close() //nolint:errcheck // the connection is closed anyway
+		wg.Wait()
+	}()
+
+	// the pings keep the connection open through proxies and detect dead clients
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(cfg.PingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := ws.ping(); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		defer wg.Done()
+		if err := forward(cfg, broker, ws, creds); err != nil && !errors.Is(err, io.EOF) {
+			log.Debugw("mqttws: the client connection failed", "error", err)
+		}
+		done <- struct{}{}
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(ws, broker) //nolint:errcheck // the broker closed the connection
+		ws.close(closeNormal)
+		done <- struct{}{}
+	}()
+	select {
+	case <-done:
+	case <-drain:
+		ws.close(cfg.ShutdownCloseCode)
+	}
+}
+
+// forward copies the MQTT packets of the client to the broker. The credentials of the
+// CONNECT packet are replaced if the Credentials of the config returned them.
+func forward(cfg *Config, broker io.Writer, ws *wsConn, creds *Credentials) error {
+	packet, err := readPacket(ws, cfg.MaxMessageSize)
+	switch {
+	case errors.Is(err, errMessageTooBig):
+		return ws.fail(closeMessageTooBig, err)
+	case errors.Is(err, errMalformedPacket):
+		return ws.fail(closeProtocolError, err)
+	case err != nil:
+		return err
+	}
+	if packet[0]>>4 != packetConnect {
+		return ws.fail(closeProtocolError, errConnectMissing)
+	}
+	if creds != nil {
+		if packet, err = setCredentials(packet, creds); err != nil {
+			return ws.fail(closeProtocolError, err)
+		}
+	}
+	if _, err := broker.Write(packet); err != nil {
+		return err //nolint:wrapcheck // the error of the connection is returned unchanged
+	}
+	_, err = io.Copy(broker, ws)
+	return err //nolint:wrapcheck // the error of the connection is returned unchanged
+}
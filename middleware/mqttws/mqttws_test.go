@@ -0,0 +1,311 @@
+package mqttws
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// connectPacket returns an MQTT 3.1.1 CONNECT packet with the credentials.
+func connectPacket(username, password string) []byte {
+	body := appendString(nil, "MQTT")
+	flags := byte(0x02)
+	if username != "" {
+		flags |= flagUsername
+	}
+	if password != "" {
+		flags |= flagPassword
+	}
+	body = append(body, 4, flags, 0, 60)
+	body = appendString(body, "dashboard")
+	if username != "" {
+		body = appendString(body, username)
+	}
+	if password != "" {
+		body = appendString(body, password)
+	}
+	return append([]byte{packetConnect << 4, byte(len(body))}, body...)
+}
+
+// echoBroker answers the CONNECT packet with a CONNACK packet and echoes the other packets.
+type echoBroker struct {
+	connects chan []byte
+}
+
+func (b *echoBroker) ServeConn(conn net.Conn) {
+	defer conn.Close() //nolint:errcheck // It's a test
+	packet, err := readPacket(conn, 1024)
+	if err != nil {
+		return
+	}
+	b.connects <- packet
+	if _, err := conn.Write([]byte{0x20, 0x02, 0x00, 0x00}); err != nil {
+		return
+	}
+	_, _ = io.Copy(conn, conn) //nolint:errcheck // It's a test
+}
+
+// wsClient is the client side of a WebSocket connection.
+type wsClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dial connects to the app and sends the upgrade request.
+func dial(t *testing.T, app *fiber.App) (*wsClient, *http.Response) {
+	t.Helper()
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln, fiber.ListenConfig{DisableStartupMessage: true}))
+	}()
+
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		var err error
+		conn, err = ln.Dial()
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, err := conn.Write([]byte("GET /mqtt HTTP/1.1\r\nHost: example.com\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Protocol: mqtt\r\n" +
+		"Authorization: Bearer alice\r\n\r\n"))
+	require.NoError(t, err)
+
+	client := &wsClient{conn: conn, reader: bufio.NewReader(conn)}
+	resp, err := http.ReadResponse(client.reader, nil)
+	require.NoError(t, err)
+	return client, resp
+}
+
+// write sends a masked frame.
+func (ws *wsClient) write(t *testing.T, header byte, payload []byte) {
+	t.Helper()
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{header, 0x80 | byte(len(payload))}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	_, err := ws.conn.Write(frame)
+	require.NoError(t, err)
+}
+
+// read receives an unmasked frame.
+func (ws *wsClient) read(t *testing.T) (byte, []byte) {
+	t.Helper()
+	require.NoError(t, ws.conn.SetReadDeadline(time.Now().Add(time.Second)))
+	header := make([]byte, 2)
+	_, err := io.ReadFull(ws.reader, header)
+	require.NoError(t, err)
+	payload := make([]byte, header[1]&0x7F)
+	_, err = io.ReadFull(ws.reader, payload)
+	require.NoError(t, err)
+	return header[0], payload
+}
+
+// go test -run Test_MQTTWS
+func Test_MQTTWS(t *testing.T) {
+	t.Parallel()
+	broker := &echoBroker{connects: make(chan []byte, 1)}
+	app := fiber.New()
+	app.Use("/mqtt", New(Config{
+		Broker: broker,
+		Credentials: func(c fiber.Ctx) (*Credentials, error) {
+			return &Credentials{Username: c.Get(fiber.HeaderAuthorization)[len("Bearer "):], Password: "secret"}, nil
+		},
+	}))
+	app.Get("/mqtt", func(c fiber.Ctx) error {
+		return c.SendString("dashboard")
+	})
+	defer app.Shutdown() //nolint:errcheck // It's a test
+
+	client, resp := dial(t, app)
+	require.Equal(t, fiber.StatusSwitchingProtocols, resp.StatusCode)
+	require.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get(fiber.HeaderSecWebSocketAccept))
+	require.Equal(t, "mqtt", resp.Header.Get(fiber.HeaderSecWebSocketProtocol))
+
+	// the CONNECT packet is fragmented, the credentials are replaced
+	connect := connectPacket("mallory", "guess")
+	client.write(t, opBinary, connect[:5])
+	client.write(t, 0x80|opContinuation, connect[5:])
+	require.Equal(t, connectPacket("alice", "secret"), <-broker.connects)
+
+	header, payload := client.read(t)
+	require.Equal(t, byte(0x80|opBinary), header)
+	require.Equal(t, []byte{0x20, 0x02, 0x00, 0x00}, payload)
+
+	// pings are answered by the bridge, the MQTT keep-alives are forwarded to the broker
+	client.write(t, 0x80|opPing, []byte("ping"))
+	header, payload = client.read(t)
+	require.Equal(t, byte(0x80|opPong), header)
+	require.Equal(t, []byte("ping"), payload)
+
+	client.write(t, 0x80|opBinary, []byte{0xC0, 0x00})
+	_, payload = client.read(t)
+	require.Equal(t, []byte{0xC0, 0x00}, payload)
+
+	// text messages aren't allowed
+	client.write(t, 0x80|opText, []byte("hello"))
+	header, payload = client.read(t)
+	require.Equal(t, byte(0x80|opClose), header)
+	require.Equal(t, uint16(closeUnsupportedData), binary.BigEndian.Uint16(payload))
+
+	// requests which aren't upgrades are passed to the next handler
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/mqtt", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "dashboard", string(body))
+}
+
+// go test -run Test_MQTTWS_Address
+func Test_MQTTWS_Address(t *testing.T) {
+	t.Parallel()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close() //nolint:errcheck // It's a test
+	broker := &echoBroker{connects: make(chan []byte, 1)}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		broker.ServeConn(conn)
+	}()
+
+	app := fiber.New()
+	app.Use("/mqtt", New(Config{Address: ln.Addr().String(), PingInterval: 100 * time.Millisecond}))
+	defer app.Shutdown() //nolint:errcheck // It's a test
+
+	client, resp := dial(t, app)
+	require.Equal(t, fiber.StatusSwitchingProtocols, resp.StatusCode)
+
+	// the credentials of the client are forwarded
+	connect := connectPacket("bob", "")
+	client.write(t, 0x80|opBinary, connect)
+	require.Equal(t, connect, <-broker.connects)
+
+	// the bridge sends pings, the client must answer them
+	var connack, ping bool
+	for !connack || !ping {
+		header, payload := client.read(t)
+		switch header {
+		case 0x80 | opPing:
+			ping = true
+			client.write(t, 0x80|opPong, payload)
+		case 0x80 | opBinary:
+			connack = true
+			require.Equal(t, []byte{0x20, 0x02, 0x00, 0x00}, payload)
+		}
+	}
+
+	// the close frame is answered
+	client.write(t, 0x80|opClose, binary.BigEndian.AppendUint16(nil, closeNormal))
+	header, payload := client.read(t)
+	for header == 0x80|opPing {
+		header, payload = client.read(t)
+	}
+	require.Equal(t, byte(0x80|opClose), header)
+	require.Equal(t, uint16(closeNormal), binary.BigEndian.Uint16(payload))
+}
+
+// go test -run Test_MQTTWS_Reject
+func Test_MQTTWS_Reject(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Address:      "127.0.0.1:1",
+		AllowOrigins: []string{"https://dashboard.example.com"},
+		Credentials: func(c fiber.Ctx) (*Credentials, error) {
+			if c.Get(fiber.HeaderAuthorization) == "" {
+				return nil, errors.New("no session")
+			}
+			return nil, nil
+		},
+	}))
+
+	upgrade := func(headers map[string]string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderConnection, "Upgrade")
+		req.Header.Set(fiber.HeaderUpgrade, "websocket")
+		req.Header.Set(fiber.HeaderSecWebSocketVersion, "13")
+		req.Header.Set(fiber.HeaderSecWebSocketKey, "dGhlIHNhbXBsZSBub25jZQ==")
+		req.Header.Set(fiber.HeaderOrigin, "https://dashboard.example.com")
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer alice")
+		for header, value := range headers {
+			req.Header.Set(header, value)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	require.Equal(t, fiber.StatusUpgradeRequired, upgrade(map[string]string{fiber.HeaderSecWebSocketVersion: "8"}))
+	require.Equal(t, fiber.StatusForbidden, upgrade(map[string]string{fiber.HeaderOrigin: "https://evil.example.com"}))
+	require.Equal(t, fiber.StatusBadRequest, upgrade(map[string]string{fiber.HeaderSecWebSocketProtocol: "chat"}))
+	require.Equal(t, fiber.StatusUnauthorized, upgrade(map[string]string{fiber.HeaderAuthorization: ""}))
+	require.Equal(t, fiber.StatusBadGateway, upgrade(nil))
+}
+
+// go test -run Test_SetCredentials
+func Test_SetCredentials(t *testing.T) {
+	t.Parallel()
+
+	// MQTT 5 with properties and a will
+	body := appendString(nil, "MQTT")
+	body = append(body, mqttVersion5, flagWill|flagPassword, 0, 30, 2, 0x11, 0x00)
+	body = appendString(body, "sensor")
+	body = append(body, 0)
+	body = appendString(body, "status")
+	body = appendString(body, "offline")
+	body = appendString(body, "token")
+	packet := append([]byte{packetConnect << 4, byte(len(body))}, body...)
+
+	result, err := setCredentials(packet, &Credentials{Username: "device"})
+	require.NoError(t, err)
+	want := append([]byte{}, body[:len(body)-7]...)
+	want[7] = flagWill | flagUsername
+	want = appendString(want, "device")
+	require.Equal(t, append([]byte{packetConnect << 4, byte(len(want))}, want...), result)
+
+	_, err = setCredentials(packet[:len(packet)-1], &Credentials{})
+	require.ErrorIs(t, err, errMalformedPacket)
+	_, err = setCredentials([]byte{0xC0, 0x00}, &Credentials{})
+	require.ErrorIs(t, err, errConnectMissing)
+}
+
+// go test -run Test_MQTTWS_Shutdown
+func Test_MQTTWS_Shutdown(t *testing.T) {
+	t.Parallel()
+	broker := &echoBroker{connects: make(chan []byte, 1)}
+	app := fiber.New()
+	app.Use("/mqtt", New(Config{Broker: broker}))
+
+	client, resp := dial(t, app)
+	require.Equal(t, fiber.StatusSwitchingProtocols, resp.StatusCode)
+	client.write(t, 0x80|opBinary, connectPacket("bob", ""))
+	<-broker.connects
+	_, payload := client.read(t)
+	require.Equal(t, []byte{0x20, 0x02, 0x00, 0x00}, payload)
+
+	// the clients receive a close frame when the drain begins
+	shutdown := make(chan error, 1)
+	go func() {
+		shutdown <- app.Shutdown()
+	}()
+	header, payload := client.read(t)
+	require.Equal(t, byte(0x80|opClose), header)
+	require.Equal(t, uint16(closeGoingAway), binary.BigEndian.Uint16(payload))
+	require.NoError(t, <-shutdown)
+}
@@ -0,0 +1,300 @@
+package mqttws
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // SHA-1 is required by the WebSocket handshake
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// websocketGUID is appended to the key of the handshake, see RFC 6455, 1.3.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// The opcodes of the WebSocket frames, see RFC 6455, 5.2.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// The status codes of the close frames, see RFC 6455, 7.4.1.
+const (
+	closeNormal          = 1000
+	closeGoingAway       = 1001
+	closeProtocolError   = 1002
+	closeUnsupportedData = 1003
+	closeMessageTooBig   = 1009
+)
+
+// maxControlPayload is the maximum payload size of the control frames.
+const maxControlPayload = 125
+
+// The errors of the WebSocket connections, the close frame is sent to the client before they're returned.
+var (
+	errProtocol       = errors.New("mqttws: websocket protocol error")
+	errTextMessage    = errors.New("mqttws: MQTT must be sent in binary messages")
+	errMessageTooBig  = errors.New("mqttws: websocket message too big")
+	errConnectMissing = errors.New("mqttws: the first MQTT packet must be CONNECT")
+)
+
+// subprotocols are the WebSocket subprotocols of MQTT, "mqtt" for MQTT 3.1.1 and 5 and
+// "mqttv3.1" for MQTT 3.1.
+var subprotocols = []string{"mqtt", "mqttv3.1"}
+
+// isUpgrade returns true if the request is a WebSocket upgrade.
+func isUpgrade(c fiber.Ctx) bool {
+	return c.Method() == fiber.MethodGet &&
+		c.Context().Request.Header.ConnectionUpgrade() &&
+		utils.EqualFold(c.Get(fiber.HeaderUpgrade), "websocket")
+}
+
+// selectSubprotocol returns the first MQTT subprotocol which is offered by the client,
+// or false if the client offers only other subprotocols.
+func selectSubprotocol(offered string) (string, bool) {
+	if offered == "" {
+		return "", true
+	}
+	for _, protocol := range strings.Split(offered, ",") {
+		protocol = strings.TrimSpace(protocol)
+		for _, supported := range subprotocols {
+			if protocol == supported {
+				return supported, true
+			}
+		}
+	}
+	return "", false
+}
+
+// acceptKey returns the Sec-WebSocket-Accept header of the key, see RFC 6455, 4.2.2.
+func acceptKey(key string) string {
+	h := sha1.New() //nolint:gosec // SHA-1 is required by the WebSocket handshake
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// handshake returns the "101 Switching Protocols" response of the upgrade.
+func handshake(accept, protocol string) []byte {
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		fiber.HeaderSecWebSocketAccept + ": " + accept + "\r\n"
+	if protocol != "" {
+		response += fiber.HeaderSecWebSocketProtocol + ": " + protocol + "\r\n"
+	}
+	return []byte(response + "\r\n")
+}
+
+// wsConn is the server side of a WebSocket connection, which reads the payloads of the
+// binary messages as a stream and writes a binary message for each write.
+type wsConn struct {
+	conn           net.Conn
+	reader         *bufio.Reader
+	idleTimeout    time.Duration
+	maxMessageSize int
+
+	writeMutex sync.Mutex
+	closeOnce  sync.Once
+
+	// the state of the current data frame
+	remaining   uint64
+	messageSize uint64
+	mask        [4]byte
+	maskPos     int
+	fragmented  bool
+}
+
+// newWSConn returns the WebSocket connection of the hijacked connection.
+func newWSConn(conn net.Conn, cfg *Config) *wsConn {
+	return &wsConn{
+		conn:           conn,
+		reader:         bufio.NewReader(conn),
+		idleTimeout:    2 * cfg.PingInterval,
+		maxMessageSize: cfg.MaxMessageSize,
+	}
+}
+
+// Read reads the payload of the binary messages. Pings are answered, a close frame is
+// answered and ends the stream with io.EOF.
+func (ws *wsConn) Read(p []byte) (int, error) {
+	for ws.remaining == 0 {
+		if err := ws.nextFrame(); err != nil {
+			return 0, err
+		}
+	}
+
+	if uint64(len(p)) > ws.remaining {
+		p = p[:ws.remaining]
+	}
+	n, err := ws.reader.Read(p)
+	for i := 0; i < n; i++ {
+		p[i] ^= ws.mask[ws.maskPos&3]
+		ws.maskPos++
+	}
+	ws.remaining -= uint64(n) //nolint:gosec // n is never negative
+	return n, err             //nolint:wrapcheck // the error of the connection is returned unchanged
+}
+
+// nextFrame reads the header of the next frame. Control frames are handled, the payload of
+// the data frames is read by Read.
+func (ws *wsConn) nextFrame() error {
+	// the client answers the pings, so it's dead if nothing is received for two intervals
+	if err := ws.conn.SetReadDeadline(time.Now().Add(ws.idleTimeout)); err != nil {
+		return err //nolint:wrapcheck // the error of the connection is returned unchanged
+	}
+
+	var header [2]byte
+	if _, err := io.ReadFull(ws.reader, header[:]); err != nil {
+		return err //nolint:wrapcheck // the error of the connection is returned unchanged
+	}
+	fin, opcode := header[0]&0x80 != 0, header[0]&0x0F
+	masked, length := header[1]&0x80 != 0, uint64(header[1]&0x7F)
+
+	// no extensions are negotiated and the frames of the clients must be masked
+	if header[0]&0x70 != 0 || !masked {
+		return ws.fail(closeProtocolError, errProtocol)
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return err //nolint:wrapcheck // the error of the connection is returned unchanged
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return err //nolint:wrapcheck // the error of the connection is returned unchanged
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if _, err := io.ReadFull(ws.reader, ws.mask[:]); err != nil {
+		return err //nolint:wrapcheck // the error of the connection is returned unchanged
+	}
+	ws.maskPos = 0
+
+	if opcode >= opClose {
+		return ws.controlFrame(opcode, fin, length)
+	}
+
+	switch {
+	case opcode == opText:
+		return ws.fail(closeUnsupportedData, errTextMessage)
+	case opcode == opBinary && !ws.fragmented:
+		ws.messageSize = 0
+	case opcode != opContinuation || !ws.fragmented:
+		return ws.fail(closeProtocolError, errProtocol)
+	}
+	ws.fragmented = !fin
+	ws.messageSize += length
+	if ws.messageSize > uint64(ws.maxMessageSize) { //nolint:gosec // the size is never negative
+		return ws.fail(closeMessageTooBig, errMessageTooBig)
+	}
+	ws.remaining = length
+	return nil
+}
+
+// controlFrame handles a ping, pong or close frame.
+func (ws *wsConn) controlFrame(opcode byte, fin bool, length uint64) error {
+	if !fin || length > maxControlPayload {
+		return ws.fail(closeProtocolError, errProtocol)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return err //nolint:wrapcheck // the error of the connection is returned unchanged
+	}
+	for i := range payload {
+		payload[i] ^= ws.mask[i&3]
+	}
+
+	switch opcode {
+	case opPing:
+		return ws.writeFrame(opPong, payload)
+	case opPong:
+		return nil
+	case opClose:
+		code := uint16(closeNormal)
+		if len(payload) >= 2 {
+			code = binary.BigEndian.Uint16(payload)
+		}
+		ws.close(code)
+		return io.EOF
+	default:
+		return ws.fail(closeProtocolError, errProtocol)
+	}
+}
+
+// Write sends the bytes as a binary message.
+func (ws *wsConn) Write(p []byte) (int, error) {
+	if err := ws.writeFrame(opBinary, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ping sends a ping frame.
+func (ws *wsConn) ping() error {
+	return ws.writeFrame(opPing, nil)
+}
+
+// writeFrame sends an unmasked frame, the writes of the frames are serialized.
+func (ws *wsConn) writeFrame(opcode byte, payload []byte) error {
+	length := len(payload)
+	frame := make([]byte, 0, length+10)
+	frame = append(frame, 0x80|opcode)
+	switch {
+	case length <= 125:
+		frame = append(frame, byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(length))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(length))
+	}
+	frame = append(frame, payload...)
+
+	ws.writeMutex.Lock()
+	defer ws.writeMutex.Unlock()
+	if err := ws.conn.SetWriteDeadline(time.Now().Add(ws.idleTimeout)); err != nil {
+		return err //nolint:wrapcheck // the error of the connection is returned unchanged
+	}
+	_, err := ws.conn.Write(frame)
+	return err //nolint:wrapcheck // the error of the connection is returned unchanged
+}
+
+// close sends a close frame with the status code once, the connection is closed afterward.
+func (ws *wsConn) close(code uint16) {
+	ws.closeOnce.Do(func() {
+		_ = ws.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, code)) //nolint:errcheck // the connection is closed anyway
+	})
+}
+
+// shutdown closes the connection to unblock the pending reads and writes. The hijacked
+// connection of fasthttp ignores Close until the hijack handler returned, so the
+// underlying connection is closed, fasthttp closes it again afterward.
+func (ws *wsConn) shutdown() {
+	conn := ws.conn
+	if hijacked, ok := conn.(interface{ UnsafeConn() net.Conn }); ok {
+		conn = hijacked.UnsafeConn()
+	}
+	_ = conn.Close() //nolint:errcheck // the connection is closed anyway
+}
+
+// fail sends a close frame with the status code and returns the error.
+func (ws *wsConn) fail(code uint16, err error) error {
+	ws.close(code)
+	return err
+}
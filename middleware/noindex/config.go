@@ -0,0 +1,82 @@
+package noindex
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Profiles are the profiles of the app in which the middleware is active,
+	// see fiber.Config.Profile.
+	//
+	// Optional. Default: []fiber.Profile{fiber.ProfileDevelopment, fiber.ProfileStaging}
+	Profiles []fiber.Profile
+
+	// RobotsTag is the value of the X-Robots-Tag header.
+	//
+	// Optional. Default: "noindex, nofollow"
+	RobotsTag string
+
+	// Crawlers are the case-insensitive substrings of the User-Agent headers of
+	// the crawlers which are blocked with 403 Forbidden.
+	//
+	// Optional. Default: DefaultCrawlers
+	Crawlers []string
+
+	// AllowCrawlers disables the blocking of the crawlers, they only get the
+	// X-Robots-Tag header and the robots.txt.
+	//
+	// Optional. Default: false
+	AllowCrawlers bool
+
+	// DisableRobotsTxt disables the "/robots.txt" which disallows all paths.
+	//
+	// Optional. Default: false
+	DisableRobotsTxt bool
+}
+
+// DefaultCrawlers are the User-Agent substrings of well-known search engines,
+// archives and AI crawlers.
+var DefaultCrawlers = []string{
+	"googlebot", "adsbot-google", "mediapartners-google", "bingbot", "msnbot", "slurp",
+	"duckduckbot", "baiduspider", "yandex", "sogou", "exabot", "applebot", "petalbot",
+	"seznambot", "facebookexternalhit", "facebot", "twitterbot", "linkedinbot", "ia_archiver",
+	"archive.org_bot", "semrushbot", "ahrefsbot", "mj12bot", "dotbot", "gptbot", "ccbot",
+	"bytespider", "amazonbot", "perplexitybot",
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:      nil,
+	Profiles:  []fiber.Profile{fiber.ProfileDevelopment, fiber.ProfileStaging},
+	RobotsTag: "noindex, nofollow",
+	Crawlers:  DefaultCrawlers,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Profiles == nil {
+		cfg.Profiles = ConfigDefault.Profiles
+	}
+	if cfg.RobotsTag == "" {
+		cfg.RobotsTag = ConfigDefault.RobotsTag
+	}
+	if cfg.Crawlers == nil {
+		cfg.Crawlers = ConfigDefault.Crawlers
+	}
+	return cfg
+}
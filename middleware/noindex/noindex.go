@@ -0,0 +1,63 @@
+package noindex
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// robotsTxt disallows all paths for all crawlers.
+const robotsTxt = "User-agent: *\nDisallow: /\n"
+
+// New creates a new middleware handler, which keeps the app out of search engines
+// in the non-production profiles, e.g. of a staging environment.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	crawlers := make([]string, len(cfg.Crawlers))
+	for i, crawler := range cfg.Crawlers {
+		crawlers[i] = utils.ToLower(crawler)
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		if !slices.Contains(cfg.Profiles, c.App().Profile()) {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderXRobotsTag, cfg.RobotsTag)
+
+		if !cfg.DisableRobotsTxt && c.Path() == "/robots.txt" {
+			c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+			return c.SendString(robotsTxt)
+		}
+
+		if !cfg.AllowCrawlers && isCrawler(c.Get(fiber.HeaderUserAgent), crawlers) {
+			return fiber.ErrForbidden
+		}
+
+		return c.Next()
+	}
+}
+
+// isCrawler reports whether the user agent contains one of the lower-cased crawler names.
+func isCrawler(userAgent string, crawlers []string) bool {
+	if userAgent == "" {
+		return false
+	}
+	userAgent = utils.ToLower(userAgent)
+	for _, crawler := range crawlers {
+		if strings.Contains(userAgent, crawler) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package noindex
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// testApp returns an app with the profile and the middleware.
+func testApp(profile fiber.Profile, config ...Config) *fiber.App {
+	app := fiber.New(fiber.Config{Profile: profile})
+	app.Use(New(config...))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	return app
+}
+
+// go test -run Test_NoIndex
+func Test_NoIndex(t *testing.T) {
+	t.Parallel()
+	app := testApp(fiber.ProfileStaging)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "noindex, nofollow", resp.Header.Get(fiber.HeaderXRobotsTag))
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderUserAgent, "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	require.Equal(t, "noindex, nofollow", resp.Header.Get(fiber.HeaderXRobotsTag))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/robots.txt", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "User-agent: *\nDisallow: /\n", string(body))
+}
+
+// go test -run Test_NoIndex_Production
+func Test_NoIndex_Production(t *testing.T) {
+	t.Parallel()
+	app := testApp(fiber.ProfileProduction)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderUserAgent, "Googlebot/2.1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Empty(t, resp.Header.Get(fiber.HeaderXRobotsTag))
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/robots.txt", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// go test -run Test_NoIndex_Config
+func Test_NoIndex_Config(t *testing.T) {
+	t.Parallel()
+	app := testApp(fiber.ProfileProduction, Config{
+		Profiles:         []fiber.Profile{fiber.ProfileProduction},
+		RobotsTag:        "none",
+		Crawlers:         []string{"CustomBot"},
+		DisableRobotsTxt: true,
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderUserAgent, "Googlebot/2.1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "none", resp.Header.Get(fiber.HeaderXRobotsTag))
+
+	req.Header.Set(fiber.HeaderUserAgent, "custombot/1.0")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/robots.txt", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	app = testApp(fiber.ProfileDevelopment, Config{AllowCrawlers: true})
+	req.Header.Set(fiber.HeaderUserAgent, "Googlebot/2.1")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "noindex, nofollow", resp.Header.Get(fiber.HeaderXRobotsTag))
+}
+
+// go test -run Test_NoIndex_Next
+func Test_NoIndex_Next(t *testing.T) {
+	t.Parallel()
+	app := testApp(fiber.ProfileStaging, Config{
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Empty(t, resp.Header.Get(fiber.HeaderXRobotsTag))
+}
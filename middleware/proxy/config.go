@@ -62,6 +62,14 @@ type Config struct {
 	//
 	// Optional. Default: false
 	DialDualStack bool
+
+	// Forwarded appends an element for the client to the Forwarded header (RFC 7239) of the request,
+	// with the remote address, the host and the scheme of the request received by the proxy.
+	// The Forwarded header of the request is only kept if the client is a trusted proxy,
+	// see fiber.Config.EnableTrustedProxyCheck.
+	//
+	// Optional. Default: false
+	Forwarded bool
 }
 
 // ConfigDefault is the default config
@@ -70,6 +70,11 @@ func Balancer(config Config) fiber.Handler {
 		// Don't proxy "Connection" header
 		req.Header.Del(fiber.HeaderConnection)
 
+		// Append the client to the "Forwarded" header
+		if cfg.Forwarded {
+			setForwarded(c)
+		}
+
 		// Modify request
 		if cfg.ModifyRequest != nil {
 			if err := cfg.ModifyRequest(c); err != nil {
@@ -99,6 +104,23 @@ func Balancer(config Config) fiber.Handler {
 	}
 }
 
+// setForwarded replaces the Forwarded headers of the request with the trusted elements
+// and an element for the client of the request.
+func setForwarded(c fiber.Ctx) {
+	proto := "http"
+	if c.Context().IsTLS() {
+		proto = "https"
+	}
+	elements := append(c.Forwarded(), fiber.ForwardedElement{
+		For:   c.Context().RemoteIP().String(),
+		Host:  string(c.Request().Host()),
+		Proto: proto,
+	})
+	forwarded := fiber.FormatForwarded(elements...)
+	c.Request().Header.Del(fiber.HeaderForwarded)
+	c.Request().Header.Set(fiber.HeaderForwarded, forwarded)
+}
+
 var client = &fasthttp.Client{
 	NoDefaultUserAgentHeader: true,
 	DisablePathNormalizing:   true,
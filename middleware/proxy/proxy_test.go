@@ -384,6 +384,37 @@ func Test_Proxy_Modify_Request(t *testing.T) {
 	require.Equal(t, "modified request", string(b))
 }
 
+// go test -run Test_Proxy_Balancer_Forwarded
+func Test_Proxy_Balancer_Forwarded(t *testing.T) {
+	t.Parallel()
+
+	_, addr := createProxyTestServerIPv4(t, func(c fiber.Ctx) error {
+		return c.SendString(c.Get(fiber.HeaderForwarded))
+	})
+
+	for _, tt := range []struct {
+		config   fiber.Config
+		expected string
+	}{
+		{config: fiber.Config{}, expected: "for=192.0.2.60, for=0.0.0.0;host=example.com;proto=http"},
+		// the header of an untrusted client is dropped
+		{config: fiber.Config{EnableTrustedProxyCheck: true}, expected: "for=0.0.0.0;host=example.com;proto=http"},
+	} {
+		app := fiber.New(tt.config)
+		app.Use(Balancer(Config{Servers: []string{addr}, Forwarded: true}))
+
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(fiber.HeaderForwarded, "for=192.0.2.60")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, string(b))
+	}
+}
+
 // go test -run Test_Proxy_Timeout_Slow_Server
 func Test_Proxy_Timeout_Slow_Server(t *testing.T) {
 	t.Parallel()
@@ -588,6 +619,7 @@ func Test_Proxy_DoTimeout_Timeout(t *testing.T) {
 	require.Equal(t, "timeout", string(body))
 	require.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 	require.Equal(t, "/test", resp.Request.URL.String())
+	require.Equal(t, uint64(1), app.ErrorStats().Categories[fiber.ErrorCategoryUpstream])
 }
 
 // go test -race -run Test_Proxy_DoDeadline_RestoreOriginalURL
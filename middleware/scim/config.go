@@ -0,0 +1,56 @@
+package scim
+
+import (
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Store stores the users and groups. It is required.
+	//
+	// Required. Default: nil
+	Store Store
+
+	// Prefix is the path prefix of the endpoints, e.g. "/scim/v2/Users".
+	//
+	// Optional. Default: "/scim/v2"
+	Prefix string
+
+	// MaxResults is the maximum number of resources of a list response.
+	//
+	// Optional. Default: 100
+	MaxResults int
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:       nil,
+	Store:      nil,
+	Prefix:     "/scim/v2",
+	MaxResults: 100,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.Prefix == "" {
+		cfg.Prefix = ConfigDefault.Prefix
+	}
+	if cfg.MaxResults <= 0 {
+		cfg.MaxResults = ConfigDefault.MaxResults
+	}
+	return cfg
+}
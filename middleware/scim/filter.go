@@ -0,0 +1,405 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The operators of the filters
+const (
+	FilterAnd       = "and"
+	FilterOr        = "or"
+	FilterNot       = "not"
+	FilterPresent   = "pr"
+	FilterValuePath = "[]"
+)
+
+// comparisonOperators are the operators which compare an attribute with a value.
+var comparisonOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+// Filter is a parsed filter of RFC 7644, e.g. `userName eq "bjensen" and emails[type eq "work"]`.
+// Stores can translate it to their queries or evaluate it with Match.
+type Filter struct {
+	// Value is the compared value: a string, a float64, a bool or nil.
+	Value any
+	// Operator is FilterAnd, FilterOr, FilterNot, FilterPresent, FilterValuePath
+	// or a comparison operator: "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt" or "le".
+	Operator string
+	// Attribute is the attribute path of the comparisons, FilterPresent and FilterValuePath,
+	// e.g. "name.familyName" or "emails".
+	Attribute string
+	// Filters are the operands of FilterAnd and FilterOr, the operand of FilterNot
+	// and the filter of the values of FilterValuePath.
+	Filters []*Filter
+}
+
+// ParseFilter parses a filter of RFC 7644.
+func ParseFilter(filter string) (*Filter, error) {
+	tokens, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return f, nil
+}
+
+// filterToken is a token of a filter, strings are decoded.
+type filterToken struct {
+	value  any
+	text   string
+	quoted bool
+}
+
+// tokenizeFilter splits the filter into parentheses, brackets, strings and words.
+func tokenizeFilter(filter string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(filter); {
+		switch ch := filter[i]; {
+		case ch == ' ' || ch == '\t':
+			i++
+		case ch == '(' || ch == ')' || ch == '[' || ch == ']':
+			tokens = append(tokens, filterToken{text: filter[i : i+1]})
+			i++
+		case ch == '"':
+			end := i + 1
+			for end < len(filter) && filter[end] != '"' {
+				if filter[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(filter) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			var s string
+			if err := json.Unmarshal([]byte(filter[i:end+1]), &s); err != nil {
+				return nil, fmt.Errorf("invalid string at %d: %w", i, err)
+			}
+			tokens = append(tokens, filterToken{text: filter[i : end+1], value: s, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(filter) && !strings.ContainsRune(" \t()[]\"", rune(filter[end])) {
+				end++
+			}
+			tokens = append(tokens, filterToken{text: filter[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// filterParser is a recursive descent parser of the tokens of a filter.
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+// peek returns the lower-cased text of the next unquoted token, or "".
+func (p *filterParser) peek() string {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted {
+		return ""
+	}
+	return strings.ToLower(p.tokens[p.pos].text)
+}
+
+// expect consumes the next token, which must be the text.
+func (p *filterParser) expect(text string) error {
+	if p.peek() != text {
+		if p.pos >= len(p.tokens) {
+			return fmt.Errorf("expected %q at the end", text)
+		}
+		return fmt.Errorf("expected %q instead of %q", text, p.tokens[p.pos].text)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *filterParser) parseOr() (*Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == FilterOr {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Filter{Operator: FilterOr, Filters: []*Filter{left, right}}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (*Filter, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == FilterAnd {
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &Filter{Operator: FilterAnd, Filters: []*Filter{left, right}}
+	}
+	return left, nil
+}
+
+// parseTerm parses a negation, a group, a value path or an attribute expression.
+func (p *filterParser) parseTerm() (*Filter, error) {
+	switch p.peek() {
+	case FilterNot:
+		p.pos++
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return &Filter{Operator: FilterNot, Filters: []*Filter{f}}, p.expect(")")
+	case "(":
+		p.pos++
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return f, p.expect(")")
+	case "", ")", "[", "]":
+		if p.pos >= len(p.tokens) {
+			return nil, fmt.Errorf("unexpected end of filter")
+		}
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+
+	attribute := p.tokens[p.pos].text
+	p.pos++
+
+	if p.peek() == "[" {
+		p.pos++
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return &Filter{Operator: FilterValuePath, Attribute: attribute, Filters: []*Filter{f}}, p.expect("]")
+	}
+
+	operator := p.peek()
+	if operator == FilterPresent {
+		p.pos++
+		return &Filter{Operator: FilterPresent, Attribute: attribute}, nil
+	}
+	if !comparisonOperators[operator] {
+		if p.pos >= len(p.tokens) {
+			return nil, fmt.Errorf("missing operator after %q", attribute)
+		}
+		return nil, fmt.Errorf("unknown operator %q", p.tokens[p.pos].text)
+	}
+	p.pos++
+
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("missing value after %q", operator)
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	value := token.value
+	if !token.quoted {
+		switch strings.ToLower(token.text) {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			n, err := strconv.ParseFloat(token.text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", token.text)
+			}
+			value = n
+		}
+	}
+	return &Filter{Operator: operator, Attribute: attribute, Value: value}, nil
+}
+
+// Match reports whether the resource matches the filter. Strings are compared
+// case-insensitive and multi-valued attributes match if one of their values matches.
+func (f *Filter) Match(resource Resource) bool {
+	switch f.Operator {
+	case FilterAnd:
+		for _, sub := range f.Filters {
+			if !sub.Match(resource) {
+				return false
+			}
+		}
+		return true
+	case FilterOr:
+		for _, sub := range f.Filters {
+			if sub.Match(resource) {
+				return true
+			}
+		}
+		return false
+	case FilterNot:
+		return !f.Filters[0].Match(resource)
+	case FilterValuePath:
+		for _, v := range lookup(resource, f.Attribute) {
+			if m, ok := asMap(v); ok && f.Filters[0].Match(m) {
+				return true
+			}
+		}
+		return false
+	}
+
+	values := lookup(resource, f.Attribute)
+	if f.Operator == FilterPresent {
+		for _, v := range values {
+			if v != nil && v != "" {
+				return true
+			}
+		}
+		return false
+	}
+	if f.Operator == "ne" {
+		return !(&Filter{Operator: "eq", Attribute: f.Attribute, Value: f.Value}).Match(resource)
+	}
+	if f.Value == nil {
+		// "eq null" matches the absent attributes
+		return len(values) == 0
+	}
+	for _, v := range values {
+		if m, ok := asMap(v); ok {
+			// complex values are compared by their "value" sub-attribute
+			v = m.Get("value")
+		}
+		if compare(v, f.Operator, f.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare compares the attribute value with the value of the filter.
+func compare(actual any, operator string, expected any) bool {
+	switch e := expected.(type) {
+	case string:
+		a, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		a, e = strings.ToLower(a), strings.ToLower(e)
+		switch operator {
+		case "eq":
+			return a == e
+		case "co":
+			return strings.Contains(a, e)
+		case "sw":
+			return strings.HasPrefix(a, e)
+		case "ew":
+			return strings.HasSuffix(a, e)
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	case float64:
+		a, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch operator {
+		case "eq":
+			return a == e
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	case bool:
+		a, ok := actual.(bool)
+		return ok && operator == "eq" && a == e
+	}
+	return false
+}
+
+// splitPath splits an attribute path into the schema of an extension, if the path
+// has a schema prefix, and the names of the attribute and its sub-attributes,
+// e.g. "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:manager.value".
+func splitPath(path string) (string, []string) {
+	if !strings.HasPrefix(strings.ToLower(path), "urn:") {
+		return "", strings.Split(path, ".")
+	}
+	i := strings.LastIndexByte(path, ':')
+	if i == len(path)-1 || unicode.IsUpper(rune(path[i+1])) {
+		// the path is the schema itself, e.g. of the extension attribute
+		return "", []string{path}
+	}
+	return path[:i], strings.Split(path[i+1:], ".")
+}
+
+// lookup returns the values of the attribute path. The values of multi-valued
+// attributes are flattened, so "emails.value" returns the values of all emails.
+func lookup(resource Resource, path string) []any {
+	schema, names := splitPath(path)
+	var current any = resource
+	if schema != "" {
+		if ext := resource.Get(schema); ext != nil {
+			current = ext
+		}
+	}
+
+	values := []any{current}
+	for _, name := range names {
+		var next []any
+		for _, v := range values {
+			m, ok := asMap(v)
+			if !ok {
+				continue
+			}
+			switch child := m.Get(name).(type) {
+			case nil:
+			case []any:
+				next = append(next, child...)
+			default:
+				next = append(next, child)
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+// asMap returns the value as resource if it is a JSON object.
+func asMap(v any) (Resource, bool) {
+	switch m := v.(type) {
+	case Resource:
+		return m, true
+	case map[string]any:
+		return m, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,110 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testUser returns a decoded user.
+func testUser(t *testing.T) Resource {
+	t.Helper()
+	var user Resource
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "2819c223",
+		"userName": "bjensen",
+		"name": {"givenName": "Barbara", "familyName": "Jensen"},
+		"emails": [
+			{"type": "work", "value": "bjensen@example.com"},
+			{"type": "home", "value": "babs@jensen.org"}
+		],
+		"active": true,
+		"meta": {"lastModified": "2024-05-13T04:42:34Z"},
+		"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User": {"employeeNumber": "701984"}
+	}`), &user))
+	return user
+}
+
+// go test -run Test_Filter_Match
+func Test_Filter_Match(t *testing.T) {
+	t.Parallel()
+	user := testUser(t)
+
+	for filter, expected := range map[string]bool{
+		`userName eq "BJensen"`:                           true,
+		`username ne "bjensen"`:                           false,
+		`name.familyName co "ens"`:                        true,
+		`userName sw "bj" and active eq true`:             true,
+		`userName sw "x" or emails.value ew "jensen.org"`: true,
+		`not (emails co "example.com")`:                   false,
+		`emails[type eq "work" and value co "@example"]`:  true,
+		`emails[type eq "other"]`:                         false,
+		`title pr`:                                        false,
+		`title eq null`:                                   true,
+		`meta.lastModified gt "2024-01-01T00:00:00Z"`:     true,
+		`(active eq false or userName eq "x") and id pr`:  false,
+		`urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:employeeNumber eq "701984"`: true,
+	} {
+		f, err := ParseFilter(filter)
+		require.NoError(t, err, filter)
+		require.Equal(t, expected, f.Match(user), filter)
+	}
+
+	for _, filter := range []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName like "x"`,
+		`userName eq "x" and`,
+		`(userName eq "x"`,
+		`emails[type eq "work"`,
+		`userName eq "unterminated`,
+		`userName eq abc`,
+	} {
+		_, err := ParseFilter(filter)
+		require.Error(t, err, filter)
+	}
+}
+
+// go test -run Test_ApplyPatch
+func Test_ApplyPatch(t *testing.T) {
+	t.Parallel()
+	user := testUser(t)
+
+	for _, op := range []PatchOperation{
+		{Op: "replace", Path: `emails[type eq "work"].value`, Value: "barbara@example.com"},
+		{Op: "add", Path: "emails", Value: map[string]any{"type": "other", "value": "b@example.net"}},
+		{Op: "remove", Path: `emails[type eq "home"]`},
+		{Op: "replace", Path: "name", Value: map[string]any{"givenName": "Babs"}},
+		{Op: "add", Path: "nickName", Value: "Babs"},
+		{Op: "remove", Path: "active"},
+		{Op: "replace", Path: "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", Value: "Tour"},
+	} {
+		require.NoError(t, applyPatch(user, op), op.Path)
+	}
+
+	require.Equal(t, []any{
+		map[string]any{"type": "work", "value": "barbara@example.com"},
+		map[string]any{"type": "other", "value": "b@example.net"},
+	}, user["emails"])
+	require.Equal(t, map[string]any{"givenName": "Babs", "familyName": "Jensen"}, user["name"])
+	require.Equal(t, "Babs", user["nickName"])
+	require.NotContains(t, user, "active")
+	require.Equal(t, map[string]any{"employeeNumber": "701984", "department": "Tour"},
+		user["urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"])
+
+	for op, scimType := range map[PatchOperation]string{
+		{Op: "move", Path: "name"}: "invalidSyntax",
+		{Op: "remove"}:             "noTarget",
+		{Op: "add", Value: "x"}:    "invalidValue",
+		{Op: "replace", Path: `emails[type eq "fax"].value`, Value: "x"}: "noTarget",
+		{Op: "replace", Path: `emails[type eq`, Value: "x"}:              "invalidPath",
+		{Op: "replace", Path: "name..givenName", Value: "x"}:             "invalidPath",
+	} {
+		err := applyPatch(user, op)
+		var scimErr *Error
+		require.ErrorAs(t, err, &scimErr, op.Path)
+		require.Equal(t, scimType, scimErr.ScimType, op.Path)
+	}
+}
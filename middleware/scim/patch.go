@@ -0,0 +1,300 @@
+package scim
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// The operations of PATCH requests
+const (
+	PatchAdd     = "add"
+	PatchReplace = "replace"
+	PatchRemove  = "remove"
+)
+
+// PatchOperation is an operation of a PATCH request.
+type PatchOperation struct {
+	// Value is the value of PatchAdd and PatchReplace, and the removed values of PatchRemove.
+	Value any `json:"value,omitempty"`
+	// Op is PatchAdd, PatchReplace or PatchRemove, case-insensitive.
+	Op string `json:"op"`
+	// Path is the attribute path, e.g. "members" or `emails[type eq "work"].value`.
+	Path string `json:"path,omitempty"`
+}
+
+// PatchRequest is the body of a PATCH request.
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"` //nolint:tagliatelle // the name is defined by RFC 7644
+}
+
+// patchPath is a parsed path of a PATCH operation.
+type patchPath struct {
+	// filter selects the values of a multi-valued attribute
+	filter *Filter
+	schema string
+	// names are the attribute and its sub-attributes before the filter
+	names []string
+	// sub is the sub-attribute of the selected values
+	sub string
+}
+
+// parsePatchPath parses a path, e.g. `emails[type eq "work"].value`.
+func parsePatchPath(path string) (*patchPath, error) {
+	p := &patchPath{}
+	attr := path
+	if open := strings.IndexByte(path, '['); open >= 0 {
+		end := strings.LastIndexByte(path, ']')
+		if end < open {
+			return nil, newError(fiber.StatusBadRequest, "invalidPath", fmt.Sprintf("invalid path %q", path))
+		}
+		filter, err := ParseFilter(path[open+1 : end])
+		if err != nil {
+			return nil, newError(fiber.StatusBadRequest, "invalidPath", fmt.Sprintf("invalid filter of path %q: %v", path, err))
+		}
+		p.filter = filter
+		attr = path[:open]
+		if rest := path[end+1:]; rest != "" {
+			sub, ok := strings.CutPrefix(rest, ".")
+			if !ok || sub == "" || strings.Contains(sub, ".") {
+				return nil, newError(fiber.StatusBadRequest, "invalidPath", fmt.Sprintf("invalid path %q", path))
+			}
+			p.sub = sub
+		}
+	}
+	p.schema, p.names = splitPath(attr)
+	for _, name := range p.names {
+		if name == "" {
+			return nil, newError(fiber.StatusBadRequest, "invalidPath", fmt.Sprintf("invalid path %q", path))
+		}
+	}
+	return p, nil
+}
+
+// applyPatch applies the operation to the resource.
+func applyPatch(resource Resource, op PatchOperation) error {
+	kind := strings.ToLower(op.Op)
+	switch kind {
+	case PatchAdd, PatchReplace, PatchRemove:
+	default:
+		return newError(fiber.StatusBadRequest, "invalidSyntax", fmt.Sprintf("unknown operation %q", op.Op))
+	}
+
+	if op.Path == "" {
+		if kind == PatchRemove {
+			return newError(fiber.StatusBadRequest, "noTarget", "remove requires a path")
+		}
+		values, ok := asMap(op.Value)
+		if !ok {
+			return newError(fiber.StatusBadRequest, "invalidValue", "the value of an operation without path must be an object")
+		}
+		// each attribute of the value is applied as if it was the path
+		for name, value := range values {
+			if err := applyPatch(resource, PatchOperation{Op: kind, Path: name, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	path, err := parsePatchPath(op.Path)
+	if err != nil {
+		return err
+	}
+
+	parent := resource
+	if path.schema != "" {
+		parent = child(resource, path.schema, kind != PatchRemove)
+	}
+	for _, name := range path.names[:len(path.names)-1] {
+		if parent == nil {
+			break
+		}
+		parent = child(parent, name, kind != PatchRemove)
+	}
+	if parent == nil {
+		if kind == PatchRemove {
+			return nil
+		}
+		return newError(fiber.StatusBadRequest, "invalidPath", fmt.Sprintf("invalid path %q", op.Path))
+	}
+	name := path.names[len(path.names)-1]
+
+	if path.filter == nil {
+		switch kind {
+		case PatchAdd:
+			addValue(parent, name, op.Value)
+		case PatchReplace:
+			replaceValue(parent, name, op.Value)
+		default:
+			removeValue(parent, name, op.Value)
+		}
+		return nil
+	}
+
+	return patchValues(parent, name, kind, path, op)
+}
+
+// patchValues applies the operation to the values of the multi-valued attribute which match the filter of the path.
+func patchValues(parent Resource, name, kind string, path *patchPath, op PatchOperation) error {
+	list, _ := parent.Get(name).([]any) //nolint:errcheck // a missing attribute has no values
+	var (
+		kept    []any
+		matched bool
+	)
+	for _, v := range list {
+		m, ok := asMap(v)
+		if !ok || !path.filter.Match(m) {
+			kept = append(kept, v)
+			continue
+		}
+		matched = true
+
+		switch {
+		case kind == PatchRemove && path.sub == "":
+			continue
+		case kind == PatchRemove:
+			deleteKey(m, path.sub)
+		case path.sub != "":
+			setKey(m, path.sub, op.Value)
+		default:
+			values, ok := asMap(op.Value)
+			if !ok {
+				return newError(fiber.StatusBadRequest, "invalidValue", fmt.Sprintf("the value of %q must be an object", op.Path))
+			}
+			for k, v := range values {
+				setKey(m, k, v)
+			}
+		}
+		kept = append(kept, map[string]any(m))
+	}
+
+	if !matched {
+		if kind == PatchRemove {
+			return nil
+		}
+		return newError(fiber.StatusBadRequest, "noTarget", fmt.Sprintf("no values match the path %q", op.Path))
+	}
+	if len(kept) == 0 {
+		deleteKey(parent, name)
+	} else {
+		setKey(parent, name, kept)
+	}
+	return nil
+}
+
+// addValue adds the value to the attribute: values are appended to multi-valued
+// attributes, complex values are merged and other values are replaced.
+func addValue(parent Resource, name string, value any) {
+	switch existing := parent.Get(name).(type) {
+	case []any:
+		values, ok := value.([]any)
+		if !ok {
+			values = []any{value}
+		}
+		for _, v := range values {
+			if !containsValue(existing, v) {
+				existing = append(existing, v)
+			}
+		}
+		setKey(parent, name, existing)
+	case map[string]any:
+		if values, ok := asMap(value); ok {
+			for k, v := range values {
+				setKey(existing, k, v)
+			}
+			return
+		}
+		setKey(parent, name, value)
+	default:
+		setKey(parent, name, value)
+	}
+}
+
+// replaceValue replaces the attribute, the sub-attributes of complex values are merged.
+func replaceValue(parent Resource, name string, value any) {
+	if existing, ok := parent.Get(name).(map[string]any); ok {
+		if values, ok := asMap(value); ok {
+			for k, v := range values {
+				setKey(existing, k, v)
+			}
+			return
+		}
+	}
+	setKey(parent, name, value)
+}
+
+// removeValue removes the attribute or, if values are given, these values of the
+// multi-valued attribute, e.g. {"op": "remove", "path": "members", "value": [{"value": "2819c223"}]}.
+func removeValue(parent Resource, name string, value any) {
+	existing, ok := parent.Get(name).([]any)
+	values, isList := value.([]any)
+	if !ok || !isList {
+		deleteKey(parent, name)
+		return
+	}
+
+	var kept []any
+	for _, v := range existing {
+		if !containsValue(values, v) {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) == 0 {
+		deleteKey(parent, name)
+	} else {
+		setKey(parent, name, kept)
+	}
+}
+
+// containsValue reports whether the list contains the value. Complex values are
+// compared by their "value" sub-attribute, e.g. the ids of the members.
+func containsValue(list []any, value any) bool {
+	if m, ok := asMap(value); ok {
+		value = m.Get("value")
+	}
+	for _, v := range list {
+		if m, ok := asMap(v); ok {
+			v = m.Get("value")
+		}
+		if v != nil && fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// child returns the complex attribute of the parent, which is created if create is set.
+func child(parent Resource, name string, create bool) Resource {
+	if m, ok := asMap(parent.Get(name)); ok {
+		return m
+	}
+	if !create {
+		return nil
+	}
+	m := map[string]any{}
+	setKey(parent, name, m)
+	return m
+}
+
+// setKey sets the attribute, keeping the case of an existing attribute name.
+func setKey(m Resource, name string, value any) {
+	for key := range m {
+		if strings.EqualFold(key, name) {
+			m[key] = value
+			return
+		}
+	}
+	m[name] = value
+}
+
+// deleteKey deletes the attribute, the name is case-insensitive.
+func deleteKey(m Resource, name string) {
+	for key := range m {
+		if strings.EqualFold(key, name) {
+			delete(m, key)
+		}
+	}
+}
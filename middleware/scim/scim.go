@@ -0,0 +1,420 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// MIMEApplicationSCIM is the content type of the SCIM responses.
+const MIMEApplicationSCIM = "application/scim+json"
+
+// The schemas of RFC 7643 and RFC 7644
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// Error is an error which is sent as SCIM error response.
+type Error struct {
+	// ScimType is the detail error keyword of RFC 7644, e.g. "invalidFilter", or empty.
+	ScimType string
+	// Detail is the human-readable message.
+	Detail string
+	// Status is the HTTP status code.
+	Status int
+}
+
+// newError creates a SCIM error.
+func newError(status int, scimType, detail string) *Error {
+	return &Error{Status: status, ScimType: scimType, Detail: detail}
+}
+
+// Error returns the detail of the error.
+func (e *Error) Error() string {
+	return "scim: " + e.Detail
+}
+
+// resourceType is a resource type of the endpoints.
+type resourceType struct {
+	name     string
+	endpoint string
+	schema   string
+	// required is the required attribute of the resources.
+	required string
+}
+
+var resourceTypes = []resourceType{
+	{name: ResourceUser, endpoint: "/Users", schema: SchemaUser, required: "userName"},
+	{name: ResourceGroup, endpoint: "/Groups", schema: SchemaGroup, required: "displayName"},
+}
+
+// listResponse is the response of list requests.
+type listResponse struct {
+	Schemas      []string   `json:"schemas"`
+	Resources    []Resource `json:"Resources"` //nolint:tagliatelle // the name is defined by RFC 7644
+	TotalResults int        `json:"totalResults"`
+	StartIndex   int        `json:"startIndex"`
+	ItemsPerPage int        `json:"itemsPerPage"`
+}
+
+// New creates a new middleware handler, which serves the Users, Groups,
+// ServiceProviderConfig and ResourceTypes endpoints of SCIM 2.0 below the prefix.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	if cfg.Store == nil {
+		panic("scim: a store is required")
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		path, found := strings.CutPrefix(c.Path(), cfg.Prefix)
+		if !found {
+			return c.Next()
+		}
+
+		err := serve(c, &cfg, path)
+		var scimErr *Error
+		if errors.As(err, &scimErr) {
+			return sendError(c, scimErr)
+		}
+		return err
+	}
+}
+
+// serve dispatches the request to the endpoint of the path.
+func serve(c fiber.Ctx, cfg *Config, path string) error {
+	switch path {
+	case "/ServiceProviderConfig":
+		if c.Method() != fiber.MethodGet {
+			return newError(fiber.StatusMethodNotAllowed, "", "method not allowed")
+		}
+		return c.JSON(serviceProviderConfig(cfg), MIMEApplicationSCIM)
+	case "/ResourceTypes":
+		if c.Method() != fiber.MethodGet {
+			return newError(fiber.StatusMethodNotAllowed, "", "method not allowed")
+		}
+		return c.JSON(resourceTypesResponse(c, cfg), MIMEApplicationSCIM)
+	}
+
+	for i := range resourceTypes {
+		rt := &resourceTypes[i]
+		rest, ok := strings.CutPrefix(path, rt.endpoint)
+		if !ok {
+			continue
+		}
+		if rest == "" || rest == "/" {
+			switch c.Method() {
+			case fiber.MethodGet:
+				return list(c, cfg, rt)
+			case fiber.MethodPost:
+				return create(c, cfg, rt)
+			default:
+				return newError(fiber.StatusMethodNotAllowed, "", "method not allowed")
+			}
+		}
+		if id, ok := strings.CutPrefix(rest, "/"); ok && !strings.Contains(id, "/") {
+			switch c.Method() {
+			case fiber.MethodGet:
+				return get(c, cfg, rt, id)
+			case fiber.MethodPut:
+				return replace(c, cfg, rt, id)
+			case fiber.MethodPatch:
+				return patch(c, cfg, rt, id)
+			case fiber.MethodDelete:
+				if err := cfg.Store.Delete(c.UserContext(), rt.name, id); err != nil {
+					return storeError(err)
+				}
+				return c.SendStatus(fiber.StatusNoContent)
+			default:
+				return newError(fiber.StatusMethodNotAllowed, "", "method not allowed")
+			}
+		}
+	}
+
+	return c.Next()
+}
+
+// list sends a page of the resources which match the filter of the query.
+func list(c fiber.Ctx, cfg *Config, rt *resourceType) error {
+	query := Query{StartIndex: 1, Count: cfg.MaxResults}
+	if filter := c.Query("filter"); filter != "" {
+		f, err := ParseFilter(filter)
+		if err != nil {
+			return newError(fiber.StatusBadRequest, "invalidFilter", err.Error())
+		}
+		query.Filter = f
+	}
+	if v := c.Query("startIndex"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return newError(fiber.StatusBadRequest, "invalidValue", "invalid startIndex "+strconv.Quote(v))
+		}
+		query.StartIndex = max(n, 1)
+	}
+	if v := c.Query("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return newError(fiber.StatusBadRequest, "invalidValue", "invalid count "+strconv.Quote(v))
+		}
+		query.Count = min(max(n, 0), cfg.MaxResults)
+	}
+
+	resources, total, err := cfg.Store.List(c.UserContext(), rt.name, query)
+	if err != nil {
+		return storeError(err)
+	}
+	if resources == nil {
+		resources = []Resource{}
+	}
+	for _, resource := range resources {
+		project(c, resource)
+	}
+
+	return c.JSON(listResponse{
+		Schemas:      []string{SchemaListResponse},
+		Resources:    resources,
+		TotalResults: total,
+		StartIndex:   query.StartIndex,
+		ItemsPerPage: len(resources),
+	}, MIMEApplicationSCIM)
+}
+
+// get sends the resource with the id.
+func get(c fiber.Ctx, cfg *Config, rt *resourceType, id string) error {
+	resource, err := cfg.Store.Get(c.UserContext(), rt.name, id)
+	if err != nil {
+		return storeError(err)
+	}
+	project(c, resource)
+	return c.JSON(resource, MIMEApplicationSCIM)
+}
+
+// create stores the resource of the body with a new id.
+func create(c fiber.Ctx, cfg *Config, rt *resourceType) error {
+	resource, err := decodeResource(c, rt)
+	if err != nil {
+		return err
+	}
+
+	resource["id"] = utils.UUIDv4()
+	location := touch(c, cfg, rt, resource, nil)
+	if err := cfg.Store.Create(c.UserContext(), rt.name, resource); err != nil {
+		return storeError(err)
+	}
+
+	c.Set(fiber.HeaderLocation, location)
+	return c.Status(fiber.StatusCreated).JSON(resource, MIMEApplicationSCIM)
+}
+
+// replace replaces the resource with the id by the resource of the body.
+func replace(c fiber.Ctx, cfg *Config, rt *resourceType, id string) error {
+	existing, err := cfg.Store.Get(c.UserContext(), rt.name, id)
+	if err != nil {
+		return storeError(err)
+	}
+	resource, err := decodeResource(c, rt)
+	if err != nil {
+		return err
+	}
+
+	resource["id"] = id
+	touch(c, cfg, rt, resource, existing)
+	if err := cfg.Store.Replace(c.UserContext(), rt.name, resource); err != nil {
+		return storeError(err)
+	}
+	return c.JSON(resource, MIMEApplicationSCIM)
+}
+
+// patch applies the operations of the body to the resource with the id.
+func patch(c fiber.Ctx, cfg *Config, rt *resourceType, id string) error {
+	var req PatchRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return newError(fiber.StatusBadRequest, "invalidSyntax", "invalid JSON: "+err.Error())
+	}
+	if len(req.Operations) == 0 {
+		return newError(fiber.StatusBadRequest, "invalidValue", "no operations")
+	}
+
+	existing, err := cfg.Store.Get(c.UserContext(), rt.name, id)
+	if err != nil {
+		return storeError(err)
+	}
+	resource, err := cloneResource(existing)
+	if err != nil {
+		return err
+	}
+	for _, op := range req.Operations {
+		if err := applyPatch(resource, op); err != nil {
+			return err
+		}
+	}
+
+	// the id and meta are read-only
+	deleteKey(resource, "id")
+	resource["id"] = id
+	if err := validate(resource, rt); err != nil {
+		return err
+	}
+	touch(c, cfg, rt, resource, existing)
+	if err := cfg.Store.Replace(c.UserContext(), rt.name, resource); err != nil {
+		return storeError(err)
+	}
+	return c.JSON(resource, MIMEApplicationSCIM)
+}
+
+// decodeResource decodes and validates the resource of the body.
+// The id and meta of the body are ignored.
+func decodeResource(c fiber.Ctx, rt *resourceType) (Resource, error) {
+	var resource Resource
+	if err := json.Unmarshal(c.Body(), &resource); err != nil || resource == nil {
+		return nil, newError(fiber.StatusBadRequest, "invalidSyntax", "the body must be a JSON object")
+	}
+	deleteKey(resource, "id")
+	if resource.Get("schemas") == nil {
+		resource["schemas"] = []any{rt.schema}
+	}
+	return resource, validate(resource, rt)
+}
+
+// validate checks the required attribute of the resource.
+func validate(resource Resource, rt *resourceType) error {
+	if v, ok := resource.Get(rt.required).(string); !ok || v == "" {
+		return newError(fiber.StatusBadRequest, "invalidValue", rt.required+" is required")
+	}
+	return nil
+}
+
+// touch sets the meta of the resource, the creation time is kept from the
+// existing resource. It returns the location of the resource.
+func touch(c fiber.Ctx, cfg *Config, rt *resourceType, resource, existing Resource) string {
+	now := time.Now().UTC().Format(time.RFC3339)
+	created := now
+	if meta, ok := asMap(existing.Get("meta")); ok {
+		if v, ok := meta.Get("created").(string); ok {
+			created = v
+		}
+	}
+
+	location := c.BaseURL() + cfg.Prefix + rt.endpoint + "/" + resource.ID()
+	deleteKey(resource, "meta")
+	resource["meta"] = map[string]any{
+		"resourceType": rt.name,
+		"created":      created,
+		"lastModified": now,
+		"location":     location,
+	}
+	return location
+}
+
+// project applies the "attributes" and "excludedAttributes" parameters to the
+// top-level attributes of the resource. The id and schemas are always returned.
+func project(c fiber.Ctx, resource Resource) {
+	split := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		names := strings.Split(s, ",")
+		for i := range names {
+			names[i] = strings.ToLower(strings.TrimSpace(names[i]))
+		}
+		return names
+	}
+	attributes, excluded := split(c.Query("attributes")), split(c.Query("excludedAttributes"))
+	if attributes == nil && excluded == nil {
+		return
+	}
+
+	for key := range resource {
+		name := strings.ToLower(key)
+		if name == "id" || name == "schemas" {
+			continue
+		}
+		if (attributes != nil && !slices.Contains(attributes, name)) || slices.Contains(excluded, name) {
+			delete(resource, key)
+		}
+	}
+}
+
+// storeError converts the errors of the store to SCIM errors.
+func storeError(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return newError(fiber.StatusNotFound, "", "resource not found")
+	case errors.Is(err, ErrConflict):
+		return newError(fiber.StatusConflict, "uniqueness", "resource already exists")
+	default:
+		return err
+	}
+}
+
+// sendError sends the SCIM error response.
+func sendError(c fiber.Ctx, err *Error) error {
+	body := map[string]any{
+		"schemas": []string{SchemaError},
+		"status":  strconv.Itoa(err.Status),
+		"detail":  err.Detail,
+	}
+	if err.ScimType != "" {
+		body["scimType"] = err.ScimType
+	}
+	return c.Status(err.Status).JSON(body, MIMEApplicationSCIM)
+}
+
+// serviceProviderConfig returns the supported features of the endpoints.
+func serviceProviderConfig(cfg *Config) map[string]any {
+	return map[string]any{
+		"schemas":               []string{SchemaServiceProviderConfig},
+		"patch":                 map[string]any{"supported": true},
+		"bulk":                  map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":                map[string]any{"supported": true, "maxResults": cfg.MaxResults},
+		"changePassword":        map[string]any{"supported": false},
+		"sort":                  map[string]any{"supported": false},
+		"etag":                  map[string]any{"supported": false},
+		"authenticationSchemes": []any{},
+	}
+}
+
+// resourceTypesResponse returns the resource types of the endpoints.
+func resourceTypesResponse(c fiber.Ctx, cfg *Config) listResponse {
+	resources := make([]Resource, 0, len(resourceTypes))
+	for _, rt := range resourceTypes {
+		resources = append(resources, Resource{
+			"schemas":  []string{SchemaResourceType},
+			"id":       rt.name,
+			"name":     rt.name,
+			"endpoint": rt.endpoint,
+			"schema":   rt.schema,
+			"meta": map[string]any{
+				"resourceType": "ResourceType",
+				"location":     fmt.Sprintf("%s%s/ResourceTypes/%s", c.BaseURL(), cfg.Prefix, rt.name),
+			},
+		})
+	}
+	return listResponse{
+		Schemas:      []string{SchemaListResponse},
+		Resources:    resources,
+		TotalResults: len(resources),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+	}
+}
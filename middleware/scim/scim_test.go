@@ -0,0 +1,198 @@
+package scim
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// testRequest sends the request and returns the status and the decoded body.
+func testRequest(t *testing.T, app *fiber.App, method, target, body string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, MIMEApplicationSCIM)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var decoded map[string]any
+	if resp.Header.Get(fiber.HeaderContentType) == MIMEApplicationSCIM {
+		require.NoError(t, json.Unmarshal(b, &decoded))
+	}
+	return resp.StatusCode, decoded
+}
+
+// go test -run Test_SCIM_Users
+func Test_SCIM_Users(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Store: NewMemoryStore()}))
+
+	status, user := testRequest(t, app, fiber.MethodPost, "/scim/v2/Users", `{
+		"userName": "bjensen",
+		"name": {"givenName": "Barbara", "familyName": "Jensen"},
+		"emails": [{"type": "work", "value": "bjensen@example.com", "primary": true}],
+		"active": true
+	}`)
+	require.Equal(t, fiber.StatusCreated, status)
+	id, ok := user["id"].(string)
+	require.True(t, ok)
+	require.Len(t, id, 36)
+	require.Equal(t, []any{SchemaUser}, user["schemas"])
+	meta, ok := user["meta"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, ResourceUser, meta["resourceType"])
+	require.Equal(t, "http://example.com/scim/v2/Users/"+id, meta["location"])
+
+	status, body := testRequest(t, app, fiber.MethodPost, "/scim/v2/Users", `{"userName": "BJENSEN"}`)
+	require.Equal(t, fiber.StatusConflict, status)
+	require.Equal(t, "uniqueness", body["scimType"])
+	require.Equal(t, "409", body["status"])
+
+	status, body = testRequest(t, app, fiber.MethodPost, "/scim/v2/Users", `{"displayName": "Jim"}`)
+	require.Equal(t, fiber.StatusBadRequest, status)
+	require.Equal(t, "userName is required", body["detail"])
+
+	status, body = testRequest(t, app, fiber.MethodGet, "/scim/v2/Users/"+id, "")
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "bjensen", body["userName"])
+
+	status, body = testRequest(t, app, fiber.MethodPut, "/scim/v2/Users/"+id, `{"userName": "bjensen", "active": false}`)
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, false, body["active"])
+	require.Nil(t, body["emails"])
+	require.Equal(t, meta["created"], body["meta"].(map[string]any)["created"]) //nolint:forcetypeassert // it's a test
+
+	status, _ = testRequest(t, app, fiber.MethodDelete, "/scim/v2/Users/"+id, "")
+	require.Equal(t, fiber.StatusNoContent, status)
+
+	status, body = testRequest(t, app, fiber.MethodGet, "/scim/v2/Users/"+id, "")
+	require.Equal(t, fiber.StatusNotFound, status)
+	require.Equal(t, []any{SchemaError}, body["schemas"])
+
+	status, _ = testRequest(t, app, fiber.MethodPost, "/scim/v2/Users/"+id, "")
+	require.Equal(t, fiber.StatusMethodNotAllowed, status)
+
+	// other paths are passed to the next handler
+	status, _ = testRequest(t, app, fiber.MethodGet, "/scim/v2/Other", "")
+	require.Equal(t, fiber.StatusNotFound, status)
+}
+
+// go test -run Test_SCIM_List
+func Test_SCIM_List(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Store: NewMemoryStore(), MaxResults: 2}))
+
+	for _, name := range []string{"alice", "bob", "carol"} {
+		status, _ := testRequest(t, app, fiber.MethodPost, "/scim/v2/Users", `{"userName": "`+name+`", "title": "Engineer"}`)
+		require.Equal(t, fiber.StatusCreated, status)
+	}
+
+	status, body := testRequest(t, app, fiber.MethodGet, "/scim/v2/Users?filter="+
+		`userName+eq+"BOB"`, "")
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, []any{SchemaListResponse}, body["schemas"])
+	require.InDelta(t, 1, body["totalResults"], 0)
+	require.Equal(t, "bob", body["Resources"].([]any)[0].(map[string]any)["userName"]) //nolint:forcetypeassert // it's a test
+
+	// the count is limited by MaxResults
+	status, body = testRequest(t, app, fiber.MethodGet, "/scim/v2/Users?count=10&attributes=userName", "")
+	require.Equal(t, fiber.StatusOK, status)
+	require.InDelta(t, 3, body["totalResults"], 0)
+	require.InDelta(t, 2, body["itemsPerPage"], 0)
+	resource := body["Resources"].([]any)[0].(map[string]any) //nolint:forcetypeassert // it's a test
+	require.Nil(t, resource["title"])
+	require.NotNil(t, resource["id"])
+
+	status, body = testRequest(t, app, fiber.MethodGet, "/scim/v2/Users?startIndex=3&excludedAttributes=title", "")
+	require.Equal(t, fiber.StatusOK, status)
+	require.InDelta(t, 1, body["itemsPerPage"], 0)
+	require.InDelta(t, 3, body["startIndex"], 0)
+	require.Nil(t, body["Resources"].([]any)[0].(map[string]any)["title"]) //nolint:forcetypeassert // it's a test
+
+	status, body = testRequest(t, app, fiber.MethodGet, "/scim/v2/Users?filter=userName+like+%22bob%22", "")
+	require.Equal(t, fiber.StatusBadRequest, status)
+	require.Equal(t, "invalidFilter", body["scimType"])
+
+	status, body = testRequest(t, app, fiber.MethodGet, "/scim/v2/Groups", "")
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, []any{}, body["Resources"])
+}
+
+// go test -run Test_SCIM_Patch
+func Test_SCIM_Patch(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Store: NewMemoryStore(), Prefix: "/scim"}))
+
+	status, group := testRequest(t, app, fiber.MethodPost, "/scim/Groups", `{
+		"displayName": "Admins",
+		"members": [{"value": "u1"}, {"value": "u2"}]
+	}`)
+	require.Equal(t, fiber.StatusCreated, status)
+	target := "/scim/Groups/" + group["id"].(string) //nolint:forcetypeassert // it's a test
+
+	status, body := testRequest(t, app, fiber.MethodPatch, target, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{"op": "Add", "path": "members", "value": [{"value": "u2"}, {"value": "u3"}]},
+			{"op": "remove", "path": "members[value eq \"u1\"]"},
+			{"op": "replace", "value": {"displayName": "Administrators", "id": "changed"}}
+		]
+	}`)
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "Administrators", body["displayName"])
+	require.Equal(t, group["id"], body["id"])
+	require.Equal(t, []any{map[string]any{"value": "u2"}, map[string]any{"value": "u3"}}, body["members"])
+
+	status, body = testRequest(t, app, fiber.MethodPatch, target, `{
+		"Operations": [{"op": "replace", "path": "members[value eq \"u9\"]", "value": {"display": "x"}}]
+	}`)
+	require.Equal(t, fiber.StatusBadRequest, status)
+	require.Equal(t, "noTarget", body["scimType"])
+
+	status, body = testRequest(t, app, fiber.MethodPatch, target, `{"Operations": [{"op": "remove", "path": "displayName"}]}`)
+	require.Equal(t, fiber.StatusBadRequest, status)
+	require.Equal(t, "displayName is required", body["detail"])
+
+	status, _ = testRequest(t, app, fiber.MethodPatch, "/scim/Groups/missing", `{"Operations": [{"op": "remove", "path": "members"}]}`)
+	require.Equal(t, fiber.StatusNotFound, status)
+}
+
+// go test -run Test_SCIM_Discovery
+func Test_SCIM_Discovery(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Store: NewMemoryStore()}))
+
+	status, body := testRequest(t, app, fiber.MethodGet, "/scim/v2/ServiceProviderConfig", "")
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, map[string]any{"supported": true}, body["patch"])
+	require.Equal(t, map[string]any{"supported": true, "maxResults": float64(100)}, body["filter"])
+
+	status, body = testRequest(t, app, fiber.MethodGet, "/scim/v2/ResourceTypes", "")
+	require.Equal(t, fiber.StatusOK, status)
+	require.InDelta(t, 2, body["totalResults"], 0)
+}
+
+// go test -run Test_SCIM_Next
+func Test_SCIM_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Store: NewMemoryStore(),
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	status, _ := testRequest(t, app, fiber.MethodGet, "/scim/v2/Users", "")
+	require.Equal(t, fiber.StatusNotFound, status)
+}
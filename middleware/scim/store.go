@@ -0,0 +1,204 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The resource types of the endpoints
+const (
+	ResourceUser  = "User"
+	ResourceGroup = "Group"
+)
+
+// The errors of the stores
+var (
+	// ErrNotFound is returned by the stores if the resource doesn't exist.
+	ErrNotFound = errors.New("scim: resource not found")
+	// ErrConflict is returned by the stores if a unique attribute, e.g. the userName, is already used.
+	ErrConflict = errors.New("scim: resource already exists")
+)
+
+// Resource is a SCIM resource, the JSON object of a user or a group.
+// The attribute names are case-insensitive, see Get.
+type Resource map[string]any
+
+// ID returns the "id" of the resource.
+func (r Resource) ID() string {
+	id, _ := r.Get("id").(string) //nolint:errcheck // the id is a string or missing
+	return id
+}
+
+// Get returns the value of the attribute, the attribute name is case-insensitive.
+func (r Resource) Get(name string) any {
+	if v, ok := r[name]; ok {
+		return v
+	}
+	for key, v := range r {
+		if strings.EqualFold(key, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// Query is the query of a list request.
+type Query struct {
+	// Filter is the parsed filter, or nil if the request has no filter.
+	Filter *Filter
+	// StartIndex is the 1-based index of the first resource.
+	StartIndex int
+	// Count is the maximum number of resources.
+	Count int
+}
+
+// Store stores the users and groups of the endpoints. The resource type is
+// ResourceUser or ResourceGroup. PATCH requests are applied to the resource
+// which is returned by Get and stored with Replace.
+type Store interface {
+	// Get returns the resource with the id, or ErrNotFound.
+	Get(ctx context.Context, resourceType, id string) (Resource, error)
+	// List returns the page of the resources which match the filter of the query
+	// and the total number of the matching resources.
+	List(ctx context.Context, resourceType string, query Query) ([]Resource, int, error)
+	// Create stores a new resource, whose "id" and "meta" are set.
+	Create(ctx context.Context, resourceType string, resource Resource) error
+	// Replace replaces the resource with the id of the resource, or returns ErrNotFound.
+	Replace(ctx context.Context, resourceType string, resource Resource) error
+	// Delete deletes the resource with the id, or returns ErrNotFound.
+	Delete(ctx context.Context, resourceType, id string) error
+}
+
+// MemoryStore is a Store which keeps the resources in memory, e.g. for tests.
+// The userName of the users and the displayName of the groups are unique.
+type MemoryStore struct {
+	resources map[string]map[string]Resource
+	mutex     sync.RWMutex
+}
+
+// NewMemoryStore creates an empty store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{resources: map[string]map[string]Resource{
+		ResourceUser:  {},
+		ResourceGroup: {},
+	}}
+}
+
+// Get returns a copy of the resource.
+func (s *MemoryStore) Get(_ context.Context, resourceType, id string) (Resource, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	resource, ok := s.resources[resourceType][id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneResource(resource)
+}
+
+// List returns copies of the resources which match the filter, ordered by their id.
+func (s *MemoryStore) List(_ context.Context, resourceType string, query Query) ([]Resource, int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var matches []Resource
+	for _, resource := range s.resources[resourceType] {
+		if query.Filter == nil || query.Filter.Match(resource) {
+			matches = append(matches, resource)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].ID() < matches[j].ID()
+	})
+
+	total := len(matches)
+	start := min(max(query.StartIndex-1, 0), total)
+	end := min(start+query.Count, total)
+
+	page := make([]Resource, 0, end-start)
+	for _, resource := range matches[start:end] {
+		clone, err := cloneResource(resource)
+		if err != nil {
+			return nil, 0, err
+		}
+		page = append(page, clone)
+	}
+	return page, total, nil
+}
+
+// Create stores a copy of the resource.
+func (s *MemoryStore) Create(_ context.Context, resourceType string, resource Resource) error {
+	return s.store(resourceType, resource, false)
+}
+
+// Replace replaces the resource with a copy of the resource.
+func (s *MemoryStore) Replace(_ context.Context, resourceType string, resource Resource) error {
+	return s.store(resourceType, resource, true)
+}
+
+// Delete deletes the resource.
+func (s *MemoryStore) Delete(_ context.Context, resourceType, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.resources[resourceType][id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.resources[resourceType], id)
+	return nil
+}
+
+// store creates or replaces the resource after checking its unique attribute.
+func (s *MemoryStore) store(resourceType string, resource Resource, replace bool) error {
+	clone, err := cloneResource(resource)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	resources, ok := s.resources[resourceType]
+	if !ok {
+		return ErrNotFound
+	}
+	id := clone.ID()
+	if _, exists := resources[id]; exists != replace {
+		if replace {
+			return ErrNotFound
+		}
+		return ErrConflict
+	}
+
+	unique := "userName"
+	if resourceType == ResourceGroup {
+		unique = "displayName"
+	}
+	if value, ok := clone.Get(unique).(string); ok {
+		for otherID, other := range resources {
+			if otherValue, ok := other.Get(unique).(string); ok && otherID != id && strings.EqualFold(value, otherValue) {
+				return ErrConflict
+			}
+		}
+	}
+
+	resources[id] = clone
+	return nil
+}
+
+// cloneResource returns a deep copy of the resource.
+func cloneResource(resource Resource) (Resource, error) {
+	b, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var clone Resource
+	if err := json.Unmarshal(b, &clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
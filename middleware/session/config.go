@@ -18,6 +18,11 @@ type Config struct {
 	// Optional. Default value memory.New()
 	Storage fiber.Storage
 
+	// Clock is the source of the time of the expirations of the cookies and
+	// of the in memory store, e.g. a fiber.ManualClock in tests.
+	// Optional. Default value nil, the system time.
+	Clock fiber.Clock
+
 	// KeyLookup is a string in the form of "<source>:<name>" that is used
 	// to extract session id from the request.
 	// Possible values: "header:<name>", "query:<name>" or "cookie:<name>"
@@ -53,6 +58,12 @@ type Config struct {
 	// Optional. Default value utils.UUIDv4
 	KeyGenerator func() string
 
+	// Keyring signs the session id in the cookie, header or query, so forged
+	// ids are rejected before the storage is accessed. The sessions of the
+	// retired keys stay valid and are signed with the active key when they are saved.
+	// Optional. Default value nil.
+	Keyring *fiber.Keyring
+
 	// Source defines where to obtain the session id
 	source Source
 
@@ -214,20 +214,21 @@ func (s *Session) SetExpiry(exp time.Duration) {
 }
 
 func (s *Session) setSession() {
+	value := s.config.signID(s.id)
 	if s.config.source == SourceHeader {
-		s.ctx.Request().Header.SetBytesV(s.config.sessionName, []byte(s.id))
-		s.ctx.Response().Header.SetBytesV(s.config.sessionName, []byte(s.id))
+		s.ctx.Request().Header.SetBytesV(s.config.sessionName, []byte(value))
+		s.ctx.Response().Header.SetBytesV(s.config.sessionName, []byte(value))
 	} else {
 		fcookie := fasthttp.AcquireCookie()
 		fcookie.SetKey(s.config.sessionName)
-		fcookie.SetValue(s.id)
+		fcookie.SetValue(value)
 		fcookie.SetPath(s.config.CookiePath)
 		fcookie.SetDomain(s.config.CookieDomain)
 		// Cookies are also session cookies if they do not specify the Expires or Max-Age attribute.
 		// refer: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Set-Cookie
 		if !s.config.CookieSessionOnly {
 			fcookie.SetMaxAge(int(s.exp.Seconds()))
-			fcookie.SetExpire(time.Now().Add(s.exp))
+			fcookie.SetExpire(s.now().Add(s.exp))
 		}
 		fcookie.SetSecure(s.config.CookieSecure)
 		fcookie.SetHTTPOnly(s.config.CookieHTTPOnly)
@@ -245,6 +246,14 @@ func (s *Session) setSession() {
 	}
 }
 
+// now returns the time of the clock of the config.
+func (s *Session) now() time.Time {
+	if s.config.Clock != nil {
+		return s.config.Clock.Now()
+	}
+	return time.Now()
+}
+
 func (s *Session) delSession() {
 	if s.config.source == SourceHeader {
 		s.ctx.Request().Header.Del(s.config.sessionName)
@@ -258,7 +267,7 @@ func (s *Session) delSession() {
 		fcookie.SetPath(s.config.CookiePath)
 		fcookie.SetDomain(s.config.CookieDomain)
 		fcookie.SetMaxAge(-1)
-		fcookie.SetExpire(time.Now().Add(-1 * time.Minute))
+		fcookie.SetExpire(s.now().Add(-1 * time.Minute))
 		fcookie.SetSecure(s.config.CookieSecure)
 		fcookie.SetHTTPOnly(s.config.CookieHTTPOnly)
 
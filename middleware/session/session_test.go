@@ -386,6 +386,32 @@ func Test_Session_Save_Expiration(t *testing.T) {
 	})
 }
 
+// go test -run Test_Session_Clock
+func Test_Session_Clock(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := fiber.NewManualClock(start)
+	store := New(Config{Expiration: time.Hour, Clock: clock})
+	app := fiber.New()
+	ctx := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(ctx)
+
+	sess, err := store.Get(ctx)
+	require.NoError(t, err)
+	sess.Set("name", "john")
+	require.NoError(t, sess.Save())
+
+	sess, err = store.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "john", sess.Get("name"))
+
+	// the session expires on the clock without sleeping
+	clock.Advance(time.Hour)
+	sess, err = store.Get(ctx)
+	require.NoError(t, err)
+	require.Nil(t, sess.Get("name"))
+}
+
 // go test -run Test_Session_Destroy
 func Test_Session_Destroy(t *testing.T) {
 	t.Parallel()
@@ -497,6 +523,57 @@ func Test_Session_Cookie_In_Response(t *testing.T) {
 	require.Equal(t, "john", sess.Get("name"))
 }
 
+// go test -run Test_Session_Keyring
+func Test_Session_Keyring(t *testing.T) {
+	t.Parallel()
+	keyring := fiber.NewKeyring(fiber.KeyringConfig{Keys: []fiber.Key{{ID: "v1", Value: []byte("secret-1")}}})
+	store := New(Config{Keyring: keyring})
+	app := fiber.New()
+
+	ctx := app.AcquireCtx(&fasthttp.RequestCtx{})
+	sess, err := store.Get(ctx)
+	require.NoError(t, err)
+	sess.Set("name", "john")
+	id := sess.ID()
+	require.NoError(t, sess.Save())
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+	require.NoError(t, cookie.ParseBytes(ctx.Response().Header.PeekCookie(store.sessionName)))
+	signed := string(cookie.Value())
+	require.Contains(t, signed, id+".v1:")
+	app.ReleaseCtx(ctx)
+
+	// the signed id loads the session
+	ctx = app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().Header.SetCookie(store.sessionName, signed)
+	sess, err = store.Get(ctx)
+	require.NoError(t, err)
+	require.False(t, sess.Fresh())
+	require.Equal(t, "john", sess.Get("name"))
+	app.ReleaseCtx(ctx)
+
+	// a forged id creates a new session
+	ctx = app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().Header.SetCookie(store.sessionName, id)
+	sess, err = store.Get(ctx)
+	require.NoError(t, err)
+	require.True(t, sess.Fresh())
+	require.NotEqual(t, id, sess.ID())
+	app.ReleaseCtx(ctx)
+
+	// the id signed by the retired key stays valid and is signed with the active key on save
+	keyring.Rotate(fiber.Key{ID: "v2", Value: []byte("secret-2")})
+	ctx = app.AcquireCtx(&fasthttp.RequestCtx{})
+	ctx.Request().Header.SetCookie(store.sessionName, signed)
+	sess, err = store.Get(ctx)
+	require.NoError(t, err)
+	require.False(t, sess.Fresh())
+	require.Equal(t, id, sess.ID())
+	require.NoError(t, sess.Save())
+	require.Contains(t, string(ctx.Response().Header.PeekCookie(store.sessionName)), id+".v2:")
+	app.ReleaseCtx(ctx)
+}
+
 // go test -run Test_Session_Deletes_Single_Key
 // Regression: https://github.com/gofiber/fiber/issues/1365
 func Test_Session_Deletes_Single_Key(t *testing.T) {
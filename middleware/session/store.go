@@ -26,7 +26,7 @@ func New(config ...Config) *Store {
 	cfg := configDefault(config...)
 
 	if cfg.Storage == nil {
-		cfg.Storage = memory.New()
+		cfg.Storage = memory.New(memory.Config{Clock: cfg.Clock})
 	}
 
 	return &Store{
@@ -45,7 +45,7 @@ func (s *Store) Get(c fiber.Ctx) (*Session, error) {
 	var fresh bool
 	loadData := true
 
-	id := s.getSessionID(c)
+	id := s.verifyID(s.getSessionID(c))
 
 	if len(id) == 0 {
 		fresh = true
@@ -53,6 +53,7 @@ func (s *Store) Get(c fiber.Ctx) (*Session, error) {
 		if id, err = s.responseCookies(c); err != nil {
 			return nil, err
 		}
+		id = s.verifyID(id)
 	}
 
 	// If no key exist, create new one
@@ -141,6 +142,30 @@ func (s *Store) responseCookies(c fiber.Ctx) (string, error) {
 	return id, nil
 }
 
+// keyringPurpose separates the signatures of the session ids from the other signatures of the keyring.
+const keyringPurpose = "fiber/session"
+
+// signID signs the session id with the keyring, if it is set.
+func (s *Store) signID(id string) string {
+	if s.Keyring == nil {
+		return id
+	}
+	return s.Keyring.SignValue(keyringPurpose, id)
+}
+
+// verifyID returns the session id of a signed id, or an empty string if the signature
+// is invalid. The id is returned as it is if the keyring isn't set.
+func (s *Store) verifyID(value string) string {
+	if s.Keyring == nil || value == "" {
+		return value
+	}
+	id, ok := s.Keyring.VerifyValue(keyringPurpose, value)
+	if !ok {
+		return ""
+	}
+	return id
+}
+
 // Reset will delete all session from the storage
 func (s *Store) Reset() error {
 	return s.Storage.Reset()
@@ -2,6 +2,7 @@ package skip
 
 import (
 	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
 )
 
 // New creates a middleware handler which skips the wrapped handler
@@ -11,11 +12,93 @@ func New(handler fiber.Handler, exclude func(c fiber.Ctx) bool) fiber.Handler {
 		return handler
 	}
 
-	return func(c fiber.Ctx) error {
-		if exclude(c) {
-			return c.Next()
+	return fiber.Skip(exclude).Wrap(handler)
+}
+
+// Only creates a middleware handler which only executes the wrapped handler
+// if the include predicate returns true.
+func Only(handler fiber.Handler, include func(c fiber.Ctx) bool) fiber.Handler {
+	if include == nil {
+		return handler
+	}
+
+	return fiber.Only(include).Wrap(handler)
+}
+
+// Paths returns a predicate which is true if the path of the request matches one of the
+// route patterns, e.g. "/health" or "/api/*", see fiber.RoutePatternMatch.
+func Paths(patterns ...string) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		for _, pattern := range patterns {
+			if fiber.RoutePatternMatch(c.Path(), pattern) {
+				return true
+			}
 		}
+		return false
+	}
+}
+
+// Methods returns a predicate which is true if the method of the request is one of the methods.
+func Methods(methods ...string) func(c fiber.Ctx) bool {
+	upper := make([]string, len(methods))
+	for i, method := range methods {
+		upper[i] = utils.ToUpper(method)
+	}
+	return func(c fiber.Ctx) bool {
+		method := c.Method()
+		for _, m := range upper {
+			if m == method {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Header returns a predicate which is true if the request has the header, with one of the values
+// if values are given.
+func Header(key string, values ...string) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		value := c.Get(key)
+		if len(values) == 0 {
+			return value != ""
+		}
+		for _, v := range values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Locals returns a predicate which is true if the local of the key is set and isn't false,
+// e.g. a flag which was set by a previous middleware.
+func Locals(key any) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		value := c.Locals(key)
+		if b, ok := value.(bool); ok {
+			return b
+		}
+		return value != nil
+	}
+}
+
+// Any returns a predicate which is true if one of the predicates is true.
+func Any(predicates ...func(c fiber.Ctx) bool) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		for _, predicate := range predicates {
+			if predicate(c) {
+				return true
+			}
+		}
+		return false
+	}
+}
 
-		return handler(c)
+// Not returns a predicate which is true if the predicate is false.
+func Not(predicate func(c fiber.Ctx) bool) func(c fiber.Ctx) bool {
+	return func(c fiber.Ctx) bool {
+		return !predicate(c)
 	}
 }
@@ -1,6 +1,7 @@
 package skip_test
 
 import (
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
@@ -55,3 +56,61 @@ func helloWorldHandler(c fiber.Ctx) error {
 func errTeapotHandler(fiber.Ctx) error {
 	return fiber.ErrTeapot
 }
+
+// go test -run Test_Only
+func Test_Only(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	app.Use(skip.Only(errTeapotHandler, skip.Paths("/admin/*")))
+	app.Get("/*", helloWorldHandler)
+
+	for path, status := range map[string]int{"/": fiber.StatusOK, "/admin/users": fiber.StatusTeapot, "/ADMIN/": fiber.StatusTeapot} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		require.NoError(t, err)
+		require.Equal(t, status, resp.StatusCode, path)
+	}
+}
+
+// go test -run Test_Skip_Predicates
+func Test_Skip_Predicates(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("trusted", c.Query("trusted") == "1")
+		return c.Next()
+	})
+	app.Use(fiber.Skip(skip.Any(
+		skip.Methods("options"),
+		skip.Header("X-Probe", "live", "ready"),
+		skip.Locals("trusted"),
+	)), errTeapotHandler)
+	app.Use(fiber.Only(skip.Not(skip.Header("Authorization"))), func(c fiber.Ctx) error {
+		c.Set("X-Anonymous", "1")
+		return c.Next()
+	})
+	app.All("/", helloWorldHandler)
+
+	request := func(method, target string, headers map[string]string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest(method, target, nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	require.Equal(t, fiber.StatusTeapot, request(fiber.MethodGet, "/", nil).StatusCode)
+	require.Equal(t, fiber.StatusOK, request(fiber.MethodOptions, "/", nil).StatusCode)
+	require.Equal(t, fiber.StatusOK, request(fiber.MethodGet, "/", map[string]string{"X-Probe": "ready"}).StatusCode)
+	require.Equal(t, fiber.StatusTeapot, request(fiber.MethodGet, "/", map[string]string{"X-Probe": "startup"}).StatusCode)
+
+	resp := request(fiber.MethodGet, "/?trusted=1", nil)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "1", resp.Header.Get("X-Anonymous"))
+	resp = request(fiber.MethodGet, "/?trusted=1", map[string]string{"Authorization": "Bearer token"})
+	require.Empty(t, resp.Header.Get("X-Anonymous"))
+}
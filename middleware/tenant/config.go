@@ -0,0 +1,84 @@
+package tenant
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Resolvers resolve the ID of the tenant of a request. They are called in
+	// order, the first ID which isn't empty is used.
+	//
+	// Optional. Default: []Resolver{FromHeader("X-Tenant-ID")}
+	Resolvers []Resolver
+
+	// Lookup returns the tenant with the given ID, e.g. from a database.
+	// Requests of unknown tenants, for which it returns nil, are rejected with ErrUnknownTenant.
+	//
+	// Optional. Default: a tenant with the ID and without limits
+	Lookup func(id string) (*Tenant, error)
+
+	// Optional allows requests without a tenant, FromContext returns nil for them.
+	// Otherwise they are rejected with ErrMissingTenant.
+	//
+	// Optional. Default: false
+	Optional bool
+
+	// LimitWindow is the window of the MaxRequests of the tenants.
+	//
+	// Optional. Default: 1 * time.Minute
+	LimitWindow time.Duration
+
+	// LimitReached is called when a tenant exceeded its MaxRequests.
+	//
+	// Optional. Default: func(c fiber.Ctx) error {
+	//   return c.SendStatus(fiber.StatusTooManyRequests)
+	// }
+	LimitReached fiber.Handler
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:      nil,
+	Resolvers: []Resolver{FromHeader(HeaderTenantID)},
+	Lookup: func(id string) (*Tenant, error) {
+		return &Tenant{ID: id}, nil
+	},
+	LimitWindow: 1 * time.Minute,
+	LimitReached: func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusTooManyRequests)
+	},
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if len(cfg.Resolvers) == 0 {
+		cfg.Resolvers = ConfigDefault.Resolvers
+	}
+	if cfg.Lookup == nil {
+		cfg.Lookup = ConfigDefault.Lookup
+	}
+	if cfg.LimitWindow <= 0 {
+		cfg.LimitWindow = ConfigDefault.LimitWindow
+	}
+	if cfg.LimitReached == nil {
+		cfg.LimitReached = ConfigDefault.LimitReached
+	}
+	return cfg
+}
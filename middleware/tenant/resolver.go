@@ -0,0 +1,89 @@
+package tenant
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/utils/v2"
+)
+
+// HeaderTenantID is the header of the default resolver.
+const HeaderTenantID = "X-Tenant-ID"
+
+// Resolver returns the tenant ID of a request, or an empty string if it is not found.
+type Resolver func(c fiber.Ctx) string
+
+// FromSubdomain resolves the tenant ID from the first subdomain of the host,
+// e.g. "acme" for "acme.example.com". The offset is the number of the parts of the
+// domain, see c.Subdomains.
+func FromSubdomain(offset ...int) Resolver {
+	return func(c fiber.Ctx) string {
+		if subdomains := c.Subdomains(offset...); len(subdomains) > 0 {
+			return utils.CopyString(subdomains[0])
+		}
+		return ""
+	}
+}
+
+// FromHeader resolves the tenant ID from the request header.
+func FromHeader(header string) Resolver {
+	return func(c fiber.Ctx) string {
+		return utils.CopyString(c.Get(header))
+	}
+}
+
+// FromPath resolves the tenant ID from the path segment after the prefix,
+// e.g. "acme" for "/tenants/acme/users" with the prefix "/tenants/".
+func FromPath(prefix string) Resolver {
+	return func(c fiber.Ctx) string {
+		path := c.Path()
+		if !strings.HasPrefix(path, prefix) {
+			return ""
+		}
+		id := path[len(prefix):]
+		if i := strings.IndexByte(id, '/'); i >= 0 {
+			id = id[:i]
+		}
+		return utils.CopyString(id)
+	}
+}
+
+// FromClaim resolves the tenant ID from a claim of the bearer JWT of the Authorization header.
+// The signature of the token is NOT verified, the token must be verified by an
+// authentication middleware which is executed before this middleware.
+func FromClaim(claim string) Resolver {
+	return func(c fiber.Ctx) string {
+		auth := c.Get(fiber.HeaderAuthorization)
+		if len(auth) < 7 || !utils.EqualFold(auth[:7], "bearer ") {
+			return ""
+		}
+
+		parts := strings.Split(auth[7:], ".")
+		if len(parts) != 3 {
+			return ""
+		}
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			return ""
+		}
+
+		var claims map[string]any
+		dec := json.NewDecoder(bytes.NewReader(payload))
+		dec.UseNumber()
+		if err := dec.Decode(&claims); err != nil {
+			return ""
+		}
+
+		switch value := claims[claim].(type) {
+		case string:
+			return value
+		case json.Number:
+			return value.String()
+		default:
+			return ""
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package tenant
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ErrResetNotSupported is returned by the Reset method of the storage of a tenant,
+// the keys of a tenant can't be listed with a fiber.Storage.
+var ErrResetNotSupported = errors.New("tenant: reset of the tenant storage is not supported")
+
+// prefixStorage prefixes the keys of a storage.
+type prefixStorage struct {
+	storage fiber.Storage
+	prefix  string
+}
+
+// Get gets the value of the prefixed key.
+func (s *prefixStorage) Get(key string) ([]byte, error) {
+	return s.storage.Get(s.prefix + key)
+}
+
+// Set stores the value with the prefixed key.
+func (s *prefixStorage) Set(key string, val []byte, exp time.Duration) error {
+	if key == "" {
+		return nil
+	}
+	return s.storage.Set(s.prefix+key, val, exp)
+}
+
+// Delete deletes the value of the prefixed key.
+func (s *prefixStorage) Delete(key string) error {
+	return s.storage.Delete(s.prefix + key)
+}
+
+// Reset returns ErrResetNotSupported, it would delete the keys of all tenants.
+func (*prefixStorage) Reset() error {
+	return ErrResetNotSupported
+}
+
+// Close doesn't close the shared storage.
+func (*prefixStorage) Close() error {
+	return nil
+}
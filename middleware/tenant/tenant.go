@@ -0,0 +1,143 @@
+package tenant
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+// The keys for the values in context
+const (
+	tenantKey contextKey = iota
+)
+
+// LabelTenant is the name of the metrics label which contains the tenant ID, see Labels.
+const LabelTenant = "tenant"
+
+// Errors of the middleware
+var (
+	// ErrMissingTenant is returned when no resolver found a tenant ID.
+	ErrMissingTenant = fiber.NewError(fiber.StatusBadRequest, "tenant: missing tenant")
+	// ErrUnknownTenant is returned when the lookup didn't find the tenant.
+	ErrUnknownTenant = fiber.NewError(fiber.StatusNotFound, "tenant: unknown tenant")
+)
+
+// Tenant is a customer of a multi-tenant app.
+type Tenant struct {
+	// Config contains the settings of the tenant, e.g. its plan or feature flags.
+	Config map[string]any `json:"config,omitempty"`
+	// ID identifies the tenant.
+	ID string `json:"id"`
+	// MaxRequests is the maximum number of requests of the tenant per LimitWindow,
+	// 0 means unlimited.
+	MaxRequests int `json:"max_requests,omitempty"`
+}
+
+// Key returns the storage key of the tenant, e.g. "tenant:acme:sessions" for "sessions".
+func (t *Tenant) Key(key string) string {
+	return "tenant:" + t.ID + ":" + key
+}
+
+// Storage returns a storage whose keys are prefixed with the key prefix of the tenant,
+// so the tenants can share a storage without seeing the data of each other.
+func (t *Tenant) Storage(storage fiber.Storage) fiber.Storage {
+	return &prefixStorage{storage: storage, prefix: t.Key("")}
+}
+
+// window counts the requests of a tenant in the current limit window.
+type window struct {
+	start time.Time
+	count int
+}
+
+// New creates a new middleware handler
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	var mutex sync.Mutex
+	windows := make(map[string]*window)
+
+	// allow counts the request of the tenant and reports if it is within its limit.
+	allow := func(t *Tenant) bool {
+		if t.MaxRequests <= 0 {
+			return true
+		}
+
+		now := time.Now()
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		w, ok := windows[t.ID]
+		if !ok || now.Sub(w.start) >= cfg.LimitWindow {
+			w = &window{start: now}
+			windows[t.ID] = w
+		}
+		if w.count >= t.MaxRequests {
+			return false
+		}
+		w.count++
+		return true
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		var id string
+		for _, resolve := range cfg.Resolvers {
+			if id = resolve(c); id != "" {
+				break
+			}
+		}
+		if id == "" {
+			if cfg.Optional {
+				return c.Next()
+			}
+			return ErrMissingTenant
+		}
+
+		t, err := cfg.Lookup(id)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			return ErrUnknownTenant
+		}
+
+		if !allow(t) {
+			return cfg.LimitReached(c)
+		}
+
+		c.Locals(tenantKey, t)
+
+		return c.Next()
+	}
+}
+
+// FromContext returns the tenant of the request.
+// If there is no tenant, nil is returned.
+func FromContext(c fiber.Ctx) *Tenant {
+	if t, ok := c.Locals(tenantKey).(*Tenant); ok {
+		return t
+	}
+	return nil
+}
+
+// Labels returns the metrics labels of the tenant of the request, e.g. for
+// Prometheus counters. The label is empty if there is no tenant.
+func Labels(c fiber.Ctx) map[string]string {
+	id := ""
+	if t := FromContext(c); t != nil {
+		id = t.ID
+	}
+	return map[string]string{LabelTenant: id}
+}
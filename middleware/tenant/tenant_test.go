@@ -0,0 +1,268 @@
+package tenant
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+// tenantHandler responds with the ID of the tenant.
+func tenantHandler(c fiber.Ctx) error {
+	t := FromContext(c)
+	if t == nil {
+		return c.SendString("none")
+	}
+	return c.SendString(t.ID)
+}
+
+// testTenant sends the request and returns the status code and the body.
+func testTenant(t *testing.T, app *fiber.App, req *http.Request) (int, string) {
+	t.Helper()
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+// go test -run Test_Tenant
+func Test_Tenant(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New())
+	app.Get("/", tenantHandler)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(HeaderTenantID, "acme")
+	status, body := testTenant(t, app, req)
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "acme", body)
+
+	// the tenant is required by default
+	status, _ = testTenant(t, app, httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.Equal(t, fiber.StatusBadRequest, status)
+}
+
+// go test -run Test_Tenant_Optional
+func Test_Tenant_Optional(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Optional: true}))
+	app.Get("/", tenantHandler)
+
+	status, body := testTenant(t, app, httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "none", body)
+}
+
+// go test -run Test_Tenant_Next
+func Test_Tenant_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Next: func(fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Get("/", tenantHandler)
+
+	status, body := testTenant(t, app, httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.Equal(t, fiber.StatusOK, status)
+	require.Equal(t, "none", body)
+}
+
+// go test -run Test_Tenant_Lookup
+func Test_Tenant_Lookup(t *testing.T) {
+	t.Parallel()
+	errDatabase := errors.New("database is down")
+	tenants := map[string]*Tenant{
+		"acme": {ID: "acme", Config: map[string]any{"plan": "pro"}},
+	}
+
+	app := fiber.New()
+	app.Use(New(Config{
+		Lookup: func(id string) (*Tenant, error) {
+			if id == "broken" {
+				return nil, errDatabase
+			}
+			return tenants[id], nil
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		plan, ok := FromContext(c).Config["plan"].(string)
+		require.True(t, ok)
+		return c.SendString(plan)
+	})
+
+	for id, want := range map[string]int{
+		"acme":    fiber.StatusOK,
+		"unknown": fiber.StatusNotFound,
+		"broken":  fiber.StatusInternalServerError,
+	} {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(HeaderTenantID, id)
+		status, body := testTenant(t, app, req)
+		require.Equal(t, want, status, id)
+		if want == fiber.StatusOK {
+			require.Equal(t, "pro", body)
+		}
+	}
+}
+
+// go test -run Test_Tenant_Resolvers
+func Test_Tenant_Resolvers(t *testing.T) {
+	t.Parallel()
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"john","tenant":"claimed","org":42}`))
+	token := "Bearer header." + payload + ".signature"
+
+	tests := []struct {
+		name     string
+		resolver Resolver
+		target   string
+		header   string
+		value    string
+		want     string
+	}{
+		{name: "subdomain", resolver: FromSubdomain(), target: "http://acme.example.com/", want: "acme"},
+		{name: "no subdomain", resolver: FromSubdomain(), target: "http://example.com/", want: "none"},
+		{name: "header", resolver: FromHeader("X-Org"), target: "/", header: "X-Org", value: "acme", want: "acme"},
+		{name: "path", resolver: FromPath("/tenants/"), target: "/tenants/acme/users", want: "acme"},
+		{name: "path end", resolver: FromPath("/tenants/"), target: "/tenants/acme", want: "acme"},
+		{name: "other path", resolver: FromPath("/tenants/"), target: "/users", want: "none"},
+		{name: "claim", resolver: FromClaim("tenant"), target: "/", header: fiber.HeaderAuthorization, value: token, want: "claimed"},
+		{name: "number claim", resolver: FromClaim("org"), target: "/", header: fiber.HeaderAuthorization, value: token, want: "42"},
+		{name: "missing claim", resolver: FromClaim("org_id"), target: "/", header: fiber.HeaderAuthorization, value: token, want: "none"},
+		{name: "invalid token", resolver: FromClaim("tenant"), target: "/", header: fiber.HeaderAuthorization, value: "Bearer invalid", want: "none"},
+		{name: "basic auth", resolver: FromClaim("tenant"), target: "/", header: fiber.HeaderAuthorization, value: "Basic am9objpkb2U=", want: "none"},
+	}
+
+	for _, tt := range tests {
+		app := fiber.New()
+		app.Use(New(Config{Resolvers: []Resolver{tt.resolver}, Optional: true}))
+		app.Get("/*", tenantHandler)
+
+		req := httptest.NewRequest(fiber.MethodGet, tt.target, nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		status, body := testTenant(t, app, req)
+		require.Equal(t, fiber.StatusOK, status, tt.name)
+		require.Equal(t, tt.want, body, tt.name)
+	}
+}
+
+// go test -run Test_Tenant_ResolverOrder
+func Test_Tenant_ResolverOrder(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Resolvers: []Resolver{FromHeader("X-Org"), FromSubdomain()}}))
+	app.Get("/", tenantHandler)
+
+	req := httptest.NewRequest(fiber.MethodGet, "http://acme.example.com/", nil)
+	_, body := testTenant(t, app, req)
+	require.Equal(t, "acme", body)
+
+	req.Header.Set("X-Org", "globex")
+	_, body = testTenant(t, app, req)
+	require.Equal(t, "globex", body)
+}
+
+// go test -run Test_Tenant_MaxRequests
+func Test_Tenant_MaxRequests(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		Lookup: func(id string) (*Tenant, error) {
+			if id == "free" {
+				return &Tenant{ID: id, MaxRequests: 2}, nil
+			}
+			return &Tenant{ID: id}, nil
+		},
+		LimitWindow: 100 * time.Millisecond,
+	}))
+	app.Get("/", tenantHandler)
+
+	send := func(id string) int {
+		req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+		req.Header.Set(HeaderTenantID, id)
+		status, _ := testTenant(t, app, req)
+		return status
+	}
+
+	require.Equal(t, fiber.StatusOK, send("free"))
+	require.Equal(t, fiber.StatusOK, send("free"))
+	require.Equal(t, fiber.StatusTooManyRequests, send("free"))
+	// the limits are per tenant
+	for i := 0; i < 5; i++ {
+		require.Equal(t, fiber.StatusOK, send("enterprise"))
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	require.Equal(t, fiber.StatusOK, send("free"))
+}
+
+// go test -run Test_Tenant_Storage
+func Test_Tenant_Storage(t *testing.T) {
+	t.Parallel()
+	shared := memory.New()
+	acme := &Tenant{ID: "acme"}
+	globex := &Tenant{ID: "globex"}
+
+	require.Equal(t, "tenant:acme:sessions", acme.Key("sessions"))
+
+	storage := acme.Storage(shared)
+	require.NoError(t, storage.Set("session", []byte("1"), 0))
+
+	val, err := storage.Get("session")
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), val)
+
+	val, err = shared.Get("tenant:acme:session")
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), val)
+
+	// the tenants don't see the data of each other
+	val, err = globex.Storage(shared).Get("session")
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	require.NoError(t, storage.Delete("session"))
+	val, err = shared.Get("tenant:acme:session")
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	require.ErrorIs(t, storage.Reset(), ErrResetNotSupported)
+	require.NoError(t, storage.Close())
+	require.NoError(t, shared.Set("other", []byte("1"), 0))
+}
+
+// go test -run Test_Tenant_Labels
+func Test_Tenant_Labels(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{Optional: true}))
+
+	var labels []map[string]string
+	app.Get("/", func(c fiber.Ctx) error {
+		labels = append(labels, Labels(c))
+		return nil
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(HeaderTenantID, "acme")
+	testTenant(t, app, req)
+	testTenant(t, app, httptest.NewRequest(fiber.MethodGet, "/", nil))
+
+	require.Equal(t, []map[string]string{
+		{LabelTenant: "acme"},
+		{LabelTenant: ""},
+	}, labels)
+}
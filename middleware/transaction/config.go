@@ -0,0 +1,82 @@
+package transaction
+
+import (
+	"database/sql"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// Driver begins the transactions, e.g. SQL for a *sql.DB. It is required.
+	//
+	// Required. Default: nil
+	Driver Driver
+
+	// ReadOnly reports if the route of the request only reads, its transaction
+	// is started read-only then, e.g. so it can be sent to a replica.
+	//
+	// Optional. Default: true for GET, HEAD and OPTIONS requests
+	ReadOnly func(c fiber.Ctx) bool
+
+	// Commit reports if the transaction is committed after the handlers returned.
+	// The transaction is always rolled back if a handler returned an error or panicked.
+	//
+	// Optional. Default: true for 2xx and 3xx responses
+	Commit func(c fiber.Ctx) bool
+
+	// Isolation is the isolation level of the transactions.
+	//
+	// Optional. Default: sql.LevelDefault
+	Isolation sql.IsolationLevel
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:      nil,
+	Driver:    nil,
+	ReadOnly:  safeMethod,
+	Commit:    successStatus,
+	Isolation: sql.LevelDefault,
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.ReadOnly == nil {
+		cfg.ReadOnly = ConfigDefault.ReadOnly
+	}
+	if cfg.Commit == nil {
+		cfg.Commit = ConfigDefault.Commit
+	}
+	return cfg
+}
+
+// safeMethod reports if the request has a safe method.
+func safeMethod(c fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// successStatus reports if the response has a 2xx or 3xx status.
+func successStatus(c fiber.Ctx) bool {
+	status := c.Response().StatusCode()
+	return status >= fiber.StatusOK && status < fiber.StatusBadRequest
+}
@@ -0,0 +1,50 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Tx is a database transaction.
+type Tx interface {
+	Commit() error
+	Rollback() error
+}
+
+// Options are the options of a transaction.
+type Options struct {
+	// Isolation is the isolation level of the transaction.
+	Isolation sql.IsolationLevel
+	// ReadOnly reports if the transaction only reads.
+	ReadOnly bool
+}
+
+// Driver begins the transactions of the requests. Implement it for the database
+// library of your choice, SQL adapts a *sql.DB.
+type Driver interface {
+	Begin(ctx context.Context, opts Options) (Tx, error)
+}
+
+// SQL returns a driver which begins *sql.Tx transactions of the database.
+//
+//	app.Use(transaction.New(transaction.Config{Driver: transaction.SQL(db)}))
+//
+//	tx := transaction.FromContext(c).(*sql.Tx)
+func SQL(db *sql.DB) Driver {
+	return sqlDriver{db: db}
+}
+
+// sqlDriver is the driver of a *sql.DB.
+type sqlDriver struct {
+	db *sql.DB
+}
+
+// Begin implements Driver.
+func (d sqlDriver) Begin(ctx context.Context, opts Options) (Tx, error) {
+	tx, err := d.db.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,81 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// The contextKey type is unexported to prevent collisions with context keys defined in
+// other packages.
+type contextKey int
+
+// The keys for the values in context
+const (
+	txKey contextKey = iota
+)
+
+// New creates a new middleware handler which runs the handlers of a request in a
+// transaction. The transaction is committed if the response is successful and rolled
+// back if a handler returned an error or panicked.
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	if cfg.Driver == nil {
+		panic("transaction: a driver is required")
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		tx, err := cfg.Driver.Begin(c.UserContext(), Options{
+			Isolation: cfg.Isolation,
+			ReadOnly:  cfg.ReadOnly(c),
+		})
+		if err != nil {
+			return fmt.Errorf("transaction: %w", err)
+		}
+		c.Locals(txKey, tx)
+
+		// Roll back and repanic, so the recover middleware handles the panic
+		defer func() {
+			if r := recover(); r != nil {
+				_ = tx.Rollback() //nolint:errcheck // the panic is more important
+				panic(r)
+			}
+		}()
+
+		if err := c.Next(); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return errors.Join(err, fmt.Errorf("transaction: failed to roll back: %w", rollbackErr))
+			}
+			return err
+		}
+
+		if !cfg.Commit(c) {
+			if err := tx.Rollback(); err != nil {
+				return fmt.Errorf("transaction: failed to roll back: %w", err)
+			}
+			return nil
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("transaction: failed to commit: %w", err)
+		}
+		return nil
+	}
+}
+
+// FromContext returns the transaction of the request.
+// If there is no transaction, nil is returned.
+func FromContext(c fiber.Ctx) Tx {
+	if tx, ok := c.Locals(txKey).(Tx); ok {
+		return tx
+	}
+	return nil
+}
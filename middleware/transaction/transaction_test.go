@@ -0,0 +1,196 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/recover"
+	"github.com/stretchr/testify/require"
+)
+
+// testTx records how the transaction ended.
+type testTx struct {
+	opts   Options
+	result string
+}
+
+func (tx *testTx) Commit() error {
+	tx.result = "commit"
+	return nil
+}
+
+func (tx *testTx) Rollback() error {
+	tx.result = "rollback"
+	return nil
+}
+
+// testDriver records the transactions.
+type testDriver struct {
+	err error
+	txs []*testTx
+	mu  sync.Mutex
+}
+
+func (d *testDriver) Begin(_ context.Context, opts Options) (Tx, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tx := &testTx{opts: opts}
+	d.txs = append(d.txs, tx)
+	return tx, nil
+}
+
+func (d *testDriver) last() *testTx {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.txs[len(d.txs)-1]
+}
+
+func newTestApp(t *testing.T, driver Driver, config ...Config) *fiber.App {
+	t.Helper()
+	cfg := Config{}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	cfg.Driver = driver
+
+	app := fiber.New()
+	app.Use(recover.New())
+	app.Use(New(cfg))
+	app.All("/:status", func(c fiber.Ctx) error {
+		require.NotNil(t, FromContext(c))
+		switch c.Params("status") {
+		case "error":
+			return errors.New("failed")
+		case "panic":
+			panic("failed")
+		case "redirect":
+			return c.Redirect().To("/")
+		case "bad":
+			return c.SendStatus(fiber.StatusBadRequest)
+		default:
+			return c.SendStatus(fiber.StatusOK)
+		}
+	})
+	return app
+}
+
+// go test -run Test_Transaction
+func Test_Transaction(t *testing.T) {
+	t.Parallel()
+	driver := &testDriver{}
+	app := newTestApp(t, driver)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		result string
+	}{
+		{method: fiber.MethodPost, path: "/ok", status: fiber.StatusOK, result: "commit"},
+		{method: fiber.MethodPost, path: "/redirect", status: fiber.StatusFound, result: "commit"},
+		{method: fiber.MethodPost, path: "/bad", status: fiber.StatusBadRequest, result: "rollback"},
+		{method: fiber.MethodPost, path: "/error", status: fiber.StatusInternalServerError, result: "rollback"},
+		{method: fiber.MethodPost, path: "/panic", status: fiber.StatusInternalServerError, result: "rollback"},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+		require.NoError(t, err)
+		require.Equal(t, tt.status, resp.StatusCode, tt.path)
+		require.Equal(t, tt.result, driver.last().result, tt.path)
+		require.False(t, driver.last().opts.ReadOnly, tt.path)
+	}
+}
+
+// go test -run Test_Transaction_ReadOnly
+func Test_Transaction_ReadOnly(t *testing.T) {
+	t.Parallel()
+	driver := &testDriver{}
+	app := newTestApp(t, driver)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ok", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.True(t, driver.last().opts.ReadOnly)
+
+	// custom read-only routes and isolation level
+	driver = &testDriver{}
+	app = newTestApp(t, driver, Config{
+		ReadOnly: func(c fiber.Ctx) bool {
+			return c.Path() == "/ok"
+		},
+		Isolation: sql.LevelSerializable,
+	})
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodPost, "/ok", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, Options{Isolation: sql.LevelSerializable, ReadOnly: true}, driver.last().opts)
+}
+
+// go test -run Test_Transaction_Commit
+func Test_Transaction_Commit(t *testing.T) {
+	t.Parallel()
+	driver := &testDriver{}
+	app := newTestApp(t, driver, Config{
+		Commit: func(c fiber.Ctx) bool {
+			return c.Response().StatusCode() == fiber.StatusBadRequest
+		},
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/bad", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	require.Equal(t, "commit", driver.last().result)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodPost, "/ok", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "rollback", driver.last().result)
+}
+
+// go test -run Test_Transaction_BeginError
+func Test_Transaction_BeginError(t *testing.T) {
+	t.Parallel()
+	app := newTestApp(t, &testDriver{err: errors.New("connection refused")})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/ok", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+// go test -run Test_Transaction_Next
+func Test_Transaction_Next(t *testing.T) {
+	t.Parallel()
+	driver := &testDriver{}
+	app := fiber.New()
+	app.Use(New(Config{
+		Driver: driver,
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+	app.Get("/", func(c fiber.Ctx) error {
+		require.Nil(t, FromContext(c))
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Empty(t, driver.txs)
+}
+
+// go test -run Test_Transaction_NoDriver
+func Test_Transaction_NoDriver(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New()
+	})
+}
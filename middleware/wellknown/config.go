@@ -0,0 +1,101 @@
+package wellknown
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+)
+
+// Config defines the config for middleware.
+type Config struct {
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(c fiber.Ctx) bool
+
+	// SecurityTxt is served at "/.well-known/security.txt" (RFC 9116).
+	//
+	// Optional. Default: nil
+	SecurityTxt *SecurityTxt
+
+	// ChangePasswordURL is the URL of the page to change the password, which
+	// "/.well-known/change-password" redirects to.
+	//
+	// Optional. Default: ""
+	ChangePasswordURL string
+
+	// OpenIDConfiguration is the OpenID Connect discovery document which is served at
+	// "/.well-known/openid-configuration".
+	//
+	// Optional. Default: nil
+	OpenIDConfiguration map[string]any
+
+	// OpenIDConfigurationURL is the URL of the discovery document of an identity provider,
+	// which is fetched and served at "/.well-known/openid-configuration" instead of OpenIDConfiguration.
+	//
+	// Optional. Default: ""
+	OpenIDConfigurationURL string
+
+	// OpenIDConfigurationTTL is the duration for which the fetched discovery document is cached.
+	// If it can't be fetched, the previous document is served.
+	//
+	// Optional. Default: 1 * time.Hour
+	OpenIDConfigurationTTL time.Duration
+
+	// Client fetches the discovery document of OpenIDConfigurationURL.
+	//
+	// Optional. Default: a new fasthttp.Client
+	Client *fasthttp.Client
+
+	// AppleAppSiteAssociation is served as JSON at "/.well-known/apple-app-site-association",
+	// e.g. a map with the "applinks" and "webcredentials" of the apps.
+	//
+	// Optional. Default: nil
+	AppleAppSiteAssociation any
+
+	// AssetLinks are served at "/.well-known/assetlinks.json" for the Digital Asset Links of Android apps.
+	//
+	// Optional. Default: nil
+	AssetLinks []AssetLink
+
+	// Documents are additional documents by their name below "/.well-known/", e.g. "nodeinfo".
+	//
+	// Optional. Default: nil
+	Documents map[string]Document
+
+	// CacheControl is the Cache-Control header of the documents.
+	//
+	// Optional. Default: "public, max-age=86400"
+	CacheControl string
+}
+
+// ConfigDefault is the default config
+var ConfigDefault = Config{
+	Next:                   nil,
+	OpenIDConfigurationTTL: time.Hour,
+	CacheControl:           "public, max-age=86400",
+}
+
+// Helper function to set default values
+func configDefault(config ...Config) Config {
+	// Return default config if nothing provided
+	if len(config) < 1 {
+		return ConfigDefault
+	}
+
+	// Override default config
+	cfg := config[0]
+
+	// Set default values
+	if cfg.OpenIDConfigurationTTL <= 0 {
+		cfg.OpenIDConfigurationTTL = ConfigDefault.OpenIDConfigurationTTL
+	}
+	if cfg.Client == nil {
+		cfg.Client = &fasthttp.Client{}
+	}
+	if cfg.CacheControl == "" {
+		cfg.CacheControl = ConfigDefault.CacheControl
+	}
+	return cfg
+}
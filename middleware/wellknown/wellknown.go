@@ -0,0 +1,249 @@
+package wellknown
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/valyala/fasthttp"
+)
+
+// Prefix is the path prefix of the well-known documents (RFC 8615).
+const Prefix = "/.well-known/"
+
+const (
+	hAllow = "GET, HEAD, OPTIONS"
+	hZero  = "0"
+)
+
+// openIDFetchTimeout is the timeout to fetch the discovery document of OpenIDConfigurationURL.
+const openIDFetchTimeout = 10 * time.Second
+
+// SecurityTxt is the security.txt of RFC 9116, which tells security researchers how to report vulnerabilities.
+type SecurityTxt struct {
+	// Expires is the date after which the data is considered stale.
+	// Default: one year after the middleware was created
+	Expires time.Time
+	// Contact are the URIs to report vulnerabilities, e.g. "mailto:security@example.com". It is required.
+	Contact []string
+	// Encryption are the URIs of the keys to encrypt the reports.
+	Encryption []string
+	// Acknowledgments are the URIs of the pages which acknowledge the researchers.
+	Acknowledgments []string
+	// PreferredLanguages are the language tags of the reports, e.g. "en".
+	PreferredLanguages []string
+	// Canonical are the URIs where the security.txt is located.
+	Canonical []string
+	// Policy are the URIs of the vulnerability disclosure policies.
+	Policy []string
+	// Hiring are the URIs of the security-related job positions.
+	Hiring []string
+}
+
+// String returns the security.txt, one field per line.
+func (s *SecurityTxt) String() string {
+	var sb strings.Builder
+	write := func(field string, values []string) {
+		for _, v := range values {
+			sb.WriteString(field + ": " + v + "\n")
+		}
+	}
+	write("Contact", s.Contact)
+	sb.WriteString("Expires: " + s.Expires.UTC().Format(time.RFC3339) + "\n")
+	write("Encryption", s.Encryption)
+	write("Acknowledgments", s.Acknowledgments)
+	if len(s.PreferredLanguages) > 0 {
+		write("Preferred-Languages", []string{strings.Join(s.PreferredLanguages, ", ")})
+	}
+	write("Canonical", s.Canonical)
+	write("Policy", s.Policy)
+	write("Hiring", s.Hiring)
+	return sb.String()
+}
+
+// AssetLink is a statement of the Digital Asset Links of Android apps.
+type AssetLink struct {
+	// Target is the app or the website of the statement.
+	Target AssetLinkTarget `json:"target"`
+	// Relation are the granted relations, e.g. "delegate_permission/common.handle_all_urls".
+	Relation []string `json:"relation"`
+}
+
+// AssetLinkTarget is the target of an AssetLink.
+type AssetLinkTarget struct {
+	// Namespace is "android_app" or "web".
+	Namespace string `json:"namespace"`
+	// PackageName is the package name of the Android app.
+	PackageName string `json:"package_name,omitempty"`
+	// Site is the URL of the website.
+	Site string `json:"site,omitempty"`
+	// SHA256CertFingerprints are the fingerprints of the signing certificates of the Android app.
+	SHA256CertFingerprints []string `json:"sha256_cert_fingerprints,omitempty"`
+}
+
+// Document is a well-known document.
+type Document struct {
+	// ContentType is the content type of the document, e.g. "application/json".
+	ContentType string
+	// Body is the content of the document.
+	Body []byte
+}
+
+// New creates a new middleware handler, which serves the configured documents below "/.well-known/".
+func New(config ...Config) fiber.Handler {
+	// Set default config
+	cfg := configDefault(config...)
+
+	// sources return the documents by their name below the prefix
+	sources := make(map[string]func() (Document, error), len(cfg.Documents)+4)
+	static := func(name string, document Document) {
+		sources[name] = func() (Document, error) {
+			return document, nil
+		}
+	}
+	if cfg.SecurityTxt != nil {
+		if len(cfg.SecurityTxt.Contact) == 0 {
+			panic("wellknown: security.txt requires a contact")
+		}
+		securityTxt := *cfg.SecurityTxt
+		if securityTxt.Expires.IsZero() {
+			securityTxt.Expires = time.Now().AddDate(1, 0, 0).Truncate(time.Second)
+		}
+		static("security.txt", Document{ContentType: fiber.MIMETextPlainCharsetUTF8, Body: []byte(securityTxt.String())})
+	}
+	if cfg.OpenIDConfiguration != nil {
+		static("openid-configuration", jsonDocument("openid-configuration", cfg.OpenIDConfiguration))
+	}
+	if cfg.OpenIDConfigurationURL != "" {
+		proxy := &openIDProxy{url: cfg.OpenIDConfigurationURL, ttl: cfg.OpenIDConfigurationTTL, client: cfg.Client}
+		sources["openid-configuration"] = proxy.document
+	}
+	if cfg.AppleAppSiteAssociation != nil {
+		static("apple-app-site-association", jsonDocument("apple-app-site-association", cfg.AppleAppSiteAssociation))
+	}
+	if cfg.AssetLinks != nil {
+		static("assetlinks.json", jsonDocument("assetlinks.json", cfg.AssetLinks))
+	}
+	for name, document := range cfg.Documents {
+		static(strings.TrimPrefix(name, "/"), document)
+	}
+
+	// redirects are the URLs which the documents redirect to
+	redirects := make(map[string]string, 1)
+	if cfg.ChangePasswordURL != "" {
+		redirects["change-password"] = cfg.ChangePasswordURL
+	}
+
+	// Return new handler
+	return func(c fiber.Ctx) error {
+		// Don't execute middleware if Next returns true
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		name, found := strings.CutPrefix(c.Path(), Prefix)
+		if !found {
+			return c.Next()
+		}
+		redirect, isRedirect := redirects[name]
+		source, ok := sources[name]
+		if !isRedirect && !ok {
+			return c.Next()
+		}
+
+		// Only allow GET, HEAD and OPTIONS requests
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			if c.Method() != fiber.MethodOptions {
+				c.Status(fiber.StatusMethodNotAllowed)
+			} else {
+				c.Status(fiber.StatusOK)
+			}
+			c.Set(fiber.HeaderAllow, hAllow)
+			c.Set(fiber.HeaderContentLength, hZero)
+			return nil
+		}
+
+		if isRedirect {
+			return c.Redirect().Status(fiber.StatusFound).To(redirect)
+		}
+
+		document, err := source()
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, document.ContentType)
+		c.Set(fiber.HeaderCacheControl, cfg.CacheControl)
+		return c.Send(document.Body)
+	}
+}
+
+// jsonDocument encodes the value as JSON document.
+func jsonDocument(name string, v any) Document {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("wellknown: failed to encode %s: %v", name, err))
+	}
+	return Document{ContentType: fiber.MIMEApplicationJSON, Body: body}
+}
+
+// openIDProxy fetches and caches the discovery document of an identity provider.
+type openIDProxy struct {
+	fetched time.Time
+	client  *fasthttp.Client
+	url     string
+	body    []byte
+	ttl     time.Duration
+	mutex   sync.Mutex
+}
+
+// document returns the cached document, which is fetched again after the TTL.
+// If it can't be fetched, the previous document is returned.
+func (p *openIDProxy) document() (Document, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.body == nil || time.Since(p.fetched) >= p.ttl {
+		body, err := p.fetch()
+		switch {
+		case err == nil:
+			p.body = body
+		case p.body == nil:
+			log.Errorw("wellknown: failed to fetch the OpenID configuration", "url", p.url, "error", err)
+			return Document{}, fiber.NewError(fiber.StatusBadGateway, "failed to fetch the OpenID configuration")
+		default:
+			log.Errorw("wellknown: failed to fetch the OpenID configuration, the previous document is served", "url", p.url, "error", err)
+		}
+		// a failed fetch is retried after the TTL
+		p.fetched = time.Now()
+	}
+
+	return Document{ContentType: fiber.MIMEApplicationJSON, Body: p.body}, nil
+}
+
+// fetch fetches the discovery document.
+func (p *openIDProxy) fetch() ([]byte, error) {
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(p.url)
+	req.Header.SetMethod(fiber.MethodGet)
+	req.Header.Set(fiber.HeaderAccept, fiber.MIMEApplicationJSON)
+	if err := p.client.DoTimeout(req, resp, openIDFetchTimeout); err != nil {
+		return nil, fmt.Errorf("wellknown: failed to fetch %s: %w", p.url, err)
+	}
+	if resp.StatusCode() != fiber.StatusOK {
+		return nil, fmt.Errorf("wellknown: failed to fetch %s: status %d", p.url, resp.StatusCode())
+	}
+	body := append([]byte(nil), resp.Body()...)
+	if !json.Valid(body) {
+		return nil, errors.New("wellknown: the OpenID configuration isn't valid JSON")
+	}
+	return body, nil
+}
@@ -0,0 +1,166 @@
+package wellknown
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// testGet sends a GET request and returns the response and its body.
+func testGet(t *testing.T, app *fiber.App, target string) (*http.Response, string) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+// go test -run Test_WellKnown_Documents
+func Test_WellKnown_Documents(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		SecurityTxt: &SecurityTxt{
+			Contact:            []string{"mailto:security@example.com", "https://example.com/security"},
+			Expires:            time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+			PreferredLanguages: []string{"en", "de"},
+			Policy:             []string{"https://example.com/disclosure"},
+		},
+		OpenIDConfiguration:     map[string]any{"issuer": "https://example.com"},
+		AppleAppSiteAssociation: map[string]any{"applinks": map[string]any{"details": []any{}}},
+		AssetLinks: []AssetLink{{
+			Relation: []string{"delegate_permission/common.handle_all_urls"},
+			Target:   AssetLinkTarget{Namespace: "android_app", PackageName: "com.example", SHA256CertFingerprints: []string{"14:6D:E9"}},
+		}},
+		Documents: map[string]Document{
+			"nodeinfo": {ContentType: fiber.MIMEApplicationJSON, Body: []byte(`{"links":[]}`)},
+		},
+	}))
+
+	resp, body := testGet(t, app, "/.well-known/security.txt")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, fiber.MIMETextPlainCharsetUTF8, resp.Header.Get(fiber.HeaderContentType))
+	require.Equal(t, "public, max-age=86400", resp.Header.Get(fiber.HeaderCacheControl))
+	require.Equal(t, "Contact: mailto:security@example.com\n"+
+		"Contact: https://example.com/security\n"+
+		"Expires: 2030-01-01T00:00:00Z\n"+
+		"Preferred-Languages: en, de\n"+
+		"Policy: https://example.com/disclosure\n", body)
+
+	resp, body = testGet(t, app, "/.well-known/openid-configuration")
+	require.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+	require.JSONEq(t, `{"issuer":"https://example.com"}`, body)
+
+	resp, body = testGet(t, app, "/.well-known/apple-app-site-association")
+	require.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+	require.JSONEq(t, `{"applinks":{"details":[]}}`, body)
+
+	_, body = testGet(t, app, "/.well-known/assetlinks.json")
+	require.JSONEq(t, `[{
+		"relation": ["delegate_permission/common.handle_all_urls"],
+		"target": {"namespace": "android_app", "package_name": "com.example", "sha256_cert_fingerprints": ["14:6D:E9"]}
+	}]`, body)
+
+	_, body = testGet(t, app, "/.well-known/nodeinfo")
+	require.Equal(t, `{"links":[]}`, body)
+
+	// unknown documents are passed to the next handler
+	resp, _ = testGet(t, app, "/.well-known/change-password")
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/.well-known/security.txt", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusMethodNotAllowed, resp.StatusCode)
+	require.Equal(t, "GET, HEAD, OPTIONS", resp.Header.Get(fiber.HeaderAllow))
+}
+
+// go test -run Test_WellKnown_ChangePassword
+func Test_WellKnown_ChangePassword(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{ChangePasswordURL: "/account/password"}))
+
+	resp, _ := testGet(t, app, "/.well-known/change-password")
+	require.Equal(t, fiber.StatusFound, resp.StatusCode)
+	require.Equal(t, "/account/password", resp.Header.Get(fiber.HeaderLocation))
+}
+
+// go test -run Test_WellKnown_SecurityTxt_Defaults
+func Test_WellKnown_SecurityTxt_Defaults(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New(Config{SecurityTxt: &SecurityTxt{}})
+	})
+
+	app := fiber.New()
+	app.Use(New(Config{SecurityTxt: &SecurityTxt{Contact: []string{"mailto:security@example.com"}}}))
+
+	_, body := testGet(t, app, "/.well-known/security.txt")
+	require.Contains(t, body, "Expires: "+time.Now().AddDate(1, 0, 0).UTC().Format("2006-01-02"))
+}
+
+// go test -run Test_WellKnown_OpenIDConfigurationURL
+func Test_WellKnown_OpenIDConfigurationURL(t *testing.T) {
+	t.Parallel()
+	var (
+		requests atomic.Int32
+		failing  atomic.Bool
+	)
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"issuer":"https://idp.example.com"}`)) //nolint:errcheck // It's a test
+	}))
+	defer provider.Close()
+
+	app := fiber.New()
+	app.Use(New(Config{
+		OpenIDConfigurationURL: provider.URL + "/.well-known/openid-configuration",
+		OpenIDConfigurationTTL: 50 * time.Millisecond,
+	}))
+
+	for i := 0; i < 2; i++ {
+		resp, body := testGet(t, app, "/.well-known/openid-configuration")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		require.JSONEq(t, `{"issuer":"https://idp.example.com"}`, body)
+	}
+	require.Equal(t, int32(1), requests.Load())
+
+	// the previous document is served if the provider fails
+	failing.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	resp, body := testGet(t, app, "/.well-known/openid-configuration")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.JSONEq(t, `{"issuer":"https://idp.example.com"}`, body)
+	require.Equal(t, int32(2), requests.Load())
+
+	app = fiber.New()
+	app.Use(New(Config{OpenIDConfigurationURL: provider.URL}))
+	resp, _ = testGet(t, app, "/.well-known/openid-configuration")
+	require.Equal(t, fiber.StatusBadGateway, resp.StatusCode)
+}
+
+// go test -run Test_WellKnown_Next
+func Test_WellKnown_Next(t *testing.T) {
+	t.Parallel()
+	app := fiber.New()
+	app.Use(New(Config{
+		ChangePasswordURL: "/account/password",
+		Next: func(_ fiber.Ctx) bool {
+			return true
+		},
+	}))
+
+	resp, _ := testGet(t, app, "/.well-known/change-password")
+	require.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,259 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/utils/v2"
+)
+
+// Pagination contains the pagination params of a request, see Ctx.Pagination.
+type Pagination struct {
+	// Limit is the number of items of a page.
+	Limit int `json:"limit"`
+	// Offset is the number of skipped items.
+	Offset int `json:"offset"`
+	// Cursor is the cursor of the request for cursor-based pagination.
+	Cursor string `json:"cursor,omitempty"`
+	// NextCursor is the cursor of the next page, it is set by the handler
+	// and used by Ctx.PaginationHeaders for the next link.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// PaginationMeta is the pagination metadata of a response body.
+type PaginationMeta struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+	Page   int `json:"page"`
+	Pages  int `json:"pages"`
+}
+
+// Page returns the number of the page, starting at 1.
+func (p Pagination) Page() int {
+	return p.Offset/p.Limit + 1
+}
+
+// Meta returns the metadata of the pagination with the total number of items.
+func (p Pagination) Meta(total int) PaginationMeta {
+	return PaginationMeta{
+		Limit:  p.Limit,
+		Offset: p.Offset,
+		Total:  total,
+		Page:   p.Page(),
+		Pages:  (total + p.Limit - 1) / p.Limit,
+	}
+}
+
+// Pagination parses the query params limit, offset, page and cursor. The limit defaults to
+// Config.PaginationDefaultLimit and is capped by Config.PaginationMaxLimit, a page is
+// converted to its offset. ErrPaginationMalformed is returned for invalid params.
+//
+//	GET /items?limit=50&offset=100
+//	GET /items?page=3
+//	GET /items?cursor=eyJpZCI6NDJ9
+func (c *DefaultCtx) Pagination() (Pagination, error) {
+	p := Pagination{Limit: c.app.config.PaginationDefaultLimit}
+	if cursor := c.Query("cursor"); cursor != "" {
+		p.Cursor = utils.CopyString(cursor)
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return p, ErrPaginationMalformed
+		}
+		p.Limit = min(n, c.app.config.PaginationMaxLimit)
+	}
+
+	if offset := c.Query("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			return p, ErrPaginationMalformed
+		}
+		p.Offset = n
+	} else if page := c.Query("page"); page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 || n-1 > math.MaxInt/p.Limit {
+			return p, ErrPaginationMalformed
+		}
+		p.Offset = (n - 1) * p.Limit
+	}
+	return p, nil
+}
+
+// PaginationHeaders sets the Link header with the first, prev, next and last pages of the
+// pagination and the X-Total-Count header. A negative total is unknown, the last link and
+// the total are omitted then. For cursor-based pagination, the next link contains the
+// NextCursor of the pagination. The links keep the other query params of the request.
+func (c *DefaultCtx) PaginationHeaders(p Pagination, total int) {
+	if total >= 0 {
+		c.Set(HeaderXTotalCount, strconv.Itoa(total))
+	}
+
+	NewLinkSet(c).Pagination(p, total).SetHeader() //nolint:errcheck // There are no route links
+}
+
+// SortField is a field of the sort order of a request, see Ctx.Sort.
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+// Direction returns "ASC" or "DESC".
+func (s SortField) Direction() string {
+	if s.Desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// Sort parses the sort order of the query param sort, e.g. "sort=-created_at,name" or
+// "sort=created_at:desc,name:asc". A leading "-" or the suffix ":desc" sorts descending.
+// Only the allowed fields can be sorted, other fields return ErrSortNotAllowed, so the
+// fields can be used in queries safely.
+func (c *DefaultCtx) Sort(allowed ...string) ([]SortField, error) {
+	var fields []SortField
+	for _, param := range c.Request().URI().QueryArgs().PeekMulti("sort") {
+		for _, item := range strings.Split(string(param), ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+
+			field := SortField{}
+			switch {
+			case strings.HasPrefix(item, "-"):
+				field.Field, field.Desc = item[1:], true
+			case strings.HasPrefix(item, "+"):
+				field.Field = item[1:]
+			default:
+				name, direction, _ := strings.Cut(item, ":")
+				switch utils.ToLower(direction) {
+				case "", "asc":
+				case "desc":
+					field.Desc = true
+				default:
+					return nil, fmt.Errorf("%w: %s", ErrSortNotAllowed, item)
+				}
+				field.Field = name
+			}
+
+			if !containsString(allowed, field.Field) {
+				return nil, fmt.Errorf("%w: %s", ErrSortNotAllowed, field.Field)
+			}
+			if !containsSortField(fields, field.Field) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields, nil
+}
+
+// containsString reports if the values contain the value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsSortField reports if the fields contain the field name.
+func containsSortField(fields []SortField, name string) bool {
+	for _, field := range fields {
+		if field.Field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterOperator is the operator of a Filter.
+type FilterOperator string
+
+// The operators of the filter expressions
+const (
+	FilterEq    FilterOperator = "eq"
+	FilterNe    FilterOperator = "ne"
+	FilterGt    FilterOperator = "gt"
+	FilterGte   FilterOperator = "gte"
+	FilterLt    FilterOperator = "lt"
+	FilterLte   FilterOperator = "lte"
+	FilterLike  FilterOperator = "like"
+	FilterIn    FilterOperator = "in"
+	FilterNotIn FilterOperator = "nin"
+)
+
+// Filter is a filter of a field, see Ctx.Filters and ParseFilter.
+type Filter struct {
+	Field    string         `json:"field"`
+	Operator FilterOperator `json:"operator"`
+	// Values contains the value of the filter, or the values of the operators in and nin.
+	Values []string `json:"values"`
+}
+
+// Value returns the first value of the filter.
+func (f Filter) Value() string {
+	if len(f.Values) == 0 {
+		return ""
+	}
+	return f.Values[0]
+}
+
+// ParseFilter parses the filter expression of a field in the format "<operator>:<value>",
+// e.g. "eq:open", "gte:10" or "in:open,closed". An expression without a known operator
+// is the value of the operator eq. ErrFilterMalformed is returned if the value of a
+// comparison or the values of in and nin are empty.
+func ParseFilter(field, expression string) (Filter, error) {
+	filter := Filter{Field: field, Operator: FilterEq, Values: []string{expression}}
+
+	operator, value, ok := strings.Cut(expression, ":")
+	if !ok {
+		return filter, nil
+	}
+	switch op := FilterOperator(utils.ToLower(operator)); op {
+	case FilterEq, FilterNe, FilterLike:
+		filter.Operator, filter.Values = op, []string{value}
+	case FilterGt, FilterGte, FilterLt, FilterLte:
+		if value == "" {
+			return filter, fmt.Errorf("%w: %s=%s", ErrFilterMalformed, field, expression)
+		}
+		filter.Operator, filter.Values = op, []string{value}
+	case FilterIn, FilterNotIn:
+		values := strings.Split(value, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+			if values[i] == "" {
+				return filter, fmt.Errorf("%w: %s=%s", ErrFilterMalformed, field, expression)
+			}
+		}
+		filter.Operator, filter.Values = op, values
+	}
+	return filter, nil
+}
+
+// Filters parses the filter expressions of the query params of the allowed fields,
+// e.g. "status=eq:open&priority=gte:2". Other query params are ignored, a field can
+// have several filters. See ParseFilter for the format of the expressions.
+func (c *DefaultCtx) Filters(allowed ...string) ([]Filter, error) {
+	var filters []Filter
+	args := c.Request().URI().QueryArgs()
+	for _, field := range allowed {
+		for _, expression := range args.PeekMulti(field) {
+			filter, err := ParseFilter(field, string(expression))
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, filter)
+		}
+	}
+	return filters, nil
+}
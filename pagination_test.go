@@ -0,0 +1,184 @@
+package fiber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Ctx_Pagination
+func Test_Ctx_Pagination(t *testing.T) {
+	t.Parallel()
+	app := New(Config{PaginationDefaultLimit: 10, PaginationMaxLimit: 50})
+
+	tests := []struct {
+		query string
+		want  Pagination
+		err   bool
+	}{
+		{query: "", want: Pagination{Limit: 10}},
+		{query: "limit=20&offset=40", want: Pagination{Limit: 20, Offset: 40}},
+		{query: "limit=500", want: Pagination{Limit: 50}},
+		{query: "limit=5&page=3", want: Pagination{Limit: 5, Offset: 10}},
+		{query: "page=2&offset=3", want: Pagination{Limit: 10, Offset: 3}},
+		{query: "cursor=abc", want: Pagination{Limit: 10, Cursor: "abc"}},
+		{query: "limit=0", err: true},
+		{query: "limit=x", err: true},
+		{query: "offset=-1", err: true},
+		{query: "page=0", err: true},
+		{query: "page=9223372036854775807", err: true},
+	}
+
+	for _, tt := range tests {
+		c := app.AcquireCtx(&fasthttp.RequestCtx{})
+		c.Request().URI().SetQueryString(tt.query)
+		p, err := c.Pagination()
+		if tt.err {
+			require.ErrorIs(t, err, ErrPaginationMalformed, tt.query)
+		} else {
+			require.NoError(t, err, tt.query)
+			require.Equal(t, tt.want, p, tt.query)
+		}
+		app.ReleaseCtx(c)
+	}
+
+	require.Equal(t, DefaultPaginationLimit, New().Config().PaginationDefaultLimit)
+	require.Equal(t, 5, New(Config{PaginationMaxLimit: 5}).Config().PaginationDefaultLimit)
+}
+
+// go test -run Test_Pagination_Meta
+func Test_Pagination_Meta(t *testing.T) {
+	t.Parallel()
+	p := Pagination{Limit: 10, Offset: 20}
+	require.Equal(t, 3, p.Page())
+	require.Equal(t, PaginationMeta{Limit: 10, Offset: 20, Total: 41, Page: 3, Pages: 5}, p.Meta(41))
+	require.Equal(t, 0, p.Meta(0).Pages)
+}
+
+// go test -run Test_Ctx_PaginationHeaders
+func Test_Ctx_PaginationHeaders(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	tests := []struct {
+		query string
+		p     Pagination
+		total int
+		links string
+		count string
+	}{
+		{
+			query: "status=open&offset=20&limit=10",
+			p:     Pagination{Limit: 10, Offset: 20},
+			total: 45,
+			links: `<http://example.com/items?status=open&offset=0&limit=10>; rel="first",` +
+				`<http://example.com/items?status=open&offset=10&limit=10>; rel="prev",` +
+				`<http://example.com/items?status=open&offset=30&limit=10>; rel="next",` +
+				`<http://example.com/items?status=open&offset=40&limit=10>; rel="last"`,
+			count: "45",
+		},
+		{
+			query: "page=1",
+			p:     Pagination{Limit: 20},
+			total: 20,
+			links: `<http://example.com/items?page=1>; rel="first",<http://example.com/items?page=1>; rel="last"`,
+			count: "20",
+		},
+		{
+			query: "",
+			p:     Pagination{Limit: 20},
+			total: -1,
+			links: `<http://example.com/items?offset=0>; rel="first",<http://example.com/items?offset=20>; rel="next"`,
+		},
+		{
+			query: "cursor=abc&limit=5",
+			p:     Pagination{Limit: 5, Cursor: "abc", NextCursor: "def"},
+			total: -1,
+			links: `<http://example.com/items?limit=5>; rel="first",<http://example.com/items?cursor=def&limit=5>; rel="next"`,
+		},
+	}
+
+	for _, tt := range tests {
+		fctx := &fasthttp.RequestCtx{}
+		fctx.Request.SetRequestURI("/items?" + tt.query)
+		fctx.Request.Header.SetHost("example.com")
+		c := app.AcquireCtx(fctx)
+		c.PaginationHeaders(tt.p, tt.total)
+		require.Equal(t, tt.links, string(c.Response().Header.Peek(HeaderLink)), tt.query)
+		require.Equal(t, tt.count, string(c.Response().Header.Peek(HeaderXTotalCount)), tt.query)
+		app.ReleaseCtx(c)
+	}
+}
+
+// go test -run Test_Ctx_Sort
+func Test_Ctx_Sort(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().URI().SetQueryString("sort=-created_at,name:asc,,priority:DESC&sort=%2Bid,name")
+	fields, err := c.Sort("name", "created_at", "priority", "id")
+	require.NoError(t, err)
+	require.Equal(t, []SortField{
+		{Field: "created_at", Desc: true},
+		{Field: "name"},
+		{Field: "priority", Desc: true},
+		{Field: "id"},
+	}, fields)
+	require.Equal(t, "DESC", fields[0].Direction())
+	require.Equal(t, "ASC", fields[1].Direction())
+
+	_, err = c.Sort("name")
+	require.ErrorIs(t, err, ErrSortNotAllowed)
+	require.EqualError(t, err, "sort: field is not allowed: created_at")
+
+	c.Request().URI().SetQueryString("sort=name:sideways")
+	_, err = c.Sort("name")
+	require.ErrorIs(t, err, ErrSortNotAllowed)
+
+	c.Request().URI().SetQueryString("")
+	fields, err = c.Sort("name")
+	require.NoError(t, err)
+	require.Empty(t, fields)
+}
+
+// go test -run Test_Ctx_Filters
+func Test_Ctx_Filters(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	c.Request().URI().SetQueryString("status=in:open,%20pending&priority=gte:2&priority=lt:5&at=10:30&secret=eq:x")
+	filters, err := c.Filters("status", "priority", "at")
+	require.NoError(t, err)
+	require.Equal(t, []Filter{
+		{Field: "status", Operator: FilterIn, Values: []string{"open", "pending"}},
+		{Field: "priority", Operator: FilterGte, Values: []string{"2"}},
+		{Field: "priority", Operator: FilterLt, Values: []string{"5"}},
+		{Field: "at", Operator: FilterEq, Values: []string{"10:30"}},
+	}, filters)
+	require.Equal(t, "2", filters[1].Value())
+	require.Equal(t, "", Filter{}.Value())
+
+	c.Request().URI().SetQueryString("priority=gt:")
+	_, err = c.Filters("priority")
+	require.ErrorIs(t, err, ErrFilterMalformed)
+}
+
+// go test -run Test_ParseFilter
+func Test_ParseFilter(t *testing.T) {
+	t.Parallel()
+	filter, err := ParseFilter("name", "LIKE:jo%")
+	require.NoError(t, err)
+	require.Equal(t, Filter{Field: "name", Operator: FilterLike, Values: []string{"jo%"}}, filter)
+
+	filter, err = ParseFilter("name", "john")
+	require.NoError(t, err)
+	require.Equal(t, Filter{Field: "name", Operator: FilterEq, Values: []string{"john"}}, filter)
+
+	_, err = ParseFilter("id", "nin:1,,2")
+	require.ErrorIs(t, err, ErrFilterMalformed)
+}
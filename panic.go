@@ -0,0 +1,127 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"net"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// PanicPolicy defines how a panic of a handler is treated, if it is not
+// recovered by a middleware like the recover middleware.
+type PanicPolicy uint8
+
+const (
+	// PanicPolicyRepanic re-panics, which crashes the process.
+	PanicPolicyRepanic PanicPolicy = iota
+	// PanicPolicyErrorHandler passes a *PanicError to the ErrorHandler,
+	// the DefaultErrorHandler responds with 500 Internal Server Error.
+	PanicPolicyErrorHandler
+	// PanicPolicyCloseConnection closes the connection immediately without sending a response.
+	PanicPolicyCloseConnection
+)
+
+// String returns the name of the panic policy.
+func (p PanicPolicy) String() string {
+	switch p {
+	case PanicPolicyRepanic:
+		return "repanic"
+	case PanicPolicyErrorHandler:
+		return "error-handler"
+	case PanicPolicyCloseConnection:
+		return "close-connection"
+	default:
+		return fmt.Sprintf("PanicPolicy(%d)", uint8(p))
+	}
+}
+
+// PanicError is passed to the ErrorHandler when a handler panics
+// and the panic policy is PanicPolicyErrorHandler.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panic.
+	Stack []byte
+}
+
+// Error returns the panic value as string.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// PanicPolicy overwrites the panic policy of the app for the latest registered route.
+//
+//	app.Get("/payments", handler).PanicPolicy(fiber.PanicPolicyCloseConnection)
+func (app *App) PanicPolicy(policy PanicPolicy) Router {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	for _, routes := range app.stack {
+		for _, route := range routes {
+			isMethodValid := route.Method == app.latestRoute.Method || app.latestRoute.use ||
+				(app.latestRoute.Method == MethodGet && route.Method == MethodHead)
+
+			if route.Path == app.latestRoute.Path && isMethodValid {
+				route.panicPolicy = &policy
+			}
+		}
+	}
+
+	return app
+}
+
+// PanicPolicy overwrites the panic policy of the app for the latest registered route.
+func (grp *Group) PanicPolicy(policy PanicPolicy) Router {
+	grp.app.PanicPolicy(policy)
+
+	return grp
+}
+
+// recoverPanic applies the panic policy of the current route or the app
+// to a panic which is not recovered by any handler.
+func (app *App) recoverPanic(c CustomCtx) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	policy := app.config.PanicPolicy
+	if route := c.Route(); route.panicPolicy != nil {
+		policy = *route.panicPolicy
+	}
+
+	switch policy {
+	case PanicPolicyErrorHandler:
+		err := &PanicError{Value: r, Stack: debug.Stack()}
+		app.logw(log.LevelError, "recovered from panic", "panic", r, "method", c.Method(), "path", c.Path())
+		if catch := c.App().ErrorHandler(c, err); catch != nil {
+			_ = c.SendStatus(StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
+		}
+	case PanicPolicyCloseConnection:
+		app.errorStats.panic.Add(1)
+		app.reportPanic(c, r)
+		app.logw(log.LevelError, "recovered from panic", "panic", r, "method", c.Method(), "path", c.Path())
+		// close the connection after the handler without writing a response
+		c.Hijack(func(net.Conn) {})
+	default:
+		app.errorStats.panic.Add(1)
+		app.reportPanic(c, r)
+		if app.config.CrashDumpDir != "" {
+			_, _ = app.writeCrashReport(r, debug.Stack(), c) //nolint:errcheck // The app crashes anyway
+		}
+		panic(r)
+	}
+}
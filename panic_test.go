@@ -0,0 +1,81 @@
+package fiber
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_App_PanicPolicy_ErrorHandler
+func Test_App_PanicPolicy_ErrorHandler(t *testing.T) {
+	t.Parallel()
+	errPanic := errors.New("boom")
+
+	var panicErr *PanicError
+	app := New(Config{
+		PanicPolicy: PanicPolicyErrorHandler,
+		ErrorHandler: func(c Ctx, err error) error {
+			require.ErrorAs(t, err, &panicErr)
+			return DefaultErrorHandler(c, err)
+		},
+	})
+	app.Get("/", func(Ctx) error {
+		panic(errPanic)
+	})
+
+	status, body := testRequestBody(t, app, "/")
+	require.Equal(t, StatusInternalServerError, status)
+	require.Equal(t, "panic: boom", body)
+	require.ErrorIs(t, panicErr, errPanic)
+	require.NotEmpty(t, panicErr.Stack)
+}
+
+// go test -run Test_App_PanicPolicy_CloseConnection
+func Test_App_PanicPolicy_CloseConnection(t *testing.T) {
+	t.Parallel()
+	app := New(Config{PanicPolicy: PanicPolicyCloseConnection})
+	app.Get("/", func(Ctx) error {
+		panic("boom")
+	})
+
+	_, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.Error(t, err)
+}
+
+// go test -run Test_App_PanicPolicy_Route
+func Test_App_PanicPolicy_Route(t *testing.T) {
+	t.Parallel()
+	app := New(Config{PanicPolicy: PanicPolicyCloseConnection})
+	app.Get("/recover", func(Ctx) error {
+		panic("boom")
+	}).PanicPolicy(PanicPolicyErrorHandler)
+
+	grp := app.Group("/v1")
+	grp.Get("/recover", func(Ctx) error {
+		panic("boom")
+	}).PanicPolicy(PanicPolicyErrorHandler)
+
+	app.Get("/close", func(Ctx) error {
+		panic("boom")
+	})
+
+	status, _ := testRequestBody(t, app, "/recover")
+	require.Equal(t, StatusInternalServerError, status)
+
+	status, _ = testRequestBody(t, app, "/v1/recover")
+	require.Equal(t, StatusInternalServerError, status)
+
+	_, err := app.Test(httptest.NewRequest(MethodGet, "/close", nil))
+	require.Error(t, err)
+}
+
+// go test -run Test_PanicPolicy_String
+func Test_PanicPolicy_String(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "repanic", PanicPolicyRepanic.String())
+	require.Equal(t, "error-handler", PanicPolicyErrorHandler.String())
+	require.Equal(t, "close-connection", PanicPolicyCloseConnection.String())
+	require.Equal(t, "PanicPolicy(9)", PanicPolicy(9).String())
+}
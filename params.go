@@ -0,0 +1,227 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ParamType contains the types of the parameters which are parsed by Param, QueryAs and HeaderAs.
+type ParamType interface {
+	int | int8 | int16 | int32 | int64 |
+		uint | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64 | bool | string | uuid.UUID | time.Time
+}
+
+// The sources of the parameters in the messages of ParamError.
+const (
+	paramSourceRoute  = "route parameter"
+	paramSourceQuery  = "query parameter"
+	paramSourceHeader = "header"
+)
+
+// ParamError is the error of a route parameter, query parameter or header which is
+// missing or can't be parsed. It unwraps to ErrBadRequest, so the ErrorHandler
+// responds with 400 Bad Request when it's returned by a handler.
+type ParamError struct {
+	// Err is the cause, ErrParamMissing or the error of the parser.
+	Err error
+	// Source is the source of the parameter, e.g. "route parameter", "query parameter" or "header".
+	Source string
+	// Key is the name of the parameter.
+	Key string
+	// Value is the value of the parameter.
+	Value string
+	// Type is the expected type, e.g. "int64".
+	Type string
+}
+
+// Error returns the message of the error, which is safe to send to the client.
+func (e *ParamError) Error() string {
+	source := e.Source
+	if source == "" {
+		source = "parameter"
+	}
+	if errors.Is(e.Err, ErrParamMissing) {
+		return fmt.Sprintf("missing %s %q", source, e.Key)
+	}
+	return fmt.Sprintf("invalid %s %q: %q is not a valid %s", source, e.Key, e.Value, e.Type)
+}
+
+// Unwrap returns the cause and ErrBadRequest.
+func (e *ParamError) Unwrap() []error {
+	return []error{e.Err, ErrBadRequest}
+}
+
+// Param returns the route parameter parsed as the type, or a *ParamError if the
+// parameter is empty or invalid. Times are parsed as time.RFC3339.
+// Unlike Params, the errors can be returned by the handler as 400 Bad Request.
+//
+//	id, err := fiber.Param[int64](c, "id")
+//	if err != nil {
+//	    return err // 400 invalid route parameter "id": "abc" is not a valid int64
+//	}
+func Param[T ParamType](c Ctx, key string) (T, error) {
+	return parseParam[T](paramSourceRoute, key, c.Params(key), time.RFC3339)
+}
+
+// QueryAs returns the query parameter parsed as the type, or a *ParamError if it's
+// invalid. A missing or empty parameter returns the default value, or a *ParamError
+// of ErrParamMissing without a default value. Times are parsed as time.RFC3339.
+//
+//	page, err := fiber.QueryAs(c, "page", 1)
+//	if err != nil {
+//	    return err // 400 invalid query parameter "page": "abc" is not a valid int
+//	}
+func QueryAs[T ParamType](c Ctx, key string, defaultValue ...T) (T, error) {
+	return parseParam(paramSourceQuery, key, c.Query(key), time.RFC3339, defaultValue...)
+}
+
+// HeaderAs returns the request header parsed as the type, like QueryAs.
+// Times are parsed as HTTP dates, e.g. "Mon, 02 Jan 2006 15:04:05 GMT".
+func HeaderAs[T ParamType](c Ctx, key string, defaultValue ...T) (T, error) {
+	return parseParam(paramSourceHeader, key, c.Get(key), http.TimeFormat, defaultValue...)
+}
+
+// parseParam parses the value of the parameter, times are parsed with the layout.
+// An empty value returns the default value or a *ParamError of ErrParamMissing.
+func parseParam[T ParamType](source, key, value, layout string, defaultValue ...T) (T, error) {
+	var v T
+	if value == "" {
+		if len(defaultValue) > 0 {
+			return defaultValue[0], nil
+		}
+		return v, &ParamError{Err: ErrParamMissing, Source: source, Key: key, Type: fmt.Sprintf("%T", v)}
+	}
+
+	var err error
+	switch p := any(&v).(type) {
+	case *int:
+		*p, err = parseParamInt[int](value, strconv.IntSize)
+	case *int8:
+		*p, err = parseParamInt[int8](value, 8)
+	case *int16:
+		*p, err = parseParamInt[int16](value, 16)
+	case *int32:
+		*p, err = parseParamInt[int32](value, 32)
+	case *int64:
+		*p, err = parseParamInt[int64](value, 64)
+	case *uint:
+		*p, err = parseParamUint[uint](value, strconv.IntSize)
+	case *uint8:
+		*p, err = parseParamUint[uint8](value, 8)
+	case *uint16:
+		*p, err = parseParamUint[uint16](value, 16)
+	case *uint32:
+		*p, err = parseParamUint[uint32](value, 32)
+	case *uint64:
+		*p, err = parseParamUint[uint64](value, 64)
+	case *float32:
+		var f float64
+		f, err = strconv.ParseFloat(value, 32)
+		*p = float32(f)
+	case *float64:
+		*p, err = strconv.ParseFloat(value, 64)
+	case *bool:
+		*p, err = strconv.ParseBool(value)
+	case *string:
+		*p = value
+	case *uuid.UUID:
+		*p, err = uuid.Parse(value)
+	case *time.Time:
+		*p, err = time.Parse(layout, value)
+	}
+	if err != nil {
+		var zero T
+		return zero, &ParamError{Err: err, Source: source, Key: key, Value: value, Type: fmt.Sprintf("%T", v)}
+	}
+	return v, nil
+}
+
+// parseParamInt parses a signed integer of the bit size.
+func parseParamInt[T int | int8 | int16 | int32 | int64](value string, bitSize int) (T, error) {
+	n, err := strconv.ParseInt(value, 10, bitSize)
+	return T(n), err //nolint:wrapcheck // The error is wrapped by Param
+}
+
+// parseParamUint parses an unsigned integer of the bit size.
+func parseParamUint[T uint | uint8 | uint16 | uint32 | uint64](value string, bitSize int) (T, error) {
+	n, err := strconv.ParseUint(value, 10, bitSize)
+	return T(n), err //nolint:wrapcheck // The error is wrapped by Param
+}
+
+// ParamsInt64 returns the route parameter as int64, see Param.
+func (c *DefaultCtx) ParamsInt64(key string) (int64, error) {
+	return Param[int64](c, key)
+}
+
+// ParamsUint returns the route parameter as uint64, see Param.
+func (c *DefaultCtx) ParamsUint(key string) (uint64, error) {
+	return Param[uint64](c, key)
+}
+
+// ParamsFloat returns the route parameter as float64, see Param.
+func (c *DefaultCtx) ParamsFloat(key string) (float64, error) {
+	return Param[float64](c, key)
+}
+
+// ParamsBool returns the route parameter as bool, e.g. "true", "1" or "false", see Param.
+func (c *DefaultCtx) ParamsBool(key string) (bool, error) {
+	return Param[bool](c, key)
+}
+
+// ParamsUUID returns the route parameter as UUID, see Param.
+func (c *DefaultCtx) ParamsUUID(key string) (uuid.UUID, error) {
+	return Param[uuid.UUID](c, key)
+}
+
+// ParamsTime returns the route parameter as time of the layout, time.RFC3339 by default, see Param.
+func (c *DefaultCtx) ParamsTime(key string, layout ...string) (time.Time, error) {
+	if len(layout) == 0 {
+		return Param[time.Time](c, key)
+	}
+	return parseParam[time.Time](paramSourceRoute, key, c.Params(key), layout[0])
+}
+
+// QueryInt returns the query parameter as int, see QueryAs.
+func (c *DefaultCtx) QueryInt(key string, defaultValue ...int) (int, error) {
+	return QueryAs(c, key, defaultValue...)
+}
+
+// QueryFloat returns the query parameter as float64, see QueryAs.
+func (c *DefaultCtx) QueryFloat(key string, defaultValue ...float64) (float64, error) {
+	return QueryAs(c, key, defaultValue...)
+}
+
+// QueryBool returns the query parameter as bool, see QueryAs.
+func (c *DefaultCtx) QueryBool(key string, defaultValue ...bool) (bool, error) {
+	return QueryAs(c, key, defaultValue...)
+}
+
+// RequiredQuery returns the query parameter, or a *ParamError if it's missing or empty.
+func (c *DefaultCtx) RequiredQuery(key string) (string, error) {
+	return QueryAs[string](c, key)
+}
+
+// HeaderInt returns the request header as int, see HeaderAs.
+func (c *DefaultCtx) HeaderInt(key string, defaultValue ...int) (int, error) {
+	return HeaderAs(c, key, defaultValue...)
+}
+
+// HeaderTime returns the request header as time of an HTTP date, e.g. of If-Modified-Since, see HeaderAs.
+func (c *DefaultCtx) HeaderTime(key string, defaultValue ...time.Time) (time.Time, error) {
+	return HeaderAs(c, key, defaultValue...)
+}
+
+// RequiredHeader returns the request header, or a *ParamError if it's missing or empty.
+func (c *DefaultCtx) RequiredHeader(key string) (string, error) {
+	return HeaderAs[string](c, key)
+}
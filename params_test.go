@@ -0,0 +1,209 @@
+package fiber
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_Param
+func Test_Param(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/:id/:count/:ratio/:active/:uuid/:time", func(c Ctx) error {
+		id, err := c.ParamsInt64("id")
+		require.NoError(t, err)
+		require.Equal(t, int64(-42), id)
+
+		count, err := c.ParamsUint("count")
+		require.NoError(t, err)
+		require.Equal(t, uint64(7), count)
+
+		small, err := Param[uint8](c, "count")
+		require.NoError(t, err)
+		require.Equal(t, uint8(7), small)
+
+		ratio, err := c.ParamsFloat("ratio")
+		require.NoError(t, err)
+		require.InDelta(t, 0.5, ratio, 0)
+
+		active, err := c.ParamsBool("active")
+		require.NoError(t, err)
+		require.True(t, active)
+
+		id2, err := c.ParamsUUID("uuid")
+		require.NoError(t, err)
+		require.Equal(t, uuid.MustParse("0b5fbd4c-5a5c-4d8e-9f4b-6f1b4c1d2e3f"), id2)
+
+		at, err := c.ParamsTime("time")
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), at)
+
+		name, err := Param[string](c, "id")
+		require.NoError(t, err)
+		require.Equal(t, "-42", name)
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/-42/7/0.5/true/0b5fbd4c-5a5c-4d8e-9f4b-6f1b4c1d2e3f/2024-05-01T10:00:00Z", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_Param_Errors
+func Test_Param_Errors(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/:id/:day?", func(c Ctx) error {
+		_, err := c.ParamsInt64("id")
+		var paramErr *ParamError
+		require.ErrorAs(t, err, &paramErr)
+		require.Equal(t, "id", paramErr.Key)
+		require.Equal(t, "abc", paramErr.Value)
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+		require.ErrorIs(t, err, ErrBadRequest)
+		require.EqualError(t, err, `invalid route parameter "id": "abc" is not a valid int64`)
+
+		_, err = Param[uint8](c, "id")
+		require.EqualError(t, err, `invalid route parameter "id": "abc" is not a valid uint8`)
+		_, err = c.ParamsUUID("id")
+		require.EqualError(t, err, `invalid route parameter "id": "abc" is not a valid uuid.UUID`)
+
+		_, err = c.ParamsTime("day", time.DateOnly)
+		require.ErrorIs(t, err, ErrParamMissing)
+		require.EqualError(t, err, `missing route parameter "day"`)
+		return nil
+	})
+	app.Get("/int8/:n/:day", func(c Ctx) error {
+		_, err := Param[int8](c, "n")
+		require.ErrorIs(t, err, strconv.ErrRange)
+
+		day, err := c.ParamsTime("day", time.DateOnly)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), day)
+
+		_, err = c.ParamsTime("day")
+		var paramErr *ParamError
+		require.ErrorAs(t, err, &paramErr)
+		return nil
+	})
+
+	for _, path := range []string{"/abc", "/int8/300/2024-05-01"} {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		require.NoError(t, err)
+		require.Equal(t, StatusOK, resp.StatusCode)
+	}
+}
+
+// go test -run Test_Param_ErrorHandler
+func Test_Param_ErrorHandler(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/users/:id", func(c Ctx) error {
+		id, err := c.ParamsInt64("id")
+		if err != nil {
+			return err
+		}
+		return c.SendString(strconv.FormatInt(id, 10))
+	})
+
+	// the errors of the parameters are returned as 400 Bad Request
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/users/abc", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusBadRequest, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, `invalid route parameter "id": "abc" is not a valid int64`, string(body))
+
+	require.False(t, errors.Is(&ParamError{Err: ErrParamMissing}, ErrNotFound))
+}
+
+// go test -run Test_QueryAs
+func Test_QueryAs(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		page, err := c.QueryInt("page", 1)
+		require.NoError(t, err)
+		require.Equal(t, 3, page)
+
+		limit, err := c.QueryInt("limit", 20)
+		require.NoError(t, err)
+		require.Equal(t, 20, limit)
+
+		_, err = c.QueryInt("limit")
+		require.ErrorIs(t, err, ErrParamMissing)
+		require.EqualError(t, err, `missing query parameter "limit"`)
+
+		ratio, err := c.QueryFloat("ratio")
+		require.NoError(t, err)
+		require.InDelta(t, 0.25, ratio, 0)
+
+		_, err = c.QueryBool("active", true)
+		require.ErrorIs(t, err, ErrBadRequest)
+		require.EqualError(t, err, `invalid query parameter "active": "maybe" is not a valid bool`)
+
+		token, err := c.RequiredQuery("token")
+		require.NoError(t, err)
+		require.Equal(t, "secret", token)
+
+		_, err = c.RequiredQuery("empty")
+		require.EqualError(t, err, `missing query parameter "empty"`)
+
+		since, err := QueryAs[time.Time](c, "since")
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), since)
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/?page=3&ratio=0.25&active=maybe&token=secret&empty=&since=2024-05-01T10:00:00Z", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_HeaderAs
+func Test_HeaderAs(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		since, err := c.HeaderTime(HeaderIfUnmodifiedSince)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC), since)
+
+		_, err = c.HeaderTime(HeaderIfModifiedSince)
+		require.ErrorIs(t, err, ErrParamMissing)
+		modified, err := c.HeaderTime(HeaderIfModifiedSince, time.Time{})
+		require.NoError(t, err)
+		require.True(t, modified.IsZero())
+
+		_, err = c.HeaderInt("X-Count", 1)
+		require.EqualError(t, err, `invalid header "X-Count": "many" is not a valid int`)
+
+		id, err := HeaderAs[uuid.UUID](c, "X-Request-Id")
+		require.NoError(t, err)
+		require.Equal(t, uuid.MustParse("0b5fbd4c-5a5c-4d8e-9f4b-6f1b4c1d2e3f"), id)
+
+		key, err := c.RequiredHeader("X-Api-Key")
+		if err != nil {
+			return err
+		}
+		return c.SendString(key)
+	})
+
+	req := httptest.NewRequest(MethodGet, "/", nil)
+	req.Header.Set(HeaderIfUnmodifiedSince, "Wed, 21 Oct 2015 07:28:00 GMT")
+	req.Header.Set("X-Count", "many")
+	req.Header.Set("X-Request-Id", "0b5fbd4c-5a5c-4d8e-9f4b-6f1b4c1d2e3f")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusBadRequest, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, `missing header "X-Api-Key"`, string(body))
+}
@@ -0,0 +1,220 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"unicode/utf8"
+)
+
+// PathDecodePolicy is the percent-decoding policy of the path normalization.
+type PathDecodePolicy int
+
+const (
+	// PathDecodeNone keeps the percent-encoded characters.
+	PathDecodeNone PathDecodePolicy = iota
+	// PathDecodeUnreserved decodes the unreserved characters (letters, digits, "-", ".", "_" and "~"),
+	// which are equivalent to their encodings (RFC 3986, section 6.2.2.2).
+	PathDecodeUnreserved
+	// PathDecodeAll decodes all characters, except the encoded slash "%2F",
+	// which would change the segments of the path.
+	PathDecodeAll
+)
+
+// PathNormalizationConfig configures the normalization of the request paths, which is applied
+// before the routing. The normalized path is returned by c.Path(), c.OriginalURL() is unchanged.
+type PathNormalizationConfig struct {
+	// UnicodeNormalizer normalizes the decoded path, e.g. norm.NFC.String of golang.org/x/text/unicode/norm,
+	// so the different representations of the same characters are routed alike.
+	//
+	// Optional. Default: nil
+	UnicodeNormalizer func(path string) string `json:"-"`
+
+	// Decode is the percent-decoding policy of the path.
+	// Don't combine PathDecodeAll with Config.UnescapePath, the path would be decoded twice.
+	//
+	// Optional. Default: PathDecodeNone
+	Decode PathDecodePolicy `json:"decode"`
+
+	// When set to true, the "." and ".." segments are removed from the path (RFC 3986, section 5.2.4),
+	// after the path was decoded.
+	//
+	// Optional. Default: false
+	RemoveDotSegments bool `json:"remove_dot_segments"`
+
+	// When set to true, duplicate slashes are collapsed into one.
+	//
+	// Optional. Default: false
+	CollapseSlashes bool `json:"collapse_slashes"`
+
+	// When set to true, requests with suspicious encodings in the path are rejected with
+	// ErrSuspiciousPath, because they are used to bypass path-based access checks:
+	// encoded slashes, backslashes and dots, double encodings, invalid percent-encodings,
+	// control characters and invalid UTF-8.
+	//
+	// Optional. Default: false
+	Strict bool `json:"strict"`
+}
+
+// enabled reports whether the path is normalized.
+func (cfg *PathNormalizationConfig) enabled() bool {
+	return cfg.Decode != PathDecodeNone || cfg.UnicodeNormalizer != nil || cfg.RemoveDotSegments || cfg.CollapseSlashes
+}
+
+// normalize normalizes the path in place.
+func (cfg *PathNormalizationConfig) normalize(path []byte) []byte {
+	if cfg.Decode != PathDecodeNone {
+		path = decodePath(path, cfg.Decode)
+	}
+	if cfg.UnicodeNormalizer != nil {
+		path = append(path[:0], cfg.UnicodeNormalizer(string(path))...)
+	}
+	if cfg.CollapseSlashes {
+		path = collapseSlashes(path)
+	}
+	if cfg.RemoveDotSegments {
+		path = removeDotSegments(path)
+	}
+	return path
+}
+
+// decodePath decodes the percent-encoded characters of the path in place, depending on the policy.
+func decodePath(path []byte, policy PathDecodePolicy) []byte {
+	w := 0
+	for i := 0; i < len(path); i++ {
+		if path[i] == '%' {
+			if b, ok := unhexByte(path, i+1); ok && (isUnreservedByte(b) || (policy == PathDecodeAll && b != '/')) {
+				path[w] = b
+				w++
+				i += 2
+				continue
+			}
+		}
+		path[w] = path[i]
+		w++
+	}
+	return path[:w]
+}
+
+// collapseSlashes collapses the duplicate slashes of the path in place.
+func collapseSlashes(path []byte) []byte {
+	w := 0
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && w > 0 && path[w-1] == '/' {
+			continue
+		}
+		path[w] = path[i]
+		w++
+	}
+	return path[:w]
+}
+
+// removeDotSegments removes the "." and ".." segments of the absolute path in place.
+// The ".." segments above the root are dropped.
+func removeDotSegments(path []byte) []byte {
+	if len(path) == 0 || path[0] != '/' {
+		return path
+	}
+
+	w := 0
+	for i := 0; i < len(path); {
+		// the segment after the slash at i
+		end := i + 1
+		for end < len(path) && path[end] != '/' {
+			end++
+		}
+		segment := path[i+1 : end]
+		last := end == len(path)
+
+		switch string(segment) {
+		case ".":
+			if last {
+				path[w] = '/'
+				w++
+			}
+		case "..":
+			for w > 0 {
+				w--
+				if path[w] == '/' {
+					break
+				}
+			}
+			if last {
+				path[w] = '/'
+				w++
+			}
+		default:
+			w += copy(path[w:], path[i:end])
+		}
+		i = end
+	}
+	if w == 0 {
+		path[0] = '/'
+		w = 1
+	}
+	return path[:w]
+}
+
+// suspiciousPath reports whether the raw path contains encodings which are used to bypass
+// path-based access checks, see PathNormalizationConfig.Strict.
+func suspiciousPath(path string) bool {
+	decode := false
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '\\' || c < 0x20 || c == 0x7f:
+			return true
+		case c == '%':
+			b, ok := unhexByte(path, i+1)
+			if !ok {
+				return true
+			}
+			switch {
+			case b == '/' || b == '\\' || b == '.' || b < 0x20 || b == 0x7f:
+				return true
+			case b == '%':
+				// double encoding, e.g. %252e
+				if _, ok := unhexByte(path, i+3); ok {
+					return true
+				}
+			case b >= utf8.RuneSelf:
+				decode = true
+			}
+			i += 2
+		}
+	}
+
+	if decode {
+		return !utf8.Valid(decodePath([]byte(path), PathDecodeAll))
+	}
+	return !utf8.ValidString(path)
+}
+
+// unhexByte decodes the two hex digits at i of s.
+func unhexByte[S ~string | ~[]byte](s S, i int) (byte, bool) {
+	if i+1 >= len(s) {
+		return 0, false
+	}
+	hi, ok1 := unhexDigit(s[i])
+	lo, ok2 := unhexDigit(s[i+1])
+	return hi<<4 | lo, ok1 && ok2
+}
+
+func unhexDigit(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// isUnreservedByte reports whether the byte is an unreserved character of RFC 3986.
+func isUnreservedByte(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
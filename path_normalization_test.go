@@ -0,0 +1,83 @@
+package fiber
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_PathNormalization_Normalize
+func Test_PathNormalization_Normalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		cfg      PathNormalizationConfig
+		path     string
+		expected string
+	}{
+		{cfg: PathNormalizationConfig{RemoveDotSegments: true}, path: "/a/b/../c/./d", expected: "/a/c/d"},
+		{cfg: PathNormalizationConfig{RemoveDotSegments: true}, path: "/a/b/..", expected: "/a/"},
+		{cfg: PathNormalizationConfig{RemoveDotSegments: true}, path: "/a/.", expected: "/a/"},
+		{cfg: PathNormalizationConfig{RemoveDotSegments: true}, path: "/../../etc/passwd", expected: "/etc/passwd"},
+		{cfg: PathNormalizationConfig{RemoveDotSegments: true}, path: "/..", expected: "/"},
+		{cfg: PathNormalizationConfig{RemoveDotSegments: true}, path: "/a/..b/.c", expected: "/a/..b/.c"},
+		{cfg: PathNormalizationConfig{CollapseSlashes: true}, path: "//a///b/", expected: "/a/b/"},
+		{cfg: PathNormalizationConfig{CollapseSlashes: true, RemoveDotSegments: true}, path: "/a//../b", expected: "/b"},
+		{cfg: PathNormalizationConfig{Decode: PathDecodeUnreserved}, path: "/%7Euser/%41%20b%2F", expected: "/~user/A%20b%2F"},
+		{cfg: PathNormalizationConfig{Decode: PathDecodeAll}, path: "/%7Euser/%41%20b%2F%zz", expected: "/~user/A b%2F%zz"},
+		{cfg: PathNormalizationConfig{Decode: PathDecodeUnreserved, RemoveDotSegments: true}, path: "/static/%2e%2e/admin", expected: "/admin"},
+		{cfg: PathNormalizationConfig{UnicodeNormalizer: strings.ToLower}, path: "/ÄB", expected: "/äb"},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.expected, string(tc.cfg.normalize([]byte(tc.path))), tc.path)
+	}
+}
+
+// go test -run Test_PathNormalization_Suspicious
+func Test_PathNormalization_Suspicious(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"/", "/users/42", "/caf%C3%A9", "/100%25", "/a%20b", "/~user"} {
+		require.False(t, suspiciousPath(path), path)
+	}
+	for _, path := range []string{
+		"/static/%2e%2e/admin", "/a%2Fb", "/a%5cb", "/a\\b", "/a%252e", "/a%zz", "/a%2",
+		"/a%00", "/a\x01", "/%C3%28", "/\xff",
+	} {
+		require.True(t, suspiciousPath(path), path)
+	}
+}
+
+// go test -run Test_App_PathNormalization
+func Test_App_PathNormalization(t *testing.T) {
+	t.Parallel()
+
+	app := New(Config{PathNormalization: PathNormalizationConfig{
+		Decode:            PathDecodeUnreserved,
+		RemoveDotSegments: true,
+		CollapseSlashes:   true,
+	}})
+	app.Get("/admin", func(c Ctx) error {
+		return c.SendString(c.Path())
+	})
+
+	for _, path := range []string{"/admin", "//admin", "/public/../admin", "/public/%2e%2e/admin", "/%61dmin"} {
+		require.Equal(t, StatusOK, testRawRequest(t, app, "10.0.0.1", "GET "+path+" HTTP/1.1\r\nHost: example.com\r\n\r\n"), path)
+	}
+}
+
+// go test -run Test_App_PathNormalization_Strict
+func Test_App_PathNormalization_Strict(t *testing.T) {
+	t.Parallel()
+
+	app := New(Config{PathNormalization: PathNormalizationConfig{Strict: true}})
+	app.Get("/*", func(c Ctx) error {
+		return c.SendString(c.Path())
+	})
+
+	require.Equal(t, StatusOK, testRawRequest(t, app, "10.0.0.1", "GET /files/a%20b HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	require.Equal(t, StatusBadRequest, testRawRequest(t, app, "10.0.0.1", "GET /files/%2e%2e/secret HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	require.Equal(t, StatusBadRequest, testRawRequest(t, app, "10.0.0.1", "GET /files/..%2Fsecret HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+}
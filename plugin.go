@@ -0,0 +1,119 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Plugin is a reusable bundle of routes, middleware, hooks and background
+// workers that is registered as a single unit with app.Use(plugin).
+//
+// Register is called immediately when the plugin is used. Start is called in
+// registration order right before the server starts serving, Stop is called in
+// reverse order after the server has been shut down.
+type Plugin interface {
+	// Name returns the unique name of the plugin.
+	Name() string
+
+	// Register attaches routes, middleware and hooks to the app.
+	Register(app *App) error
+
+	// Start starts the background work of the plugin.
+	Start(ctx context.Context) error
+
+	// Stop stops the background work of the plugin.
+	Stop(ctx context.Context) error
+}
+
+// Put fields related to plugins.
+type pluginFields struct {
+	// Registered plugins in registration order
+	plugins []Plugin
+	// Amount of plugins which have been started successfully
+	started int
+}
+
+// registerPlugin registers the given plugin and panics if it can't be registered.
+func (app *App) registerPlugin(plugin Plugin) {
+	app.mutex.Lock()
+	for _, p := range app.pluginFields.plugins {
+		if p.Name() == plugin.Name() {
+			app.mutex.Unlock()
+			panic(fmt.Errorf("%w: %q", ErrPluginAlreadyRegistered, plugin.Name()))
+		}
+	}
+	app.pluginFields.plugins = append(app.pluginFields.plugins, plugin)
+	app.mutex.Unlock()
+
+	if err := plugin.Register(app); err != nil {
+		panic(fmt.Errorf("plugin: failed to register %q: %w", plugin.Name(), err))
+	}
+}
+
+// Plugin returns the registered plugin with the given name or nil if it doesn't exist.
+func (app *App) Plugin(name string) Plugin {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	for _, p := range app.pluginFields.plugins {
+		if p.Name() == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// Plugins returns all registered plugins in registration order.
+func (app *App) Plugins() []Plugin {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	plugins := make([]Plugin, len(app.pluginFields.plugins))
+	copy(plugins, app.pluginFields.plugins)
+
+	return plugins
+}
+
+// startPlugins starts all registered plugins in registration order.
+// If a plugin fails to start, the already started plugins are stopped in reverse order.
+func (app *App) startPlugins(ctx context.Context) error {
+	app.mutex.Lock()
+	plugins := app.pluginFields.plugins[app.pluginFields.started:]
+	app.mutex.Unlock()
+
+	for _, p := range plugins {
+		if err := p.Start(ctx); err != nil {
+			startErr := fmt.Errorf("plugin: failed to start %q: %w", p.Name(), err)
+			return errors.Join(startErr, app.stopPlugins(ctx))
+		}
+
+		app.mutex.Lock()
+		app.pluginFields.started++
+		app.mutex.Unlock()
+	}
+
+	return nil
+}
+
+// stopPlugins stops all started plugins in reverse registration order.
+func (app *App) stopPlugins(ctx context.Context) error {
+	app.mutex.Lock()
+	plugins := app.pluginFields.plugins[:app.pluginFields.started]
+	app.pluginFields.started = 0
+	app.mutex.Unlock()
+
+	var errs []error
+	for i := len(plugins) - 1; i >= 0; i-- {
+		if err := plugins[i].Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("plugin: failed to stop %q: %w", plugins[i].Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,173 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testPlugin struct {
+	name     string
+	events   *[]string
+	startErr error
+	stopErr  error
+}
+
+func (p *testPlugin) Name() string {
+	return p.name
+}
+
+func (p *testPlugin) Register(app *App) error {
+	app.Get("/"+p.name, func(c Ctx) error {
+		return c.SendString(p.name)
+	})
+	return nil
+}
+
+func (p *testPlugin) Start(_ context.Context) error {
+	*p.events = append(*p.events, "start:"+p.name)
+	return p.startErr
+}
+
+func (p *testPlugin) Stop(_ context.Context) error {
+	*p.events = append(*p.events, "stop:"+p.name)
+	return p.stopErr
+}
+
+// go test -run Test_App_Use_Plugin
+func Test_App_Use_Plugin(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var events []string
+	plugin := &testPlugin{name: "metrics", events: &events}
+	app.Use(plugin)
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/metrics", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "metrics", string(body))
+
+	require.Equal(t, plugin, app.Plugin("metrics"))
+	require.Nil(t, app.Plugin("unknown"))
+	require.Len(t, app.Plugins(), 1)
+	require.Empty(t, events)
+}
+
+// go test -run Test_App_Use_Plugin_Duplicate
+func Test_App_Use_Plugin_Duplicate(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var events []string
+	app.Use(&testPlugin{name: "auth", events: &events})
+
+	defer func() {
+		err, ok := recover().(error)
+		require.True(t, ok)
+		require.ErrorIs(t, err, ErrPluginAlreadyRegistered)
+	}()
+
+	app.Use(&testPlugin{name: "auth", events: &events})
+}
+
+// go test -run Test_App_Plugin_Lifecycle
+func Test_App_Plugin_Lifecycle(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var events []string
+	app.Use(&testPlugin{name: "a", events: &events}, &testPlugin{name: "b", events: &events})
+
+	require.NoError(t, app.startPlugins(context.Background()))
+	require.NoError(t, app.Shutdown())
+	require.Equal(t, []string{"start:a", "start:b", "stop:b", "stop:a"}, events)
+
+	// plugins are only stopped once
+	require.NoError(t, app.stopPlugins(context.Background()))
+	require.Len(t, events, 4)
+}
+
+// go test -run Test_App_Plugin_StartError
+func Test_App_Plugin_StartError(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var events []string
+	startErr := errors.New("cannot connect")
+	app.Use(&testPlugin{name: "a", events: &events})
+	app.Use(&testPlugin{name: "b", events: &events, startErr: startErr})
+	app.Use(&testPlugin{name: "c", events: &events})
+
+	err := app.startPlugins(context.Background())
+	require.ErrorIs(t, err, startErr)
+	require.Equal(t, []string{"start:a", "start:b", "stop:a"}, events)
+}
+
+// go test -run Test_App_Plugin_StopError
+func Test_App_Plugin_StopError(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var events []string
+	stopErr := errors.New("cannot close")
+	app.Use(&testPlugin{name: "a", events: &events, stopErr: stopErr})
+
+	require.NoError(t, app.startPlugins(context.Background()))
+	require.ErrorIs(t, app.Shutdown(), stopErr)
+}
+
+// go test -run Test_App_Listener_Plugin_StartError
+func Test_App_Listener_Plugin_StartError(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var events []string
+	startErr := errors.New("cannot connect")
+	app.Use(&testPlugin{name: "a", events: &events})
+	app.Use(&testPlugin{name: "b", events: &events, startErr: startErr})
+
+	ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+	require.NoError(t, err)
+
+	err = app.Listener(ln, ListenConfig{DisableStartupMessage: true})
+	require.ErrorIs(t, err, startErr)
+	require.Equal(t, []string{"start:a", "start:b", "stop:a"}, events)
+
+	// the listener is closed
+	_, err = ln.Accept()
+	require.ErrorIs(t, err, net.ErrClosed)
+}
+
+// go test -run Test_App_Listener_BeforeServeFuncError
+func Test_App_Listener_BeforeServeFuncError(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	var events []string
+	app.Use(&testPlugin{name: "a", events: &events})
+
+	ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+	require.NoError(t, err)
+
+	serveErr := errors.New("not ready")
+	err = app.Listener(ln, ListenConfig{
+		DisableStartupMessage: true,
+		BeforeServeFunc: func(*App) error {
+			return serveErr
+		},
+	})
+	require.ErrorIs(t, err, serveErr)
+	require.Equal(t, []string{"start:a", "stop:a"}, events)
+
+	_, err = ln.Accept()
+	require.ErrorIs(t, err, net.ErrClosed)
+}
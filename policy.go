@@ -0,0 +1,133 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"github.com/gofiber/utils/v2"
+)
+
+// DefaultPrincipalKey is the default key of the authenticated principal in the locals, see Config.PrincipalKey.
+const DefaultPrincipalKey = "principal"
+
+// PolicyDecider decides whether the authenticated principal has the permissions
+// which are required by a route, see Require.
+type PolicyDecider interface {
+	// Decide reports whether the principal has all permissions. Errors are passed to the ErrorHandler.
+	Decide(c Ctx, principal any, permissions []string) (bool, error)
+}
+
+// PolicyDeciderFunc is an adapter to use a function as a PolicyDecider.
+type PolicyDeciderFunc func(c Ctx, principal any, permissions []string) (bool, error)
+
+// Decide calls f(c, principal, permissions).
+func (f PolicyDeciderFunc) Decide(c Ctx, principal any, permissions []string) (bool, error) {
+	return f(c, principal, permissions)
+}
+
+// Principal is an authenticated principal which is evaluated by the DefaultPolicyDecider.
+type Principal interface {
+	// HasPermission reports whether the principal has the permission or role, e.g. "orders:write".
+	HasPermission(permission string) bool
+}
+
+// DefaultPolicyDecider is the PolicyDecider of the app if Config.PolicyDecider isn't set.
+// It allows the principals which implement Principal and have all permissions.
+var DefaultPolicyDecider PolicyDecider = PolicyDeciderFunc(func(_ Ctx, principal any, permissions []string) (bool, error) {
+	p, ok := principal.(Principal)
+	if !ok {
+		return false, nil
+	}
+	for _, permission := range permissions {
+		if !p.HasPermission(permission) {
+			return false, nil
+		}
+	}
+	return true, nil
+})
+
+// PolicyDenial is the error of a request which was denied by the authorization policy.
+// It is passed to Config.PolicyDeniedHandler and unwraps to ErrUnauthorized, if there
+// is no authenticated principal, or ErrForbidden.
+type PolicyDenial struct {
+	// Principal is the authenticated principal, or nil.
+	Principal any
+	// Permissions are the permissions required by the route.
+	Permissions []string
+	// Status is StatusUnauthorized or StatusForbidden.
+	Status int
+}
+
+// Error returns the status message of the denial, the required permissions aren't disclosed.
+func (d *PolicyDenial) Error() string {
+	return utils.StatusMessage(d.Status)
+}
+
+// Unwrap returns ErrUnauthorized or ErrForbidden.
+func (d *PolicyDenial) Unwrap() error {
+	if d.Status == StatusUnauthorized {
+		return ErrUnauthorized
+	}
+	return ErrForbidden
+}
+
+// Require sets the permissions or roles which are required for the latest registered route.
+// They are evaluated by the Config.PolicyDecider against the authenticated principal in the
+// locals, which is set by an authentication middleware with Config.PrincipalKey.
+// If it is set for a middleware, e.g. of a group, it is required for all requests which are
+// handled by the middleware. Multiple calls replace the permissions of the route.
+//
+//	app.Post("/orders", handler).Require("orders:write")
+func (app *App) Require(permissions ...string) Router {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	for _, routes := range app.stack {
+		for _, route := range routes {
+			isMethodValid := route.Method == app.latestRoute.Method || app.latestRoute.use ||
+				(app.latestRoute.Method == MethodGet && route.Method == MethodHead)
+
+			// middlewares with the same path keep their permissions
+			if route.Path == app.latestRoute.Path && route.use == app.latestRoute.use && isMethodValid {
+				route.permissions = append([]string{}, permissions...)
+			}
+		}
+	}
+
+	return app
+}
+
+// Require sets the permissions or roles which are required for the latest registered route.
+func (grp *Group) Require(permissions ...string) Router {
+	grp.app.Require(permissions...)
+
+	return grp
+}
+
+// authorize evaluates the permissions of the route for the principal of the request.
+// It returns false if the request was denied, the denial is returned as error
+// unless it was rendered by the PolicyDeniedHandler.
+func (app *App) authorize(c CustomCtx, route *Route) (bool, error) {
+	principal := c.Locals(app.config.PrincipalKey)
+	if principal == nil {
+		return false, app.policyDenied(c, &PolicyDenial{Permissions: route.permissions, Status: StatusUnauthorized})
+	}
+
+	allowed, err := app.config.PolicyDecider.Decide(c, principal, route.permissions)
+	if err != nil {
+		return false, err
+	}
+	if !allowed {
+		return false, app.policyDenied(c, &PolicyDenial{Principal: principal, Permissions: route.permissions, Status: StatusForbidden})
+	}
+	return true, nil
+}
+
+// policyDenied passes the denial to the PolicyDeniedHandler, if it is set.
+func (app *App) policyDenied(c CustomCtx, denial *PolicyDenial) error {
+	if app.config.PolicyDeniedHandler == nil {
+		return denial
+	}
+	return app.config.PolicyDeniedHandler(c, denial)
+}
@@ -0,0 +1,141 @@
+package fiber
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testPrincipal is a Principal with a list of permissions.
+type testPrincipal []string
+
+func (p testPrincipal) HasPermission(permission string) bool {
+	return slices.Contains(p, permission)
+}
+
+// testAuthenticate sets the principal of the X-Permissions header, if it is set.
+func testAuthenticate(c Ctx) error {
+	if permissions := c.Get("X-Permissions"); permissions != "" {
+		c.Locals(DefaultPrincipalKey, testPrincipal{permissions})
+	}
+	return c.Next()
+}
+
+// go test -run Test_Route_Require
+func Test_Route_Require(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+	app.Use(testAuthenticate)
+	app.Get("/orders", func(c Ctx) error {
+		return c.SendString("orders")
+	}).Require("orders:read")
+	app.Post("/orders", func(c Ctx) error {
+		return c.SendString("created")
+	}).Require("orders:write")
+	app.Get("/public", func(c Ctx) error {
+		return c.SendString("public")
+	})
+
+	request := func(method, path, permissions string) int {
+		req := httptest.NewRequest(method, path, nil)
+		if permissions != "" {
+			req.Header.Set("X-Permissions", permissions)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	require.Equal(t, StatusOK, request(MethodGet, "/public", ""))
+	require.Equal(t, StatusUnauthorized, request(MethodGet, "/orders", ""))
+	require.Equal(t, StatusOK, request(MethodGet, "/orders", "orders:read"))
+	require.Equal(t, StatusForbidden, request(MethodPost, "/orders", "orders:read"))
+	require.Equal(t, StatusOK, request(MethodPost, "/orders", "orders:write"))
+}
+
+// go test -run Test_Group_Require
+func Test_Group_Require(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+	app.Use(testAuthenticate)
+	admin := app.Group("/admin", func(c Ctx) error {
+		return c.Next()
+	}).Require("role:admin")
+	admin.Get("/users", func(c Ctx) error {
+		return c.SendString("users")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/admin/users", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusUnauthorized, resp.StatusCode)
+
+	req := httptest.NewRequest(MethodGet, "/admin/users", nil)
+	req.Header.Set("X-Permissions", "role:user")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusForbidden, resp.StatusCode)
+
+	req.Header.Set("X-Permissions", "role:admin")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_App_PolicyDecider
+func Test_App_PolicyDecider(t *testing.T) {
+	t.Parallel()
+
+	errDecider := errors.New("policy store unavailable")
+	app := New(Config{
+		PrincipalKey: "user",
+		PolicyDecider: PolicyDeciderFunc(func(c Ctx, principal any, permissions []string) (bool, error) {
+			if c.Query("fail") != "" {
+				return false, errDecider
+			}
+			return principal == "alice" && slices.Equal(permissions, []string{"reports:read"}), nil
+		}),
+		PolicyDeniedHandler: func(c Ctx, err error) error {
+			var denial *PolicyDenial
+			require.ErrorAs(t, err, &denial)
+			if denial.Status == StatusForbidden {
+				require.ErrorIs(t, err, ErrForbidden)
+				return c.Status(StatusForbidden).SendString("missing " + denial.Permissions[0])
+			}
+			require.ErrorIs(t, err, ErrUnauthorized)
+			return err
+		},
+	})
+	app.Use(func(c Ctx) error {
+		c.Locals("user", c.Get("X-User"))
+		return c.Next()
+	})
+	app.Get("/reports", func(c Ctx) error {
+		return c.SendString("reports")
+	}).Require("reports:read")
+
+	req := httptest.NewRequest(MethodGet, "/reports", nil)
+	req.Header.Set("X-User", "alice")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+
+	req.Header.Set("X-User", "bob")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusForbidden, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "missing reports:read", string(body))
+
+	req = httptest.NewRequest(MethodGet, "/reports?fail=1", nil)
+	req.Header.Set("X-User", "alice")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+}
@@ -0,0 +1,105 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// EntityVersion is the version of an entity for optimistic concurrency control,
+// see Ctx.CheckPreconditions and Ctx.SetEntityVersion.
+type EntityVersion struct {
+	// ETag is the entity tag without quotes, e.g. a revision number or a hash.
+	// It is empty if the entity doesn't exist or has no entity tag.
+	ETag string
+	// Weak marks the entity tag as weak, weak tags never match If-Match.
+	Weak bool
+	// LastModified is the time of the last modification of the entity.
+	LastModified time.Time
+}
+
+// exists reports if the version belongs to an existing entity.
+func (v EntityVersion) exists() bool {
+	return v.ETag != "" || !v.LastModified.IsZero()
+}
+
+// SetEntityVersion sets the ETag and Last-Modified headers of the version, so clients
+// can send them back in the If-Match and If-Unmodified-Since headers of an update.
+func (c *DefaultCtx) SetEntityVersion(version EntityVersion) {
+	if version.ETag != "" {
+		etag := `"` + version.ETag + `"`
+		if version.Weak {
+			etag = "W/" + etag
+		}
+		c.Set(HeaderETag, etag)
+	}
+	if !version.LastModified.IsZero() {
+		c.Set(HeaderLastModified, version.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// CheckPreconditions evaluates the If-Match and If-Unmodified-Since headers of the request
+// against the current version of the entity, which is supplied by the handler (RFC 9110, 13.2.2).
+// ErrPreconditionFailed (412) is returned if the entity was changed in the meantime. If
+// Config.RequirePreconditions is set, ErrPreconditionRequired (428) is returned for PUT,
+// PATCH and DELETE requests without precondition, so lost updates are prevented.
+//
+//	app.Put("/articles/:id", func(c fiber.Ctx) error {
+//	    article := store.Get(c.Params("id"))
+//	    if err := c.CheckPreconditions(fiber.EntityVersion{ETag: article.Revision}); err != nil {
+//	        return err
+//	    }
+//	    // update the article
+//	})
+func (c *DefaultCtx) CheckPreconditions(version EntityVersion) error {
+	ifMatch := c.Get(HeaderIfMatch)
+	ifUnmodifiedSince := c.Get(HeaderIfUnmodifiedSince)
+
+	if ifMatch != "" {
+		if !matchETag(ifMatch, version) {
+			return ErrPreconditionFailed
+		}
+		return nil
+	}
+
+	// an invalid date is ignored
+	if since, err := http.ParseTime(ifUnmodifiedSince); ifUnmodifiedSince != "" && err == nil {
+		if !version.exists() || version.LastModified.Truncate(time.Second).After(since) {
+			return ErrPreconditionFailed
+		}
+		return nil
+	}
+
+	if c.app.config.RequirePreconditions {
+		switch c.Method() {
+		case MethodPut, MethodPatch, MethodDelete:
+			return ErrPreconditionRequired
+		}
+	}
+	return nil
+}
+
+// matchETag reports if the If-Match header matches the version with the strong comparison.
+func matchETag(ifMatch string, version EntityVersion) bool {
+	if strings.TrimSpace(ifMatch) == "*" {
+		return version.exists()
+	}
+	if version.ETag == "" || version.Weak {
+		return false
+	}
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		// weak tags never match with the strong comparison
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		if tag == `"`+version.ETag+`"` {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package fiber
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Ctx_SetEntityVersion
+func Test_Ctx_SetEntityVersion(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	c.SetEntityVersion(EntityVersion{ETag: "r42", LastModified: modified})
+	require.Equal(t, `"r42"`, string(c.Response().Header.Peek(HeaderETag)))
+	require.Equal(t, "Wed, 01 May 2024 10:00:00 GMT", string(c.Response().Header.Peek(HeaderLastModified)))
+
+	c.SetEntityVersion(EntityVersion{ETag: "r43", Weak: true})
+	require.Equal(t, `W/"r43"`, string(c.Response().Header.Peek(HeaderETag)))
+}
+
+// go test -run Test_Ctx_CheckPreconditions
+func Test_Ctx_CheckPreconditions(t *testing.T) {
+	t.Parallel()
+	modified := time.Date(2024, 5, 1, 10, 0, 0, 500, time.UTC)
+	version := EntityVersion{ETag: "r42", LastModified: modified}
+
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		version EntityVersion
+		require bool
+		err     error
+	}{
+		{name: "no precondition", method: MethodPut, version: version},
+		{name: "required", method: MethodPatch, version: version, require: true, err: ErrPreconditionRequired},
+		{name: "required for unsafe methods only", method: MethodGet, version: version, require: true},
+		{name: "match", method: MethodPut, headers: map[string]string{HeaderIfMatch: `"r41", "r42"`}, version: version, require: true},
+		{name: "mismatch", method: MethodPut, headers: map[string]string{HeaderIfMatch: `"r41"`}, version: version, err: ErrPreconditionFailed},
+		{name: "weak tag", method: MethodPut, headers: map[string]string{HeaderIfMatch: `W/"r42"`}, version: version, err: ErrPreconditionFailed},
+		{name: "weak version", method: MethodPut, headers: map[string]string{HeaderIfMatch: `"r42"`}, version: EntityVersion{ETag: "r42", Weak: true}, err: ErrPreconditionFailed},
+		{name: "any", method: MethodDelete, headers: map[string]string{HeaderIfMatch: "*"}, version: version},
+		{name: "any without entity", method: MethodPut, headers: map[string]string{HeaderIfMatch: "*"}, err: ErrPreconditionFailed},
+		{
+			name:    "if-match before if-unmodified-since",
+			method:  MethodPut,
+			headers: map[string]string{HeaderIfMatch: `"r42"`, HeaderIfUnmodifiedSince: modified.Add(-time.Hour).Format(http.TimeFormat)},
+			version: version,
+		},
+		{
+			name:    "unmodified",
+			method:  MethodPut,
+			headers: map[string]string{HeaderIfUnmodifiedSince: modified.Format(http.TimeFormat)},
+			version: version,
+			require: true,
+		},
+		{
+			name:    "modified",
+			method:  MethodPut,
+			headers: map[string]string{HeaderIfUnmodifiedSince: modified.Add(-time.Second).Format(http.TimeFormat)},
+			version: version,
+			err:     ErrPreconditionFailed,
+		},
+		{
+			name:    "invalid date",
+			method:  MethodPut,
+			headers: map[string]string{HeaderIfUnmodifiedSince: "yesterday"},
+			version: version,
+			require: true,
+			err:     ErrPreconditionRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		app := New(Config{RequirePreconditions: tt.require})
+		fctx := &fasthttp.RequestCtx{}
+		fctx.Request.Header.SetMethod(tt.method)
+		for key, value := range tt.headers {
+			fctx.Request.Header.Set(key, value)
+		}
+		c := app.AcquireCtx(fctx)
+		err := c.CheckPreconditions(tt.version)
+		if tt.err == nil {
+			require.NoError(t, err, tt.name)
+		} else {
+			require.ErrorIs(t, err, tt.err, tt.name)
+		}
+		app.ReleaseCtx(c)
+	}
+}
@@ -1,6 +1,7 @@
 package fiber
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -51,23 +53,42 @@ func (app *App) prefork(addr string, tlsConfig *tls.Config, cfg ListenConfig) er
 			}
 			return fmt.Errorf("prefork: %w", err)
 		}
+		// limit the connections of the child process
+		ln = app.limitListener(ln, cfg)
 		// wrap a tls config around the listener if provided
 		if tlsConfig != nil {
-			ln = tls.NewListener(ln, tlsConfig)
+			ln = tls.NewListener(ln, app.hookTLSConfig(tlsConfig))
 		}
 
 		// kill current child proc when master exits
 		go watchMaster()
 
+		// report the stats to the master process
+		if pipe := preforkStatsPipe(); pipe != nil {
+			go app.reportPreforkStats(pipe)
+		}
+
 		// prepare the server for the start
 		app.startupProcess()
 
+		// start plugins, the started plugins are stopped if one fails
+		if err = app.startPlugins(context.Background()); err != nil {
+			_ = ln.Close() //nolint:errcheck // It is fine to ignore the error here
+			return err
+		}
+
 		if cfg.ListenerAddrFunc != nil {
 			cfg.ListenerAddrFunc(ln.Addr())
 		}
 
-		// listen for incoming connections
-		return app.server.Serve(ln)
+		// listen for incoming connections while the warm-up hooks are executed
+		err = app.serveWarm(ln, func() error {
+			return app.server.Serve(ln)
+		})
+		if err != nil && app.config.CrashDumpDir != "" {
+			_, _ = app.writeCrashReport(err, debug.Stack(), nil) //nolint:errcheck // The error is returned
+		}
+		return err
 	}
 
 	// 👮 master process 👮
@@ -85,7 +106,7 @@ func (app *App) prefork(addr string, tlsConfig *tls.Config, cfg ListenConfig) er
 		for _, proc := range childs {
 			if err := proc.Process.Kill(); err != nil {
 				if !errors.Is(err, os.ErrProcessDone) {
-					log.Errorf("prefork: failed to kill child: %v", err)
+					app.logw(log.LevelError, "prefork: failed to kill child", "pid", proc.Process.Pid, "error", err)
 				}
 			}
 		}
@@ -111,12 +132,33 @@ func (app *App) prefork(addr string, tlsConfig *tls.Config, cfg ListenConfig) er
 			fmt.Sprintf("%s=%s", envPreforkChildKey, envPreforkChildVal),
 		)
 
-		if err = cmd.Start(); err != nil {
+		// collect the stats of the child with a pipe, extra files aren't supported on windows
+		var statsReader, statsWriter *os.File
+		if runtime.GOOS != "windows" {
+			if statsReader, statsWriter, err = os.Pipe(); err != nil {
+				return fmt.Errorf("prefork: failed to create stats pipe: %w", err)
+			}
+			cmd.ExtraFiles = []*os.File{statsWriter}
+			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%d", envPreforkStatsFDKey, preforkStatsFD))
+		}
+
+		err = cmd.Start()
+		if statsWriter != nil {
+			// the child owns the write end now
+			_ = statsWriter.Close() //nolint:errcheck // It is fine to ignore the error here
+		}
+		if err != nil {
+			if statsReader != nil {
+				_ = statsReader.Close() //nolint:errcheck // It is fine to ignore the error here
+			}
 			return fmt.Errorf("failed to start a child prefork process, error: %w", err)
 		}
 
 		// store child process
 		pid := cmd.Process.Pid
+		if statsReader != nil {
+			go app.collectPreforkStats(pid, statsReader)
+		}
 		childs[pid] = cmd
 		pids = append(pids, strconv.Itoa(pid))
 
@@ -0,0 +1,139 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// Profile is the environment the app runs in, it changes the defaults of the Config and the ListenConfig.
+type Profile string
+
+const (
+	// ProfileDevelopment keeps the defaults. If it is selected explicitly, it shows an error
+	// page with the details of server errors, including the stack trace of panics, to browsers.
+	ProfileDevelopment Profile = "development"
+	// ProfileStaging sends the DefaultSecurityHeaders, logs from the info level
+	// and adjusts the runtime to the limits of the container.
+	ProfileStaging Profile = "staging"
+	// ProfileProduction sends the DefaultSecurityHeaders, logs from the warn level, adjusts
+	// the runtime to the limits of the container and hides the messages of internal errors.
+	ProfileProduction Profile = "production"
+)
+
+// ProfileEnv is the environment variable which selects the profile if Config.Profile is empty,
+// e.g. FIBER_PROFILE=production.
+const ProfileEnv = "FIBER_PROFILE"
+
+// DefaultSecurityHeaders are the security headers of the staging and production profiles.
+// Strict-Transport-Security is only sent with HTTPS responses.
+var DefaultSecurityHeaders = map[string]string{
+	HeaderXContentTypeOptions:       "nosniff",
+	HeaderXFrameOptions:             "DENY",
+	HeaderReferrerPolicy:            "no-referrer",
+	HeaderCrossOriginResourcePolicy: "same-origin",
+	HeaderStrictTransportSecurity:   "max-age=63072000; includeSubDomains",
+}
+
+// ParseProfile parses the name of a profile, the short names "dev", "stage" and "prod" are accepted too.
+func ParseProfile(name string) (Profile, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "dev", string(ProfileDevelopment):
+		return ProfileDevelopment, nil
+	case "stage", string(ProfileStaging):
+		return ProfileStaging, nil
+	case "prod", string(ProfileProduction):
+		return ProfileProduction, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownProfile, name)
+	}
+}
+
+// Profile returns the active profile of the app.
+func (app *App) Profile() Profile {
+	return app.config.Profile
+}
+
+// applyProfile selects the profile and sets the defaults of the profile for the fields which weren't configured.
+// An unknown profile of the config panics, the error of an unknown profile of the environment variable is
+// returned to be logged and the default profile is kept.
+func (app *App) applyProfile() error {
+	var envErr error
+	profile := ProfileDevelopment
+	if app.config.Profile != "" {
+		var err error
+		if profile, err = ParseProfile(string(app.config.Profile)); err != nil {
+			panic(err)
+		}
+		app.profileSelected = true
+	} else if name := os.Getenv(ProfileEnv); name != "" {
+		if parsed, err := ParseProfile(name); err != nil {
+			envErr = err
+		} else {
+			profile = parsed
+			app.profileSelected = true
+		}
+	}
+	app.config.Profile = profile
+
+	if profile == ProfileDevelopment {
+		return envErr
+	}
+	if app.config.SecurityHeaders == nil {
+		app.config.SecurityHeaders = DefaultSecurityHeaders
+	}
+	if app.config.LogLevel == log.LevelTrace {
+		app.config.LogLevel = log.LevelInfo
+		if profile == ProfileProduction {
+			app.config.LogLevel = log.LevelWarn
+		}
+	}
+	return envErr
+}
+
+// profileListenConfig sets the defaults of the profile of the app for the listen config.
+func (app *App) profileListenConfig(cfg ListenConfig) ListenConfig {
+	if app.config.Profile != ProfileDevelopment {
+		cfg.EnableContainerLimits = true
+	}
+	return cfg
+}
+
+// setSecurityHeaders sets the security headers of the config on the response.
+func (app *App) setSecurityHeaders(c Ctx) {
+	for key, value := range app.config.SecurityHeaders {
+		if key == HeaderStrictTransportSecurity && c.Scheme() != schemeHTTPS {
+			continue
+		}
+		c.Response().Header.Set(key, value)
+	}
+}
+
+// developmentErrorPage sends the error page of the development profile.
+func developmentErrorPage(c Ctx, code int, err error) error {
+	var stack string
+	var p *PanicError
+	if errors.As(err, &p) {
+		stack = string(p.Stack)
+	}
+	c.Status(code)
+	return c.HTML(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>%d %s</title></head>
+<body>
+<h1>%d %s</h1>
+<p><code>%s %s</code></p>
+<pre>%s</pre>
+<pre>%s</pre>
+<p>This page is shown by the development profile, see fiber.Config.Profile.</p>
+</body>
+</html>`, code, utils.StatusMessage(code), code, utils.StatusMessage(code), c.Method(), c.OriginalURL(), err.Error(), stack)
+}
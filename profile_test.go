@@ -0,0 +1,167 @@
+package fiber
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_ParseProfile
+func Test_ParseProfile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		profile Profile
+	}{
+		{name: "dev", profile: ProfileDevelopment},
+		{name: "development", profile: ProfileDevelopment},
+		{name: "stage", profile: ProfileStaging},
+		{name: " Staging ", profile: ProfileStaging},
+		{name: "PROD", profile: ProfileProduction},
+		{name: "production", profile: ProfileProduction},
+	}
+	for _, tt := range tests {
+		profile, err := ParseProfile(tt.name)
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.profile, profile, tt.name)
+	}
+
+	_, err := ParseProfile("test")
+	require.ErrorIs(t, err, ErrUnknownProfile)
+}
+
+// go test -run Test_App_Profile
+func Test_App_Profile(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	app := New()
+	require.Equal(t, ProfileDevelopment, app.Profile())
+	require.Nil(t, app.Config().SecurityHeaders)
+	require.Equal(t, log.LevelTrace, app.LogLevel())
+	require.False(t, app.profileListenConfig(ListenConfig{}).EnableContainerLimits)
+
+	app = New(Config{Profile: "prod"})
+	require.Equal(t, ProfileProduction, app.Profile())
+	require.Equal(t, DefaultSecurityHeaders, app.Config().SecurityHeaders)
+	require.Equal(t, log.LevelWarn, app.LogLevel())
+	require.True(t, app.profileListenConfig(ListenConfig{}).EnableContainerLimits)
+
+	// the profile is read from the environment variable
+	t.Setenv(ProfileEnv, "stage")
+	app = New()
+	require.Equal(t, ProfileStaging, app.Profile())
+	require.Equal(t, log.LevelInfo, app.LogLevel())
+
+	// the configured values are kept
+	app = New(Config{LogLevel: log.LevelError, SecurityHeaders: map[string]string{}})
+	require.Equal(t, log.LevelError, app.LogLevel())
+	require.Empty(t, app.Config().SecurityHeaders)
+
+	// an unknown profile of the environment variable is ignored
+	t.Setenv(ProfileEnv, "test")
+	app = New()
+	require.Equal(t, ProfileDevelopment, app.Profile())
+	require.False(t, app.profileSelected)
+
+	require.PanicsWithError(t, `profile: unknown profile: "test"`, func() {
+		New(Config{Profile: "test"})
+	})
+}
+
+// go test -run Test_App_Profile_Production
+func Test_App_Profile_Production(t *testing.T) {
+	t.Parallel()
+	app := New(Config{Profile: ProfileProduction})
+	app.Get("/internal", func(_ Ctx) error {
+		return errors.New("db: connection refused")
+	})
+	app.Get("/public", func(_ Ctx) error {
+		return ErrTeapot
+	})
+	app.Get("/", func(c Ctx) error {
+		c.Set(HeaderXFrameOptions, "SAMEORIGIN")
+		return nil
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, "nosniff", resp.Header.Get(HeaderXContentTypeOptions))
+	require.Equal(t, "no-referrer", resp.Header.Get(HeaderReferrerPolicy))
+	require.Equal(t, "SAMEORIGIN", resp.Header.Get(HeaderXFrameOptions))
+	require.Empty(t, resp.Header.Get(HeaderStrictTransportSecurity))
+
+	// Strict-Transport-Security is only sent with HTTPS responses
+	req := httptest.NewRequest(MethodGet, "/", nil)
+	req.Header.Set(HeaderXForwardedProto, schemeHTTPS)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, "max-age=63072000; includeSubDomains", resp.Header.Get(HeaderStrictTransportSecurity))
+
+	// the messages of internal errors are hidden
+	status, body := testRequestBody(t, app, "/internal")
+	require.Equal(t, StatusInternalServerError, status)
+	require.Equal(t, "Internal Server Error", body)
+
+	status, body = testRequestBody(t, app, "/public")
+	require.Equal(t, StatusTeapot, status)
+	require.Equal(t, "I'm a teapot", body)
+}
+
+// go test -run Test_App_Profile_DevelopmentErrorPage
+func Test_App_Profile_DevelopmentErrorPage(t *testing.T) {
+	t.Parallel()
+	app := New(Config{Profile: ProfileDevelopment, PanicPolicy: PanicPolicyErrorHandler})
+	app.Get("/panic", func(_ Ctx) error {
+		panic("<boom>")
+	})
+	app.Get("/missing", func(_ Ctx) error {
+		return ErrNotFound
+	})
+
+	req := httptest.NewRequest(MethodGet, "/panic?id=1", nil)
+	req.Header.Set(HeaderAccept, "text/html,application/xhtml+xml")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+	require.Equal(t, MIMETextHTMLCharsetUTF8, resp.Header.Get(HeaderContentType))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "<h1>500 Internal Server Error</h1>")
+	require.Contains(t, string(body), "<code>GET /panic?id=1</code>")
+	require.Contains(t, string(body), "panic: &lt;boom&gt;")
+	require.Contains(t, string(body), "profile_test.go")
+
+	// clients which don't accept HTML and client errors get the plain message
+	status, body2 := testRequestBody(t, app, "/panic")
+	require.Equal(t, StatusInternalServerError, status)
+	require.Equal(t, "panic: <boom>", body2)
+
+	req = httptest.NewRequest(MethodGet, "/missing", nil)
+	req.Header.Set(HeaderAccept, "text/html")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, MIMETextPlainCharsetUTF8, resp.Header.Get(HeaderContentType))
+}
+
+// go test -run Test_App_Profile_DefaultErrorPage
+func Test_App_Profile_DefaultErrorPage(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	app := New(Config{PanicPolicy: PanicPolicyErrorHandler})
+	app.Get("/panic", func(_ Ctx) error {
+		panic("<boom>")
+	})
+
+	// the error page is only shown if the development profile is selected
+	req := httptest.NewRequest(MethodGet, "/panic", nil)
+	req.Header.Set(HeaderAccept, "text/html")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+	require.Equal(t, MIMETextPlainCharsetUTF8, resp.Header.Get(HeaderContentType))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "panic: <boom>", string(body))
+}
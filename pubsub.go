@@ -0,0 +1,19 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import "context"
+
+// PubSub sends messages to the instances of an app, e.g. with Redis or NATS, so
+// the events of one instance reach the clients which are connected to the others.
+type PubSub interface {
+	// Publish sends the message to the subscribers of the channel on all instances,
+	// including the publishing instance.
+	Publish(ctx context.Context, channel string, message []byte) error
+
+	// Subscribe calls fn with the messages of the channel.
+	// It blocks until the context is done or subscribing failed.
+	Subscribe(ctx context.Context, channel string, fn func(message []byte)) error
+}
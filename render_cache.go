@@ -0,0 +1,242 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"hash/crc32"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/utils/v2"
+	"github.com/valyala/bytebufferpool"
+)
+
+// DefaultRenderCacheTTL is the default time a rendered page is cached.
+const DefaultRenderCacheTTL = time.Minute
+
+// RenderCacheRule configures the caching of a template, see RenderCache.Cache.
+type RenderCacheRule struct {
+	// TTL is the time a rendered page is cached.
+	//
+	// Optional. Default: DefaultRenderCacheTTL
+	TTL time.Duration
+
+	// Key returns the cache key of the page of a request, the template is rendered
+	// once per key, e.g. per locale. The key must contain everything the page
+	// depends on, including the layouts if they differ between requests.
+	//
+	// Optional. Default: c.OriginalURL()
+	Key func(c Ctx) string
+}
+
+// RenderCache caches the pages rendered by Ctx.Render for the templates with a rule, so
+// mostly static pages are rendered once per TTL. Cached pages are sent with ETag and
+// Last-Modified headers and conditional requests are answered with 304 Not Modified.
+//
+//	cache := fiber.NewRenderCache(10_000).
+//	    Cache("index", fiber.RenderCacheRule{TTL: 5 * time.Minute}).
+//	    Cache("article", fiber.RenderCacheRule{Key: func(c fiber.Ctx) string { return c.Params("slug") }})
+//
+//	app := fiber.New(fiber.Config{Views: engine, RenderCache: cache})
+//
+// The cache only skips the rendering, the handler still computes the bind data.
+type RenderCache struct {
+	rules        map[string]RenderCacheRule
+	entries      map[renderCacheKey]*renderCacheEntry
+	onInvalidate []func(name, key string)
+	maxEntries   int
+	mutex        sync.RWMutex
+}
+
+// renderCacheKey is the key of a rendered page.
+type renderCacheKey struct {
+	name string
+	key  string
+}
+
+// renderCacheEntry is a rendered page.
+type renderCacheEntry struct {
+	body     []byte
+	etag     string
+	modified time.Time
+	expires  time.Time
+}
+
+// NewRenderCache creates a RenderCache which caches up to maxEntries pages, pages which
+// don't fit are rendered for every request. A maxEntries <= 0 disables the limit.
+func NewRenderCache(maxEntries int) *RenderCache {
+	return &RenderCache{
+		rules:      make(map[string]RenderCacheRule),
+		entries:    make(map[renderCacheKey]*renderCacheEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+// Cache caches the pages of the template with the rule.
+func (r *RenderCache) Cache(name string, rule RenderCacheRule) *RenderCache {
+	if rule.TTL <= 0 {
+		rule.TTL = DefaultRenderCacheTTL
+	}
+	r.mutex.Lock()
+	r.rules[name] = rule
+	r.mutex.Unlock()
+	return r
+}
+
+// OnInvalidate registers a hook which is called for every page removed by Invalidate,
+// InvalidateKey or Purge, e.g. to purge the page from a CDN.
+func (r *RenderCache) OnInvalidate(hook func(name, key string)) {
+	r.mutex.Lock()
+	r.onInvalidate = append(r.onInvalidate, hook)
+	r.mutex.Unlock()
+}
+
+// Invalidate removes the pages of the templates, e.g. after their data changed.
+func (r *RenderCache) Invalidate(names ...string) {
+	r.remove(func(k renderCacheKey) bool {
+		return containsString(names, k.name)
+	})
+}
+
+// InvalidateKey removes the page of the template with the key.
+func (r *RenderCache) InvalidateKey(name, key string) {
+	r.remove(func(k renderCacheKey) bool {
+		return k.name == name && k.key == key
+	})
+}
+
+// Purge removes all pages.
+func (r *RenderCache) Purge() {
+	r.remove(func(renderCacheKey) bool {
+		return true
+	})
+}
+
+// Len returns the number of cached pages, including expired pages which weren't removed yet.
+func (r *RenderCache) Len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return len(r.entries)
+}
+
+// remove removes the pages whose key matches and calls the hooks.
+func (r *RenderCache) remove(match func(k renderCacheKey) bool) {
+	r.mutex.Lock()
+	var removed []renderCacheKey
+	for k := range r.entries {
+		if match(k) {
+			removed = append(removed, k)
+			delete(r.entries, k)
+		}
+	}
+	hooks := r.onInvalidate
+	r.mutex.Unlock()
+
+	for _, k := range removed {
+		for _, hook := range hooks {
+			hook(k.name, k.key)
+		}
+	}
+}
+
+// rule returns the rule of the template.
+func (r *RenderCache) rule(name string) (RenderCacheRule, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	rule, ok := r.rules[name]
+	return rule, ok
+}
+
+// get returns the page with the key if it isn't expired.
+func (r *RenderCache) get(k renderCacheKey) (*renderCacheEntry, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	entry, ok := r.entries[k]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// set creates the page with the key from the body, it is cached if there is room.
+func (r *RenderCache) set(k renderCacheKey, body []byte, ttl time.Duration) *renderCacheEntry {
+	now := time.Now()
+	entry := &renderCacheEntry{
+		body:     append([]byte(nil), body...),
+		etag:     `"` + strconv.Itoa(len(body)) + "-" + strconv.FormatUint(uint64(crc32.ChecksumIEEE(body)), 10) + `"`,
+		modified: now,
+		expires:  now.Add(ttl),
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	existing, ok := r.entries[k]
+	if ok {
+		// an unchanged page keeps its modification time
+		if existing.etag == entry.etag {
+			entry.modified = existing.modified
+		}
+	} else if r.maxEntries > 0 && len(r.entries) >= r.maxEntries {
+		for key, e := range r.entries {
+			if now.After(e.expires) {
+				delete(r.entries, key)
+			}
+		}
+		if len(r.entries) >= r.maxEntries {
+			return entry
+		}
+	}
+	r.entries[renderCacheKey{name: utils.CopyString(k.name), key: utils.CopyString(k.key)}] = entry
+	return entry
+}
+
+// renderCached renders the template with the rule of the render cache.
+func (c *DefaultCtx) renderCached(cache *RenderCache, rule RenderCacheRule, name string, bind Map, layouts []string) error {
+	// the key may reference the request, it is copied when the page is cached
+	k := renderCacheKey{name: name}
+	if rule.Key != nil {
+		k.key = rule.Key(c)
+	} else {
+		k.key = c.OriginalURL()
+	}
+
+	entry, ok := cache.get(k)
+	if !ok {
+		buf := bytebufferpool.Get()
+		defer bytebufferpool.Put(buf)
+		if err := c.renderTemplate(buf, name, bind, layouts); err != nil {
+			return err
+		}
+		entry = cache.set(k, buf.Bytes(), rule.TTL)
+	}
+
+	c.fasthttp.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
+	c.Set(HeaderETag, entry.etag)
+	c.Set(HeaderLastModified, entry.modified.UTC().Format(http.TimeFormat))
+	if c.notModified(entry) {
+		c.fasthttp.Response.ResetBody()
+		c.Status(StatusNotModified)
+		return nil
+	}
+	c.fasthttp.Response.SetBody(entry.body)
+	return nil
+}
+
+// notModified reports if the conditional GET or HEAD request matches the page.
+func (c *DefaultCtx) notModified(entry *renderCacheEntry) bool {
+	if c.Method() != MethodGet && c.Method() != MethodHead {
+		return false
+	}
+	if noneMatch := c.Get(HeaderIfNoneMatch); noneMatch != "" {
+		return noneMatch == "*" || !c.app.isEtagStale(entry.etag, c.app.getBytes(noneMatch))
+	}
+	if modifiedSince := c.Get(HeaderIfModifiedSince); modifiedSince != "" {
+		since, err := http.ParseTime(modifiedSince)
+		return err == nil && !entry.modified.Truncate(time.Second).After(since)
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package fiber
+
+import (
+	"io"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingTemplateEngine counts the renders of the test template engine.
+type countingTemplateEngine struct {
+	testTemplateEngine
+	renders atomic.Int32
+}
+
+func (t *countingTemplateEngine) Render(w io.Writer, name string, bind any, layout ...string) error {
+	t.renders.Add(1)
+	return t.testTemplateEngine.Render(w, name, bind, layout...)
+}
+
+// go test -run Test_RenderCache
+func Test_RenderCache(t *testing.T) {
+	t.Parallel()
+	engine := &countingTemplateEngine{}
+	cache := NewRenderCache(0).Cache("index.tmpl", RenderCacheRule{TTL: time.Hour})
+	app := New(Config{Views: engine, RenderCache: cache})
+	app.Get("/", func(c Ctx) error {
+		return c.Render("index.tmpl", Map{"Title": "Hello, World!"})
+	})
+	app.Get("/uncached", func(c Ctx) error {
+		return c.Render("hello_world.tmpl", Map{"Name": "World"})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	etag := resp.Header.Get(HeaderETag)
+	lastModified := resp.Header.Get(HeaderLastModified)
+	require.Regexp(t, `^"\d+-\d+"$`, etag)
+	require.NotEmpty(t, lastModified)
+	require.Equal(t, MIMETextHTMLCharsetUTF8, resp.Header.Get(HeaderContentType))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Hello, World!</h1>", string(body))
+
+	// the cached page is sent again
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, etag, resp.Header.Get(HeaderETag))
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Hello, World!</h1>", string(body))
+	require.Equal(t, int32(1), engine.renders.Load())
+	require.Equal(t, 1, cache.Len())
+
+	// conditional requests
+	req := httptest.NewRequest(MethodGet, "/", nil)
+	req.Header.Set(HeaderIfNoneMatch, etag)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusNotModified, resp.StatusCode)
+
+	req = httptest.NewRequest(MethodGet, "/", nil)
+	req.Header.Set(HeaderIfNoneMatch, `"0-0"`)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest(MethodGet, "/", nil)
+	req.Header.Set(HeaderIfModifiedSince, lastModified)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusNotModified, resp.StatusCode)
+
+	req = httptest.NewRequest(MethodGet, "/", nil)
+	req.Header.Set(HeaderIfModifiedSince, "Mon, 01 Jan 2001 00:00:00 GMT")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, int32(1), engine.renders.Load())
+
+	// templates without rule are not cached
+	for i := 0; i < 2; i++ {
+		resp, err = app.Test(httptest.NewRequest(MethodGet, "/uncached", nil))
+		require.NoError(t, err)
+		require.Empty(t, resp.Header.Get(HeaderETag))
+	}
+	require.Equal(t, int32(3), engine.renders.Load())
+}
+
+// go test -run Test_RenderCache_Key
+func Test_RenderCache_Key(t *testing.T) {
+	t.Parallel()
+	engine := &countingTemplateEngine{}
+	cache := NewRenderCache(0).Cache("index.tmpl", RenderCacheRule{
+		Key: func(c Ctx) string {
+			return c.Query("lang")
+		},
+	})
+	app := New(Config{Views: engine, RenderCache: cache})
+	app.Get("/", func(c Ctx) error {
+		return c.Render("index.tmpl", Map{"Title": c.Query("lang")})
+	})
+
+	for _, url := range []string{"/?lang=en", "/?lang=de", "/?lang=en&page=2"} {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, url, nil))
+		require.NoError(t, err)
+		require.Equal(t, StatusOK, resp.StatusCode)
+	}
+	require.Equal(t, int32(2), engine.renders.Load())
+	require.Equal(t, 2, cache.Len())
+}
+
+// go test -run Test_RenderCache_Invalidate
+func Test_RenderCache_Invalidate(t *testing.T) {
+	t.Parallel()
+	engine := &countingTemplateEngine{}
+	cache := NewRenderCache(0).
+		Cache("index.tmpl", RenderCacheRule{}).
+		Cache("hello_world.tmpl", RenderCacheRule{})
+	var invalidated []string
+	cache.OnInvalidate(func(name, key string) {
+		invalidated = append(invalidated, name+" "+key)
+	})
+	app := New(Config{Views: engine, RenderCache: cache})
+	app.Get("/index", func(c Ctx) error {
+		return c.Render("index.tmpl", Map{"Title": "Hello"})
+	})
+	app.Get("/hello", func(c Ctx) error {
+		return c.Render("hello_world.tmpl", Map{"Name": "World"})
+	})
+	request := func(url string) {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, url, nil))
+		require.NoError(t, err)
+		require.Equal(t, StatusOK, resp.StatusCode)
+	}
+
+	request("/index")
+	request("/hello")
+	require.Equal(t, 2, cache.Len())
+
+	cache.Invalidate("index.tmpl")
+	require.Equal(t, []string{"index.tmpl /index"}, invalidated)
+	require.Equal(t, 1, cache.Len())
+	request("/index")
+	require.Equal(t, int32(3), engine.renders.Load())
+
+	cache.InvalidateKey("hello_world.tmpl", "/other")
+	require.Equal(t, 2, cache.Len())
+	cache.InvalidateKey("hello_world.tmpl", "/hello")
+	require.Equal(t, 1, cache.Len())
+
+	cache.Purge()
+	require.Equal(t, 0, cache.Len())
+	require.Len(t, invalidated, 3)
+}
+
+// go test -run Test_RenderCache_Expiration
+func Test_RenderCache_Expiration(t *testing.T) {
+	t.Parallel()
+	engine := &countingTemplateEngine{}
+	cache := NewRenderCache(1).Cache("index.tmpl", RenderCacheRule{TTL: 20 * time.Millisecond})
+	app := New(Config{Views: engine, RenderCache: cache})
+	app.Get("/*", func(c Ctx) error {
+		return c.Render("index.tmpl", Map{"Title": "Hello"})
+	})
+	request := func(url string) string {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, url, nil))
+		require.NoError(t, err)
+		require.Equal(t, StatusOK, resp.StatusCode)
+		return resp.Header.Get(HeaderLastModified)
+	}
+
+	lastModified := request("/a")
+	// the cache is full, the page is rendered for every request
+	request("/b")
+	request("/b")
+	require.Equal(t, int32(3), engine.renders.Load())
+	require.Equal(t, 1, cache.Len())
+
+	time.Sleep(30 * time.Millisecond)
+	// the expired page is rendered again and keeps its modification time
+	require.Equal(t, lastModified, request("/a"))
+	require.Equal(t, int32(4), engine.renders.Load())
+	request("/a")
+	require.Equal(t, int32(4), engine.renders.Load())
+}
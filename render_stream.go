@@ -0,0 +1,120 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bufio"
+	"bytes"
+	"html/template"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// flushMarker is written by the flush template function, it is an HTML comment
+// so it is harmless if a template with flush points is rendered by Ctx.Render.
+const flushMarker = "<!--fiber:flush-->"
+
+// StreamFuncMap returns the template functions of Ctx.RenderStream, they are
+// available in the templates without view engine. Register them with the view
+// engine to use flush points in its templates:
+//
+//	engine.AddFuncMap(fiber.StreamFuncMap())
+//
+//	<head>...</head>
+//	{{ flush }}
+//	<body>...</body>
+func StreamFuncMap() map[string]any {
+	return map[string]any{
+		"flush": func() template.HTML {
+			return flushMarker
+		},
+	}
+}
+
+// RenderStream renders a template like Render, but sends the page progressively: the
+// output up to a {{ flush }} point of the template is sent to the client while the
+// rest of the template is executed, e.g. the head of a page while slow data is loaded.
+//
+// The template is executed in the handler until its first flush point, errors before
+// are returned like by Render. After the first flush point the status and headers are
+// sent, errors can't change the response anymore, they are logged and the page is
+// truncated. A template without flush point is sent as a whole. The rest of the template
+// is executed after the handler returned, functions of the bind data must not access the Ctx.
+func (c *DefaultCtx) RenderStream(name string, bind Map, layouts ...string) error {
+	execute, err := c.prepareRender(name, bind, layouts)
+	if err != nil {
+		return err
+	}
+
+	w := &flushWriter{chunks: make(chan []byte)}
+	result := make(chan error, 1)
+	go func() {
+		result <- execute(w)
+		close(w.chunks)
+	}()
+
+	// the template is executed until the first flush point
+	first, ok := <-w.chunks
+	if !ok {
+		if err := <-result; err != nil {
+			return err
+		}
+		c.fasthttp.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
+		c.fasthttp.Response.SetBody(w.buf)
+		return nil
+	}
+
+	c.fasthttp.Response.Header.SetContentType(MIMETextHTMLCharsetUTF8)
+	return c.SendStreamWriter(func(bw *bufio.Writer) {
+		// the chunks are consumed after a write error, so the template is executed to the end
+		failed := false
+		write := func(chunk []byte) {
+			if failed {
+				return
+			}
+			if _, err := bw.Write(chunk); err != nil {
+				failed = true
+				return
+			}
+			if err := bw.Flush(); err != nil {
+				failed = true
+			}
+		}
+
+		write(first)
+		for chunk := range w.chunks {
+			write(chunk)
+		}
+		if err := <-result; err != nil {
+			log.Errorf("failed to stream template %s: %v", name, err)
+			return
+		}
+		write(w.buf)
+	})
+}
+
+// flushWriter collects the output of a template and sends it as a chunk at every flush point.
+type flushWriter struct {
+	chunks chan []byte
+	buf    []byte
+}
+
+// Write implements io.Writer.
+func (w *flushWriter) Write(p []byte) (int, error) {
+	// the marker may be split across writes
+	from := max(len(w.buf)-len(flushMarker)+1, 0)
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.Index(w.buf[from:], []byte(flushMarker))
+		if i < 0 {
+			return len(p), nil
+		}
+		i += from
+		chunk := w.buf[:i]
+		w.buf = append([]byte(nil), w.buf[i+len(flushMarker):]...)
+		w.chunks <- chunk
+		from = 0
+	}
+}
@@ -0,0 +1,159 @@
+package fiber
+
+import (
+	"bufio"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// streamTemplateEngine renders html templates with the stream functions.
+type streamTemplateEngine struct {
+	templates *template.Template
+}
+
+func (e *streamTemplateEngine) Load() error {
+	return nil
+}
+
+func (e *streamTemplateEngine) Render(w io.Writer, name string, bind any, _ ...string) error {
+	return e.templates.ExecuteTemplate(w, name, bind) //nolint:wrapcheck // the error is wrapped by Render
+}
+
+func newStreamTemplateEngine(t *testing.T) *streamTemplateEngine {
+	t.Helper()
+	templates := template.Must(template.New("").Funcs(StreamFuncMap()).Parse(
+		`{{ define "page" }}<head>{{ .Title }}</head>{{ flush }}<body>{{ call .Body }}</body>{{ end }}`))
+	return &streamTemplateEngine{templates: templates}
+}
+
+// go test -run Test_Ctx_RenderStream
+func Test_Ctx_RenderStream(t *testing.T) {
+	t.Parallel()
+	app := New(Config{Views: newStreamTemplateEngine(t)})
+	release := make(chan struct{})
+	app.Get("/", func(c Ctx) error {
+		return c.RenderStream("page", Map{
+			"Title": "Stream",
+			"Body": func() string {
+				<-release
+				return "loaded"
+			},
+		})
+	})
+
+	ln := fasthttputil.NewInmemoryListener()
+	go func() {
+		assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+	}()
+	conn, err := ln.Dial()
+	require.NoError(t, err)
+	defer conn.Close() //nolint:errcheck // It is fine to ignore the error here
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	require.NoError(t, err)
+
+	// the head is sent before the body is loaded
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, MIMETextHTMLCharsetUTF8, resp.Header.Get(HeaderContentType))
+	head := make([]byte, len("<head>Stream</head>"))
+	_, err = io.ReadFull(resp.Body, head)
+	require.NoError(t, err)
+	require.Equal(t, "<head>Stream</head>", string(head))
+
+	close(release)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "<body>loaded</body>", string(body))
+}
+
+// go test -run Test_Ctx_RenderStream_Errors
+func Test_Ctx_RenderStream_Errors(t *testing.T) {
+	t.Parallel()
+	app := New(Config{Views: newStreamTemplateEngine(t)})
+	app.Get("/", func(c Ctx) error {
+		return c.RenderStream("page", Map{
+			"Title": "Stream",
+			"Body": func() (string, error) {
+				return "", errors.New("failed to load")
+			},
+		})
+	})
+	app.Get("/missing", func(c Ctx) error {
+		return c.RenderStream("missing", nil)
+	})
+
+	// errors before the first flush point are returned
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/missing", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+
+	// errors after the first flush point truncate the page
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "<head>Stream</head>", string(body))
+}
+
+// go test -run Test_Ctx_RenderStream_WithoutEngine
+func Test_Ctx_RenderStream_WithoutEngine(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	withFlush := filepath.Join(dir, "flush.tmpl")
+	require.NoError(t, os.WriteFile(withFlush, []byte("<h1>{{ .Title }}</h1>{{ flush }}<p>{{ .Text }}</p>{{ flush }}"), 0o600))
+	withoutFlush := filepath.Join(dir, "plain.tmpl")
+	require.NoError(t, os.WriteFile(withoutFlush, []byte("<h1>{{ .Title }}</h1>"), 0o600))
+
+	app := New()
+	app.Get("/:name", func(c Ctx) error {
+		return c.RenderStream(filepath.Join(dir, c.Params("name")+".tmpl"), Map{"Title": "Hello", "Text": "World"})
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/flush", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Hello</h1><p>World</p>", string(body))
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/plain", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, MIMETextHTMLCharsetUTF8, resp.Header.Get(HeaderContentType))
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Hello</h1>", string(body))
+}
+
+// go test -run Test_FlushWriter
+func Test_FlushWriter(t *testing.T) {
+	t.Parallel()
+	w := &flushWriter{chunks: make(chan []byte, 10)}
+
+	// the marker is split across writes
+	for _, p := range []string{"a", flushMarker[:5], flushMarker[5:] + "b" + flushMarker + flushMarker, "c"} {
+		n, err := w.Write([]byte(p))
+		require.NoError(t, err)
+		require.Equal(t, len(p), n)
+	}
+	close(w.chunks)
+
+	var chunks []string
+	for chunk := range w.chunks {
+		chunks = append(chunks, string(chunk))
+	}
+	require.Equal(t, []string{"a", "b", ""}, chunks)
+	require.Equal(t, "c", string(w.buf))
+}
@@ -0,0 +1,141 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/valyala/bytebufferpool"
+)
+
+// RenderView is the data of a handler which is rendered by a Renderer.
+type RenderView struct {
+	// Bind is the data of the handler, which is also bound to the template.
+	Bind Map
+	// Name is the name of the template, empty if the handler has none.
+	Name string
+	// BaseURL is the base URL of the request, e.g. to resolve the relative links of the HTML.
+	BaseURL string
+	// HTML is the rendered template, nil if the handler has none.
+	HTML []byte
+}
+
+// Renderer renders the data of handlers into binary documents, e.g. PDFs of the HTML with
+// wkhtmltopdf or a headless Chromium, or images. The renderers are registered with
+// RegisterRenderer and selected by Negotiate with the Accept header.
+type Renderer interface {
+	// MediaType returns the media type of the documents, e.g. "application/pdf".
+	MediaType() string
+	// Render writes the document of the view. The context is the user context of the request.
+	Render(ctx context.Context, out io.Writer, view RenderView) error
+}
+
+// RegisterRenderer registers a renderer for Negotiate. The renderers are offered in
+// registration order after HTML and JSON. They must be registered before the app serves requests.
+//
+//	app.RegisterRenderer(&fiber.CommandRenderer{
+//	    Type:    "application/pdf",
+//	    Command: "wkhtmltopdf",
+//	    Args:    []string{"--quiet", "-", "-"},
+//	})
+func (app *App) RegisterRenderer(renderer Renderer) {
+	app.mutex.Lock()
+	app.renderers = append(app.renderers, renderer)
+	app.mutex.Unlock()
+}
+
+// Negotiate responds with the data of the handler in the format which the Accept header prefers:
+// the template rendered as HTML, the bind as JSON, or a document of a registered Renderer, e.g. a PDF.
+// Without template name, only JSON and the renderers are offered. The first offer is used without
+// Accept header, if no offer is acceptable, StatusNotAcceptable is sent.
+func (c *DefaultCtx) Negotiate(name string, bind Map, layouts ...string) error {
+	offers := make([]string, 0, 2+len(c.app.renderers))
+	if name != "" {
+		offers = append(offers, MIMETextHTML)
+	}
+	offers = append(offers, MIMEApplicationJSON)
+	for _, renderer := range c.app.renderers {
+		offers = append(offers, renderer.MediaType())
+	}
+
+	c.VaryBy(HeaderAccept)
+	accept := offers[0]
+	if c.Get(HeaderAccept) != "" {
+		if accept = c.Accepts(offers...); accept == "" {
+			return c.SendStatus(StatusNotAcceptable)
+		}
+	}
+
+	switch accept {
+	case MIMETextHTML:
+		return c.Render(name, bind, layouts...)
+	case MIMEApplicationJSON:
+		return c.JSON(bind)
+	}
+
+	var renderer Renderer
+	for _, r := range c.app.renderers {
+		if r.MediaType() == accept {
+			renderer = r
+			break
+		}
+	}
+	view := RenderView{Bind: bind, Name: name, BaseURL: c.BaseURL()}
+	if name != "" {
+		buf := bytebufferpool.Get()
+		defer bytebufferpool.Put(buf)
+		if err := c.renderTemplate(buf, name, bind, layouts); err != nil {
+			return err
+		}
+		view.HTML = buf.Bytes()
+	}
+
+	out := bytebufferpool.Get()
+	defer bytebufferpool.Put(out)
+	if err := renderer.Render(c.UserContext(), out, view); err != nil {
+		return fmt.Errorf("render: failed to render %s: %w", accept, err)
+	}
+	c.fasthttp.Response.Header.SetContentType(accept)
+	c.fasthttp.Response.SetBody(out.Bytes())
+	return nil
+}
+
+// CommandRenderer is a Renderer which pipes the HTML of the template through a command, which
+// reads the HTML from stdin and writes the document to stdout, e.g. wkhtmltopdf. The command
+// is killed when the request is canceled.
+type CommandRenderer struct {
+	// Type is the media type of the documents of the command, e.g. "application/pdf".
+	Type string
+	// Command is the name or the path of the command.
+	Command string
+	// Args are the arguments of the command.
+	Args []string
+}
+
+// MediaType returns the media type of the documents.
+func (r *CommandRenderer) MediaType() string {
+	return r.Type
+}
+
+// Render runs the command with the HTML of the view, views without template can't be rendered.
+func (r *CommandRenderer) Render(ctx context.Context, out io.Writer, view RenderView) error {
+	if view.HTML == nil {
+		return ErrRenderNoTemplate
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.Command, r.Args...)
+	cmd.Stdin = bytes.NewReader(view.HTML)
+	cmd.Stdout = out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w: %s", r.Command, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package fiber
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testPDFRenderer renders the HTML in upper case as "PDF".
+type testPDFRenderer struct{}
+
+func (testPDFRenderer) MediaType() string {
+	return "application/pdf"
+}
+
+func (testPDFRenderer) Render(_ context.Context, out io.Writer, view RenderView) error {
+	_, err := io.WriteString(out, "%PDF "+view.BaseURL+" "+strings.ToUpper(string(view.HTML)))
+	return err
+}
+
+// go test -run Test_Ctx_Negotiate
+func Test_Ctx_Negotiate(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.RegisterRenderer(testPDFRenderer{})
+	app.Get("/invoice", func(c Ctx) error {
+		return c.Negotiate("./.github/testdata/index.tmpl", Map{"Title": "Invoice"})
+	})
+	app.Get("/data", func(c Ctx) error {
+		return c.Negotiate("", Map{"Title": "Invoice"})
+	})
+
+	send := func(target, accept string) (int, string, string) {
+		req := httptest.NewRequest(MethodGet, target, nil)
+		if accept != "" {
+			req.Header.Set(HeaderAccept, accept)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, HeaderAccept, resp.Header.Get(HeaderVary))
+		return resp.StatusCode, resp.Header.Get(HeaderContentType), string(body)
+	}
+
+	status, contentType, body := send("/invoice", "")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, MIMETextHTMLCharsetUTF8, contentType)
+	require.Equal(t, "<h1>Invoice</h1>", body)
+
+	_, contentType, body = send("/invoice", "application/json")
+	require.Equal(t, MIMEApplicationJSON, contentType)
+	require.Equal(t, `{"Title":"Invoice"}`, body)
+
+	_, contentType, body = send("/invoice", "application/pdf, text/html;q=0.5")
+	require.Equal(t, "application/pdf", contentType)
+	require.Equal(t, "%PDF http://example.com <H1>INVOICE</H1>", body)
+
+	status, _, _ = send("/invoice", "image/png")
+	require.Equal(t, StatusNotAcceptable, status)
+
+	// without template, JSON is offered first and the renderers get no HTML
+	_, contentType, _ = send("/data", "")
+	require.Equal(t, MIMEApplicationJSON, contentType)
+	_, _, body = send("/data", "text/html, application/pdf;q=0.9")
+	require.Equal(t, "%PDF http://example.com ", body)
+}
+
+// go test -run Test_CommandRenderer
+func Test_CommandRenderer(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat is not installed")
+	}
+	renderer := &CommandRenderer{Type: "text/x-copy", Command: "cat"}
+	require.Equal(t, "text/x-copy", renderer.MediaType())
+
+	var out strings.Builder
+	require.NoError(t, renderer.Render(context.Background(), &out, RenderView{HTML: []byte("<p>copy</p>")}))
+	require.Equal(t, "<p>copy</p>", out.String())
+
+	require.ErrorIs(t, renderer.Render(context.Background(), &out, RenderView{}), ErrRenderNoTemplate)
+
+	renderer.Args = []string{"/missing/file"}
+	err := renderer.Render(context.Background(), &out, RenderView{HTML: []byte("x")})
+	require.ErrorContains(t, err, "/missing/file")
+}
@@ -0,0 +1,98 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// Timeout sets a timeout for the handlers of the latest registered route. The user context
+// of the request is canceled when the timeout is exceeded and errors which wrap
+// context.DeadlineExceeded are passed to the ErrorHandler as ErrRequestTimeout.
+// The timeout can't extend the RequestTimeout of the app, the earlier deadline applies.
+//
+//	app.Get("/reports", handler).Timeout(30 * time.Second)
+func (app *App) Timeout(timeout time.Duration) Router {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	for _, routes := range app.stack {
+		for _, route := range routes {
+			isMethodValid := route.Method == app.latestRoute.Method || app.latestRoute.use ||
+				(app.latestRoute.Method == MethodGet && route.Method == MethodHead)
+
+			// middlewares with the same path keep their timeout
+			if route.Path == app.latestRoute.Path && route.use == app.latestRoute.use && isMethodValid {
+				route.timeout = &timeout
+			}
+		}
+	}
+
+	return app
+}
+
+// Timeout sets a timeout for the handlers of the latest registered route.
+func (grp *Group) Timeout(timeout time.Duration) Router {
+	grp.app.Timeout(timeout)
+
+	return grp
+}
+
+// startRequestContext sets the user context of the request, which is canceled
+// after the RequestTimeout or when the client disconnects.
+// The returned function cancels the context after the request.
+func (app *App) startRequestContext(c CustomCtx) func() {
+	ctx, cancel := context.WithCancelCause(c.UserContext())
+	userCtx, cancelTimeout := ctx, context.CancelFunc(func() {})
+	if app.config.RequestTimeout > 0 {
+		userCtx, cancelTimeout = withTimeout(ctx, app.config.Clock, app.config.RequestTimeout)
+	}
+	c.SetUserContext(userCtx)
+
+	if app.config.DisconnectCheckInterval > 0 {
+		c.abortState().onAbort(cancel, disconnectConn(c), app.config.DisconnectCheckInterval)
+	}
+
+	return func() {
+		cancelTimeout()
+		cancel(nil)
+	}
+}
+
+// handleWithTimeout calls the handler with a user context which is canceled after the timeout.
+func (app *App) handleWithTimeout(c Ctx, timeout time.Duration, handler Handler) error {
+	parent := c.UserContext()
+	ctx, cancel := withTimeout(parent, app.config.Clock, timeout)
+	defer cancel()
+
+	c.SetUserContext(ctx)
+	err := handler(c)
+	c.SetUserContext(parent)
+
+	if err != nil && errors.Is(err, context.DeadlineExceeded) {
+		return ErrRequestTimeout
+	}
+	return err
+}
+
+// unwrapConn returns the underlying connection of the wrapped connections of the
+// listeners and TLS, or nil if the connection can't be checked for a disconnect.
+func unwrapConn(conn net.Conn) net.Conn {
+	for {
+		switch wrapped := conn.(type) {
+		case *limitConn:
+			conn = wrapped.Conn
+		case interface{ NetConn() net.Conn }:
+			conn = wrapped.NetConn()
+		case *net.TCPConn:
+			return wrapped
+		default:
+			return nil
+		}
+	}
+}
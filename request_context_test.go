@@ -0,0 +1,141 @@
+package fiber
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_App_RequestTimeout
+func Test_App_RequestTimeout(t *testing.T) {
+	t.Parallel()
+	app := New(Config{RequestTimeout: 20 * time.Millisecond})
+	app.Get("/", func(c Ctx) error {
+		<-c.UserContext().Done()
+		return fmt.Errorf("query: %w", c.UserContext().Err())
+	})
+	app.Get("/fast", func(c Ctx) error {
+		_, ok := c.UserContext().Deadline()
+		require.True(t, ok)
+		return c.SendStatus(StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusRequestTimeout, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/fast", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_Route_Timeout
+func Test_Route_Timeout(t *testing.T) {
+	t.Parallel()
+	app := New(Config{RequestTimeout: time.Minute})
+
+	var ctxErr error
+	app.Use(func(c Ctx) error {
+		err := c.Next()
+		// the route timeout only applies to the handlers of the route
+		ctxErr = c.UserContext().Err()
+		return err
+	})
+	app.Get("/", func(c Ctx) error {
+		<-c.UserContext().Done()
+		return c.UserContext().Err()
+	}).Timeout(20 * time.Millisecond)
+
+	grp := app.Group("/api")
+	grp.Get("/slow", func(c Ctx) error {
+		deadline, ok := c.UserContext().Deadline()
+		require.True(t, ok)
+		// the earlier deadline of the app applies
+		require.LessOrEqual(t, time.Until(deadline), time.Minute)
+		return c.SendStatus(StatusOK)
+	}).Timeout(time.Hour)
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusRequestTimeout, resp.StatusCode)
+	require.NoError(t, ctxErr)
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/api/slow", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_App_RequestTimeout_Clock
+func Test_App_RequestTimeout_Clock(t *testing.T) {
+	t.Parallel()
+	clock := NewManualClock(time.Now())
+	app := New(Config{Clock: clock, RequestTimeout: time.Hour})
+
+	// the timeouts are measured by the clock of the app
+	handler := func(c Ctx) error {
+		go func() {
+			assert.NoError(t, clock.WaitForTimers(context.Background(), 1))
+			clock.Advance(time.Hour)
+		}()
+		<-c.UserContext().Done()
+		return c.UserContext().Err()
+	}
+	app.Get("/", handler)
+	app.Get("/route", handler).Timeout(time.Minute)
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusRequestTimeout, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/route", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusRequestTimeout, resp.StatusCode)
+}
+
+// go test -run Test_App_DisconnectCheckInterval
+func Test_App_DisconnectCheckInterval(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("disconnect checks are not supported on windows")
+	}
+
+	started := make(chan struct{})
+	cause := make(chan error, 1)
+
+	app := New(Config{DisconnectCheckInterval: 5 * time.Millisecond})
+	app.Get("/", func(c Ctx) error {
+		close(started)
+		select {
+		case <-c.UserContext().Done():
+			cause <- context.Cause(c.UserContext())
+		case <-time.After(5 * time.Second):
+			cause <- nil
+		}
+		return nil
+	})
+
+	ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		assert.NoError(t, app.Listener(ln, ListenConfig{DisableStartupMessage: true}))
+	}()
+	t.Cleanup(func() {
+		assert.NoError(t, app.Shutdown())
+	})
+
+	conn, err := net.Dial(NetworkTCP4, ln.Addr().String())
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	require.NoError(t, err)
+
+	<-started
+	require.NoError(t, conn.Close())
+	require.ErrorIs(t, <-cause, ErrClientDisconnected)
+}
@@ -0,0 +1,253 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"errors"
+	"io"
+	"net"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/valyala/fasthttp"
+)
+
+// ResponseSizePolicy is the behavior when a response exceeds the maximum response size.
+type ResponseSizePolicy int
+
+const (
+	// ResponseSizeError replaces the response with ErrResponseTooLarge, which is passed to the ErrorHandler.
+	// Streamed bodies of unknown size are aborted like with ResponseSizeAbort,
+	// because the status is already sent when the limit is reached.
+	ResponseSizeError ResponseSizePolicy = iota
+	// ResponseSizeTruncate truncates the body to the maximum size and sets the X-Response-Truncated header.
+	// Streamed bodies of unknown size are truncated without the header, because the headers are
+	// already sent when the limit is reached. Bodies with a Content-Encoding can't be decoded
+	// when they're truncated, so they're handled like with ResponseSizeError.
+	ResponseSizeTruncate
+	// ResponseSizeAbort closes the connection without a response. Streamed bodies of unknown size
+	// are aborted when the limit is reached, so the client receives an incomplete response.
+	ResponseSizeAbort
+)
+
+// errResponseStreamTooLarge aborts the streamed bodies which exceed the maximum response size.
+var errResponseStreamTooLarge = errors.New("response stream exceeds the maximum response size")
+
+// responseSizeLimit is the maximum response size of a route.
+type responseSizeLimit struct {
+	size   int
+	policy ResponseSizePolicy
+}
+
+// MaxResponseSize sets the maximum size of the response bodies of the latest registered route,
+// it overwrites the MaxResponseSize of the app. A size of 0 disables the limit for the route.
+// If it is set for a middleware, e.g. of a group, it is used for all requests which are handled
+// by the middleware, unless the handler route has its own limit.
+//
+//	app.Get("/export", handler).MaxResponseSize(100<<20, fiber.ResponseSizeAbort)
+func (app *App) MaxResponseSize(size int, policy ...ResponseSizePolicy) Router {
+	limit := &responseSizeLimit{size: size, policy: app.config.ResponseSizePolicy}
+	if len(policy) > 0 {
+		limit.policy = policy[0]
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	app.responseSizeLimits = true
+	for _, routes := range app.stack {
+		for _, route := range routes {
+			isMethodValid := route.Method == app.latestRoute.Method || app.latestRoute.use ||
+				(app.latestRoute.Method == MethodGet && route.Method == MethodHead)
+
+			// middlewares with the same path keep their limit
+			if route.Path == app.latestRoute.Path && route.use == app.latestRoute.use && isMethodValid {
+				route.maxResponseSize = limit
+			}
+		}
+	}
+
+	return app
+}
+
+// MaxResponseSize sets the maximum size of the response bodies of the latest registered route.
+func (grp *Group) MaxResponseSize(size int, policy ...ResponseSizePolicy) Router {
+	grp.app.MaxResponseSize(size, policy...)
+
+	return grp
+}
+
+// responseSizeLimit returns the limit of the last matching route with a limit, the middlewares
+// before the first matching handler are included. The limit of the app is used otherwise.
+func (app *App) responseSizeLimit(c CustomCtx) responseSizeLimit {
+	limit := responseSizeLimit{size: app.config.MaxResponseSize, policy: app.config.ResponseSizePolicy}
+	if !app.responseSizeLimits {
+		return limit
+	}
+
+	tree, ok := c.getTreeStack()[c.getMethodINT()][c.getTreePath()]
+	if !ok {
+		tree = c.getTreeStack()[c.getMethodINT()][""]
+	}
+
+	for _, route := range tree {
+		if !route.match(c.getDetectionPath(), c.Path(), c.getValues()) {
+			continue
+		}
+		if route.maxResponseSize != nil {
+			limit = *route.maxResponseSize
+		}
+		if !route.use {
+			break
+		}
+	}
+
+	return limit
+}
+
+// limitResponseSize applies the policy of the maximum response size to the response.
+func (app *App) limitResponseSize(c CustomCtx) {
+	limit := app.responseSizeLimit(c)
+	if limit.size <= 0 || c.Hijacked() {
+		return
+	}
+
+	res := c.Response()
+	// encoded bodies can't be truncated, the client couldn't decode them
+	truncate := limit.policy == ResponseSizeTruncate && len(res.Header.ContentEncoding()) == 0
+	size := res.Header.ContentLength()
+	if res.IsBodyStream() {
+		// streams of a known size are handled like bodies
+		if size < 0 {
+			if stream := limitableBodyStream(res, size); stream != nil {
+				stream.limit(limit.size, truncate)
+			} else {
+				app.logw(log.LevelWarn, "the response stream can't be limited to the maximum response size, set it with c.SendStream or c.SendStreamWriter",
+					"method", c.Method(), "path", c.Path())
+			}
+			return
+		} else if size <= limit.size {
+			return
+		}
+	} else if size = len(res.Body()); size <= limit.size {
+		return
+	}
+
+	app.logw(log.LevelWarn, "response exceeds the maximum response size",
+		"method", c.Method(), "path", c.Path(), "max", limit.size, "size", size)
+
+	switch {
+	case truncate && res.IsBodyStream():
+		// only the limit is read from the stream
+		if stream := limitableBodyStream(res, size); stream != nil {
+			stream.limit(limit.size, true)
+			res.Header.SetContentLength(limit.size)
+		} else {
+			body := make([]byte, limit.size)
+			n, _ := io.ReadFull(res.BodyStream(), body) //nolint:errcheck // the body is truncated to the bytes which were read
+			res.SetBody(body[:n])
+		}
+		res.Header.Set(HeaderXResponseTruncated, "true")
+	case truncate:
+		res.SetBody(res.Body()[:limit.size])
+		res.Header.Set(HeaderXResponseTruncated, "true")
+	case limit.policy == ResponseSizeAbort:
+		res.ResetBody()
+		c.Hijack(func(net.Conn) {})
+	default:
+		res.ResetBody()
+		res.Header.Del(HeaderContentEncoding)
+		if catch := app.ErrorHandler(c, ErrResponseTooLarge); catch != nil {
+			_ = c.SendStatus(StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
+		}
+	}
+}
+
+// setBodyStream sets the body stream of the response. If the app has a maximum response size,
+// the stream is wrapped, so it can be limited after the handlers returned.
+func (c *DefaultCtx) setBodyStream(stream io.Reader, size int) {
+	if c.app.config.MaxResponseSize > 0 || c.app.responseSizeLimits {
+		stream = &limitedResponseStream{stream: stream, remaining: -1}
+	}
+	c.fasthttp.Response.SetBodyStream(stream, size)
+}
+
+// limitableBodyStream returns the body stream of the response, which can be limited. The streams
+// which were set with setBodyStream are returned as is. Other streams are wrapped, unless they're
+// an io.Closer, because fasthttp closes the stream when it is replaced, which would abort e.g. the
+// writers of SetBodyStreamWriter. Then nil is returned.
+func limitableBodyStream(res *fasthttp.Response, size int) *limitedResponseStream {
+	switch stream := res.BodyStream().(type) {
+	case *limitedResponseStream:
+		return stream
+	case io.Closer:
+		return nil
+	default:
+		limited := &limitedResponseStream{stream: stream, remaining: -1}
+		res.SetBodyStream(limited, size)
+		return limited
+	}
+}
+
+// limitedResponseStream limits a streamed response body. It is unlimited until limit is called.
+type limitedResponseStream struct {
+	stream    io.Reader
+	remaining int
+	truncate  bool
+}
+
+// limit limits the stream to the size, it is truncated or aborted when the limit is reached.
+func (s *limitedResponseStream) limit(size int, truncate bool) {
+	s.remaining, s.truncate = size, truncate
+}
+
+// Read reads from the stream until the limit is reached, then the stream
+// is truncated or aborted with an error, which closes the connection.
+func (s *limitedResponseStream) Read(p []byte) (int, error) {
+	if s.remaining < 0 {
+		return s.stream.Read(p) //nolint:wrapcheck // the error of the stream is returned unchanged
+	}
+	if s.remaining == 0 {
+		// check if the stream ended exactly at the limit
+		var b [1]byte
+		n, err := io.ReadFull(s.stream, b[:])
+		if n == 0 {
+			return 0, err
+		}
+		if s.truncate {
+			return 0, io.EOF
+		}
+		return 0, errResponseStreamTooLarge
+	}
+
+	if len(p) > s.remaining {
+		p = p[:s.remaining]
+	}
+	n, err := s.stream.Read(p)
+	s.remaining -= n
+	return n, err
+}
+
+// WriteTo writes the stream to the writer. Unlimited and truncated streams are copied with
+// io.Copy, so files are still sent with sendfile.
+func (s *limitedResponseStream) WriteTo(w io.Writer) (int64, error) {
+	switch {
+	case s.remaining < 0:
+		return io.Copy(w, s.stream) //nolint:wrapcheck // the error of the stream is returned unchanged
+	case s.truncate:
+		n, err := io.Copy(w, io.LimitReader(s.stream, int64(s.remaining)))
+		s.remaining -= int(n)
+		return n, err //nolint:wrapcheck // the error of the stream is returned unchanged
+	default:
+		return io.Copy(w, struct{ io.Reader }{s}) //nolint:wrapcheck // the error of the stream is returned unchanged
+	}
+}
+
+// Close closes the stream, if it is an io.Closer.
+func (s *limitedResponseStream) Close() error {
+	if closer, ok := s.stream.(io.Closer); ok {
+		return closer.Close() //nolint:wrapcheck // the error of the stream is returned unchanged
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package fiber
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_App_MaxResponseSize
+func Test_App_MaxResponseSize(t *testing.T) {
+	t.Parallel()
+	app := New(Config{MaxResponseSize: 10})
+	app.Get("/small", func(c Ctx) error {
+		return c.SendString("0123456789")
+	})
+	app.Get("/large", func(c Ctx) error {
+		c.Set(HeaderContentDisposition, "attachment")
+		return c.SendString(strings.Repeat("a", 11))
+	})
+	app.Get("/unlimited", func(c Ctx) error {
+		return c.SendString(strings.Repeat("a", 11))
+	}).MaxResponseSize(0)
+
+	status, body := testRequestBody(t, app, "/small")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "0123456789", body)
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/large", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "response exceeds the maximum response size", string(b))
+
+	status, body = testRequestBody(t, app, "/unlimited")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, strings.Repeat("a", 11), body)
+}
+
+// go test -run Test_Route_MaxResponseSize
+func Test_Route_MaxResponseSize(t *testing.T) {
+	t.Parallel()
+	app := New(Config{ResponseSizePolicy: ResponseSizeTruncate})
+	api := app.Group("/api", func(c Ctx) error {
+		return c.Next()
+	}).MaxResponseSize(5)
+	api.Get("/users", func(c Ctx) error {
+		return c.SendString("0123456789")
+	})
+	api.Get("/export", func(c Ctx) error {
+		return c.SendString("0123456789")
+	}).MaxResponseSize(8)
+	app.Get("/abort", func(c Ctx) error {
+		return c.SendString("0123456789")
+	}).MaxResponseSize(5, ResponseSizeAbort)
+
+	// the limit of the group
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/api/users", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, "true", resp.Header.Get(HeaderXResponseTruncated))
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "01234", string(b))
+
+	// the limit of the handler route is preferred
+	status, body := testRequestBody(t, app, "/api/export")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "01234567", body)
+
+	// the connection is closed without a response
+	_, err = app.Test(httptest.NewRequest(MethodGet, "/abort", nil))
+	require.Error(t, err)
+}
+
+// go test -run Test_App_MaxResponseSize_Stream
+func Test_App_MaxResponseSize_Stream(t *testing.T) {
+	t.Parallel()
+	app := New(Config{MaxResponseSize: 10})
+	stream := func(size int) Handler {
+		return func(c Ctx) error {
+			return c.SendStreamWriter(func(w *bufio.Writer) {
+				for i := 0; i < size; i++ {
+					_ = w.WriteByte('a') //nolint:errcheck // It is fine to ignore the error here
+					_ = w.Flush()        //nolint:errcheck // It is fine to ignore the error here
+				}
+			})
+		}
+	}
+	app.Get("/small", stream(10))
+	app.Get("/large", stream(100))
+	app.Get("/truncate", stream(100)).MaxResponseSize(5, ResponseSizeTruncate)
+	app.Get("/known", func(c Ctx) error {
+		return c.SendStream(strings.NewReader(strings.Repeat("a", 11)), 11)
+	})
+
+	status, body := testRequestBody(t, app, "/small")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, strings.Repeat("a", 10), body)
+
+	status, body = testRequestBody(t, app, "/truncate")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "aaaaa", body)
+
+	// streams of unknown size are aborted
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/large", nil))
+	if err == nil {
+		_, err = io.ReadAll(resp.Body)
+	}
+	require.Error(t, err)
+
+	// streams of a known size are handled like bodies
+	status, _ = testRequestBody(t, app, "/known")
+	require.Equal(t, StatusInternalServerError, status)
+}
+
+// endlessReader returns an endless body, it fails if more than max bytes are read.
+type endlessReader struct {
+	read int
+	max  int
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	if r.read += len(p); r.read > r.max {
+		return 0, errors.New("the stream was read to the end")
+	}
+	for i := range p {
+		p[i] = 'a'
+	}
+	return len(p), nil
+}
+
+// go test -run Test_App_MaxResponseSize_Truncate
+func Test_App_MaxResponseSize_Truncate(t *testing.T) {
+	t.Parallel()
+	app := New(Config{MaxResponseSize: 5, ResponseSizePolicy: ResponseSizeTruncate})
+	app.Get("/known", func(c Ctx) error {
+		return c.SendStream(&endlessReader{max: 64 << 10}, 1<<30)
+	})
+	app.Get("/encoded", func(c Ctx) error {
+		c.Set(HeaderContentEncoding, "gzip")
+		return c.SendString(strings.Repeat("a", 10))
+	})
+
+	// only the limit is read from a stream of a known size
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/known", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	require.Equal(t, "true", resp.Header.Get(HeaderXResponseTruncated))
+	require.Equal(t, int64(5), resp.ContentLength)
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "aaaaa", string(b))
+
+	// encoded bodies are rejected instead of truncated
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/encoded", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusInternalServerError, resp.StatusCode)
+	require.Empty(t, resp.Header.Get(HeaderContentEncoding))
+	require.Empty(t, resp.Header.Get(HeaderXResponseTruncated))
+}
+
+// go test -run Test_App_MaxResponseSize_FasthttpStream
+func Test_App_MaxResponseSize_FasthttpStream(t *testing.T) {
+	t.Parallel()
+	app := New(Config{MaxResponseSize: 5, ResponseSizePolicy: ResponseSizeTruncate})
+	app.Get("/reader", func(c Ctx) error {
+		c.Response().SetBodyStream(strings.NewReader(strings.Repeat("a", 10)), -1)
+		return nil
+	})
+	app.Get("/writer", func(c Ctx) error {
+		c.Response().SetBodyStreamWriter(func(w *bufio.Writer) {
+			_, _ = w.WriteString(strings.Repeat("a", 10)) //nolint:errcheck // It is fine to ignore the error here
+		})
+		return nil
+	})
+	app.Get("/closer", func(c Ctx) error {
+		c.Response().SetBodyStream(io.NopCloser(strings.NewReader(strings.Repeat("a", 10))), 10)
+		return nil
+	})
+
+	// streams which aren't closed when they're replaced are limited
+	status, body := testRequestBody(t, app, "/reader")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "aaaaa", body)
+
+	// the writers of SetBodyStreamWriter aren't aborted, they can't be limited
+	status, body = testRequestBody(t, app, "/writer")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, strings.Repeat("a", 10), body)
+
+	// the beginning of other streams of a known size is read
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/closer", nil))
+	require.NoError(t, err)
+	require.Equal(t, "true", resp.Header.Get(HeaderXResponseTruncated))
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "aaaaa", string(b))
+}
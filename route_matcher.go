@@ -0,0 +1,175 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"sort"
+	"strings"
+)
+
+// routeTree is a snapshot of the tree stack and its compiled matcher,
+// which is used for the whole request.
+type routeTree struct {
+	stack   []map[string][]*Route
+	matcher *routeMatcher // nil if the routes aren't compiled
+}
+
+// routeMatcher is a radix tree of the static prefixes of all routes.
+// The tree stack of compiled routes is divided by these prefixes instead of
+// the first three characters of the path, every area contains the routes whose
+// static prefix is a prefix of the area. The longest prefix of the request path
+// selects the area, so only the routes which can match the path are traversed.
+type routeMatcher struct {
+	prefix   string
+	key      string // static prefix of the routes which ends at this node
+	isKey    bool
+	indices  []byte // first bytes of the prefixes of the children
+	children []*routeMatcher
+}
+
+// insert adds the static prefix to the radix tree.
+func (n *routeMatcher) insert(key string) {
+	s := key
+	for {
+		// split the node at the end of the common prefix
+		i := 0
+		for i < len(s) && i < len(n.prefix) && s[i] == n.prefix[i] {
+			i++
+		}
+		if i < len(n.prefix) {
+			child := &routeMatcher{
+				prefix:   n.prefix[i:],
+				key:      n.key,
+				isKey:    n.isKey,
+				indices:  n.indices,
+				children: n.children,
+			}
+			n.prefix = n.prefix[:i]
+			n.key, n.isKey = "", false
+			n.indices = []byte{child.prefix[0]}
+			n.children = []*routeMatcher{child}
+		}
+
+		s = s[i:]
+		if s == "" {
+			n.key, n.isKey = key, true
+			return
+		}
+
+		next := n.child(s[0])
+		if next == nil {
+			n.indices = append(n.indices, s[0])
+			n.children = append(n.children, &routeMatcher{prefix: s, key: key, isKey: true})
+			return
+		}
+		n = next
+	}
+}
+
+func (n *routeMatcher) child(c byte) *routeMatcher {
+	for i, index := range n.indices {
+		if index == c {
+			return n.children[i]
+		}
+	}
+	return nil
+}
+
+// lookup returns the longest static prefix of the path.
+func (n *routeMatcher) lookup(path string) string {
+	var longest string
+	for n != nil && strings.HasPrefix(path, n.prefix) {
+		path = path[len(n.prefix):]
+		if n.isKey {
+			longest = n.key
+		}
+		if path == "" {
+			break
+		}
+		n = n.child(path[0])
+	}
+	return longest
+}
+
+// routeStaticPrefix returns the part of the detection path which has to precede
+// every path the route matches.
+func routeStaticPrefix(route *Route) string {
+	if route.star || route.mount || (route.root && route.use) {
+		return ""
+	}
+	if len(route.Params) == 0 {
+		return route.path
+	}
+	if len(route.routeParser.segs) == 0 || route.routeParser.segs[0].IsParam {
+		return ""
+	}
+	// the slash in front of a parameter can be optional
+	prefix := route.routeParser.segs[0].Const
+	if i := strings.LastIndexByte(prefix, '/'); i >= 0 {
+		prefix = prefix[:i]
+	}
+	if strings.IndexByte(prefix, escapeChar) >= 0 {
+		return ""
+	}
+	return prefix
+}
+
+// compileTree divides the tree stack of all methods by the static prefixes of the routes.
+func (app *App) compileTree() {
+	prefixes := make(map[*Route]string)
+	keySet := map[string]struct{}{"": {}}
+	for m := range app.config.RequestMethods {
+		for _, route := range app.stack[m] {
+			prefix := routeStaticPrefix(route)
+			prefixes[route] = prefix
+			keySet[prefix] = struct{}{}
+		}
+	}
+
+	// prefixes sort in front of the keys which start with them
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	matcher := &routeMatcher{}
+	for _, key := range keys {
+		matcher.insert(key)
+	}
+
+	for m := range app.config.RequestMethods {
+		own := make(map[string][]*Route)
+		for _, route := range app.stack[m] {
+			own[prefixes[route]] = append(own[prefixes[route]], route)
+		}
+
+		tsMap := make(map[string][]*Route, len(keys))
+		var parents []string
+		for _, key := range keys {
+			for len(parents) > 0 && !strings.HasPrefix(key, parents[len(parents)-1]) {
+				parents = parents[:len(parents)-1]
+			}
+			var inherited []*Route
+			if len(parents) > 0 {
+				inherited = tsMap[parents[len(parents)-1]]
+			}
+
+			// areas without own routes share the routes of their parent
+			if routes := own[key]; len(routes) > 0 {
+				merged := make([]*Route, 0, len(inherited)+len(routes))
+				merged = append(append(merged, inherited...), routes...)
+				sort.Slice(merged, func(i, j int) bool { return merged[i].pos < merged[j].pos })
+				tsMap[key] = merged
+			} else {
+				tsMap[key] = inherited
+			}
+			parents = append(parents, key)
+		}
+		app.treeStack[m] = tsMap
+	}
+
+	app.routeMatcher = matcher
+}
@@ -0,0 +1,129 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_RouteMatcher_Lookup
+func Test_RouteMatcher_Lookup(t *testing.T) {
+	t.Parallel()
+	matcher := &routeMatcher{}
+	for _, key := range []string{"", "/api", "/api/v1", "/api/v2/users", "/apis", "/b"} {
+		matcher.insert(key)
+	}
+
+	require.Equal(t, "/api/v1", matcher.lookup("/api/v1/users"))
+	require.Equal(t, "/api", matcher.lookup("/api/v2/user"))
+	require.Equal(t, "/api/v2/users", matcher.lookup("/api/v2/users/1"))
+	require.Equal(t, "/apis", matcher.lookup("/apis"))
+	require.Equal(t, "/api", matcher.lookup("/api"))
+	require.Equal(t, "", matcher.lookup("/ap"))
+	require.Equal(t, "", matcher.lookup("/c"))
+}
+
+// go test -run Test_App_RouterCompileThreshold
+func Test_App_RouterCompileThreshold(t *testing.T) {
+	t.Parallel()
+	register := func(app *App) {
+		app.Use(func(c Ctx) error {
+			c.Append("X-Trace", "global")
+			return c.Next()
+		})
+		app.Use("/repos", func(c Ctx) error {
+			c.Append("X-Trace", "repos")
+			return c.Next()
+		})
+		app.Get("/users/:name?", func(c Ctx) error {
+			return c.SendString("optional " + c.Params("name"))
+		})
+		app.Get("/files/*", func(c Ctx) error {
+			return c.SendString("files " + c.Params("*"))
+		})
+		app.Get("/shop/product-:id<int>", func(c Ctx) error {
+			return c.SendString("product " + c.Params("id"))
+		})
+		for _, r := range routesFixture.GithubAPI {
+			path := r.Path
+			app.Add([]string{r.Method}, path, func(c Ctx) error {
+				return c.SendString(path)
+			})
+		}
+	}
+
+	compiled := New(Config{RouterCompileThreshold: 10})
+	register(compiled)
+	tree := New()
+	register(tree)
+	compiledHandler, treeHandler := compiled.Handler(), tree.Handler()
+	require.NotNil(t, compiled.routeMatcher)
+	require.Nil(t, tree.routeMatcher)
+
+	requests := append([]testRoute{
+		{Method: MethodGet, Path: "/users"},
+		{Method: MethodGet, Path: "/users/john"},
+		{Method: MethodGet, Path: "/USERS/John/"},
+		{Method: MethodGet, Path: "/files"},
+		{Method: MethodGet, Path: "/files/docs/readme.md"},
+		{Method: MethodGet, Path: "/shop/product-42"},
+		{Method: MethodGet, Path: "/shop/product-abc"},
+		{Method: MethodGet, Path: "/"},
+		{Method: MethodDelete, Path: "/unknown"},
+		{Method: MethodPatch, Path: "/user"},
+		{Method: MethodPost, Path: "/users/john"},
+	}, routesFixture.TestRoutes...)
+
+	for _, r := range requests {
+		compiledCtx, treeCtx := &fasthttp.RequestCtx{}, &fasthttp.RequestCtx{}
+		for _, c := range []*fasthttp.RequestCtx{compiledCtx, treeCtx} {
+			c.Request.Header.SetMethod(r.Method)
+			c.URI().SetPath(r.Path)
+		}
+		compiledHandler(compiledCtx)
+		treeHandler(treeCtx)
+
+		require.Equal(t, treeCtx.Response.StatusCode(), compiledCtx.Response.StatusCode(), r.Method+" "+r.Path)
+		require.Equal(t, string(treeCtx.Response.Body()), string(compiledCtx.Response.Body()), r.Method+" "+r.Path)
+		require.Equal(t, treeCtx.Response.Header.PeekAll("X-Trace"), compiledCtx.Response.Header.PeekAll("X-Trace"), r.Method+" "+r.Path)
+		require.Equal(t, string(treeCtx.Response.Header.Peek(HeaderAllow)), string(compiledCtx.Response.Header.Peek(HeaderAllow)), r.Method+" "+r.Path)
+	}
+
+	// routes which are added later are compiled with the next request
+	compiled.Get("/late", func(c Ctx) error {
+		return c.SendString("late")
+	})
+	resp, err := compiled.Test(httptest.NewRequest(MethodGet, "/late", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
+
+// go test -v -run=^$ -bench=Benchmark_Router_Github_API_Compiled -benchmem -count=4
+func Benchmark_Router_Github_API_Compiled(b *testing.B) {
+	app := New(Config{RouterCompileThreshold: 1})
+	registerDummyRoutes(app)
+	app.startupProcess()
+
+	c := &fasthttp.RequestCtx{}
+	var match bool
+	var err error
+
+	b.ResetTimer()
+
+	for i := range routesFixture.TestRoutes {
+		c.Request.Header.SetMethod(routesFixture.TestRoutes[i].Method)
+		for n := 0; n < b.N; n++ {
+			c.URI().SetPath(routesFixture.TestRoutes[i].Path)
+
+			ctx := app.AcquireCtx(c).(*DefaultCtx) //nolint:errcheck, forcetypeassert // not needed
+
+			match, err = app.next(ctx)
+			app.ReleaseCtx(ctx)
+		}
+
+		require.NoError(b, err)
+		require.True(b, match)
+	}
+}
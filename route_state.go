@@ -0,0 +1,112 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// routeStateIDs is the number of created route states, used as index in the route
+var routeStateIDs atomic.Uint32
+
+// RouteState is a state of a middleware which is created once per route,
+// e.g. a compiled template or regex which depends on the route. It is stored
+// in the route and retrieved in O(1), instead of a map lookup in the middleware.
+// Route states should be created once, e.g. in the constructor of the middleware.
+type RouteState[T any] struct {
+	id      uint32
+	newFunc func(route *Route) T
+}
+
+// NewRouteState creates a route state. newFunc is called on the first use
+// of the state for each route.
+func NewRouteState[T any](newFunc func(route *Route) T) *RouteState[T] {
+	return &RouteState[T]{
+		id:      routeStateIDs.Add(1) - 1,
+		newFunc: newFunc,
+	}
+}
+
+// Get returns the state of the matched route.
+func (s *RouteState[T]) Get(c Ctx) T {
+	return s.Route(c.Route())
+}
+
+// Route returns the state of the route.
+func (s *RouteState[T]) Route(route *Route) T {
+	// routes which weren't registered have no storage for states
+	if route.states == nil {
+		return s.newFunc(route)
+	}
+
+	value, ok := route.states.load(s.id, func() any {
+		return s.newFunc(route)
+	}).(T)
+	if !ok {
+		return s.newFunc(route)
+	}
+	return value
+}
+
+// RoutePool is a pool of values per route, e.g. buffers whose size depends on the route.
+type RoutePool[T any] struct {
+	pools *RouteState[*sync.Pool]
+}
+
+// NewRoutePool creates a route pool. newFunc is called if the pool of the route is empty.
+func NewRoutePool[T any](newFunc func(route *Route) T) *RoutePool[T] {
+	return &RoutePool[T]{
+		pools: NewRouteState(func(route *Route) *sync.Pool {
+			return &sync.Pool{
+				New: func() any {
+					return newFunc(route)
+				},
+			}
+		}),
+	}
+}
+
+// Acquire returns a value from the pool of the matched route.
+func (p *RoutePool[T]) Acquire(c Ctx) T {
+	value, _ := p.pools.Get(c).Get().(T) //nolint:errcheck // The pool only contains values of type T
+	return value
+}
+
+// Release returns the value to the pool of the matched route.
+func (p *RoutePool[T]) Release(c Ctx, value T) {
+	p.pools.Get(c).Put(value)
+}
+
+// routeStates stores the route states of a route, indexed by their id.
+// The slice is replaced on writes, so reads don't need a lock.
+type routeStates struct {
+	mutex  sync.Mutex
+	values atomic.Pointer[[]any]
+}
+
+func (rs *routeStates) load(id uint32, create func() any) any {
+	if values := rs.values.Load(); values != nil && int(id) < len(*values) && (*values)[id] != nil {
+		return (*values)[id]
+	}
+
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+
+	var old []any
+	if values := rs.values.Load(); values != nil {
+		old = *values
+	}
+	if int(id) < len(old) && old[id] != nil {
+		return old[id]
+	}
+
+	values := make([]any, max(len(old), int(id)+1))
+	copy(values, old)
+	values[id] = create()
+	rs.values.Store(&values)
+
+	return values[id]
+}
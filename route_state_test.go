@@ -0,0 +1,67 @@
+package fiber
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_RouteState
+func Test_RouteState(t *testing.T) {
+	t.Parallel()
+	var created atomic.Int32
+	state := NewRouteState(func(route *Route) *regexp.Regexp {
+		created.Add(1)
+		return regexp.MustCompile("^" + regexp.QuoteMeta(route.Path))
+	})
+
+	app := New()
+	app.Use(func(c Ctx) error {
+		if !state.Get(c).MatchString(c.Path()) {
+			return ErrBadRequest
+		}
+		return c.Next()
+	})
+	app.Get("/users", testSimpleHandler)
+	app.Get("/books", testSimpleHandler)
+
+	for i := 0; i < 3; i++ {
+		for _, path := range []string{"/users", "/books"} {
+			resp, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+			require.NoError(t, err)
+			require.Equal(t, StatusOK, resp.StatusCode)
+		}
+	}
+	// the state is created once for the GET copy of the use route
+	require.Equal(t, int32(1), created.Load())
+
+	// routes which weren't registered don't store the state
+	route := &Route{Path: "/"}
+	require.NotSame(t, state.Route(route), state.Route(route))
+}
+
+// go test -run Test_RoutePool
+func Test_RoutePool(t *testing.T) {
+	t.Parallel()
+	pool := NewRoutePool(func(route *Route) *bytes.Buffer {
+		return bytes.NewBuffer(make([]byte, 0, len(route.Path)*1024))
+	})
+
+	app := New()
+	app.Get("/buffer", func(c Ctx) error {
+		buf := pool.Acquire(c)
+		defer pool.Release(c, buf)
+
+		buf.Reset()
+		buf.WriteString(c.Route().Path)
+		return c.Send(buf.Bytes())
+	})
+
+	status, body := testRequestBody(t, app, "/buffer")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "/buffer", body)
+}
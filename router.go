@@ -5,6 +5,7 @@
 package fiber
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"html"
@@ -14,6 +15,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/gofiber/fiber/v3/cachecontrol"
 	"github.com/gofiber/utils/v2"
 	"github.com/valyala/fasthttp"
 )
@@ -41,28 +43,51 @@ type Router interface {
 	Route(path string) Register
 
 	Name(name string) Router
+	PanicPolicy(policy PanicPolicy) Router
+	Timeout(timeout time.Duration) Router
+	Priority(priority Priority) Router
+	MaxResponseSize(size int, policy ...ResponseSizePolicy) Router
+	Require(permissions ...string) Router
+	CacheControl(policy cachecontrol.Policy) Router
+	HeaderPolicy(policy HeaderPolicy) Router
+	Tag(tags ...string) Router
 }
 
 // Route is a struct that holds all metadata for each registered handler.
 type Route struct {
 	// ### important: always keep in sync with the copy method "app.copyRoute" ###
 	// Data for routing
-	pos         uint32      // Position in stack -> important for the sort of the matched routes
-	use         bool        // USE matches path prefixes
-	mount       bool        // Indicated a mounted app on a specific route
-	star        bool        // Path equals '*'
-	root        bool        // Path equals '/'
-	path        string      // Prettified path
-	routeParser routeParser // Parameter parser
-	group       *Group      // Group instance. used for routes in groups
+	pos         uint32         // Position in stack -> important for the sort of the matched routes
+	use         bool           // USE matches path prefixes
+	mount       bool           // Indicated a mounted app on a specific route
+	star        bool           // Path equals '*'
+	root        bool           // Path equals '/'
+	path        string         // Prettified path
+	routeParser routeParser    // Parameter parser
+	group       *Group         // Group instance. used for routes in groups
+	panicPolicy *PanicPolicy   // Overwrites the panic policy of the app
+	timeout     *time.Duration // Timeout of the handlers of the route
+	priority    *Priority      // Priority of the requests of the route for the scheduler
+	// Maximum size of the response bodies, overwrites the MaxResponseSize of the app
+	maxResponseSize *responseSizeLimit
+	states          *routeStates // States of the middlewares, see RouteState
+	permissions     []string     // Permissions required by the route, see Require
+	// Default Cache-Control policy of the responses, see CacheControl
+	cacheControl *cachecontrol.Policy
+	// Policy of the response headers, see HeaderPolicy
+	headerPolicy *HeaderPolicy
+	sites        []string    // Registration sites of the handlers, see MiddlewareChain
+	injected     int         // Number of the middlewares of UseTag at the start of the handlers
+	statsTags    []*tagStats // Counters of the tags of Config.StatsTags
 
 	// Public fields
 	Method string `json:"method"` // HTTP method
 	Name   string `json:"name"`   // Route's name
 	//nolint:revive // Having both a Path (uppercase) and a path (lowercase) is fine
-	Path     string    `json:"path"`   // Original registered route path
-	Params   []string  `json:"params"` // Case sensitive param keys
-	Handlers []Handler `json:"-"`      // Ctx handlers
+	Path     string    `json:"path"`           // Original registered route path
+	Params   []string  `json:"params"`         // Case sensitive param keys
+	Tags     []string  `json:"tags,omitempty"` // Tags of the route, see Tag
+	Handlers []Handler `json:"-"`              // Ctx handlers
 }
 
 func (r *Route) match(detectionPath, path string, params *[maxParams]string) bool {
@@ -102,9 +127,9 @@ func (r *Route) match(detectionPath, path string, params *[maxParams]string) boo
 
 func (app *App) nextCustom(c CustomCtx) (bool, error) { //nolint: unparam // bool param might be useful for testing
 	// Get stack length
-	tree, ok := app.treeStack[c.getMethodINT()][c.getTreePath()]
+	tree, ok := c.getTreeStack()[c.getMethodINT()][c.getTreePath()]
 	if !ok {
-		tree = app.treeStack[c.getMethodINT()][""]
+		tree = c.getTreeStack()[c.getMethodINT()][""]
 	}
 	lenr := len(tree) - 1
 
@@ -131,8 +156,18 @@ func (app *App) nextCustom(c CustomCtx) (bool, error) { //nolint: unparam // boo
 			c.setMatched(true)
 		}
 
+		// Authorize the request with the permissions of the route
+		if route.permissions != nil {
+			if allowed, err := app.authorize(c, route); !allowed {
+				return match, err
+			}
+		}
+
 		// Execute first handler of route
 		c.setIndexHandler(0)
+		if route.timeout != nil {
+			return match, app.handleWithTimeout(c, *route.timeout, route.Handlers[0])
+		}
 		err := route.Handlers[0](c)
 		return match, err // Stop scanning the stack
 	}
@@ -150,9 +185,9 @@ func (app *App) nextCustom(c CustomCtx) (bool, error) { //nolint: unparam // boo
 
 func (app *App) next(c *DefaultCtx) (bool, error) {
 	// Get stack length
-	tree, ok := app.treeStack[c.methodINT][c.treePath]
+	tree, ok := c.treeStack[c.methodINT][c.treePath]
 	if !ok {
-		tree = app.treeStack[c.methodINT][""]
+		tree = c.treeStack[c.methodINT][""]
 	}
 	lenTree := len(tree) - 1
 
@@ -185,9 +220,23 @@ func (app *App) next(c *DefaultCtx) (bool, error) {
 			c.matched = true
 		}
 
+		// Report the allocations of the hot path before the first handler is called
+		if allocAuditEnabled && c.allocAuditStart != 0 {
+			app.auditAllocs(c)
+		}
+
+		// Authorize the request with the permissions of the route
+		if route.permissions != nil {
+			if allowed, err := app.authorize(c, route); !allowed {
+				return match, err
+			}
+		}
+
 		// Execute first handler of route
 		c.indexHandler = 0
-		if len(route.Handlers) > 0 {
+		if route.timeout != nil && len(route.Handlers) > 0 {
+			err = app.handleWithTimeout(c, *route.timeout, route.Handlers[0])
+		} else if len(route.Handlers) > 0 {
 			err = route.Handlers[0](c)
 		}
 		return match, err // Stop scanning the stack
@@ -204,6 +253,11 @@ func (app *App) next(c *DefaultCtx) (bool, error) {
 }
 
 func (app *App) requestHandler(rctx *fasthttp.RequestCtx) {
+	var allocs uint64
+	if allocAuditEnabled {
+		allocs = heapAllocs()
+	}
+
 	// Handler for default ctxs
 	var c CustomCtx
 	var ok bool
@@ -217,8 +271,55 @@ func (app *App) requestHandler(rctx *fasthttp.RequestCtx) {
 		if !ok {
 			panic(errors.New("requestHandler: failed to type-assert to *DefaultCtx"))
 		}
+		if allocAuditEnabled {
+			c.(*DefaultCtx).allocAuditStart = allocs //nolint:forcetypeassert,errcheck // It is always a *DefaultCtx here
+		}
 	}
 	defer app.ReleaseCtx(c)
+	if app.config.EnableCommitHooks {
+		defer app.commits.track(c)
+	}
+	if app.config.EnableRequestStats {
+		app.requestStats.start(rctx.Time())
+		defer app.requestStats.done(c)
+	}
+	if app.slowRequests != nil {
+		defer app.slowRequests.untrack(app.slowRequests.track(c))
+	}
+	// the error of the handlers is recorded after the panics were recovered
+	var chainErr error
+	if app.flightRecorder != nil {
+		defer func() {
+			app.flightRecorder.record(c, rctx.Time(), chainErr)
+		}()
+	}
+	// the responses of the requests which were admitted by the throttle are observed after the panics were recovered
+	var (
+		throttled bool
+		admitted  time.Time
+	)
+	if app.throttle != nil {
+		defer func() {
+			if throttled {
+				app.throttle.observe(c, admitted, app.config.Clock.Now())
+			}
+		}()
+	}
+	defer app.recoverPanic(c)
+	if app.config.RequestTimeout > 0 || app.config.DisconnectCheckInterval > 0 {
+		defer app.startRequestContext(c)()
+	}
+
+	if len(app.config.SecurityHeaders) > 0 {
+		app.setSecurityHeaders(c)
+	}
+
+	// send the version of the app with every response
+	if app.config.VersionHeader {
+		if version := app.GetBuildInfo().Version; version != "" {
+			c.Response().Header.Set(HeaderXAppVersion, version)
+		}
+	}
 
 	// handle invalid http method directly
 	if app.methodInt(c.Method()) == -1 {
@@ -226,6 +327,55 @@ func (app *App) requestHandler(rctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	// reject requests with malformed headers in the strict header mode
+	if app.config.StrictHeaders {
+		if err := app.checkStrictHeaders(c); err != nil {
+			if catch := app.ErrorHandler(c, err); catch != nil {
+				_ = c.SendStatus(StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
+			}
+			return
+		}
+	}
+
+	// reject requests with suspicious encodings in the path
+	if app.config.PathNormalization.Strict && suspiciousPath(c.getPathOriginal()) {
+		if catch := app.ErrorHandler(c, ErrSuspiciousPath); catch != nil {
+			_ = c.SendStatus(StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
+		}
+		return
+	}
+
+	// reject requests while in maintenance mode, except the discovery endpoint which reports it
+	if app.maintenance.Load() && (app.discoveryPath == "" || c.Path() != app.discoveryPath) {
+		if catch := app.ErrorHandler(c, ErrServiceUnavailable); catch != nil {
+			_ = c.SendStatus(StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
+		}
+		return
+	}
+
+	// shed or arrest the request if the throttle is burning the error budget or the rate spikes
+	if app.throttle != nil {
+		admitted = app.config.Clock.Now()
+		if err := app.throttle.admit(c, admitted); err != nil {
+			if catch := app.ErrorHandler(c, err); catch != nil {
+				_ = c.SendStatus(StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
+			}
+			return
+		}
+		throttled = true
+	}
+
+	// queue or shed the request if the app is at capacity
+	if app.scheduler != nil {
+		if err := app.scheduler.acquire(c); err != nil {
+			if catch := app.ErrorHandler(c, err); catch != nil {
+				_ = c.SendStatus(StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
+			}
+			return
+		}
+		defer app.scheduler.release()
+	}
+
 	// check flash messages
 	if strings.Contains(utils.UnsafeString(c.Request().Header.RawHeaders()), FlashCookieName) {
 		c.Redirect().setFlash()
@@ -239,11 +389,44 @@ func (app *App) requestHandler(rctx *fasthttp.RequestCtx) {
 		_, err = app.next(c.(*DefaultCtx))
 	}
 	if err != nil {
+		if app.config.RequestTimeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+			err = ErrRequestTimeout
+		}
+		chainErr = err
 		if catch := c.App().ErrorHandler(c, err); catch != nil {
 			_ = c.SendStatus(StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
 		}
 		// TODO: Do we need to return here?
 	}
+
+	// set the default Cache-Control policy, the policies of the routes are only looked up if they're set
+	if app.cacheControlPolicies {
+		app.setCacheControl(c)
+	}
+
+	// limit the size of the response, the limits of the routes are only looked up if they're set
+	if app.config.MaxResponseSize > 0 || app.responseSizeLimits {
+		app.limitResponseSize(c)
+	}
+
+	// emit the fields of the VaryBy calls, even if a handler overwrote the Vary header
+	if fields := c.getVary(); len(fields) > 0 {
+		app.emitVary(c, fields)
+	}
+
+	// apply the header policies last, so they apply to the headers of the responses of errors too
+	if app.headerPolicies {
+		app.applyHeaderPolicies(c)
+	}
+
+	if len(app.hooks.onAbort) > 0 && c.IsAborted() {
+		app.hooks.executeOnAbortHooks(c)
+	}
+
+	// close the connection after the response if the keep-alive policies require it
+	if app.keepAlivePolicy.Load() {
+		app.closeConnectionIfNeeded(c)
+	}
 }
 
 func (app *App) addPrefixToRoute(prefix string, route *Route) *Route {
@@ -280,13 +463,24 @@ func (*App) copyRoute(route *Route) *Route {
 		routeParser: route.routeParser,
 
 		// misc
-		pos: route.pos,
+		pos:             route.pos,
+		panicPolicy:     route.panicPolicy,
+		timeout:         route.timeout,
+		priority:        route.priority,
+		maxResponseSize: route.maxResponseSize,
+		states:          &routeStates{},
+		permissions:     route.permissions,
+		cacheControl:    route.cacheControl,
+		headerPolicy:    route.headerPolicy,
+		sites:           route.sites,
+		injected:        route.injected,
 
 		// Public data
 		Path:     route.Path,
 		Params:   route.Params,
 		Name:     route.Name,
 		Method:   route.Method,
+		Tags:     route.Tags,
 		Handlers: route.Handlers,
 	}
 }
@@ -296,6 +490,7 @@ func (app *App) register(methods []string, pathRaw string, group *Group, handler
 	if handler != nil {
 		handlers = append(handlers, handler)
 	}
+	sites := registrationSites(len(handlers))
 
 	for _, method := range methods {
 		// Uppercase HTTP methods
@@ -353,6 +548,7 @@ func (app *App) register(methods []string, pathRaw string, group *Group, handler
 
 			// Group data
 			group: group,
+			sites: sites,
 
 			// Public data
 			Path:     pathRaw,
@@ -506,6 +702,7 @@ func (app *App) registerStatic(prefix, root string, config ...Static) {
 		Method:   MethodGet,
 		Path:     prefix,
 		Handlers: []Handler{handler},
+		sites:    registrationSites(1),
 	}
 	// Increment global handler count
 	atomic.AddUint32(&app.handlersCount, 1)
@@ -530,10 +727,14 @@ func (app *App) addRoute(method string, route *Route, isMounted ...bool) {
 	if l > 0 && app.stack[m][l-1].Path == route.Path && route.use == app.stack[m][l-1].use && !route.mount && !app.stack[m][l-1].mount {
 		preRoute := app.stack[m][l-1]
 		preRoute.Handlers = append(preRoute.Handlers, route.Handlers...)
+		preRoute.sites = append(preRoute.sites, route.sites...)
 	} else {
 		// Increment global route position
 		route.pos = atomic.AddUint32(&app.routesCount, 1)
 		route.Method = method
+		if route.states == nil {
+			route.states = &routeStates{}
+		}
 		// Add route to the stack
 		app.stack[m] = append(app.stack[m], route)
 		app.routesRefreshed = true
@@ -556,6 +757,23 @@ func (app *App) buildTree() *App {
 		return app
 	}
 
+	// inject the middlewares of the tags into the routes
+	app.injectTagMiddlewares()
+	app.assignStatsTags()
+
+	// compile the routes of large apps
+	routes := 0
+	for m := range app.stack {
+		routes += len(app.stack[m])
+	}
+	if app.config.RouterCompileThreshold > 0 && routes > app.config.RouterCompileThreshold {
+		app.compileTree()
+		app.routesRefreshed = false
+		app.publishTree()
+		return app
+	}
+	app.routeMatcher = nil
+
 	// loop all the methods and stacks and create the prefix tree
 	for m := range app.config.RequestMethods {
 		tsMap := make(map[string][]*Route)
@@ -585,5 +803,24 @@ func (app *App) buildTree() *App {
 	}
 	app.routesRefreshed = false
 
+	// publish the new tree for the upcoming requests
+	app.publishTree()
+
 	return app
 }
+
+// publishTree publishes a copy of the current tree stack, which is used by the upcoming requests.
+// Requests which are already running keep using the tree they started with.
+func (app *App) publishTree() {
+	stack := make([]map[string][]*Route, len(app.treeStack))
+	copy(stack, app.treeStack)
+	app.activeTree.Store(&routeTree{stack: stack, matcher: app.routeMatcher})
+}
+
+// loadTree returns the tree stack which is used to serve a new request.
+func (app *App) loadTree() routeTree {
+	if tree := app.activeTree.Load(); tree != nil {
+		return *tree
+	}
+	return routeTree{stack: app.treeStack, matcher: app.routeMatcher}
+}
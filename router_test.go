@@ -490,6 +490,54 @@ func Test_Router_NotFound_HTML_Inject(t *testing.T) {
 	require.Equal(t, "Cannot DELETE /does/not/exist&lt;script&gt;alert(&#39;foo&#39;);&lt;/script&gt;", string(c.Response.Body()))
 }
 
+// go test -run Test_Router_ZeroAlloc
+//
+//nolint:paralleltest // The allocations of the whole process are counted
+func Test_Router_ZeroAlloc(t *testing.T) {
+	app := New()
+	app.Use(func(c Ctx) error {
+		c.Set(HeaderXContentTypeOptions, "nosniff")
+		return c.Next()
+	})
+	app.Get("/", func(c Ctx) error {
+		return c.SendStatus(StatusOK)
+	})
+	api := app.Group("/api")
+	api.Get("/users/:name/books/:id<int>", func(c Ctx) error {
+		if c.Params("name") == "" || c.Get(HeaderAccept) == "" {
+			return ErrBadRequest
+		}
+		return c.SendString(c.Params("id"))
+	})
+	api.Get("/files/*", func(c Ctx) error {
+		return c.SendString(c.Params("*"))
+	})
+	appHandler := app.Handler()
+
+	testCases := []struct {
+		uri  string
+		body string
+	}{
+		{uri: "/", body: "OK"},
+		{uri: "/api/users/john%20doe/books/42", body: "42"},
+		{uri: "/api/files/docs/readme.md", body: "docs/readme.md"},
+	}
+
+	for _, tc := range testCases {
+		c := &fasthttp.RequestCtx{}
+		c.Request.Header.SetMethod(MethodGet)
+		c.Request.SetRequestURI(tc.uri)
+		c.Request.Header.Set(HeaderAccept, MIMEApplicationJSON)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			appHandler(c)
+		})
+		require.Equal(t, StatusOK, c.Response.StatusCode(), tc.uri)
+		require.Equal(t, tc.body, string(c.Response.Body()), tc.uri)
+		require.Zero(t, allocs, tc.uri)
+	}
+}
+
 //////////////////////////////////////////////
 ///////////////// BENCHMARKS /////////////////
 //////////////////////////////////////////////
@@ -0,0 +1,271 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Priority is the priority class of the requests of a route, which is used
+// by the request scheduler when the app is at capacity.
+type Priority uint8
+
+const (
+	// PriorityLow requests are shed first when the app is at capacity.
+	PriorityLow Priority = iota + 1
+	// PriorityNormal is the priority of the routes without a priority.
+	PriorityNormal
+	// PriorityHigh requests are handled before the queued requests with a lower priority,
+	// e.g. for login and checkout endpoints.
+	PriorityHigh
+	// PriorityCritical requests are never queued or shed, e.g. for health checks.
+	PriorityCritical
+)
+
+// String returns the name of the priority.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("Priority(%d)", uint8(p))
+	}
+}
+
+// SchedulerConfig configures the request scheduler, which limits the number of
+// concurrently handled requests and queues the further requests by priority.
+type SchedulerConfig struct {
+	// MaxInFlight is the maximum number of concurrently handled requests.
+	// Further requests are queued, the queued requests with the highest priority
+	// are handled first. Set to 0 to disable the scheduler.
+	//
+	// Default: 0
+	MaxInFlight int `json:"max_in_flight"`
+
+	// MaxQueue is the maximum number of queued requests per priority.
+	// Further requests are rejected with 503 Service Unavailable.
+	//
+	// Default: MaxInFlight
+	MaxQueue int `json:"max_queue"`
+
+	// QueueTimeout is the maximum duration a request is queued,
+	// afterwards it is rejected with 503 Service Unavailable.
+	//
+	// Default: 1 * time.Second
+	QueueTimeout time.Duration `json:"queue_timeout"`
+
+	// ShedBelow is the priority below which requests are rejected immediately
+	// with 503 Service Unavailable instead of being queued when the app is at capacity.
+	//
+	// Default: PriorityNormal
+	ShedBelow Priority `json:"shed_below"`
+}
+
+// SchedulerStats contains the counters of the request scheduler by priority.
+type SchedulerStats struct {
+	// InFlight is the number of requests which are currently handled by the scheduler.
+	InFlight int `json:"in_flight"`
+	// Queued is the number of requests which are currently queued.
+	Queued map[Priority]int `json:"queued"`
+	// Shed is the number of rejected requests.
+	Shed map[Priority]uint64 `json:"shed"`
+}
+
+// schedulerWaiter is a queued request.
+type schedulerWaiter struct {
+	ready chan struct{}
+}
+
+// scheduler admits the requests by priority.
+type scheduler struct {
+	config   SchedulerConfig
+	mutex    sync.Mutex
+	inFlight int
+	queues   [PriorityCritical + 1][]*schedulerWaiter
+	shed     [PriorityCritical + 1]atomic.Uint64
+}
+
+func newScheduler(config SchedulerConfig) *scheduler {
+	if config.MaxQueue <= 0 {
+		config.MaxQueue = config.MaxInFlight
+	}
+	if config.QueueTimeout <= 0 {
+		config.QueueTimeout = time.Second
+	}
+	if config.ShedBelow == 0 {
+		config.ShedBelow = PriorityNormal
+	}
+	return &scheduler{config: config}
+}
+
+// acquire waits until the request can be handled. It returns ErrServiceUnavailable
+// if the request was shed, release must only be called if it returned nil.
+func (s *scheduler) acquire(c CustomCtx) error {
+	s.mutex.Lock()
+	if s.inFlight < s.config.MaxInFlight {
+		s.inFlight++
+		s.mutex.Unlock()
+		return nil
+	}
+
+	s.mutex.Unlock()
+
+	// the priority is only looked up when the app is at capacity
+	priority := requestPriority(c)
+
+	s.mutex.Lock()
+	switch {
+	case s.inFlight < s.config.MaxInFlight || priority >= PriorityCritical:
+		s.inFlight++
+		s.mutex.Unlock()
+		return nil
+	case priority < s.config.ShedBelow || len(s.queues[priority]) >= s.config.MaxQueue:
+		s.mutex.Unlock()
+		s.shed[priority].Add(1)
+		return ErrServiceUnavailable
+	}
+
+	waiter := &schedulerWaiter{ready: make(chan struct{})}
+	s.queues[priority] = append(s.queues[priority], waiter)
+	s.mutex.Unlock()
+
+	timer := time.NewTimer(s.config.QueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-timer.C:
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i, queued := range s.queues[priority] {
+		if queued == waiter {
+			s.queues[priority] = append(s.queues[priority][:i], s.queues[priority][i+1:]...)
+			s.shed[priority].Add(1)
+			return ErrServiceUnavailable
+		}
+	}
+	// the slot was passed to the request while the timer fired
+	return nil
+}
+
+// release passes the slot of a handled request to the queued request with the highest priority.
+func (s *scheduler) release() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for priority := PriorityHigh; priority >= PriorityLow; priority-- {
+		if queue := s.queues[priority]; len(queue) > 0 {
+			close(queue[0].ready)
+			queue[0] = nil
+			s.queues[priority] = queue[1:]
+			return
+		}
+	}
+	s.inFlight--
+}
+
+// stats returns the counters of the scheduler.
+func (s *scheduler) stats() SchedulerStats {
+	stats := SchedulerStats{
+		Queued: make(map[Priority]int),
+		Shed:   make(map[Priority]uint64),
+	}
+
+	s.mutex.Lock()
+	stats.InFlight = s.inFlight
+	for priority := PriorityLow; priority <= PriorityCritical; priority++ {
+		if queued := len(s.queues[priority]); queued > 0 {
+			stats.Queued[priority] = queued
+		}
+	}
+	s.mutex.Unlock()
+
+	for priority := PriorityLow; priority <= PriorityCritical; priority++ {
+		if shed := s.shed[priority].Load(); shed > 0 {
+			stats.Shed[priority] = shed
+		}
+	}
+
+	return stats
+}
+
+// requestPriority returns the priority of the first matching route with a priority,
+// the middlewares before the first matching handler are included.
+func requestPriority(c CustomCtx) Priority {
+	tree, ok := c.getTreeStack()[c.getMethodINT()][c.getTreePath()]
+	if !ok {
+		tree = c.getTreeStack()[c.getMethodINT()][""]
+	}
+
+	for _, route := range tree {
+		if !route.match(c.getDetectionPath(), c.Path(), c.getValues()) {
+			continue
+		}
+		if route.priority != nil {
+			return *route.priority
+		}
+		if !route.use {
+			break
+		}
+	}
+
+	return PriorityNormal
+}
+
+// Priority sets the priority of the latest registered route, which is used by the
+// request scheduler when the app is at capacity. If it is set for a middleware, e.g.
+// of a group, it is used for all requests which are handled by the middleware.
+//
+//	app.Post("/checkout", handler).Priority(fiber.PriorityHigh)
+//	app.Group("/reports", handler).Priority(fiber.PriorityLow)
+func (app *App) Priority(priority Priority) Router {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	for _, routes := range app.stack {
+		for _, route := range routes {
+			isMethodValid := route.Method == app.latestRoute.Method || app.latestRoute.use ||
+				(app.latestRoute.Method == MethodGet && route.Method == MethodHead)
+
+			// middlewares with the same path keep their priority
+			if route.Path == app.latestRoute.Path && route.use == app.latestRoute.use && isMethodValid {
+				route.priority = &priority
+			}
+		}
+	}
+
+	return app
+}
+
+// Priority sets the priority of the latest registered route.
+func (grp *Group) Priority(priority Priority) Router {
+	grp.app.Priority(priority)
+
+	return grp
+}
+
+// SchedulerStats returns the counters of the request scheduler,
+// the maps are empty if the scheduler is disabled.
+func (app *App) SchedulerStats() SchedulerStats {
+	if app.scheduler == nil {
+		return SchedulerStats{
+			Queued: make(map[Priority]int),
+			Shed:   make(map[Priority]uint64),
+		}
+	}
+	return app.scheduler.stats()
+}
@@ -0,0 +1,145 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitSchedulerQueued waits until the number of queued requests of the priority is reached.
+func waitSchedulerQueued(t *testing.T, app *App, priority Priority, queued int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return app.SchedulerStats().Queued[priority] == queued
+	}, time.Second, time.Millisecond)
+}
+
+// go test -run Test_App_Scheduler
+func Test_App_Scheduler(t *testing.T) {
+	t.Parallel()
+	app := New(Config{Scheduler: SchedulerConfig{MaxInFlight: 1, QueueTimeout: 5 * time.Second}})
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	var order []string
+	var mutex sync.Mutex
+	record := func(c Ctx) error {
+		mutex.Lock()
+		order = append(order, c.Route().Path)
+		mutex.Unlock()
+		return c.SendStatus(StatusOK)
+	}
+
+	app.Get("/block", func(c Ctx) error {
+		close(started)
+		<-unblock
+		return c.SendStatus(StatusOK)
+	})
+	app.Get("/normal", record)
+	app.Group("/checkout", func(c Ctx) error {
+		return c.Next()
+	}).Priority(PriorityHigh)
+	app.Get("/checkout/pay", record)
+	app.Get("/reports", record).Priority(PriorityLow)
+	app.Get("/health", record).Priority(PriorityCritical)
+
+	var wg sync.WaitGroup
+	request := func(path string, status int) {
+		defer wg.Done()
+		resp, err := app.Test(httptest.NewRequest(MethodGet, path, nil), 10*time.Second)
+		if assert.NoError(t, err) {
+			assert.Equal(t, status, resp.StatusCode, path)
+		}
+	}
+
+	wg.Add(1)
+	go request("/block", StatusOK)
+	<-started
+
+	// low priority requests are shed, critical requests are handled immediately
+	wg.Add(2)
+	request("/reports", StatusServiceUnavailable)
+	request("/health", StatusOK)
+
+	// the high priority request is handled before the queued normal request
+	wg.Add(2)
+	go request("/normal", StatusOK)
+	waitSchedulerQueued(t, app, PriorityNormal, 1)
+	go request("/checkout/pay", StatusOK)
+	waitSchedulerQueued(t, app, PriorityHigh, 1)
+
+	close(unblock)
+	wg.Wait()
+
+	require.Equal(t, []string{"/health", "/checkout/pay", "/normal"}, order)
+
+	stats := app.SchedulerStats()
+	require.Equal(t, 0, stats.InFlight)
+	require.Empty(t, stats.Queued)
+	require.Equal(t, map[Priority]uint64{PriorityLow: 1}, stats.Shed)
+}
+
+// go test -run Test_App_Scheduler_QueueLimits
+func Test_App_Scheduler_QueueLimits(t *testing.T) {
+	t.Parallel()
+	app := New(Config{Scheduler: SchedulerConfig{
+		MaxInFlight:  1,
+		MaxQueue:     1,
+		QueueTimeout: 50 * time.Millisecond,
+	}})
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	app.Get("/block", func(c Ctx) error {
+		close(started)
+		<-unblock
+		return c.SendStatus(StatusOK)
+	})
+	app.Get("/", testSimpleHandler)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := app.Test(httptest.NewRequest(MethodGet, "/block", nil), 10*time.Second)
+		assert.NoError(t, err)
+	}()
+	<-started
+
+	queued := make(chan int)
+	go func() {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+		assert.NoError(t, err)
+		queued <- resp.StatusCode
+	}()
+	waitSchedulerQueued(t, app, PriorityNormal, 1)
+
+	// the queue is full
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusServiceUnavailable, resp.StatusCode)
+
+	// the queued request times out
+	require.Equal(t, StatusServiceUnavailable, <-queued)
+	require.Equal(t, uint64(2), app.SchedulerStats().Shed[PriorityNormal])
+
+	close(unblock)
+	<-done
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+}
+
+// go test -run Test_Priority_String
+func Test_Priority_String(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "low", PriorityLow.String())
+	require.Equal(t, "normal", PriorityNormal.String())
+	require.Equal(t, "high", PriorityHigh.String())
+	require.Equal(t, "critical", PriorityCritical.String())
+	require.Equal(t, "Priority(9)", Priority(9).String())
+}
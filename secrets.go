@@ -0,0 +1,190 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// SecretsProvider provides secrets like TLS certificates and the keys of middlewares,
+// e.g. from environment variables, files or a secrets manager. The adapters are in
+// the addon/secrets package.
+type SecretsProvider interface {
+	// Get returns the current value of the secret.
+	// ErrSecretNotFound is returned if the secret doesn't exist.
+	Get(ctx context.Context, name string) ([]byte, error)
+
+	// Watch calls fn with the new value whenever the secret changes.
+	// It blocks until the context is done or watching failed.
+	Watch(ctx context.Context, name string, fn func(value []byte)) error
+}
+
+// Secret is a secret of a SecretsProvider which is watched for changes, so keys
+// can be rotated without a restart. It keeps the previous value, e.g. to decrypt
+// the values which were encrypted before the rotation.
+type Secret struct {
+	name   string
+	cancel context.CancelFunc
+
+	mutex    sync.RWMutex
+	current  []byte
+	previous []byte
+}
+
+// LoadSecret gets the secret and watches it until Close is called.
+func LoadSecret(provider SecretsProvider, name string) (*Secret, error) {
+	value, err := provider.Get(context.Background(), name)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: failed to get %q: %w", name, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Secret{name: name, cancel: cancel, current: value}
+	go func() {
+		if err := provider.Watch(ctx, name, s.update); err != nil && ctx.Err() == nil {
+			log.Warnw("secrets: failed to watch secret", "name", name, "error", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// MustLoadSecret is like LoadSecret but panics if the secret can't be loaded.
+func MustLoadSecret(provider SecretsProvider, name string) *Secret {
+	s, err := LoadSecret(provider, name)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// update replaces the value of the secret.
+func (s *Secret) update(value []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if bytes.Equal(s.current, value) {
+		return
+	}
+	s.previous, s.current = s.current, value
+}
+
+// Name returns the name of the secret.
+func (s *Secret) Name() string {
+	return s.name
+}
+
+// Value returns the current value of the secret. It must not be modified.
+func (s *Secret) Value() []byte {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.current
+}
+
+// Previous returns the value before the latest rotation, or nil. It must not be modified.
+func (s *Secret) Previous() []byte {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.previous
+}
+
+// Close stops watching the secret.
+func (s *Secret) Close() {
+	s.cancel()
+}
+
+// secretCertificate is a TLS certificate whose PEM blocks are loaded from a
+// SecretsProvider, it is reloaded when one of the secrets changes.
+type secretCertificate struct {
+	provider SecretsProvider
+	certName string
+	keyName  string
+	cert     atomic.Pointer[tls.Certificate]
+}
+
+// loadSecretCertificate loads the certificate and watches its secrets until the context is done.
+func loadSecretCertificate(ctx context.Context, provider SecretsProvider, certName, keyName string) (*secretCertificate, error) {
+	sc := &secretCertificate{provider: provider, certName: certName, keyName: keyName}
+	if err := sc.load(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{certName, keyName} {
+		go func(name string) {
+			err := provider.Watch(ctx, name, func([]byte) {
+				if err := sc.load(ctx); err != nil {
+					log.Warnw("tls: failed to reload certificate, the previous certificate is used", "error", err)
+				}
+			})
+			if err != nil && ctx.Err() == nil {
+				log.Warnw("tls: failed to watch secret", "name", name, "error", err)
+			}
+		}(name)
+	}
+
+	return sc, nil
+}
+
+// load loads the key pair from the secrets.
+func (sc *secretCertificate) load(ctx context.Context) error {
+	certPEM, err := sc.provider.Get(ctx, sc.certName)
+	if err != nil {
+		return fmt.Errorf("tls: failed to get certificate secret %q: %w", sc.certName, err)
+	}
+	keyPEM, err := sc.provider.Get(ctx, sc.keyName)
+	if err != nil {
+		return fmt.Errorf("tls: failed to get key secret %q: %w", sc.keyName, err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("tls: cannot load TLS key pair from secrets %q and %q: %w", sc.certName, sc.keyName, err)
+	}
+	sc.cert.Store(&cert)
+	return nil
+}
+
+// secretsTLSConfig creates the TLS config of Listen with the certificates of the TLSSecrets.
+// The secrets are watched until the context is done.
+func (app *App) secretsTLSConfig(ctx context.Context, cfg ListenConfig) (*tls.Config, error) {
+	sc, err := loadSecretCertificate(ctx, cfg.TLSSecrets, cfg.CertFile, cfg.CertKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsHandler := &TLSHandler{}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(info *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			_, _ = tlsHandler.GetClientInfo(info) //nolint:errcheck // It always returns nil
+			return sc.cert.Load(), nil
+		},
+	}
+
+	if cfg.CertClientFile != "" {
+		clientCACert, err := cfg.TLSSecrets.Get(ctx, cfg.CertClientFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls: failed to get client certificate secret %q: %w", cfg.CertClientFile, err)
+		}
+
+		clientCertPool := x509.NewCertPool()
+		clientCertPool.AppendCertsFromPEM(clientCACert)
+
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = clientCertPool
+	}
+
+	// Attach the tlsHandler to the config
+	app.SetTLSHandler(tlsHandler)
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,173 @@
+package fiber
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSecretsProvider is a SecretsProvider whose secrets can be changed by the tests.
+type testSecretsProvider struct {
+	mutex    sync.Mutex
+	values   map[string][]byte
+	watchers map[string][]func(value []byte)
+}
+
+func newTestSecretsProvider(values map[string][]byte) *testSecretsProvider {
+	return &testSecretsProvider{values: values, watchers: make(map[string][]func(value []byte))}
+}
+
+func (p *testSecretsProvider) Get(_ context.Context, name string) ([]byte, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	value, ok := p.values[name]
+	if !ok {
+		return nil, ErrSecretNotFound
+	}
+	return value, nil
+}
+
+func (p *testSecretsProvider) Watch(ctx context.Context, name string, fn func(value []byte)) error {
+	p.mutex.Lock()
+	p.watchers[name] = append(p.watchers[name], fn)
+	p.mutex.Unlock()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// set changes the secret after it is watched.
+func (p *testSecretsProvider) set(t *testing.T, name string, value []byte) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		return len(p.watchers[name]) > 0
+	}, time.Second, time.Millisecond)
+
+	p.mutex.Lock()
+	p.values[name] = value
+	watchers := p.watchers[name]
+	p.mutex.Unlock()
+	for _, fn := range watchers {
+		fn(value)
+	}
+}
+
+// go test -run Test_LoadSecret
+func Test_LoadSecret(t *testing.T) {
+	t.Parallel()
+	provider := newTestSecretsProvider(map[string][]byte{"key": []byte("v1")})
+
+	_, err := LoadSecret(provider, "missing")
+	require.ErrorIs(t, err, ErrSecretNotFound)
+	require.Panics(t, func() {
+		MustLoadSecret(provider, "missing")
+	})
+
+	secret := MustLoadSecret(provider, "key")
+	defer secret.Close()
+	require.Equal(t, "key", secret.Name())
+	require.Equal(t, []byte("v1"), secret.Value())
+	require.Nil(t, secret.Previous())
+
+	provider.set(t, "key", []byte("v2"))
+	require.Equal(t, []byte("v2"), secret.Value())
+	require.Equal(t, []byte("v1"), secret.Previous())
+
+	// the same value doesn't replace the previous value
+	provider.set(t, "key", []byte("v2"))
+	require.Equal(t, []byte("v1"), secret.Previous())
+}
+
+// testCertificate returns a self-signed certificate and its key in PEM format.
+func testCertificate(t *testing.T, commonName string) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// go test -run Test_Listen_TLSSecrets
+func Test_Listen_TLSSecrets(t *testing.T) {
+	t.Parallel()
+	certPEM, err := os.ReadFile("./.github/testdata/ssl.pem")
+	require.NoError(t, err)
+	keyPEM, err := os.ReadFile("./.github/testdata/ssl.key")
+	require.NoError(t, err)
+	provider := newTestSecretsProvider(map[string][]byte{"tls.cert": certPEM, "tls.key": keyPEM})
+
+	// the secrets are required
+	app := New()
+	require.ErrorIs(t, app.Listen("127.0.0.1:0", ListenConfig{
+		DisableStartupMessage: true,
+		TLSSecrets:            provider,
+		CertFile:              "tls.cert",
+		CertKeyFile:           "missing",
+	}), ErrSecretNotFound)
+
+	addr := make(chan string, 1)
+	app = New()
+	go func() {
+		assert.NoError(t, app.Listen("127.0.0.1:0", ListenConfig{
+			DisableStartupMessage: true,
+			TLSSecrets:            provider,
+			CertFile:              "tls.cert",
+			CertKeyFile:           "tls.key",
+			ListenerAddrFunc: func(a net.Addr) {
+				addr <- a.String()
+			},
+		}))
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, app.Shutdown())
+	})
+	serverAddr := <-addr
+
+	commonName := func() string {
+		conn, err := tls.Dial("tcp", serverAddr, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec // The test certificates are self-signed
+		require.NoError(t, err)
+		defer conn.Close() //nolint:errcheck // It is fine to ignore the error here
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+	expected, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(expected.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, leaf.Subject.CommonName, commonName())
+
+	// an invalid key pair keeps the previous certificate
+	rotatedCert, rotatedKey := testCertificate(t, "rotated")
+	provider.set(t, "tls.cert", rotatedCert)
+	require.Equal(t, leaf.Subject.CommonName, commonName())
+
+	// the certificate is replaced when both secrets changed
+	provider.set(t, "tls.key", rotatedKey)
+	require.Equal(t, "rotated", commonName())
+}
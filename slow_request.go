@@ -0,0 +1,194 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// SlowRequest describes a request which is still handled after Config.SlowRequestThreshold.
+type SlowRequest struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the original URL of the request.
+	Path string
+	// Start is the time when the request handling started.
+	Start time.Time
+	// Duration is the duration of the request when it was detected as slow.
+	Duration time.Duration
+	// Stack is the stack of the goroutine which handles the request,
+	// nil if Config.SlowRequestStack is disabled.
+	Stack []byte
+}
+
+// trackedRequest is a request which is watched by the slowRequestWatchdog.
+type trackedRequest struct {
+	method    string
+	path      string
+	start     time.Time
+	goroutine []byte // goroutine id, only set if the stack is captured
+	reported  bool   // only accessed by the watchdog goroutine
+}
+
+// slowRequestWatchdog reports the requests which exceed the SlowRequestThreshold.
+// The watchdog goroutine is started with the first request and stopped on shutdown.
+type slowRequestWatchdog struct {
+	app       *App
+	threshold time.Duration
+	stack     bool
+	requests  sync.Map // *trackedRequest -> struct{}
+	start     sync.Once
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+func newSlowRequestWatchdog(app *App) *slowRequestWatchdog {
+	w := &slowRequestWatchdog{
+		app:       app,
+		threshold: app.config.SlowRequestThreshold,
+		stack:     app.config.SlowRequestStack,
+		stop:      make(chan struct{}),
+	}
+
+	app.hooks.OnShutdown(func() error {
+		w.stopOnce.Do(func() {
+			close(w.stop)
+		})
+		return nil
+	})
+
+	return w
+}
+
+// track starts to watch the request of the given ctx.
+func (w *slowRequestWatchdog) track(c Ctx) *trackedRequest {
+	w.start.Do(func() {
+		go w.watch()
+	})
+
+	req := &trackedRequest{
+		// copy the values, the ctx is reused after the request
+		method: string(c.Request().Header.Method()),
+		path:   string(c.Request().RequestURI()),
+		start:  time.Now(),
+	}
+	if w.stack {
+		req.goroutine = currentGoroutineID()
+	}
+	w.requests.Store(req, struct{}{})
+
+	return req
+}
+
+// untrack stops to watch the request.
+func (w *slowRequestWatchdog) untrack(req *trackedRequest) {
+	w.requests.Delete(req)
+}
+
+// watch checks the tracked requests until the app is shut down.
+func (w *slowRequestWatchdog) watch() {
+	ticker := time.NewTicker(w.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case now := <-ticker.C:
+			w.check(now)
+		}
+	}
+}
+
+// interval returns the check interval, a request is reported at most half
+// of the threshold after it became slow.
+func (w *slowRequestWatchdog) interval() time.Duration {
+	const minInterval = 5 * time.Millisecond
+
+	if interval := w.threshold / 2; interval > minInterval {
+		return interval
+	}
+	return minInterval
+}
+
+// check reports the tracked requests which exceed the threshold and weren't reported yet.
+func (w *slowRequestWatchdog) check(now time.Time) {
+	var stacks []byte
+	w.requests.Range(func(key, _ any) bool {
+		req := key.(*trackedRequest) //nolint:forcetypeassert,errcheck // The map only contains *trackedRequest
+		duration := now.Sub(req.start)
+		if req.reported || duration < w.threshold {
+			return true
+		}
+		req.reported = true
+
+		slow := SlowRequest{
+			Method:   req.method,
+			Path:     req.path,
+			Start:    req.start,
+			Duration: duration,
+		}
+		if req.goroutine != nil {
+			// capture all stacks once per check
+			if stacks == nil {
+				stacks = allGoroutineStacks()
+			}
+			slow.Stack = goroutineStack(stacks, req.goroutine)
+		}
+
+		w.app.logw(log.LevelWarn, "slow request", "method", slow.Method, "path", slow.Path, "duration", slow.Duration)
+		w.app.hooks.executeOnSlowRequestHooks(slow)
+
+		return true
+	})
+}
+
+// currentGoroutineID returns the id of the current goroutine,
+// parsed from the header of its stack: "goroutine 18 [running]:".
+func currentGoroutineID() []byte {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	if _, err := strconv.ParseUint(string(b), 10, 64); err != nil {
+		return nil
+	}
+
+	return append([]byte(nil), b...)
+}
+
+// allGoroutineStacks returns the stacks of all goroutines.
+func allGoroutineStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// goroutineStack returns the stack of the goroutine with the given id from the stacks
+// of all goroutines, which are separated by empty lines. It returns nil if the goroutine
+// isn't found.
+func goroutineStack(stacks, id []byte) []byte {
+	header := append(append([]byte("goroutine "), id...), " ["...)
+	for _, stack := range bytes.Split(stacks, []byte("\n\n")) {
+		if bytes.HasPrefix(stack, header) {
+			return append([]byte(nil), stack...)
+		}
+	}
+
+	return nil
+}
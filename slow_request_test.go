@@ -0,0 +1,70 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_App_SlowRequest
+func Test_App_SlowRequest(t *testing.T) {
+	t.Parallel()
+	logger := &testLogger{}
+	app := New(Config{
+		Logger:               logger,
+		SlowRequestThreshold: 20 * time.Millisecond,
+		SlowRequestStack:     true,
+	})
+
+	var mutex sync.Mutex
+	var reports []SlowRequest
+	app.Hooks().OnSlowRequest(func(req SlowRequest) error {
+		mutex.Lock()
+		reports = append(reports, req)
+		mutex.Unlock()
+		return nil
+	})
+
+	app.Get("/slow", func(c Ctx) error {
+		time.Sleep(200 * time.Millisecond)
+		return c.SendString("slow")
+	})
+	app.Get("/fast", func(c Ctx) error {
+		return c.SendString("fast")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/fast", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/slow?id=1", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	// reported once, while the handler was running
+	require.Len(t, reports, 1)
+	require.Equal(t, MethodGet, reports[0].Method)
+	require.Equal(t, "/slow?id=1", reports[0].Path)
+	require.GreaterOrEqual(t, reports[0].Duration, 20*time.Millisecond)
+	require.Less(t, reports[0].Duration, 200*time.Millisecond)
+	require.Contains(t, string(reports[0].Stack), "Test_App_SlowRequest")
+	require.Len(t, logger.Entries(), 1)
+
+	require.NoError(t, app.Shutdown())
+}
+
+// go test -run Test_GoroutineStack
+func Test_GoroutineStack(t *testing.T) {
+	t.Parallel()
+
+	id := currentGoroutineID()
+	require.NotEmpty(t, id)
+
+	stack := goroutineStack(allGoroutineStacks(), id)
+	require.Contains(t, string(stack), "Test_GoroutineStack")
+	require.Nil(t, goroutineStack(allGoroutineStacks(), []byte("0")))
+}
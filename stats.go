@@ -0,0 +1,258 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+const (
+	envPreforkStatsFDKey = "FIBER_PREFORK_STATS_FD"
+	// preforkStatsFD is the file descriptor of the stats pipe in the child process,
+	// the first file of exec.Cmd.ExtraFiles
+	preforkStatsFD = 3
+	// preforkStatsInterval is the interval of the stats reports of the prefork children
+	preforkStatsInterval = time.Second
+)
+
+// Stats is a snapshot of the connection and request statistics of the app.
+// In the prefork master process, it is the sum of the stats of all children.
+type Stats struct {
+	// Connections contains the connection counters of the listeners.
+	Connections ConnStats `json:"connections"`
+	// InFlight is the number of requests which are currently handled.
+	InFlight int64 `json:"in_flight"`
+	// Requests is the total number of handled requests.
+	Requests uint64 `json:"requests"`
+	// RequestsPerSecond is the number of requests which were handled in the last second.
+	RequestsPerSecond uint64 `json:"requests_per_second"`
+	// BytesIn is the number of bytes which were received by the listeners.
+	BytesIn uint64 `json:"bytes_in"`
+	// BytesOut is the number of bytes which were sent by the listeners.
+	BytesOut uint64 `json:"bytes_out"`
+	// Status contains the number of responses by status code.
+	Status map[int]uint64 `json:"status"`
+	// Errors contains the counters of the errors by category.
+	Errors ErrorStats `json:"errors"`
+	// Tags contains the counters of the routes by the tags of Config.StatsTags.
+	Tags map[string]TagStats `json:"tags,omitempty"`
+	// Companions contains the counters of the companion listeners, see ListenTCP and ListenUDP.
+	// They aren't aggregated in the prefork master process.
+	Companions []CompanionStats `json:"companions,omitempty"`
+	// Children is the number of prefork children whose stats are included.
+	Children int `json:"children,omitempty"`
+}
+
+// PreforkChildStats contains the latest stats which were reported by a prefork child.
+type PreforkChildStats struct {
+	// Stats are the stats of the child. The gauges, e.g. the open connections and the
+	// requests in flight, are zero after the child exited.
+	Stats Stats `json:"stats"`
+	// Updated is the time of the latest report, it is zero before the first report.
+	Updated time.Time `json:"updated"`
+	// PID is the process ID of the child.
+	PID int `json:"pid"`
+	// Exited is true if the child exited.
+	Exited bool `json:"exited"`
+}
+
+// add adds the counters of the other stats.
+func (s *Stats) add(other Stats) {
+	s.Connections.Open += other.Connections.Open
+	s.Connections.Accepted += other.Connections.Accepted
+	s.Connections.RejectedMaxConns += other.Connections.RejectedMaxConns
+	s.Connections.RejectedMaxConnsPerIP += other.Connections.RejectedMaxConnsPerIP
+	s.Connections.RejectedAcceptRate += other.Connections.RejectedAcceptRate
+	s.InFlight += other.InFlight
+	s.Requests += other.Requests
+	s.RequestsPerSecond += other.RequestsPerSecond
+	s.BytesIn += other.BytesIn
+	s.BytesOut += other.BytesOut
+	for status, count := range other.Status {
+		s.Status[status] += count
+	}
+	s.Errors.add(other.Errors)
+	for tag, stats := range other.Tags {
+		if s.Tags == nil {
+			s.Tags = make(map[string]TagStats, len(other.Tags))
+		}
+		sum := s.Tags[tag]
+		sum.Requests += stats.Requests
+		sum.Errors += stats.Errors
+		sum.Duration += stats.Duration
+		s.Tags[tag] = sum
+	}
+}
+
+// requestStats contains the request counters of the app.
+type requestStats struct {
+	inFlight atomic.Int64
+	requests atomic.Uint64
+	// requests of the current second and of the previous second
+	window      atomic.Int64
+	windowCount atomic.Uint64
+	lastCount   atomic.Uint64
+	status      [600]atomic.Uint64
+	// counters of Config.StatsTags
+	tags map[string]*tagStats
+	// latest stats of the prefork children by pid
+	children sync.Map
+}
+
+// start counts a request which is handled now.
+func (s *requestStats) start(now time.Time) {
+	s.inFlight.Add(1)
+	s.requests.Add(1)
+
+	sec := now.Unix()
+	if window := s.window.Load(); sec != window && s.window.CompareAndSwap(window, sec) {
+		if sec == window+1 {
+			s.lastCount.Store(s.windowCount.Swap(0))
+		} else {
+			s.lastCount.Store(0)
+			s.windowCount.Store(0)
+		}
+	}
+	s.windowCount.Add(1)
+}
+
+// done counts the response of a handled request.
+func (s *requestStats) done(c Ctx) {
+	if status := c.Response().StatusCode(); status >= 0 && status < len(s.status) {
+		s.status[status].Add(1)
+	}
+	if len(s.tags) > 0 {
+		s.countTags(c)
+	}
+	s.inFlight.Add(-1)
+}
+
+// perSecond returns the number of requests of the last completed second.
+func (s *requestStats) perSecond(now time.Time) uint64 {
+	switch now.Unix() - s.window.Load() {
+	case 0:
+		return s.lastCount.Load()
+	case 1:
+		return s.windowCount.Load()
+	default:
+		return 0
+	}
+}
+
+// Stats returns a snapshot of the connection and request statistics of the app.
+// In the prefork master process, the stats of all children are aggregated.
+// The stats can be exposed with the admin API or the expvar middleware:
+//
+//	expvar.Publish("fiber", expvar.Func(func() any {
+//	    return app.Stats()
+//	}))
+func (app *App) Stats() Stats {
+	stats := Stats{
+		Connections:       app.ConnStats(),
+		InFlight:          app.requestStats.inFlight.Load(),
+		Requests:          app.requestStats.requests.Load(),
+		RequestsPerSecond: app.requestStats.perSecond(time.Now()),
+		BytesIn:           app.connStats.bytesIn.Load(),
+		BytesOut:          app.connStats.bytesOut.Load(),
+		Status:            make(map[int]uint64),
+		Errors:            app.ErrorStats(),
+		Tags:              app.requestStats.tagStats(),
+		Companions:        app.companions.stats(),
+	}
+	for status := range app.requestStats.status {
+		if count := app.requestStats.status[status].Load(); count > 0 {
+			stats.Status[status] = count
+		}
+	}
+
+	app.requestStats.children.Range(func(_, value any) bool {
+		if child, ok := value.(PreforkChildStats); ok {
+			stats.add(child.Stats)
+			stats.Children++
+		}
+		return true
+	})
+
+	return stats
+}
+
+// PreforkChildren returns the latest stats of each prefork child, sorted by the PID.
+// It is empty outside the prefork master process.
+func (app *App) PreforkChildren() []PreforkChildStats {
+	var children []PreforkChildStats
+	app.requestStats.children.Range(func(_, value any) bool {
+		if child, ok := value.(PreforkChildStats); ok {
+			children = append(children, child)
+		}
+		return true
+	})
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].PID < children[j].PID
+	})
+	return children
+}
+
+// reportPreforkStats writes the stats of the prefork child to the pipe
+// of the master process, one JSON object per line.
+func (app *App) reportPreforkStats(w io.WriteCloser) {
+	defer w.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	ticker := time.NewTicker(preforkStatsInterval)
+	defer ticker.Stop()
+
+	enc := json.NewEncoder(w)
+	for range ticker.C {
+		if err := enc.Encode(app.Stats()); err != nil {
+			// the master process exited
+			return
+		}
+	}
+}
+
+// collectPreforkStats reads the stats of the prefork child with the given pid
+// until its pipe is closed, i.e. the child exited.
+func (app *App) collectPreforkStats(pid int, r io.ReadCloser) {
+	defer r.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	// the child is listed before its first report
+	child := PreforkChildStats{PID: pid}
+	app.requestStats.children.Store(pid, child)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var stats Stats
+		if err := json.Unmarshal(scanner.Bytes(), &stats); err != nil {
+			app.logw(log.LevelWarn, "prefork: invalid stats of child", "pid", pid, "error", err)
+			continue
+		}
+		child.Stats, child.Updated = stats, time.Now()
+		app.requestStats.children.Store(pid, child)
+	}
+
+	// the counters of the exited child stay in the aggregate, its gauges are reset
+	child.Exited = true
+	child.Stats.Connections.Open = 0
+	child.Stats.InFlight = 0
+	child.Stats.RequestsPerSecond = 0
+	app.requestStats.children.Store(pid, child)
+}
+
+// preforkStatsPipe returns the write end of the stats pipe of the prefork child,
+// or nil if the master process doesn't collect the stats.
+func preforkStatsPipe() *os.File {
+	if os.Getenv(envPreforkStatsFDKey) == "" {
+		return nil
+	}
+	return os.NewFile(preforkStatsFD, "fiber-prefork-stats")
+}
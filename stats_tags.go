@@ -0,0 +1,126 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxStatsTags is the maximum number of Config.StatsTags, which bounds the labels of the exported metrics.
+	maxStatsTags = 32
+	// maxStatsTagLength is the maximum length of a tag of Config.StatsTags.
+	maxStatsTagLength = 64
+)
+
+// TagStats contains the counters of the requests of the routes with a tag, see Config.StatsTags.
+type TagStats struct {
+	// Requests is the number of handled requests of the routes with the tag.
+	Requests uint64 `json:"requests"`
+	// Errors is the number of errors of the routes with the tag which were passed to the ErrorHandler.
+	Errors uint64 `json:"errors"`
+	// Duration is the total duration of the requests, divided by Requests it is the mean latency.
+	Duration time.Duration `json:"duration"`
+}
+
+// tagStats counts the requests of the routes with a tag.
+type tagStats struct {
+	requests atomic.Uint64
+	errors   atomic.Uint64
+	duration atomic.Int64
+}
+
+// newStatsTags validates the tags and returns their counters, it panics for invalid tags.
+func newStatsTags(tags []string) map[string]*tagStats {
+	if len(tags) == 0 {
+		return nil
+	}
+	if len(tags) > maxStatsTags {
+		panic(fmt.Errorf("%w: more than %d tags", ErrInvalidStatsTag, maxStatsTags))
+	}
+
+	stats := make(map[string]*tagStats, len(tags))
+	for _, tag := range tags {
+		if !validStatsTag(tag) {
+			panic(fmt.Errorf("%w: %q", ErrInvalidStatsTag, tag))
+		}
+		stats[tag] = &tagStats{}
+	}
+	return stats
+}
+
+// validStatsTag reports if the tag can be used as the label of a metric: it consists
+// of up to 64 letters, digits and the characters "_", ":", "." and "-".
+func validStatsTag(tag string) bool {
+	if tag == "" || len(tag) > maxStatsTagLength {
+		return false
+	}
+	for i := 0; i < len(tag); i++ {
+		switch b := tag[i]; {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9':
+		case b == '_', b == ':', b == '.', b == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// assignStatsTags sets the counters of the StatsTags of the routes. It must be called with the mutex held.
+func (app *App) assignStatsTags() {
+	tags := app.requestStats.tags
+	if len(tags) == 0 {
+		return
+	}
+
+	for m := range app.stack {
+		for _, route := range app.stack[m] {
+			route.statsTags = route.statsTags[:0]
+			for _, tag := range route.Tags {
+				if stats, ok := tags[tag]; ok {
+					route.statsTags = append(route.statsTags, stats)
+				}
+			}
+		}
+	}
+}
+
+// countTags counts the request in the tags of its route.
+func (*requestStats) countTags(c Ctx) {
+	route := c.Route()
+	if len(route.statsTags) == 0 {
+		return
+	}
+	duration := int64(time.Since(c.Context().Time()))
+	for _, stats := range route.statsTags {
+		stats.requests.Add(1)
+		stats.duration.Add(duration)
+	}
+}
+
+// countTagError counts the error in the tags of the route of the request.
+func (*requestStats) countTagError(c Ctx) {
+	for _, stats := range c.Route().statsTags {
+		stats.errors.Add(1)
+	}
+}
+
+// tagStats returns a snapshot of the counters of the tags.
+func (s *requestStats) tagStats() map[string]TagStats {
+	if len(s.tags) == 0 {
+		return nil
+	}
+	stats := make(map[string]TagStats, len(s.tags))
+	for tag, counters := range s.tags {
+		stats[tag] = TagStats{
+			Requests: counters.requests.Load(),
+			Errors:   counters.errors.Load(),
+			Duration: time.Duration(counters.duration.Load()),
+		}
+	}
+	return stats
+}
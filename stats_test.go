@@ -0,0 +1,210 @@
+package fiber
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_App_Stats
+func Test_App_Stats(t *testing.T) {
+	t.Parallel()
+	app := New(Config{EnableRequestStats: true})
+	app.Get("/", testSimpleHandler)
+
+	for i := 0; i < 3; i++ {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+		require.NoError(t, err)
+		require.Equal(t, StatusOK, resp.StatusCode)
+	}
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/missing", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusNotFound, resp.StatusCode)
+
+	stats := app.Stats()
+	require.Equal(t, int64(0), stats.InFlight)
+	require.Equal(t, uint64(4), stats.Requests)
+	require.Equal(t, map[int]uint64{StatusOK: 3, StatusNotFound: 1}, stats.Status)
+	require.Zero(t, stats.Children)
+
+	// the requests are only counted with EnableRequestStats
+	app = New()
+	app.Get("/", testSimpleHandler)
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+	stats = app.Stats()
+	require.Zero(t, stats.Requests)
+	require.Empty(t, stats.Status)
+}
+
+// go test -run Test_App_Stats_Bytes
+func Test_App_Stats_Bytes(t *testing.T) {
+	t.Parallel()
+	app, ln := startConnLimitApp(t, ListenConfig{})
+
+	var ok bool
+	require.Eventually(t, func() bool {
+		_, ok = dialConnLimitApp(t, ln)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	stats := app.Stats()
+	require.Equal(t, uint64(len("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")), stats.BytesIn)
+	require.NotZero(t, stats.BytesOut)
+	// the requests aren't counted without EnableRequestStats
+	require.Zero(t, stats.Requests)
+	require.NoError(t, app.Shutdown())
+}
+
+// go test -run Test_RequestStats_PerSecond
+func Test_RequestStats_PerSecond(t *testing.T) {
+	t.Parallel()
+	var stats requestStats
+	now := time.Unix(1000, 0)
+
+	stats.start(now)
+	stats.start(now.Add(500 * time.Millisecond))
+	// the current second isn't completed yet
+	require.Equal(t, uint64(0), stats.perSecond(now))
+	require.Equal(t, uint64(2), stats.perSecond(now.Add(time.Second)))
+
+	stats.start(now.Add(time.Second))
+	require.Equal(t, uint64(2), stats.perSecond(now.Add(time.Second)))
+	require.Equal(t, uint64(1), stats.perSecond(now.Add(2*time.Second)))
+	// no requests in the last second
+	require.Equal(t, uint64(0), stats.perSecond(now.Add(3*time.Second)))
+
+	stats.start(now.Add(5 * time.Second))
+	require.Equal(t, uint64(0), stats.perSecond(now.Add(5*time.Second)))
+	require.Equal(t, uint64(4), stats.requests.Load())
+}
+
+// go test -run Test_App_Stats_PreforkChildren
+func Test_App_Stats_PreforkChildren(t *testing.T) {
+	t.Parallel()
+	logger := &testLogger{}
+	app := New(Config{Logger: logger})
+
+	for pid, requests := range map[int]uint64{10: 5, 11: 7} {
+		r, w := io.Pipe()
+		done := make(chan struct{})
+		go func() {
+			app.collectPreforkStats(pid, r)
+			close(done)
+		}()
+
+		child := Stats{Requests: requests, BytesIn: 100, Status: map[int]uint64{StatusOK: requests}}
+		b, err := json.Marshal(child)
+		require.NoError(t, err)
+		_, err = w.Write(append([]byte("invalid\n"), append(b, '\n')...))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		<-done
+	}
+
+	require.Len(t, logger.Entries(), 2)
+	stats := app.Stats()
+	require.Equal(t, 2, stats.Children)
+	require.Equal(t, uint64(12), stats.Requests)
+	require.Equal(t, uint64(200), stats.BytesIn)
+	require.Equal(t, map[int]uint64{StatusOK: 12}, stats.Status)
+
+	children := app.PreforkChildren()
+	require.Len(t, children, 2)
+	require.Equal(t, 10, children[0].PID)
+	require.Equal(t, uint64(5), children[0].Stats.Requests)
+	require.False(t, children[0].Updated.IsZero())
+	require.True(t, children[0].Exited)
+	require.Equal(t, 11, children[1].PID)
+}
+
+// go test -run Test_App_Stats_PreforkChildren_Exited
+func Test_App_Stats_PreforkChildren_Exited(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	r, w := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		app.collectPreforkStats(10, r)
+		close(done)
+	}()
+
+	// the child is listed before its first report
+	require.Eventually(t, func() bool {
+		return len(app.PreforkChildren()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.True(t, app.PreforkChildren()[0].Updated.IsZero())
+
+	b, err := json.Marshal(Stats{
+		Connections:       ConnStats{Open: 3, Accepted: 9},
+		InFlight:          2,
+		Requests:          20,
+		RequestsPerSecond: 4,
+	})
+	require.NoError(t, err)
+	_, err = w.Write(append(b, '\n'))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return app.Stats().InFlight == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, int64(3), app.Stats().Connections.Open)
+
+	// the gauges of the exited child are reset, its counters stay in the aggregate
+	require.NoError(t, w.Close())
+	<-done
+	stats := app.Stats()
+	require.Equal(t, int64(0), stats.InFlight)
+	require.Equal(t, int64(0), stats.Connections.Open)
+	require.Equal(t, uint64(0), stats.RequestsPerSecond)
+	require.Equal(t, uint64(9), stats.Connections.Accepted)
+	require.Equal(t, uint64(20), stats.Requests)
+	require.Equal(t, 1, stats.Children)
+	require.True(t, app.PreforkChildren()[0].Exited)
+}
+
+// go test -run Test_App_Stats_Tags
+func Test_App_Stats_Tags(t *testing.T) {
+	t.Parallel()
+	app := New(Config{EnableRequestStats: true, StatsTags: []string{"domain:orders", "domain:users"}})
+	app.Get("/orders", testSimpleHandler).Tag("domain:orders", "audit")
+	app.Get("/orders/fail", func(_ Ctx) error {
+		return ErrBadRequest
+	}).Tag("domain:orders")
+	app.Get("/health", testSimpleHandler)
+
+	for _, path := range []string{"/orders", "/orders", "/orders/fail", "/health"} {
+		_, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		require.NoError(t, err)
+	}
+
+	stats := app.Stats()
+	require.Len(t, stats.Tags, 2)
+	require.Equal(t, uint64(3), stats.Tags["domain:orders"].Requests)
+	require.Equal(t, uint64(1), stats.Tags["domain:orders"].Errors)
+	require.Positive(t, stats.Tags["domain:orders"].Duration)
+	require.Equal(t, TagStats{}, stats.Tags["domain:users"])
+
+	// the tags of the prefork children are summed up
+	stats.add(Stats{Tags: map[string]TagStats{"domain:orders": {Requests: 2, Errors: 1}}})
+	require.Equal(t, uint64(5), stats.Tags["domain:orders"].Requests)
+	require.Equal(t, uint64(2), stats.Tags["domain:orders"].Errors)
+
+	// the tags are bounded and validated
+	require.PanicsWithError(t, `stats: invalid tag: "domain orders"`, func() {
+		New(Config{EnableRequestStats: true, StatsTags: []string{"domain orders"}})
+	})
+	tags := make([]string, maxStatsTags+1)
+	for i := range tags {
+		tags[i] = "tag" + strconv.Itoa(i)
+	}
+	require.PanicsWithError(t, "stats: invalid tag: more than 32 tags", func() {
+		New(Config{EnableRequestStats: true, StatsTags: tags})
+	})
+}
@@ -0,0 +1,164 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+)
+
+// HeaderViolationStats contains the counters of the header violations of the strict header mode,
+// see Config.StrictHeaders.
+//
+// They can be exposed with the expvar middleware:
+//
+//	expvar.Publish("fiber_header_violations", expvar.Func(func() any {
+//	    return app.HeaderViolations()
+//	}))
+type HeaderViolationStats struct {
+	// ConflictingLength is the number of requests with both Content-Length and Transfer-Encoding.
+	ConflictingLength uint64 `json:"conflicting_length"`
+	// ObsFold is the number of requests with obsolete line folding.
+	ObsFold uint64 `json:"obs_fold"`
+	// NULByte is the number of requests with NUL bytes in the headers.
+	NULByte uint64 `json:"nul_byte"`
+	// TooManyHeaders is the number of requests with more headers than Config.MaxRequestHeaders.
+	TooManyHeaders uint64 `json:"too_many_headers"`
+	// Allowed is the number of violating requests of the clients of Config.StrictHeadersAllowlist,
+	// which weren't rejected.
+	Allowed uint64 `json:"allowed"`
+}
+
+type headerViolations struct {
+	conflictingLength atomic.Uint64
+	obsFold           atomic.Uint64
+	nulByte           atomic.Uint64
+	tooManyHeaders    atomic.Uint64
+	allowed           atomic.Uint64
+}
+
+// HeaderViolations returns the counters of the header violations of the strict header mode.
+func (app *App) HeaderViolations() HeaderViolationStats {
+	return HeaderViolationStats{
+		ConflictingLength: app.headerViolations.conflictingLength.Load(),
+		ObsFold:           app.headerViolations.obsFold.Load(),
+		NULByte:           app.headerViolations.nulByte.Load(),
+		TooManyHeaders:    app.headerViolations.tooManyHeaders.Load(),
+		Allowed:           app.headerViolations.allowed.Load(),
+	}
+}
+
+// handleStrictHeadersAllowlist parses the IPs and IP ranges of the legacy clients
+// which are exempt from the strict header mode.
+func (app *App) handleStrictHeadersAllowlist() {
+	app.strictHeadersAllowlist = make([]*net.IPNet, 0, len(app.config.StrictHeadersAllowlist))
+	for _, ipAddress := range app.config.StrictHeadersAllowlist {
+		cidr := ipAddress
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			app.logw(log.LevelWarn, "IP range could not be parsed", "range", ipAddress, "error", err)
+			continue
+		}
+		app.strictHeadersAllowlist = append(app.strictHeadersAllowlist, ipNet)
+	}
+}
+
+// checkStrictHeaders returns the error of the first header violation of the request, or nil.
+// The violations of the clients of the allowlist are counted, but not returned.
+func (app *App) checkStrictHeaders(c CustomCtx) error {
+	err := app.headerViolation(c)
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, ErrConflictingContentLength):
+		app.headerViolations.conflictingLength.Add(1)
+	case errors.Is(err, ErrObsoleteLineFolding):
+		app.headerViolations.obsFold.Add(1)
+	case errors.Is(err, ErrHeaderNULByte):
+		app.headerViolations.nulByte.Add(1)
+	default:
+		app.headerViolations.tooManyHeaders.Add(1)
+	}
+
+	ip := c.Context().RemoteIP()
+	for _, ipNet := range app.strictHeadersAllowlist {
+		if ipNet.Contains(ip) {
+			app.headerViolations.allowed.Add(1)
+			return nil
+		}
+	}
+
+	return err
+}
+
+// headerViolation returns the error of the first header violation of the request, or nil.
+// The raw headers are checked, because fasthttp normalizes them while parsing. Requests
+// without raw headers, e.g. HTTP/2 requests, are only checked for the number of headers.
+func (app *App) headerViolation(c CustomCtx) error {
+	header := &c.Request().Header
+	raw := header.RawHeaders()
+	if len(raw) == 0 {
+		if header.Len() > app.config.MaxRequestHeaders {
+			return ErrTooManyHeaders
+		}
+		return nil
+	}
+
+	if bytes.IndexByte(raw, 0) != -1 {
+		return ErrHeaderNULByte
+	}
+
+	var count int
+	var contentLength, transferEncoding bool
+	for len(raw) > 0 {
+		var line []byte
+		if i := bytes.IndexByte(raw, '\n'); i != -1 {
+			line, raw = raw[:i], raw[i+1:]
+		} else {
+			line, raw = raw, nil
+		}
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if len(line) == 0 {
+			break
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			return ErrObsoleteLineFolding
+		}
+
+		count++
+		name, _, _ := bytes.Cut(line, []byte(":"))
+		name = bytes.TrimRight(name, " \t")
+		switch {
+		case utils.EqualFold(name, []byte(HeaderContentLength)):
+			contentLength = true
+		case utils.EqualFold(name, []byte(HeaderTransferEncoding)):
+			transferEncoding = true
+		}
+	}
+
+	if contentLength && transferEncoding {
+		return ErrConflictingContentLength
+	}
+	if count > app.config.MaxRequestHeaders {
+		return ErrTooManyHeaders
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package fiber
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// testRawRequest handles the raw request of the client ip with the app and returns the status code.
+func testRawRequest(t *testing.T, app *App, ip, raw string) int {
+	t.Helper()
+
+	var req fasthttp.Request
+	require.NoError(t, req.Read(bufio.NewReader(strings.NewReader(raw))))
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Init(&req, &net.TCPAddr{IP: net.ParseIP(ip)}, nil)
+	app.Handler()(fctx)
+
+	return fctx.Response.StatusCode()
+}
+
+// go test -run Test_App_StrictHeaders
+func Test_App_StrictHeaders(t *testing.T) {
+	t.Parallel()
+
+	app := New(Config{StrictHeaders: true, MaxRequestHeaders: 3})
+	app.All("/", func(c Ctx) error {
+		return c.SendString("ok")
+	})
+
+	require.Equal(t, StatusOK, testRawRequest(t, app, "10.0.0.1",
+		"GET / HTTP/1.1\r\nHost: example.com\r\nAccept: */*\r\n\r\n"))
+
+	require.Equal(t, StatusBadRequest, testRawRequest(t, app, "10.0.0.1",
+		"POST / HTTP/1.1\r\nHost: example.com\r\nContent-Length: 5\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nhello\r\n0\r\n\r\n"))
+
+	require.Equal(t, StatusBadRequest, testRawRequest(t, app, "10.0.0.1",
+		"GET / HTTP/1.1\r\nHost: example.com\r\nX-Custom: a\r\n b\r\n\r\n"))
+
+	require.Equal(t, StatusBadRequest, testRawRequest(t, app, "10.0.0.1",
+		"GET / HTTP/1.1\r\nHost: example.com\r\nX-Custom: a\x00b\r\n\r\n"))
+
+	require.Equal(t, StatusRequestHeaderFieldsTooLarge, testRawRequest(t, app, "10.0.0.1",
+		"GET / HTTP/1.1\r\nHost: example.com\r\nA: 1\r\nB: 2\r\nC: 3\r\n\r\n"))
+
+	require.Equal(t, HeaderViolationStats{
+		ConflictingLength: 1,
+		ObsFold:           1,
+		NULByte:           1,
+		TooManyHeaders:    1,
+	}, app.HeaderViolations())
+}
+
+// go test -run Test_App_StrictHeaders_Allowlist
+func Test_App_StrictHeaders_Allowlist(t *testing.T) {
+	t.Parallel()
+
+	app := New(Config{StrictHeaders: true, StrictHeadersAllowlist: []string{"10.1.0.0/16", "192.168.0.10", "invalid"}})
+	app.Get("/", func(c Ctx) error {
+		return c.SendString("ok")
+	})
+
+	raw := "GET / HTTP/1.1\r\nHost: example.com\r\nX-Custom: a\r\n b\r\n\r\n"
+	require.Equal(t, StatusOK, testRawRequest(t, app, "10.1.2.3", raw))
+	require.Equal(t, StatusOK, testRawRequest(t, app, "192.168.0.10", raw))
+	require.Equal(t, StatusBadRequest, testRawRequest(t, app, "192.168.0.11", raw))
+
+	require.Equal(t, HeaderViolationStats{ObsFold: 3, Allowed: 2}, app.HeaderViolations())
+}
+
+// go test -run Test_App_StrictHeaders_Disabled
+func Test_App_StrictHeaders_Disabled(t *testing.T) {
+	t.Parallel()
+
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.SendString("ok")
+	})
+
+	require.Equal(t, StatusOK, testRawRequest(t, app, "10.0.0.1",
+		"GET / HTTP/1.1\r\nHost: example.com\r\nX-Custom: a\r\n b\r\n\r\n"))
+	require.Equal(t, HeaderViolationStats{}, app.HeaderViolations())
+}
@@ -0,0 +1,282 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/gofiber/utils/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// Supervisor runs several apps behind one shared listener and dispatches the
+// requests by the Host header, or the TLS server name (SNI) if the Host header is empty.
+//
+//	sv := fiber.NewSupervisor()
+//	sv.Host("api.example.com", apiApp)
+//	sv.Host("*.example.com", tenantApp)
+//	sv.Default(wwwApp)
+//	log.Fatal(sv.Listen(":443", fiber.ListenConfig{CertFile: "cert.pem", CertKeyFile: "key.pem"}))
+//
+// The listener, TLS, prefork and the server settings are handled by the front app,
+// which can be configured by the config passed to NewSupervisor.
+type Supervisor struct {
+	mutex sync.RWMutex
+	// App which owns the shared listener and server
+	app *App
+	// Apps by exact hostname
+	hosts map[string]*App
+	// Apps by wildcard suffix, e.g. ".example.com", longest suffix first
+	wildcards []supervisorWildcard
+	// App for unknown hosts
+	fallback *App
+	// check registered lifecycle hooks
+	hooksRegistered sync.Once
+}
+
+type supervisorWildcard struct {
+	suffix string
+	app    *App
+}
+
+// NewSupervisor creates a new Supervisor. The config is used for the front app
+// which owns the shared listener, e.g. for the server header, limits and timeouts.
+// Requests for unknown hosts are answered with ErrMisdirectedRequest by the
+// error handler of the front app unless a default app is set.
+func NewSupervisor(config ...Config) *Supervisor {
+	s := &Supervisor{
+		app:   New(config...),
+		hosts: make(map[string]*App),
+	}
+	s.app.server.Handler = s.requestHandler
+
+	return s
+}
+
+// Host registers the app for the given hostname. A leading "*." registers the
+// app for all subdomains of the hostname, e.g. "*.example.com".
+func (s *Supervisor) Host(host string, app *App) *Supervisor {
+	host = utils.ToLower(strings.TrimSpace(host))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if strings.HasPrefix(host, "*.") {
+		s.wildcards = append(s.wildcards, supervisorWildcard{suffix: host[1:], app: app})
+		sort.SliceStable(s.wildcards, func(i, j int) bool {
+			return len(s.wildcards[i].suffix) > len(s.wildcards[j].suffix)
+		})
+		return s
+	}
+
+	s.hosts[host] = app
+	return s
+}
+
+// Default sets the app for requests with an unknown host.
+func (s *Supervisor) Default(app *App) *Supervisor {
+	s.mutex.Lock()
+	s.fallback = app
+	s.mutex.Unlock()
+
+	return s
+}
+
+// App returns the front app which owns the shared listener and server.
+func (s *Supervisor) App() *App {
+	return s.app
+}
+
+// Apps returns all distinct apps of the supervisor.
+func (s *Supervisor) Apps() []*App {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	seen := make(map[*App]struct{})
+	apps := make([]*App, 0, len(s.hosts)+len(s.wildcards)+1)
+	add := func(app *App) {
+		if app == nil {
+			return
+		}
+		if _, ok := seen[app]; ok {
+			return
+		}
+		seen[app] = struct{}{}
+		apps = append(apps, app)
+	}
+
+	for _, app := range s.hosts {
+		add(app)
+	}
+	for _, w := range s.wildcards {
+		add(w.app)
+	}
+	add(s.fallback)
+
+	return apps
+}
+
+// Handler returns the request handler of the supervisor.
+func (s *Supervisor) Handler() fasthttp.RequestHandler {
+	s.startupProcess()
+	return s.requestHandler
+}
+
+// Listen serves the apps of the supervisor from the given addr.
+// The ListenConfig is applied to the shared listener, see App.Listen.
+// If a GracefulContext is given, all apps are shut down when it is done.
+func (s *Supervisor) Listen(addr string, config ...ListenConfig) error {
+	cfg := listenConfigDefault(config...)
+
+	if cfg.GracefulContext != nil {
+		ctx, cancel := context.WithCancel(cfg.GracefulContext)
+		defer cancel()
+
+		go s.gracefulShutdown(ctx, cfg)
+		cfg.GracefulContext = nil
+	}
+
+	s.startupProcess()
+
+	return s.app.Listen(addr, cfg)
+}
+
+// Listener serves the apps of the supervisor from the given listener.
+// If a GracefulContext is given, all apps are shut down when it is done.
+func (s *Supervisor) Listener(ln net.Listener, config ...ListenConfig) error {
+	cfg := listenConfigDefault(config...)
+
+	if cfg.GracefulContext != nil {
+		ctx, cancel := context.WithCancel(cfg.GracefulContext)
+		defer cancel()
+
+		go s.gracefulShutdown(ctx, cfg)
+		cfg.GracefulContext = nil
+	}
+
+	s.startupProcess()
+
+	return s.app.Listener(ln, cfg)
+}
+
+// Test is used for internal debugging by passing a *http.Request, see App.Test.
+func (s *Supervisor) Test(req *http.Request, timeout ...time.Duration) (*http.Response, error) {
+	s.startupProcess()
+	return s.app.Test(req, timeout...)
+}
+
+// Shutdown gracefully shuts down the shared server and all apps of the supervisor.
+func (s *Supervisor) Shutdown() error {
+	return s.ShutdownWithContext(context.Background())
+}
+
+// ShutdownWithContext shuts down the shared server and afterwards all apps of the
+// supervisor, so that their shutdown hooks and plugins are executed.
+func (s *Supervisor) ShutdownWithContext(ctx context.Context) error {
+	errs := []error{s.app.ShutdownWithContext(ctx)}
+	for _, app := range s.Apps() {
+		if err := app.ShutdownWithContext(ctx); err != nil && !errors.Is(err, ErrNotRunning) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// startupProcess prepares all apps of the supervisor and lets them follow the lifecycle of the front app.
+func (s *Supervisor) startupProcess() {
+	for _, app := range s.Apps() {
+		app.startupProcess()
+	}
+
+	s.hooksRegistered.Do(func() {
+		s.app.Hooks().OnListen(func(data ListenData) error {
+			for _, app := range s.Apps() {
+				if err := app.startPlugins(context.Background()); err != nil {
+					return err
+				}
+				if err := app.hooks.executeOnListenHooks(data); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// requestHandler dispatches the request to the app of the requested host.
+func (s *Supervisor) requestHandler(fctx *fasthttp.RequestCtx) {
+	host := s.app.getString(fctx.Host())
+	if host == "" {
+		if state := fctx.TLSConnectionState(); state != nil {
+			host = state.ServerName
+		}
+	}
+
+	if app := s.appForHost(host); app != nil {
+		app.requestHandler(fctx)
+		return
+	}
+
+	c := s.app.AcquireCtx(fctx)
+	defer s.app.ReleaseCtx(c)
+
+	if catch := s.app.ErrorHandler(c, ErrMisdirectedRequest); catch != nil {
+		_ = c.SendStatus(StatusInternalServerError) //nolint:errcheck // It is fine to ignore the error here
+	}
+}
+
+// appForHost returns the app for the given host or the default app.
+func (s *Supervisor) appForHost(host string) *App {
+	host = utils.ToLower(hostWithoutPort(host))
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if app, ok := s.hosts[host]; ok {
+		return app
+	}
+	for _, w := range s.wildcards {
+		if len(host) > len(w.suffix) && strings.HasSuffix(host, w.suffix) {
+			return w.app
+		}
+	}
+
+	return s.fallback
+}
+
+// shutdown goroutine
+func (s *Supervisor) gracefulShutdown(ctx context.Context, cfg ListenConfig) {
+	<-ctx.Done()
+
+	if err := s.Shutdown(); err != nil { //nolint:contextcheck // TODO: Implement it
+		if cfg.OnShutdownError != nil {
+			cfg.OnShutdownError(err)
+		} else {
+			s.app.logw(log.LevelError, "shutdown: failed to shut down", "error", err)
+		}
+	}
+
+	if success := cfg.OnShutdownSuccess; success != nil {
+		success()
+	}
+}
+
+// hostWithoutPort strips the port of a host, e.g. "example.com:8080" or "[::1]:8080".
+func hostWithoutPort(host string) string {
+	i := strings.LastIndexByte(host, ':')
+	if i == -1 || i < strings.LastIndexByte(host, ']') {
+		return host
+	}
+	return host[:i]
+}
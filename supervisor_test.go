@@ -0,0 +1,135 @@
+package fiber
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+func newHostApp(body string) *App {
+	app := New()
+	app.Get("/", func(c Ctx) error {
+		return c.SendString(body)
+	})
+	return app
+}
+
+// go test -run Test_Supervisor_Host
+func Test_Supervisor_Host(t *testing.T) {
+	t.Parallel()
+	sv := NewSupervisor()
+	sv.Host("api.example.com", newHostApp("api"))
+	sv.Host("*.example.com", newHostApp("tenant"))
+	sv.Host("*.eu.example.com", newHostApp("eu"))
+
+	testCases := []struct {
+		host   string
+		body   string
+		status int
+	}{
+		{host: "api.example.com", body: "api", status: StatusOK},
+		{host: "API.Example.com:8080", body: "api", status: StatusOK},
+		{host: "acme.example.com", body: "tenant", status: StatusOK},
+		{host: "acme.eu.example.com", body: "eu", status: StatusOK},
+		{host: "example.com", status: StatusMisdirectedRequest},
+		{host: "other.org", status: StatusMisdirectedRequest},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(MethodGet, "/", nil)
+		req.Host = tc.host
+
+		resp, err := sv.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, tc.status, resp.StatusCode, tc.host)
+
+		if tc.status == StatusOK {
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tc.body, string(body), tc.host)
+		}
+	}
+}
+
+// go test -run Test_Supervisor_Default
+func Test_Supervisor_Default(t *testing.T) {
+	t.Parallel()
+	sv := NewSupervisor()
+	sv.Host("api.example.com", newHostApp("api"))
+	sv.Default(newHostApp("www"))
+
+	req := httptest.NewRequest(MethodGet, "/", nil)
+	req.Host = "unknown.org"
+
+	resp, err := sv.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "www", string(body))
+	require.Len(t, sv.Apps(), 2)
+}
+
+// go test -run Test_Supervisor_Lifecycle
+func Test_Supervisor_Lifecycle(t *testing.T) {
+	t.Parallel()
+	sv := NewSupervisor()
+
+	var events []string
+	api := newHostApp("api")
+	api.Use(&testPlugin{name: "api", events: &events})
+	api.Hooks().OnListen(func(ListenData) error {
+		events = append(events, "listen:api")
+		return nil
+	})
+	api.Hooks().OnShutdown(func() error {
+		events = append(events, "shutdown:api")
+		return nil
+	})
+	sv.Host("api.example.com", api)
+
+	ln := fasthttputil.NewInmemoryListener()
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	shutdown := make(chan struct{})
+	go func() {
+		errs <- sv.Listener(ln, ListenConfig{
+			DisableStartupMessage: true,
+			GracefulContext:       ctx,
+			OnShutdownSuccess: func() {
+				close(shutdown)
+			},
+		})
+	}()
+
+	// Server readiness check
+	require.Eventually(t, func() bool {
+		conn, err := ln.Dial()
+		if err != nil {
+			return false
+		}
+		conn.Close() //nolint:errcheck // ignore error
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-errs)
+	<-shutdown
+
+	require.Equal(t, []string{"start:api", "listen:api", "shutdown:api", "stop:api"}, events)
+}
+
+// go test -run Test_HostWithoutPort
+func Test_HostWithoutPort(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "example.com", hostWithoutPort("example.com"))
+	require.Equal(t, "example.com", hostWithoutPort("example.com:8080"))
+	require.Equal(t, "[::1]", hostWithoutPort("[::1]:8080"))
+	require.Equal(t, "[::1]", hostWithoutPort("[::1]"))
+}
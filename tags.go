@@ -0,0 +1,105 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"slices"
+)
+
+// tagMiddleware contains the middlewares of UseTag which are injected into the routes with the tag.
+type tagMiddleware struct {
+	tag      string
+	handlers []Handler
+	sites    []string
+}
+
+// Tag adds tags to the latest registered route, e.g. "auth:required". The middlewares which
+// were registered for the tags with UseTag are executed before the handlers of the route,
+// independent of the groups of the route. If it is set for a middleware, e.g. of a group,
+// the middlewares of the tags are executed before the middleware.
+//
+//	app.Get("/orders", handler).Tag("auth:required", "audit")
+func (app *App) Tag(tags ...string) Router {
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	for _, routes := range app.stack {
+		for _, route := range routes {
+			isMethodValid := route.Method == app.latestRoute.Method || app.latestRoute.use ||
+				(app.latestRoute.Method == MethodGet && route.Method == MethodHead)
+
+			// middlewares with the same path share their tags
+			if route.Path == app.latestRoute.Path && route.use == app.latestRoute.use && isMethodValid {
+				for _, tag := range tags {
+					if !slices.Contains(route.Tags, tag) {
+						route.Tags = append(route.Tags, tag)
+					}
+				}
+			}
+		}
+	}
+	app.routesRefreshed = true
+
+	return app
+}
+
+// Tag adds tags to the latest registered route.
+func (grp *Group) Tag(tags ...string) Router {
+	grp.app.Tag(tags...)
+
+	return grp
+}
+
+// UseTag registers middlewares which are executed before the handlers of all routes with the tag,
+// including the routes which are tagged later, e.g. to decouple the authentication from the
+// structure of the groups. The middlewares of multiple tags of a route are executed in the order
+// of their registration. They are injected when the server is started, like the routes of mounted apps.
+//
+//	app.UseTag("auth:required", jwtMiddleware)
+//	app.Get("/orders", handler).Tag("auth:required")
+func (app *App) UseTag(tag string, handlers ...Handler) {
+	if len(handlers) == 0 {
+		panic("use tag: missing handler for tag " + tag)
+	}
+
+	app.mutex.Lock()
+	defer app.mutex.Unlock()
+
+	app.tagMiddlewares = append(app.tagMiddlewares, tagMiddleware{
+		tag:      tag,
+		handlers: handlers,
+		sites:    registrationSites(len(handlers)),
+	})
+	app.routesRefreshed = true
+}
+
+// injectTagMiddlewares injects the middlewares of UseTag before the handlers of the routes with
+// their tags. The injected middlewares are replaced on each call. It must be called with the mutex held.
+func (app *App) injectTagMiddlewares() {
+	if len(app.tagMiddlewares) == 0 {
+		return
+	}
+
+	for m := range app.stack {
+		for _, route := range app.stack[m] {
+			var handlers []Handler
+			var sites []string
+			for _, middleware := range app.tagMiddlewares {
+				if slices.Contains(route.Tags, middleware.tag) {
+					handlers = append(handlers, middleware.handlers...)
+					sites = append(sites, middleware.sites...)
+				}
+			}
+			if len(handlers) == 0 && route.injected == 0 {
+				continue
+			}
+
+			// the same route can be in the stacks of multiple methods, e.g. GET and HEAD
+			route.Handlers = append(handlers, route.Handlers[route.injected:]...)
+			route.sites = append(sites, route.sites[min(route.injected, len(route.sites)):]...)
+			route.injected = len(handlers)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_App_UseTag
+func Test_App_UseTag(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	appendHeader := func(value string) Handler {
+		return func(c Ctx) error {
+			c.Append("X-Chain", value)
+			return c.Next()
+		}
+	}
+	app.UseTag("auth:required", appendHeader("auth"))
+	app.Use(appendHeader("use"))
+
+	api := app.Group("/api", appendHeader("group"))
+	api.Get("/orders", func(c Ctx) error {
+		c.Append("X-Chain", "handler")
+		return nil
+	}).Tag("auth:required", "audit")
+	api.Get("/status", testSimpleHandler)
+
+	// the middlewares are also injected into the routes which were tagged before
+	app.UseTag("audit", appendHeader("audit"))
+
+	request := func(method, path string) (int, string) {
+		resp, err := app.Test(httptest.NewRequest(method, path, nil))
+		require.NoError(t, err)
+		return resp.StatusCode, resp.Header.Get("X-Chain")
+	}
+
+	status, chain := request(MethodGet, "/api/orders")
+	require.Equal(t, StatusOK, status)
+	require.Equal(t, "use, group, auth, audit, handler", chain)
+	_, chain = request(MethodGet, "/api/status")
+	require.Equal(t, "use, group", chain)
+
+	// the injected middlewares are replaced when the tree is rebuilt
+	app.Get("/health", testSimpleHandler).Tag("audit")
+	_, chain = request(MethodGet, "/api/orders")
+	require.Equal(t, "use, group, auth, audit, handler", chain)
+	_, chain = request(MethodGet, "/health")
+	require.Equal(t, "use, audit", chain)
+
+	routes := app.GetRoutes(true)
+	require.Equal(t, "/api/orders", routes[0].Path)
+	require.Equal(t, []string{"auth:required", "audit"}, routes[0].Tags)
+
+	chainHandlers := app.MiddlewareChain(MethodGet, "/api/orders")
+	require.Len(t, chainHandlers, 5)
+	require.Contains(t, chainHandlers[2].Site, "tags_test.go")
+}
+
+// go test -run Test_App_UseTag_Panic
+func Test_App_UseTag_Panic(t *testing.T) {
+	t.Parallel()
+	require.Panics(t, func() {
+		New().UseTag("auth:required")
+	})
+}
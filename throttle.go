@@ -0,0 +1,329 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// ThrottleConfig configures the adaptive throttle, which watches the error rate and the latency
+// of the responses and sheds a percentage of the low-priority requests while the error budget
+// of the SLO burns too fast. Its spike arrest smooths the rate of the requests.
+type ThrottleConfig struct {
+	// SLO is the objective of the ratio of the good responses, e.g. 0.99. Responses
+	// with a 5xx status and responses slower than LatencyTarget are bad.
+	// Set to 0 to disable the adaptive shedding.
+	//
+	// Default: 0
+	SLO float64 `json:"slo"`
+
+	// LatencyTarget is the latency above which responses are bad.
+	// Set to 0 to only count the 5xx responses.
+	//
+	// Default: 0
+	LatencyTarget time.Duration `json:"latency_target"`
+
+	// Window is the duration whose responses are evaluated.
+	//
+	// Default: 30 * time.Second
+	Window time.Duration `json:"window"`
+
+	// Interval is the interval in which the shed percentage is adjusted.
+	//
+	// Default: 1 * time.Second
+	Interval time.Duration `json:"interval"`
+
+	// MinRequests is the minimum number of responses in the Window to evaluate them,
+	// with fewer responses the shed percentage is lowered.
+	//
+	// Default: 50
+	MinRequests int `json:"min_requests"`
+
+	// BurnThreshold is the burn rate of the error budget from which the shed percentage
+	// is raised by Step in every Interval. The burn rate is the ratio of the bad responses
+	// divided by the ratio allowed by the SLO, e.g. 5 for 5% bad responses with an SLO of 0.99.
+	//
+	// Default: 5
+	BurnThreshold float64 `json:"burn_threshold"`
+
+	// RecoverThreshold is the burn rate below which the shed percentage is lowered by Step
+	// in every Interval. In between the thresholds it is kept, so it doesn't oscillate.
+	//
+	// Default: 1
+	RecoverThreshold float64 `json:"recover_threshold"`
+
+	// Step is the percentage by which the shed percentage is raised or lowered.
+	//
+	// Default: 10
+	Step int `json:"step"`
+
+	// MaxShed is the maximum percentage of the low-priority requests which are shed.
+	//
+	// Default: 90
+	MaxShed int `json:"max_shed"`
+
+	// ShedBelow is the priority below which requests are shed, see Priority.
+	//
+	// Default: PriorityNormal
+	ShedBelow Priority `json:"shed_below"`
+
+	// MaxRate is the maximum number of requests per second of the spike arrest. It's enforced
+	// in fractions of a second, so spikes are rejected with 429 Too Many Requests even if the
+	// rate of the second is below MaxRate. Requests with PriorityCritical aren't arrested.
+	// Set to 0 to disable the spike arrest.
+	//
+	// Default: 0
+	MaxRate int `json:"max_rate"`
+
+	// Burst is the number of requests which the spike arrest allows at once.
+	//
+	// Default: MaxRate / 10, at least 1
+	Burst int `json:"burst"`
+}
+
+// ThrottleStats contains the state and the counters of the adaptive throttle.
+type ThrottleStats struct {
+	// Override is the shed percentage set by the operator, nil if it is adjusted automatically.
+	Override *int `json:"override"`
+	// Shedding is the current percentage of the low-priority requests which are shed.
+	Shedding int `json:"shedding"`
+	// BurnRate is the burn rate of the error budget of the last evaluated Window.
+	BurnRate float64 `json:"burn_rate"`
+	// Shed is the number of shed requests.
+	Shed uint64 `json:"shed"`
+	// Arrested is the number of requests which were rejected by the spike arrest.
+	Arrested uint64 `json:"arrested"`
+}
+
+// throttleSlot contains the response counters of an Interval.
+type throttleSlot struct {
+	total uint64
+	bad   uint64
+}
+
+// throttle sheds requests by the burn rate of the error budget and arrests spikes.
+type throttle struct {
+	config ThrottleConfig
+	logw   func(level log.Level, msg string, keysAndValues ...any)
+
+	// response counters of the current interval
+	total atomic.Uint64
+	bad   atomic.Uint64
+
+	// evaluation state, guarded by mutex
+	mutex    sync.Mutex
+	slots    []throttleSlot
+	current  int
+	lastEval time.Time
+
+	shedding atomic.Int64
+	override atomic.Int64
+	burnRate atomic.Uint64
+	admitted atomic.Uint64
+	shed     atomic.Uint64
+	arrested atomic.Uint64
+
+	// token bucket of the spike arrest, guarded by spikeMutex
+	spikeMutex sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newThrottle(config ThrottleConfig, clock Clock, logw func(level log.Level, msg string, keysAndValues ...any)) *throttle {
+	if config.Window <= 0 {
+		config.Window = 30 * time.Second
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = 50
+	}
+	if config.BurnThreshold <= 0 {
+		config.BurnThreshold = 5
+	}
+	if config.RecoverThreshold <= 0 {
+		config.RecoverThreshold = 1
+	}
+	if config.Step <= 0 {
+		config.Step = 10
+	}
+	if config.MaxShed <= 0 || config.MaxShed > 100 {
+		config.MaxShed = 90
+	}
+	if config.ShedBelow == 0 {
+		config.ShedBelow = PriorityNormal
+	}
+	if config.MaxRate > 0 && config.Burst <= 0 {
+		config.Burst = max(config.MaxRate/10, 1)
+	}
+
+	t := &throttle{
+		config:   config,
+		logw:     logw,
+		slots:    make([]throttleSlot, max(int(config.Window/config.Interval), 1)),
+		lastEval: clock.Now(),
+		tokens:   float64(config.Burst),
+	}
+	t.lastRefill = t.lastEval
+	t.override.Store(-1)
+	return t
+}
+
+// admit returns ErrTooManyRequests if the request was arrested, and ErrServiceUnavailable
+// if it was shed. The priority is only looked up if the request may be rejected.
+func (t *throttle) admit(c CustomCtx, now time.Time) error {
+	if t.config.MaxRate > 0 && !t.take(now) {
+		if requestPriority(c) < PriorityCritical {
+			t.arrested.Add(1)
+			return ErrTooManyRequests
+		}
+	}
+
+	percent := uint64(t.percent()) //nolint:gosec // the percentage is between 0 and 100
+	if percent == 0 || requestPriority(c) >= t.config.ShedBelow {
+		return nil
+	}
+	// the shed requests are spread evenly, e.g. every second request is shed at 50%
+	n := t.admitted.Add(1)
+	if n*percent/100 != (n-1)*percent/100 {
+		t.shed.Add(1)
+		return ErrServiceUnavailable
+	}
+	return nil
+}
+
+// take takes a token of the spike arrest, it returns false if there is none.
+func (t *throttle) take(now time.Time) bool {
+	t.spikeMutex.Lock()
+	defer t.spikeMutex.Unlock()
+
+	if elapsed := now.Sub(t.lastRefill); elapsed > 0 {
+		t.tokens = math.Min(t.tokens+elapsed.Seconds()*float64(t.config.MaxRate), float64(t.config.Burst))
+		t.lastRefill = now
+	}
+	if t.tokens < 1 {
+		return false
+	}
+	t.tokens--
+	return true
+}
+
+// percent returns the shed percentage, the override if it is set.
+func (t *throttle) percent() int64 {
+	if override := t.override.Load(); override >= 0 {
+		return override
+	}
+	return t.shedding.Load()
+}
+
+// observe counts the response of an admitted request and adjusts the shed percentage
+// when the Interval passed.
+func (t *throttle) observe(c Ctx, start, now time.Time) {
+	if t.config.SLO <= 0 {
+		return
+	}
+
+	t.total.Add(1)
+	if c.Response().StatusCode() >= StatusInternalServerError ||
+		(t.config.LatencyTarget > 0 && now.Sub(start) > t.config.LatencyTarget) {
+		t.bad.Add(1)
+	}
+
+	if !t.mutex.TryLock() {
+		return
+	}
+	defer t.mutex.Unlock()
+	if now.Sub(t.lastEval) >= t.config.Interval {
+		t.evaluate(now)
+	}
+}
+
+// evaluate moves the counters of the interval into the window and adjusts the shed percentage
+// by the burn rate of the window. It must be called with the mutex held.
+func (t *throttle) evaluate(now time.Time) {
+	// the slots of the intervals without responses are cleared
+	elapsed := min(int(now.Sub(t.lastEval)/t.config.Interval), len(t.slots))
+	for i := 1; i < elapsed; i++ {
+		t.current = (t.current + 1) % len(t.slots)
+		t.slots[t.current] = throttleSlot{}
+	}
+	t.current = (t.current + 1) % len(t.slots)
+	t.slots[t.current] = throttleSlot{total: t.total.Swap(0), bad: t.bad.Swap(0)}
+	t.lastEval = now
+
+	var total, bad uint64
+	for _, slot := range t.slots {
+		total += slot.total
+		bad += slot.bad
+	}
+
+	shedding := t.shedding.Load()
+	burnRate := 0.0
+	if total >= uint64(t.config.MinRequests) { //nolint:gosec // MinRequests is positive
+		burnRate = float64(bad) / float64(total) / (1 - math.Min(t.config.SLO, 0.9999))
+	}
+	t.burnRate.Store(math.Float64bits(burnRate))
+
+	next := shedding
+	switch {
+	case burnRate >= t.config.BurnThreshold:
+		next = min(shedding+int64(t.config.Step), int64(t.config.MaxShed))
+	case burnRate < t.config.RecoverThreshold:
+		next = max(shedding-int64(t.config.Step), 0)
+	}
+	if next == shedding {
+		return
+	}
+	t.shedding.Store(next)
+
+	level := log.LevelWarn
+	if next < shedding {
+		level = log.LevelInfo
+	}
+	t.logw(level, "throttle: shed percentage changed", "shedding", next, "burn_rate", burnRate)
+}
+
+// stats returns the state and the counters of the throttle.
+func (t *throttle) stats() ThrottleStats {
+	stats := ThrottleStats{
+		Shedding: int(t.percent()),
+		BurnRate: math.Float64frombits(t.burnRate.Load()),
+		Shed:     t.shed.Load(),
+		Arrested: t.arrested.Load(),
+	}
+	if override := t.override.Load(); override >= 0 {
+		percent := int(override)
+		stats.Override = &percent
+	}
+	return stats
+}
+
+// SetThrottleOverride sets the percentage of the low-priority requests which are shed by the
+// adaptive throttle, e.g. by an operator during an incident. A negative percentage restores
+// the automatic adjustment. It has no effect if the throttle isn't enabled in the config.
+//
+//	app.SetThrottleOverride(50) // shed every second low-priority request
+//	app.SetThrottleOverride(-1) // adjust automatically
+func (app *App) SetThrottleOverride(percent int) {
+	if app.throttle == nil {
+		return
+	}
+	app.throttle.override.Store(int64(max(min(percent, 100), -1)))
+	app.logw(log.LevelInfo, "throttle: override changed", "override", percent)
+}
+
+// ThrottleStats returns the state and the counters of the adaptive throttle.
+func (app *App) ThrottleStats() ThrottleStats {
+	if app.throttle == nil {
+		return ThrottleStats{}
+	}
+	return app.throttle.stats()
+}
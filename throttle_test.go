@@ -0,0 +1,150 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Throttle_Evaluate
+func Test_Throttle_Evaluate(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+	defer app.ReleaseCtx(c)
+
+	th := newThrottle(ThrottleConfig{SLO: 0.9, Window: 10 * time.Second, MinRequests: 10, Step: 50, MaxShed: 100}, app.config.Clock, app.logw)
+	base := th.lastEval
+	observe := func(status, count int, now time.Time) {
+		c.Status(status)
+		for i := 0; i < count; i++ {
+			th.observe(c, now, now)
+		}
+	}
+
+	// all responses fail, the burn rate is 10
+	observe(StatusInternalServerError, 10, base)
+	observe(StatusInternalServerError, 1, base.Add(time.Second))
+	require.Equal(t, 50, th.stats().Shedding)
+	require.InDelta(t, 10.0, th.stats().BurnRate, 0.001)
+
+	// the burn rate is between the thresholds, the shed percentage is kept
+	observe(StatusOK, 50, base.Add(1500*time.Millisecond))
+	observe(StatusOK, 1, base.Add(2*time.Second))
+	require.Equal(t, 50, th.stats().Shedding)
+	require.InDelta(t, 11.0/62/0.1, th.stats().BurnRate, 0.001)
+
+	// the failed responses left the window
+	observe(StatusOK, 1, base.Add(20*time.Second))
+	require.Equal(t, 0, th.stats().Shedding)
+
+	// slow responses are bad
+	th = newThrottle(ThrottleConfig{SLO: 0.99, LatencyTarget: 100 * time.Millisecond, MinRequests: 1}, app.config.Clock, app.logw)
+	c.Status(StatusOK)
+	th.observe(c, th.lastEval, th.lastEval.Add(time.Second))
+	require.Equal(t, 10, th.stats().Shedding)
+	require.InDelta(t, 100.0, th.stats().BurnRate, 0.001)
+}
+
+// go test -run Test_App_Throttle
+func Test_App_Throttle(t *testing.T) {
+	t.Parallel()
+	app := New(Config{Throttle: ThrottleConfig{SLO: 0.99}})
+	app.Get("/users", testSimpleHandler)
+	app.Get("/reports", testSimpleHandler).Priority(PriorityLow)
+
+	request := func(path string) int {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	app.SetThrottleOverride(50)
+	require.Equal(t, ThrottleStats{Override: &[]int{50}[0], Shedding: 50}, app.ThrottleStats())
+	statuses := make([]int, 0, 4)
+	for i := 0; i < 4; i++ {
+		statuses = append(statuses, request("/reports"))
+		require.Equal(t, StatusOK, request("/users"))
+	}
+	require.Equal(t, []int{StatusOK, StatusServiceUnavailable, StatusOK, StatusServiceUnavailable}, statuses)
+	require.Equal(t, uint64(2), app.ThrottleStats().Shed)
+
+	app.SetThrottleOverride(-1)
+	require.Nil(t, app.ThrottleStats().Override)
+	require.Equal(t, StatusOK, request("/reports"))
+
+	// the throttle is disabled by default
+	app = New()
+	app.SetThrottleOverride(100)
+	require.Equal(t, ThrottleStats{}, app.ThrottleStats())
+}
+
+// go test -run Test_App_Throttle_SpikeArrest
+func Test_App_Throttle_SpikeArrest(t *testing.T) {
+	t.Parallel()
+	app := New(Config{Throttle: ThrottleConfig{MaxRate: 1, Burst: 2}})
+	app.Get("/", testSimpleHandler)
+	app.Get("/health", testSimpleHandler).Priority(PriorityCritical)
+
+	request := func(path string) int {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, path, nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	require.Equal(t, StatusOK, request("/"))
+	require.Equal(t, StatusOK, request("/"))
+	require.Equal(t, StatusTooManyRequests, request("/"))
+	require.Equal(t, StatusOK, request("/health"))
+	require.Equal(t, uint64(1), app.ThrottleStats().Arrested)
+}
+
+// go test -run Test_App_Throttle_Clock
+func Test_App_Throttle_Clock(t *testing.T) {
+	t.Parallel()
+	clock := NewManualClock(time.Now())
+	app := New(Config{Clock: clock, Throttle: ThrottleConfig{MaxRate: 1, Burst: 1}})
+	app.Get("/", testSimpleHandler)
+
+	request := func() int {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	require.Equal(t, StatusOK, request())
+	require.Equal(t, StatusTooManyRequests, request())
+
+	// the tokens are refilled by the time of the clock
+	clock.Advance(time.Second)
+	require.Equal(t, StatusOK, request())
+}
+
+// go test -run Test_App_AdminApp_Throttle
+func Test_App_AdminApp_Throttle(t *testing.T) {
+	t.Parallel()
+	app := New(Config{Throttle: ThrottleConfig{SLO: 0.99}})
+	admin := app.AdminApp(AdminConfig{
+		Auth: func(c Ctx) error {
+			return c.Next()
+		},
+	})
+
+	status, body := testAdminRequest(t, admin, MethodPut, "/throttle", `{"override":50}`)
+	require.Equal(t, StatusOK, status)
+	require.Contains(t, body, `"override":50,"shedding":50`)
+	status, _ = testAdminRequest(t, admin, MethodPut, "/throttle", `{"override":101}`)
+	require.Equal(t, StatusBadRequest, status)
+	status, body = testAdminRequest(t, admin, MethodPut, "/throttle", `{"override":null}`)
+	require.Equal(t, StatusOK, status)
+	require.Contains(t, body, `"override":null,"shedding":0`)
+	status, body = testAdminRequest(t, admin, MethodGet, "/throttle", "")
+	require.Equal(t, StatusOK, status)
+	require.Contains(t, body, `"burn_rate":0`)
+
+	status, _ = testAdminRequest(t, New().AdminApp(AdminConfig{Auth: func(c Ctx) error {
+		return c.Next()
+	}}), MethodPut, "/throttle", `{"override":50}`)
+	require.Equal(t, StatusConflict, status)
+}
@@ -0,0 +1,166 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// The parameters of the ECH configs, see draft-ietf-tls-esni.
+const (
+	echVersion        = 0xfe0d
+	echKEMX25519      = 0x0020 // DHKEM(X25519, HKDF-SHA256)
+	echKDFHKDFSHA256  = 0x0001
+	echAEADAES128GCM  = 0x0001
+	echMaxNameLength  = 0 // the clients pad the server name with their default
+	echPEMTypeConfig  = "ECHCONFIG"
+	echPEMTypePrivate = "PRIVATE KEY"
+)
+
+// ECHKey is a key of Encrypted Client Hello (ECH), which encrypts the server name and the other
+// privacy-sensitive parts of the TLS ClientHello. The clients get the configs of the keys from
+// the "ech" parameter of the HTTPS DNS record of the domain, see ECHConfigList.
+type ECHKey struct {
+	// Config is the marshalled ECHConfig of the key.
+	Config []byte
+	// PrivateKey is the marshalled HPKE private key of the Config.
+	PrivateKey []byte
+	// SendAsRetry sends the Config to the clients whose ECH was rejected, e.g. because they
+	// used an outdated config, so they can retry with it.
+	SendAsRetry bool
+}
+
+// GenerateECHKey generates an ECH key with X25519, HKDF-SHA256 and AES-128-GCM. The id
+// identifies the config, it should differ between the keys which are used at the same time.
+// publicName is the server name of the outer ClientHello, which is visible on the network,
+// e.g. "ech.example.com". The server needs a certificate for it to retry rejected clients.
+//
+//	key, err := fiber.GenerateECHKey(1, "ech.example.com")
+//	pemBytes, err := key.MarshalPEM()
+func GenerateECHKey(id uint8, publicName string) (ECHKey, error) {
+	if publicName == "" || len(publicName) > 255 {
+		return ECHKey{}, fmt.Errorf("tls: invalid ECH public name %q", publicName)
+	}
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return ECHKey{}, fmt.Errorf("tls: failed to generate the ECH key: %w", err)
+	}
+
+	publicKey := privateKey.PublicKey().Bytes()
+	config := binary.BigEndian.AppendUint16(nil, echVersion)
+	contents := []byte{id}
+	contents = binary.BigEndian.AppendUint16(contents, echKEMX25519)
+	contents = binary.BigEndian.AppendUint16(contents, uint16(len(publicKey)))
+	contents = append(contents, publicKey...)
+	contents = binary.BigEndian.AppendUint16(contents, 4) // one cipher suite
+	contents = binary.BigEndian.AppendUint16(contents, echKDFHKDFSHA256)
+	contents = binary.BigEndian.AppendUint16(contents, echAEADAES128GCM)
+	contents = append(contents, echMaxNameLength, byte(len(publicName)))
+	contents = append(contents, publicName...)
+	contents = binary.BigEndian.AppendUint16(contents, 0) // no extensions
+	config = binary.BigEndian.AppendUint16(config, uint16(len(contents)))
+	config = append(config, contents...)
+
+	return ECHKey{
+		Config:      config,
+		PrivateKey:  privateKey.Bytes(),
+		SendAsRetry: true,
+	}, nil
+}
+
+// ECHConfigList returns the ECHConfigList of the configs of the keys, which is published
+// base64 encoded in the "ech" parameter of the HTTPS DNS record of the domain:
+//
+//	example.com. 300 IN HTTPS 1 . alpn="h2,http/1.1" ech="<base64 of the list>"
+func ECHConfigList(keys ...ECHKey) []byte {
+	var size int
+	for _, key := range keys {
+		size += len(key.Config)
+	}
+	list := binary.BigEndian.AppendUint16(make([]byte, 0, size+2), uint16(size)) //nolint:gosec // the configs are small
+	for _, key := range keys {
+		list = append(list, key.Config...)
+	}
+	return list
+}
+
+// MarshalPEM encodes the key in the PEM format of ECH key files: the PKCS #8 private key
+// and the ECHConfigList of its config.
+func (key ECHKey) MarshalPEM() ([]byte, error) {
+	privateKey, err := ecdh.X25519().NewPrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("tls: invalid ECH private key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("tls: failed to marshal the ECH private key: %w", err)
+	}
+	out := pem.EncodeToMemory(&pem.Block{Type: echPEMTypePrivate, Bytes: der})
+	return append(out, pem.EncodeToMemory(&pem.Block{Type: echPEMTypeConfig, Bytes: ECHConfigList(key)})...), nil
+}
+
+// ParseECHKeyPEM parses an ECH key file in PEM format, e.g. of MarshalPEM or OpenSSL.
+// Only X25519 keys are supported. The config is sent to the rejected clients as retry config.
+func ParseECHKeyPEM(data []byte) (ECHKey, error) {
+	key := ECHKey{SendAsRetry: true}
+	for {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+		switch block.Type {
+		case echPEMTypePrivate:
+			parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return ECHKey{}, fmt.Errorf("tls: invalid ECH private key: %w", err)
+			}
+			privateKey, ok := parsed.(*ecdh.PrivateKey)
+			if !ok || privateKey.Curve() != ecdh.X25519() {
+				return ECHKey{}, errors.New("tls: the ECH private key isn't a X25519 key")
+			}
+			key.PrivateKey = privateKey.Bytes()
+		case echPEMTypeConfig:
+			// the first config of the list
+			list := block.Bytes
+			if len(list) < 6 || int(binary.BigEndian.Uint16(list)) != len(list)-2 {
+				return ECHKey{}, errors.New("tls: invalid ECH config list")
+			}
+			size := 4 + int(binary.BigEndian.Uint16(list[4:]))
+			if len(list)-2 < size {
+				return ECHKey{}, errors.New("tls: invalid ECH config list")
+			}
+			key.Config = list[2 : 2+size]
+		}
+	}
+	if key.PrivateKey == nil || key.Config == nil {
+		return ECHKey{}, fmt.Errorf("tls: the ECH key file needs a %q and an %q block", echPEMTypePrivate, echPEMTypeConfig)
+	}
+	return key, nil
+}
+
+// echKeys returns the ECH keys of the listen config and its key files.
+func echKeys(cfg ListenConfig) ([]ECHKey, error) {
+	keys := append([]ECHKey(nil), cfg.ECHKeys...)
+	for _, file := range cfg.ECHKeyFiles {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return nil, fmt.Errorf("tls: failed to read the ECH key file: %w", err)
+		}
+		key, err := ParseECHKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, file)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,20 @@
+//go:build go1.24
+
+package fiber
+
+import (
+	"crypto/tls"
+)
+
+// applyECHKeys sets the ECH keys of the TLS config.
+func applyECHKeys(tlsConfig *tls.Config, keys []ECHKey) error {
+	tlsConfig.EncryptedClientHelloKeys = make([]tls.EncryptedClientHelloKey, len(keys))
+	for i, key := range keys {
+		tlsConfig.EncryptedClientHelloKeys[i] = tls.EncryptedClientHelloKey{
+			Config:      key.Config,
+			PrivateKey:  key.PrivateKey,
+			SendAsRetry: key.SendAsRetry,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !go1.24
+
+package fiber
+
+import (
+	"crypto/tls"
+)
+
+// applyECHKeys returns ErrECHNotSupported, the ECH keys of crypto/tls are only configurable since Go 1.24
+func applyECHKeys(*tls.Config, []ECHKey) error {
+	return ErrECHNotSupported
+}
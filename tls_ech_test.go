@@ -0,0 +1,79 @@
+//go:build go1.24
+
+package fiber
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_ECHKey_PEM
+func Test_ECHKey_PEM(t *testing.T) {
+	t.Parallel()
+	key, err := GenerateECHKey(7, "public.example")
+	require.NoError(t, err)
+	require.Len(t, key.PrivateKey, 32)
+
+	list := ECHConfigList(key)
+	require.Equal(t, len(key.Config), int(list[0])<<8|int(list[1]))
+	require.Equal(t, key.Config, list[2:])
+
+	data, err := key.MarshalPEM()
+	require.NoError(t, err)
+	require.Contains(t, string(data), "-----BEGIN ECHCONFIG-----")
+	parsed, err := ParseECHKeyPEM(data)
+	require.NoError(t, err)
+	require.Equal(t, key, parsed)
+
+	_, err = ParseECHKeyPEM(data[:len(data)/2])
+	require.Error(t, err)
+	_, err = GenerateECHKey(1, "")
+	require.Error(t, err)
+}
+
+// go test -run Test_ECHKeys_Handshake
+func Test_ECHKeys_Handshake(t *testing.T) {
+	t.Parallel()
+	key, err := GenerateECHKey(1, "public.example")
+	require.NoError(t, err)
+	data, err := key.MarshalPEM()
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "ech.pem")
+	require.NoError(t, os.WriteFile(file, data, 0o600))
+
+	keys, err := echKeys(ListenConfig{ECHKeyFiles: []string{file}})
+	require.NoError(t, err)
+	_, err = echKeys(ListenConfig{ECHKeyFiles: []string{file + ".missing"}})
+	require.Error(t, err)
+
+	cert, err := tls.LoadX509KeyPair("./.github/testdata/ssl.pem", "./.github/testdata/ssl.key")
+	require.NoError(t, err)
+	serverConfig := &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{cert}}
+	require.NoError(t, applyECHKeys(serverConfig, keys))
+
+	serverConn, clientConn := net.Pipe()
+	server := tls.Server(serverConn, serverConfig)
+	client := tls.Client(clientConn, &tls.Config{
+		MinVersion:                     tls.VersionTLS13,
+		ServerName:                     "secret.example",
+		InsecureSkipVerify:             true, //nolint:gosec // self signed test certificate
+		EncryptedClientHelloConfigList: ECHConfigList(keys...),
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Handshake()
+	}()
+	require.NoError(t, client.Handshake())
+	require.NoError(t, <-done)
+
+	// the inner server name is only visible to the server
+	require.True(t, server.ConnectionState().ECHAccepted)
+	require.Equal(t, "secret.example", server.ConnectionState().ServerName)
+	require.NoError(t, clientConn.Close())
+	require.NoError(t, serverConn.Close())
+}
@@ -0,0 +1,47 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// EnvSSLKeyLogFile is the environment variable of the key log file, which is
+// used if ListenConfig.InsecureTLSKeyLog is enabled without TLSKeyLogWriter.
+const EnvSSLKeyLogFile = "SSLKEYLOGFILE"
+
+// tlsKeyLogEnabled reports whether the TLS session keys are logged with the listen config.
+func tlsKeyLogEnabled(cfg ListenConfig) bool {
+	return cfg.InsecureTLSKeyLog && (cfg.TLSKeyLogWriter != nil || os.Getenv(EnvSSLKeyLogFile) != "")
+}
+
+// tlsKeyLogWriter returns the writer of the TLS session keys of the listen config.
+// The file of the SSLKEYLOGFILE environment variable is opened if TLSKeyLogWriter isn't set,
+// it has to be closed by the caller. ErrTLSKeyLogNotAllowed is returned if TLSKeyLogWriter
+// is set without InsecureTLSKeyLog.
+func tlsKeyLogWriter(cfg ListenConfig) (io.Writer, io.Closer, error) {
+	if !cfg.InsecureTLSKeyLog {
+		if cfg.TLSKeyLogWriter != nil {
+			return nil, nil, ErrTLSKeyLogNotAllowed
+		}
+		return nil, nil, nil
+	}
+	if cfg.TLSKeyLogWriter != nil {
+		return cfg.TLSKeyLogWriter, nil, nil
+	}
+
+	path := os.Getenv(EnvSSLKeyLogFile)
+	if path == "" {
+		return nil, nil, nil
+	}
+	file, err := os.OpenFile(filepath.Clean(path), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("tls: failed to open the key log file: %w", err)
+	}
+	return file, file, nil
+}
@@ -0,0 +1,121 @@
+package fiber
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer which can be written and read concurrently.
+type syncBuffer struct {
+	buf   bytes.Buffer
+	mutex sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p) //nolint:wrapcheck // It's a test
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+// go test -run Test_TLSKeyLogWriter
+func Test_TLSKeyLogWriter(t *testing.T) {
+	t.Parallel()
+
+	writer := &syncBuffer{}
+	keyLog, closer, err := tlsKeyLogWriter(ListenConfig{TLSKeyLogWriter: writer})
+	require.ErrorIs(t, err, ErrTLSKeyLogNotAllowed)
+	require.Nil(t, keyLog)
+	require.Nil(t, closer)
+
+	keyLog, closer, err = tlsKeyLogWriter(ListenConfig{TLSKeyLogWriter: writer, InsecureTLSKeyLog: true})
+	require.NoError(t, err)
+	require.Equal(t, writer, keyLog)
+	require.Nil(t, closer)
+}
+
+// go test -run Test_TLSKeyLogWriter_Env
+func Test_TLSKeyLogWriter_Env(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.log")
+	t.Setenv(EnvSSLKeyLogFile, path)
+
+	// the environment variable is only used with InsecureTLSKeyLog
+	keyLog, _, err := tlsKeyLogWriter(ListenConfig{})
+	require.NoError(t, err)
+	require.Nil(t, keyLog)
+	require.False(t, tlsKeyLogEnabled(ListenConfig{}))
+
+	cfg := ListenConfig{InsecureTLSKeyLog: true}
+	require.True(t, tlsKeyLogEnabled(cfg))
+	keyLog, closer, err := tlsKeyLogWriter(cfg)
+	require.NoError(t, err)
+	_, err = keyLog.Write([]byte("CLIENT_RANDOM 00 00\n"))
+	require.NoError(t, err)
+	require.NoError(t, closer.Close())
+
+	b, err := os.ReadFile(path) //nolint:gosec // It's a test
+	require.NoError(t, err)
+	require.Equal(t, "CLIENT_RANDOM 00 00\n", string(b))
+}
+
+// go test -run Test_Listen_TLSKeyLogWriter
+func Test_Listen_TLSKeyLogWriter(t *testing.T) {
+	app := New()
+
+	require.ErrorIs(t, app.Listen(":0", ListenConfig{
+		DisableStartupMessage: true,
+		TLSKeyLogWriter:       &syncBuffer{},
+		CertFile:              "./.github/testdata/ssl.pem",
+		CertKeyFile:           "./.github/testdata/ssl.key",
+	}), ErrTLSKeyLogNotAllowed)
+
+	writer := &syncBuffer{}
+	addr := make(chan string, 1)
+	go func() {
+		assert.NoError(t, app.Listen("127.0.0.1:0", ListenConfig{
+			DisableStartupMessage: true,
+			TLSKeyLogWriter:       writer,
+			InsecureTLSKeyLog:     true,
+			CertFile:              "./.github/testdata/ssl.pem",
+			CertKeyFile:           "./.github/testdata/ssl.key",
+			ListenerAddrFunc: func(a net.Addr) {
+				addr <- a.String()
+			},
+		}))
+	}()
+
+	conn, err := tls.Dial(NetworkTCP4, <-addr, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec // It's a test
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(writer.String(), "CLIENT_HANDSHAKE_TRAFFIC_SECRET")
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, app.Shutdown())
+}
+
+// go test -run Test_Listen_Master_Process_Show_Startup_MessageWithTLSKeyLog
+func Test_Listen_Master_Process_Show_Startup_MessageWithTLSKeyLog(t *testing.T) {
+	app := New()
+	cfg := ListenConfig{TLSKeyLogWriter: &syncBuffer{}, InsecureTLSKeyLog: true}
+
+	startupMessage := captureOutput(func() {
+		app.startupMessage(":3000", true, "", cfg)
+	})
+	require.Contains(t, startupMessage, "WARN TLS key log: \t\tthe session keys are logged, never use it in production")
+}
@@ -0,0 +1,209 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// TLSPolicy is a preset of the TLS settings of the server: the versions,
+// the cipher suites, the curves and the session tickets.
+type TLSPolicy string
+
+const (
+	// TLSPolicyModern only allows TLS 1.3, for clients which are known to support it.
+	TLSPolicyModern TLSPolicy = "modern"
+	// TLSPolicyIntermediate allows TLS 1.2 with forward secret AEAD cipher suites and TLS 1.3,
+	// which is recommended for general-purpose servers.
+	TLSPolicyIntermediate TLSPolicy = "intermediate"
+	// TLSPolicyFIPS only allows the FIPS 140 approved cipher suites (AES-GCM) and curves (P-256, P-384)
+	// with TLS 1.2 and TLS 1.3. Session tickets are disabled, because their keys aren't managed by the module.
+	TLSPolicyFIPS TLSPolicy = "fips"
+)
+
+// tlsCertificateExpiryWarning is the remaining validity of the certificates which is reported by Validate.
+const tlsCertificateExpiryWarning = 30 * 24 * time.Hour
+
+// tlsPolicySettings are the TLS settings of a policy.
+type tlsPolicySettings struct {
+	curves                 []tls.CurveID
+	cipherSuites           []uint16
+	minVersion             uint16
+	maxVersion             uint16
+	sessionTicketsDisabled bool
+}
+
+var tlsPolicies = map[TLSPolicy]tlsPolicySettings{
+	TLSPolicyModern: {
+		minVersion: tls.VersionTLS13,
+		maxVersion: tls.VersionTLS13,
+		curves:     []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384},
+	},
+	TLSPolicyIntermediate: {
+		minVersion: tls.VersionTLS12,
+		maxVersion: tls.VersionTLS13,
+		curves:     []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384},
+		cipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	},
+	TLSPolicyFIPS: {
+		minVersion: tls.VersionTLS12,
+		maxVersion: tls.VersionTLS13,
+		curves:     []tls.CurveID{tls.CurveP256, tls.CurveP384},
+		cipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+		},
+		sessionTicketsDisabled: true,
+	},
+}
+
+// Apply applies the settings of the policy to the config.
+// ErrUnknownTLSPolicy is returned for unknown policies.
+func (p TLSPolicy) Apply(config *tls.Config) error {
+	settings, ok := tlsPolicies[p]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownTLSPolicy, string(p))
+	}
+
+	config.MinVersion = settings.minVersion
+	config.MaxVersion = settings.maxVersion
+	config.CipherSuites = slices.Clone(settings.cipherSuites)
+	config.CurvePreferences = slices.Clone(settings.curves)
+	config.SessionTicketsDisabled = settings.sessionTicketsDisabled
+	return nil
+}
+
+// Validate returns the violations of the policy by the config, e.g. after it was customized
+// with TLSConfigFunc, and the certificates of the config which are expired or expire within 30 days.
+// The cipher suites of TLS 1.3 can't be configured, so they aren't validated.
+func (p TLSPolicy) Validate(config *tls.Config) []string {
+	settings, ok := tlsPolicies[p]
+	if !ok {
+		return []string{fmt.Sprintf("unknown TLS policy %q", string(p))}
+	}
+
+	var report []string
+	minVersion, maxVersion := config.MinVersion, config.MaxVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12 // the default of the servers of crypto/tls
+	}
+	if maxVersion == 0 {
+		maxVersion = tls.VersionTLS13
+	}
+	if minVersion < settings.minVersion {
+		report = append(report, fmt.Sprintf("minimum version %s is below %s", tls.VersionName(minVersion), tls.VersionName(settings.minVersion)))
+	}
+	if maxVersion > settings.maxVersion {
+		report = append(report, fmt.Sprintf("maximum version %s is above %s", tls.VersionName(maxVersion), tls.VersionName(settings.maxVersion)))
+	}
+
+	if minVersion < tls.VersionTLS13 {
+		suites := config.CipherSuites
+		if len(suites) == 0 {
+			for _, suite := range tls.CipherSuites() {
+				suites = append(suites, suite.ID)
+			}
+		}
+		for _, suite := range suites {
+			if !slices.Contains(settings.cipherSuites, suite) && !isTLS13CipherSuite(suite) {
+				report = append(report, "cipher suite "+tls.CipherSuiteName(suite)+" isn't allowed")
+			}
+		}
+	}
+
+	curves := config.CurvePreferences
+	if len(curves) == 0 {
+		curves = []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384, tls.CurveP521} // the defaults of crypto/tls
+	}
+	for _, curve := range curves {
+		if !slices.Contains(settings.curves, curve) {
+			report = append(report, "curve "+curve.String()+" isn't allowed")
+		}
+	}
+
+	if settings.sessionTicketsDisabled && !config.SessionTicketsDisabled {
+		report = append(report, "session tickets are enabled")
+	}
+
+	for i := range config.Certificates {
+		report = append(report, p.validateCertificate(&config.Certificates[i])...)
+	}
+
+	return report
+}
+
+// validateCertificate returns the violations of the policy by the key of the certificate
+// and its expiry, if it is expired or expires within 30 days.
+func (p TLSPolicy) validateCertificate(cert *tls.Certificate) []string {
+	leaf := cert.Leaf
+	if leaf == nil {
+		if len(cert.Certificate) == 0 {
+			return nil
+		}
+		var err error
+		if leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return []string{"certificate can't be parsed: " + err.Error()}
+		}
+	}
+
+	var report []string
+	switch key := leaf.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			report = append(report, fmt.Sprintf("certificate %q has a %d bit RSA key, at least 2048 bits are required", leaf.Subject.CommonName, key.N.BitLen()))
+		}
+	case *ecdsa.PublicKey:
+		if p == TLSPolicyFIPS && key.Curve != elliptic.P256() && key.Curve != elliptic.P384() {
+			report = append(report, fmt.Sprintf("certificate %q has an ECDSA key with the curve %s", leaf.Subject.CommonName, key.Curve.Params().Name))
+		}
+	default:
+		if p == TLSPolicyFIPS {
+			report = append(report, fmt.Sprintf("certificate %q has a %s key, only RSA and ECDSA keys are allowed", leaf.Subject.CommonName, leaf.PublicKeyAlgorithm))
+		}
+	}
+
+	if remaining := time.Until(leaf.NotAfter); remaining <= 0 {
+		report = append(report, fmt.Sprintf("certificate %q expired on %s", leaf.Subject.CommonName, leaf.NotAfter.Format(time.DateOnly)))
+	} else if remaining < tlsCertificateExpiryWarning {
+		report = append(report, fmt.Sprintf("certificate %q expires on %s", leaf.Subject.CommonName, leaf.NotAfter.Format(time.DateOnly)))
+	}
+
+	return report
+}
+
+// isTLS13CipherSuite reports whether the cipher suite is a TLS 1.3 cipher suite.
+func isTLS13CipherSuite(id uint16) bool {
+	for _, suite := range tls.CipherSuites() {
+		if suite.ID == id {
+			return len(suite.SupportedVersions) == 1 && suite.SupportedVersions[0] == tls.VersionTLS13
+		}
+	}
+	return false
+}
+
+// validateTLSPolicy validates the TLS config with the TLS policy of the listen config,
+// after it was customized with TLSConfigFunc, and stores the report for the startup message.
+func (app *App) validateTLSPolicy(tlsConfig *tls.Config, cfg ListenConfig) {
+	if cfg.TLSPolicy == "" || tlsConfig == nil {
+		return
+	}
+	app.tlsPolicyReport = cfg.TLSPolicy.Validate(tlsConfig)
+}
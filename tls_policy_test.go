@@ -0,0 +1,140 @@
+package fiber
+
+import (
+	"crypto/tls"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -run Test_TLSPolicy_Apply
+func Test_TLSPolicy_Apply(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []TLSPolicy{TLSPolicyModern, TLSPolicyIntermediate, TLSPolicyFIPS} {
+		config := &tls.Config{MinVersion: tls.VersionTLS10} //nolint:gosec // the policy raises the version
+		require.NoError(t, policy.Apply(config))
+		require.Empty(t, policy.Validate(config), string(policy))
+	}
+
+	config := &tls.Config{} //nolint:gosec // It's a test
+	require.NoError(t, TLSPolicyModern.Apply(config))
+	require.Equal(t, uint16(tls.VersionTLS13), config.MinVersion)
+	require.Equal(t, uint16(tls.VersionTLS13), config.MaxVersion)
+
+	require.NoError(t, TLSPolicyFIPS.Apply(config))
+	require.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	require.Equal(t, []tls.CurveID{tls.CurveP256, tls.CurveP384}, config.CurvePreferences)
+	require.True(t, config.SessionTicketsDisabled)
+
+	require.ErrorIs(t, TLSPolicy("legacy").Apply(config), ErrUnknownTLSPolicy)
+}
+
+// go test -run Test_TLSPolicy_Validate
+func Test_TLSPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	config := &tls.Config{ //nolint:gosec // It's a test
+		MinVersion: tls.VersionTLS10,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+		},
+	}
+	require.Equal(t, []string{
+		"minimum version TLS 1.0 is below TLS 1.2",
+		"cipher suite TLS_RSA_WITH_AES_128_CBC_SHA isn't allowed",
+		"curve CurveP521 isn't allowed",
+	}, TLSPolicyIntermediate.Validate(config))
+
+	config = &tls.Config{} //nolint:gosec // It's a test
+	require.NoError(t, TLSPolicyFIPS.Apply(config))
+	config.CurvePreferences = append(config.CurvePreferences, tls.X25519)
+	config.SessionTicketsDisabled = false
+	require.Equal(t, []string{
+		"curve X25519 isn't allowed",
+		"session tickets are enabled",
+	}, TLSPolicyFIPS.Validate(config))
+
+	// the cipher suites of TLS 1.2 aren't used with TLS 1.3
+	config = &tls.Config{
+		MinVersion:       tls.VersionTLS13,
+		CipherSuites:     []uint16{tls.TLS_RSA_WITH_AES_128_CBC_SHA},
+		CurvePreferences: []tls.CurveID{tls.X25519},
+	}
+	require.Empty(t, TLSPolicyModern.Validate(config))
+
+	require.Equal(t, []string{`unknown TLS policy "legacy"`}, TLSPolicy("legacy").Validate(config))
+}
+
+// go test -run Test_TLSPolicy_Validate_Certificate
+func Test_TLSPolicy_Validate_Certificate(t *testing.T) {
+	t.Parallel()
+
+	cert, err := tls.LoadX509KeyPair("./.github/testdata/ssl.pem", "./.github/testdata/ssl.key")
+	require.NoError(t, err)
+
+	config := &tls.Config{Certificates: []tls.Certificate{cert}} //nolint:gosec // It's a test
+	require.NoError(t, TLSPolicyIntermediate.Apply(config))
+	require.Equal(t, []string{`certificate "ubuntu.nan" expired on 2025-02-01`}, TLSPolicyIntermediate.Validate(config))
+
+	config.Certificates[0].Leaf.NotAfter = time.Now().Add(24 * time.Hour)
+	report := TLSPolicyIntermediate.Validate(config)
+	require.Len(t, report, 1)
+	require.True(t, strings.HasPrefix(report[0], `certificate "ubuntu.nan" expires on `))
+}
+
+// go test -run Test_Listen_TLSPolicy
+func Test_Listen_TLSPolicy(t *testing.T) {
+	app := New()
+
+	require.ErrorIs(t, app.Listen(":0", ListenConfig{
+		DisableStartupMessage: true,
+		TLSPolicy:             "legacy",
+		CertFile:              "./.github/testdata/ssl.pem",
+		CertKeyFile:           "./.github/testdata/ssl.key",
+	}), ErrUnknownTLSPolicy)
+
+	var tlsConfig *tls.Config
+	go func() {
+		time.Sleep(1000 * time.Millisecond)
+		assert.NoError(t, app.Shutdown())
+	}()
+
+	require.NoError(t, app.Listen(":0", ListenConfig{
+		DisableStartupMessage: true,
+		TLSPolicy:             TLSPolicyModern,
+		TLSConfigFunc: func(config *tls.Config) {
+			tlsConfig = config
+			config.MinVersion = tls.VersionTLS12
+		},
+		CertFile:    "./.github/testdata/ssl.pem",
+		CertKeyFile: "./.github/testdata/ssl.key",
+	}))
+
+	require.Equal(t, []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384}, tlsConfig.CurvePreferences)
+	require.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MaxVersion)
+	require.Contains(t, app.tlsPolicyReport, "minimum version TLS 1.2 is below TLS 1.3")
+}
+
+// go test -run Test_Listen_Master_Process_Show_Startup_MessageWithTLSPolicy
+func Test_Listen_Master_Process_Show_Startup_MessageWithTLSPolicy(t *testing.T) {
+	app := New()
+	app.tlsPolicyReport = []string{"session tickets are enabled"}
+	cfg := ListenConfig{TLSPolicy: TLSPolicyFIPS}
+
+	startupMessage := captureOutput(func() {
+		app.startupMessage(":3000", true, "", cfg)
+	})
+	require.Contains(t, startupMessage, "INFO TLS policy: \t\tfips")
+	require.Contains(t, startupMessage, "WARN TLS policy: \t\tsession tickets are enabled")
+
+	// the policy isn't reported without TLS
+	startupMessage = captureOutput(func() {
+		app.startupMessage(":3000", false, "", cfg)
+	})
+	require.NotContains(t, startupMessage, "TLS policy")
+}
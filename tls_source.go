@@ -0,0 +1,69 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+)
+
+// CertificateSource provides the certificate of the server and the roots of the client
+// certificates, which are rotated without a restart, e.g. the X.509 SVIDs of the SPIFFE
+// Workload API of the addon/spiffe package.
+type CertificateSource interface {
+	// Certificate returns the current certificate of the server.
+	Certificate() (*tls.Certificate, error)
+
+	// ClientCAs returns the current roots of the client certificates,
+	// or nil if the client certificates aren't requested.
+	ClientCAs() (*x509.CertPool, error)
+}
+
+// sourceConfig is the TLS config of the handshakes with the client CAs of a CertificateSource.
+type sourceConfig struct {
+	mutex  sync.Mutex
+	roots  *x509.CertPool
+	config *tls.Config
+}
+
+// sourceTLSConfig creates the TLS config of Listen with the certificates of the source.
+// The client certificates are verified with the current roots of the source.
+func (app *App) sourceTLSConfig(source CertificateSource) *tls.Config {
+	tlsHandler := &TLSHandler{}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(info *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			_, _ = tlsHandler.GetClientInfo(info) //nolint:errcheck // It always returns nil
+			return source.Certificate()
+		},
+	}
+
+	// the config of the handshakes is cloned when the roots changed, so the changes
+	// of the config after Listen, e.g. by TLSConfigFunc, are included
+	sc := &sourceConfig{}
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		roots, err := source.ClientCAs()
+		if err != nil || roots == nil {
+			return nil, err
+		}
+
+		sc.mutex.Lock()
+		defer sc.mutex.Unlock()
+		if sc.roots != roots {
+			sc.config = tlsConfig.Clone()
+			sc.config.GetConfigForClient = nil
+			sc.config.ClientAuth = tls.RequireAndVerifyClientCert
+			sc.config.ClientCAs = roots
+			sc.roots = roots
+		}
+		return sc.config, nil
+	}
+
+	// Attach the tlsHandler to the config
+	app.SetTLSHandler(tlsHandler)
+
+	return tlsConfig
+}
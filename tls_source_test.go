@@ -0,0 +1,88 @@
+package fiber
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCertificateSource struct {
+	cert  *tls.Certificate
+	roots atomic.Pointer[x509.CertPool]
+}
+
+func (s *testCertificateSource) Certificate() (*tls.Certificate, error) {
+	return s.cert, nil
+}
+
+func (s *testCertificateSource) ClientCAs() (*x509.CertPool, error) {
+	return s.roots.Load(), nil
+}
+
+// testSourceHandshake runs a handshake with the TLS config and returns the verified chains of the client certificate.
+func testSourceHandshake(t *testing.T, config *tls.Config, clientCert *tls.Certificate) ([][]*x509.Certificate, error) {
+	t.Helper()
+	// the TLS alerts would block on a synchronous net.Pipe
+	ln, err := net.Listen(NetworkTCP4, "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close() //nolint:errcheck // It is fine to ignore the error here
+	clientConn, err := net.Dial(NetworkTCP4, ln.Addr().String())
+	require.NoError(t, err)
+	defer clientConn.Close() //nolint:errcheck // It is fine to ignore the error here
+	serverConn, err := ln.Accept()
+	require.NoError(t, err)
+	defer serverConn.Close() //nolint:errcheck // It is fine to ignore the error here
+
+	clientConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: true} //nolint:gosec // The test certificate isn't verified
+	if clientCert != nil {
+		clientConfig.Certificates = []tls.Certificate{*clientCert}
+	}
+	client := tls.Client(clientConn, clientConfig)
+	go func() {
+		_ = client.Handshake()              //nolint:errcheck // The error of the server is checked
+		_, _ = client.Read(make([]byte, 1)) //nolint:errcheck // It waits for the alert of the server
+		_ = clientConn.Close()              //nolint:errcheck // It is fine to ignore the error here
+	}()
+
+	server := tls.Server(serverConn, config)
+	if err := server.Handshake(); err != nil {
+		return nil, err
+	}
+	return server.ConnectionState().VerifiedChains, nil
+}
+
+// go test -run Test_App_SourceTLSConfig
+func Test_App_SourceTLSConfig(t *testing.T) {
+	t.Parallel()
+	serverCert, _ := testClientCertificate(t, "spiffe://example.org/server")
+	clientCert, roots := testClientCertificate(t, "spiffe://example.org/client")
+	source := &testCertificateSource{cert: &serverCert}
+
+	app := New()
+	config := app.sourceTLSConfig(source)
+	require.NotNil(t, app.tlsHandler)
+
+	// the client certificates aren't requested without roots
+	chains, err := testSourceHandshake(t, config, &clientCert)
+	require.NoError(t, err)
+	require.Empty(t, chains)
+
+	// the client certificates are verified with the current roots
+	source.roots.Store(roots)
+	_, err = testSourceHandshake(t, config, nil)
+	require.Error(t, err)
+	chains, err = testSourceHandshake(t, config, &clientCert)
+	require.NoError(t, err)
+	require.Len(t, chains, 1)
+	require.Equal(t, "spiffe://example.org/client", chains[0][0].URIs[0].String())
+
+	// the rotated roots don't verify the certificates of the previous CA
+	_, otherRoots := testClientCertificate(t, "")
+	source.roots.Store(otherRoots)
+	_, err = testSourceHandshake(t, config, &clientCert)
+	require.Error(t, err)
+}
@@ -0,0 +1,75 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// VaryBy records that the response depends on the request headers, e.g. on Accept-Language
+// for a translated response or on Cookie for a response of the session, and adds them to the
+// Vary header. The fields are deduplicated case-insensitively and "*" replaces all fields.
+// After the handlers returned, the Vary header is emitted once with the fields of all VaryBy
+// calls of the request, even if a handler overwrote the header in the meantime.
+func (c *DefaultCtx) VaryBy(fields ...string) {
+	added := false
+	for _, field := range fields {
+		if !containsFold(c.vary, field) {
+			c.vary = append(c.vary, field)
+			added = true
+		}
+	}
+	if added {
+		mergeVary(&c.fasthttp.Response.Header, c.vary)
+	}
+}
+
+// getVary returns the fields of the VaryBy calls of the request.
+func (c *DefaultCtx) getVary() []string {
+	return c.vary
+}
+
+// emitVary adds the fields of the VaryBy calls to the Vary header of the response.
+func (*App) emitVary(c CustomCtx, fields []string) {
+	if !c.Hijacked() {
+		mergeVary(&c.Response().Header, fields)
+	}
+}
+
+// mergeVary sets the Vary header to the fields of the header and the fields, without
+// duplicates. It is set to "*" if one of the fields is "*".
+func mergeVary(header *fasthttp.ResponseHeader, fields []string) {
+	current := string(header.Peek(HeaderVary))
+
+	merged := make([]string, 0, len(fields)+strings.Count(current, ",")+1)
+	for _, field := range strings.Split(current, ",") {
+		if field = strings.TrimSpace(field); field != "" && !containsFold(merged, field) {
+			merged = append(merged, field)
+		}
+	}
+	for _, field := range fields {
+		if !containsFold(merged, field) {
+			merged = append(merged, field)
+		}
+	}
+
+	value := strings.Join(merged, ", ")
+	if slices.Contains(merged, "*") {
+		value = "*"
+	}
+	if value != current {
+		header.Set(HeaderVary, value)
+	}
+}
+
+// containsFold reports whether the fields contain the field, ignoring the case.
+func containsFold(fields []string, field string) bool {
+	return slices.ContainsFunc(fields, func(f string) bool {
+		return strings.EqualFold(f, field)
+	})
+}
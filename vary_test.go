@@ -0,0 +1,58 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// go test -run Test_Ctx_VaryBy
+func Test_Ctx_VaryBy(t *testing.T) {
+	t.Parallel()
+	app := New()
+	c := app.AcquireCtx(&fasthttp.RequestCtx{})
+
+	c.Set(HeaderVary, "origin")
+	c.VaryBy(HeaderAcceptLanguage, HeaderOrigin)
+	c.VaryBy("accept-language", HeaderCookie)
+	c.Vary(HeaderAcceptEncoding, HeaderCookie)
+	require.Equal(t, "origin, Accept-Language, Cookie, Accept-Encoding", string(c.Response().Header.Peek(HeaderVary)))
+
+	c.VaryBy("*")
+	require.Equal(t, "*", string(c.Response().Header.Peek(HeaderVary)))
+
+	app.ReleaseCtx(c)
+	c = app.AcquireCtx(&fasthttp.RequestCtx{})
+	require.Empty(t, c.(*DefaultCtx).getVary()) //nolint:forcetypeassert,errcheck // not needed
+}
+
+// go test -run Test_App_VaryBy
+func Test_App_VaryBy(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.Use(func(c Ctx) error {
+		c.VaryBy(HeaderAcceptLanguage)
+		return c.Next()
+	})
+	app.Get("/", func(c Ctx) error {
+		c.VaryBy(HeaderAcceptEncoding, HeaderAcceptLanguage)
+		// the fields of VaryBy are emitted, even if the header is overwritten
+		c.Set(HeaderVary, "User-Agent")
+		return c.SendString("ok")
+	})
+	app.Get("/error", func(c Ctx) error {
+		c.VaryBy(HeaderCookie)
+		return ErrForbidden
+	})
+
+	resp, err := app.Test(httptest.NewRequest(MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, "User-Agent, Accept-Language, Accept-Encoding", resp.Header.Get(HeaderVary))
+
+	resp, err = app.Test(httptest.NewRequest(MethodGet, "/error", nil))
+	require.NoError(t, err)
+	require.Equal(t, StatusForbidden, resp.StatusCode)
+	require.Equal(t, "Accept-Language, Cookie", resp.Header.Get(HeaderVary))
+}
@@ -0,0 +1,76 @@
+// ⚡️ Fiber is an Express inspired web framework written in Go with ☕️
+// 🤖 Github Repository: https://github.com/gofiber/fiber
+// 📌 API Documentation: https://docs.gofiber.io
+
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// warmupState contains the state of the warm-up hooks, see OnWarmup.
+type warmupState struct {
+	// running is true while the warm-up hooks are executed
+	running atomic.Bool
+
+	// errors of the failed hooks with WarmupDegrade, guarded by mutex
+	mutex    sync.Mutex
+	degraded []error
+}
+
+// Ready reports if the app is ready to receive traffic. It is false while the warm-up hooks
+// of OnWarmup are executed and after a hook with WarmupAbort failed, e.g. for readiness probes:
+//
+//	app.Get(healthcheck.DefaultReadinessEndpoint, healthcheck.NewHealthChecker(healthcheck.Config{
+//	    Probe: func(c fiber.Ctx) bool {
+//	        return c.App().Ready()
+//	    },
+//	}))
+func (app *App) Ready() bool {
+	return !app.warmup.running.Load()
+}
+
+// WarmupErrors returns the errors of the failed warm-up hooks with WarmupDegrade,
+// nil if the app isn't degraded.
+func (app *App) WarmupErrors() error {
+	app.warmup.mutex.Lock()
+	defer app.warmup.mutex.Unlock()
+	return errors.Join(app.warmup.degraded...)
+}
+
+// serveWarm serves the listener with serve while the warm-up hooks are executed. If a hook
+// with WarmupAbort fails, the listener is closed and the error of the hook is returned.
+func (app *App) serveWarm(ln net.Listener, serve func() error) error {
+	app.mutex.Lock()
+	hooks := len(app.hooks.onWarmup)
+	app.mutex.Unlock()
+	if hooks == 0 {
+		return serve()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app.warmup.running.Store(true)
+	failed := make(chan error, 1)
+	go func() {
+		if err := app.hooks.executeOnWarmupHooks(ctx); err != nil {
+			failed <- err
+			_ = ln.Close() //nolint:errcheck // the error of the hook is returned
+			return
+		}
+		app.warmup.running.Store(false)
+	}()
+
+	err := serve()
+	select {
+	case warmupErr := <-failed:
+		return warmupErr
+	default:
+		return err
+	}
+}
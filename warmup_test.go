@@ -0,0 +1,83 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// go test -run Test_Hook_OnWarmup
+func Test_Hook_OnWarmup(t *testing.T) {
+	t.Parallel()
+	app := New()
+	app.EnableDiscovery()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var order []string
+	app.Hooks().OnWarmup("templates", func(context.Context) error {
+		order = append(order, "templates")
+		close(started)
+		<-release
+		return nil
+	})
+	app.Hooks().OnWarmup("cache", func(context.Context) error {
+		order = append(order, "cache")
+		return errors.New("cache is cold")
+	}, WarmupConfig{Policy: WarmupDegrade})
+
+	status := func() int {
+		resp, err := app.Test(httptest.NewRequest(MethodGet, DefaultDiscoveryPath, nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	require.True(t, app.Ready())
+	require.Equal(t, StatusOK, status())
+
+	ln := fasthttputil.NewInmemoryListener()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- app.Listener(ln, ListenConfig{DisableStartupMessage: true})
+	}()
+
+	<-started
+	require.False(t, app.Ready())
+	require.Equal(t, StatusServiceUnavailable, status())
+
+	close(release)
+	require.Eventually(t, app.Ready, time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"templates", "cache"}, order)
+	require.ErrorContains(t, app.WarmupErrors(), `warmup hook "cache": cache is cold`)
+	require.Equal(t, StatusOK, status())
+
+	require.NoError(t, app.Shutdown())
+	require.NoError(t, <-errs)
+}
+
+// go test -run Test_Hook_OnWarmup_Abort
+func Test_Hook_OnWarmup_Abort(t *testing.T) {
+	t.Parallel()
+	app := New()
+
+	executed := false
+	app.Hooks().OnWarmup("db", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, WarmupConfig{Timeout: 10 * time.Millisecond})
+	app.Hooks().OnWarmup("cache", func(context.Context) error {
+		executed = true
+		return nil
+	})
+
+	err := app.Listener(fasthttputil.NewInmemoryListener(), ListenConfig{DisableStartupMessage: true})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, `warmup hook "db"`)
+	require.False(t, executed)
+	require.False(t, app.Ready())
+	require.NoError(t, app.WarmupErrors())
+}